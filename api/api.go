@@ -92,11 +92,37 @@ func (a ActionConfig) String() string {
 	return strings.Join(s, ", ")
 }
 
+// GeofenceConfig defines actions triggered by the vehicle position relative to home
+type GeofenceConfig struct {
+	Latitude   float64       `mapstructure:"latitude"`   // home latitude
+	Longitude  float64       `mapstructure:"longitude"`  // home longitude
+	Radius     float64       `mapstructure:"radius"`     // home radius in m
+	Approach   float64       `mapstructure:"approach"`   // radius in m for approaching home
+	Interval   time.Duration `mapstructure:"interval"`   // position polling interval
+	Trip       float64       `mapstructure:"trip"`       // distance in km from home or driven since leaving beyond which the vehicle is on a road trip
+	Loadpoint  string        `mapstructure:"loadpoint"`  // loadpoint title, defaults to loadpoint with vehicle as default vehicle
+	OnLeave    ActionConfig  `mapstructure:"onLeave"`    // action when vehicle leaves home
+	OnApproach ActionConfig  `mapstructure:"onApproach"` // action when vehicle approaches home
+	Plan       GeofencePlan  `mapstructure:"plan"`       // charge plan created when vehicle approaches home
+}
+
+// GeofencePlan defines a charge plan created when the vehicle approaches home with low soc
+type GeofencePlan struct {
+	Below int    `mapstructure:"below"` // create plan below this soc
+	SoC   int    `mapstructure:"soc"`   // target soc
+	Time  string `mapstructure:"time"`  // target time of day, e.g. 07:00
+}
+
 // Meter is able to provide current power in W
 type Meter interface {
 	CurrentPower() (float64, error)
 }
 
+// MeterContext is a Meter supporting cancellation of in-flight requests
+type MeterContext interface {
+	CurrentPowerContext(ctx context.Context) (float64, error)
+}
+
 // MeterEnergy is able to provide current energy in kWh
 type MeterEnergy interface {
 	TotalEnergy() (float64, error)
@@ -107,6 +133,16 @@ type MeterCurrent interface {
 	Currents() (float64, float64, float64, error)
 }
 
+// MeterFrequency is able to provide grid frequency in Hz
+type MeterFrequency interface {
+	Frequency() (float64, error)
+}
+
+// MeterIsland is able to detect backup power/ island operation
+type MeterIsland interface {
+	Island() (bool, error)
+}
+
 // Battery is able to provide battery SoC in %
 type Battery interface {
 	SoC() (float64, error)
@@ -117,6 +153,11 @@ type ChargeState interface {
 	Status() (ChargeStatus, error)
 }
 
+// ChargeStateContext is a ChargeState supporting cancellation of in-flight requests
+type ChargeStateContext interface {
+	StatusContext(ctx context.Context) (ChargeStatus, error)
+}
+
 // Charger is able to provide current charging status and enable/disable charging
 type Charger interface {
 	ChargeState
@@ -135,6 +176,28 @@ type PhaseSwitcher interface {
 	Phases1p3p(phases int) error
 }
 
+// StandbyController is able to power down the charger electronics to save standby power
+type StandbyController interface {
+	Standby(standby bool) error
+}
+
+// Indication is the charger's LED or display state reflecting the evcc state
+type Indication struct {
+	Color      string `mapstructure:"color"`      // LED color as #rrggbb
+	Brightness int    `mapstructure:"brightness"` // LED brightness in %
+	Text       string `mapstructure:"text"`       // display text
+}
+
+// Indicator drives the charger's LED or display, unsupported parts of the indication are ignored
+type Indicator interface {
+	Indicate(Indication) error
+}
+
+// Closer releases device resources like connections on shutdown or when the device is recreated
+type Closer interface {
+	Close() error
+}
+
 // Diagnosis is a helper interface that allows to dump diagnostic data to console
 type Diagnosis interface {
 	Diagnose()
@@ -195,6 +258,16 @@ type VehiclePosition interface {
 	Position() (float64, float64, error)
 }
 
+// Geofencer provides the vehicles geofence configuration
+type Geofencer interface {
+	Geofence() GeofenceConfig
+}
+
+// SocModeler provides the vehicles soc estimation model
+type SocModeler interface {
+	SocModel() string
+}
+
 // SocLimiter returns the vehicles charge limit
 type SocLimiter interface {
 	TargetSoC() (float64, error)
@@ -217,6 +290,67 @@ type Tariff interface {
 	CurrentPrice() (float64, error) // EUR/kWh, CHF/kWh, ...
 }
 
+// Rate is the price within a time slot
+type Rate struct {
+	Start, End time.Time
+	Price      float64 // EUR/kWh, CHF/kWh, ...
+}
+
+// TariffRates provides known current and upcoming prices of variable tariffs
+type TariffRates interface {
+	Rates() ([]Rate, error)
+}
+
+// TariffCheapLimit provides the price up to which the tariff is considered cheap
+type TariffCheapLimit interface {
+	CheapLimit() float64
+}
+
+// TariffImporter imports a custom price schedule
+type TariffImporter interface {
+	Import([]byte) error
+}
+
+// TariffLocation interprets local timestamps in the site's timezone
+type TariffLocation interface {
+	SetLocation(*time.Location)
+}
+
+// ForecastSlot is the expected average power within a time slot
+type ForecastSlot struct {
+	Start, End time.Time
+	Power      float64 // W
+}
+
+// SolarForecast is the pv production forecast
+type SolarForecast interface {
+	Forecast() ([]ForecastSlot, error)
+}
+
+// ForecastValue is the expected value within a time slot
+type ForecastValue struct {
+	Start, End time.Time
+	Value      float64
+}
+
+// Forecaster is a pluggable forecast, e.g. of a third party model. Production and consumption
+// forecasts provide power in W, price forecasts the grid price per kWh.
+type Forecaster interface {
+	Forecast() ([]ForecastValue, error)
+}
+
+// WeatherWarning is an official severe weather warning
+type WeatherWarning struct {
+	Event      string
+	Severity   int       // 1 minor, 2 moderate, 3 severe, 4 extreme
+	Start, End time.Time // end is zero if open
+}
+
+// WeatherWarnings provides current and upcoming weather warnings
+type WeatherWarnings interface {
+	Warnings() ([]WeatherWarning, error)
+}
+
 // AuthProvider is the ability to provide OAuth authentication through the ui
 type AuthProvider interface {
 	SetCallbackParams(baseURL, redirectURL string, authenticated chan<- bool)
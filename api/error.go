@@ -5,9 +5,18 @@ import "errors"
 // ErrNotAvailable indicates that a feature is not available
 var ErrNotAvailable = errors.New("not available")
 
-// ErrMustRetry indicates that a rate-limited operation should be retried
+// ErrMustRetry indicates that the operation should be retried, e.g. while waking up
 var ErrMustRetry = errors.New("must retry")
 
+// ErrRateLimited indicates that the remote service limits requests and callers should back off
+var ErrRateLimited = errors.New("rate limited")
+
+// ErrAuthExpired indicates that the authorization has expired and the user must log in again
+var ErrAuthExpired = errors.New("authorization expired")
+
+// ErrWriteIgnored indicates that the device did not apply a written value
+var ErrWriteIgnored = errors.New("write ignored")
+
 // ErrSponsorRequired indicates that a sponsor token is required
 var ErrSponsorRequired = errors.New("sponsorship required, see https://github.com/evcc-io/evcc#sponsorship")
 
@@ -283,3 +283,10 @@ func (wb *ABB) Diagnose() {
 		fmt.Printf("\tError code:\t%x\n", binary.BigEndian.Uint32(b))
 	}
 }
+
+var _ api.Closer = (*ABB)(nil)
+
+// Close implements the api.Closer interface
+func (wb *ABB) Close() error {
+	return wb.conn.Close()
+}
@@ -264,3 +264,10 @@ func (wb *ABLeMH) WakeUp() error {
 
 	return err
 }
+
+var _ api.Closer = (*ABLeMH)(nil)
+
+// Close implements the api.Closer interface
+func (wb *ABLeMH) Close() error {
+	return wb.conn.Close()
+}
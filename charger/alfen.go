@@ -249,3 +249,10 @@ func (c *Alfen) Phases1p3p(phases int) error {
 // 		fmt.Printf("Firmware: %0 x\n", b)
 // 	}
 // }
+
+var _ api.Closer = (*Alfen)(nil)
+
+// Close implements the api.Closer interface
+func (wb *Alfen) Close() error {
+	return wb.conn.Close()
+}
@@ -131,3 +131,10 @@ func (wb *Alphatec) MaxCurrent(current int64) error {
 
 	return err
 }
+
+var _ api.Closer = (*Alphatec)(nil)
+
+// Close implements the api.Closer interface
+func (wb *Alphatec) Close() error {
+	return wb.conn.Close()
+}
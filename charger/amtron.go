@@ -189,3 +189,10 @@ func (wb *Amtron) Diagnose() {
 		fmt.Printf("Serial: %d\n", binary.LittleEndian.Uint32(b))
 	}
 }
+
+var _ api.Closer = (*Amtron)(nil)
+
+// Close implements the api.Closer interface
+func (wb *Amtron) Close() error {
+	return wb.conn.Close()
+}
@@ -347,3 +347,10 @@ func (wb *BenderCC) Diagnose() {
 		fmt.Printf("\tUserID:\t%s\n", b)
 	}
 }
+
+var _ api.Closer = (*BenderCC)(nil)
+
+// Close implements the api.Closer interface
+func (wb *BenderCC) Close() error {
+	return wb.conn.Close()
+}
@@ -125,3 +125,10 @@ func (wb *CfosPowerBrain) MaxCurrentMillis(current float64) error {
 	_, err := wb.conn.WriteSingleRegister(cfosRegMaxCurrent, uint16(current*10))
 	return err
 }
+
+var _ api.Closer = (*CfosPowerBrain)(nil)
+
+// Close implements the api.Closer interface
+func (wb *CfosPowerBrain) Close() error {
+	return wb.conn.Close()
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/modbus"
+	"github.com/mitchellh/mapstructure"
 )
 
 type chargerRegistry map[string]func(map[string]interface{}) (api.Charger, error)
@@ -29,13 +31,44 @@ var registry chargerRegistry = make(map[string]func(map[string]interface{}) (api
 // NewFromConfig creates charger from configuration
 func NewFromConfig(typ string, other map[string]interface{}) (v api.Charger, err error) {
 	factory, err := registry.Get(strings.ToLower(typ))
+	if err != nil {
+		return nil, fmt.Errorf("invalid charger type: %s", typ)
+	}
+
+	other, verify, err := verifyConfig(other)
 	if err == nil {
-		if v, err = factory(other); err != nil {
-			err = fmt.Errorf("cannot create charger '%s': %w", typ, err)
+		v, err = factory(other)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot create charger '%s': %w", typ, err)
+	}
+
+	// modbus chargers create their connection internally
+	if verify > 0 {
+		var cc modbus.Settings
+		if err := mapstructure.WeakDecode(other, &cc); err != nil {
+			return nil, fmt.Errorf("cannot create charger '%s': %w", typ, err)
+		}
+		modbus.VerifyWrites(cc.URI, cc.Device, verify)
+	}
+
+	return v, nil
+}
+
+// verifyConfig removes the write verification retries from the charger configuration
+func verifyConfig(other map[string]interface{}) (map[string]interface{}, int, error) {
+	var verify int
+	res := make(map[string]interface{}, len(other))
+
+	for k, v := range other {
+		if strings.EqualFold(k, "verify") {
+			if err := mapstructure.WeakDecode(v, &verify); err != nil {
+				return nil, 0, fmt.Errorf("verify: %w", err)
+			}
+			continue
 		}
-	} else {
-		err = fmt.Errorf("invalid charger type: %s", typ)
+		res[k] = v
 	}
 
-	return
+	return res, verify, nil
 }
@@ -0,0 +1,21 @@
+package charger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyConfig(t *testing.T) {
+	other := map[string]interface{}{"uri": "192.168.0.8:502", "Verify": "3"}
+
+	res, verify, err := verifyConfig(other)
+	require.NoError(t, err)
+	assert.Equal(t, 3, verify)
+	assert.Equal(t, map[string]interface{}{"uri": "192.168.0.8:502"}, res)
+	assert.Contains(t, other, "Verify", "original config must not be modified")
+
+	_, _, err = verifyConfig(map[string]interface{}{"verify": "foo"})
+	assert.Error(t, err)
+}
@@ -242,3 +242,10 @@ func (wb *Dadapower) Diagnose() {
 		fmt.Printf("Identification:\t%s\n", b)
 	}
 }
+
+var _ api.Closer = (*Dadapower)(nil)
+
+// Close implements the api.Closer interface
+func (wb *Dadapower) Close() error {
+	return wb.conn.Close()
+}
@@ -56,8 +56,9 @@ type Easee struct {
 	phaseMode             int
 	currentPower, sessionEnergy, totalEnergy,
 	currentL1, currentL2, currentL3 float64
-	rfid string
-	lp   loadpoint.API
+	rfid   string
+	lp     loadpoint.API
+	cancel context.CancelFunc // stops the signalr client
 }
 
 func init() {
@@ -141,7 +142,10 @@ func NewEasee(user, password, charger string) (*Easee, error) {
 		}
 	}
 
-	client, err := signalr.NewClient(context.Background(),
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	client, err := signalr.NewClient(ctx,
 		signalr.WithConnector(c.connect(ts)),
 		signalr.WithReceiver(c),
 		signalr.Logger(easee.SignalrLogger(c.log.TRACE), false),
@@ -557,3 +561,11 @@ func (c *Easee) updateSmartCharging() {
 func (c *Easee) LoadpointControl(lp loadpoint.API) {
 	c.lp = lp
 }
+
+var _ api.Closer = (*Easee)(nil)
+
+// Close implements the api.Closer interface
+func (c *Easee) Close() error {
+	c.cancel()
+	return nil
+}
@@ -242,3 +242,10 @@ func (wb *Etrel) Diagnose() {
 		fmt.Printf("Software:\t%s\n", b)
 	}
 }
+
+var _ api.Closer = (*Etrel)(nil)
+
+// Close implements the api.Closer interface
+func (wb *Etrel) Close() error {
+	return wb.conn.Close()
+}
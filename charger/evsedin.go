@@ -124,3 +124,10 @@ func (evse *EvseDIN) MaxCurrent(current int64) error {
 
 	return err
 }
+
+var _ api.Closer = (*EvseDIN)(nil)
+
+// Close implements the api.Closer interface
+func (evse *EvseDIN) Close() error {
+	return evse.conn.Close()
+}
@@ -20,6 +20,8 @@ package charger
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -205,6 +207,33 @@ func (c *GoE) totalEnergy() (float64, error) {
 	return val, err
 }
 
+var _ api.Indicator = (*GoE)(nil)
+
+// Indicate implements the api.Indicator interface - v2 only
+func (c *GoE) Indicate(ind api.Indication) error {
+	if !c.api.IsV2() {
+		return api.ErrNotAvailable
+	}
+
+	var params []string
+	if ind.Color != "" {
+		// use the color for all vehicle states
+		color := url.QueryEscape(fmt.Sprintf("%q", ind.Color))
+		for _, key := range []string{"cid", "cwc", "cch", "cfi"} {
+			params = append(params, fmt.Sprintf("%s=%s", key, color))
+		}
+	}
+	if ind.Brightness > 0 {
+		params = append(params, fmt.Sprintf("lbr=%d", ind.Brightness*255/100))
+	}
+
+	if len(params) == 0 {
+		return nil
+	}
+
+	return c.api.Update(strings.Join(params, "&"))
+}
+
 // phases1p3p implements the api.PhaseSwitcher interface - v2 only
 func (c *GoE) phases1p3p(phases int) error {
 	if phases == 3 {
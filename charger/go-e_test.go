@@ -86,3 +86,24 @@ func TestGoEV2(t *testing.T) {
 		t.Error("missing PhaseSwitcher api")
 	}
 }
+
+func TestGoEV2Indicator(t *testing.T) {
+	h := &handler{}
+	srv := httptest.NewServer(h)
+
+	h.expect("/api/status?filter=alw")
+	wb, err := NewGoE(srv.URL, "", 0)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ind, ok := wb.(api.Indicator)
+	if !ok {
+		t.Fatal("missing Indicator api")
+	}
+
+	h.expect("/api/set?cid=%22%2300ff00%22&cwc=%22%2300ff00%22&cch=%22%2300ff00%22&cfi=%22%2300ff00%22&lbr=127")
+	if err := ind.Indicate(api.Indication{Color: "#00ff00", Brightness: 50}); err != nil {
+		t.Error(err)
+	}
+}
@@ -296,3 +296,10 @@ func (wb *HeidelbergEC) WakeUp() error {
 	// return to normal operation by unlocking after ~10 sec
 	return wb.set(hecRegRemoteLock, 1)
 }
+
+var _ api.Closer = (*HeidelbergEC)(nil)
+
+// Close implements the api.Closer interface
+func (wb *HeidelbergEC) Close() error {
+	return wb.conn.Close()
+}
@@ -209,3 +209,10 @@ func (wb *Innogy) Diagnose() {
 		fmt.Printf("Firmware:\t%s\n", b)
 	}
 }
+
+var _ api.Closer = (*Innogy)(nil)
+
+// Close implements the api.Closer interface
+func (wb *Innogy) Close() error {
+	return wb.conn.Close()
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -280,6 +281,24 @@ func (c *Keba) MaxCurrentMillis(current float64) error {
 	return nil
 }
 
+var _ api.Indicator = (*Keba)(nil)
+
+// Indicate implements the api.Indicator interface
+func (c *Keba) Indicate(ind api.Indication) error {
+	if ind.Text == "" {
+		return nil
+	}
+
+	// display holds up to 23 characters, spaces are sent as $
+	text := strings.ReplaceAll(ind.Text, " ", "$")
+	if r := []rune(text); len(r) > 23 {
+		text = string(r[:23])
+	}
+
+	var resp string
+	return c.roundtrip(fmt.Sprintf("display 1 10 10 0 %s", text), 0, &resp)
+}
+
 // currentPower implements the api.Meter interface
 func (c *Keba) currentPower() (float64, error) {
 	var kr keba.Report3
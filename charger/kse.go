@@ -245,3 +245,10 @@ func (wb *KSE) Diagnose() {
 		}
 	}
 }
+
+var _ api.Closer = (*KSE)(nil)
+
+// Close implements the api.Closer interface
+func (wb *KSE) Close() error {
+	return wb.conn.Close()
+}
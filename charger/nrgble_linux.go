@@ -344,3 +344,11 @@ func (nrg *NRGKickBLE) Currents() (float64, float64, float64, error) {
 // 	nrg.log.TRACE.Printf("energy: %+v", res)
 // 	return float64(res.EnergyLastCharge) / 1000, nil
 // }
+
+var _ api.Closer = (*NRGKickBLE)(nil)
+
+// Close implements the api.Closer interface
+func (nrg *NRGKickBLE) Close() error {
+	nrg.close()
+	return nil
+}
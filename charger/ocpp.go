@@ -124,6 +124,7 @@ func NewOCPP(id string, connector int, idtag string, meterValues string, meterIn
 
 	select {
 	case <-time.After(timeout):
+		ocpp.Instance().Unregister(cp)
 		return nil, api.ErrTimeout
 	case <-cp.HasConnected():
 	}
@@ -436,3 +437,11 @@ func (c *OCPP) phases1p3p(phases int) error {
 // func (c *OCPP) Identify() (string, error) {
 // 	return "", errors.New("not implemented")
 // }
+
+var _ api.Closer = (*OCPP)(nil)
+
+// Close implements the api.Closer interface
+func (c *OCPP) Close() error {
+	ocpp.Instance().Unregister(c.cp)
+	return nil
+}
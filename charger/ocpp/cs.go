@@ -3,17 +3,34 @@ package ocpp
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/evcc-io/evcc/util"
 	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 )
 
 type CS struct {
 	mu  sync.Mutex
 	log *util.Logger
 	ocpp16.CentralSystem
-	cps map[string]*CP
+	cps          map[string]*CP
+	pending      map[string]*PendingChargePoint
+	rejected     map[string]bool
+	registration bool // unknown chargepoints wait for registration
+}
+
+// PendingChargePoint is an unknown chargepoint connected to the central system waiting for registration
+type PendingChargePoint struct {
+	ID        string    `json:"id"`
+	Vendor    string    `json:"vendor,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	Serial    string    `json:"serial,omitempty"`
+	Firmware  string    `json:"firmware,omitempty"`
+	Connected time.Time `json:"connected"`
 }
 
 func (cs *CS) Register(id string, cp *CP) error {
@@ -25,10 +42,80 @@ func (cs *CS) Register(id string, cp *CP) error {
 	}
 
 	cs.cps[id] = cp
+	delete(cs.rejected, id)
+
+	// chargepoint has been waiting for registration
+	if _, ok := cs.pending[id]; ok {
+		cs.log.INFO.Printf("chargepoint registered: %s", id)
+		delete(cs.pending, id)
+		cp.Connect()
+	}
+
+	return nil
+}
+
+// EnableRegistration keeps unknown chargepoints pending for registration instead of ignoring them
+func (cs *CS) EnableRegistration() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.registration = true
+}
+
+// Pending returns the unknown chargepoints waiting for registration
+func (cs *CS) Pending() []PendingChargePoint {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	res := make([]PendingChargePoint, 0, len(cs.pending))
+	for _, p := range cs.pending {
+		res = append(res, *p)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Connected.Before(res[j].Connected)
+	})
+
+	return res
+}
+
+// IsPending checks if the chargepoint is waiting for registration
+func (cs *CS) IsPending(id string) bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	_, ok := cs.pending[id]
+	return ok
+}
+
+// Reject removes the chargepoint from the pending chargepoints, further boot notifications are rejected
+func (cs *CS) Reject(id string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if _, ok := cs.pending[id]; !ok {
+		return fmt.Errorf("unknown charge point: %s", id)
+	}
+
+	cs.log.INFO.Printf("chargepoint rejected: %s", id)
+	delete(cs.pending, id)
+	cs.rejected[id] = true
 
 	return nil
 }
 
+// Unregister removes the chargepoint, e.g. when the charger is recreated
+func (cs *CS) Unregister(cp *CP) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	for id, c := range cs.cps {
+		if c == cp {
+			delete(cs.cps, id)
+		}
+	}
+}
+
 // errorHandler logs error channel
 func (cs *CS) errorHandler(errC <-chan error) {
 	for err := range errC {
@@ -62,18 +149,57 @@ func (cs *CS) NewChargePoint(chargePoint ocpp16.ChargePointConnection) {
 			return
 		}
 
-		cs.log.WARN.Printf("chargepoint connected, ignoring: %s", chargePoint.ID())
+		if !cs.registration || cs.rejected[chargePoint.ID()] {
+			cs.log.WARN.Printf("chargepoint connected, ignoring: %s", chargePoint.ID())
+			return
+		}
+
+		cs.log.WARN.Printf("chargepoint connected, pending registration: %s", chargePoint.ID())
+		cs.pending[chargePoint.ID()] = &PendingChargePoint{
+			ID:        chargePoint.ID(),
+			Connected: time.Now(),
+		}
 	} else {
 		cs.log.DEBUG.Printf("chargepoint connected: %s", chargePoint.ID())
 		cp.Connect()
 	}
 }
 
+// unregisteredBootNotification answers boot notifications of pending or rejected chargepoints
+func (cs *CS) unregisteredBootNotification(id string, request *core.BootNotificationRequest) (*core.BootNotificationConfirmation, bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	status := core.RegistrationStatusRejected
+
+	if p, ok := cs.pending[id]; ok {
+		status = core.RegistrationStatusPending
+
+		p.Vendor = request.ChargePointVendor
+		p.Model = request.ChargePointModel
+		p.Serial = request.ChargePointSerialNumber
+		p.Firmware = request.FirmwareVersion
+	} else if !cs.rejected[id] {
+		return nil, false
+	}
+
+	res := &core.BootNotificationConfirmation{
+		CurrentTime: types.NewDateTime(time.Now()),
+		Interval:    60,
+		Status:      status,
+	}
+
+	return res, true
+}
+
 func (cs *CS) ChargePointDisconnected(chargePoint ocpp16.ChargePointConnection) {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
 
-	if _, err := cs.chargepointByID(chargePoint.ID()); err != nil {
+	if _, ok := cs.pending[chargePoint.ID()]; ok {
+		cs.log.DEBUG.Printf("pending chargepoint disconnected: %s", chargePoint.ID())
+		delete(cs.pending, chargePoint.ID())
+	} else if _, err := cs.chargepointByID(chargePoint.ID()); err != nil {
 		cs.log.ERROR.Printf("chargepoint disconnected: %v", err)
 	} else {
 		cs.log.DEBUG.Printf("chargepoint disconnected: %s", chargePoint.ID())
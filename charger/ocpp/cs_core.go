@@ -56,6 +56,10 @@ func (cs *CS) OnAuthorize(id string, request *core.AuthorizeRequest) (*core.Auth
 }
 
 func (cs *CS) OnBootNotification(id string, request *core.BootNotificationRequest) (*core.BootNotificationConfirmation, error) {
+	if res, ok := cs.unregisteredBootNotification(id, request); ok {
+		return res, nil
+	}
+
 	cp, err := cs.chargepointByID(id)
 	if err != nil {
 		return nil, err
@@ -16,6 +16,8 @@ func Instance() *CS {
 		instance = &CS{
 			log:           util.NewLogger("ocpp"),
 			cps:           make(map[string]*CP),
+			pending:       make(map[string]*PendingChargePoint),
+			rejected:      make(map[string]bool),
 			CentralSystem: cs,
 		}
 
@@ -179,3 +179,10 @@ func (wb *PhoenixEMEth) currents() (float64, float64, float64, error) {
 
 	return currents[0], currents[1], currents[2], nil
 }
+
+var _ api.Closer = (*PhoenixEMEth)(nil)
+
+// Close implements the api.Closer interface
+func (wb *PhoenixEMEth) Close() error {
+	return wb.conn.Close()
+}
@@ -180,3 +180,10 @@ func (wb *PhoenixEVEth) currents() (float64, float64, float64, error) {
 
 	return currents[0], currents[1], currents[2], nil
 }
+
+var _ api.Closer = (*PhoenixEVEth)(nil)
+
+// Close implements the api.Closer interface
+func (wb *PhoenixEVEth) Close() error {
+	return wb.conn.Close()
+}
@@ -96,3 +96,10 @@ func (wb *PhoenixEVSer) MaxCurrent(current int64) error {
 
 	return err
 }
+
+var _ api.Closer = (*PhoenixEVSer)(nil)
+
+// Close implements the api.Closer interface
+func (wb *PhoenixEVSer) Close() error {
+	return wb.conn.Close()
+}
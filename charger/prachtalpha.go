@@ -170,3 +170,10 @@ func (wb *PrachtAlpha) MaxCurrent(current int64) error {
 	}
 	return err
 }
+
+var _ api.Closer = (*PrachtAlpha)(nil)
+
+// Close implements the api.Closer interface
+func (wb *PrachtAlpha) Close() error {
+	return wb.conn.Close()
+}
@@ -0,0 +1,122 @@
+package charger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Switched charger implementation for non-smart EVSEs like granny cables.
+// Charging is controlled by a switchable smart plug and measured by a separate meter.
+type Switched struct {
+	*switchSocket
+	clock  clock.Clock
+	sw     api.Charger
+	meter  api.Meter
+	mu     sync.Mutex
+	energy float64 // integrated energy in kWh if meter does not provide energy
+	power  float64
+	last   time.Time
+}
+
+func init() {
+	registry.Add("switched", NewSwitchedFromConfig)
+}
+
+// NewSwitchedFromConfig creates a switched charger from generic config
+func NewSwitchedFromConfig(other map[string]interface{}) (api.Charger, error) {
+	var cc struct {
+		Switch struct {
+			Type  string
+			Other map[string]interface{} `mapstructure:",remain"`
+		}
+		Meter struct {
+			Type  string
+			Other map[string]interface{} `mapstructure:",remain"`
+		}
+		StandbyPower float64
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	sw, err := NewFromConfig(cc.Switch.Type, cc.Switch.Other)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := meter.NewFromConfig(cc.Meter.Type, cc.Meter.Other)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSwitched(sw, m, cc.StandbyPower), nil
+}
+
+// NewSwitched creates a switched charger from smart plug and meter
+func NewSwitched(sw api.Charger, m api.Meter, standbypower float64) *Switched {
+	c := &Switched{
+		clock: clock.New(),
+		sw:    sw,
+		meter: m,
+	}
+
+	c.switchSocket = NewSwitchSocket(c.Enabled, c.measure, standbypower)
+
+	return c
+}
+
+// measure reads the meter and integrates energy if the meter does not provide it
+func (c *Switched) measure() (float64, error) {
+	power, err := c.meter.CurrentPower()
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	if !c.last.IsZero() {
+		c.energy += c.power * now.Sub(c.last).Hours() / 1e3
+	}
+
+	c.power = power
+	c.last = now
+
+	return power, nil
+}
+
+// Enabled implements the api.Charger interface
+func (c *Switched) Enabled() (bool, error) {
+	return c.sw.Enabled()
+}
+
+// Enable implements the api.Charger interface
+func (c *Switched) Enable(enable bool) error {
+	return c.sw.Enable(enable)
+}
+
+// MaxCurrent implements the api.Charger interface
+func (c *Switched) MaxCurrent(current int64) error {
+	return nil
+}
+
+var _ api.MeterEnergy = (*Switched)(nil)
+
+// TotalEnergy implements the api.MeterEnergy interface
+func (c *Switched) TotalEnergy() (float64, error) {
+	if m, ok := c.meter.(api.MeterEnergy); ok {
+		return m.TotalEnergy()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.energy, nil
+}
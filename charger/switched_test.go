@@ -0,0 +1,49 @@
+package charger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwitched(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sw := mock.NewMockCharger(ctrl)
+	m := mock.NewMockMeter(ctrl)
+
+	clck := clock.NewMock()
+	c := NewSwitched(sw, m, 10)
+	c.clock = clck
+
+	sw.EXPECT().Enable(true).Return(nil)
+	assert.NoError(t, c.Enable(true))
+
+	// charging
+	m.EXPECT().CurrentPower().Return(2000.0, nil)
+	status, err := c.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, api.StatusC, status)
+
+	// energy integrated from power
+	clck.Add(30 * time.Minute)
+	m.EXPECT().CurrentPower().Return(5.0, nil)
+	power, err := c.CurrentPower()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, power)
+
+	energy, err := c.TotalEnergy()
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, energy)
+
+	// standby
+	m.EXPECT().CurrentPower().Return(5.0, nil)
+	status, err = c.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, api.StatusB, status)
+}
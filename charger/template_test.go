@@ -5,6 +5,7 @@ import (
 
 	"github.com/evcc-io/evcc/util/templates"
 	"github.com/evcc-io/evcc/util/test"
+	"github.com/evcc-io/evcc/util/test/replay"
 	"golang.org/x/exp/slices"
 )
 
@@ -59,3 +60,9 @@ func TestTemplates(t *testing.T) {
 		})
 	}
 }
+
+func TestReplay(t *testing.T) {
+	replay.Run(t, "testdata/replay/*.yaml", func(values map[string]interface{}) (interface{}, error) {
+		return NewFromConfig("template", values)
+	})
+}
@@ -245,3 +245,10 @@ func (wb *Vestel) Diagnose() {
 		fmt.Printf("Firmware:\t%s\n", b)
 	}
 }
+
+var _ api.Closer = (*Vestel)(nil)
+
+// Close implements the api.Closer interface
+func (wb *Vestel) Close() error {
+	return wb.conn.Close()
+}
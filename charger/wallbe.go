@@ -231,3 +231,10 @@ func (wb *Wallbe) Diagnose() {
 		fmt.Printf("Firmware:\t%s\n", encoding.StringLsbFirst(b))
 	}
 }
+
+var _ api.Closer = (*Wallbe)(nil)
+
+// Close implements the api.Closer interface
+func (wb *Wallbe) Close() error {
+	return wb.conn.Close()
+}
@@ -298,3 +298,10 @@ func (wb *WebastoNext) Diagnose() {
 		fmt.Printf("Error code:\t%v\n", binary.BigEndian.Uint16(b))
 	}
 }
+
+var _ api.Closer = (*WebastoNext)(nil)
+
+// Close implements the api.Closer interface
+func (wb *WebastoNext) Close() error {
+	return wb.conn.Close()
+}
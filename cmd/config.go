@@ -14,36 +14,44 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/cmd/shutdown"
+	"github.com/evcc-io/evcc/dyndns"
 	"github.com/evcc-io/evcc/meter"
 	"github.com/evcc-io/evcc/provider/mqtt"
 	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/relay"
 	"github.com/evcc-io/evcc/server"
 	autoauth "github.com/evcc-io/evcc/server/auth"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
+	"github.com/evcc-io/evcc/util/templates"
 	"github.com/evcc-io/evcc/vehicle"
 	"github.com/evcc-io/evcc/vehicle/wrapper"
 	"github.com/gorilla/handlers"
-	"github.com/gorilla/mux"
 	"golang.org/x/exp/maps"
 	"golang.org/x/sync/errgroup"
 )
 
-var conf = config{
-	Interval: 10 * time.Second,
-	Log:      "info",
-	Network: networkConfig{
-		Schema: "http",
-		Host:   "evcc.local",
-		Port:   7070,
-	},
-	Mqtt: mqttConfig{
-		Topic: "evcc",
-	},
-	Database: dbConfig{
-		Type: "sqlite",
-		Dsn:  "~/.evcc/evcc.db",
-	},
+var conf = defaultConfig()
+
+// defaultConfig returns the configuration defaults applied before reading the config file
+func defaultConfig() config {
+	return config{
+		Interval: 10 * time.Second,
+		Log:      "info",
+		Network: networkConfig{
+			Schema: "http",
+			Host:   "evcc.local",
+			Port:   7070,
+		},
+		Mqtt: mqttConfig{
+			Topic: "evcc",
+		},
+		Database: dbConfig{
+			Type: "sqlite",
+			Dsn:  "~/.evcc/evcc.db",
+		},
+	}
 }
 
 type config struct {
@@ -59,12 +67,19 @@ type config struct {
 	Interval     time.Duration
 	Mqtt         mqttConfig
 	ModbusProxy  []proxyConfig
+	VehicleProxy vehicleProxyConfig
 	Database     dbConfig
 	Javascript   map[string]interface{}
+	I18n         i18nConfig
 	Influx       server.InfluxConfig
 	EEBus        map[string]interface{}
 	HEMS         typedConfig
+	DynDNS       dynDNSConfig
+	Relay        relay.Config
 	Messaging    messagingConfig
+	Tenancy      server.TenancyConfig
+	Watchdog     server.WatchdogConfig
+	OCPP         ocppConfig
 	Meters       []qualifiedConfig
 	Chargers     []qualifiedConfig
 	Vehicles     []qualifiedConfig
@@ -76,6 +91,8 @@ type config struct {
 type mqttConfig struct {
 	mqtt.Config `mapstructure:",squash"`
 	Topic       string
+	Permissions []server.MQTTPermission
+	Queue       int // messages buffered in the database during outages
 }
 
 type proxyConfig struct {
@@ -84,6 +101,28 @@ type proxyConfig struct {
 	modbus.Settings `mapstructure:",squash"`
 }
 
+type ocppConfig struct {
+	Registration bool           // accept unknown chargepoints using the api
+	Templates    []ocppTemplate // defaults of the accepted chargepoints
+}
+
+type ocppTemplate struct {
+	Name      string
+	Charger   map[string]interface{} // ocpp charger parameters, e.g. connector or idtag
+	LoadPoint map[string]interface{} // loadpoint configuration
+}
+
+type vehicleProxyConfig struct {
+	Port  int
+	Token string
+	Cache time.Duration
+}
+
+type i18nConfig struct {
+	Language string // default language
+	Path     string // directory with additional translation files
+}
+
 type dbConfig struct {
 	Type string
 	Dsn  string
@@ -99,6 +138,13 @@ type typedConfig struct {
 	Other map[string]interface{} `mapstructure:",remain"`
 }
 
+type dynDNSConfig struct {
+	Type     string
+	Interval time.Duration
+	ACME     *dyndns.ACMEConfig
+	Other    map[string]interface{} `mapstructure:",remain"`
+}
+
 type messagingConfig struct {
 	Events   map[string]push.EventTemplateConfig
 	Services []typedConfig
@@ -111,9 +157,22 @@ type tariffConfig struct {
 }
 
 type networkConfig struct {
-	Schema string
-	Host   string
-	Port   int
+	Schema         string
+	Host           string
+	Port           int
+	BasePath       string
+	TrustedProxies []string
+	Listen         []server.ListenConfig
+	User           string
+	Password       string
+}
+
+// ListenConfig returns the configured listeners defaulting to all interfaces at configured port
+func (c networkConfig) ListenConfig() []server.ListenConfig {
+	if len(c.Listen) > 0 {
+		return c.Listen
+	}
+	return []server.ListenConfig{{Address: fmt.Sprintf(":%d", c.Port)}}
 }
 
 func (c networkConfig) HostPort() string {
@@ -124,24 +183,38 @@ func (c networkConfig) HostPort() string {
 }
 
 func (c networkConfig) URI() string {
-	return fmt.Sprintf("%s://%s", c.Schema, c.HostPort())
+	return fmt.Sprintf("%s://%s%s", c.Schema, c.HostPort(), strings.TrimSuffix(c.BasePath, "/"))
 }
 
 // ConfigProvider provides configuration items
 type ConfigProvider struct {
+	mu       sync.Mutex
 	meters   map[string]api.Meter
 	chargers map[string]api.Charger
 	vehicles map[string]api.Vehicle
 	visited  map[string]bool
+	used     map[string]bool // devices referenced by loadpoints and site
 	auth     *util.AuthCollection
+	devices  []server.DeviceConfig // devices managed by the config api
 }
 
 func (cp *ConfigProvider) TrackVisitors() {
 	cp.visited = make(map[string]bool)
+	cp.used = make(map[string]bool)
+}
+
+// use marks a device as referenced while tracking visitors
+func (cp *ConfigProvider) use(class templates.Class, name string) {
+	if cp.used != nil {
+		cp.used[string(class)+"."+name] = true
+	}
 }
 
 // Meter provides meters by name
 func (cp *ConfigProvider) Meter(name string) (api.Meter, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
 	if meter, ok := cp.meters[name]; ok {
 		// track duplicate usage https://github.com/evcc-io/evcc/issues/1744
 		if cp.visited != nil {
@@ -151,6 +224,8 @@ func (cp *ConfigProvider) Meter(name string) (api.Meter, error) {
 			cp.visited[name] = true
 		}
 
+		cp.use(templates.Meter, name)
+
 		return meter, nil
 	}
 	return nil, fmt.Errorf("meter does not exist: %s", name)
@@ -158,7 +233,11 @@ func (cp *ConfigProvider) Meter(name string) (api.Meter, error) {
 
 // Charger provides chargers by name
 func (cp *ConfigProvider) Charger(name string) (api.Charger, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
 	if charger, ok := cp.chargers[name]; ok {
+		cp.use(templates.Charger, name)
 		return charger, nil
 	}
 	return nil, fmt.Errorf("charger does not exist: %s", name)
@@ -166,13 +245,47 @@ func (cp *ConfigProvider) Charger(name string) (api.Charger, error) {
 
 // Vehicle provides vehicles by name
 func (cp *ConfigProvider) Vehicle(name string) (api.Vehicle, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
 	if vehicle, ok := cp.vehicles[name]; ok {
+		cp.use(templates.Vehicle, name)
 		return vehicle, nil
 	}
 	return nil, fmt.Errorf("vehicle does not exist: %s", name)
 }
 
+// Vehicles provides all vehicles for the site
+func (cp *ConfigProvider) Vehicles() []api.Vehicle {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	res := make([]api.Vehicle, 0, len(cp.vehicles))
+	for name, v := range cp.vehicles {
+		cp.use(templates.Vehicle, name)
+		res = append(res, v)
+	}
+
+	return res
+}
+
+// VehicleName returns the configured name of the vehicle
+func (cp *ConfigProvider) VehicleName(vehicle api.Vehicle) string {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	for name, v := range cp.vehicles {
+		if v == vehicle {
+			return name
+		}
+	}
+	return ""
+}
+
 func (cp *ConfigProvider) configure(conf config) error {
+	// devices are closed after the loadpoints have stopped their sessions
+	shutdown.Finalize(cp.closeDevices)
+
 	err := cp.configureMeters(conf)
 	if err == nil {
 		err = cp.configureChargers(conf)
@@ -180,17 +293,103 @@ func (cp *ConfigProvider) configure(conf config) error {
 	if err == nil {
 		err = cp.configureVehicles(conf)
 	}
+	if err == nil {
+		err = cp.configureDevices()
+	}
 	return err
 }
 
+// closeDevice releases the device's resources if supported
+func closeDevice(name string, dev any) {
+	if c, ok := dev.(api.Closer); ok {
+		if err := c.Close(); err != nil {
+			log.ERROR.Printf("closing %s: %v", name, err)
+		}
+	}
+}
+
+// closeDevices releases the resources of all configured devices
+func (cp *ConfigProvider) closeDevices() {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	for name, m := range cp.meters {
+		closeDevice(name, m)
+	}
+	for name, c := range cp.chargers {
+		closeDevice(name, c)
+	}
+	for name, v := range cp.vehicles {
+		closeDevice(name, v)
+	}
+}
+
+// newMeter creates a meter
+func newMeter(cc qualifiedConfig) (m api.Meter, err error) {
+	server.WithDeviceLabel("meter."+cc.Name, func() {
+		m, err = meter.NewFromConfig(cc.Type, cc.Other)
+	})
+	if err == nil {
+		m = meter.Faulty(cc.Name, m)
+	}
+	return m, err
+}
+
+// newCharger creates a charger
+func newCharger(cc qualifiedConfig) (c api.Charger, err error) {
+	server.WithDeviceLabel("charger."+cc.Name, func() {
+		c, err = charger.NewFromConfig(cc.Type, cc.Other)
+	})
+	return c, err
+}
+
+// newVehicle creates a vehicle. Creation errors are wrapped to prevent fatals.
+func newVehicle(cc qualifiedConfig) (api.Vehicle, error) {
+	// ensure vehicle config has title without modifying the configuration
+	var ccWithTitle struct {
+		Title string
+		Other map[string]interface{} `mapstructure:",remain"`
+	}
+
+	if err := util.DecodeOther(cc.Other, &ccWithTitle); err != nil {
+		return nil, err
+	}
+
+	other := maps.Clone(cc.Other)
+	if ccWithTitle.Title == "" {
+		if other == nil {
+			other = make(map[string]interface{})
+		}
+
+		//lint:ignore SA1019 as Title is safe on ascii
+		other["title"] = strings.Title(cc.Name)
+	}
+
+	var v api.Vehicle
+	var err error
+	server.WithDeviceLabel("vehicle."+cc.Name, func() {
+		v, err = vehicle.NewFromConfig(cc.Type, other)
+	})
+	if err != nil {
+		// wrap any created errors to prevent fatals
+		v, _ = wrapper.New(v, err)
+	}
+
+	return v, nil
+}
+
 func (cp *ConfigProvider) configureMeters(conf config) error {
+	for name, m := range cp.meters {
+		closeDevice(name, m)
+	}
+
 	cp.meters = make(map[string]api.Meter)
 	for id, cc := range conf.Meters {
 		if cc.Name == "" {
 			return fmt.Errorf("cannot create %s meter: missing name", humanize.Ordinal(id+1))
 		}
 
-		m, err := meter.NewFromConfig(cc.Type, cc.Other)
+		m, err := newMeter(cc)
 		if err != nil {
 			err = fmt.Errorf("cannot create meter '%s': %w", cc.Name, err)
 			return err
@@ -210,6 +409,10 @@ func (cp *ConfigProvider) configureChargers(conf config) error {
 	var mu sync.Mutex
 	g, _ := errgroup.WithContext(context.Background())
 
+	for name, c := range cp.chargers {
+		closeDevice(name, c)
+	}
+
 	cp.chargers = make(map[string]api.Charger)
 	for id, cc := range conf.Chargers {
 		if cc.Name == "" {
@@ -219,7 +422,7 @@ func (cp *ConfigProvider) configureChargers(conf config) error {
 		cc := cc
 
 		g.Go(func() error {
-			c, err := charger.NewFromConfig(cc.Type, cc.Other)
+			c, err := newCharger(cc)
 			if err != nil {
 				return fmt.Errorf("cannot create charger '%s': %w", cc.Name, err)
 			}
@@ -243,6 +446,10 @@ func (cp *ConfigProvider) configureVehicles(conf config) error {
 	var mu sync.Mutex
 	g, _ := errgroup.WithContext(context.Background())
 
+	for name, v := range cp.vehicles {
+		closeDevice(name, v)
+	}
+
 	cp.vehicles = make(map[string]api.Vehicle)
 	for id, cc := range conf.Vehicles {
 		if cc.Name == "" {
@@ -252,25 +459,9 @@ func (cp *ConfigProvider) configureVehicles(conf config) error {
 		cc := cc
 
 		g.Go(func() error {
-			// ensure vehicle config has title
-			var ccWithTitle struct {
-				Title string
-				Other map[string]interface{} `mapstructure:",remain"`
-			}
-
-			if err := util.DecodeOther(cc.Other, &ccWithTitle); err != nil {
-				return err
-			}
-
-			if ccWithTitle.Title == "" {
-				//lint:ignore SA1019 as Title is safe on ascii
-				cc.Other["title"] = strings.Title(cc.Name)
-			}
-
-			v, err := vehicle.NewFromConfig(cc.Type, cc.Other)
+			v, err := newVehicle(cc)
 			if err != nil {
-				// wrap any created errors to prevent fatals
-				v, _ = wrapper.New(v, err)
+				return err
 			}
 
 			mu.Lock()
@@ -289,8 +480,9 @@ func (cp *ConfigProvider) configureVehicles(conf config) error {
 }
 
 // webControl handles routing for devices. For now only api.AuthProvider related routes
-func (cp *ConfigProvider) webControl(conf networkConfig, router *mux.Router, paramC chan<- util.Param) {
-	auth := router.PathPrefix("/oauth").Subrouter()
+func (cp *ConfigProvider) webControl(conf networkConfig, httpd *server.HTTPd, paramC chan<- util.Param) {
+	auth := httpd.Router().PathPrefix("/oauth").Subrouter()
+	auth.Use(httpd.AdminHandler)
 	auth.Use(handlers.CompressHandler)
 	auth.Use(handlers.CORS(
 		handlers.AllowedHeaders([]string{"Content-Type"}),
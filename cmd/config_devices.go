@@ -0,0 +1,387 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/evcc-io/evcc/vehicle"
+	"github.com/evcc-io/evcc/vehicle/wrapper"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// devicesSetting is the settings key of the devices managed by the config api
+const devicesSetting = "config.devices"
+
+var _ server.DeviceConfigurator = (*ConfigProvider)(nil)
+
+// configureDevices creates the devices persisted by the config api
+func (cp *ConfigProvider) configureDevices() error {
+	var devices []server.DeviceConfig
+	if err := settings.Json(devicesSetting, &devices); err != nil && !errors.Is(err, settings.ErrNotFound) {
+		return fmt.Errorf("cannot load devices: %w", err)
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	for _, dc := range devices {
+		if cp.exists(dc.Class, dc.Name) {
+			return fmt.Errorf("duplicate %s name: %s already defined and must be unique", dc.Class, dc.Name)
+		}
+
+		dev, err := newDevice(dc)
+		if err != nil {
+			if templates.Class(dc.Class) != templates.Vehicle {
+				return err
+			}
+
+			// wrap any created errors to prevent fatals
+			dev, _ = wrapper.New(nil, err)
+		}
+
+		cp.setDevice(dc.Class, dc.Name, dev)
+	}
+
+	cp.devices = devices
+
+	return nil
+}
+
+// newDevice creates a device from its template configuration
+func newDevice(dc server.DeviceConfig) (dev any, err error) {
+	other := maps.Clone(dc.Other)
+	if other == nil {
+		other = make(map[string]interface{})
+	}
+
+	if templates.Class(dc.Class) == templates.Vehicle {
+		if title, ok := other["title"].(string); !ok || title == "" {
+			//lint:ignore SA1019 as Title is safe on ascii
+			other["title"] = strings.Title(dc.Name)
+		}
+	}
+
+	server.WithDeviceLabel(dc.Class+"."+dc.Name, func() {
+		switch templates.Class(dc.Class) {
+		case templates.Meter:
+			var m api.Meter
+			if m, err = meter.NewFromConfig("template", other); err == nil {
+				dev = meter.Faulty(dc.Name, m)
+			}
+		case templates.Charger:
+			dev, err = charger.NewFromConfig("template", other)
+		case templates.Vehicle:
+			dev, err = vehicle.NewFromConfig("template", other)
+		default:
+			err = fmt.Errorf("invalid device class: %s", dc.Class)
+		}
+	})
+
+	if err != nil {
+		err = fmt.Errorf("cannot create %s '%s': %w", dc.Class, dc.Name, err)
+	}
+
+	return dev, err
+}
+
+// exists checks if a device name is already used within its class
+func (cp *ConfigProvider) exists(class, name string) (ok bool) {
+	switch templates.Class(class) {
+	case templates.Meter:
+		_, ok = cp.meters[name]
+	case templates.Charger:
+		_, ok = cp.chargers[name]
+	case templates.Vehicle:
+		_, ok = cp.vehicles[name]
+	}
+	return ok
+}
+
+// cloneDevices copies a device map since it may be shared, e.g. with the vehicle proxy
+func cloneDevices[T any](m map[string]T) map[string]T {
+	res := make(map[string]T, len(m)+1)
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
+}
+
+// setDevice adds or replaces a device
+func (cp *ConfigProvider) setDevice(class, name string, dev any) {
+	switch templates.Class(class) {
+	case templates.Meter:
+		cp.meters = cloneDevices(cp.meters)
+		cp.meters[name] = dev.(api.Meter)
+	case templates.Charger:
+		cp.chargers = cloneDevices(cp.chargers)
+		cp.chargers[name] = dev.(api.Charger)
+	case templates.Vehicle:
+		cp.vehicles = cloneDevices(cp.vehicles)
+		cp.vehicles[name] = dev.(api.Vehicle)
+	}
+}
+
+// removeDevice closes and removes a device
+func (cp *ConfigProvider) removeDevice(class, name string) {
+	switch templates.Class(class) {
+	case templates.Meter:
+		closeDevice(name, cp.meters[name])
+		cp.meters = cloneDevices(cp.meters)
+		delete(cp.meters, name)
+	case templates.Charger:
+		closeDevice(name, cp.chargers[name])
+		cp.chargers = cloneDevices(cp.chargers)
+		delete(cp.chargers, name)
+	case templates.Vehicle:
+		closeDevice(name, cp.vehicles[name])
+		cp.vehicles = cloneDevices(cp.vehicles)
+		delete(cp.vehicles, name)
+	}
+}
+
+// inUse returns an error if the device is referenced by loadpoints or site.
+// These keep using the device instance, it must not be replaced or closed.
+// Chargers of ocpp registrations are in use even if their loadpoint is not applied yet.
+func (cp *ConfigProvider) inUse(class, name string) error {
+	if cp.used[class+"."+name] {
+		return fmt.Errorf("%s %s: %w by loadpoint or site", class, name, server.ErrDeviceInUse)
+	}
+
+	if templates.Class(class) == templates.Charger {
+		regs, err := ocppRegistrations()
+		if err != nil {
+			return err
+		}
+
+		for _, reg := range regs {
+			if reg.LoadPoint["charger"] == name {
+				return fmt.Errorf("%s %s: %w by ocpp registration %s", class, name, server.ErrDeviceInUse, reg.StationID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// secretParam checks if the template param contains credentials
+func secretParam(tmpl templates.Template, key string) bool {
+	for _, p := range tmpl.Params {
+		if strings.EqualFold(p.Name, key) && p.Mask {
+			return true
+		}
+	}
+
+	key = strings.ToLower(key)
+	return strings.Contains(key, "password") || strings.Contains(key, "token") || strings.Contains(key, "secret")
+}
+
+// redactDevice replaces the device's credentials
+func redactDevice(dc server.DeviceConfig) server.DeviceConfig {
+	name, _ := dc.Other["template"].(string)
+	tmpl, _ := templates.ByName(templates.Class(dc.Class), name)
+
+	other := maps.Clone(dc.Other)
+	for k, v := range other {
+		if v != "" && secretParam(tmpl, k) {
+			other[k] = util.RedactReplacement
+		}
+	}
+	dc.Other = other
+
+	return dc
+}
+
+// unredactDevice restores the credentials of a redacted device configuration from the previous configuration
+func unredactDevice(dc, prev server.DeviceConfig) server.DeviceConfig {
+	other := maps.Clone(dc.Other)
+	for k, v := range other {
+		if v == util.RedactReplacement {
+			other[k] = prev.Other[k]
+		}
+	}
+	dc.Other = other
+
+	return dc
+}
+
+// deviceIndex returns the index of a device managed by the config api
+func (cp *ConfigProvider) deviceIndex(class, name string) int {
+	return slices.IndexFunc(cp.devices, func(dc server.DeviceConfig) bool {
+		return dc.Class == class && dc.Name == name
+	})
+}
+
+// persistDevices writes the devices managed by the config api to the settings database.
+// The devices are applied by the caller only after they have been persisted.
+func (cp *ConfigProvider) persistDevices(devices []server.DeviceConfig) error {
+	if err := settings.SetJson(devicesSetting, devices); err != nil {
+		return err
+	}
+
+	if err := settings.Persist(); err != nil {
+		// restore the unchanged devices
+		_ = settings.SetJson(devicesSetting, cp.devices)
+		return err
+	}
+
+	return nil
+}
+
+// Devices implements server.DeviceConfigurator. Credentials are redacted.
+func (cp *ConfigProvider) Devices() []server.DeviceConfig {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	res := make([]server.DeviceConfig, 0, len(cp.devices))
+	for _, dc := range cp.devices {
+		res = append(res, redactDevice(dc))
+	}
+
+	return res
+}
+
+// AddDevice implements server.DeviceConfigurator
+func (cp *ConfigProvider) AddDevice(dc server.DeviceConfig) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if cp.exists(dc.Class, dc.Name) {
+		return fmt.Errorf("duplicate %s name: %s already defined and must be unique", dc.Class, dc.Name)
+	}
+
+	dev, err := newDevice(dc)
+	if err != nil {
+		return err
+	}
+
+	devices := append(slices.Clone(cp.devices), dc)
+	if err := cp.persistDevices(devices); err != nil {
+		closeDevice(dc.Name, dev)
+		return err
+	}
+
+	cp.setDevice(dc.Class, dc.Name, dev)
+	cp.devices = devices
+
+	return nil
+}
+
+// UpdateDevice implements server.DeviceConfigurator. Devices used by loadpoints or site cannot be updated.
+// Redacted credentials keep their previous value.
+func (cp *ConfigProvider) UpdateDevice(dc server.DeviceConfig) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	idx := cp.deviceIndex(dc.Class, dc.Name)
+	if idx < 0 {
+		return fmt.Errorf("%s %s: %w", dc.Class, dc.Name, server.ErrDeviceNotFound)
+	}
+
+	if err := cp.inUse(dc.Class, dc.Name); err != nil {
+		return err
+	}
+
+	dc = unredactDevice(dc, cp.devices[idx])
+
+	dev, err := newDevice(dc)
+	if err != nil {
+		return err
+	}
+
+	devices := slices.Clone(cp.devices)
+	devices[idx] = dc
+
+	if err := cp.persistDevices(devices); err != nil {
+		closeDevice(dc.Name, dev)
+		return err
+	}
+
+	cp.removeDevice(dc.Class, dc.Name)
+	cp.setDevice(dc.Class, dc.Name, dev)
+	cp.devices = devices
+
+	return nil
+}
+
+// DeleteDevice implements server.DeviceConfigurator. Devices used by loadpoints or site cannot be deleted.
+func (cp *ConfigProvider) DeleteDevice(class, name string) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	idx := cp.deviceIndex(class, name)
+	if idx < 0 {
+		return fmt.Errorf("%s %s: %w", class, name, server.ErrDeviceNotFound)
+	}
+
+	if err := cp.inUse(class, name); err != nil {
+		return err
+	}
+
+	devices := slices.Delete(slices.Clone(cp.devices), idx, idx+1)
+	if err := cp.persistDevices(devices); err != nil {
+		return err
+	}
+
+	cp.removeDevice(class, name)
+	cp.devices = devices
+
+	return nil
+}
+
+// TestDevice implements server.DeviceConfigurator. The device is created, read and closed again.
+func (cp *ConfigProvider) TestDevice(dc server.DeviceConfig) (map[string]interface{}, error) {
+	dev, err := newDevice(dc)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDevice(dc.Name, dev)
+
+	res := make(map[string]interface{})
+
+	// chargers and vehicles may implement meter interfaces, hence switch by class
+	switch templates.Class(dc.Class) {
+	case templates.Meter:
+		m := dev.(api.Meter)
+		if res["power"], err = m.CurrentPower(); err != nil {
+			return nil, err
+		}
+		if m, ok := m.(api.MeterEnergy); ok {
+			res["energy"], err = m.TotalEnergy()
+		}
+		if m, ok := m.(api.Battery); ok && err == nil {
+			res["soc"], err = m.SoC()
+		}
+
+	case templates.Charger:
+		c := dev.(api.Charger)
+		if res["status"], err = c.Status(); err != nil {
+			return nil, err
+		}
+		res["enabled"], err = c.Enabled()
+
+	case templates.Vehicle:
+		v := dev.(api.Vehicle)
+		if res["soc"], err = v.SoC(); err != nil {
+			return nil, err
+		}
+		if v, ok := v.(api.VehicleRange); ok {
+			res["range"], err = v.Range()
+		}
+	}
+
+	// optional values
+	if err != nil && !errors.Is(err, api.ErrNotAvailable) {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigDevices(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", filepath.Join(t.TempDir(), "evcc.db")))
+	require.NoError(t, settings.Init())
+
+	dc := server.DeviceConfig{
+		Class: "vehicle",
+		Name:  "car",
+		Other: map[string]interface{}{"template": "offline", "capacity": 50.0},
+	}
+
+	cp := new(ConfigProvider)
+
+	res, err := cp.TestDevice(dc)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, res["soc"])
+	assert.Empty(t, cp.Devices())
+
+	require.NoError(t, cp.AddDevice(dc))
+	assert.Error(t, cp.AddDevice(dc), "duplicate")
+
+	v, err := cp.Vehicle("car")
+	require.NoError(t, err)
+	assert.Equal(t, "Car", v.Title())
+
+	dc.Other["title"] = "Mine"
+	require.NoError(t, cp.UpdateDevice(dc))
+
+	v, err = cp.Vehicle("car")
+	require.NoError(t, err)
+	assert.Equal(t, "Mine", v.Title())
+
+	// restored from settings
+	cp2 := new(ConfigProvider)
+	require.NoError(t, cp2.configureDevices())
+	assert.Equal(t, []server.DeviceConfig{dc}, cp2.Devices())
+
+	require.NoError(t, cp.DeleteDevice("vehicle", "car"))
+	assert.ErrorIs(t, cp.DeleteDevice("vehicle", "car"), server.ErrDeviceNotFound)
+	assert.ErrorIs(t, cp.UpdateDevice(dc), server.ErrDeviceNotFound)
+
+	_, err = cp.Vehicle("car")
+	assert.Error(t, err)
+
+	dc.Class = "foo"
+	assert.Error(t, cp.AddDevice(dc))
+}
+
+func TestConfigDevicesInUse(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", filepath.Join(t.TempDir(), "evcc.db")))
+	require.NoError(t, settings.Init())
+
+	dc := server.DeviceConfig{
+		Class: "vehicle",
+		Name:  "car",
+		Other: map[string]interface{}{"template": "offline", "capacity": 50.0},
+	}
+
+	cp := new(ConfigProvider)
+	require.NoError(t, cp.AddDevice(dc))
+
+	// used by site
+	cp.TrackVisitors()
+	assert.Len(t, cp.Vehicles(), 1)
+
+	assert.ErrorIs(t, cp.UpdateDevice(dc), server.ErrDeviceInUse)
+	assert.ErrorIs(t, cp.DeleteDevice("vehicle", "car"), server.ErrDeviceInUse)
+
+	_, err := cp.Vehicle("car")
+	require.NoError(t, err)
+}
+
+func TestConfigDevicesPersistFailure(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", filepath.Join(t.TempDir(), "evcc.db")))
+	require.NoError(t, settings.Init())
+
+	dc := server.DeviceConfig{
+		Class: "vehicle",
+		Name:  "car",
+		Other: map[string]interface{}{"template": "offline", "title": "Car", "capacity": 50.0},
+	}
+
+	cp := new(ConfigProvider)
+	require.NoError(t, cp.AddDevice(dc))
+
+	// database unavailable
+	sqlDB, err := db.Instance.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+
+	// add
+	assert.Error(t, cp.AddDevice(server.DeviceConfig{Class: "vehicle", Name: "other", Other: dc.Other}))
+	_, err = cp.Vehicle("other")
+	assert.Error(t, err)
+	assert.Len(t, cp.Devices(), 1)
+
+	// update keeps the previous device
+	assert.Error(t, cp.UpdateDevice(server.DeviceConfig{
+		Class: "vehicle",
+		Name:  "car",
+		Other: map[string]interface{}{"template": "offline", "title": "Mine", "capacity": 50.0},
+	}))
+	v, err := cp.Vehicle("car")
+	require.NoError(t, err)
+	assert.Equal(t, "Car", v.Title())
+	assert.Equal(t, "Car", cp.Devices()[0].Other["title"])
+
+	// delete keeps the device
+	assert.Error(t, cp.DeleteDevice("vehicle", "car"))
+	_, err = cp.Vehicle("car")
+	assert.NoError(t, err)
+}
+
+func TestConfigDevicesRedacted(t *testing.T) {
+	dc := server.DeviceConfig{
+		Class: "vehicle",
+		Name:  "car",
+		Other: map[string]interface{}{"template": "tesla", "accessToken": "access", "refreshToken": "refresh", "vin": "W1"},
+	}
+
+	res := redactDevice(dc)
+	assert.Equal(t, "***", res.Other["accessToken"])
+	assert.Equal(t, "***", res.Other["refreshToken"])
+	assert.Equal(t, "W1", res.Other["vin"])
+	assert.Equal(t, "access", dc.Other["accessToken"])
+
+	res.Other["vin"] = "W2"
+	res = unredactDevice(res, dc)
+	assert.Equal(t, "access", res.Other["accessToken"])
+	assert.Equal(t, "W2", res.Other["vin"])
+}
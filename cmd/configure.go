@@ -2,11 +2,14 @@ package cmd
 
 import (
 	_ "embed"
+	"errors"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/evcc-io/evcc/cmd/configure"
+	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/util"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -21,14 +24,25 @@ var configureCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(configureCmd)
-	configureCmd.Flags().String("lang", "", "Define the localization to be used (en, de)")
+	configureCmd.Flags().String("lang", "", "Define the localization to be used (de, en, es, fr, it, nl, pl), asks for the language if not set")
 	configureCmd.Flags().Bool("advanced", false, "Enables handling of advanced configuration options")
 	configureCmd.Flags().Bool("expand", false, "Enables rendering expanded configuration files")
-	configureCmd.Flags().String("category", "", "Pre-select device category for advanced configuration (implies advanced)")
+	configureCmd.Flags().String("category", "", "Configure a single device of the category (wallbox, grid, pv, battery, charge, vehicle)")
+	configureCmd.Flags().String("merge", "", "Merge the device configured using --category into the existing configuration file")
+	configureCmd.Flags().String("answers", "", "Run headless using pre-recorded answers from file")
+	configureCmd.Flags().Bool("validate", false, "Validate the devices of an existing configuration file, or the generated configuration with --dry-run")
+	configureCmd.Flags().Bool(flagDryRun, false, flagDryRunDescription)
 }
 
 func runConfigure(cmd *cobra.Command, args []string) {
-	impl := &configure.CmdConfigure{}
+	impl := &configure.CmdConfigure{
+		Logins: map[string]configure.Login{
+			"tesla":    {Token: teslaLogin, Refresh: teslaTokenRefresh},
+			"mercedes": {Token: mercedesToken, Refresh: mercedesTokenRefresh},
+			"bmw":      {Verify: bmwLogin("bmw")},
+			"mini":     {Verify: bmwLogin("mini")},
+		},
+	}
 
 	lang, err := cmd.Flags().GetString("lang")
 	if err != nil {
@@ -50,6 +64,30 @@ func runConfigure(cmd *cobra.Command, args []string) {
 		panic(err)
 	}
 
+	merge, err := cmd.Flags().GetString("merge")
+	if err != nil {
+		panic(err)
+	}
+
+	if merge != "" && category == "" {
+		log.FATAL.Fatal("--merge requires --category")
+	}
+
+	answers, err := cmd.Flags().GetString("answers")
+	if err != nil {
+		panic(err)
+	}
+
+	validate, err := cmd.Flags().GetBool("validate")
+	if err != nil {
+		panic(err)
+	}
+
+	dryRun, err := cmd.Flags().GetBool(flagDryRun)
+	if err != nil {
+		panic(err)
+	}
+
 	util.LogLevel(viper.GetString("log"), nil)
 
 	// catch signals
@@ -62,5 +100,101 @@ func runConfigure(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}()
 
-	impl.Run(log, lang, advanced, expand, category)
+	if validate && !dryRun {
+		runConfigureValidate(impl, lang)
+		return
+	}
+
+	if validate {
+		impl.Validator = validateConfiguration
+	}
+
+	impl.Run(log, lang, advanced, expand, dryRun, category, merge, answers)
+}
+
+// validateConfiguration checks the generated configuration and prints its diagnostics to stderr
+func validateConfiguration(b []byte) error {
+	diags := checkConfig(b)
+	for _, d := range diags {
+		fmt.Fprintf(os.Stderr, "%d:%d: %s: %s\n", d.Line, d.Column, d.Severity, d.Message)
+	}
+
+	if hasErrors(diags) {
+		return errors.New("generated configuration is invalid")
+	}
+
+	return nil
+}
+
+// runConfigureValidate tests all devices of the configuration file
+func runConfigureValidate(impl *configure.CmdConfigure, lang string) {
+	if err := loadConfigFile(&conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	devices, err := validateDevices(conf)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if !impl.Validate(log, lang, devices) {
+		os.Exit(1)
+	}
+}
+
+// validateDevices collects the configured devices and derives the meter categories from site and loadpoint references
+func validateDevices(conf config) ([]configure.ValidateDevice, error) {
+	var site struct {
+		Meters core.MetersConfig
+		Other  map[string]interface{} `mapstructure:",remain"`
+	}
+
+	if err := util.DecodeOther(conf.Site, &site); err != nil {
+		return nil, err
+	}
+
+	categories := make(map[string]configure.DeviceCategory)
+
+	for _, ref := range append([]string{site.Meters.PVMeterRef}, site.Meters.PVMetersRef...) {
+		categories[ref] = configure.DeviceCategoryPVMeter
+	}
+	for _, ref := range append([]string{site.Meters.BatteryMeterRef}, site.Meters.BatteryMetersRef...) {
+		categories[ref] = configure.DeviceCategoryBatteryMeter
+	}
+	categories[site.Meters.GridMeterRef] = configure.DeviceCategoryGridMeter
+
+	for _, other := range conf.LoadPoints {
+		var lp struct {
+			Meter string
+			Other map[string]interface{} `mapstructure:",remain"`
+		}
+
+		if err := util.DecodeOther(other, &lp); err != nil {
+			return nil, err
+		}
+
+		categories[lp.Meter] = configure.DeviceCategoryChargeMeter
+	}
+
+	var res []configure.ValidateDevice
+
+	for _, cc := range conf.Meters {
+		// meters not referenced by site or loadpoint are tested like charge meters
+		category, ok := categories[cc.Name]
+		if !ok || cc.Name == "" {
+			category = configure.DeviceCategoryChargeMeter
+		}
+
+		res = append(res, configure.ValidateDevice{Name: cc.Name, Category: category, Type: cc.Type, Other: cc.Other})
+	}
+
+	for _, cc := range conf.Chargers {
+		res = append(res, configure.ValidateDevice{Name: cc.Name, Category: configure.DeviceCategoryCharger, Type: cc.Type, Other: cc.Other})
+	}
+
+	for _, cc := range conf.Vehicles {
+		res = append(res, configure.ValidateDevice{Name: cc.Name, Category: configure.DeviceCategoryVehicle, Type: cc.Type, Other: cc.Other})
+	}
+
+	return res, nil
 }
@@ -0,0 +1,131 @@
+package configure
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/AlecAivazis/survey/v2"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// errAnswersExhausted indicates that all pre-recorded answers have been consumed
+var errAnswersExhausted = errors.New("answer file exhausted")
+
+// errAnswerMasked indicates that the masked answer has not been recorded and must be asked again
+var errAnswerMasked = errors.New("masked answer not recorded")
+
+// answers are pre-recorded answers consumed in order of the survey questions
+type answers struct {
+	Answers []string
+	pos     int
+	secrets []int // positions of masked answers not recorded in a resumed session
+	resume  bool  // continue interactively once exhausted
+}
+
+// loadAnswers reads an answer file for running the configuration headless
+func loadAnswers(file string) (*answers, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var res answers
+	if err := yaml.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("invalid answer file: %w", err)
+	}
+
+	return &res, nil
+}
+
+// answer fills the response for the prompt from the next pre-recorded answer and echoes it to w
+func (a *answers) answer(w io.Writer, p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+	var options survey.AskOptions
+	for _, opt := range opts {
+		if err := opt(&options); err != nil {
+			return err
+		}
+	}
+
+	message, value, err := a.value(w, p)
+	if err != nil {
+		return err
+	}
+
+	for _, validate := range options.Validators {
+		if err := validate(value); err != nil {
+			return fmt.Errorf("answer %d (%s): %w", a.pos, message, err)
+		}
+	}
+
+	switch res := response.(type) {
+	case *string:
+		*res = value.(string)
+	case *bool:
+		*res = value.(bool)
+	default:
+		return fmt.Errorf("answer %d (%s): invalid response type %T", a.pos, message, response)
+	}
+
+	return nil
+}
+
+// value converts the next answer to the value expected by the prompt
+func (a *answers) value(w io.Writer, p survey.Prompt) (string, interface{}, error) {
+	var message string
+	switch p := p.(type) {
+	case *survey.Select:
+		message = p.Message
+	case *survey.Confirm:
+		message = p.Message
+	case *survey.Input:
+		message = p.Message
+	case *survey.Password:
+		message = p.Message
+	}
+
+	if a.pos >= len(a.Answers) {
+		return message, nil, fmt.Errorf("%w at question: %s", errAnswersExhausted, message)
+	}
+
+	s := a.Answers[a.pos]
+	a.pos++
+
+	if _, ok := p.(*survey.Password); ok && slices.Contains(a.secrets, a.pos-1) {
+		return message, nil, errAnswerMasked
+	}
+
+	switch p := p.(type) {
+	case *survey.Select:
+		if !slices.Contains(p.Options, s) {
+			return message, nil, fmt.Errorf("answer %d (%s): %s is not one of %v", a.pos, message, s, p.Options)
+		}
+		fmt.Fprintln(w, message, s)
+		return message, s, nil
+
+	case *survey.Confirm:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return message, nil, fmt.Errorf("answer %d (%s): %s is not a boolean", a.pos, message, s)
+		}
+		fmt.Fprintln(w, message, b)
+		return message, b, nil
+
+	case *survey.Input:
+		if s == "" {
+			s = p.Default
+		}
+		fmt.Fprintln(w, message, s)
+		return message, s, nil
+
+	case *survey.Password:
+		fmt.Fprintln(w, message, "***")
+		return message, s, nil
+
+	default:
+		return message, nil, errors.New("unsupported prompt")
+	}
+}
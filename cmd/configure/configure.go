@@ -26,9 +26,18 @@ type loadpoint struct {
 	MinCurrent        int
 	MaxCurrent        int
 	Phases            int
+	Priority          int
 	ResetOnDisconnect string
 }
 
+// circuit is a group of loadpoints sharing a limited supply
+type circuit struct {
+	Name       string
+	MaxCurrent int
+	Strategy   string
+	Loadpoints []string
+}
+
 type config struct {
 	Meters     []device
 	Chargers   []device
@@ -39,6 +48,7 @@ type config struct {
 		Grid      string
 		PVs       []string
 		Batteries []string
+		Circuits  []circuit
 	}
 	Hems         string
 	EEBUS        string
@@ -57,6 +57,9 @@ loadpoints:
   phases: {{ .Phases }}
   mincurrent: {{ .MinCurrent }}
   maxcurrent: {{ .MaxCurrent }}
+{{-     if .Priority }}
+  priority: {{ .Priority }}
+{{-     end }}
   resetOnDisconnect: {{ .ResetOnDisconnect }}
 {{-   end }}
 {{- end }}
@@ -79,11 +82,30 @@ site:
     - {{ . }}
 {{-   end }}
 {{- end }}
+{{- if len .Site.Circuits }}
+  circuits:
+{{-   range .Site.Circuits }}
+  - name: {{ .Name }}
+    maxCurrent: {{ .MaxCurrent }}
+{{-     if .Strategy }}
+    strategy: {{ .Strategy }}
+{{-     end }}
+    loadpoints:
+{{-     range .Loadpoints }}
+    - {{ . | quote }}
+{{-     end }}
+{{-   end }}
+{{- end }}
 {{- if ne (len .Hems) 0 }}
 
 hems:
 {{ .Hems | indent 2 }}
 {{- end }}
+{{- if ne (len .MQTT) 0 }}
+
+mqtt:
+{{ .MQTT | indent 2 }}
+{{- end }}
 {{- if ne (len .EEBUS) 0 }}
 
 eebus:
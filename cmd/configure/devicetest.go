@@ -6,9 +6,11 @@ import (
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/devicetest"
 	"github.com/evcc-io/evcc/meter"
 	"github.com/evcc-io/evcc/util/templates"
 	"github.com/evcc-io/evcc/vehicle"
+	"golang.org/x/exp/maps"
 	"gopkg.in/yaml.v3"
 )
 
@@ -24,6 +26,7 @@ type DeviceTest struct {
 	DeviceCategory DeviceCategory
 	Template       templates.Template
 	ConfigValues   map[string]interface{}
+	Pairing        func() error // waits for the device to pair before testing
 }
 
 // Test returns:
@@ -35,6 +38,17 @@ func (d *DeviceTest) Test() (DeviceTestResult, error) {
 		return DeviceTestResultInvalid, err
 	}
 
+	// release device connections after testing
+	if c, ok := v.(api.Closer); ok {
+		defer c.Close()
+	}
+
+	if d.Pairing != nil {
+		if err := d.Pairing(); err != nil {
+			return DeviceTestResultInvalid, err
+		}
+	}
+
 	switch DeviceCategories[d.DeviceCategory].class {
 	case templates.Charger:
 		return d.testCharger(v)
@@ -52,7 +66,14 @@ func (d *DeviceTest) Test() (DeviceTestResult, error) {
 
 // configure creates a configured device from a template so we can test it
 func (d *DeviceTest) configure() (interface{}, error) {
-	b, _, err := d.Template.RenderResult(templates.TemplateRenderModeInstance, d.ConfigValues)
+	// the generic meter invert option is not a template param
+	values, invert := d.ConfigValues, d.ConfigValues["invert"]
+	if invert != nil {
+		values = maps.Clone(values)
+		delete(values, "invert")
+	}
+
+	b, _, err := d.Template.RenderResult(templates.TemplateRenderModeInstance, values)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +91,12 @@ func (d *DeviceTest) configure() (interface{}, error) {
 
 	switch DeviceCategories[d.DeviceCategory].class {
 	case templates.Meter:
+		if invert != nil {
+			if instance.Other == nil {
+				instance.Other = make(map[string]interface{})
+			}
+			instance.Other["invert"] = invert
+		}
 		v, err = meter.NewFromConfig(instance.Type, instance.Other)
 	case templates.Charger:
 		v, err = charger.NewFromConfig(instance.Type, instance.Other)
@@ -80,23 +107,35 @@ func (d *DeviceTest) configure() (interface{}, error) {
 	return v, err
 }
 
+// checkResult converts a failed or skipped check to an error
+func checkResult(r devicetest.Report, check string) (devicetest.Status, error) {
+	res, _ := r.Result(check)
+	if res.Status == devicetest.StatusFail {
+		return res.Status, errors.New(res.Error)
+	}
+	return res.Status, nil
+}
+
 // testCharger tests a charger device
 func (d *DeviceTest) testCharger(v interface{}) (DeviceTestResult, error) {
 	c, ok := v.(api.Charger)
 	if !ok {
 		return DeviceTestResultInvalid, errors.New("selected device is not a wallbox")
 	}
-	if _, err := c.Status(); err != nil {
+
+	r := devicetest.Charger(d.Template.Template, c, devicetest.Options{ReadOnly: true})
+
+	if _, err := checkResult(r, devicetest.CheckStatus); err != nil {
 		return DeviceTestResultInvalid, err
 	}
 
-	m, ok := v.(api.Meter)
-	if !ok {
-		return DeviceTestResultValidMissingMeter, nil
-	}
-	if _, err := m.CurrentPower(); err != nil {
+	status, err := checkResult(r, devicetest.CheckPower)
+	if err != nil {
 		return DeviceTestResultInvalid, err
 	}
+	if status == devicetest.StatusSkip {
+		return DeviceTestResultValidMissingMeter, nil
+	}
 
 	return DeviceTestResultValid, nil
 }
@@ -108,34 +147,27 @@ func (d *DeviceTest) testMeter(deviceCategory DeviceCategory, v interface{}) (De
 		return DeviceTestResultInvalid, errors.New("selected device is not a meter")
 	}
 
-	power, err := m.CurrentPower()
-	if err != nil {
+	r := devicetest.Meter(d.Template.Template, m)
+
+	if _, err := checkResult(r, devicetest.CheckPower); err != nil {
 		return DeviceTestResultInvalid, err
 	}
 
 	// check if the grid meter reports power 0, which should be impossible
 	// happens with Kostal Piko charger that do not have a grid meter attached
 	// but we can't determine this
-	if power == 0 && deviceCategory == DeviceCategoryGridMeter {
+	if res, _ := r.Result(devicetest.CheckPower); res.Value == 0.0 && deviceCategory == DeviceCategoryGridMeter {
 		return DeviceTestResultInvalid, errors.New("grid meter reports power 0")
 	}
 
 	if deviceCategory == DeviceCategoryBatteryMeter {
-		b, ok := v.(api.Battery)
-		if !ok {
-			return DeviceTestResultInvalid, errors.New("selected device is not a battery meter")
-		}
-
-		_, err := b.SoC()
-
-		for err != nil && errors.Is(err, api.ErrMustRetry) {
-			time.Sleep(3 * time.Second)
-			_, err = b.SoC()
-		}
-
+		status, err := checkResult(r, devicetest.CheckSoC)
 		if err != nil {
 			return DeviceTestResultInvalid, err
 		}
+		if status == devicetest.StatusSkip {
+			return DeviceTestResultInvalid, errors.New("selected device is not a battery meter")
+		}
 	}
 
 	return DeviceTestResultValid, nil
@@ -148,9 +180,121 @@ func (d *DeviceTest) testVehicle(v interface{}) (DeviceTestResult, error) {
 		return DeviceTestResultInvalid, errors.New("selected device is not a vehicle")
 	}
 
-	if _, err := vv.SoC(); err != nil {
+	r := devicetest.Vehicle(d.Template.Template, vv)
+
+	if _, err := checkResult(r, devicetest.CheckSoC); err != nil {
 		return DeviceTestResultInvalid, err
 	}
 
 	return DeviceTestResultValid, nil
 }
+
+// PreviewValues are the live values of a device read during preview
+type PreviewValues struct {
+	Power  *float64         // power in W
+	SoC    *float64         // battery soc in %
+	Status api.ChargeStatus // charger status
+	Error  error
+}
+
+// Preview reads the live values of a meter or charger for the given number of refreshes.
+// Vehicles are not previewed to avoid repeated logins.
+func (d *DeviceTest) Preview(refreshes int, interval time.Duration, show func(PreviewValues)) error {
+	v, err := d.configure()
+	if err != nil {
+		return err
+	}
+
+	if c, ok := v.(api.Closer); ok {
+		defer c.Close()
+	}
+
+	for i := 0; i < refreshes; i++ {
+		if i > 0 {
+			time.Sleep(interval)
+		}
+
+		show(preview(v))
+	}
+
+	return nil
+}
+
+// preview reads the device's current values
+func preview(v interface{}) PreviewValues {
+	var res PreviewValues
+
+	if c, ok := v.(api.Charger); ok {
+		if res.Status, res.Error = c.Status(); res.Error != nil {
+			return res
+		}
+	}
+
+	if m, ok := v.(api.Meter); ok {
+		power, err := m.CurrentPower()
+		if err != nil {
+			res.Error = err
+			return res
+		}
+		res.Power = &power
+	}
+
+	if b, ok := v.(api.Battery); ok {
+		soc, err := b.SoC()
+		if err != nil && !errors.Is(err, api.ErrNotAvailable) {
+			res.Error = err
+			return res
+		}
+		if err == nil {
+			res.SoC = &soc
+		}
+	}
+
+	return res
+}
+
+// PowerChange samples the meter power before and after switchOn returns and returns the change of the average power
+func (d *DeviceTest) PowerChange(samples int, interval time.Duration, switchOn func()) (float64, error) {
+	v, err := d.configure()
+	if err != nil {
+		return 0, err
+	}
+
+	if c, ok := v.(api.Closer); ok {
+		defer c.Close()
+	}
+
+	m, ok := v.(api.Meter)
+	if !ok {
+		return 0, errors.New("selected device is not a meter")
+	}
+
+	before, err := averagePower(m, samples, interval)
+	if err != nil {
+		return 0, err
+	}
+
+	switchOn()
+
+	after, err := averagePower(m, samples, interval)
+
+	return after - before, err
+}
+
+// averagePower returns the average of the sampled meter power
+func averagePower(m api.Meter, samples int, interval time.Duration) (float64, error) {
+	var sum float64
+
+	for i := 0; i < samples; i++ {
+		time.Sleep(interval)
+
+		power, err := m.CurrentPower()
+		if err != nil {
+			return 0, err
+		}
+
+		sum += power
+	}
+
+	return sum / float64(samples), nil
+}
@@ -0,0 +1,60 @@
+package configure
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+)
+
+const (
+	directionSamples  = 3
+	directionInterval = 2 * time.Second
+	directionMinPower = 500.0 // minimum grid power change in W caused by the known load
+)
+
+// detectGridDirection samples the grid meter while the user switches on a known load and returns if the meter is inverted.
+// Detection is interactive only, resumed sessions replay the detected direction.
+func (c *CmdConfigure) detectGridDirection(deviceTest DeviceTest) bool {
+	if c.answers != nil {
+		return c.session != nil && c.session.inverted()
+	}
+
+	fmt.Fprintln(c.console)
+
+	run := true
+	if err := c.askInteractive(&survey.Confirm{Message: c.localizedString("Direction_Run", nil), Default: true}, &run); err != nil || !run {
+		return false
+	}
+
+	fmt.Fprintln(c.console, c.localizedString("Direction_Baseline", nil))
+
+	delta, err := deviceTest.PowerChange(directionSamples, directionInterval, func() {
+		var enter string
+		_ = c.askInteractive(&survey.Input{Message: c.localizedString("Direction_SwitchOn", nil)}, &enter)
+		fmt.Fprintln(c.console, c.localizedString("Direction_Measuring", nil))
+	})
+	if err != nil {
+		fmt.Fprintln(c.console, "  ", c.localizedString("Error", localizeMap{"Error": err}))
+		return false
+	}
+
+	power := localizeMap{"Power": fmt.Sprintf("%.0f", delta)}
+
+	switch {
+	case delta <= -directionMinPower:
+		fmt.Fprintln(c.console, c.localizedString("Direction_Inverted", power))
+		if c.session != nil {
+			c.session.invert()
+		}
+		return true
+
+	case delta >= directionMinPower:
+		fmt.Fprintln(c.console, c.localizedString("Direction_Correct", power))
+
+	default:
+		fmt.Fprintln(c.console, c.localizedString("Direction_Inconclusive", power))
+	}
+
+	return false
+}
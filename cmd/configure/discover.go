@@ -0,0 +1,125 @@
+package configure
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/evcc-io/evcc/detect"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/korylprince/ipnetgen"
+	"golang.org/x/exp/slices"
+)
+
+// discovery is a device found on the local network
+type discovery struct {
+	template string
+	ip       string
+	values   map[string]string // param defaults, e.g. host and modbus id
+}
+
+// discoverDevices optionally scans the local network for known devices
+func (c *CmdConfigure) discoverDevices() {
+	fmt.Fprintln(c.console)
+	if !c.askYesNo(c.localizedString("Discovery_Run", nil)) {
+		return
+	}
+
+	hosts := localHosts()
+	if len(hosts) == 0 {
+		fmt.Fprintln(c.console, c.localizedString("Discovery_NoNetwork", nil))
+		return
+	}
+
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Discovery_Scanning", localizeMap{"Hosts": len(hosts)}))
+
+	for _, hit := range detect.Work(c.log, 50, hosts) {
+		values := map[string]string{"host": hit.IP}
+
+		if hit.ModbusResult != nil {
+			values[templates.ModbusParamNameId] = strconv.Itoa(int(hit.ModbusResult.SlaveID))
+			if hit.Port != 0 {
+				values[templates.ModbusParamNamePort] = strconv.Itoa(hit.Port)
+			}
+		}
+
+		for _, tmpl := range detect.Templates[hit.ID] {
+			c.discovered = append(c.discovered, discovery{template: tmpl, ip: hit.IP, values: values})
+		}
+	}
+
+	fmt.Fprintln(c.console, c.localizedString("Discovery_Found", localizeMap{"Count": len(c.discovered)}))
+}
+
+// localHosts returns the hosts of the local /24 networks
+func localHosts() []string {
+	var res []string
+
+	for _, ipnet := range util.LocalIPs() {
+		// limit scan to the host's /24 network
+		if ones, _ := ipnet.Mask.Size(); ones < 24 {
+			ipnet.Mask = net.CIDRMask(24, 32)
+		}
+
+		gen, err := ipnetgen.New((&net.IPNet{IP: ipnet.IP.Mask(ipnet.Mask), Mask: ipnet.Mask}).String())
+		if err != nil {
+			continue
+		}
+
+		var hosts []string
+		for ip := gen.Next(); ip != nil; ip = gen.Next() {
+			hosts = append(hosts, ip.String())
+		}
+
+		// remove network and broadcast address
+		if len(hosts) > 2 {
+			res = append(res, hosts[1:len(hosts)-1]...)
+		}
+	}
+
+	return res
+}
+
+// discoveredElements returns the discovered devices matching the category's templates, titled with their address
+func (c *CmdConfigure) discoveredElements(elements []templates.Template) []templates.Template {
+	var res []templates.Template
+
+	for _, d := range c.discovered {
+		idx := slices.IndexFunc(elements, func(t templates.Template) bool {
+			return t.Template == d.template
+		})
+		if idx < 0 {
+			continue
+		}
+
+		item := elements[idx]
+		item.Params = slices.Clone(item.Params)
+		item.SetTitle(fmt.Sprintf("%s (%s)", item.Title(), d.ip))
+
+		if c.discoveredValues == nil {
+			c.discoveredValues = make(map[string]map[string]string)
+		}
+		c.discoveredValues[item.Title()] = d.values
+
+		res = append(res, item)
+	}
+
+	return res
+}
+
+// applyDiscoveredValues uses the discovered values as param defaults
+func (c *CmdConfigure) applyDiscoveredValues(templateItem *templates.Template) {
+	values, ok := c.discoveredValues[templateItem.Title()]
+	if !ok {
+		return
+	}
+
+	templateItem.Params = slices.Clone(templateItem.Params)
+	for i, p := range templateItem.Params {
+		if v, ok := values[p.Name]; ok {
+			templateItem.Params[i].Default = v
+		}
+	}
+}
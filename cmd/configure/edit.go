@@ -0,0 +1,426 @@
+package configure
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	configfile "github.com/evcc-io/evcc/util/config"
+	"github.com/evcc-io/evcc/util/templates"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// deviceSections are the configuration file sections of the device classes
+var deviceSections = map[templates.Class]string{
+	templates.Meter:   "meters",
+	templates.Charger: "chargers",
+	templates.Vehicle: "vehicles",
+}
+
+// configFile is an existing configuration file edited as yaml node tree to preserve untouched sections and comments
+type configFile struct {
+	file *configfile.File
+}
+
+// loadConfigFile parses an existing configuration file
+func loadConfigFile(filename string) (*configFile, error) {
+	file, err := configfile.Load(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &configFile{file: file}, nil
+}
+
+// newConfigFile creates an empty configuration file
+func newConfigFile() *configFile {
+	return &configFile{file: configfile.New()}
+}
+
+func (f *configFile) root() *yaml.Node {
+	return f.file.Root()
+}
+
+// section returns the device sequence of the class, created if missing
+func (f *configFile) section(class templates.Class) *yaml.Node {
+	key := deviceSections[class]
+
+	n := configfile.MapValue(f.root(), key)
+	if n == nil || n.Kind != yaml.SequenceNode {
+		n = &yaml.Node{Kind: yaml.SequenceNode}
+		configfile.SetMapValue(f.root(), key, n)
+	}
+
+	return n
+}
+
+// siteMeters returns the site's meter references, created if missing
+func (f *configFile) siteMeters() *yaml.Node {
+	site := configfile.MapValue(f.root(), "site")
+	if site == nil {
+		site = &yaml.Node{Kind: yaml.MappingNode}
+		configfile.SetMapValue(f.root(), "site", site)
+	}
+
+	meters := configfile.MapValue(site, "meters")
+	if meters == nil {
+		meters = &yaml.Node{Kind: yaml.MappingNode}
+		configfile.SetMapValue(site, "meters", meters)
+	}
+
+	return meters
+}
+
+// names returns the names of the configured devices of the class
+func (f *configFile) names(class templates.Class) []string {
+	var res []string
+
+	if sec := configfile.MapValue(f.root(), deviceSections[class]); sec != nil {
+		for _, d := range sec.Content {
+			var name string
+			if n := configfile.MapValue(d, "name"); n != nil {
+				name = n.Value
+			}
+			res = append(res, name)
+		}
+	}
+
+	return res
+}
+
+// description returns the device's type or template
+func (f *configFile) description(class templates.Class, name string) string {
+	d := configfile.MapValue(f.root(), deviceSections[class]).Content[slices.Index(f.names(class), name)]
+
+	if tmpl := configfile.MapValue(d, "template"); tmpl != nil {
+		return tmpl.Value
+	}
+	if typ := configfile.MapValue(d, "type"); typ != nil {
+		return typ.Value
+	}
+
+	return ""
+}
+
+// category derives the device category from the site's meter references
+func (f *configFile) category(class templates.Class, name string) DeviceCategory {
+	switch class {
+	case templates.Charger:
+		return DeviceCategoryCharger
+	case templates.Vehicle:
+		return DeviceCategoryVehicle
+	}
+
+	meters := configfile.MapValue(configfile.MapValue(f.root(), "site"), "meters")
+
+	switch {
+	case slices.Contains(configfile.Scalars(configfile.MapValue(meters, "grid")), name):
+		return DeviceCategoryGridMeter
+	case slices.Contains(append(configfile.Scalars(configfile.MapValue(meters, "pv")), configfile.Scalars(configfile.MapValue(meters, "pvs"))...), name):
+		return DeviceCategoryPVMeter
+	case slices.Contains(append(configfile.Scalars(configfile.MapValue(meters, "battery")), configfile.Scalars(configfile.MapValue(meters, "batteries"))...), name):
+		return DeviceCategoryBatteryMeter
+	default:
+		return DeviceCategoryChargeMeter
+	}
+}
+
+// loadpointReferences returns the titles of loadpoints referencing the device
+func (f *configFile) loadpointReferences(name string) []string {
+	var res []string
+
+	if lps := configfile.MapValue(f.root(), "loadpoints"); lps != nil {
+		for i, lp := range lps.Content {
+			for _, key := range []string{"charger", "meter", "vehicle", "vehicles"} {
+				if slices.Contains(configfile.Scalars(configfile.MapValue(lp, key)), name) {
+					title := fmt.Sprintf("#%d", i+1)
+					if t := configfile.MapValue(lp, "title"); t != nil {
+						title = t.Value
+					}
+					res = append(res, title)
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// deviceNode parses the rendered device configuration
+func deviceNode(deviceYaml string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(deviceYaml), &doc); err != nil {
+		return nil, err
+	}
+
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("invalid device configuration")
+	}
+
+	return doc.Content[0], nil
+}
+
+// add adds the device and references meters from the site
+func (f *configFile) add(category DeviceCategory, d device) (string, error) {
+	n, err := deviceNode(d.Yaml)
+	if err != nil {
+		return "", err
+	}
+
+	class := DeviceCategories[category].class
+
+	// ensure unique name
+	name := d.Name
+	for i := 2; slices.Contains(f.names(class), name); i++ {
+		name = fmt.Sprintf("%s_%d", d.Name, i)
+	}
+	configfile.SetMapValue(n, "name", configfile.Scalar(name))
+
+	sec := f.section(class)
+	sec.Content = append(sec.Content, n)
+
+	switch category {
+	case DeviceCategoryGridMeter:
+		configfile.SetMapValue(f.siteMeters(), "grid", configfile.Scalar(name))
+	case DeviceCategoryPVMeter:
+		f.addReference("pv", "pvs", name)
+	case DeviceCategoryBatteryMeter:
+		f.addReference("battery", "batteries", name)
+	}
+
+	return name, nil
+}
+
+// addReference adds the meter to the site using the single key unless already in use
+func (f *configFile) addReference(single, multiple, name string) {
+	meters := f.siteMeters()
+
+	refs := append(configfile.Scalars(configfile.MapValue(meters, single)), configfile.Scalars(configfile.MapValue(meters, multiple))...)
+	if len(refs) == 0 {
+		configfile.SetMapValue(meters, single, configfile.Scalar(name))
+		return
+	}
+
+	seq := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, ref := range append(refs, name) {
+		seq.Content = append(seq.Content, configfile.Scalar(ref))
+	}
+
+	configfile.DeleteMapValue(meters, single)
+	configfile.SetMapValue(meters, multiple, seq)
+}
+
+// replace replaces the device configuration keeping its name
+func (f *configFile) replace(class templates.Class, name string, d device) error {
+	n, err := deviceNode(d.Yaml)
+	if err != nil {
+		return err
+	}
+
+	configfile.SetMapValue(n, "name", configfile.Scalar(name))
+
+	sec := f.section(class)
+	idx := slices.Index(f.names(class), name)
+
+	// keep comments of the replaced device
+	n.HeadComment, n.LineComment, n.FootComment = sec.Content[idx].HeadComment, sec.Content[idx].LineComment, sec.Content[idx].FootComment
+	sec.Content[idx] = n
+
+	return nil
+}
+
+// remove removes the device and its site references
+func (f *configFile) remove(class templates.Class, name string) {
+	sec := f.section(class)
+	idx := slices.Index(f.names(class), name)
+	sec.Content = append(sec.Content[:idx], sec.Content[idx+1:]...)
+
+	if class != templates.Meter {
+		return
+	}
+
+	meters := configfile.MapValue(configfile.MapValue(f.root(), "site"), "meters")
+	if meters == nil {
+		return
+	}
+
+	for i := 0; i < len(meters.Content)-1; i += 2 {
+		val := meters.Content[i+1]
+
+		switch val.Kind {
+		case yaml.ScalarNode:
+			if val.Value == name {
+				meters.Content = append(meters.Content[:i], meters.Content[i+2:]...)
+				i -= 2
+			}
+		case yaml.SequenceNode:
+			var refs []*yaml.Node
+			for _, n := range val.Content {
+				if n.Value != name {
+					refs = append(refs, n)
+				}
+			}
+			val.Content = refs
+		}
+	}
+}
+
+// bytes renders the configuration file
+func (f *configFile) bytes() ([]byte, error) {
+	return f.file.Bytes()
+}
+
+// save writes the configuration file keeping a backup of the previous version
+func (f *configFile) save(filename string) error {
+	return f.file.Save(filename)
+}
+
+// flowEditConfigFile implements the flow for adding, reconfiguring or removing a device of an existing configuration file
+func (c *CmdConfigure) flowEditConfigFile() {
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Flow_EditConfiguration_Setup", nil))
+	fmt.Fprintln(c.console)
+
+	var filename string
+	var file *configFile
+
+	for {
+		filename = c.askValue(question{
+			label:        c.localizedString("Edit_Filename", nil),
+			defaultValue: DefaultConfigFilename,
+			required:     true,
+		})
+
+		var err error
+		if file, err = loadConfigFile(filename); err == nil {
+			break
+		}
+
+		fmt.Fprintln(c.console, c.localizedString("Edit_Error_LoadFailed", localizeMap{"FileName": filename, "Error": err}))
+	}
+
+	c.configFile = filename
+
+	for {
+		fmt.Fprintln(c.console)
+		fmt.Fprintln(c.console, c.localizedString("Edit_Devices", nil))
+		for _, d := range file.devices() {
+			fmt.Fprintln(c.console, "  "+d.title)
+		}
+
+		fmt.Fprintln(c.console)
+		action, _ := c.askChoice(c.localizedString("Edit_Action", nil), []string{
+			c.localizedString("Edit_Action_Add", nil),
+			c.localizedString("Edit_Action_Reconfigure", nil),
+			c.localizedString("Edit_Action_Remove", nil),
+			c.localizedString("Edit_Action_Save", nil),
+			c.localizedString("Edit_Action_Cancel", nil),
+		})
+
+		switch action {
+		case 0:
+			category := c.askDeviceCategory()
+			for _, d := range c.configureDevices(category, false, false) {
+				name, err := file.add(category, d)
+				if err != nil {
+					c.log.FATAL.Fatal(err)
+				}
+				fmt.Fprintln(c.console, c.localizedString("Edit_Added", localizeMap{"Name": name}))
+			}
+
+		case 1:
+			d, ok := c.askEditDevice(file)
+			if !ok {
+				continue
+			}
+
+			for _, item := range c.configureDevices(file.category(d.class, d.name), false, false) {
+				if err := file.replace(d.class, d.name, item); err != nil {
+					c.log.FATAL.Fatal(err)
+				}
+				fmt.Fprintln(c.console, c.localizedString("Edit_Reconfigured", localizeMap{"Name": d.name}))
+			}
+
+		case 2:
+			d, ok := c.askEditDevice(file)
+			if !ok {
+				continue
+			}
+
+			if refs := file.loadpointReferences(d.name); len(refs) > 0 {
+				fmt.Fprintln(c.console, c.localizedString("Edit_Referenced", localizeMap{"Name": d.name, "Loadpoints": strings.Join(refs, ", ")}))
+				continue
+			}
+
+			if c.askYesNo(c.localizedString("Edit_Remove", localizeMap{"Name": d.name})) {
+				file.remove(d.class, d.name)
+			}
+
+		case 3:
+			fmt.Fprintln(c.console)
+
+			if c.dryRun {
+				b, err := file.bytes()
+				if err != nil {
+					c.log.FATAL.Fatal(err)
+				}
+				c.printConfiguration(b)
+				return
+			}
+
+			if err := file.save(filename); err != nil {
+				fmt.Fprintf(c.console, "%s: ", c.localizedString("File_Error_SaveFailed", localizeMap{"FileName": filename}))
+				c.log.FATAL.Fatal(err)
+			}
+			fmt.Fprintln(c.console, c.localizedString("File_SaveSuccess", localizeMap{"FileName": filename}))
+			return
+
+		default:
+			return
+		}
+	}
+}
+
+// editDevice is a device of the configuration file
+type editDevice struct {
+	class       templates.Class
+	name, title string
+}
+
+// devices returns the configured devices of all classes
+func (f *configFile) devices() []editDevice {
+	var res []editDevice
+
+	for _, class := range []templates.Class{templates.Meter, templates.Charger, templates.Vehicle} {
+		for _, name := range f.names(class) {
+			title := fmt.Sprintf("%s: %s", DeviceCategories[f.category(class, name)].title, name)
+			if desc := f.description(class, name); desc != "" {
+				title += fmt.Sprintf(" (%s)", desc)
+			}
+
+			res = append(res, editDevice{class: class, name: name, title: title})
+		}
+	}
+
+	return res
+}
+
+// askEditDevice lets the user choose a configured device
+func (c *CmdConfigure) askEditDevice(file *configFile) (editDevice, bool) {
+	devices := file.devices()
+	if len(devices) == 0 {
+		fmt.Fprintln(c.console, c.localizedString("Edit_NoDevices", nil))
+		return editDevice{}, false
+	}
+
+	var choices []string
+	for _, d := range devices {
+		choices = append(choices, d.title)
+	}
+
+	fmt.Fprintln(c.console)
+	idx, _ := c.askChoice(c.localizedString("Edit_Select", nil), choices)
+
+	return devices[idx], true
+}
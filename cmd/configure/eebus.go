@@ -2,13 +2,86 @@ package configure
 
 import (
 	"crypto/x509/pkix"
+	"errors"
 	"fmt"
+	"time"
 
 	certhelper "github.com/evcc-io/eebus/cert"
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/server"
+	"gopkg.in/yaml.v3"
 )
 
+// eebusPairingTimeout is the time to wait for the wallbox to pair before asking to continue waiting
+const eebusPairingTimeout = 2 * time.Minute
+
+// setupEEBus creates the EEBUS certificate if not yet configured, starts the SHIP node and displays the SKI for pairing
+func (c *CmdConfigure) setupEEBus() error {
+	if server.EEBusInstance != nil {
+		return nil
+	}
+
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, "-- EEBUS -----------------------------------")
+	fmt.Fprintln(c.console)
+
+	var eebusConfig map[string]interface{}
+	if c.configuration.config.EEBUS != "" {
+		// resumed session
+		if err := yaml.Unmarshal([]byte(c.configuration.config.EEBUS), &eebusConfig); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if eebusConfig, err = c.eebusCertificate(); err != nil {
+			return fmt.Errorf("%s: %s", c.localizedString("Requirements_EEBUS_Cert_Error", nil), err)
+		}
+	}
+
+	if err := c.configureEEBus(eebusConfig); err != nil {
+		return err
+	}
+
+	eebusYaml, err := yaml.Marshal(eebusConfig)
+	if err != nil {
+		return err
+	}
+
+	c.configuration.config.EEBUS = string(eebusYaml)
+
+	fmt.Fprintln(c.console, c.localizedString("Requirements_EEBUS_Pairing", localizeMap{"SKI": server.EEBusInstance.SKI}))
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, "--------------------------------------------")
+
+	return nil
+}
+
+// waitEEBusPairing waits until the wallbox with the given SKI connected to the SHIP node
+func (c *CmdConfigure) waitEEBusPairing(ski string) error {
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("EEBUS_Pairing_Waiting", localizeMap{"SKI": ski, "EVCCSKI": server.EEBusInstance.SKI}))
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	timeout := time.After(eebusPairingTimeout)
+
+	for !server.EEBusInstance.Connected(ski) {
+		select {
+		case <-ticker.C:
+		case <-timeout:
+			if !c.askYesNo(c.localizedString("EEBUS_Pairing_Timeout", nil)) {
+				return errors.New(c.localizedString("EEBUS_Pairing_Failed", nil))
+			}
+			timeout = time.After(eebusPairingTimeout)
+		}
+	}
+
+	fmt.Fprintln(c.console, c.localizedString("EEBUS_Pairing_Successful", nil))
+
+	return nil
+}
+
 // configureEEBus setup EEBus
 func (c *CmdConfigure) configureEEBus(conf map[string]interface{}) error {
 	var err error
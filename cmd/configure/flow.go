@@ -23,7 +23,7 @@ func (c *CmdConfigure) configureDeviceGuidedSetup() {
 	deviceItem := device{}
 
 	for {
-		fmt.Println()
+		fmt.Fprintln(c.console)
 
 		templateItem, err = c.processDeviceSelection(DeviceCategoryGuidedSetup)
 		if err != nil {
@@ -59,10 +59,10 @@ func (c *CmdConfigure) configureDeviceGuidedSetup() {
 		deviceItem, err = c.processDeviceValues(values, templateItem, deviceItem, deviceCategory)
 		if err != nil {
 			if err != c.errDeviceNotValid {
-				fmt.Println()
-				fmt.Println(err)
+				fmt.Fprintln(c.console)
+				fmt.Fprintln(c.console, err)
 			}
-			fmt.Println()
+			fmt.Fprintln(c.console)
 			if !c.askConfigFailureNextStep() {
 				return
 			}
@@ -73,6 +73,7 @@ func (c *CmdConfigure) configureDeviceGuidedSetup() {
 	}
 
 	c.configuration.AddDevice(deviceItem, deviceCategory)
+	c.checkpoint()
 	c.processDeviceCapabilities(templateItem.Capabilities)
 
 	if len(supportedDeviceCategories) > 1 {
@@ -84,11 +85,12 @@ func (c *CmdConfigure) configureDeviceGuidedSetup() {
 			}
 
 			c.configuration.AddDevice(deviceItem, additionalCategory)
+			c.checkpoint()
 		}
 	}
 
-	fmt.Println()
-	fmt.Println(templateItem.Title() + " " + c.localizedString("Device_Added", nil))
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, templateItem.Title()+" "+c.localizedString("Device_Added", nil))
 
 	c.configureLinkedTypes(templateItem)
 }
@@ -106,7 +108,7 @@ func (c *CmdConfigure) configureLinkedTypes(templateItem templates.Template) {
 
 		linkedTemplateItem, err := templates.ByName(templates.Meter, linkedTemplate.Template)
 		if err != nil {
-			fmt.Println("Error: " + err.Error())
+			fmt.Fprintln(c.console, "Error: "+err.Error())
 			return
 		}
 
@@ -124,7 +126,7 @@ func (c *CmdConfigure) configureLinkedTypes(templateItem templates.Template) {
 			"Category":   DeviceCategories[category].title,
 		}
 
-		fmt.Println()
+		fmt.Fprintln(c.console)
 		if !c.askYesNo(c.localizedString("AddLinkedDeviceInCategory", localizeMap)) {
 			continue
 		}
@@ -138,7 +140,7 @@ func (c *CmdConfigure) configureLinkedTypes(templateItem templates.Template) {
 				break
 			}
 
-			fmt.Println()
+			fmt.Fprintln(c.console)
 			if !c.askYesNo(c.localizedString("AddAnotherLinkedDeviceInCategory", localizeMap)) {
 				break
 			}
@@ -156,20 +158,21 @@ func (c *CmdConfigure) configureLinkedTemplate(templateItem templates.Template,
 		deviceItem, err := c.processDeviceValues(values, templateItem, deviceItem, category)
 		if err != nil {
 			if !errors.Is(err, c.errDeviceNotValid) {
-				fmt.Println()
-				fmt.Println(err)
+				fmt.Fprintln(c.console)
+				fmt.Fprintln(c.console, err)
 			}
-			fmt.Println()
+			fmt.Fprintln(c.console)
 			if c.askConfigFailureNextStep() {
 				continue
 			}
 
 		} else {
 			c.configuration.AddDevice(deviceItem, category)
+			c.checkpoint()
 			c.processDeviceCapabilities(templateItem.Capabilities)
 
-			fmt.Println()
-			fmt.Println(templateItem.Title() + " " + c.localizedString("Device_Added", nil))
+			fmt.Fprintln(c.console)
+			fmt.Fprintln(c.console, templateItem.Title()+" "+c.localizedString("Device_Added", nil))
 			return true
 		}
 		break
@@ -179,8 +182,8 @@ func (c *CmdConfigure) configureLinkedTemplate(templateItem templates.Template,
 
 // configureDeviceCategory lets the user select and configure a device from a specific category
 func (c *CmdConfigure) configureDeviceCategory(deviceCategory DeviceCategory) (device, []string, error) {
-	fmt.Println()
-	fmt.Printf("- %s %s\n", c.localizedString("Device_Configure", nil), DeviceCategories[deviceCategory].title)
+	fmt.Fprintln(c.console)
+	fmt.Fprintf(c.console, "- %s %s\n", c.localizedString("Device_Configure", nil), DeviceCategories[deviceCategory].title)
 
 	device := device{
 		Name: DeviceCategories[deviceCategory].defaultName,
@@ -191,7 +194,7 @@ func (c *CmdConfigure) configureDeviceCategory(deviceCategory DeviceCategory) (d
 
 	// repeat until the device is added or the user chooses to continue without adding a device
 	for {
-		fmt.Println()
+		fmt.Fprintln(c.console)
 
 		templateItem, err := c.processDeviceSelection(deviceCategory)
 		if err != nil {
@@ -204,11 +207,11 @@ func (c *CmdConfigure) configureDeviceCategory(deviceCategory DeviceCategory) (d
 		device, err = c.processDeviceValues(values, templateItem, device, deviceCategory)
 		if err != nil {
 			if err != c.errDeviceNotValid {
-				fmt.Println()
-				fmt.Println(err)
+				fmt.Fprintln(c.console)
+				fmt.Fprintln(c.console, err)
 			}
 			// ask if the user wants to add the
-			fmt.Println()
+			fmt.Fprintln(c.console)
 			if !c.askConfigFailureNextStep() {
 				return device, capabilities, err
 			}
@@ -219,6 +222,7 @@ func (c *CmdConfigure) configureDeviceCategory(deviceCategory DeviceCategory) (d
 	}
 
 	c.configuration.AddDevice(device, deviceCategory)
+	c.checkpoint()
 	c.processDeviceCapabilities(capabilities)
 
 	var deviceTitle string
@@ -226,8 +230,8 @@ func (c *CmdConfigure) configureDeviceCategory(deviceCategory DeviceCategory) (d
 		deviceTitle = " " + device.Title
 	}
 
-	fmt.Println()
-	fmt.Println(deviceDescription + deviceTitle + " " + c.localizedString("Device_Added", nil))
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, deviceDescription+deviceTitle+" "+c.localizedString("Device_Added", nil))
 
 	return device, capabilities, nil
 }
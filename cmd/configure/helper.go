@@ -6,7 +6,9 @@ import (
 	"strings"
 
 	"github.com/evcc-io/evcc/provider/mqtt"
+	"github.com/evcc-io/evcc/server"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/secrets"
 	"github.com/evcc-io/evcc/util/sponsor"
 	"github.com/evcc-io/evcc/util/templates"
 	stripmd "github.com/writeas/go-strip-markdown"
@@ -48,13 +50,13 @@ func (c *CmdConfigure) processDeviceValues(values map[string]interface{}, templa
 
 	var categoryWithUsage bool
 
-	fmt.Println()
+	fmt.Fprintln(c.console)
 	switch deviceCategory {
 	case DeviceCategoryPVMeter, DeviceCategoryBatteryMeter, DeviceCategoryGridMeter:
 		categoryWithUsage = true
-		fmt.Println(c.localizedString("TestingDevice_TitleUsage", localizeMap{"Device": templateItem.Title(), "Usage": deviceCategory.String()}))
+		fmt.Fprintln(c.console, c.localizedString("TestingDevice_TitleUsage", localizeMap{"Device": templateItem.Title(), "Usage": deviceCategory.String()}))
 	default:
-		fmt.Println(c.localizedString("TestingDevice_Title", localizeMap{"Device": templateItem.Title()}))
+		fmt.Fprintln(c.console, c.localizedString("TestingDevice_Title", localizeMap{"Device": templateItem.Title()}))
 	}
 
 	deviceTest := DeviceTest{
@@ -63,10 +65,23 @@ func (c *CmdConfigure) processDeviceValues(values map[string]interface{}, templa
 		ConfigValues:   values,
 	}
 
-	testResult, err := deviceTest.Test()
+	// wait for EEBUS wallboxes to pair before testing them
+	if ski, ok := values["ski"].(string); ok && server.EEBusInstance != nil && slices.Contains(templateItem.Requirements.EVCC, templates.RequirementEEBUS) {
+		deviceTest.Pairing = func() error { return c.waitEEBusPairing(ski) }
+	}
+
+	testResult, err := c.testDevice(deviceTest)
+	if err != nil {
+		if res, ok := c.probeBaudrate(deviceTest); ok {
+			testResult, err = res, nil
+		}
+	}
+
+	var inverted bool
+
 	if err != nil {
-		fmt.Println("  ", c.localizedString("Error", localizeMap{"Error": err}))
-		fmt.Println()
+		fmt.Fprintln(c.console, "  ", c.localizedString("Error", localizeMap{"Error": err}))
+		fmt.Fprintln(c.console)
 
 		question := c.localizedString("TestingDevice_AddFailed", localizeMap{"Device": templateItem.Title()})
 		if categoryWithUsage {
@@ -80,11 +95,24 @@ func (c *CmdConfigure) processDeviceValues(values map[string]interface{}, templa
 		if deviceCategory == DeviceCategoryCharger && testResult == DeviceTestResultValid {
 			device.ChargerHasMeter = true
 		}
+
+		if !c.previewDevice(deviceTest) {
+			c.addedDeviceIndex--
+			return device, c.errDeviceNotValid
+		}
+
+		// inverted grid meters are corrected by the generic meter option
+		if deviceCategory == DeviceCategoryGridMeter && c.detectGridDirection(deviceTest) {
+			inverted = true
+		}
 	}
 
 	templateItem.Params = append(templateItem.Params, templates.Param{Name: "name", Value: device.Name})
+	if inverted {
+		templateItem.Params = append(templateItem.Params, templates.Param{Name: "invert", Value: "true"})
+	}
 	if !c.expandedMode {
-		b, err := templateItem.RenderProxyWithValues(values, c.lang)
+		b, err := templateItem.RenderProxyWithValues(values, c.templateLang())
 		if err != nil {
 			c.addedDeviceIndex--
 			return device, err
@@ -97,6 +125,10 @@ func (c *CmdConfigure) processDeviceValues(values map[string]interface{}, templa
 				values["name"] = p.Value
 				templateItem.Render = fmt.Sprintf("name: {{ .name }}\n%s", templateItem.Render)
 			}
+			if p.Name == "invert" {
+				values["invert"] = p.Value
+				templateItem.Render = fmt.Sprintf("invert: {{ .invert }}\n%s", templateItem.Render)
+			}
 		}
 
 		b, _, err := templateItem.RenderResult(templates.TemplateRenderModeInstance, values)
@@ -108,6 +140,15 @@ func (c *CmdConfigure) processDeviceValues(values map[string]interface{}, templa
 		device.Yaml = string(b)
 	}
 
+	// replace stored secrets by references
+	b, err := secrets.Reference([]byte(device.Yaml), c.deviceSecrets)
+	if err != nil {
+		c.addedDeviceIndex--
+		return device, err
+	}
+
+	device.Yaml = string(b)
+
 	return device, nil
 }
 
@@ -119,16 +160,16 @@ func (c *CmdConfigure) processDeviceCapabilities(capabilitites []string) {
 
 // processDeviceRequirements handles device requirements
 func (c *CmdConfigure) processDeviceRequirements(templateItem templates.Template) error {
-	requirementDescription := stripmd.Strip(templateItem.Requirements.Description.String(c.lang))
+	requirementDescription := stripmd.Strip(templateItem.Requirements.Description.String(c.templateLang()))
 	if len(requirementDescription) > 0 {
-		fmt.Println()
-		fmt.Println("-------------------------------------------------")
-		fmt.Println(c.localizedString("Requirements_Title", nil))
-		fmt.Println(requirementDescription)
+		fmt.Fprintln(c.console)
+		fmt.Fprintln(c.console, "-------------------------------------------------")
+		fmt.Fprintln(c.console, c.localizedString("Requirements_Title", nil))
+		fmt.Fprintln(c.console, requirementDescription)
 		if len(templateItem.Requirements.URI) > 0 {
-			fmt.Println("  " + c.localizedString("Requirements_More", nil) + " " + templateItem.Requirements.URI)
+			fmt.Fprintln(c.console, "  "+c.localizedString("Requirements_More", nil)+" "+templateItem.Requirements.URI)
 		}
-		fmt.Println("-------------------------------------------------")
+		fmt.Fprintln(c.console, "-------------------------------------------------")
 	}
 
 	// check if sponsorship is required
@@ -141,48 +182,20 @@ func (c *CmdConfigure) processDeviceRequirements(templateItem templates.Template
 	// check if we need to setup an MQTT broker
 	if slices.Contains(templateItem.Requirements.EVCC, templates.RequirementMQTT) {
 		if c.configuration.config.MQTT == "" {
-			mqttConfig, err := c.configureMQTT(templateItem)
-			if err != nil {
-				return err
-			}
+			fmt.Fprintln(c.console)
+			fmt.Fprintln(c.console, c.localizedString("Requirements_MQTT", nil))
 
-			mqttYaml, err := yaml.Marshal(mqttConfig)
-			if err != nil {
+			if err := c.configureMQTTBroker(); err != nil {
 				return err
 			}
-
-			c.configuration.config.MQTT = string(mqttYaml)
 		}
 	}
 
 	// check if we need to setup an EEBUS HEMS
 	if slices.Contains(templateItem.Requirements.EVCC, templates.RequirementEEBUS) {
-		if c.configuration.config.EEBUS == "" {
-			fmt.Println()
-			fmt.Println("-- EEBUS -----------------------------------")
-			fmt.Println()
-			eebusConfig, err := c.eebusCertificate()
-			if err != nil {
-				return fmt.Errorf("%s: %s", c.localizedString("Requirements_EEBUS_Cert_Error", nil), err)
-			}
-
-			if err := c.configureEEBus(eebusConfig); err != nil {
-				return err
-			}
-
-			eebusYaml, err := yaml.Marshal(eebusConfig)
-			if err != nil {
-				return err
-			}
-
-			c.configuration.config.EEBUS = string(eebusYaml)
-			fmt.Println()
-			fmt.Println("--------------------------------------------")
+		if err := c.setupEEBus(); err != nil {
+			return err
 		}
-
-		fmt.Println()
-		fmt.Println(c.localizedString("Requirements_EEBUS_Pairing", nil))
-		fmt.Scanln()
 	}
 
 	return nil
@@ -190,16 +203,16 @@ func (c *CmdConfigure) processDeviceRequirements(templateItem templates.Template
 
 // processParamRequirements handles param requirements
 func (c *CmdConfigure) processParamRequirements(param templates.Param) error {
-	requirementDescription := stripmd.Strip(param.Requirements.Description.String(c.lang))
+	requirementDescription := stripmd.Strip(param.Requirements.Description.String(c.templateLang()))
 	if len(requirementDescription) > 0 {
-		fmt.Println()
-		fmt.Println("-------------------------------------------------")
-		fmt.Println(c.localizedString("Requirements_Title", nil))
-		fmt.Println(requirementDescription)
+		fmt.Fprintln(c.console)
+		fmt.Fprintln(c.console, "-------------------------------------------------")
+		fmt.Fprintln(c.console, c.localizedString("Requirements_Title", nil))
+		fmt.Fprintln(c.console, requirementDescription)
 		if len(param.Requirements.URI) > 0 {
-			fmt.Println("  " + c.localizedString("Requirements_More", nil) + " " + param.Requirements.URI)
+			fmt.Fprintln(c.console, "  "+c.localizedString("Requirements_More", nil)+" "+param.Requirements.URI)
 		}
-		fmt.Println("-------------------------------------------------")
+		fmt.Fprintln(c.console, "-------------------------------------------------")
 	}
 
 	// check if sponsorship is required
@@ -213,22 +226,22 @@ func (c *CmdConfigure) processParamRequirements(param templates.Param) error {
 }
 
 func (c *CmdConfigure) askSponsortoken(required, feature bool) error {
-	fmt.Println("-- Sponsorship -----------------------------")
+	fmt.Fprintln(c.console, "-- Sponsorship -----------------------------")
 	if required {
-		fmt.Println()
+		fmt.Fprintln(c.console)
 		if feature {
-			fmt.Println(c.localizedString("Requirements_Sponsorship_Feature_Title", nil))
+			fmt.Fprintln(c.console, c.localizedString("Requirements_Sponsorship_Feature_Title", nil))
 		} else {
-			fmt.Println(c.localizedString("Requirements_Sponsorship_Title", nil))
+			fmt.Fprintln(c.console, c.localizedString("Requirements_Sponsorship_Title", nil))
 		}
 	} else {
-		fmt.Println()
-		fmt.Println(c.localizedString("Requirements_Sponsorship_Optional_Title", nil))
+		fmt.Fprintln(c.console)
+		fmt.Fprintln(c.console, c.localizedString("Requirements_Sponsorship_Optional_Title", nil))
 	}
-	fmt.Println()
+	fmt.Fprintln(c.console)
 	if !c.askYesNo(c.localizedString("Requirements_Sponsorship_Token", nil)) {
-		fmt.Println()
-		fmt.Println("--------------------------------------------")
+		fmt.Fprintln(c.console)
+		fmt.Fprintln(c.console, "--------------------------------------------")
 		return c.errItemNotPresent
 	}
 
@@ -249,66 +262,99 @@ func (c *CmdConfigure) askSponsortoken(required, feature bool) error {
 		c.configuration.config.SponsorToken = sponsortoken
 	}
 
-	fmt.Println()
-	fmt.Println("--------------------------------------------")
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, "--------------------------------------------")
 
 	return err
 }
 
-func (c *CmdConfigure) configureMQTT(templateItem templates.Template) (map[string]interface{}, error) {
-	fmt.Println()
-	fmt.Println("-- MQTT Broker ----------------------------")
-
-	var err error
+// configureMQTT asks for the mqtt broker configuration and tests the connection
+func (c *CmdConfigure) configureMQTT() (map[string]interface{}, error) {
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, "-- MQTT Broker ----------------------------")
 
 	for {
-		fmt.Println()
-		_, paramHost := templateItem.ConfigDefaults.ParamByName("host")
-		_, paramPort := templateItem.ConfigDefaults.ParamByName("port")
-		_, paramUser := templateItem.ConfigDefaults.ParamByName("user")
-		_, paramPassword := templateItem.ConfigDefaults.ParamByName("password")
+		fmt.Fprintln(c.console)
 		host := c.askValue(question{
-			label:    paramHost.Description.String(c.lang),
-			mask:     false,
+			label:    c.localizedString("MQTT_Host", nil),
 			required: true,
 		})
 
 		port := c.askValue(question{
-			label:    paramPort.Description.String(c.lang),
-			mask:     false,
-			required: true,
+			label:          c.localizedString("MQTT_Port", nil),
+			defaultValue:   "1883",
+			valueType:      templates.ParamValueTypeNumber,
+			minNumberValue: 1,
+			maxNumberValue: 65535,
+			required:       true,
 		})
 
+		broker := fmt.Sprintf("%s:%s", host, port)
+
+		var insecure bool
+		if c.askYesNo(c.localizedString("MQTT_TLS", nil)) {
+			broker = "tls://" + broker
+			insecure = c.advancedMode && c.askYesNo(c.localizedString("MQTT_Insecure", nil))
+		}
+
 		user := c.askValue(question{
-			label:    paramUser.Description.String(c.lang),
-			mask:     false,
-			required: false,
+			label: c.localizedString("MQTT_User", nil),
 		})
 
 		password := c.askValue(question{
-			label:    paramPassword.Description.String(c.lang),
-			mask:     true,
-			required: false,
+			label: c.localizedString("MQTT_Password", nil),
+			mask:  true,
 		})
 
-		fmt.Println()
-		fmt.Println("--------------------------------------------")
+		var clientID string
+		topic := "evcc"
+		if c.advancedMode {
+			clientID = c.askValue(question{
+				label: c.localizedString("MQTT_ClientID", nil),
+				help:  c.localizedString("MQTT_ClientID_Help", nil),
+			})
+
+			topic = c.askValue(question{
+				label:        c.localizedString("MQTT_Topic", nil),
+				defaultValue: topic,
+				required:     true,
+			})
+		}
 
-		broker := fmt.Sprintf("%s:%s", host, port)
+		fmt.Fprintln(c.console)
+		fmt.Fprintln(c.console, "--------------------------------------------")
 
 		mqttConfig := map[string]interface{}{
-			"broker":   broker,
-			"user":     user,
-			"password": password,
+			"broker": broker,
+			"topic":  topic,
+		}
+
+		for k, v := range map[string]string{"user": user, "password": password, "clientid": clientID} {
+			if v != "" {
+				mqttConfig[k] = v
+			}
 		}
 
+		if insecure {
+			mqttConfig["insecure"] = true
+		}
+
+		fmt.Fprintln(c.console)
+		fmt.Fprintln(c.console, c.localizedString("TestingMQTT", localizeMap{"Broker": broker}))
+
+		// same client as used by evcc, set as default instance for the devices' tests
 		log := util.NewLogger("mqtt")
 
-		if mqtt.Instance, err = mqtt.RegisteredClient(log, broker, user, password, "", 1, false); err == nil {
+		var err error
+		if mqtt.Instance, err = mqtt.RegisteredClient(log, broker, user, password, clientID, 1, insecure); err == nil {
+			fmt.Fprintln(c.console, c.localizedString("TestingMQTTSuccessful", nil))
 			return mqttConfig, nil
 		}
 
-		fmt.Println()
+		fmt.Fprintln(c.console)
+		fmt.Fprintln(c.console, c.localizedString("Error", localizeMap{"Error": err}))
+
+		fmt.Fprintln(c.console)
 		question := c.localizedString("TestingMQTTFailed", nil)
 		if !c.askYesNo(question) {
 			return nil, fmt.Errorf("failed configuring mqtt: %w", err)
@@ -316,6 +362,23 @@ func (c *CmdConfigure) configureMQTT(templateItem templates.Template) (map[strin
 	}
 }
 
+// configureMQTTBroker adds the mqtt broker configuration
+func (c *CmdConfigure) configureMQTTBroker() error {
+	mqttConfig, err := c.configureMQTT()
+	if err != nil {
+		return err
+	}
+
+	mqttYaml, err := yaml.Marshal(mqttConfig)
+	if err != nil {
+		return err
+	}
+
+	c.configuration.config.MQTT = string(mqttYaml)
+
+	return nil
+}
+
 // fetchElements returns template items of a given class
 func (c *CmdConfigure) fetchElements(deviceCategory DeviceCategory) []templates.Template {
 	var items []templates.Template
@@ -324,11 +387,11 @@ func (c *CmdConfigure) fetchElements(deviceCategory DeviceCategory) []templates.
 			continue
 		}
 
-		for _, t := range tmpl.Titles(c.lang) {
+		for _, t := range tmpl.Titles(c.templateLang()) {
 			titleTmpl := templates.Template{
 				TemplateDefinition: tmpl.TemplateDefinition,
 				ConfigDefaults:     tmpl.ConfigDefaults,
-				Lang:               c.lang,
+				Lang:               c.templateLang(),
 			}
 			title := t
 			groupTitle := titleTmpl.GroupTitle()
@@ -392,11 +455,14 @@ func (c *CmdConfigure) paramChoiceValues(params []templates.Param, name string)
 // processConfig processes an EVCC configuration item
 // Returns a map with param name and values
 func (c *CmdConfigure) processConfig(templateItem *templates.Template, deviceCategory DeviceCategory) map[string]interface{} {
-	fmt.Println()
-	fmt.Println(c.localizedString("Config_Title", nil))
-	fmt.Println()
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Config_Title", nil))
+	fmt.Fprintln(c.console)
+
+	c.deviceSecrets = make(map[string]string)
 
 	c.processModbusConfig(templateItem, deviceCategory)
+	c.applyDiscoveredValues(templateItem)
 
 	// TODO remove
 	// type mapped = struct {
@@ -404,14 +470,17 @@ func (c *CmdConfigure) processConfig(templateItem *templates.Template, deviceCat
 	// 	Default any
 	// }
 
-	// fmt.Printf("%+v\n", lo.Map(templateItem.Params, func(p templates.Param, _ int) mapped {
+	// fmt.Fprintf(c.console, "%+v\n", lo.Map(templateItem.Params, func(p templates.Param, _ int) mapped {
 	// 	return mapped{
 	// 		Name:    p.Name,
 	// 		Default: p.Default,
 	// 	}
 	// }))
 
-	return c.processParams(templateItem, deviceCategory)
+	values := c.processParams(templateItem, deviceCategory)
+	c.verifyLogin(templateItem, values)
+
+	return values
 }
 
 // process a list of params
@@ -435,26 +504,42 @@ func (c *CmdConfigure) processParams(templateItem *templates.Template, deviceCat
 				continue
 			}
 
+			if !param.DependenciesMet(paramValues(templateItem, additionalConfig)) {
+				continue
+			}
+
 			if param.Hidden && param.Default != "" {
 				additionalConfig[param.Name] = param.Default
 				continue
 			}
 
+			// tokens created by the account login
+			if param.Name == paramAccessToken && c.processLogin(templateItem, additionalConfig) {
+				continue
+			}
+			if param.Name == paramRefreshToken && additionalConfig[param.Name] != nil {
+				continue
+			}
+
+			if param.Name == templates.ModbusParamNameDevice && additionalConfig[templates.ParamModbus] == templates.ModbusKeyRS485Serial {
+				if value := c.processSerialPort(param); value != "" {
+					additionalConfig[param.Name] = value
+					continue
+				}
+			}
+
 			switch param.ValueType {
 			case templates.ParamValueTypeStringList:
-				values := c.processListInputConfig(param)
-				var nonEmptyValues []string
-				for _, value := range values {
-					if value != "" {
-						nonEmptyValues = append(nonEmptyValues, value)
-					}
-				}
-				additionalConfig[param.Name] = nonEmptyValues
+				additionalConfig[param.Name] = c.processListInputConfig(param)
 
 			default:
 				// TODO make processInputConfig aware of default values added by template
 				if value := c.processInputConfig(param); value != "" {
 					additionalConfig[param.Name] = value
+
+					if param.Mask {
+						c.processSecret(templateItem.Template, param.Name, value)
+					}
 				}
 			}
 		}
@@ -463,50 +548,59 @@ func (c *CmdConfigure) processParams(templateItem *templates.Template, deviceCat
 	return additionalConfig
 }
 
-// handle user input of multiple items in a list
-func (c *CmdConfigure) processListInputConfig(param templates.Param) []string {
-	var values []string
-
-	// ask for values until the user decides to stop
-	for {
-		newValue := c.processInputConfig(param)
-		values = append(values, newValue)
-
-		if newValue == "" {
-			break
+// paramValues returns the param values provided so far, falling back to the params' defaults
+func paramValues(templateItem *templates.Template, values map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{})
+	for _, param := range templateItem.Params {
+		if param.Default != "" {
+			res[param.Name] = param.Default
 		}
+	}
 
-		if !c.askYesNo("  " + c.localizedString("Config_AddAnotherValue", nil)) {
-			break
-		}
+	for k, v := range values {
+		res[k] = v
 	}
 
-	return values
+	return res
 }
 
-// handle user input for a simple one value input
-func (c *CmdConfigure) processInputConfig(param templates.Param) string {
+// handle user input of multiple items in a list
+func (c *CmdConfigure) processListInputConfig(param templates.Param) []string {
+	return c.askValues(c.paramQuestion(param))
+}
+
+// paramQuestion creates the question for a template param
+func (c *CmdConfigure) paramQuestion(param templates.Param) question {
 	label := param.Name
-	langLabel := param.Description.String(c.lang)
+	langLabel := param.Description.String(c.templateLang())
 	if langLabel != "" {
 		label = langLabel
 	}
 
-	help := param.Help.ShortString(c.lang)
+	help := param.Help.ShortString(c.templateLang())
 	if slices.Contains(param.Requirements.EVCC, templates.RequirementSponsorship) {
 		help = fmt.Sprintf("%s\n\n%s", help, c.localizedString("Requirements_Sponsorship_Feature_Title", nil))
 	}
 
-	value := c.askValue(question{
-		label:        label,
-		defaultValue: param.Default,
-		exampleValue: param.Example,
-		help:         help,
-		valueType:    param.ValueType,
-		validValues:  param.ValidValues,
-		mask:         param.Mask,
-		required:     param.Required,
-	})
+	return question{
+		label:          label,
+		defaultValue:   param.Default,
+		exampleValue:   param.Example,
+		help:           help,
+		valueType:      param.ValueType,
+		unit:           param.Unit,
+		minNumberValue: param.Min,
+		maxNumberValue: param.Max,
+		validValues:    param.ValidValues,
+		mask:           param.Mask,
+		confirm:        param.Mask,
+		required:       param.Required,
+	}
+}
+
+// handle user input for a simple one value input
+func (c *CmdConfigure) processInputConfig(param templates.Param) string {
+	value := c.askValue(c.paramQuestion(param))
 
 	if param.ValueType == templates.ParamValueTypeBool && value == "true" {
 		if err := c.processParamRequirements(param); err != nil {
@@ -537,7 +631,7 @@ func (c *CmdConfigure) processModbusConfig(templateItem *templates.Template, dev
 
 		for _, itype := range config.Interfaces[choice] {
 			title := config.Types[itype].Description
-			choices = append(choices, title.String(c.lang))
+			choices = append(choices, title.String(c.templateLang()))
 			choiceTypes = append(choiceTypes, itype)
 		}
 	}
@@ -0,0 +1,106 @@
+package configure
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/evcc-io/evcc/util/templates"
+	"golang.org/x/oauth2"
+)
+
+const (
+	paramAccessToken  = "accessToken"
+	paramRefreshToken = "refreshToken"
+	paramUser         = "user"
+	paramPassword     = "password"
+)
+
+// Login is the interactive account login of a vehicle template. Token logins create the template's access and
+// refresh tokens, credential logins verify the user and password of templates logging in at runtime.
+// The login functions receive the param values provided so far, token logins write their prompts to the wizard output.
+type Login struct {
+	Token   func(io.Writer, map[string]interface{}) (*oauth2.Token, error)     // runs the login flow
+	Refresh func(map[string]interface{}, *oauth2.Token) (*oauth2.Token, error) // refreshes the token to verify it remains usable
+	Verify  func(map[string]interface{}) error                                 // verifies the user and password
+}
+
+// processLogin runs the template's account login and adds the resulting tokens to the values instead of asking for them.
+// The login is interactive only, answer files provide the tokens as param values.
+func (c *CmdConfigure) processLogin(templateItem *templates.Template, values map[string]interface{}) bool {
+	login, ok := c.Logins[templateItem.Template]
+	if !ok || login.Token == nil || c.answers != nil {
+		return false
+	}
+
+	if refreshIndex, _ := templateItem.ParamByName(paramRefreshToken); refreshIndex < 0 {
+		return false
+	}
+
+	fmt.Fprintln(c.console)
+
+	start := true
+	if err := c.askInteractive(&survey.Confirm{Message: c.localizedString("Login_Start", nil), Default: true}, &start); err != nil || !start {
+		return false
+	}
+
+	token, err := login.Token(c.console, values)
+	if err == nil && login.Refresh != nil {
+		fmt.Fprintln(c.console, c.localizedString("Login_Refresh", nil))
+		token, err = login.Refresh(values, token)
+	}
+
+	if err == nil && (token.AccessToken == "" || token.RefreshToken == "") {
+		err = errors.New("missing access and/or refresh token")
+	}
+
+	if err != nil {
+		fmt.Fprintln(c.console, c.localizedString("Login_Failed", localizeMap{"Error": err}))
+		return false
+	}
+
+	values[paramAccessToken] = token.AccessToken
+	values[paramRefreshToken] = token.RefreshToken
+
+	fmt.Fprintln(c.console, c.localizedString("Login_Successful", nil))
+
+	return true
+}
+
+// verifyLogin verifies the credentials of templates logging in at runtime, asking for them again if the login fails
+func (c *CmdConfigure) verifyLogin(templateItem *templates.Template, values map[string]interface{}) {
+	login, ok := c.Logins[templateItem.Template]
+	if !ok || login.Verify == nil || c.answers != nil {
+		return
+	}
+
+	for {
+		fmt.Fprintln(c.console)
+		fmt.Fprintln(c.console, c.localizedString("Login_Verify", nil))
+
+		err := login.Verify(values)
+		if err == nil {
+			fmt.Fprintln(c.console, c.localizedString("Login_Verified", nil))
+			return
+		}
+
+		fmt.Fprintln(c.console, c.localizedString("Login_VerifyFailed", localizeMap{"Error": err}))
+
+		retry := true
+		if err := c.askInteractive(&survey.Confirm{Message: c.localizedString("Login_Retry", nil), Default: true}, &retry); err != nil || !retry {
+			return
+		}
+
+		for _, name := range []string{paramUser, paramPassword} {
+			if index, param := templateItem.ParamByName(name); index >= 0 {
+				value := c.processInputConfig(param)
+				values[name] = value
+
+				if param.Mask {
+					c.processSecret(templateItem.Template, param.Name, value)
+				}
+			}
+		}
+	}
+}
@@ -2,32 +2,36 @@ package configure
 
 import (
 	"bufio"
-	_ "embed"
+	"embed"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"strconv"
 	"strings"
 
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
 	"github.com/BurntSushi/toml"
 	"github.com/cloudfoundry/jibber_jabber"
+	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/hems/semp"
 	"github.com/evcc-io/evcc/server"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/secrets"
 	"github.com/evcc-io/evcc/util/templates"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"golang.org/x/exp/slices"
 	"golang.org/x/text/language"
 )
 
-//go:embed localization/de.toml
-var lang_de string
-
-//go:embed localization/en.toml
-var lang_en string
+//go:embed localization/*.toml
+var localizations embed.FS
 
 type CmdConfigure struct {
 	configuration Configure
+	bundle        *i18n.Bundle
 	localizer     *i18n.Localizer
 	log           *util.Logger
 
@@ -37,44 +41,73 @@ type CmdConfigure struct {
 	errItemNotPresent, errDeviceNotValid error
 
 	capabilitySMAHems bool
+
+	answers *answers // pre-recorded answers for headless mode
+	session *session // checkpoint for resuming interactive mode
+
+	discovered       []discovery                  // devices found on the local network
+	discoveredValues map[string]map[string]string // param defaults by discovered item title
+
+	configFile    string            // configuration file written to, the secrets file is located next to it
+	secrets       *secrets.Store    // encrypted secrets file
+	deviceSecrets map[string]string // secret names by value of the current device
+
+	dryRun  bool                // print configuration instead of writing it
+	output  io.Writer           // configuration output in dry-run mode
+	console terminal.FileWriter // prompts and messages, stderr in dry-run mode to keep the configuration output clean
+
+	// Validator checks the generated configuration in dry-run mode
+	Validator func(yaml []byte) error
+
+	// Logins are the interactive account logins by vehicle template
+	Logins map[string]Login
 }
 
 // Run starts the interactive configuration
-func (c *CmdConfigure) Run(log *util.Logger, flagLang string, advancedMode, expandedMode bool, category string) {
+func (c *CmdConfigure) Run(log *util.Logger, flagLang string, advancedMode, expandedMode, dryRun bool, category, mergeFile, answerFile string) {
 	c.log = log
 	c.advancedMode = advancedMode
 	c.expandedMode = expandedMode
+	c.dryRun = dryRun
 
-	c.log.INFO.Printf("evcc %s", server.FormattedVersion())
-
-	bundle := i18n.NewBundle(language.German)
-	bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
-	if _, err := bundle.ParseMessageFileBytes([]byte(lang_de), "localization/de.toml"); err != nil {
-		panic(err)
-	}
-	if _, err := bundle.ParseMessageFileBytes([]byte(lang_en), "localization/en.toml"); err != nil {
-		panic(err)
+	c.configFile = DefaultConfigFilename
+	if mergeFile != "" {
+		c.configFile = mergeFile
 	}
 
-	c.lang = "de"
-	systemLanguage, err := jibber_jabber.DetectLanguage()
-	if err == nil {
-		c.lang = systemLanguage
+	// keep stdout for the configuration in dry-run mode, prompts and messages go to stderr
+	c.console = os.Stdout
+	if c.dryRun {
+		c.output = os.Stdout
+		c.console = os.Stderr
 	}
-	if flagLang != "" {
-		c.lang = flagLang
+
+	c.log.INFO.Printf("evcc %s", server.FormattedVersion())
+
+	if answerFile != "" {
+		var err error
+		if c.answers, err = loadAnswers(answerFile); err != nil {
+			c.log.FATAL.Fatal(err)
+		}
 	}
 
-	c.localizer = i18n.NewLocalizer(bundle, c.lang)
+	c.setupLocalizer(flagLang)
+	if flagLang == "" && c.answers == nil {
+		c.selectLanguage()
+	}
 
-	c.setDefaultTexts()
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Intro", nil))
 
-	fmt.Println()
-	fmt.Println(c.localizedString("Intro", nil))
+	// checkpoint interactive sessions for resuming after interruption
+	if c.answers == nil {
+		c.resumeSession()
+		defer c.session.remove()
+	}
 
 	if !c.advancedMode && category == "" {
 		// ask the user for his knowledge, so advanced mode can also be turned on this way
-		fmt.Println()
+		fmt.Fprintln(c.console)
 		flowIndex, _ := c.askChoice(c.localizedString("Flow_Mode", nil), []string{
 			c.localizedString("Flow_Mode_Standard", nil),
 			c.localizedString("Flow_Mode_Advanced", nil),
@@ -84,84 +117,245 @@ func (c *CmdConfigure) Run(log *util.Logger, flagLang string, advancedMode, expa
 		}
 	}
 
+	c.discoverDevices()
+
 	if !c.advancedMode && category == "" {
 		c.flowNewConfigFile()
 		return
 	}
 
 	if category != "" {
-		for cat := range DeviceCategories {
-			if cat == DeviceCategory(category) {
-				c.flowSingleDevice(DeviceCategory(category))
-				return
-			}
+		if !slices.Contains(singleDeviceCategories, DeviceCategory(category)) {
+			c.log.FATAL.Fatalf("invalid category: %s, must be one of %v", category, singleDeviceCategories)
 		}
 
-		panic("invalid category: " + category)
+		c.flowSingleDevice(DeviceCategory(category), mergeFile)
+		return
 	}
 
-	fmt.Println()
+	fmt.Fprintln(c.console)
 	flowIndex, _ := c.askChoice(c.localizedString("Flow_Type", nil), []string{
 		c.localizedString("Flow_Type_NewConfiguration", nil),
 		c.localizedString("Flow_Type_SingleDevice", nil),
+		c.localizedString("Flow_Type_EditConfiguration", nil),
 	})
 	switch flowIndex {
 	case 0:
 		c.flowNewConfigFile()
 	case 1:
-		c.flowSingleDevice("")
+		c.flowSingleDevice("", "")
+	case 2:
+		c.flowEditConfigFile()
 	}
 }
 
-// configureSingleDevice implements the flow for getting a single device configuration
-func (c *CmdConfigure) flowSingleDevice(category DeviceCategory) {
-	fmt.Println()
-	fmt.Println(c.localizedString("Flow_SingleDevice_Setup", nil))
-	fmt.Println()
-	fmt.Println(c.localizedString("Flow_SingleDevice_Select", nil))
+// setupLocalizer loads the localizations and selects the language
+func (c *CmdConfigure) setupLocalizer(flagLang string) {
+	// missing translations fall back to english
+	c.bundle = i18n.NewBundle(language.English)
+	c.bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
 
-	// only consider the device categories that are marked for this flow
-	categoryChoices := []string{
-		DeviceCategories[DeviceCategoryGridMeter].title,
-		DeviceCategories[DeviceCategoryPVMeter].title,
-		DeviceCategories[DeviceCategoryBatteryMeter].title,
-		DeviceCategories[DeviceCategoryChargeMeter].title,
-		DeviceCategories[DeviceCategoryCharger].title,
-		DeviceCategories[DeviceCategoryVehicle].title,
+	files, err := fs.Glob(localizations, "localization/*.toml")
+	if err != nil {
+		panic(err)
 	}
 
+	for _, file := range files {
+		b, err := localizations.ReadFile(file)
+		if err == nil {
+			_, err = c.bundle.ParseMessageFileBytes(b, file)
+		}
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	lang := "de"
+	systemLanguage, err := jibber_jabber.DetectLanguage()
+	if err == nil {
+		lang = systemLanguage
+	}
+	if flagLang != "" {
+		lang = flagLang
+	}
+
+	c.setLanguage(lang)
+}
+
+// setLanguage switches the language of the dialog
+func (c *CmdConfigure) setLanguage(lang string) {
+	c.lang = lang
+	c.localizer = i18n.NewLocalizer(c.bundle, c.lang)
+
+	c.setDefaultTexts()
+}
+
+// languages returns the available dialog languages and their names
+func (c *CmdConfigure) languages() ([]string, []string) {
+	var langs, names []string
+
+	for _, tag := range c.bundle.LanguageTags() {
+		base, _ := tag.Base()
+		langs = append(langs, base.String())
+	}
+	slices.Sort(langs)
+
+	for _, lang := range langs {
+		names = append(names, i18n.NewLocalizer(c.bundle, lang).MustLocalize(&i18n.LocalizeConfig{
+			MessageID: "Language_Name",
+		}))
+	}
+
+	return langs, names
+}
+
+// selectLanguage asks for the dialog language, preselecting the detected language.
+// The selection is interactive only, answer files use the --lang flag.
+func (c *CmdConfigure) selectLanguage() {
+	langs, names := c.languages()
+
+	current := slices.Index(langs, c.lang)
+	if current < 0 {
+		current = slices.Index(langs, language.English.String())
+	}
+
+	fmt.Fprintln(c.console)
+
+	var name string
+	prompt := &survey.Select{
+		Message: c.localizedString("Flow_Language", nil),
+		Options: names,
+		Default: names[current],
+	}
+	if err := c.askInteractive(prompt, &name); err != nil {
+		return
+	}
+
+	if lang := langs[slices.Index(names, name)]; lang != c.lang {
+		c.setLanguage(lang)
+	}
+}
+
+// singleDeviceCategories are the device categories that can be configured as single device
+var singleDeviceCategories = []DeviceCategory{
+	DeviceCategoryGridMeter, DeviceCategoryPVMeter, DeviceCategoryBatteryMeter,
+	DeviceCategoryChargeMeter, DeviceCategoryCharger, DeviceCategoryVehicle,
+}
+
+// flowSingleDevice implements the flow for getting a single device configuration.
+// The devices are merged into the existing configuration file if given.
+func (c *CmdConfigure) flowSingleDevice(category DeviceCategory, mergeFile string) {
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Flow_SingleDevice_Setup", nil))
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Flow_SingleDevice_Select", nil))
+
 	if category == "" {
-		fmt.Println()
-		_, categoryTitle := c.askChoice(c.localizedString("Flow_SingleDevice_Select", nil), categoryChoices)
+		category = c.askDeviceCategory()
+	}
 
-		for item, data := range DeviceCategories {
-			if data.title == categoryTitle {
-				category = item
-				break
+	devices := c.configureDevices(category, false, false)
+
+	if mergeFile != "" {
+		c.mergeDevices(category, devices, mergeFile)
+		return
+	}
+
+	// configuration fragment including the site's meter references
+	if c.dryRun {
+		file := newConfigFile()
+		for _, item := range devices {
+			if _, err := file.add(category, item); err != nil {
+				c.log.FATAL.Fatal(err)
 			}
 		}
+
+		b, err := file.bytes()
+		if err == nil {
+			_, err = c.output.Write(b)
+		}
+		if err != nil {
+			c.log.FATAL.Fatal(err)
+		}
+
+		return
 	}
 
-	devices := c.configureDevices(category, false, false)
 	for _, item := range devices {
-		fmt.Println()
-		fmt.Println(c.localizedString("Flow_SingleDevice_Config", localizeMap{}))
-		fmt.Println()
+		fmt.Fprintln(c.console)
+		fmt.Fprintln(c.console, c.localizedString("Flow_SingleDevice_Config", localizeMap{}))
+		fmt.Fprintln(c.console)
 
 		scanner := bufio.NewScanner(strings.NewReader(item.Yaml))
 		for scanner.Scan() {
-			fmt.Println("  " + scanner.Text())
+			fmt.Fprintln(c.console, "  "+scanner.Text())
+		}
+	}
+	fmt.Fprintln(c.console)
+}
+
+// mergeDevices adds the devices to the existing configuration file
+func (c *CmdConfigure) mergeDevices(category DeviceCategory, devices []device, filename string) {
+	file, err := loadConfigFile(filename)
+	if err != nil {
+		c.log.FATAL.Fatal(c.localizedString("Edit_Error_LoadFailed", localizeMap{"FileName": filename, "Error": err}))
+	}
+
+	fmt.Fprintln(c.console)
+	for _, item := range devices {
+		name, err := file.add(category, item)
+		if err != nil {
+			c.log.FATAL.Fatal(err)
+		}
+		fmt.Fprintln(c.console, c.localizedString("Edit_Added", localizeMap{"Name": name}))
+	}
+
+	if c.dryRun {
+		b, err := file.bytes()
+		if err != nil {
+			c.log.FATAL.Fatal(err)
 		}
+		c.printConfiguration(b)
+		return
+	}
+
+	if err := file.save(filename); err != nil {
+		fmt.Fprintf(c.console, "%s: ", c.localizedString("File_Error_SaveFailed", localizeMap{"FileName": filename}))
+		c.log.FATAL.Fatal(err)
 	}
-	fmt.Println()
+	fmt.Fprintln(c.console, c.localizedString("File_SaveSuccess", localizeMap{"FileName": filename}))
+}
+
+// askDeviceCategory lets the user choose the category of a single device
+func (c *CmdConfigure) askDeviceCategory() DeviceCategory {
+	// only consider the device categories that are marked for this flow
+	categoryChoices := []string{
+		DeviceCategories[DeviceCategoryGridMeter].title,
+		DeviceCategories[DeviceCategoryPVMeter].title,
+		DeviceCategories[DeviceCategoryBatteryMeter].title,
+		DeviceCategories[DeviceCategoryChargeMeter].title,
+		DeviceCategories[DeviceCategoryCharger].title,
+		DeviceCategories[DeviceCategoryVehicle].title,
+	}
+
+	fmt.Fprintln(c.console)
+	_, categoryTitle := c.askChoice(c.localizedString("Flow_SingleDevice_Select", nil), categoryChoices)
+
+	for item, data := range DeviceCategories {
+		if data.title == categoryTitle {
+			return item
+		}
+	}
+
+	return ""
 }
 
 // configureNewConfigFile implements the flow for creating a new configuration file
 func (c *CmdConfigure) flowNewConfigFile() {
-	fmt.Println()
-	fmt.Println(c.localizedString("Flow_NewConfiguration_Setup", nil))
-	fmt.Println()
-	fmt.Println(c.localizedString("Flow_NewConfiguration_Select", localizeMap{"ItemNotPresent": c.localizedString("ItemNotPresent", nil)}))
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Flow_NewConfiguration_Setup", nil))
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Flow_NewConfiguration_Select", localizeMap{"ItemNotPresent": c.localizedString("ItemNotPresent", nil)}))
 	c.configureDeviceGuidedSetup()
 
 	_ = c.configureDevices(DeviceCategoryGridMeter, true, false)
@@ -172,6 +366,10 @@ func (c *CmdConfigure) flowNewConfigFile() {
 	c.configureLoadpoints()
 	c.configureSite()
 
+	if c.configuration.config.MQTT == "" {
+		c.configureMQTTIntegration()
+	}
+
 	// check if SMA HEMS is available and ask the user if it should be added
 	if c.capabilitySMAHems {
 		c.configureSMAHems()
@@ -186,7 +384,12 @@ func (c *CmdConfigure) flowNewConfigFile() {
 		c.log.FATAL.Fatal(err)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(c.console)
+
+	if c.dryRun {
+		c.printConfiguration(yaml)
+		return
+	}
 
 	filename := DefaultConfigFilename
 
@@ -198,7 +401,7 @@ func (c *CmdConfigure) flowNewConfigFile() {
 		file.Close()
 		// in case of permission error, we can't write to the file anyway
 		if os.IsPermission(err) {
-			fmt.Println(c.localizedString("File_Permissions", localizeMap{"FileName": filename}))
+			fmt.Fprintln(c.console, c.localizedString("File_Permissions", localizeMap{"FileName": filename}))
 		} else {
 			if c.askYesNo(c.localizedString("File_Exists", localizeMap{"FileName": filename})) {
 				break
@@ -214,10 +417,27 @@ func (c *CmdConfigure) flowNewConfigFile() {
 
 	err = os.WriteFile(filename, yaml, 0o755)
 	if err != nil {
-		fmt.Printf("%s: ", c.localizedString("File_Error_SaveFailed", localizeMap{"FileName": filename}))
+		fmt.Fprintf(c.console, "%s: ", c.localizedString("File_Error_SaveFailed", localizeMap{"FileName": filename}))
 		c.log.FATAL.Fatal(err)
 	}
-	fmt.Println(c.localizedString("File_SaveSuccess", localizeMap{"FileName": filename}))
+	fmt.Fprintln(c.console, c.localizedString("File_SaveSuccess", localizeMap{"FileName": filename}))
+}
+
+// printConfiguration validates the configuration and writes it to the dry-run output
+// Validation errors are reported after printing the configuration for review.
+func (c *CmdConfigure) printConfiguration(yaml []byte) {
+	var validationErr error
+	if c.Validator != nil {
+		validationErr = c.Validator(yaml)
+	}
+
+	if _, err := c.output.Write(yaml); err != nil {
+		c.log.FATAL.Fatal(err)
+	}
+
+	if validationErr != nil {
+		c.log.FATAL.Fatal(validationErr)
+	}
 }
 
 // configureDevices asks device specific questions
@@ -239,7 +459,7 @@ func (c *CmdConfigure) configureDevices(deviceCategory DeviceCategory, askAdding
 			addDeviceText = c.localizedString("AddAnotherDeviceInCategory", localizeMap)
 		}
 
-		fmt.Println()
+		fmt.Fprintln(c.console)
 		if !c.askYesNo(addDeviceText) {
 			return nil
 		}
@@ -258,7 +478,7 @@ func (c *CmdConfigure) configureDevices(deviceCategory DeviceCategory, askAdding
 			break
 		}
 
-		fmt.Println()
+		fmt.Fprintln(c.console)
 		if !c.askYesNo(c.localizedString("AddAnotherDeviceInCategory", localizeMap)) {
 			break
 		}
@@ -267,6 +487,19 @@ func (c *CmdConfigure) configureDevices(deviceCategory DeviceCategory, askAdding
 	return devices
 }
 
+// configureMQTTIntegration asks the user if evcc should connect to an mqtt broker
+func (c *CmdConfigure) configureMQTTIntegration() {
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Flow_MQTT_Setup", nil))
+
+	fmt.Fprintln(c.console)
+	if !c.askYesNo(c.localizedString("Flow_MQTT_Add", nil)) {
+		return
+	}
+
+	_ = c.configureMQTTBroker()
+}
+
 // configureSMAHems asks the user if he wants to add the SMA HEMS
 func (c *CmdConfigure) configureSMAHems() {
 	// check if the system provides a machine-id
@@ -274,10 +507,10 @@ func (c *CmdConfigure) configureSMAHems() {
 		return
 	}
 
-	fmt.Println()
-	fmt.Println(c.localizedString("Flow_SMAHems_Setup", nil))
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Flow_SMAHems_Setup", nil))
 
-	fmt.Println()
+	fmt.Fprintln(c.console)
 	if !c.askYesNo(c.localizedString("Flow_SMAHems_Add", nil)) {
 		return
 	}
@@ -288,14 +521,18 @@ func (c *CmdConfigure) configureSMAHems() {
 
 // configureLoadpoints asks loadpoint specific questions
 func (c *CmdConfigure) configureLoadpoints() {
-	fmt.Println()
-	fmt.Println(c.localizedString("Loadpoint_Setup", nil))
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Loadpoint_Setup", nil))
 
 	for {
+		defaultTitle := c.localizedString("Loadpoint_DefaultTitle", nil)
+		if count := len(c.configuration.config.Loadpoints); count > 0 {
+			defaultTitle = fmt.Sprintf("%s %d", defaultTitle, count+1)
+		}
 
 		loadpointTitle := c.askValue(question{
 			label:        c.localizedString("Loadpoint_Title", nil),
-			defaultValue: c.localizedString("Loadpoint_DefaultTitle", nil),
+			defaultValue: defaultTitle,
 			required:     true,
 		})
 		loadpoint := loadpoint{
@@ -322,14 +559,26 @@ func (c *CmdConfigure) configureLoadpoints() {
 			}
 		}
 
-		vehicles := c.configuration.DevicesOfClass(templates.Vehicle)
+		// vehicles not yet assigned to another loadpoint
+		assigned := make(map[string]bool)
+		for _, lp := range c.configuration.config.Loadpoints {
+			assigned[lp.Vehicle] = true
+		}
+
+		var vehicles []device
+		for _, vehicle := range c.configuration.DevicesOfClass(templates.Vehicle) {
+			if !assigned[vehicle.Name] {
+				vehicles = append(vehicles, vehicle)
+			}
+		}
+
 		if len(vehicles) > 0 {
-			fmt.Println()
+			fmt.Fprintln(c.console)
 			if c.askYesNo(c.localizedString("Loadpoint_VehicleDisableAutoDetection", nil)) {
 				if len(vehicles) == 1 {
 					loadpoint.Vehicle = vehicles[0].Name
 				} else {
-					fmt.Println()
+					fmt.Fprintln(c.console)
 
 					var vehicleTitles []string
 					for _, vehicle := range vehicles {
@@ -348,10 +597,11 @@ func (c *CmdConfigure) configureLoadpoints() {
 		}
 
 		if c.advancedMode {
-			fmt.Println()
+			fmt.Fprintln(c.console)
 			minAmperage := c.askValue(question{
 				label:          c.localizedString("Loadpoint_WallboxMinAmperage", nil),
 				valueType:      templates.ParamValueTypeNumber,
+				unit:           "A",
 				minNumberValue: int64(minValue),
 				maxNumberValue: 32,
 				required:       true,
@@ -360,6 +610,7 @@ func (c *CmdConfigure) configureLoadpoints() {
 			maxAmperage := c.askValue(question{
 				label:          c.localizedString("Loadpoint_WallboxMaxAmperage", nil),
 				valueType:      templates.ParamValueTypeNumber,
+				unit:           "A",
 				minNumberValue: 6,
 				maxNumberValue: 32,
 				required:       true,
@@ -368,7 +619,7 @@ func (c *CmdConfigure) configureLoadpoints() {
 
 			if !chargerHasMeter {
 				phaseChoices := []string{"1", "2", "3"}
-				fmt.Println()
+				fmt.Fprintln(c.console)
 				phaseIndex, _ := c.askChoice(c.localizedString("Loadpoint_WallboxPhases", nil), phaseChoices)
 				loadpoint.Phases = phaseIndex + 1
 			}
@@ -379,7 +630,7 @@ func (c *CmdConfigure) configureLoadpoints() {
 				c.localizedString("Loadpoint_WallboxPower22kW", nil),
 				c.localizedString("Loadpoint_WallboxPowerOther", nil),
 			}
-			fmt.Println()
+			fmt.Fprintln(c.console)
 			powerIndex, _ := c.askChoice(c.localizedString("Loadpoint_WallboxMaxPower", nil), powerChoices)
 			loadpoint.MinCurrent = minValue
 			switch powerIndex {
@@ -402,6 +653,7 @@ func (c *CmdConfigure) configureLoadpoints() {
 				amperage := c.askValue(question{
 					label:          c.localizedString("Loadpoint_WallboxMaxAmperage", nil),
 					valueType:      templates.ParamValueTypeNumber,
+					unit:           "A",
 					minNumberValue: int64(minValue),
 					maxNumberValue: 32,
 					required:       true,
@@ -410,34 +662,155 @@ func (c *CmdConfigure) configureLoadpoints() {
 
 				if !chargerHasMeter {
 					phaseChoices := []string{"1", "2", "3"}
-					fmt.Println()
+					fmt.Fprintln(c.console)
 					phaseIndex, _ := c.askChoice(c.localizedString("Loadpoint_WallboxPhases", nil), phaseChoices)
 					loadpoint.Phases = phaseIndex + 1
 				}
 			}
 		}
 
-		fmt.Println()
+		fmt.Fprintln(c.console)
 		loadpoint.Mode = c.askValue(question{valueType: templates.ParamValueTypeChargeModes, excludeNone: true})
 
-		fmt.Println()
+		fmt.Fprintln(c.console)
 		loadpoint.ResetOnDisconnect = c.askValue(question{
 			label:     c.localizedString("Loadpoint_ResetOnDisconnect", nil),
 			valueType: templates.ParamValueTypeBool,
 		})
 		c.configuration.AddLoadpoint(loadpoint)
+		c.checkpoint()
 
-		fmt.Println()
+		fmt.Fprintln(c.console)
 		if !c.askYesNo(c.localizedString("Loadpoint_AddAnother", nil)) {
 			break
 		}
 	}
+
+	c.configureLoadManagement()
+}
+
+// configureLoadManagement asks for priorities, currents and a shared supply limit if multiple loadpoints are configured
+func (c *CmdConfigure) configureLoadManagement() {
+	loadpoints := c.configuration.config.Loadpoints
+	if len(loadpoints) < 2 {
+		return
+	}
+
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("LoadManagement_Setup", nil))
+
+	c.configureLoadpointPriorities(loadpoints)
+
+	// currents are always asked in advanced mode
+	if !c.advancedMode {
+		c.configureLoadpointCurrents(loadpoints)
+	}
+
+	c.configureSharedSupply(loadpoints)
+
+	c.checkpoint()
+}
+
+// configureLoadpointPriorities asks for the loadpoints' priorities
+func (c *CmdConfigure) configureLoadpointPriorities(loadpoints []loadpoint) {
+	fmt.Fprintln(c.console)
+	if !c.askYesNo(c.localizedString("Loadpoint_Priorities", nil)) {
+		return
+	}
+
+	for i := range loadpoints {
+		priority := c.askValue(question{
+			label:          c.localizedString("Loadpoint_Priority", localizeMap{"Title": loadpoints[i].Title}),
+			valueType:      templates.ParamValueTypeNumber,
+			defaultValue:   "0",
+			minNumberValue: 0,
+			maxNumberValue: 10,
+			required:       true,
+		})
+		loadpoints[i].Priority, _ = strconv.Atoi(priority)
+	}
+}
+
+// configureLoadpointCurrents asks for the loadpoints' min and max currents
+func (c *CmdConfigure) configureLoadpointCurrents(loadpoints []loadpoint) {
+	fmt.Fprintln(c.console)
+	if !c.askYesNo(c.localizedString("LoadManagement_Currents", nil)) {
+		return
+	}
+
+	for i := range loadpoints {
+		lp := &loadpoints[i]
+
+		// iso 15118 chargers have been configured with lower min current
+		minValue := 6
+		if lp.MinCurrent < minValue {
+			minValue = lp.MinCurrent
+		}
+
+		minCurrent := c.askValue(question{
+			label:          c.localizedString("LoadManagement_MinCurrent", localizeMap{"Title": lp.Title}),
+			valueType:      templates.ParamValueTypeNumber,
+			unit:           "A",
+			defaultValue:   strconv.Itoa(lp.MinCurrent),
+			minNumberValue: int64(minValue),
+			maxNumberValue: 32,
+			required:       true,
+		})
+		lp.MinCurrent, _ = strconv.Atoi(minCurrent)
+
+		maxCurrent := c.askValue(question{
+			label:          c.localizedString("LoadManagement_MaxCurrent", localizeMap{"Title": lp.Title}),
+			valueType:      templates.ParamValueTypeNumber,
+			unit:           "A",
+			defaultValue:   strconv.Itoa(lp.MaxCurrent),
+			minNumberValue: int64(lp.MinCurrent),
+			maxNumberValue: 32,
+			required:       true,
+		})
+		lp.MaxCurrent, _ = strconv.Atoi(maxCurrent)
+	}
+}
+
+// configureSharedSupply asks if the loadpoints share a circuit with limited current and adds a circuit for them
+func (c *CmdConfigure) configureSharedSupply(loadpoints []loadpoint) {
+	fmt.Fprintln(c.console)
+	if !c.askYesNo(c.localizedString("LoadManagement_SharedLimit", nil)) {
+		return
+	}
+
+	var strategy string
+	titles := make([]string, 0, len(loadpoints))
+	for _, lp := range loadpoints {
+		titles = append(titles, lp.Title)
+
+		// share by the configured priorities
+		if lp.Priority > 0 {
+			strategy = site.CircuitPriority
+		}
+	}
+
+	current := c.askValue(question{
+		label:          c.localizedString("LoadManagement_SharedCurrent", nil),
+		valueType:      templates.ParamValueTypeNumber,
+		unit:           "A",
+		minNumberValue: 6,
+		maxNumberValue: 630,
+		required:       true,
+	})
+	amps, _ := strconv.Atoi(current)
+
+	c.configuration.config.Site.Circuits = append(c.configuration.config.Site.Circuits, circuit{
+		Name:       "shared",
+		MaxCurrent: amps,
+		Strategy:   strategy,
+		Loadpoints: titles,
+	})
 }
 
 // configureSite asks site specific questions
 func (c *CmdConfigure) configureSite() {
-	fmt.Println()
-	fmt.Println(c.localizedString("Site_Setup", nil))
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Site_Setup", nil))
 
 	siteTitle := c.askValue(question{
 		label:        c.localizedString("Site_Title", nil),
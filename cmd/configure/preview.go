@@ -0,0 +1,78 @@
+package configure
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/evcc-io/evcc/util/templates"
+)
+
+const (
+	previewRefreshes = 4
+	previewInterval  = 2 * time.Second
+)
+
+// previewDevice shows the live values of a successfully tested meter or charger and asks if they are plausible.
+// The preview is interactive only, answer files accept the device and resumed sessions replay the decision.
+func (c *CmdConfigure) previewDevice(deviceTest DeviceTest) bool {
+	if c.answers != nil {
+		return c.session == nil || !c.session.rejected()
+	}
+
+	class := DeviceCategories[deviceTest.DeviceCategory].class
+	if deviceTest.Pairing != nil || class == templates.Vehicle {
+		return true
+	}
+
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Preview_Title", nil))
+
+	var refresh int
+	var pvInverted bool
+
+	err := deviceTest.Preview(previewRefreshes, previewInterval, func(v PreviewValues) {
+		refresh++
+
+		if v.Error != nil {
+			fmt.Fprintf(c.console, "  %d/%d  %s\n", refresh, previewRefreshes, c.localizedString("Error", localizeMap{"Error": v.Error}))
+			return
+		}
+
+		var values []string
+		if v.Status != "" {
+			values = append(values, fmt.Sprintf("%s: %s", c.localizedString("Preview_Status", nil), v.Status))
+		}
+		if v.Power != nil {
+			values = append(values, fmt.Sprintf("%s: %.0f W", c.localizedString("Preview_Power", nil), *v.Power))
+			pvInverted = pvInverted || deviceTest.DeviceCategory == DeviceCategoryPVMeter && *v.Power < 0
+		}
+		if v.SoC != nil {
+			values = append(values, fmt.Sprintf("%s: %.0f %%", c.localizedString("Preview_SoC", nil), *v.SoC))
+		}
+
+		fmt.Fprintf(c.console, "  %d/%d  %s\n", refresh, previewRefreshes, strings.Join(values, ", "))
+	})
+
+	if err != nil {
+		fmt.Fprintln(c.console, "  ", c.localizedString("Error", localizeMap{"Error": err}))
+	}
+
+	if pvInverted {
+		fmt.Fprintln(c.console, c.localizedString("Preview_PVNegative", nil))
+	}
+
+	fmt.Fprintln(c.console)
+
+	accept := true
+	if err := c.askInteractive(&survey.Confirm{Message: c.localizedString("Preview_Accept", nil), Default: true}, &accept); err != nil {
+		return true
+	}
+
+	if !accept && c.session != nil {
+		c.session.reject()
+	}
+
+	return accept
+}
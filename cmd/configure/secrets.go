@@ -0,0 +1,51 @@
+package configure
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/evcc-io/evcc/util/secrets"
+	"golang.org/x/exp/slices"
+)
+
+// processSecret offers storing a masked param value in the encrypted secrets file.
+// Stored values are replaced by a `!secret` reference when rendering the device.
+// The offer is interactive only and not part of answer files or sessions.
+func (c *CmdConfigure) processSecret(template, param, value string) {
+	if c.dryRun || c.answers != nil {
+		return
+	}
+
+	store := true
+	if err := c.askInteractive(&survey.Confirm{Message: c.localizedString("Config_StoreSecret", nil), Default: true}, &store); err != nil || !store {
+		return
+	}
+
+	filename := secrets.Filename(c.configFile)
+
+	if c.secrets == nil {
+		var err error
+		if c.secrets, err = secrets.Open(filename); err != nil {
+			fmt.Fprintln(c.console, c.localizedString("Error", localizeMap{"Error": err}))
+			return
+		}
+	}
+
+	base := fmt.Sprintf("%s-%s", template, param)
+	name := base
+	for i := 2; slices.Contains(c.secrets.Names(), name); i++ {
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	err := c.secrets.Set(name, value)
+	if err == nil {
+		err = c.secrets.Save()
+	}
+	if err != nil {
+		fmt.Fprintln(c.console, c.localizedString("Error", localizeMap{"Error": err}))
+		return
+	}
+
+	c.deviceSecrets[value] = name
+	fmt.Fprintln(c.console, c.localizedString("Config_SecretStored", localizeMap{"Name": name, "FileName": filename}))
+}
@@ -0,0 +1,107 @@
+package configure
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/evcc-io/evcc/util/templates"
+)
+
+// common RS485 baud rates probed when the device test fails with the configured baud rate
+var serialBaudrates = []int{9600, 19200, 38400, 57600, 115200, 4800, 2400}
+
+// serialPort is an available serial port
+type serialPort struct {
+	Device      string // device path, e.g. /dev/ttyUSB0
+	Description string // usb vendor and product if known
+}
+
+func (p serialPort) String() string {
+	if p.Description == "" {
+		return p.Device
+	}
+	return fmt.Sprintf("%s (%s)", p.Device, p.Description)
+}
+
+// processSerialPort offers the available serial ports for selection instead of asking for the device path.
+// Returns an empty string if no ports are found or manual entry has been chosen.
+// The selection is interactive only, answer files provide the device path as param value.
+func (c *CmdConfigure) processSerialPort(param templates.Param) string {
+	if c.answers != nil {
+		return ""
+	}
+
+	ports := serialPorts()
+	if len(ports) == 0 {
+		return ""
+	}
+
+	var choices []string
+	for _, p := range ports {
+		choices = append(choices, p.String())
+	}
+	choices = append(choices, c.localizedString("Config_SerialPortManual", nil))
+
+	label := param.Description.String(c.templateLang())
+	if label == "" {
+		label = param.Name
+	}
+
+	var selection string
+	if err := c.askInteractive(&survey.Select{Message: label, Options: choices}, &selection); err != nil {
+		return ""
+	}
+
+	for i, choice := range choices[:len(ports)] {
+		if choice == selection {
+			// record the device path so that resumed sessions answer the device question
+			if c.session != nil {
+				c.session.record(nil, &ports[i].Device)
+			}
+			return ports[i].Device
+		}
+	}
+
+	return ""
+}
+
+// probeBaudrate repeats the failed device test of a serial modbus device with the common baud rates.
+// The config values are updated with the first working baud rate.
+func (c *CmdConfigure) probeBaudrate(deviceTest DeviceTest) (DeviceTestResult, bool) {
+	values := deviceTest.ConfigValues
+	if values[templates.ParamModbus] != templates.ModbusKeyRS485Serial {
+		return "", false
+	}
+
+	configured, ok := values[templates.ModbusParamNameBaudrate]
+
+	current := fmt.Sprint(configured)
+	if !ok {
+		_, param := deviceTest.Template.ParamByName(templates.ModbusParamNameBaudrate)
+		current = param.Default
+	}
+
+	for _, baudrate := range serialBaudrates {
+		rate := strconv.Itoa(baudrate)
+		if rate == current {
+			continue
+		}
+
+		fmt.Fprintln(c.console, "  ", c.localizedString("TestingDevice_Baudrate", localizeMap{"Baudrate": rate}))
+		values[templates.ModbusParamNameBaudrate] = rate
+
+		if res, err := c.testDevice(deviceTest); err == nil {
+			fmt.Fprintln(c.console, "  ", c.localizedString("TestingDevice_BaudrateFound", localizeMap{"Baudrate": rate}))
+			return res, true
+		}
+	}
+
+	if ok {
+		values[templates.ModbusParamNameBaudrate] = configured
+	} else {
+		delete(values, templates.ModbusParamNameBaudrate)
+	}
+
+	return "", false
+}
@@ -0,0 +1,62 @@
+package configure
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// serialPorts returns the usb and on-board serial ports from sysfs
+func serialPorts() []serialPort {
+	var res []serialPort
+
+	for _, pattern := range []string{"ttyUSB*", "ttyACM*", "ttyAMA*", "ttySC*"} {
+		matches, _ := filepath.Glob(filepath.Join("/sys/class/tty", pattern))
+
+		for _, tty := range matches {
+			device, err := filepath.EvalSymlinks(filepath.Join(tty, "device"))
+			if err != nil {
+				continue
+			}
+
+			res = append(res, serialPort{
+				Device:      filepath.Join("/dev", filepath.Base(tty)),
+				Description: usbDescription(device),
+			})
+		}
+	}
+
+	return res
+}
+
+// usbDescription returns the vendor and product of the usb device the serial port belongs to
+func usbDescription(path string) string {
+	for dir := path; dir != "/" && dir != "."; dir = filepath.Dir(dir) {
+		vendorID := sysfsAttr(dir, "idVendor")
+		if vendorID == "" {
+			continue
+		}
+
+		var parts []string
+		for _, attr := range []string{"manufacturer", "product"} {
+			if s := sysfsAttr(dir, attr); s != "" {
+				parts = append(parts, s)
+			}
+		}
+
+		parts = append(parts, "["+vendorID+":"+sysfsAttr(dir, "idProduct")+"]")
+
+		return strings.Join(parts, " ")
+	}
+
+	return ""
+}
+
+// sysfsAttr reads a sysfs attribute
+func sysfsAttr(dir, attr string) string {
+	b, err := os.ReadFile(filepath.Join(dir, attr))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
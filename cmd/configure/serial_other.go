@@ -0,0 +1,17 @@
+//go:build !linux
+
+package configure
+
+import "path/filepath"
+
+// serialPorts returns the usb serial ports, device names are not available
+func serialPorts() []serialPort {
+	matches, _ := filepath.Glob("/dev/cu.usb*")
+
+	res := make([]serialPort, 0, len(matches))
+	for _, device := range matches {
+		res = append(res, serialPort{Device: device})
+	}
+
+	return res
+}
@@ -0,0 +1,232 @@
+package configure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AlecAivazis/survey/v2"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// sessionPattern is the checkpoint file pattern of unfinished configurations
+const sessionPattern = "evcc-configure-*.yaml"
+
+// sessionResult is a recorded device test result
+type sessionResult struct {
+	Result   DeviceTestResult
+	Error    string `yaml:",omitempty"`
+	Rejected bool   `yaml:",omitempty"` // live values rejected in preview
+	Inverted bool   `yaml:",omitempty"` // grid meter direction detected as inverted
+}
+
+// session records answers and device test results so that an interrupted configuration can be resumed.
+// Answers are checkpointed after each completed device. Masked answers like passwords are never recorded
+// and asked again when resuming.
+type session struct {
+	Answers []string
+	Secrets []int `yaml:",omitempty"` // positions of masked answers
+	Results []sessionResult
+
+	file       string // checkpoint file
+	pending    int    // answers not yet checkpointed
+	results    []sessionResult
+	resultsPos int
+}
+
+// loadSession reads the latest checkpoint of an unfinished configuration.
+// Only private checkpoint files are considered.
+func loadSession() (*session, error) {
+	files, err := filepath.Glob(filepath.Join(os.TempDir(), sessionPattern))
+	if err != nil {
+		return nil, err
+	}
+
+	var file string
+	var latest os.FileInfo
+	for _, f := range files {
+		fi, err := os.Lstat(f)
+		if err != nil || !fi.Mode().IsRegular() || fi.Mode().Perm() != 0o600 {
+			continue
+		}
+		if latest == nil || fi.ModTime().After(latest.ModTime()) {
+			file, latest = f, fi
+		}
+	}
+
+	if file == "" {
+		return nil, os.ErrNotExist
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	res := session{file: file}
+	if err := yaml.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+
+	if len(res.Answers) == 0 {
+		return nil, errors.New("empty session")
+	}
+
+	return &res, nil
+}
+
+// record adds the answer to the session. Masked answers are only recorded by position.
+func (s *session) record(p survey.Prompt, response interface{}) {
+	if _, ok := p.(*survey.Password); ok {
+		s.Secrets = append(s.Secrets, len(s.Answers))
+		s.Answers = append(s.Answers, "")
+		s.pending++
+		return
+	}
+
+	switch res := response.(type) {
+	case *string:
+		s.Answers = append(s.Answers, *res)
+	case *bool:
+		s.Answers = append(s.Answers, fmt.Sprint(*res))
+	}
+	s.pending++
+}
+
+// secret returns if the answer at the given position has been masked
+func (s *session) secret(pos int) bool {
+	return slices.Contains(s.Secrets, pos)
+}
+
+// result returns the recorded device test result when resuming
+func (s *session) result() (sessionResult, bool) {
+	if s.resultsPos >= len(s.results) {
+		return sessionResult{}, false
+	}
+
+	res := s.results[s.resultsPos]
+	s.resultsPos++
+
+	return res, true
+}
+
+// reject records that the live values of the last tested device were rejected
+func (s *session) reject() {
+	if len(s.Results) > 0 {
+		s.Results[len(s.Results)-1].Rejected = true
+	}
+}
+
+// rejected returns if the live values of the last tested device were rejected
+func (s *session) rejected() bool {
+	return len(s.Results) > 0 && s.Results[len(s.Results)-1].Rejected
+}
+
+// invert records that the last tested grid meter was detected as inverted
+func (s *session) invert() {
+	if len(s.Results) > 0 {
+		s.Results[len(s.Results)-1].Inverted = true
+	}
+}
+
+// inverted returns if the last tested grid meter was detected as inverted
+func (s *session) inverted() bool {
+	return len(s.Results) > 0 && s.Results[len(s.Results)-1].Inverted
+}
+
+// checkpoint saves the session's answers
+func (s *session) checkpoint() error {
+	if s.pending == 0 {
+		return nil
+	}
+
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	// private file, created once per session
+	if s.file == "" {
+		f, err := os.CreateTemp("", sessionPattern)
+		if err != nil {
+			return err
+		}
+		s.file = f.Name()
+
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	err = os.WriteFile(s.file, b, 0o600)
+	s.pending = 0
+
+	return err
+}
+
+// remove deletes the checkpoint once the configuration has completed
+func (s *session) remove() {
+	if s.file != "" {
+		_ = os.Remove(s.file)
+	}
+}
+
+// resumeSession asks to resume an unfinished configuration and replays its answers
+func (c *CmdConfigure) resumeSession() {
+	prev, err := loadSession()
+
+	// resume question is not part of the session
+	if err == nil {
+		fmt.Fprintln(c.console)
+		if !c.askYesNo(c.localizedString("Session_Resume", nil)) {
+			prev.remove()
+			prev = nil
+		}
+	}
+
+	c.session = new(session)
+
+	if prev != nil {
+		c.answers = &answers{Answers: prev.Answers, secrets: prev.Secrets, resume: true}
+		c.session.file = prev.file
+		c.session.results = prev.Results
+	}
+}
+
+// testDevice runs the device test or returns the recorded result when resuming
+func (c *CmdConfigure) testDevice(deviceTest DeviceTest) (DeviceTestResult, error) {
+	if c.session == nil {
+		return deviceTest.Test()
+	}
+
+	if res, ok := c.session.result(); ok {
+		c.session.Results = append(c.session.Results, res)
+		if res.Error != "" {
+			return res.Result, errors.New(res.Error)
+		}
+		return res.Result, nil
+	}
+
+	testResult, err := deviceTest.Test()
+
+	res := sessionResult{Result: testResult}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	c.session.Results = append(c.session.Results, res)
+
+	return testResult, err
+}
+
+// checkpoint saves the session after a device has been completed
+func (c *CmdConfigure) checkpoint() {
+	if c.session == nil {
+		return
+	}
+
+	if err := c.session.checkpoint(); err != nil {
+		c.log.ERROR.Printf("session checkpoint: %v", err)
+	}
+}
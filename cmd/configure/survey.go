@@ -15,19 +15,56 @@ import (
 	"golang.org/x/exp/slices"
 )
 
-// surveyAskOne asks the user for input
+// surveyAskOne asks the user for input or takes it from the answer file
 func (c *CmdConfigure) surveyAskOne(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+	if c.answers != nil {
+		err := c.answers.answer(c.console, p, response, opts...)
+
+		// resumed session continues interactively
+		if errors.Is(err, errAnswersExhausted) && c.answers.resume {
+			c.answers = nil
+			fmt.Fprintln(c.console)
+			fmt.Fprintln(c.console, c.localizedString("Session_Resumed", nil))
+			fmt.Fprintln(c.console)
+			return c.surveyAskOne(p, response, opts...)
+		}
+
+		// masked answers of a resumed session are asked again
+		if errors.Is(err, errAnswerMasked) {
+			err = c.askInteractive(p, response, opts...)
+		}
+
+		if err != nil {
+			fmt.Fprintf(c.console, "%s %s\n", c.localizedString("InputError", nil), err)
+		} else if c.session != nil {
+			c.session.record(p, response)
+		}
+
+		return err
+	}
+
+	err := c.askInteractive(p, response, opts...)
+
+	if err == nil && c.session != nil {
+		c.session.record(p, response)
+	}
+
+	return err
+}
+
+// askInteractive asks the user for input without using the answer file or recording the session
+func (c *CmdConfigure) askInteractive(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
 	opts = append(opts, survey.WithIcons(func(icons *survey.IconSet) {
 		icons.Question.Text = ""
-	}))
+	}), survey.WithStdio(os.Stdin, c.console, os.Stderr))
 	err := survey.AskOne(p, response, opts...)
 
 	if err != nil {
 		if err == terminal.InterruptErr {
-			fmt.Println(c.localizedString("Cancel", nil))
+			fmt.Fprintln(c.console, c.localizedString("Cancel", nil))
 			os.Exit(0)
 		}
-		fmt.Printf("%s %s\n", c.localizedString("InputError", nil), err)
+		fmt.Fprintf(c.console, "%s %s\n", c.localizedString("InputError", nil), err)
 	}
 
 	return err
@@ -35,7 +72,7 @@ func (c *CmdConfigure) surveyAskOne(p survey.Prompt, response interface{}, opts
 
 // askConfigFailureNextStep asks the user if he/she wants to select another device because the current does not work, or continue
 func (c *CmdConfigure) askConfigFailureNextStep() bool {
-	fmt.Println()
+	fmt.Fprintln(c.console)
 	return c.askYesNo(c.localizedString("TestingDevice_RepeatStep", nil))
 }
 
@@ -54,10 +91,11 @@ func (c *CmdConfigure) askSelection(message string, items []string) (string, int
 
 // selectItem selects item from list
 func (c *CmdConfigure) selectItem(deviceCategory DeviceCategory) templates.Template {
-	emptyItem := templates.Template{Lang: c.lang}
+	emptyItem := templates.Template{Lang: c.templateLang()}
 	emptyItem.SetTitle(c.localizedString("ItemNotPresent", nil))
 
 	elements := c.fetchElements(deviceCategory)
+	elements = append(c.discoveredElements(elements), elements...)
 	elements = append(elements, emptyItem)
 
 	var items []string
@@ -105,9 +143,9 @@ type question struct {
 	defaultValue, exampleValue     string
 	invalidValues                  []string
 	validValues                    []string
-	valueType                      string
+	valueType, unit                string
 	minNumberValue, maxNumberValue int64
-	mask, required                 bool
+	mask, confirm, required        bool
 	excludeNone                    bool
 }
 
@@ -120,16 +158,47 @@ func (c *CmdConfigure) askBoolValue(label string) string {
 	return values[index]
 }
 
+// withUnit returns the value including the question's unit
+func (q question) withUnit(value int64) string {
+	if q.unit == "" {
+		return strconv.FormatInt(value, 10)
+	}
+	return fmt.Sprintf("%d %s", value, q.unit)
+}
+
+// valueRange returns the question's value range including the unit
+func (q question) valueRange() string {
+	switch {
+	case q.maxNumberValue == 0:
+		return "≥ " + q.withUnit(q.minNumberValue)
+	case q.minNumberValue == 0:
+		return "≤ " + q.withUnit(q.maxNumberValue)
+	default:
+		return fmt.Sprintf("%d - %s", q.minNumberValue, q.withUnit(q.maxNumberValue))
+	}
+}
+
+// validateRange validates the number value against the question's value range
+func (c *CmdConfigure) validateRange(value float64, q question) error {
+	if q.minNumberValue != 0 && value < float64(q.minNumberValue) {
+		return errors.New(c.localizedString("ValueError_NumberLowerThanMin", localizeMap{"Min": q.withUnit(q.minNumberValue)}))
+	}
+	if q.maxNumberValue != 0 && value > float64(q.maxNumberValue) {
+		return errors.New(c.localizedString("ValueError_NumberBiggerThanMax", localizeMap{"Max": q.withUnit(q.maxNumberValue)}))
+	}
+	return nil
+}
+
 // askValue asks for value input for a given question (template param)
 func (c *CmdConfigure) askValue(q question) string {
 	if q.valueType == templates.ParamValueTypeBool {
 		label := q.label
 		if q.help != "" {
 			helpDescription := stripmd.Strip(q.help)
-			fmt.Println("-------------------------------------------------")
-			fmt.Println(c.localizedString("Value_Help", nil))
-			fmt.Println(helpDescription)
-			fmt.Println("-------------------------------------------------")
+			fmt.Fprintln(c.console, "-------------------------------------------------")
+			fmt.Fprintln(c.console, c.localizedString("Value_Help", nil))
+			fmt.Fprintln(c.console, helpDescription)
+			fmt.Fprintln(c.console, "-------------------------------------------------")
 		}
 
 		return c.askBoolValue(label)
@@ -170,10 +239,13 @@ func (c *CmdConfigure) askValue(q question) string {
 		}
 
 		if q.valueType == templates.ParamValueTypeFloat {
-			_, err := strconv.ParseFloat(value, 64)
+			floatValue, err := strconv.ParseFloat(value, 64)
 			if err != nil {
 				return errors.New(c.localizedString("ValueError_Float", nil))
 			}
+			if err := c.validateRange(floatValue, q); err != nil {
+				return err
+			}
 		}
 
 		if q.valueType == templates.ParamValueTypeNumber {
@@ -181,11 +253,8 @@ func (c *CmdConfigure) askValue(q question) string {
 			if err != nil {
 				return errors.New(c.localizedString("ValueError_Number", nil))
 			}
-			if q.minNumberValue != 0 && intValue < q.minNumberValue {
-				return errors.New(c.localizedString("ValueError_NumberLowerThanMin", localizeMap{"Min": q.minNumberValue}))
-			}
-			if q.maxNumberValue != 0 && intValue > q.maxNumberValue {
-				return errors.New(c.localizedString("ValueError_NumberBiggerThanMax", localizeMap{"Max": q.maxNumberValue}))
+			if err := c.validateRange(float64(intValue), q); err != nil {
+				return err
 			}
 		}
 
@@ -205,6 +274,12 @@ func (c *CmdConfigure) askValue(q question) string {
 	} else {
 		help += " (" + c.localizedString("Value_Optional", nil) + ")"
 	}
+	if q.unit != "" {
+		help += fmt.Sprintf(" ("+c.localizedString("Value_Unit", nil)+": %s)", q.unit)
+	}
+	if q.minNumberValue != 0 || q.maxNumberValue != 0 {
+		help += fmt.Sprintf(" ("+c.localizedString("Value_Range", nil)+": %s)", q.valueRange())
+	}
 	if q.exampleValue != "" {
 		help += fmt.Sprintf(" ("+c.localizedString("Value_Sample", nil)+": %s)", q.exampleValue)
 	}
@@ -223,6 +298,10 @@ func (c *CmdConfigure) askValue(q question) string {
 		}
 	}
 
+	if q.mask && q.confirm && c.answers == nil {
+		return c.askConfirmedValue(prompt, validate)
+	}
+
 	var input string
 	if err := c.surveyAskOne(prompt, &input, survey.WithValidator(validate)); err != nil {
 		c.log.FATAL.Fatal(err)
@@ -230,3 +309,62 @@ func (c *CmdConfigure) askValue(q question) string {
 
 	return input
 }
+
+// askConfirmedValue asks for a masked value twice until both inputs match.
+// The value itself is never recorded in the session.
+func (c *CmdConfigure) askConfirmedValue(prompt survey.Prompt, validate survey.Validator) string {
+	for {
+		var input, confirmation string
+		if err := c.askInteractive(prompt, &input, survey.WithValidator(validate)); err != nil {
+			c.log.FATAL.Fatal(err)
+		}
+
+		if input == "" {
+			return input
+		}
+
+		if err := c.askInteractive(&survey.Password{Message: c.localizedString("Value_Confirm", nil)}, &confirmation); err != nil {
+			c.log.FATAL.Fatal(err)
+		}
+
+		if input == confirmation {
+			if c.session != nil {
+				c.session.record(prompt, &input)
+			}
+			return input
+		}
+
+		fmt.Fprintln(c.console, c.localizedString("ValueError_Mismatch", nil))
+	}
+}
+
+// askValues asks for a list of values for a given question (template param of type stringlist)
+// until the user provides an empty value or doesn't want to add another value
+func (c *CmdConfigure) askValues(q question) []string {
+	var values []string
+
+	for {
+		item := q
+		item.valueType = templates.ParamValueTypeString
+		item.invalidValues = append(slices.Clone(q.invalidValues), values...)
+
+		// values after the first one are always optional
+		if len(values) > 0 {
+			item.required = false
+			item.defaultValue = ""
+		}
+
+		value := c.askValue(item)
+		if value == "" {
+			break
+		}
+
+		values = append(values, value)
+
+		if !c.askYesNo("  " + c.localizedString("Config_AddAnotherValue", nil)) {
+			break
+		}
+	}
+
+	return values
+}
@@ -29,10 +29,25 @@ func (c *CmdConfigure) updateDeviceCategoryTexts(category DeviceCategory, title,
 	DeviceCategories[category] = data
 }
 
-// localizedString is a helper for getting a localized string
+// localizedString is a helper for getting a localized string, falling back to english for missing translations
 func (c *CmdConfigure) localizedString(key string, templateData localizeMap) string {
-	return c.localizer.MustLocalize(&i18n.LocalizeConfig{
+	msg, err := c.localizer.Localize(&i18n.LocalizeConfig{
 		MessageID:    key,
 		TemplateData: templateData,
 	})
+
+	var notFound *i18n.MessageNotFoundErr
+	if err != nil && (msg == "" || !errors.As(err, &notFound)) {
+		panic(err)
+	}
+
+	return msg
+}
+
+// templateLang returns the language of template texts, which are available in german and english only
+func (c *CmdConfigure) templateLang() string {
+	if c.lang == "de" {
+		return c.lang
+	}
+	return "en"
 }
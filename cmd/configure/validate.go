@@ -0,0 +1,95 @@
+package configure
+
+import (
+	"fmt"
+
+	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/templates"
+)
+
+// ValidateDevice is a device from an existing configuration file
+type ValidateDevice struct {
+	Name     string
+	Category DeviceCategory
+	Type     string
+	Other    map[string]interface{}
+}
+
+// Validate tests the devices of an existing configuration using the wizard's device tests.
+// It prints a per-device report and returns false if any device failed.
+func (c *CmdConfigure) Validate(log *util.Logger, flagLang string, devices []ValidateDevice) bool {
+	c.log = log
+
+	c.log.INFO.Printf("evcc %s", server.FormattedVersion())
+
+	c.setupLocalizer(flagLang)
+
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Validate_Title", nil))
+	fmt.Fprintln(c.console)
+
+	var passed, failed, skipped int
+
+	for _, dev := range devices {
+		title := fmt.Sprintf("%s (%s)", dev.Name, DeviceCategories[dev.Category].title)
+
+		result, err := c.validateDevice(dev)
+
+		switch {
+		case err != nil:
+			failed++
+			fmt.Fprintln(c.console, "  [FAIL]", title)
+			fmt.Fprintln(c.console, "        ", c.localizedString("Error", localizeMap{"Error": err}))
+
+		case result == "":
+			skipped++
+			fmt.Fprintln(c.console, "  [SKIP]", title)
+			fmt.Fprintln(c.console, "        ", c.localizedString("Validate_NoTemplate", localizeMap{"Type": dev.Type}))
+
+		case result == DeviceTestResultValidMissingMeter:
+			passed++
+			fmt.Fprintln(c.console, "  [PASS]", title)
+			fmt.Fprintln(c.console, "        ", c.localizedString("Validate_MissingMeter", nil))
+
+		default:
+			passed++
+			fmt.Fprintln(c.console, "  [PASS]", title)
+		}
+	}
+
+	fmt.Fprintln(c.console)
+	fmt.Fprintln(c.console, c.localizedString("Validate_Summary", localizeMap{"Passed": passed, "Failed": failed, "Skipped": skipped}))
+
+	return failed == 0
+}
+
+// validateDevice instantiates a template device and runs the device test.
+// Devices not configured by template return an empty result.
+func (c *CmdConfigure) validateDevice(dev ValidateDevice) (DeviceTestResult, error) {
+	if dev.Type != "template" {
+		return "", nil
+	}
+
+	var cc struct {
+		Template string
+		Other    map[string]interface{} `mapstructure:",remain"`
+	}
+
+	if err := util.DecodeOther(dev.Other, &cc); err != nil {
+		return DeviceTestResultInvalid, err
+	}
+
+	tmpl, err := templates.ByName(DeviceCategories[dev.Category].class, cc.Template)
+	if err != nil {
+		return DeviceTestResultInvalid, err
+	}
+
+	deviceTest := DeviceTest{
+		DeviceCategory: dev.Category,
+		Template:       tmpl,
+		ConfigValues:   dev.Other,
+	}
+
+	return deviceTest.Test()
+}
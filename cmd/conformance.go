@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/evcc-io/evcc/devicetest"
+	"github.com/spf13/cobra"
+)
+
+// conformanceCmd represents the conformance command
+var conformanceCmd = &cobra.Command{
+	Use:   "conformance",
+	Short: "Protocol conformance tests",
+}
+
+// conformanceChargerCmd represents the conformance charger command
+var conformanceChargerCmd = &cobra.Command{
+	Use:   "charger <name>",
+	Short: "Run conformance tests against configured charger",
+	Long: "Run conformance tests against configured charger measuring enable, current change and phase switch latency and status codes. " +
+		"Tests change the charger's current, phases and enabled state. Current and phase tests require a connected vehicle.",
+	Args: cobra.ExactArgs(1),
+	Run:  runConformanceCharger,
+}
+
+const flagTimeout = "timeout"
+
+func init() {
+	rootCmd.AddCommand(conformanceCmd)
+	conformanceCmd.AddCommand(conformanceChargerCmd)
+	conformanceChargerCmd.Flags().Bool(flagJSON, false, "Print report as JSON")
+	conformanceChargerCmd.Flags().Int64P(flagCurrent, "i", 0, "Initial current reduced to 6A for testing current change latency (default 10A)")
+	conformanceChargerCmd.Flags().Duration(flagTimeout, time.Minute, "Maximum latency for the charger to follow a command")
+}
+
+func runConformanceCharger(cmd *cobra.Command, args []string) {
+	// load config
+	if err := loadConfigFile(&conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// setup environment
+	if err := configureEnvironment(cmd, conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if err := cp.configureChargers(conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	name := args[0]
+	c, err := cp.Charger(name)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	var o devicetest.ConformanceOptions
+	o.Current, _ = cmd.Flags().GetInt64(flagCurrent)
+	o.Timeout, _ = cmd.Flags().GetDuration(flagTimeout)
+
+	report := devicetest.Conformance(name, c, o)
+
+	write := report.WriteText
+	if asJSON, _ := cmd.Flags().GetBool(flagJSON); asJSON {
+		write = report.WriteJSON
+	}
+
+	if err := write(os.Stdout); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// wait for shutdown
+	<-shutdownDoneC()
+
+	if !report.Passed() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/evcc-io/evcc/devicetest"
+	"github.com/evcc-io/evcc/util"
+	"github.com/spf13/cobra"
+)
+
+// deviceCmd represents the device command
+var deviceCmd = &cobra.Command{
+	Use:   "device",
+	Short: "Device tools",
+}
+
+// deviceTestCmd represents the device test command
+var deviceTestCmd = &cobra.Command{
+	Use:       "test <charger|meter|vehicle> <name>",
+	Short:     "Run capability tests against configured device",
+	Long:      "Run capability tests against configured device. Charger tests change the charger's current, phases and enabled state and restore the settings of the charger's loadpoint afterwards.",
+	Args:      cobra.ExactArgs(2),
+	ValidArgs: []string{"charger", "meter", "vehicle"},
+	Run:       runDeviceTest,
+}
+
+const (
+	flagJSON     = "json"
+	flagReadOnly = "read-only"
+)
+
+func init() {
+	rootCmd.AddCommand(deviceCmd)
+	deviceCmd.AddCommand(deviceTestCmd)
+	deviceTestCmd.Flags().Bool(flagJSON, false, "Print report as JSON")
+	deviceTestCmd.Flags().Bool(flagReadOnly, false, "Skip tests changing the device state")
+	deviceTestCmd.Flags().Int64P(flagCurrent, "i", 0, "Current for testing maximum current (default 6A)")
+	deviceTestCmd.Flags().IntP(flagPhases, "p", 0, "Phases for testing phase switching (default 3)")
+}
+
+// loadpointSettings returns maximum current and phases of the loadpoint using the charger
+func loadpointSettings(conf config, charger string) (int64, int) {
+	for _, other := range conf.LoadPoints {
+		var lp struct {
+			Charger    string
+			MaxCurrent float64
+			Phases     int
+			Other      map[string]interface{} `mapstructure:",remain"`
+		}
+
+		if err := util.DecodeOther(other, &lp); err == nil && lp.Charger == charger {
+			return int64(lp.MaxCurrent), lp.Phases
+		}
+	}
+
+	return 0, 0
+}
+
+func runDeviceTest(cmd *cobra.Command, args []string) {
+	// load config
+	if err := loadConfigFile(&conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// setup environment
+	if err := configureEnvironment(cmd, conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	class, name := args[0], args[1]
+
+	var report devicetest.Report
+
+	switch class {
+	case "charger":
+		if err := cp.configureChargers(conf); err != nil {
+			log.FATAL.Fatal(err)
+		}
+
+		c, err := cp.Charger(name)
+		if err != nil {
+			log.FATAL.Fatal(err)
+		}
+
+		var o devicetest.Options
+		o.ReadOnly, _ = cmd.Flags().GetBool(flagReadOnly)
+		o.Current, _ = cmd.Flags().GetInt64(flagCurrent)
+		o.Phases, _ = cmd.Flags().GetInt(flagPhases)
+		o.RestoreCurrent, o.RestorePhases = loadpointSettings(conf, name)
+
+		report = devicetest.Charger(name, c, o)
+
+	case "meter":
+		if err := cp.configureMeters(conf); err != nil {
+			log.FATAL.Fatal(err)
+		}
+
+		m, err := cp.Meter(name)
+		if err != nil {
+			log.FATAL.Fatal(err)
+		}
+
+		report = devicetest.Meter(name, m)
+
+	case "vehicle":
+		if err := cp.configureVehicles(conf); err != nil {
+			log.FATAL.Fatal(err)
+		}
+
+		v, err := cp.Vehicle(name)
+		if err != nil {
+			log.FATAL.Fatal(err)
+		}
+
+		report = devicetest.Vehicle(name, v)
+
+	default:
+		log.FATAL.Fatal(fmt.Errorf("invalid device class: %s", class))
+	}
+
+	write := report.WriteText
+	if asJSON, _ := cmd.Flags().GetBool(flagJSON); asJSON {
+		write = report.WriteJSON
+	}
+
+	if err := write(os.Stdout); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// wait for shutdown
+	<-shutdownDoneC()
+
+	if !report.Passed() {
+		os.Exit(1)
+	}
+}
@@ -26,12 +26,15 @@ var (
 	dumpTmpl string
 
 	dumpConfig *bool
+	dumpCheck  *bool
 )
 
 func init() {
 	rootCmd.AddCommand(dumpCmd)
 
 	dumpConfig = dumpCmd.Flags().Bool("cfg", false, "Dump config file")
+	dumpCheck = dumpCmd.Flags().Bool("check", false, "Validate config file without connecting to any device")
+	dumpCmd.Flags().Bool(flagJSON, false, "Print check diagnostics as JSON")
 }
 
 func handle(device any, err error) any {
@@ -42,6 +45,11 @@ func handle(device any, err error) any {
 }
 
 func runDump(cmd *cobra.Command, args []string) {
+	if *dumpCheck {
+		runDumpCheck(cmd)
+		return
+	}
+
 	// load config
 	err := loadConfigFile(&conf)
 
@@ -51,8 +59,9 @@ func runDump(cmd *cobra.Command, args []string) {
 	}
 
 	var site *core.Site
+	var loadPoints []*core.LoadPoint
 	if err == nil {
-		site, err = configureSiteAndLoadpoints(conf)
+		site, loadPoints, err = configureSiteAndLoadpoints(conf)
 	}
 
 	if *dumpConfig {
@@ -125,9 +134,7 @@ func runDump(cmd *cobra.Command, args []string) {
 		d.DumpWithHeader(fmt.Sprintf("vehicle: %s", v.Title()), v)
 	}
 
-	for id, lpI := range site.LoadPoints() {
-		lp := lpI.(*core.LoadPoint)
-
+	for id, lp := range loadPoints {
 		d.Header(fmt.Sprintf("loadpoint %d", id+1), "=")
 		fmt.Println("")
 
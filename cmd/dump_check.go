@@ -0,0 +1,491 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// diagnostic severities
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// diagnostic is a configuration problem at the given position. Line is 0 if the position is unknown.
+type diagnostic struct {
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Path     string `json:"path,omitempty"`
+	Message  string `json:"message"`
+}
+
+type checkResult struct {
+	File        string       `json:"file"`
+	Valid       bool         `json:"valid"`
+	Diagnostics []diagnostic `json:"diagnostics"`
+}
+
+// runDumpCheck validates the configuration file without connecting to any device.
+// It exits with non-zero status if errors are found.
+func runDumpCheck(cmd *cobra.Command) {
+	res := checkResult{Diagnostics: []diagnostic{}}
+
+	// read without logging to keep json output clean
+	err := viper.ReadInConfig()
+
+	if res.File = viper.ConfigFileUsed(); res.File == "" {
+		res.Diagnostics = append(res.Diagnostics, diagnostic{Severity: severityError, Message: err.Error()})
+	} else if b, err := os.ReadFile(res.File); err != nil {
+		res.Diagnostics = append(res.Diagnostics, diagnostic{Severity: severityError, Message: err.Error()})
+	} else {
+		res.Diagnostics = append(res.Diagnostics, checkConfig(b)...)
+	}
+
+	if err == nil {
+		if err := viper.UnmarshalExact(&conf); err != nil {
+			res.Diagnostics = append(res.Diagnostics, diagnostic{Severity: severityError, Message: err.Error()})
+		}
+	}
+
+	res.Valid = !hasErrors(res.Diagnostics)
+
+	if asJSON, _ := cmd.Flags().GetBool(flagJSON); asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(res)
+	} else {
+		for _, d := range res.Diagnostics {
+			fmt.Printf("%s:%d:%d: %s: %s\n", res.File, d.Line, d.Column, d.Severity, d.Message)
+		}
+		if res.Valid {
+			fmt.Println("config valid")
+		}
+	}
+
+	if !res.Valid {
+		os.Exit(1)
+	}
+}
+
+func hasErrors(diags []diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == severityError {
+			return true
+		}
+	}
+	return false
+}
+
+// checker collects the diagnostics of a configuration document
+type checker struct {
+	diags []diagnostic
+	ports map[int]string
+}
+
+var yamlLineRegex = regexp.MustCompile(`line (\d+)`)
+
+// checkConfig validates the configuration file contents
+func checkConfig(b []byte) []diagnostic {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		d := diagnostic{Severity: severityError, Message: err.Error()}
+		if m := yamlLineRegex.FindStringSubmatch(err.Error()); m != nil {
+			d.Line, _ = strconv.Atoi(m[1])
+		}
+		return []diagnostic{d}
+	}
+
+	c := &checker{ports: make(map[int]string)}
+
+	if len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		c.check(doc.Content[0])
+	}
+
+	sort.SliceStable(c.diags, func(i, j int) bool {
+		return c.diags[i].Line < c.diags[j].Line
+	})
+
+	return c.diags
+}
+
+func (c *checker) add(n *yaml.Node, severity, path, format string, a ...any) {
+	d := diagnostic{
+		Severity: severity,
+		Path:     path,
+		Message:  fmt.Sprintf(format, a...),
+	}
+
+	if n != nil {
+		d.Line, d.Column = n.Line, n.Column
+	}
+
+	c.diags = append(c.diags, d)
+}
+
+// yamlEntry returns key and value node of the mapping's case-insensitive key
+func yamlEntry(n *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if strings.EqualFold(n.Content[i].Value, key) {
+			return n.Content[i], n.Content[i+1]
+		}
+	}
+
+	return nil, nil
+}
+
+// yamlValue returns the value node of the mapping's case-insensitive key
+func yamlValue(n *yaml.Node, key string) *yaml.Node {
+	_, v := yamlEntry(n, key)
+	return v
+}
+
+// yamlScalars returns the scalar node or the sequence's scalar nodes
+func yamlScalars(n *yaml.Node) []*yaml.Node {
+	if n == nil {
+		return nil
+	}
+
+	if n.Kind == yaml.SequenceNode {
+		return n.Content
+	}
+
+	return []*yaml.Node{n}
+}
+
+func (c *checker) check(root *yaml.Node) {
+	devices := map[string]map[string]*yaml.Node{
+		"meter":   c.checkDevices(root, "meters", templates.Meter),
+		"charger": c.checkDevices(root, "chargers", templates.Charger),
+		"vehicle": c.checkDevices(root, "vehicles", templates.Vehicle),
+	}
+
+	c.checkReferences(root, devices)
+	c.checkPorts(root)
+}
+
+// checkDevices validates the devices of the class and returns the device nodes by name
+func (c *checker) checkDevices(root *yaml.Node, key string, class templates.Class) map[string]*yaml.Node {
+	res := make(map[string]*yaml.Node)
+
+	seq := yamlValue(root, key)
+	if seq == nil {
+		return res
+	}
+
+	if seq.Kind != yaml.SequenceNode {
+		c.add(seq, severityError, key, "%s must be a list", key)
+		return res
+	}
+
+	for i, dev := range seq.Content {
+		path := fmt.Sprintf("%s[%d]", key, i)
+
+		if dev.Kind != yaml.MappingNode {
+			c.add(dev, severityError, path, "%s must be a map", class)
+			continue
+		}
+
+		name := yamlValue(dev, "name")
+		switch {
+		case name == nil || name.Value == "":
+			c.add(dev, severityError, path, "missing %s name", class)
+		case res[name.Value] != nil:
+			c.add(name, severityError, path+".name", "duplicate %s name: %s already defined in line %d", class, name.Value, res[name.Value].Line)
+		default:
+			res[name.Value] = name
+		}
+
+		typ := yamlValue(dev, "type")
+		if typ == nil || typ.Value == "" {
+			c.add(dev, severityError, path, "missing %s type", class)
+			continue
+		}
+
+		if strings.EqualFold(typ.Value, "template") {
+			c.checkTemplate(dev, path, class)
+		}
+	}
+
+	return res
+}
+
+// isOCPP returns true if the charger is an ocpp charger
+func isOCPP(dev *yaml.Node) bool {
+	for _, key := range []string{"type", "template"} {
+		if n := yamlValue(dev, key); n != nil && strings.EqualFold(n.Value, "ocpp") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTemplate validates the template device's params
+func (c *checker) checkTemplate(dev *yaml.Node, path string, class templates.Class) {
+	name := yamlValue(dev, "template")
+	if name == nil || name.Value == "" {
+		c.add(dev, severityError, path, "missing template")
+		return
+	}
+
+	tmpl, err := templates.ByName(class, name.Value)
+	if err != nil {
+		c.add(name, severityError, path+".template", "%v", err)
+		return
+	}
+
+	var keys []string
+	for i := 0; i+1 < len(dev.Content); i += 2 {
+		k, v := dev.Content[i], dev.Content[i+1]
+		keys = append(keys, k.Value)
+
+		var val interface{}
+		if err := v.Decode(&val); err != nil {
+			c.add(v, severityError, path+"."+k.Value, "%v", err)
+			continue
+		}
+
+		if err := tmpl.ValidateValue(k.Value, val); err != nil {
+			c.add(k, severityError, path+"."+k.Value, "%s: %v", tmpl.Template, err)
+			continue
+		}
+
+		if tmpl.Deprecated(k.Value) {
+			c.add(k, severityWarning, path+"."+k.Value, "%s: %s is deprecated", tmpl.Template, k.Value)
+		}
+	}
+
+	for _, p := range tmpl.MissingParams(keys) {
+		c.add(dev, severityError, path, "%s: missing required param: %s", tmpl.Template, p)
+	}
+}
+
+// checkReferences validates the site's and loadpoints' device references
+func (c *checker) checkReferences(root *yaml.Node, devices map[string]map[string]*yaml.Node) {
+	used := map[string]map[string]*yaml.Node{
+		"meter":   make(map[string]*yaml.Node),
+		"charger": make(map[string]*yaml.Node),
+	}
+
+	ref := func(class string, n *yaml.Node, path string) {
+		if n == nil || n.Value == "" {
+			return
+		}
+
+		if devices[class][n.Value] == nil {
+			c.add(n, severityError, path, "%s does not exist: %s", class, n.Value)
+			return
+		}
+
+		// meters and chargers are exclusive to a single usage
+		if usage, ok := used[class]; ok {
+			if prev := usage[n.Value]; prev != nil {
+				c.add(n, severityError, path, "duplicate %s usage: %s already used in line %d", class, n.Value, prev.Line)
+				return
+			}
+			usage[n.Value] = n
+		}
+	}
+
+	meters := yamlValue(yamlValue(root, "site"), "meters")
+	for _, key := range []string{"grid", "pv", "pvs", "battery", "batteries"} {
+		for i, n := range yamlScalars(yamlValue(meters, key)) {
+			path := "site.meters." + key
+			if n.Kind == yaml.ScalarNode && yamlValue(meters, key).Kind == yaml.SequenceNode {
+				path = fmt.Sprintf("%s[%d]", path, i)
+			}
+			ref("meter", n, path)
+		}
+	}
+
+	if lps := yamlValue(root, "loadpoints"); lps != nil && lps.Kind == yaml.SequenceNode {
+		for i, lp := range lps.Content {
+			path := fmt.Sprintf("loadpoints[%d]", i)
+
+			if charger := yamlValue(lp, "charger"); charger == nil || charger.Value == "" {
+				c.add(lp, severityError, path, "missing charger")
+			} else {
+				ref("charger", charger, path+".charger")
+			}
+
+			ref("meter", yamlValue(lp, "meter"), path+".meter")
+			ref("vehicle", yamlValue(lp, "vehicle"), path+".vehicle")
+
+			for j, v := range yamlScalars(yamlValue(lp, "vehicles")) {
+				ref("vehicle", v, fmt.Sprintf("%s.vehicles[%d]", path, j))
+			}
+		}
+	}
+
+	c.checkCircuits(root, devices["meter"], used["meter"])
+
+	for name, n := range devices["meter"] {
+		if used["meter"][name] == nil {
+			c.add(n, severityWarning, "", "meter %s is not used", name)
+		}
+	}
+}
+
+// checkCircuits validates the circuits' parent and meter references. Circuits must not enclose themselves
+// and a meter must not measure both a circuit and one of its enclosing circuits.
+func (c *checker) checkCircuits(root *yaml.Node, meters, used map[string]*yaml.Node) {
+	seq := yamlValue(yamlValue(root, "site"), "circuits")
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return
+	}
+
+	type circuit struct {
+		path          string
+		parent, meter *yaml.Node
+	}
+
+	var names []string
+	circuits := make(map[string]circuit)
+
+	for i, n := range seq.Content {
+		path := fmt.Sprintf("site.circuits[%d]", i)
+
+		name := yamlValue(n, "name")
+		if name == nil || name.Value == "" {
+			c.add(n, severityError, path, "missing circuit name")
+			continue
+		}
+
+		if _, ok := circuits[name.Value]; ok {
+			c.add(name, severityError, path+".name", "duplicate circuit name: %s", name.Value)
+			continue
+		}
+
+		cc := circuit{path: path, parent: yamlValue(n, "parent"), meter: yamlValue(n, "meter")}
+
+		if m := cc.meter; m != nil && m.Value != "" {
+			if meters[m.Value] == nil {
+				c.add(m, severityError, path+".meter", "meter does not exist: %s", m.Value)
+			} else {
+				used[m.Value] = m
+			}
+		}
+
+		names = append(names, name.Value)
+		circuits[name.Value] = cc
+	}
+
+	for _, name := range names {
+		cc := circuits[name]
+		if cc.parent == nil || cc.parent.Value == "" {
+			continue
+		}
+
+		if _, ok := circuits[cc.parent.Value]; !ok {
+			c.add(cc.parent, severityError, cc.path+".parent", "circuit does not exist: %s", cc.parent.Value)
+			continue
+		}
+
+		visited := map[string]bool{name: true}
+		for p := cc.parent; p != nil && p.Value != ""; p = circuits[p.Value].parent {
+			parent, ok := circuits[p.Value]
+			if !ok {
+				break
+			}
+
+			if visited[p.Value] {
+				c.add(cc.parent, severityError, cc.path+".parent", "circular circuit reference: %s", name)
+				break
+			}
+			visited[p.Value] = true
+
+			if m := cc.meter; m != nil && m.Value != "" && parent.meter != nil && parent.meter.Value == m.Value {
+				c.add(m, severityError, cc.path+".meter", "circular meter reference: %s also measures enclosing circuit %s", m.Value, p.Value)
+			}
+		}
+	}
+}
+
+// usePort registers the port's usage, reporting conflicts
+func (c *checker) usePort(port int, n *yaml.Node, usage string) {
+	if prev, ok := c.ports[port]; ok {
+		c.add(n, severityError, "", "port %d of %s already used by %s", port, usage, prev)
+		return
+	}
+	c.ports[port] = usage
+}
+
+// addressPort returns the port of host:port addresses
+func addressPort(address string) (int, bool) {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return 0, false
+	}
+
+	res, err := strconv.Atoi(port)
+	return res, err == nil
+}
+
+// checkPorts validates that listening ports are not used twice
+func (c *checker) checkPorts(root *yaml.Node) {
+	network := yamlValue(root, "network")
+
+	if listen := yamlValue(network, "listen"); listen != nil && listen.Kind == yaml.SequenceNode {
+		for _, l := range listen.Content {
+			if addr := yamlValue(l, "address"); addr != nil {
+				if port, ok := addressPort(addr.Value); ok {
+					c.usePort(port, addr, "network listener")
+				}
+			}
+		}
+	} else {
+		port := 7070
+		n := yamlValue(network, "port")
+		if n != nil {
+			port, _ = strconv.Atoi(n.Value)
+		}
+		c.usePort(port, n, "network")
+	}
+
+	if proxies := yamlValue(root, "modbusproxy"); proxies != nil && proxies.Kind == yaml.SequenceNode {
+		for _, p := range proxies.Content {
+			if n := yamlValue(p, "port"); n != nil {
+				port, _ := strconv.Atoi(n.Value)
+				c.usePort(port, n, "modbusproxy")
+			}
+		}
+	}
+
+	if n := yamlValue(yamlValue(root, "vehicleproxy"), "port"); n != nil {
+		port, _ := strconv.Atoi(n.Value)
+		c.usePort(port, n, "vehicleproxy")
+	}
+
+	if chargers := yamlValue(root, "chargers"); chargers != nil && chargers.Kind == yaml.SequenceNode {
+		for _, dev := range chargers.Content {
+			if isOCPP(dev) {
+				c.usePort(8887, yamlValue(dev, "type"), "ocpp")
+			}
+		}
+	}
+
+	if k, eebus := yamlEntry(root, "eebus"); eebus != nil {
+		uri, n := ":4712", k
+		if u := yamlValue(eebus, "uri"); u != nil {
+			uri, n = u.Value, u
+		}
+		if port, ok := addressPort(uri); ok {
+			c.usePort(port, n, "eebus")
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const checkSample = `network:
+  port: 7070
+modbusproxy:
+- port: 7070
+meters:
+- name: grid
+  type: template
+  template: shelly-3em
+  usage: grid
+  host: 192.0.2.1
+- name: grid
+  type: custom
+- name: pv
+  type: template
+  template: shelly-3em
+  usage: foo
+  foo: bar
+chargers:
+- name: wallbox
+  type: template
+  template: ocpp
+vehicles:
+- name: car
+  type: template
+  template: offline
+  title: Car
+  capacity: fifty
+loadpoints:
+- title: Garage
+  charger: wallbox
+  meter: grid
+  vehicle: bike
+site:
+  meters:
+    grid: grid
+    pvs:
+    - missing
+  circuits:
+  - name: house
+    meter: pv
+  - name: garage
+    parent: house
+    meter: pv
+  - name: loop
+    parent: loop
+  - name: orphan
+    parent: missing
+`
+
+func TestCheckConfig(t *testing.T) {
+	diags := checkConfig([]byte(checkSample))
+
+	type result struct {
+		line     int
+		severity string
+		message  string
+	}
+
+	var res []result
+	for _, d := range diags {
+		res = append(res, result{d.Line, d.Severity, d.Message})
+	}
+
+	assert.Equal(t, []result{
+		{4, severityError, "port 7070 of modbusproxy already used by network"},
+		{11, severityError, "duplicate meter name: grid already defined in line 6"},
+		{13, severityError, "shelly-3em: missing required param: host"},
+		{16, severityError, "shelly-3em: usage: invalid value foo, must be one of grid, pv, charge"},
+		{17, severityError, "shelly-3em: invalid key: foo"},
+		{27, severityError, "offline: capacity: invalid float: fifty"},
+		{31, severityError, "duplicate meter usage: grid already used in line 35"},
+		{32, severityError, "vehicle does not exist: bike"},
+		{37, severityError, "meter does not exist: missing"},
+		{43, severityError, "circular meter reference: pv also measures enclosing circuit house"},
+		{45, severityError, "circular circuit reference: loop"},
+		{47, severityError, "circuit does not exist: missing"},
+	}, res)
+}
+
+func TestCheckConfigSyntax(t *testing.T) {
+	diags := checkConfig([]byte("meters:\n- name: foo\n bar"))
+	assert.Len(t, diags, 1)
+	assert.Equal(t, 2, diags[0].Line)
+}
@@ -14,6 +14,12 @@ const (
 	flagHeaders            = "log-headers"
 	flagHeadersDescription = "Log headers"
 
+	flagChaos            = "chaos"
+	flagChaosDescription = "Fault injection scenario file (developer mode)"
+
+	flagDryRun            = "dry-run"
+	flagDryRunDescription = "Print the generated configuration instead of writing it"
+
 	flagName            = "name"
 	flagNameDescription = "Select %s by name"
 
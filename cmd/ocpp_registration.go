@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/samber/lo"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// ocppRegistrationsSetting is the settings key of the chargepoints accepted by the ocpp api
+const ocppRegistrationsSetting = "ocpp.registrations"
+
+// ocppRegistration is an accepted chargepoint and its loadpoint configuration
+type ocppRegistration struct {
+	server.OCPPRegistration
+	LoadPoint map[string]interface{} `json:"loadpoint"`
+}
+
+var _ server.OCPPRegistrar = (*ocppRegistrar)(nil)
+
+// ocppRegistrar turns pending ocpp chargepoints into chargers and loadpoints
+type ocppRegistrar struct {
+	mu        sync.Mutex
+	cp        *ConfigProvider
+	templates []ocppTemplate
+	reload    func() error
+}
+
+// ocppRegistrations returns the persisted registrations
+func ocppRegistrations() ([]ocppRegistration, error) {
+	var res []ocppRegistration
+	if err := settings.Json(ocppRegistrationsSetting, &res); err != nil && !errors.Is(err, settings.ErrNotFound) {
+		return nil, fmt.Errorf("cannot load ocpp registrations: %w", err)
+	}
+	return res, nil
+}
+
+var ocppNameRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ocppChargerName returns the charger name of the station id
+func ocppChargerName(id string) string {
+	return "ocpp-" + strings.Trim(ocppNameRegex.ReplaceAllString(strings.ToLower(id), "-"), "-")
+}
+
+// Pending implements server.OCPPRegistrar
+func (r *ocppRegistrar) Pending() []ocpp.PendingChargePoint {
+	return ocpp.Instance().Pending()
+}
+
+// Registrations implements server.OCPPRegistrar
+func (r *ocppRegistrar) Registrations() []server.OCPPRegistration {
+	res, err := ocppRegistrations()
+	if err != nil {
+		log.ERROR.Println(err)
+	}
+
+	return lo.Map(res, func(reg ocppRegistration, _ int) server.OCPPRegistration {
+		return reg.OCPPRegistration
+	})
+}
+
+// Templates implements server.OCPPRegistrar
+func (r *ocppRegistrar) Templates() []string {
+	return lo.Map(r.templates, func(t ocppTemplate, _ int) string {
+		return t.Name
+	})
+}
+
+// template returns the named template, the empty name selects no defaults
+func (r *ocppRegistrar) template(name string) (ocppTemplate, error) {
+	if name == "" {
+		return ocppTemplate{}, nil
+	}
+
+	idx := slices.IndexFunc(r.templates, func(t ocppTemplate) bool {
+		return t.Name == name
+	})
+	if idx < 0 {
+		return ocppTemplate{}, fmt.Errorf("unknown template: %s", name)
+	}
+
+	return r.templates[idx], nil
+}
+
+// Accept implements server.OCPPRegistrar.
+// The chargepoint is added as runtime configured charger and its loadpoint is created by reloading the configuration.
+func (r *ocppRegistrar) Accept(reg server.OCPPRegistration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !ocpp.Instance().IsPending(reg.StationID) {
+		return fmt.Errorf("%s: %w", reg.StationID, server.ErrChargePointNotFound)
+	}
+
+	if reg.Title == "" {
+		return errors.New("missing title")
+	}
+
+	tmpl, err := r.template(reg.Template)
+	if err != nil {
+		return err
+	}
+
+	regs, err := ocppRegistrations()
+	if err != nil {
+		return err
+	}
+
+	name := ocppChargerName(reg.StationID)
+
+	other := maps.Clone(tmpl.Charger)
+	if other == nil {
+		other = make(map[string]interface{})
+	}
+	other["template"] = "ocpp"
+	other["stationid"] = reg.StationID
+
+	if err := r.cp.AddDevice(server.DeviceConfig{Class: string(templates.Charger), Name: name, Other: other}); err != nil {
+		return err
+	}
+
+	lpc := maps.Clone(tmpl.LoadPoint)
+	if lpc == nil {
+		lpc = make(map[string]interface{})
+	}
+	lpc["title"] = reg.Title
+	lpc["charger"] = name
+
+	regs = append(regs, ocppRegistration{OCPPRegistration: reg, LoadPoint: lpc})
+	if err := settings.SetJson(ocppRegistrationsSetting, regs); err != nil {
+		return err
+	}
+
+	if err := settings.Persist(); err != nil {
+		return err
+	}
+
+	log.INFO.Printf("ocpp: registered %s as loadpoint %s", reg.StationID, reg.Title)
+
+	if err := r.reload(); err != nil {
+		return fmt.Errorf("%w: %v", server.ErrRestartRequired, err)
+	}
+
+	return nil
+}
+
+// Remove implements server.OCPPRegistrar.
+// The loadpoint is removed by reloading the configuration before the charger is deleted.
+func (r *ocppRegistrar) Remove(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	regs, err := ocppRegistrations()
+	if err != nil {
+		return err
+	}
+
+	idx := slices.IndexFunc(regs, func(reg ocppRegistration) bool {
+		return reg.StationID == id
+	})
+	if idx < 0 {
+		return fmt.Errorf("%s: %w", id, server.ErrChargePointNotFound)
+	}
+
+	name, _ := regs[idx].LoadPoint["charger"].(string)
+
+	regs = slices.Delete(regs, idx, idx+1)
+	if err := settings.SetJson(ocppRegistrationsSetting, regs); err != nil {
+		return err
+	}
+
+	if err := settings.Persist(); err != nil {
+		return err
+	}
+
+	log.INFO.Printf("ocpp: unregistered %s", id)
+
+	// the charger remains configured until the loadpoint has been removed
+	if err := r.reload(); err != nil {
+		return fmt.Errorf("%w: %v", server.ErrRestartRequired, err)
+	}
+
+	if err := r.cp.DeleteDevice(string(templates.Charger), name); err != nil && !errors.Is(err, server.ErrDeviceNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// Reject implements server.OCPPRegistrar
+func (r *ocppRegistrar) Reject(id string) error {
+	if err := ocpp.Instance().Reject(id); err != nil {
+		return fmt.Errorf("%w: %v", server.ErrChargePointNotFound, err)
+	}
+	return nil
+}
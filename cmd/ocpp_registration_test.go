@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOCPPChargerName(t *testing.T) {
+	assert.Equal(t, "ocpp-evb-p12354", ocppChargerName("EVB-P12354"))
+	assert.Equal(t, "ocpp-cp-1", ocppChargerName(" CP_1/"))
+}
+
+func TestOCPPRegisteredLoadpoints(t *testing.T) {
+	lpc := map[string]interface{}{"title": "Garage", "charger": "ocpp-foo", "mode": "pv"}
+
+	require.NoError(t, settings.SetJson(ocppRegistrationsSetting, []ocppRegistration{
+		{OCPPRegistration: server.OCPPRegistration{StationID: "foo", Title: "Garage"}, LoadPoint: lpc},
+	}))
+	defer func() {
+		require.NoError(t, settings.SetJson(ocppRegistrationsSetting, []ocppRegistration{}))
+	}()
+
+	lpcs, err := loadpointConfigs()
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{lpc}, lpcs)
+
+	r := &ocppRegistrar{templates: []ocppTemplate{{Name: "default"}}}
+	assert.Equal(t, []string{"default"}, r.Templates())
+	assert.Equal(t, []server.OCPPRegistration{{StationID: "foo", Title: "Garage"}}, r.Registrations())
+
+	_, err = r.template("foo")
+	assert.Error(t, err)
+}
+
+func TestOCPPRegistrationRemove(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", filepath.Join(t.TempDir(), "evcc.db")))
+	require.NoError(t, settings.Init())
+
+	require.NoError(t, settings.SetJson(ocppRegistrationsSetting, []ocppRegistration{
+		{OCPPRegistration: server.OCPPRegistration{StationID: "foo", Title: "Garage"}, LoadPoint: map[string]interface{}{"charger": "ocpp-foo"}},
+	}))
+	defer func() {
+		require.NoError(t, settings.SetJson(ocppRegistrationsSetting, []ocppRegistration{}))
+	}()
+
+	cp := &ConfigProvider{
+		chargers: map[string]api.Charger{"ocpp-foo": mock.NewMockCharger(gomock.NewController(t))},
+		devices:  []server.DeviceConfig{{Class: "charger", Name: "ocpp-foo", Other: map[string]interface{}{"template": "ocpp", "stationid": "foo"}}},
+	}
+
+	// registered charger cannot be deleted
+	assert.ErrorIs(t, cp.DeleteDevice("charger", "ocpp-foo"), server.ErrDeviceInUse)
+
+	var reloaded bool
+	r := &ocppRegistrar{cp: cp, reload: func() error {
+		reloaded = true
+		return nil
+	}}
+
+	assert.ErrorIs(t, r.Remove("bar"), server.ErrChargePointNotFound)
+	assert.False(t, reloaded)
+
+	require.NoError(t, r.Remove("foo"))
+	assert.True(t, reloaded)
+	assert.Empty(t, r.Registrations())
+	assert.Empty(t, cp.Devices())
+}
@@ -0,0 +1,437 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/templates"
+	"golang.org/x/exp/slices"
+)
+
+// reloadable are the config sections applied by reloading, all other sections require a restart
+var reloadable = []string{"Log", "Levels", "Interval", "Meters", "Chargers", "Vehicles", "Tariffs", "Site", "LoadPoints"}
+
+// restartSections returns the changed config sections that cannot be reloaded
+func restartSections(old, new config) []string {
+	var res []string
+
+	ov, nv := reflect.ValueOf(old), reflect.ValueOf(new)
+	for i := 0; i < ov.NumField(); i++ {
+		name := ov.Type().Field(i).Name
+		if !slices.Contains(reloadable, name) && !reflect.DeepEqual(ov.Field(i).Interface(), nv.Field(i).Interface()) {
+			res = append(res, strings.ToLower(name))
+		}
+	}
+
+	return res
+}
+
+// findConfig returns the named device configuration
+func findConfig(conf []qualifiedConfig, name string) (qualifiedConfig, bool) {
+	idx := slices.IndexFunc(conf, func(cc qualifiedConfig) bool {
+		return cc.Name == name
+	})
+	if idx < 0 {
+		return qualifiedConfig{}, false
+	}
+	return conf[idx], true
+}
+
+// reconfigureDevices creates the devices of a class from the new configuration.
+// Devices with unchanged configuration and devices managed by the config api keep their instance.
+// The previous instances of removed and changed devices are not closed. On error, the devices created so far are returned.
+func reconfigureDevices[T any](
+	class string, devices map[string]T, managed []server.DeviceConfig,
+	oldConf, newConf []qualifiedConfig, create func(qualifiedConfig) (T, error),
+) (map[string]T, []string, error) {
+	res := make(map[string]T)
+	var changed []string
+
+	for _, cc := range oldConf {
+		if ncc, ok := findConfig(newConf, cc.Name); !ok || !reflect.DeepEqual(cc, ncc) {
+			changed = append(changed, cc.Name)
+		}
+	}
+
+	for _, dc := range managed {
+		if dc.Class == class {
+			res[dc.Name] = devices[dc.Name]
+		}
+	}
+
+	for id, cc := range newConf {
+		if cc.Name == "" {
+			return res, changed, fmt.Errorf("cannot create %s %s: missing name", humanize.Ordinal(id+1), class)
+		}
+
+		if _, exists := res[cc.Name]; exists {
+			return res, changed, fmt.Errorf("duplicate %s name: %s already defined and must be unique", class, cc.Name)
+		}
+
+		if occ, ok := findConfig(oldConf, cc.Name); ok && reflect.DeepEqual(occ, cc) {
+			res[cc.Name] = devices[cc.Name]
+			continue
+		}
+
+		dev, err := create(cc)
+		if err != nil {
+			return res, changed, fmt.Errorf("cannot create %s '%s': %w", class, cc.Name, err)
+		}
+
+		res[cc.Name] = dev
+		if !slices.Contains(changed, cc.Name) {
+			changed = append(changed, cc.Name)
+		}
+	}
+
+	return res, changed, nil
+}
+
+// deviceSet are the device instances and their usage
+type deviceSet struct {
+	meters        map[string]api.Meter
+	chargers      map[string]api.Charger
+	vehicles      map[string]api.Vehicle
+	visited, used map[string]bool
+}
+
+// close closes the named devices per class
+func (d deviceSet) close(names map[templates.Class][]string) {
+	for _, name := range names[templates.Meter] {
+		if dev, ok := d.meters[name]; ok {
+			closeDevice(name, dev)
+		}
+	}
+	for _, name := range names[templates.Charger] {
+		if dev, ok := d.chargers[name]; ok {
+			closeDevice(name, dev)
+		}
+	}
+	for _, name := range names[templates.Vehicle] {
+		if dev, ok := d.vehicles[name]; ok {
+			closeDevice(name, dev)
+		}
+	}
+}
+
+// reconfigure creates the changed devices and provides them to loadpoints and site. It returns the names of the
+// re-created or removed devices per class. The previous devices are kept until done commits or discards the new devices.
+func (cp *ConfigProvider) reconfigure(old, new config) (map[templates.Class][]string, func(commit bool), error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	prev := deviceSet{cp.meters, cp.chargers, cp.vehicles, cp.visited, cp.used}
+	next := prev
+	changed := make(map[templates.Class][]string)
+
+	var err error
+	next.meters, changed[templates.Meter], err = reconfigureDevices(string(templates.Meter), cp.meters, cp.devices, old.Meters, new.Meters, newMeter)
+	if err == nil {
+		next.chargers, changed[templates.Charger], err = reconfigureDevices(string(templates.Charger), cp.chargers, cp.devices, old.Chargers, new.Chargers, newCharger)
+	}
+	if err == nil {
+		next.vehicles, changed[templates.Vehicle], err = reconfigureDevices(string(templates.Vehicle), cp.vehicles, cp.devices, old.Vehicles, new.Vehicles, newVehicle)
+	}
+
+	if err != nil {
+		next.close(changed)
+		return nil, nil, err
+	}
+
+	cp.meters, cp.chargers, cp.vehicles = next.meters, next.chargers, next.vehicles
+
+	done := func(commit bool) {
+		cp.mu.Lock()
+		defer cp.mu.Unlock()
+
+		if commit {
+			prev.close(changed)
+			return
+		}
+
+		next.close(changed)
+		cp.meters, cp.chargers, cp.vehicles = prev.meters, prev.chargers, prev.vehicles
+		cp.visited, cp.used = prev.visited, prev.used
+	}
+
+	return changed, done, nil
+}
+
+// reloader runs the site and replaces it when the configuration is reloaded
+type reloader struct {
+	mu         sync.Mutex
+	conf       config
+	lpcs       []map[string]interface{}
+	site       *core.Site
+	running    atomic.Value // running *core.Site, read without lock while reloading
+	loadpoints []*core.LoadPoint
+	stopC      chan struct{}
+	doneC      chan struct{}
+
+	httpd     *server.HTTPd
+	cache     *util.Cache
+	valueChan chan<- util.Param
+	pushChan  chan<- push.Event
+	mqtt      *server.MQTT
+	influx    *server.Influx
+}
+
+// Site returns the running site. It does not block while reloading.
+func (r *reloader) Site() site.API {
+	if site, ok := r.running.Load().(*core.Site); ok {
+		return site
+	}
+	return nil
+}
+
+// run starts the site's control loop
+func (r *reloader) run(site *core.Site, interval time.Duration) {
+	r.site = site
+	r.running.Store(site)
+	r.stopC = make(chan struct{})
+	r.doneC = make(chan struct{})
+
+	go func(stopC, doneC chan struct{}) {
+		site.Run(stopC, interval)
+		close(doneC)
+	}(r.stopC, r.doneC)
+}
+
+// stop ends the site's control loop and waits for the running cycle to complete
+func (r *reloader) stop() {
+	if r.stopC != nil {
+		close(r.stopC)
+		<-r.doneC
+		r.stopC = nil
+	}
+}
+
+// Stop ends the site's control loop on shutdown
+func (r *reloader) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stop()
+}
+
+// loadpointReusable checks if the loadpoint and its devices are unchanged
+func loadpointReusable(old, new map[string]interface{}, changed map[templates.Class][]string) bool {
+	if !reflect.DeepEqual(old, new) || len(changed[templates.Vehicle]) > 0 {
+		return false
+	}
+
+	var cc struct {
+		Charger, Meter string
+		Other          map[string]interface{} `mapstructure:",remain"`
+	}
+
+	if err := util.DecodeOther(new, &cc); err != nil {
+		return false
+	}
+
+	return !slices.Contains(changed[templates.Charger], cc.Charger) && (cc.Meter == "" || !slices.Contains(changed[templates.Meter], cc.Meter))
+}
+
+// Reload re-reads the config file and applies the changed devices, loadpoints and site.
+// Unchanged devices and loadpoints keep their state. The previous configuration continues if the reload fails.
+func (r *reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.site == nil {
+		return errors.New("site not running")
+	}
+
+	if cfgFile == "" {
+		return errors.New("missing config file")
+	}
+
+	conf := defaultConfig()
+	if err := loadConfigFile(&conf); err != nil {
+		return err
+	}
+
+	// integrations bound to the site at startup
+	if conf.HEMS.Type != "" || len(conf.Tenancy.Tenants) > 0 || conf.VehicleProxy.Port != 0 {
+		return errors.New("restart required: reload not supported with hems, tenancy or vehicle proxy")
+	}
+
+	if changed := restartSections(r.conf, conf); len(changed) > 0 {
+		return fmt.Errorf("restart required for changed configuration: %s", strings.Join(changed, ", "))
+	}
+
+	lpcs, err := loadpointConfigs()
+	if err != nil {
+		return err
+	}
+
+	log.INFO.Println("reloading configuration")
+
+	// stop control loop before touching devices
+	r.stop()
+
+	changed, done, err := cp.reconfigure(r.conf, conf)
+	if err != nil {
+		log.ERROR.Printf("reload failed: %v", err)
+		r.run(r.site, r.conf.Interval)
+		return err
+	}
+
+	cp.TrackVisitors() // track duplicate usage
+
+	loadpoints, err := r.reconfigureLoadpoints(lpcs, changed)
+
+	var tariffs tariff.Tariffs
+	if err == nil {
+		tariffs, err = configureTariffs(conf.Tariffs)
+	}
+
+	var site *core.Site
+	var detached bool
+	if err == nil {
+		// creating the site detaches the loadpoints from the previous site
+		detached = true
+		site, err = configureSite(conf.Site, cp, loadpoints, cp.Vehicles(), tariffs)
+	}
+
+	if err != nil {
+		log.ERROR.Printf("reload failed: %v", err)
+
+		r.discardLoadpoints(loadpoints)
+		done(false)
+
+		if detached {
+			return r.restore(err)
+		}
+
+		r.run(r.site, r.conf.Interval)
+		return err
+	}
+
+	r.apply(conf, lpcs, site, loadpoints)
+	done(true)
+
+	log.INFO.Println("configuration reloaded")
+
+	return nil
+}
+
+// restore re-creates the previous site from the previous configuration after the loadpoints have been detached from it
+func (r *reloader) restore(err error) error {
+	cp.TrackVisitors() // track duplicate usage
+
+	_, rerr := r.reconfigureLoadpoints(r.lpcs, nil)
+
+	var tariffs tariff.Tariffs
+	if rerr == nil {
+		tariffs, rerr = configureTariffs(r.conf.Tariffs)
+	}
+
+	var site *core.Site
+	if rerr == nil {
+		site, rerr = configureSite(r.conf.Site, cp, r.loadpoints, cp.Vehicles(), tariffs)
+	}
+
+	if rerr != nil {
+		log.ERROR.Printf("restoring previous configuration failed, restart required: %v", rerr)
+		r.run(r.site, r.conf.Interval)
+		return err
+	}
+
+	r.apply(r.conf, r.lpcs, site, r.loadpoints)
+
+	return err
+}
+
+// discardLoadpoints closes the loadpoints created by a failed reload
+func (r *reloader) discardLoadpoints(loadpoints []*core.LoadPoint) {
+	for _, lp := range loadpoints {
+		if !slices.Contains(r.loadpoints, lp) {
+			lp.Close()
+		}
+	}
+}
+
+// apply replaces the running site
+func (r *reloader) apply(conf config, lpcs []map[string]interface{}, site *core.Site, loadpoints []*core.LoadPoint) {
+	// tear down removed loadpoints
+	for _, lp := range r.loadpoints {
+		if !slices.Contains(loadpoints, lp) {
+			lp.Close()
+		}
+	}
+
+	// drop values of removed loadpoints from ui state
+	if len(loadpoints) < len(r.loadpoints) {
+		r.cache.RemoveLoadPoints(len(loadpoints))
+	}
+
+	prev := r.site
+
+	r.conf = conf
+	r.lpcs = lpcs
+	r.loadpoints = loadpoints
+
+	site.DumpConfig()
+	site.Prepare(r.valueChan, r.pushChan)
+
+	// loadpoints have been prepared for the new site
+	prev.Close()
+
+	r.httpd.RegisterSiteHandlers(site, r.cache)
+
+	if r.mqtt != nil {
+		r.mqtt.Listen(site)
+	}
+
+	if r.influx != nil {
+		r.influx.SetLoadPoints(site.LoadPoints())
+	}
+
+	r.run(site, conf.Interval)
+}
+
+// reconfigureLoadpoints creates the loadpoints from the new configuration re-using unchanged loadpoints.
+// On error, the loadpoints created so far are returned.
+func (r *reloader) reconfigureLoadpoints(lpcs []map[string]interface{}, changed map[templates.Class][]string) ([]*core.LoadPoint, error) {
+	var loadpoints []*core.LoadPoint
+	for id, lpc := range lpcs {
+		if id < len(r.lpcs) && id < len(r.loadpoints) && loadpointReusable(r.lpcs[id], lpc, changed) {
+			// re-claim the charger and charge meter
+			if ref, ok := lpc["charger"].(string); ok && ref != "" {
+				if _, err := cp.Charger(ref); err != nil {
+					return loadpoints, err
+				}
+			}
+			if ref, ok := lpc["meter"].(string); ok && ref != "" {
+				if _, err := cp.Meter(ref); err != nil {
+					return loadpoints, err
+				}
+			}
+
+			loadpoints = append(loadpoints, r.loadpoints[id])
+			continue
+		}
+
+		lp, err := newLoadPoint(cp, id, lpc)
+		if err != nil {
+			return loadpoints, err
+		}
+
+		loadpoints = append(loadpoints, lp)
+	}
+
+	return loadpoints, nil
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestartSections(t *testing.T) {
+	old := defaultConfig()
+
+	new := defaultConfig()
+	new.Interval = time.Minute
+	new.Meters = []qualifiedConfig{{Name: "grid", Type: "template"}}
+	assert.Empty(t, restartSections(old, new))
+
+	new.Network.Port = 8080
+	new.Mqtt.Broker = "localhost:1883"
+	assert.Equal(t, []string{"network", "mqtt"}, restartSections(old, new))
+}
+
+func TestLoadpointReusable(t *testing.T) {
+	lpc := map[string]interface{}{"charger": "wallbox", "meter": "charge", "mode": "pv"}
+
+	assert.True(t, loadpointReusable(lpc, lpc, nil))
+	assert.True(t, loadpointReusable(lpc, lpc, map[templates.Class][]string{templates.Meter: {"grid"}}))
+	assert.False(t, loadpointReusable(lpc, map[string]interface{}{"charger": "wallbox", "mode": "now"}, nil))
+	assert.False(t, loadpointReusable(lpc, lpc, map[templates.Class][]string{templates.Charger: {"wallbox"}}))
+	assert.False(t, loadpointReusable(lpc, lpc, map[templates.Class][]string{templates.Meter: {"charge"}}))
+	assert.False(t, loadpointReusable(lpc, lpc, map[templates.Class][]string{templates.Vehicle: {"car"}}))
+}
+
+type closerDevice struct {
+	closed bool
+}
+
+func (d *closerDevice) Close() error {
+	d.closed = true
+	return nil
+}
+
+func TestReconfigureDevices(t *testing.T) {
+	oldConf := []qualifiedConfig{{Name: "a", Type: "foo"}, {Name: "b", Type: "foo"}}
+	devices := map[string]*closerDevice{"a": {}, "b": {}}
+
+	create := func(cc qualifiedConfig) (*closerDevice, error) {
+		if cc.Type == "invalid" {
+			return nil, errors.New("invalid")
+		}
+		return new(closerDevice), nil
+	}
+
+	// a unchanged, b changed, c added
+	newConf := []qualifiedConfig{{Name: "a", Type: "foo"}, {Name: "b", Type: "bar"}, {Name: "c", Type: "foo"}}
+
+	res, changed, err := reconfigureDevices("meter", devices, nil, oldConf, newConf, create)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, changed)
+	assert.Same(t, devices["a"], res["a"])
+	assert.NotSame(t, devices["b"], res["b"])
+
+	// previous instances are closed by the caller once committed
+	assert.False(t, devices["b"].closed)
+
+	// devices created before the error are returned for closing
+	newConf = append(newConf, qualifiedConfig{Name: "d", Type: "invalid"})
+
+	res, changed, err = reconfigureDevices("meter", devices, nil, oldConf, newConf, create)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"b", "c"}, changed)
+	assert.Len(t, res, 3)
+}
+
+func TestReloaderSiteWhileReloading(t *testing.T) {
+	r := new(reloader)
+	assert.Nil(t, r.Site())
+
+	site := new(core.Site)
+	r.running.Store(site)
+
+	// reload holds the lock
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	assert.Equal(t, site, r.Site())
+}
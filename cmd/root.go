@@ -3,8 +3,6 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"net/http"
-	_ "net/http/pprof" // pprof handler
 	"os"
 	"os/signal"
 	"strings"
@@ -15,8 +13,10 @@ import (
 	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/server/modbus"
 	"github.com/evcc-io/evcc/server/updater"
+	"github.com/evcc-io/evcc/server/vehicleproxy"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/pipe"
 	"github.com/evcc-io/evcc/util/sponsor"
@@ -57,6 +57,8 @@ func init() {
 
 	rootCmd.PersistentFlags().String(flagSqlite, "", flagSqliteDescription)
 
+	rootCmd.PersistentFlags().String(flagChaos, "", flagChaosDescription)
+
 	// config file options
 	rootCmd.PersistentFlags().StringP("log", "l", "info", "Log level (fatal, error, warn, info, debug, trace)")
 	bindP(rootCmd, "log")
@@ -64,7 +66,7 @@ func init() {
 	rootCmd.Flags().Bool("metrics", false, "Expose metrics")
 	bind(rootCmd, "metrics")
 
-	rootCmd.Flags().Bool("profile", false, "Expose pprof profiles")
+	rootCmd.Flags().Bool("profile", false, "Expose pprof profiles and runtime statistics, requires tenancy or network user and password")
 	bind(rootCmd, "profile")
 }
 
@@ -88,11 +90,31 @@ func initConfig() {
 
 	viper.AutomaticEnv() // read in environment variables that match
 
-	// print version
+	// log to stderr if stdout is reserved for dry-run or json output
+	if stdoutReserved() {
+		util.LogOutput = os.Stderr
+	}
+
 	util.LogLevel("info", nil)
 	log.INFO.Printf("evcc %s", server.FormattedVersion())
 }
 
+// stdoutReserved returns true if the executed command has the dry-run or json flag set
+func stdoutReserved() bool {
+	cmd, _, err := rootCmd.Find(os.Args[1:])
+	if err != nil {
+		return false
+	}
+
+	for _, flag := range []string{flagDryRun, flagJSON} {
+		if res, _ := cmd.Flags().GetBool(flag); res {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
@@ -131,16 +153,11 @@ func runRoot(cmd *cobra.Command, args []string) {
 
 	// metrics
 	if viper.GetBool("metrics") {
-		httpd.Router().Handle("/metrics", promhttp.Handler())
-	}
-
-	// pprof
-	if viper.GetBool("profile") {
-		httpd.Router().PathPrefix("/debug/").Handler(http.DefaultServeMux)
+		httpd.Router().Handle("/metrics", httpd.AdminHandler(promhttp.Handler()))
 	}
 
 	// publish to UI
-	go socketHub.Run(tee.Attach(), cache)
+	go socketHub.Run(tee.Attach())
 
 	// setup values channel
 	valueChan := make(chan util.Param)
@@ -170,25 +187,59 @@ func runRoot(cmd *cobra.Command, args []string) {
 
 	// setup site and loadpoints
 	var site *core.Site
+	var loadPoints []*core.LoadPoint
 	if err == nil {
 		cp.TrackVisitors() // track duplicate usage
-		site, err = configureSiteAndLoadpoints(conf)
+		site, loadPoints, err = configureSiteAndLoadpoints(conf)
+	}
+
+	// setup vehicle proxy
+	if err == nil && conf.VehicleProxy.Port != 0 {
+		if conf.VehicleProxy.Cache == 0 {
+			conf.VehicleProxy.Cache = time.Minute
+		}
+		err = vehicleproxy.StartProxy(conf.VehicleProxy.Port, cp.vehicles, conf.VehicleProxy.Token, conf.VehicleProxy.Cache)
 	}
 
 	// setup database
+	var influx *server.Influx
 	if err == nil && conf.Influx.URL != "" {
-		configureInflux(conf.Influx, site.LoadPoints(), tee.Attach())
+		influx, err = configureInflux(conf.Influx, site.LoadPoints(), tee.Attach())
 	}
 
 	// setup mqtt publisher
+	var publisher *server.MQTT
 	if err == nil && conf.Mqtt.Broker != "" {
-		publisher := server.NewMQTT(strings.Trim(conf.Mqtt.Topic, "/"))
-		go publisher.Run(site, pipe.NewDropper(ignoreMqtt...).Pipe(tee.Attach()))
+		if publisher, err = server.NewMQTT(strings.Trim(conf.Mqtt.Topic, "/"), conf.Mqtt.Permissions); err == nil && conf.Mqtt.Queue > 0 {
+			var outbox *server.Outbox
+			if outbox, err = server.NewOutbox("mqtt", conf.Mqtt.Queue); err == nil {
+				publisher.SetQueue(outbox)
+			}
+		}
+
+		if err == nil {
+			go publisher.Run(site, pipe.NewDropper(ignoreMqtt...).Pipe(tee.Attach()))
+		} else {
+			err = fmt.Errorf("failed configuring mqtt: %w", err)
+		}
 	}
 
 	// announce on mDNS
 	if err == nil && strings.HasSuffix(conf.Network.Host, ".local") {
-		err = configureMDNS(conf.Network)
+		err = configureMDNS(conf.Network, site.Title)
+	}
+
+	// update dynamic dns
+	if err == nil && conf.DynDNS.Type != "" {
+		err = configureDynDNS(conf.DynDNS, httpd)
+	}
+
+	// connect remote access relay
+	if err == nil && conf.Relay.Broker != "" {
+		err = configureRelay(conf.Relay, httpd, server.Credentials{
+			User:     conf.Network.User,
+			Password: conf.Network.Password,
+		})
 	}
 
 	// start HEMS server
@@ -196,6 +247,21 @@ func runRoot(cmd *cobra.Command, args []string) {
 		err = configureHEMS(conf.HEMS, site, httpd)
 	}
 
+	// restrict api access to tenants
+	if err == nil && len(conf.Tenancy.Tenants) > 0 {
+		err = configureTenancy(conf.Tenancy, site, httpd)
+	}
+
+	// pprof and runtime statistics, also available if startup failed
+	if viper.GetBool("profile") {
+		if perr := httpd.RegisterProfileHandlers(server.Credentials{
+			User:     conf.Network.User,
+			Password: conf.Network.Password,
+		}); perr != nil {
+			log.ERROR.Printf("profile: %v", perr)
+		}
+	}
+
 	// setup messaging
 	var pushChan chan push.Event
 	if err == nil {
@@ -231,10 +297,32 @@ func runRoot(cmd *cobra.Command, args []string) {
 		os.Exit(0)
 	}()
 
+	rl := &reloader{
+		httpd:     httpd,
+		cache:     cache,
+		valueChan: valueChan,
+		pushChan:  pushChan,
+		mqtt:      publisher,
+		influx:    influx,
+	}
+
+	// setup control loop watchdog
+	var watchdog *server.Watchdog
+	if err == nil {
+		if watchdog, err = server.NewWatchdog(conf.Watchdog, rl.Site); err != nil {
+			err = fmt.Errorf("failed configuring watchdog: %w", err)
+		}
+	}
+
 	// show main ui
 	if err == nil {
 		httpd.RegisterSiteHandlers(site, cache)
 
+		// runtime device configuration requires persistence
+		if db.Instance != nil {
+			httpd.RegisterConfigHandlers(cp)
+		}
+
 		// set channels
 		site.DumpConfig()
 		site.Prepare(valueChan, pushChan)
@@ -252,10 +340,40 @@ func runRoot(cmd *cobra.Command, args []string) {
 		}
 
 		// allow web access for vehicles
-		cp.webControl(conf.Network, httpd.Router(), valueChan)
+		cp.webControl(conf.Network, httpd, valueChan)
+
+		// run site and apply configuration changes on SIGHUP or api request
+		rl.conf = conf
+		rl.lpcs, _ = loadpointConfigs()
+		rl.loadpoints = loadPoints
+		rl.run(site, conf.Interval)
+		go watchdog.Run()
+
+		httpd.RegisterReloadHandler(rl.Reload)
+
+		// plug and play onboarding of ocpp chargepoints
+		if conf.OCPP.Registration {
+			httpd.RegisterOCPPHandlers(&ocppRegistrar{
+				cp:        cp,
+				templates: conf.OCPP.Templates,
+				reload:    rl.Reload,
+			})
+		}
 
 		go func() {
-			site.Run(stopC, conf.Interval)
+			<-stopC
+			rl.Stop()
+		}()
+
+		go func() {
+			signalC := make(chan os.Signal, 1)
+			signal.Notify(signalC, syscall.SIGHUP)
+
+			for range signalC {
+				if err := rl.Reload(); err != nil {
+					log.ERROR.Printf("reload: %v", err)
+				}
+			}
 		}()
 	} else {
 		httpd.RegisterShutdownHandler(func() {
@@ -275,7 +393,10 @@ func runRoot(cmd *cobra.Command, args []string) {
 	}
 
 	// uds health check listener
-	go server.HealthListener(site)
+	go server.HealthListener(rl.Site)
 
-	log.FATAL.Println(httpd.ListenAndServe())
+	log.FATAL.Println(httpd.ListenAndServeAll(conf.Network.ListenConfig(), server.Credentials{
+		User:     conf.Network.User,
+		Password: conf.Network.Password,
+	}, conf.Network.BasePath, conf.Network.TrustedProxies))
 }
@@ -1,34 +1,40 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/rand"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	paho "github.com/eclipse/paho.mqtt.golang"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger/ocpp"
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/dyndns"
 	"github.com/evcc-io/evcc/hems"
 	"github.com/evcc-io/evcc/provider/javascript"
 	"github.com/evcc-io/evcc/provider/mqtt"
 	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/relay"
 	"github.com/evcc-io/evcc/server"
 	"github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/tariff"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/chaos"
 	"github.com/evcc-io/evcc/util/locale"
 	"github.com/evcc-io/evcc/util/machine"
 	"github.com/evcc-io/evcc/util/pipe"
 	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/secrets"
 	"github.com/evcc-io/evcc/util/sponsor"
 	"github.com/libp2p/zeroconf/v2"
-	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/text/currency"
@@ -49,6 +55,15 @@ func loadConfigFile(conf *config) error {
 
 	log.INFO.Println("using config file:", cfgFile)
 
+	// the machine id derives the secrets key, hence the plant must be applied before resolving secrets
+	if err == nil {
+		err = configurePlant()
+	}
+
+	if err == nil {
+		err = resolveSecrets()
+	}
+
 	if err == nil {
 		if err = viper.UnmarshalExact(&conf); err != nil {
 			err = fmt.Errorf("failed parsing config file: %w", err)
@@ -62,15 +77,42 @@ func loadConfigFile(conf *config) error {
 	return err
 }
 
+// configurePlant sets the machine id to the configured plant
+func configurePlant() error {
+	if plant := viper.GetString("plant"); plant != "" {
+		return machine.CustomID(plant)
+	}
+	return nil
+}
+
+// resolveSecrets re-reads the config file with `!secret` references replaced by the secrets' values
+func resolveSecrets() error {
+	b, err := os.ReadFile(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := secrets.Resolve(b, secrets.Filename(cfgFile))
+	if err != nil {
+		return fmt.Errorf("failed resolving secrets: %w", err)
+	}
+
+	if bytes.Equal(b, resolved) {
+		return nil
+	}
+
+	return viper.ReadConfig(bytes.NewReader(resolved))
+}
+
 func configureEnvironment(cmd *cobra.Command, conf config) (err error) {
 	// full http request log
 	if cmd.Flags().Lookup(flagHeaders).Changed {
 		request.LogHeaders = true
 	}
 
-	// setup machine id
-	if conf.Plant != "" {
-		err = machine.CustomID(conf.Plant)
+	// fault injection
+	if flag := cmd.Flags().Lookup(flagChaos); flag.Changed {
+		err = chaos.Load(flag.Value.String())
 	}
 
 	// setup sponsorship
@@ -83,6 +125,15 @@ func configureEnvironment(cmd *cobra.Command, conf config) (err error) {
 		err = locale.Init()
 	}
 
+	// load community translations
+	if err == nil && conf.I18n.Path != "" {
+		err = locale.LoadDir(conf.I18n.Path)
+	}
+
+	if err == nil && conf.I18n.Language != "" {
+		err = locale.SetLanguage(conf.I18n.Language)
+	}
+
 	// setup persistence
 	if err == nil && conf.Database.Dsn != "" {
 		if flag := cmd.Flags().Lookup(flagSqlite); flag.Changed {
@@ -92,6 +143,11 @@ func configureEnvironment(cmd *cobra.Command, conf config) (err error) {
 		err = configureDatabase(conf.Database)
 	}
 
+	// start ocpp server for unknown chargepoints
+	if err == nil && conf.OCPP.Registration {
+		err = configureOCPP()
+	}
+
 	// setup mqtt client listener
 	if err == nil && conf.Mqtt.Broker != "" {
 		err = configureMQTT(conf.Mqtt)
@@ -110,6 +166,17 @@ func configureEnvironment(cmd *cobra.Command, conf config) (err error) {
 	return
 }
 
+// configureOCPP starts the ocpp server accepting unknown chargepoints for registration
+func configureOCPP() error {
+	if db.Instance == nil {
+		return errors.New("ocpp registration requires database")
+	}
+
+	ocpp.Instance().EnableRegistration()
+
+	return nil
+}
+
 // configureDatabase configures session database
 func configureDatabase(conf dbConfig) error {
 	err := db.NewInstance(conf.Type, conf.Dsn)
@@ -126,7 +193,7 @@ func configureDatabase(conf dbConfig) error {
 }
 
 // configureInflux configures influx database
-func configureInflux(conf server.InfluxConfig, loadPoints []loadpoint.API, in <-chan util.Param) {
+func configureInflux(conf server.InfluxConfig, loadPoints []loadpoint.API, in <-chan util.Param) (*server.Influx, error) {
 	influx := server.NewInfluxClient(
 		conf.URL,
 		conf.Token,
@@ -136,11 +203,22 @@ func configureInflux(conf server.InfluxConfig, loadPoints []loadpoint.API, in <-
 		conf.Database,
 	)
 
+	// buffer points during outages
+	if conf.Queue > 0 {
+		outbox, err := server.NewOutbox("influx", conf.Queue)
+		if err != nil {
+			return nil, fmt.Errorf("failed configuring influx: %w", err)
+		}
+		influx.SetQueue(outbox)
+	}
+
 	// eliminate duplicate values
 	dedupe := pipe.NewDeduplicator(30*time.Minute, "vehicleCapacity", "vehicleSoC", "vehicleRange", "vehicleOdometer", "chargedEnergy", "chargeRemainingEnergy")
 	in = dedupe.Pipe(in)
 
 	go influx.Run(loadPoints, in)
+
+	return influx, nil
 }
 
 // setup mqtt
@@ -179,8 +257,76 @@ func configureHEMS(conf typedConfig, site *core.Site, httpd *server.HTTPd) error
 	return nil
 }
 
+// setup multi-tenant api access
+func configureTenancy(conf server.TenancyConfig, site *core.Site, httpd *server.HTTPd) error {
+	tenancy, err := server.NewTenancy(conf, site)
+	if err != nil {
+		return fmt.Errorf("failed configuring tenancy: %w", err)
+	}
+
+	httpd.SetTenancy(tenancy)
+
+	return nil
+}
+
+// setup dynamic dns
+func configureDynDNS(conf dynDNSConfig, httpd *server.HTTPd) error {
+	updater, err := dyndns.NewUpdaterFromConfig(conf.Type, conf.Other)
+	if err != nil {
+		return fmt.Errorf("failed configuring dyndns: %w", err)
+	}
+
+	// certificate for tls listeners
+	if conf.ACME != nil {
+		domain, _ := conf.Other["domain"].(string)
+
+		certs, err := dyndns.NewCertificates(updater, domain, *conf.ACME)
+		if err != nil {
+			return fmt.Errorf("failed configuring dyndns: %w", err)
+		}
+
+		httpd.SetCertificate(certs.GetCertificate)
+		go certs.Run()
+	}
+
+	interval := conf.Interval
+	if interval == 0 {
+		interval = 5 * time.Minute
+	}
+
+	go dyndns.Run(updater, interval)
+
+	return nil
+}
+
+// setup remote access relay
+func configureRelay(conf relay.Config, httpd *server.HTTPd, cred server.Credentials) error {
+	id, err := machine.ProtectedID("evcc-relay")
+	if err != nil {
+		return fmt.Errorf("failed configuring relay: %w", err)
+	}
+
+	secret, err := machine.ProtectedID("evcc-relay-key")
+	if err != nil {
+		return fmt.Errorf("failed configuring relay: %w", err)
+	}
+
+	// relayed requests require the same authentication as local requests
+	r, err := relay.New(conf, id, secret, httpd.AuthenticatedHandler(cred))
+	if err != nil {
+		return fmt.Errorf("failed configuring relay: %w", err)
+	}
+
+	// pairing tokens are only available to admins
+	httpd.RegisterRelayHandlers(r)
+
+	go r.Run()
+
+	return nil
+}
+
 // setup MDNS
-func configureMDNS(conf networkConfig) error {
+func configureMDNS(conf networkConfig, title string) error {
 	host := strings.TrimSuffix(conf.Host, ".local")
 
 	zc, err := zeroconf.RegisterProxy("EV Charge Controller", "_http._tcp", "local.", conf.Port, host, nil, []string{}, nil)
@@ -190,6 +336,28 @@ func configureMDNS(conf networkConfig) error {
 
 	shutdown.Register(zc.Shutdown)
 
+	// announce api with instance metadata for app discovery
+	if title == "" {
+		title = "evcc"
+	}
+
+	id, _ := machine.ProtectedID("evcc-mdns")
+
+	txt := []string{
+		"version=" + server.Version,
+		"schema=" + conf.Schema,
+		"api=/api",
+		"ws=/ws",
+		"id=" + id,
+	}
+
+	zc, err = zeroconf.RegisterProxy(title, "_evcc._tcp", "local.", conf.Port, host, nil, txt, nil)
+	if err != nil {
+		return fmt.Errorf("mDNS announcement: %w", err)
+	}
+
+	shutdown.Register(zc.Shutdown)
+
 	return nil
 }
 
@@ -216,11 +384,21 @@ func configureMessengers(conf messagingConfig, cache *util.Cache) (chan push.Eve
 	}
 
 	for _, service := range conf.Services {
-		impl, err := push.NewMessengerFromConfig(service.Type, service.Other)
+		// optional event filter, e.g. for restricting costly sms to critical events
+		var cc struct {
+			Events []string
+			Other  map[string]interface{} `mapstructure:",remain"`
+		}
+
+		if err := util.DecodeOther(service.Other, &cc); err != nil {
+			return messageChan, fmt.Errorf("failed configuring push service %s: %w", service.Type, err)
+		}
+
+		impl, err := push.NewMessengerFromConfig(service.Type, cc.Other)
 		if err != nil {
 			return messageChan, fmt.Errorf("failed configuring push service %s: %w", service.Type, err)
 		}
-		messageHub.Add(impl)
+		messageHub.Add(impl, cc.Events...)
 	}
 
 	go messageHub.Run(messageChan)
@@ -254,9 +432,8 @@ func configureTariffs(conf tariffConfig) (tariff.Tariffs, error) {
 	return *tariffs, err
 }
 
-func configureSiteAndLoadpoints(conf config) (site *core.Site, err error) {
+func configureSiteAndLoadpoints(conf config) (site *core.Site, loadPoints []*core.LoadPoint, err error) {
 	if err = cp.configure(conf); err == nil {
-		var loadPoints []*core.LoadPoint
 		loadPoints, err = configureLoadPoints(conf, cp)
 
 		var tariffs tariff.Tariffs
@@ -265,16 +442,11 @@ func configureSiteAndLoadpoints(conf config) (site *core.Site, err error) {
 		}
 
 		if err == nil {
-			// list of vehicles
-			vehicles := lo.MapToSlice(cp.vehicles, func(_ string, v api.Vehicle) api.Vehicle {
-				return v
-			})
-
-			site, err = configureSite(conf.Site, cp, loadPoints, vehicles, tariffs)
+			site, err = configureSite(conf.Site, cp, loadPoints, cp.Vehicles(), tariffs)
 		}
 	}
 
-	return site, err
+	return site, loadPoints, err
 }
 
 func configureSite(conf map[string]interface{}, cp *ConfigProvider, loadPoints []*core.LoadPoint, vehicles []api.Vehicle, tariffs tariff.Tariffs) (*core.Site, error) {
@@ -286,22 +458,46 @@ func configureSite(conf map[string]interface{}, cp *ConfigProvider, loadPoints [
 	return site, nil
 }
 
-func configureLoadPoints(conf config, cp *ConfigProvider) (loadPoints []*core.LoadPoint, err error) {
-	lpInterfaces, ok := viper.AllSettings()["loadpoints"].([]interface{})
-	if !ok || len(lpInterfaces) == 0 {
-		return nil, errors.New("missing loadpoints")
-	}
+// loadpointConfigs returns the loadpoint configurations
+func loadpointConfigs() ([]map[string]interface{}, error) {
+	lpInterfaces, _ := viper.AllSettings()["loadpoints"].([]interface{})
 
-	for id, lpcI := range lpInterfaces {
+	var res []map[string]interface{}
+	for _, lpcI := range lpInterfaces {
 		var lpc map[string]interface{}
 		if err := util.DecodeOther(lpcI, &lpc); err != nil {
 			return nil, fmt.Errorf("failed decoding loadpoint configuration: %w", err)
 		}
+		res = append(res, lpc)
+	}
+
+	// loadpoints of chargepoints accepted by the ocpp api
+	regs, err := ocppRegistrations()
+	if err != nil {
+		return nil, err
+	}
 
-		log := util.NewLogger("lp-" + strconv.Itoa(id+1))
-		lp, err := core.NewLoadPointFromConfig(log, cp, lpc)
+	for _, reg := range regs {
+		res = append(res, reg.LoadPoint)
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("missing loadpoints")
+	}
+
+	return res, nil
+}
+
+func configureLoadPoints(conf config, cp *ConfigProvider) (loadPoints []*core.LoadPoint, err error) {
+	lpcs, err := loadpointConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	for id, lpc := range lpcs {
+		lp, err := newLoadPoint(cp, id, lpc)
 		if err != nil {
-			return nil, fmt.Errorf("failed configuring loadpoint: %w", err)
+			return nil, err
 		}
 
 		loadPoints = append(loadPoints, lp)
@@ -309,3 +505,15 @@ func configureLoadPoints(conf config, cp *ConfigProvider) (loadPoints []*core.Lo
 
 	return loadPoints, nil
 }
+
+// newLoadPoint creates the loadpoint with given index
+func newLoadPoint(cp *ConfigProvider, id int, lpc map[string]interface{}) (*core.LoadPoint, error) {
+	log := util.NewLogger("lp-" + strconv.Itoa(id+1))
+
+	lp, err := core.NewLoadPointFromConfig(log, cp, lpc)
+	if err != nil {
+		return nil, fmt.Errorf("failed configuring loadpoint: %w", err)
+	}
+
+	return lp, nil
+}
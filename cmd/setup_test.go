@@ -1,13 +1,19 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/machine"
+	"github.com/evcc-io/evcc/util/secrets"
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const sample = `
@@ -35,3 +41,34 @@ func TestYamlOff(t *testing.T) {
 		t.Errorf("expected `off`, got %s", lp.Mode)
 	}
 }
+
+func TestLoadConfigPlantSecret(t *testing.T) {
+	defer viper.Reset()
+
+	plant := strings.Repeat("ab", 32)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "evcc.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("plant: "+plant+"\nsponsortoken: !secret token\n"), 0o600))
+
+	viper.SetConfigFile(file)
+
+	// plant is applied before resolving, the missing secret must not break the machine id
+	conf := defaultConfig()
+	assert.ErrorContains(t, loadConfigFile(&conf), "secret not found")
+
+	id, err := machine.ID()
+	require.NoError(t, err)
+	assert.Equal(t, plant, id)
+
+	// secrets are keyed to the plant
+	s, err := secrets.Open(secrets.Filename(file))
+	require.NoError(t, err)
+	require.NoError(t, s.Set("token", "foo"))
+	require.NoError(t, s.Save())
+
+	conf = defaultConfig()
+	require.NoError(t, loadConfigFile(&conf))
+	assert.Equal(t, "foo", conf.SponsorToken)
+	assert.Equal(t, plant, conf.Plant)
+}
@@ -5,8 +5,9 @@ import (
 )
 
 var (
-	mu       sync.Mutex
-	handlers = make([]func(), 0)
+	mu        sync.Mutex
+	handlers  = make([]func(), 0)
+	finalizer = make([]func(), 0)
 )
 
 // Register registers a function for executing on application shutdown
@@ -16,6 +17,14 @@ func Register(cb func()) {
 	mu.Unlock()
 }
 
+// Finalize registers a function for executing after all shutdown functions have completed,
+// e.g. for releasing resources the shutdown functions still depend on
+func Finalize(cb func()) {
+	mu.Lock()
+	finalizer = append(finalizer, cb)
+	mu.Unlock()
+}
+
 // Cleanup executes the registered shutdown functions when the stop channel closes
 func Cleanup(doneC chan struct{}) {
 	wg := new(sync.WaitGroup)
@@ -29,8 +38,14 @@ func Cleanup(doneC chan struct{}) {
 			wg.Done()
 		}(cb)
 	}
+	final := finalizer
 	mu.Unlock()
 
 	wg.Wait()
+
+	for _, cb := range final {
+		cb()
+	}
+
 	close(doneC)
 }
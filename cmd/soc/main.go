@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -23,11 +24,6 @@ Usage:
 `)
 }
 
-// matchesError replaces errors.Is for errors returned from GRPC
-func matchesError(err, match error) bool {
-	return strings.Contains(err.Error(), match.Error())
-}
-
 func main() {
 	if len(os.Args) < 3 {
 		usage()
@@ -85,7 +81,7 @@ func main() {
 		var err error
 
 		start := time.Now()
-		for err = api.ErrMustRetry; err != nil && matchesError(err, api.ErrMustRetry); {
+		for err = api.ErrMustRetry; err != nil && errors.Is(err, api.ErrMustRetry); {
 			if soc, err = v.SoC(); err != nil {
 				if time.Since(start) > time.Minute {
 					err = os.ErrDeadlineExceeded
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/samber/lo"
@@ -21,6 +23,16 @@ func init() {
 	rootCmd.AddCommand(tokenCmd)
 }
 
+// promptOutput is the output of interactive prompts
+type promptOutput struct {
+	io.Writer
+}
+
+// Close implements io.Closer as prompts must not close their output
+func (promptOutput) Close() error {
+	return nil
+}
+
 func runToken(cmd *cobra.Command, args []string) {
 	// load config
 	if err := loadConfigFile(&conf); err != nil {
@@ -52,9 +64,11 @@ func runToken(cmd *cobra.Command, args []string) {
 
 	switch strings.ToLower(vehicleConf.Type) {
 	case "tesla":
-		token, err = teslaToken()
+		token, err = teslaToken(os.Stdout)
 	case "tronity":
 		token, err = tronityToken(conf, vehicleConf)
+	case "mercedes":
+		token, err = mercedesToken(os.Stdout, vehicleConf.Other)
 	default:
 		log.FATAL.Fatalf("vehicle type '%s' does not support token authentication", vehicleConf.Type)
 	}
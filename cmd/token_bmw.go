@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/vehicle/bmw"
+)
+
+// bmwLogin verifies the account credentials by logging in and refreshing the token
+func bmwLogin(brand string) func(map[string]interface{}) error {
+	return func(other map[string]interface{}) error {
+		var cc struct {
+			User, Password string
+			Other          map[string]interface{} `mapstructure:",remain"`
+		}
+
+		if err := util.DecodeOther(other, &cc); err != nil {
+			return err
+		}
+
+		log := util.NewLogger(brand).Redact(cc.User, cc.Password)
+		identity := bmw.NewIdentity(log)
+
+		if err := identity.Login(cc.User, cc.Password); err != nil {
+			return err
+		}
+
+		token, err := identity.Token()
+		if err == nil {
+			_, err = identity.RefreshToken(token)
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/vehicle/mercedes"
+	"github.com/manifoldco/promptui"
+	"github.com/skratchdot/open-golang/open"
+	"golang.org/x/oauth2"
+)
+
+// mercedesConfig returns the oauth2 configuration of the vehicle's client credentials
+func mercedesConfig(other map[string]interface{}) (*oauth2.Config, error) {
+	var cc struct {
+		ClientID, ClientSecret string
+		Other                  map[string]interface{} `mapstructure:",remain"`
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.ClientID == "" || cc.ClientSecret == "" {
+		return nil, errors.New("missing client id and/or secret")
+	}
+
+	return mercedes.OAuth2Config(cc.ClientID, cc.ClientSecret)
+}
+
+// mercedesToken runs the authorization code flow. The redirect target is not required to be reachable,
+// the address the browser is redirected to is pasted instead.
+func mercedesToken(w io.Writer, other map[string]interface{}) (*oauth2.Token, error) {
+	oc, err := mercedesConfig(other)
+	if err != nil {
+		return nil, err
+	}
+
+	if oc.RedirectURL, err = (&promptui.Prompt{
+		Label:   "Redirect URI registered for the client",
+		Pointer: promptui.PipeCursor,
+		Stdout:  promptOutput{w},
+	}).Run(); err != nil {
+		return nil, err
+	}
+
+	state := state()
+	uri := oc.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.SetAuthURLParam("prompt", "login consent"))
+
+	fmt.Fprintln(w, "Log in using the following address and paste the address you are redirected to:")
+	fmt.Fprintln(w, uri)
+	_ = open.Start(uri)
+
+	redirected, err := (&promptui.Prompt{
+		Label:   "Redirected address",
+		Pointer: promptui.PipeCursor,
+		Stdout:  promptOutput{w},
+	}).Run()
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(redirected)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Query().Get("state") != state {
+		return nil, errors.New("invalid state")
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, request.NewClient(log))
+	return oc.Exchange(ctx, u.Query().Get("code"))
+}
+
+// mercedesTokenRefresh verifies the token can be refreshed and returns the refreshed token
+func mercedesTokenRefresh(other map[string]interface{}, token *oauth2.Token) (*oauth2.Token, error) {
+	oc, err := mercedesConfig(other)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, request.NewClient(log))
+	return oc.TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+}
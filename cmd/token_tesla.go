@@ -16,10 +16,11 @@ import (
 )
 
 // copied from https://github.com/bogosj/tesla
-func getUsernameAndPassword() (string, string, error) {
+func getUsernameAndPassword(w io.Writer) (string, string, error) {
 	user, err := (&promptui.Prompt{
 		Label:   "Username",
 		Pointer: promptui.PipeCursor,
+		Stdout:  promptOutput{w},
 		Validate: func(s string) error {
 			if len(s) == 0 {
 				return errors.New("len(s) == 0")
@@ -35,6 +36,7 @@ func getUsernameAndPassword() (string, string, error) {
 		Label:   "Password",
 		Mask:    '*',
 		Pointer: promptui.PipeCursor,
+		Stdout:  promptOutput{w},
 		Validate: func(s string) error {
 			if len(s) == 0 {
 				return errors.New("len(s) == 0")
@@ -49,7 +51,7 @@ func getUsernameAndPassword() (string, string, error) {
 	return user, password, nil
 }
 
-func codePrompt(ctx context.Context, devices []tesla.Device) (tesla.Device, string, error) {
+func codePrompt(ctx context.Context, w io.Writer, devices []tesla.Device) (tesla.Device, string, error) {
 	var i int
 	if len(devices) > 1 {
 		var err error
@@ -57,6 +59,7 @@ func codePrompt(ctx context.Context, devices []tesla.Device) (tesla.Device, stri
 			Label:   "Device",
 			Items:   devices,
 			Pointer: promptui.PipeCursor,
+			Stdout:  promptOutput{w},
 		}).Run()
 		if err != nil {
 			return tesla.Device{}, "", fmt.Errorf("select device: %w", err)
@@ -66,6 +69,7 @@ func codePrompt(ctx context.Context, devices []tesla.Device) (tesla.Device, stri
 	code, err := (&promptui.Prompt{
 		Label:   "Passcode",
 		Pointer: promptui.PipeCursor,
+		Stdout:  promptOutput{w},
 		Validate: func(s string) error {
 			if len(s) != 6 {
 				return errors.New("len(s) != 6")
@@ -80,7 +84,7 @@ func codePrompt(ctx context.Context, devices []tesla.Device) (tesla.Device, stri
 	return devices[i], strings.TrimSpace(code), nil
 }
 
-func captchaPrompt(ctx context.Context, svg io.Reader) (string, error) {
+func captchaPrompt(ctx context.Context, w io.Writer, svg io.Reader) (string, error) {
 	tmpFile, err := os.CreateTemp(os.TempDir(), "evcc-*.svg")
 	if err != nil {
 		return "", fmt.Errorf("cannot create temp file: %w", err)
@@ -96,11 +100,12 @@ func captchaPrompt(ctx context.Context, svg io.Reader) (string, error) {
 		return "", fmt.Errorf("cannot open captcha for display: %w", err)
 	}
 
-	fmt.Println("Captcha is now being opened in default application for svg files.")
+	fmt.Fprintln(w, "Captcha is now being opened in default application for svg files.")
 
 	captcha, err := (&promptui.Prompt{
 		Label:   "Captcha",
 		Pointer: promptui.PipeCursor,
+		Stdout:  promptOutput{w},
 		Validate: func(s string) error {
 			if len(s) < 4 {
 				return errors.New("len(s) < 4")
@@ -112,8 +117,8 @@ func captchaPrompt(ctx context.Context, svg io.Reader) (string, error) {
 	return strings.TrimSpace(captcha), err
 }
 
-func teslaToken() (*oauth2.Token, error) {
-	username, password, err := getUsernameAndPassword()
+func teslaToken(w io.Writer) (*oauth2.Token, error) {
+	username, password, err := getUsernameAndPassword(w)
 	if err != nil {
 		return nil, err
 	}
@@ -121,8 +126,12 @@ func teslaToken() (*oauth2.Token, error) {
 	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, request.NewClient(log))
 	client, err := tesla.NewClient(
 		ctx,
-		tesla.WithMFAHandler(codePrompt),
-		tesla.WithCaptchaHandler(captchaPrompt),
+		tesla.WithMFAHandler(func(ctx context.Context, devices []tesla.Device) (tesla.Device, string, error) {
+			return codePrompt(ctx, w, devices)
+		}),
+		tesla.WithCaptchaHandler(func(ctx context.Context, svg io.Reader) (string, error) {
+			return captchaPrompt(ctx, w, svg)
+		}),
 		tesla.WithCredentials(username, password),
 	)
 	if err != nil {
@@ -136,3 +145,14 @@ func teslaToken() (*oauth2.Token, error) {
 
 	return token, nil
 }
+
+// teslaLogin creates the tokens for the configure wizard
+func teslaLogin(w io.Writer, _ map[string]interface{}) (*oauth2.Token, error) {
+	return teslaToken(w)
+}
+
+// teslaTokenRefresh verifies the token can be refreshed and returns the refreshed token
+func teslaTokenRefresh(_ map[string]interface{}, token *oauth2.Token) (*oauth2.Token, error) {
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, request.NewClient(log))
+	return tesla.OAuth2Config.TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+}
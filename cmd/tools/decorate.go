@@ -24,6 +24,36 @@ type dynamicType struct {
 
 type typeStruct struct {
 	Type, ShortType, Signature, Function, VarName string
+	Params, Args, Returns                         string
+}
+
+// parseSignature splits a function signature into named parameters, call arguments and return types
+func parseSignature(signature string) (params, args, returns string, err error) {
+	if !strings.HasPrefix(signature, "func(") {
+		return "", "", "", fmt.Errorf("invalid signature: %s", signature)
+	}
+
+	end := strings.Index(signature, ")")
+	if end < 0 {
+		return "", "", "", fmt.Errorf("invalid signature: %s", signature)
+	}
+
+	var p, a []string
+	if in := strings.TrimSpace(signature[len("func("):end]); in != "" {
+		for i, param := range strings.Split(in, ",") {
+			name, typ := fmt.Sprintf("p%d", i), strings.TrimSpace(param)
+			if fields := strings.Fields(typ); len(fields) == 2 {
+				name, typ = fields[0], fields[1]
+			} else if typ == "context.Context" {
+				name = "ctx"
+			}
+
+			p = append(p, name+" "+typ)
+			a = append(a, name)
+		}
+	}
+
+	return strings.Join(p, ", "), strings.Join(a, ", "), signature[end+1:], nil
 }
 
 func generate(out io.Writer, packageName, functionName, baseType string, dynamicTypes ...dynamicType) error {
@@ -70,15 +100,29 @@ func generate(out io.Writer, packageName, functionName, baseType string, dynamic
 		return err
 	}
 
+	var imports []string
+
 	for _, dt := range dynamicTypes {
 		parts := strings.SplitN(dt.typ, ".", 2)
 
+		params, args, returns, err := parseSignature(dt.signature)
+		if err != nil {
+			return err
+		}
+
+		if strings.Contains(dt.signature, "context.") && len(imports) == 0 {
+			imports = append(imports, "context")
+		}
+
 		types[dt.typ] = typeStruct{
 			Type:      dt.typ,
 			ShortType: parts[1],
 			VarName:   strings.ToLower(parts[1][:1]) + parts[1][1:],
 			Signature: dt.signature,
 			Function:  dt.function,
+			Params:    params,
+			Args:      args,
+			Returns:   returns,
 		}
 
 		combos = append(combos, dt.typ)
@@ -96,6 +140,7 @@ func generate(out io.Writer, packageName, functionName, baseType string, dynamic
 
 	vars := struct {
 		API                 string
+		Imports             []string
 		Package, Function   string
 		BaseType, ShortBase string
 		ReturnType          string
@@ -103,6 +148,7 @@ func generate(out io.Writer, packageName, functionName, baseType string, dynamic
 		Combinations        [][]string
 	}{
 		API:          "github.com/evcc-io/evcc/api",
+		Imports:      imports,
 		Package:      packageName,
 		Function:     functionName,
 		BaseType:     baseType,
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/curve25519"
+)
+
+// wireguardCmd represents the wireguard command
+var wireguardCmd = &cobra.Command{
+	Use:   "wireguard",
+	Short: "Generate WireGuard configuration for remote access",
+	Run:   runWireguard,
+}
+
+const (
+	flagEndpoint = "endpoint"
+	flagWgPort   = "port"
+)
+
+func init() {
+	rootCmd.AddCommand(wireguardCmd)
+	wireguardCmd.Flags().StringP(flagEndpoint, "e", "", "Public host name, e.g. dynamic dns domain")
+	wireguardCmd.Flags().IntP(flagWgPort, "p", 51820, "Public WireGuard port")
+}
+
+const wireguardTmpl = `
+Home WireGuard interface (e.g. /etc/wireguard/wg0.conf):
+
+[Interface]
+Address = 10.8.0.1/24
+ListenPort = {{ .port }}
+PrivateKey = {{ .home.private }}
+
+[Peer]
+PublicKey = {{ .peer.public }}
+AllowedIPs = 10.8.0.2/32
+
+Remote peer (e.g. mobile phone):
+
+[Interface]
+Address = 10.8.0.2/32
+PrivateKey = {{ .peer.private }}
+
+[Peer]
+PublicKey = {{ .home.public }}
+Endpoint = {{ .endpoint }}:{{ .port }}
+AllowedIPs = 10.8.0.1/32
+PersistentKeepalive = 25
+
+Forward UDP port {{ .port }} on your router to the home interface.
+Then evcc is available remotely at http://10.8.0.1:{{ .evccport }}
+`
+
+// wireguardKeys generates a base64 encoded WireGuard key pair
+func wireguardKeys() (map[string]string, error) {
+	var private [curve25519.ScalarSize]byte
+	if _, err := rand.Read(private[:]); err != nil {
+		return nil, err
+	}
+
+	// clamp private key
+	private[0] &= 248
+	private[31] = (private[31] & 127) | 64
+
+	public, err := curve25519.X25519(private[:], curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"private": base64.StdEncoding.EncodeToString(private[:]),
+		"public":  base64.StdEncoding.EncodeToString(public),
+	}, nil
+}
+
+func runWireguard(cmd *cobra.Command, args []string) {
+	endpoint, _ := cmd.Flags().GetString(flagEndpoint)
+	if endpoint == "" {
+		// use configured dyndns domain
+		if err := loadConfigFile(&conf); err == nil {
+			if domain, ok := conf.DynDNS.Other["domain"].(string); ok {
+				endpoint = domain
+			}
+		}
+	}
+
+	if endpoint == "" {
+		log.FATAL.Fatal("missing endpoint")
+	}
+
+	port, _ := cmd.Flags().GetInt(flagWgPort)
+
+	home, err := wireguardKeys()
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	peer, err := wireguardKeys()
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	t := template.Must(template.New("out").Parse(wireguardTmpl))
+	if err := t.Execute(os.Stdout, map[string]interface{}{
+		"home":     home,
+		"peer":     peer,
+		"endpoint": endpoint,
+		"port":     port,
+		"evccport": conf.Network.Port,
+	}); err != nil {
+		log.FATAL.Fatal("rendering failed", err)
+	}
+}
@@ -0,0 +1,278 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	presenceInterval = time.Minute     // polling interval of presence checks
+	presenceTimeout  = 5 * time.Second // presence check timeout, device absent if exceeded
+)
+
+// AutomationConfig defines a rule switching the loadpoints' default mode by schedule and presence.
+// The first matching rule applies; the configured mode applies if no rule matches.
+type AutomationConfig struct {
+	Loadpoints []string       `mapstructure:"loadpoints"` // loadpoint titles, all loadpoints if empty
+	Mode       api.ChargeMode `mapstructure:"mode"`       // mode while the rule matches
+	Days       []string       `mapstructure:"days"`       // mon..sun, weekdays or weekend, every day if empty
+	From       string         `mapstructure:"from"`       // start time of day hh:mm, may wrap midnight
+	To         string         `mapstructure:"to"`         // end time of day hh:mm
+	Present    []string       `mapstructure:"present"`    // presence check urls, any must respond
+	Absent     []string       `mapstructure:"absent"`     // presence check urls, none must respond
+}
+
+var weekdays = map[string][]time.Weekday{
+	"sun":      {time.Sunday},
+	"mon":      {time.Monday},
+	"tue":      {time.Tuesday},
+	"wed":      {time.Wednesday},
+	"thu":      {time.Thursday},
+	"fri":      {time.Friday},
+	"sat":      {time.Saturday},
+	"weekdays": {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekend":  {time.Saturday, time.Sunday},
+}
+
+// automationRule is a parsed automation rule
+type automationRule struct {
+	AutomationConfig
+	loadpoints []*LoadPoint
+	days       map[time.Weekday]bool // nil for every day
+	from, to   time.Duration         // time of day, equal for all day
+}
+
+// automation switches loadpoint modes on rule changes
+type automation struct {
+	log      *util.Logger
+	helper   *request.Helper
+	rules    []*automationRule
+	mu       sync.Mutex
+	presence map[string]bool                // presence check results, polled in background
+	defaults map[*LoadPoint]api.ChargeMode  // configured mode
+	applied  map[*LoadPoint]*automationRule // matching rule, nil for configured mode
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time: %s", s)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// newAutomationFromConfig creates the automation rules
+func newAutomationFromConfig(log *util.Logger, rules []AutomationConfig, loadpoints []*LoadPoint) (*automation, error) {
+	a := &automation{
+		log:      log,
+		helper:   request.NewHelper(log),
+		presence: make(map[string]bool),
+		defaults: make(map[*LoadPoint]api.ChargeMode),
+		applied:  make(map[*LoadPoint]*automationRule),
+	}
+
+	a.helper.Timeout = presenceTimeout
+
+	for i, cc := range rules {
+		r := &automationRule{AutomationConfig: cc}
+
+		if _, err := api.ChargeModeString(cc.Mode.String()); err != nil {
+			return nil, fmt.Errorf("automation %d: invalid mode: %s", i, cc.Mode)
+		}
+
+		if len(cc.Loadpoints) == 0 {
+			r.loadpoints = loadpoints
+		}
+
+		for _, title := range cc.Loadpoints {
+			idx := slices.IndexFunc(loadpoints, func(lp *LoadPoint) bool {
+				return lp.Title == title
+			})
+			if idx < 0 {
+				return nil, fmt.Errorf("automation %d: loadpoint not found: %s", i, title)
+			}
+			r.loadpoints = append(r.loadpoints, loadpoints[idx])
+		}
+
+		for _, day := range cc.Days {
+			wd, ok := weekdays[strings.ToLower(day)]
+			if !ok {
+				return nil, fmt.Errorf("automation %d: invalid day: %s", i, day)
+			}
+
+			if r.days == nil {
+				r.days = make(map[time.Weekday]bool)
+			}
+			for _, d := range wd {
+				r.days[d] = true
+			}
+		}
+
+		var err error
+		if r.from, err = parseTimeOfDay(cc.From); err == nil {
+			r.to, err = parseTimeOfDay(cc.To)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("automation %d: %w", i, err)
+		}
+
+		for _, uri := range append(cc.Present, cc.Absent...) {
+			a.presence[uri] = false
+		}
+
+		a.rules = append(a.rules, r)
+	}
+
+	for _, lp := range loadpoints {
+		a.defaults[lp] = lp.configuredMode()
+	}
+
+	return a, nil
+}
+
+// scheduled returns if the rule's days and time of day contain ts
+func (r *automationRule) scheduled(ts time.Time) bool {
+	tod := ts.Sub(time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, ts.Location()))
+
+	day := ts.Weekday()
+	switch {
+	case r.from == r.to:
+		// all day
+	case r.from < r.to:
+		if tod < r.from || tod >= r.to {
+			return false
+		}
+	default:
+		// wrapping midnight, morning belongs to previous day's rule
+		if tod >= r.to && tod < r.from {
+			return false
+		}
+		if tod < r.to {
+			day = ts.AddDate(0, 0, -1).Weekday()
+		}
+	}
+
+	return r.days == nil || r.days[day]
+}
+
+// matches returns if the rule applies at ts given the presence check results
+func (a *automation) matches(r *automationRule, ts time.Time) bool {
+	if !r.scheduled(ts) {
+		return false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	present := func(uri string) bool { return a.presence[uri] }
+
+	if len(r.Present) > 0 && slices.IndexFunc(r.Present, present) < 0 {
+		return false
+	}
+
+	return slices.IndexFunc(r.Absent, present) < 0
+}
+
+// updatePresence polls the presence checks
+func (a *automation) updatePresence() {
+	a.mu.Lock()
+	uris := make([]string, 0, len(a.presence))
+	for uri := range a.presence {
+		uris = append(uris, uri)
+	}
+	a.mu.Unlock()
+
+	for _, uri := range uris {
+		_, err := a.helper.GetBody(uri)
+		present := err == nil
+
+		a.mu.Lock()
+		if present != a.presence[uri] {
+			a.log.DEBUG.Printf("automation: %s %s", uri, map[bool]string{true: "present", false: "absent"}[present])
+			a.presence[uri] = present
+		}
+		a.mu.Unlock()
+	}
+}
+
+// run polls the presence checks outside of the site's control loop until stopped
+func (a *automation) run(stopC <-chan struct{}) {
+	if len(a.presence) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(presenceInterval)
+	defer ticker.Stop()
+
+	for {
+		a.updatePresence()
+
+		select {
+		case <-ticker.C:
+		case <-stopC:
+			return
+		}
+	}
+}
+
+// update applies the loadpoints' modes when their matching rule changes
+func (a *automation) update(now time.Time, loadpoints []*LoadPoint) {
+	for _, lp := range loadpoints {
+		var rule *automationRule
+		for _, r := range a.rules {
+			if slices.Contains(r.loadpoints, lp) && a.matches(r, now) {
+				rule = r
+				break
+			}
+		}
+
+		if prev, ok := a.applied[lp]; ok && prev == rule || !ok && rule == nil {
+			continue
+		}
+		a.applied[lp] = rule
+
+		mode := a.defaults[lp]
+		if rule != nil {
+			mode = rule.Mode
+		}
+
+		lp.log.INFO.Printf("automation: mode %s", mode)
+		lp.setAutomationMode(rule)
+		lp.SetMode(mode)
+	}
+}
+
+// configuredMode returns the configured mode restored on vehicle disconnect
+func (lp *LoadPoint) configuredMode() api.ChargeMode {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if mode := lp.onDisconnect.Mode; mode != nil {
+		return *mode
+	}
+	return lp.Mode
+}
+
+// setAutomationMode sets the rule's mode restored on vehicle disconnect instead of the configured mode
+func (lp *LoadPoint) setAutomationMode(rule *automationRule) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.automationMode = nil
+	if rule != nil {
+		mode := rule.Mode
+		lp.automationMode = &mode
+	}
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutomationSchedule(t *testing.T) {
+	r := &automationRule{
+		days: map[time.Weekday]bool{time.Friday: true},
+		from: 22 * time.Hour,
+		to:   6 * time.Hour,
+	}
+
+	// friday 2022-10-07
+	fri := func(h int) time.Time { return time.Date(2022, 10, 7, h, 0, 0, 0, time.UTC) }
+
+	assert.False(t, r.scheduled(fri(21)))
+	assert.True(t, r.scheduled(fri(23)))
+	assert.True(t, r.scheduled(fri(24+5)), "saturday morning belongs to friday night")
+	assert.False(t, r.scheduled(fri(5)), "friday morning belongs to thursday night")
+}
+
+func TestAutomation(t *testing.T) {
+	present := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !present {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	lp := &LoadPoint{log: util.NewLogger("foo"), clock: clock.NewMock(), Mode: api.ModePV}
+	lps := []*LoadPoint{lp}
+
+	a, err := newAutomationFromConfig(util.NewLogger("foo"), []AutomationConfig{
+		{Mode: api.ModeMinPV, Days: []string{"weekdays"}, From: "17:00", To: "22:00", Present: []string{srv.URL}},
+		{Mode: api.ModeOff, Days: []string{"weekdays"}, From: "17:00", To: "22:00"},
+	}, lps)
+	require.NoError(t, err)
+
+	// monday 2022-10-03
+	mon := time.Date(2022, 10, 3, 12, 0, 0, 0, time.UTC)
+
+	// no rule keeps mode
+	a.update(mon, lps)
+	assert.Equal(t, api.ModePV, lp.GetMode())
+
+	// manual changes are kept until next rule change
+	lp.SetMode(api.ModeNow)
+	a.update(mon.Add(time.Hour), lps)
+	assert.Equal(t, api.ModeNow, lp.GetMode())
+
+	// present in the evening
+	a.updatePresence()
+	a.update(mon.Add(5*time.Hour), lps)
+	assert.Equal(t, api.ModeMinPV, lp.GetMode())
+	assert.Equal(t, api.ModeMinPV, *lp.automationMode)
+	assert.Nil(t, lp.onDisconnect.Mode, "configured mode must not be changed")
+
+	// absent in the evening
+	present = false
+	a.updatePresence()
+	a.update(mon.Add(6*time.Hour), lps)
+	assert.Equal(t, api.ModeOff, lp.GetMode())
+
+	// configured mode restored
+	a.update(mon.Add(10*time.Hour), lps)
+	assert.Equal(t, api.ModePV, lp.GetMode())
+	assert.Nil(t, lp.automationMode)
+
+	_, err = newAutomationFromConfig(util.NewLogger("foo"), []AutomationConfig{{Mode: api.ModePV, Days: []string{"foo"}}}, lps)
+	assert.Error(t, err)
+
+	// configured instead of startup mode
+	mode := api.ModeMinPV
+	lp.onDisconnect.Mode = &mode
+	a, err = newAutomationFromConfig(util.NewLogger("foo"), nil, lps)
+	require.NoError(t, err)
+	assert.Equal(t, api.ModeMinPV, a.defaults[lp])
+}
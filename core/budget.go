@@ -0,0 +1,201 @@
+package core
+
+import (
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+const (
+	evBudgetThrottled = "budgetThrottled" // charging throttled as budget is approached
+	evBudgetExhausted = "budgetExhausted" // grid charging stopped as budget is used up
+)
+
+// BudgetConfig defines a monthly grid energy or cost budget for charging, e.g. for prepaid or capped electricity contracts.
+// Charging current is reduced progressively once the throttle share of the budget is used and grid charging stops at the budget.
+type BudgetConfig struct {
+	Energy   float64 `mapstructure:"energy"`   // grid energy per month in kWh
+	Cost     float64 `mapstructure:"cost"`     // grid cost per month
+	Throttle float64 `mapstructure:"throttle"` // used budget in % from which charging is throttled, 80% if not set
+	StartDay int     `mapstructure:"startDay"` // first day of the budget month (1-28)
+}
+
+// budget levels for notification
+const (
+	budgetAvailable = iota
+	budgetThrottled
+	budgetExhausted
+)
+
+// budget tracks the grid energy and cost used within the current month
+type budget struct {
+	BudgetConfig
+	key     string   // settings key prefix
+	period  *billing // budget month
+	start   time.Time
+	end     time.Time
+	energy  float64 // used grid energy in kWh
+	cost    float64 // used grid cost
+	level   int     // notified budget level
+	publish func(key string, val interface{})
+	notify  func(event string)
+}
+
+// newBudgetFromConfig creates a budget persisted using the settings key
+func newBudgetFromConfig(key string, cc BudgetConfig) (*budget, error) {
+	if cc.Energy <= 0 && cc.Cost <= 0 {
+		return nil, errors.New("missing energy or cost")
+	}
+
+	if cc.Throttle == 0 {
+		cc.Throttle = 80
+	}
+	if cc.Throttle < 0 || cc.Throttle > 100 {
+		return nil, errors.New("throttle must be in [0..100]")
+	}
+
+	period, err := newBillingFromConfig(BillingConfig{Period: "monthly", StartDay: cc.StartDay})
+	if err != nil {
+		return nil, err
+	}
+
+	b := &budget{
+		BudgetConfig: cc,
+		key:          key,
+		period:       period,
+		publish:      func(string, interface{}) {},
+		notify:       func(string) {},
+	}
+
+	return b, nil
+}
+
+// load restores the used budget of the current month
+func (b *budget) load(now time.Time) {
+	b.start, b.end = b.period.bounds(now)
+
+	if start, err := settings.Time(b.key + ".start"); err == nil && start.Equal(b.start) {
+		b.energy, _ = settings.Float(b.key + ".energy")
+		b.cost, _ = settings.Float(b.key + ".cost")
+		// don't repeat notifications after restart
+		b.level = b.levelOf(b.used())
+	}
+}
+
+func (b *budget) save() {
+	settings.SetTime(b.key+".start", b.start)
+	settings.SetFloat(b.key+".energy", b.energy)
+	settings.SetFloat(b.key+".cost", b.cost)
+}
+
+// rollover starts a new budget month once the current one has ended
+func (b *budget) rollover(now time.Time) {
+	if b.end.IsZero() {
+		b.load(now)
+	}
+
+	if now.Before(b.end) {
+		return
+	}
+
+	b.start, b.end = b.period.bounds(now)
+	b.energy, b.cost, b.level = 0, 0, budgetAvailable
+
+	b.save()
+}
+
+// used returns the used share of the budget, the larger of energy and cost share
+func (b *budget) used() float64 {
+	var res float64
+	if b.Energy > 0 {
+		res = b.energy / b.Energy
+	}
+	if b.Cost > 0 && b.cost/b.Cost > res {
+		res = b.cost / b.Cost
+	}
+	return res
+}
+
+// levelOf returns the budget level of the used share
+func (b *budget) levelOf(used float64) int {
+	switch {
+	case used >= 1:
+		return budgetExhausted
+	case used*100 >= b.Throttle:
+		return budgetThrottled
+	default:
+		return budgetAvailable
+	}
+}
+
+// add adds the used grid energy and cost and notifies once a budget level is reached
+func (b *budget) add(now time.Time, energy, cost float64) {
+	b.rollover(now)
+
+	b.energy += energy
+	b.cost += cost
+	b.save()
+
+	used := b.used()
+	b.publish("budgetUsed", 100*used)
+
+	if level := b.levelOf(used); level > b.level {
+		b.level = level
+
+		event := evBudgetThrottled
+		if level == budgetExhausted {
+			event = evBudgetExhausted
+		}
+		b.notify(event)
+	}
+}
+
+// limit returns the maximum charge current for the used budget.
+// The current is reduced linearly from max to min current between throttle and full budget and is zero once the budget is used up.
+func (b *budget) limit(now time.Time, minCurrent, maxCurrent float64) float64 {
+	b.rollover(now)
+
+	used := b.used()
+	throttle := b.Throttle / 100
+
+	switch {
+	case used >= 1:
+		return 0
+	case used < throttle:
+		return maxCurrent
+	}
+
+	return maxCurrent - (maxCurrent-minCurrent)*(used-throttle)/(1-throttle)
+}
+
+// budgetCurrent throttles grid charging according to the loadpoint's and site's budgets.
+// PV mode is not throttled since it charges from surplus.
+func (lp *LoadPoint) budgetCurrent(chargeCurrent float64) (float64, bool) {
+	if len(lp.budgets) == 0 || chargeCurrent == 0 || lp.GetMode() == api.ModePV {
+		return chargeCurrent, false
+	}
+
+	current := chargeCurrent
+	for _, b := range lp.budgets {
+		if limit := b.limit(lp.now(), lp.GetMinCurrent(), lp.GetMaxCurrent()); limit < current {
+			current = limit
+		}
+	}
+
+	if current == chargeCurrent {
+		return chargeCurrent, false
+	}
+
+	lp.log.DEBUG.Printf("budget: charge current limited to %.3gA", current)
+
+	return current, true
+}
+
+// updateBudgets adds the charged grid energy and cost to the loadpoint's and site's budgets
+func (lp *LoadPoint) updateBudgets(energy, cost float64) {
+	for _, b := range lp.budgets {
+		b.add(lp.now(), energy, cost)
+	}
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudget(t *testing.T) {
+	now := time.Date(2022, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	b, err := newBudgetFromConfig("test.budget", BudgetConfig{Energy: 100, Cost: 40})
+	require.NoError(t, err)
+
+	var events []string
+	b.notify = func(event string) { events = append(events, event) }
+
+	assert.Equal(t, 16.0, b.limit(now, 6, 16))
+
+	// cost share exceeds energy share
+	b.add(now, 50, 36)
+	assert.Equal(t, 0.9, b.used())
+	assert.InDelta(t, 11.0, b.limit(now, 6, 16), 1e-6, "throttled halfway between throttle and budget")
+	assert.Equal(t, []string{evBudgetThrottled}, events)
+
+	b.add(now, 5, 2)
+	assert.Len(t, events, 1, "no repeated notification")
+
+	b.add(now, 50, 0)
+	assert.Equal(t, 0.0, b.limit(now, 6, 16))
+	assert.Equal(t, []string{evBudgetThrottled, evBudgetExhausted}, events)
+
+	// next month
+	next := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 16.0, b.limit(next, 6, 16))
+	assert.Equal(t, 0.0, b.used())
+
+	_, err = newBudgetFromConfig("test.budget", BudgetConfig{})
+	assert.Error(t, err)
+}
+
+func TestBudgetCurrent(t *testing.T) {
+	clck := clock.NewMock()
+	clck.Set(time.Date(2022, 10, 15, 12, 0, 0, 0, time.UTC))
+
+	b, err := newBudgetFromConfig("test.budgetCurrent", BudgetConfig{Energy: 100})
+	require.NoError(t, err)
+	b.add(clck.Now(), 100, 0)
+
+	lp := &LoadPoint{log: util.NewLogger("foo"), clock: clck, Mode: api.ModeNow, MinCurrent: 6, MaxCurrent: 16, budgets: []*budget{b}}
+
+	current, throttled := lp.budgetCurrent(16)
+	assert.True(t, throttled)
+	assert.Equal(t, 0.0, current)
+
+	// surplus charging is not throttled
+	lp.Mode = api.ModePV
+	current, throttled = lp.budgetCurrent(10)
+	assert.False(t, throttled)
+	assert.Equal(t, 10.0, current)
+}
@@ -0,0 +1,454 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util"
+	"golang.org/x/exp/slices"
+)
+
+// CircuitConfig defines an electrical circuit limiting the load of its loadpoints and sub-circuits,
+// e.g. house feed, garage subpanel and wallboxes. The configured limits are the fuse rating,
+// runtime limits can only lower them.
+//
+// Circuits apply after the other loadpoint groups: a shared supply connects only one of its loadpoints
+// and rotation blocks loadpoints waiting for their turn, neither of these demand circuit headroom.
+// Loadpoint priority orders the loadpoints of circuits using the priority strategy and otherwise
+// decides which loadpoints are dropped first if the minimum currents exceed the headroom.
+type CircuitConfig struct {
+	Name               string   `mapstructure:"name"`
+	Parent             string   `mapstructure:"parent"`     // enclosing circuit
+	Meter              string   `mapstructure:"meter"`      // optional meter measuring the circuit's total load
+	Loadpoints         []string `mapstructure:"loadpoints"` // loadpoint titles
+	site.CircuitLimits `mapstructure:",squash"`
+}
+
+// circuit distributes its headroom among its loadpoints and sub-circuits
+type circuit struct {
+	log        *util.Logger
+	name       string
+	parent     *circuit
+	children   []*circuit
+	loadpoints []*LoadPoint
+	meter      api.Meter
+	config     site.CircuitLimits // configured limits
+	limits     site.CircuitLimits // effective limits, lowered at runtime
+
+	otherCurrent, otherPower float64 // measured load not caused by the circuit's loadpoints
+	headCurrent, headPower   float64 // headroom of the last distribution
+	current, power           float64 // load assigned by the last distribution
+}
+
+// circuitDemand is a loadpoint's demand of the current distribution cycle
+type circuitDemand struct {
+	current   float64 // requested per phase current
+	min       float64 // minimum charge current
+	committed float64 // current the charger is currently allowed to draw
+	factor    float64 // power per ampere
+	priority  int
+}
+
+// circuitKey is the settings key of the circuit's runtime limits
+func circuitKey(name string) string {
+	return "circuit." + name
+}
+
+// circuitSettings are the runtime limits persisted with the configured limits they were set for
+type circuitSettings struct {
+	Config site.CircuitLimits `json:"config"`
+	Limits site.CircuitLimits `json:"limits"`
+}
+
+// newCircuitsFromConfig creates the circuit hierarchy and attaches it to the referenced loadpoints
+func newCircuitsFromConfig(log *util.Logger, cp configProvider, configs []CircuitConfig, loadpoints []*LoadPoint) ([]*circuit, error) {
+	var res []*circuit
+
+	find := func(name string) *circuit {
+		idx := slices.IndexFunc(res, func(c *circuit) bool {
+			return c.name == name
+		})
+		if idx < 0 {
+			return nil
+		}
+		return res[idx]
+	}
+
+	for _, cc := range configs {
+		if cc.Name == "" {
+			return nil, errors.New("circuit: missing name")
+		}
+
+		if find(cc.Name) != nil {
+			return nil, fmt.Errorf("circuit: duplicate name: %s", cc.Name)
+		}
+
+		if err := cc.CircuitLimits.Validate(); err != nil {
+			return nil, fmt.Errorf("circuit %s: %w", cc.Name, err)
+		}
+
+		c := &circuit{
+			log:    log,
+			name:   cc.Name,
+			config: cc.CircuitLimits,
+			limits: cc.CircuitLimits,
+		}
+
+		// limits changed at runtime are discarded once the configured limits change
+		var stored circuitSettings
+		if err := settings.Json(circuitKey(cc.Name), &stored); err == nil && stored.Limits.Validate() == nil {
+			if stored.Config == cc.CircuitLimits {
+				c.limits = stored.Limits.Clamp(cc.CircuitLimits)
+			} else {
+				log.WARN.Printf("circuit %s: configuration changed, discarding runtime limits", cc.Name)
+			}
+		}
+
+		if cc.Meter != "" {
+			var err error
+			if c.meter, err = cp.Meter(cc.Meter); err != nil {
+				return nil, fmt.Errorf("circuit %s: %w", cc.Name, err)
+			}
+		}
+
+		res = append(res, c)
+	}
+
+	for i, cc := range configs {
+		if cc.Parent == "" {
+			continue
+		}
+
+		parent := find(cc.Parent)
+		if parent == nil {
+			return nil, fmt.Errorf("circuit %s: parent not found: %s", cc.Name, cc.Parent)
+		}
+
+		res[i].parent = parent
+		parent.children = append(parent.children, res[i])
+	}
+
+	for _, c := range res {
+		depth := 0
+		for p := c.parent; p != nil; p = p.parent {
+			if depth++; depth > len(res) {
+				return nil, fmt.Errorf("circuit %s: circular parent", c.name)
+			}
+		}
+	}
+
+	for i, cc := range configs {
+		for _, title := range cc.Loadpoints {
+			idx := slices.IndexFunc(loadpoints, func(lp *LoadPoint) bool {
+				return lp.Title == title
+			})
+			if idx < 0 {
+				return nil, fmt.Errorf("circuit %s: loadpoint not found: %s", cc.Name, title)
+			}
+
+			lp := loadpoints[idx]
+			if lp.circuit != nil {
+				return nil, fmt.Errorf("circuit %s: loadpoint already assigned: %s", cc.Name, title)
+			}
+
+			lp.circuit = res[i]
+			res[i].loadpoints = append(res[i].loadpoints, lp)
+		}
+	}
+
+	return res, nil
+}
+
+// allLoadpoints returns the loadpoints of the circuit and its sub-circuits
+func (c *circuit) allLoadpoints() []*LoadPoint {
+	res := slices.Clone(c.loadpoints)
+	for _, child := range c.children {
+		res = append(res, child.allLoadpoints()...)
+	}
+	return res
+}
+
+// priority returns the highest loadpoint priority of the circuit
+func (c *circuit) priority(demands map[*LoadPoint]circuitDemand) int {
+	res := math.MinInt
+	for _, lp := range c.allLoadpoints() {
+		if p := demands[lp].priority; p > res {
+			res = p
+		}
+	}
+	return res
+}
+
+// capacity limits current and power to the circuit's limits less other loads
+func (c *circuit) capacity(current, power float64) (float64, float64) {
+	if c.limits.MaxCurrent > 0 {
+		current = math.Min(current, c.limits.MaxCurrent-c.otherCurrent)
+	}
+	if c.limits.MaxPower > 0 {
+		power = math.Min(power, c.limits.MaxPower-c.otherPower)
+	}
+	return math.Max(0, current), math.Max(0, power)
+}
+
+// demand returns the current and power requested by the circuit's loadpoints within the circuit's limits
+func (c *circuit) demand(demands map[*LoadPoint]circuitDemand) (float64, float64) {
+	var current, power float64
+
+	for _, lp := range c.loadpoints {
+		d := demands[lp]
+		current += d.current
+		power += d.current * d.factor
+	}
+
+	for _, child := range c.children {
+		a, w := child.demand(demands)
+		current += a
+		power += w
+	}
+
+	return c.capacity(current, power)
+}
+
+// measure returns the circuit's measured load not caused by its loadpoints
+func (c *circuit) measure(demands map[*LoadPoint]circuitDemand) (float64, float64, error) {
+	power, err := c.meter.CurrentPower()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var ownCurrent, ownPower float64
+	for _, lp := range c.allLoadpoints() {
+		if p, f := lp.GetChargePower(), demands[lp].factor; f > 0 {
+			ownCurrent += p / f
+			ownPower += p
+		}
+	}
+
+	otherPower := math.Max(0, power-ownPower)
+
+	// assume other loads on a single phase without phase currents
+	otherCurrent := otherPower / Voltage
+
+	if m, ok := c.meter.(api.MeterCurrent); ok {
+		l1, l2, l3, err := m.Currents()
+		if err != nil {
+			return 0, 0, err
+		}
+		otherCurrent = math.Max(0, math.Max(l1, math.Max(l2, l3))-ownCurrent)
+	}
+
+	return otherCurrent, otherPower, nil
+}
+
+// distribute assigns the headroom within the given current and power to the circuit's loadpoints and sub-circuits
+func (c *circuit) distribute(current, power float64, demands map[*LoadPoint]circuitDemand, res map[*LoadPoint]float64) {
+	c.headCurrent, c.headPower = c.capacity(current, power)
+
+	if c.limits.Strategy == site.CircuitPriority {
+		c.distributePriority(demands, res)
+	} else {
+		c.distributeProportional(demands, res)
+	}
+
+	c.current, c.power = 0, 0
+
+	for _, lp := range c.loadpoints {
+		c.current += res[lp]
+		c.power += res[lp] * demands[lp].factor
+	}
+
+	for _, child := range c.children {
+		c.current += child.current
+		c.power += child.power
+	}
+}
+
+// distributePriority serves loadpoints and sub-circuits in order of priority
+func (c *circuit) distributePriority(demands map[*LoadPoint]circuitDemand, res map[*LoadPoint]float64) {
+	type node struct {
+		lp       *LoadPoint
+		circuit  *circuit
+		priority int
+	}
+
+	var nodes []node
+	for _, lp := range c.loadpoints {
+		nodes = append(nodes, node{lp: lp, priority: demands[lp].priority})
+	}
+	for _, child := range c.children {
+		nodes = append(nodes, node{circuit: child, priority: child.priority(demands)})
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].priority > nodes[j].priority
+	})
+
+	current, power := c.headCurrent, c.headPower
+
+	for _, n := range nodes {
+		if n.circuit != nil {
+			n.circuit.distribute(current, power, demands, res)
+			current -= n.circuit.current
+			power -= n.circuit.power
+			continue
+		}
+
+		d := demands[n.lp]
+
+		limit := math.Min(d.current, current)
+		if d.factor > 0 {
+			limit = math.Min(limit, power/d.factor)
+		}
+
+		if limit < d.min {
+			limit = 0
+		}
+
+		res[n.lp] = limit
+		current -= limit
+		power -= limit * d.factor
+	}
+}
+
+// distributeProportional assigns each loadpoint its minimum current and shares the remaining headroom
+// in proportion to the loadpoints' and sub-circuits' additional demand.
+// If the minimum currents exceed the headroom, loadpoints are dropped starting with lowest priority.
+func (c *circuit) distributeProportional(demands map[*LoadPoint]circuitDemand, res map[*LoadPoint]float64) {
+	var active []*LoadPoint
+	for _, lp := range c.loadpoints {
+		res[lp] = 0
+		if demands[lp].current > 0 {
+			active = append(active, lp)
+		}
+	}
+
+	// minimum current and power of active loadpoints
+	minimum := func() (current, power float64) {
+		for _, lp := range active {
+			d := demands[lp]
+			current += d.min
+			power += d.min * d.factor
+		}
+		return current, power
+	}
+
+	minCurrent, minPower := minimum()
+	for len(active) > 0 && (minCurrent > c.headCurrent || minPower > c.headPower) {
+		idx := 0
+		for i, lp := range active {
+			if demands[lp].priority <= demands[active[idx]].priority {
+				idx = i
+			}
+		}
+
+		active = slices.Delete(active, idx, idx+1)
+		minCurrent, minPower = minimum()
+	}
+
+	var extraCurrent, extraPower float64
+	for _, lp := range active {
+		d := demands[lp]
+		extraCurrent += d.current - d.min
+		extraPower += (d.current - d.min) * d.factor
+	}
+
+	for _, child := range c.children {
+		a, w := child.demand(demands)
+		extraCurrent += a
+		extraPower += w
+	}
+
+	scale := 1.0
+	if remaining := c.headCurrent - minCurrent; extraCurrent > remaining {
+		scale = remaining / extraCurrent
+	}
+	if remaining := c.headPower - minPower; extraPower > remaining {
+		scale = math.Min(scale, remaining/extraPower)
+	}
+
+	for _, lp := range active {
+		d := demands[lp]
+		res[lp] = d.min + scale*(d.current-d.min)
+	}
+
+	for _, child := range c.children {
+		a, w := child.demand(demands)
+		child.distribute(scale*a, scale*w, demands, res)
+	}
+}
+
+// circuitHeadroom returns the current the loadpoint can draw without overloading any of its circuits
+// while the other loadpoints keep their current until their next update
+func circuitHeadroom(lp *LoadPoint, demands map[*LoadPoint]circuitDemand) float64 {
+	res := math.Inf(1)
+	factor := demands[lp].factor
+
+	for c := lp.circuit; c != nil; c = c.parent {
+		current, power := c.capacity(math.Inf(1), math.Inf(1))
+
+		for _, other := range c.allLoadpoints() {
+			if other != lp {
+				d := demands[other]
+				current -= d.committed
+				power -= d.committed * d.factor
+			}
+		}
+
+		res = math.Min(res, current)
+		if factor > 0 {
+			res = math.Min(res, power/factor)
+		}
+	}
+
+	return math.Max(0, res)
+}
+
+// circuitDemand returns the loadpoint's demand.
+// It is called from the site's update loop and therefore accesses loadpoints' state without locking.
+func (lp *LoadPoint) circuitDemand() circuitDemand {
+	d := circuitDemand{
+		min:      lp.GetMinCurrent(),
+		factor:   Voltage * float64(lp.activePhases()),
+		priority: lp.Priority,
+	}
+
+	if lp.enabled {
+		d.committed = lp.chargeCurrent
+	}
+
+	// no demand while off or waiting for turn in rotation
+	if lp.GetMode() == api.ModeOff || (lp.rotation != nil && lp.rotation.blocked(lp)) {
+		return d
+	}
+
+	switch lp.GetStatus() {
+	case api.StatusC:
+		d.current = lp.GetMaxCurrent()
+	case api.StatusB:
+		// reserve minimum current for starting to charge
+		d.current = d.min
+	}
+
+	return d
+}
+
+// circuitCurrent limits the charge current to the loadpoint's share of its circuits
+func (lp *LoadPoint) circuitCurrent(chargeCurrent float64) (float64, bool) {
+	if lp.circuit == nil || chargeCurrent <= lp.circuitLimit {
+		return chargeCurrent, false
+	}
+
+	limit := lp.circuitLimit
+	if limit < lp.GetMinCurrent() {
+		limit = 0
+	}
+
+	lp.log.DEBUG.Printf("circuit %s: charge current limited to %.3gA", lp.circuit.name, limit)
+
+	return limit, true
+}
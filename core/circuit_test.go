@@ -0,0 +1,205 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type circuitMeter struct {
+	power float64
+}
+
+func (m *circuitMeter) CurrentPower() (float64, error) {
+	return m.power, nil
+}
+
+type circuitProvider struct {
+	configProvider
+	meter api.Meter
+}
+
+func (cp *circuitProvider) Meter(string) (api.Meter, error) {
+	return cp.meter, nil
+}
+
+func circuitLoadpoints(titles ...string) []*LoadPoint {
+	var res []*LoadPoint
+	for _, title := range titles {
+		res = append(res, &LoadPoint{
+			log:        util.NewLogger(title),
+			Title:      title,
+			Mode:       api.ModeNow,
+			MinCurrent: 6,
+			MaxCurrent: 16,
+			phases:     3,
+			status:     api.StatusC,
+		})
+	}
+	return res
+}
+
+func TestCircuitsConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		configs []CircuitConfig
+	}{
+		{"missing limits", []CircuitConfig{{Name: "house"}}},
+		{"invalid strategy", []CircuitConfig{{Name: "house", CircuitLimits: site.CircuitLimits{MaxCurrent: 32, Strategy: "foo"}}}},
+		{"duplicate name", []CircuitConfig{
+			{Name: "house", CircuitLimits: site.CircuitLimits{MaxCurrent: 32}},
+			{Name: "house", CircuitLimits: site.CircuitLimits{MaxCurrent: 32}},
+		}},
+		{"missing parent", []CircuitConfig{{Name: "garage", Parent: "house", CircuitLimits: site.CircuitLimits{MaxCurrent: 32}}}},
+		{"circular parent", []CircuitConfig{
+			{Name: "house", Parent: "garage", CircuitLimits: site.CircuitLimits{MaxCurrent: 32}},
+			{Name: "garage", Parent: "house", CircuitLimits: site.CircuitLimits{MaxCurrent: 32}},
+		}},
+		{"missing loadpoint", []CircuitConfig{{Name: "house", Loadpoints: []string{"foo"}, CircuitLimits: site.CircuitLimits{MaxCurrent: 32}}}},
+		{"duplicate loadpoint", []CircuitConfig{
+			{Name: "house", Loadpoints: []string{"lp1"}, CircuitLimits: site.CircuitLimits{MaxCurrent: 32}},
+			{Name: "garage", Loadpoints: []string{"lp1"}, CircuitLimits: site.CircuitLimits{MaxCurrent: 32}},
+		}},
+	} {
+		_, err := newCircuitsFromConfig(util.NewLogger("foo"), nil, tc.configs, circuitLoadpoints("lp1"))
+		assert.Error(t, err, tc.name)
+	}
+}
+
+func TestCircuitsDistribution(t *testing.T) {
+	Voltage = 230 // V
+
+	loadpoints := circuitLoadpoints("lp1", "lp2", "lp3")
+	lp1, lp2, lp3 := loadpoints[0], loadpoints[1], loadpoints[2]
+
+	// house feed with garage subpanel
+	circuits, err := newCircuitsFromConfig(util.NewLogger("foo"), nil, []CircuitConfig{
+		{Name: "house", Loadpoints: []string{"lp3"}, CircuitLimits: site.CircuitLimits{MaxCurrent: 35}},
+		{Name: "garage", Parent: "house", Loadpoints: []string{"lp1", "lp2"}, CircuitLimits: site.CircuitLimits{MaxCurrent: 20}},
+	}, loadpoints)
+	require.NoError(t, err)
+
+	s := &Site{log: util.NewLogger("foo"), loadpoints: loadpoints, circuits: circuits}
+	house, garage := circuits[0], circuits[1]
+
+	limits := func() []float64 {
+		return []float64{lp1.circuitLimit, lp2.circuitLimit, lp3.circuitLimit}
+	}
+
+	// proportional within garage and house limits
+	s.updateCircuits(nil)
+	assert.InDeltaSlice(t, []float64{9.67, 9.67, 15.67}, limits(), 0.01)
+	assert.InDelta(t, 35, house.current, 1e-6)
+	assert.InDelta(t, 19.33, garage.current, 0.01)
+
+	// garage by priority
+	garage.limits.Strategy = site.CircuitPriority
+	lp2.Priority = 1
+	s.updateCircuits(nil)
+	assert.InDeltaSlice(t, []float64{0, 16, 15.67}, limits(), 0.01)
+
+	// loadpoints not reaching minimum current are dropped
+	garage.limits = site.CircuitLimits{MaxCurrent: 10}
+	lp2.Priority = 0
+	s.updateCircuits(nil)
+	assert.InDeltaSlice(t, []float64{10, 0, 16}, limits(), 0.01)
+
+	// vehicle connected but not charging reserves minimum current
+	lp1.status = api.StatusB
+	garage.limits = site.CircuitLimits{MaxCurrent: 20}
+	s.updateCircuits(nil)
+	assert.InDeltaSlice(t, []float64{6, 13.33, 15.67}, limits(), 0.01)
+
+	// updated loadpoint waits for other loadpoints to reduce current
+	lp1.status = api.StatusC
+	lp1.enabled, lp1.chargeCurrent = true, 16
+	s.updateCircuits(lp2)
+	assert.InDelta(t, 4, lp2.circuitLimit, 0.01)
+
+	current, ok := lp2.circuitCurrent(16)
+	assert.True(t, ok)
+	assert.Equal(t, 0.0, current)
+
+	current, ok = lp1.circuitCurrent(6)
+	assert.False(t, ok)
+	assert.Equal(t, 6.0, current)
+}
+
+func TestCircuitsMeter(t *testing.T) {
+	Voltage = 230 // V
+
+	loadpoints := circuitLoadpoints("lp1")
+	meter := &circuitMeter{power: 3 * 230 * 10}
+
+	circuits, err := newCircuitsFromConfig(util.NewLogger("foo"), &circuitProvider{meter: meter}, []CircuitConfig{
+		{Name: "house", Meter: "grid", Loadpoints: []string{"lp1"}, CircuitLimits: site.CircuitLimits{MaxPower: 11040}},
+	}, loadpoints)
+	require.NoError(t, err)
+
+	s := &Site{log: util.NewLogger("foo"), loadpoints: loadpoints, circuits: circuits}
+
+	// other loads leave 4.6kW
+	s.updateCircuits(nil)
+	assert.InDelta(t, 6, loadpoints[0].circuitLimit, 0.01)
+
+	// own charge power is not counted as other load
+	loadpoints[0].chargePower = 3 * 230 * 6
+	meter.power += loadpoints[0].chargePower
+	s.updateCircuits(nil)
+	assert.InDelta(t, 6, loadpoints[0].circuitLimit, 0.01)
+}
+
+func TestCircuitsLimits(t *testing.T) {
+	circuits, err := newCircuitsFromConfig(util.NewLogger("foo"), nil, []CircuitConfig{
+		{Name: "feed", CircuitLimits: site.CircuitLimits{MaxCurrent: 35}},
+	}, nil)
+	require.NoError(t, err)
+
+	s := &Site{log: util.NewLogger("foo"), circuits: circuits}
+
+	assert.Error(t, s.SetCircuitLimits("foo", site.CircuitLimits{MaxCurrent: 32}))
+	assert.Error(t, s.SetCircuitLimits("feed", site.CircuitLimits{}))
+
+	require.NoError(t, s.SetCircuitLimits("feed", site.CircuitLimits{MaxCurrent: 25, Strategy: site.CircuitPriority}))
+	assert.Equal(t, []site.Circuit{{
+		Name:          "feed",
+		CircuitLimits: site.CircuitLimits{MaxCurrent: 25, Strategy: site.CircuitPriority},
+	}}, s.GetCircuits())
+
+	// configured limits cannot be exceeded
+	require.NoError(t, s.SetCircuitLimits("feed", site.CircuitLimits{MaxCurrent: 40, MaxPower: 20000}))
+	assert.Equal(t, site.CircuitLimits{MaxCurrent: 35, MaxPower: 20000}, s.GetCircuits()[0].CircuitLimits)
+
+	require.NoError(t, s.SetCircuitLimits("feed", site.CircuitLimits{MaxCurrent: 30}))
+
+	// runtime limits are restored
+	circuits, err = newCircuitsFromConfig(util.NewLogger("foo"), nil, []CircuitConfig{
+		{Name: "feed", CircuitLimits: site.CircuitLimits{MaxCurrent: 35}},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, site.CircuitLimits{MaxCurrent: 30}, circuits[0].limits)
+
+	// until the configuration changes
+	circuits, err = newCircuitsFromConfig(util.NewLogger("foo"), nil, []CircuitConfig{
+		{Name: "feed", CircuitLimits: site.CircuitLimits{MaxCurrent: 25}},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, site.CircuitLimits{MaxCurrent: 25}, circuits[0].limits)
+}
+
+func TestCircuitsRotation(t *testing.T) {
+	loadpoints := circuitLoadpoints("lp1", "lp2")
+
+	r, err := newRotationFromConfig(RotationConfig{Loadpoints: []string{"lp1", "lp2"}, MaxPower: 11000}, loadpoints)
+	require.NoError(t, err)
+
+	// lp2 is waiting for its turn
+	r.granted = map[*LoadPoint]bool{loadpoints[0]: true}
+
+	assert.Equal(t, 16.0, loadpoints[0].circuitDemand().current)
+	assert.Equal(t, 0.0, loadpoints[1].circuitDemand().current)
+}
@@ -7,4 +7,5 @@ type configProvider interface {
 	Meter(string) (api.Meter, error)
 	Charger(string) (api.Charger, error)
 	Vehicle(string) (api.Vehicle, error)
+	VehicleName(api.Vehicle) string
 }
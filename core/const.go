@@ -10,4 +10,6 @@ const (
 	vehicleRange     = "vehicleRange"     // vehicle range
 	vehicleOdometer  = "vehicleOdometer"  // vehicle odometer
 	vehicleTargetSoC = "vehicleTargetSoC" // vehicle soc limit
+
+	vehicleConsumption = "vehicleConsumption" // learned vehicle consumption in kWh/100km
 )
@@ -0,0 +1,163 @@
+package db
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrFrozen is returned when modifying a session of a closed billing period
+var ErrFrozen = errors.New("session frozen by billing period")
+
+// Readings are charge meter readings in kWh by loadpoint
+type Readings map[string]float64
+
+// GormDataType stores readings as json string
+func (Readings) GormDataType() string {
+	return "string"
+}
+
+// Value implements the driver.Valuer interface
+func (r Readings) Value() (driver.Value, error) {
+	b, err := json.Marshal(r)
+	return string(b), err
+}
+
+// Scan implements the sql.Scanner interface
+func (r *Readings) Scan(val any) error {
+	var b []byte
+
+	switch v := val.(type) {
+	case nil:
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("invalid readings: %v", val)
+	}
+
+	return json.Unmarshal(b, r)
+}
+
+// Period is a closed billing period with the charge meter readings at its end
+type Period struct {
+	ID       uint      `json:"id" gorm:"primarykey"`
+	Start    time.Time `json:"start" gorm:"column:period_start"`
+	End      time.Time `json:"end" gorm:"column:period_end"`
+	Readings Readings  `json:"readings"`
+}
+
+// TableName implements the gorm.Tabler interface
+func (Period) TableName() string {
+	return "billing_periods"
+}
+
+// ClosePeriod persists the billing period and freezes all unassigned sessions finished before its end
+func ClosePeriod(txn *gorm.DB, start, end time.Time, readings Readings) (Period, error) {
+	res := Period{
+		Start:    start,
+		End:      end,
+		Readings: readings,
+	}
+
+	err := txn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&res).Error; err != nil {
+			return err
+		}
+
+		// period is create-only to prevent persisting a stale session from unfreezing it
+		return tx.Exec("UPDATE sessions SET period=? WHERE period=0 AND finished>? AND finished<?", res.ID, time.Time{}, end).Error
+	})
+
+	return res, err
+}
+
+// ReadingsAt returns the charge meter readings at the given time derived from the sessions' meter values.
+// Sessions in progress at that time contribute their start reading since they are billed in the following period.
+func ReadingsAt(txn *gorm.DB, ts time.Time) (Readings, error) {
+	var sessions Sessions
+	if err := txn.Where("created<?", ts).Order("created").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	res := make(Readings)
+	for _, s := range sessions {
+		switch {
+		case !s.Finished.IsZero() && s.Finished.Before(ts) && s.MeterStop > 0:
+			res[s.Loadpoint] = s.MeterStop
+		case (s.Finished.IsZero() || !s.Finished.Before(ts)) && s.MeterStart > 0:
+			res[s.Loadpoint] = s.MeterStart
+		}
+	}
+
+	return res, nil
+}
+
+// Periods returns the closed billing periods
+func Periods(txn *gorm.DB) ([]Period, error) {
+	var res []Period
+	err := txn.Order("period_start").Find(&res).Error
+	return res, err
+}
+
+// LastPeriod returns the most recent closed billing period
+func LastPeriod(txn *gorm.DB) (Period, error) {
+	var res Period
+	err := txn.Order("period_end desc").First(&res).Error
+	return res, err
+}
+
+// Statement is the billing statement of a closed period
+type Statement struct {
+	Period  Period           `json:"period"`
+	Opening Readings         `json:"opening"` // readings at period start, empty for the first period
+	Groups  []StatementGroup `json:"groups"`
+}
+
+// StatementGroup are the billed sessions of a group with the metered energy of its loadpoints
+type StatementGroup struct {
+	SessionStats
+	MeterEnergy *float64 `json:"meterEnergy,omitempty"` // charge meter difference in kWh
+}
+
+// PeriodSessions returns the period, its opening readings and its frozen sessions
+func PeriodSessions(txn *gorm.DB, id uint) (Period, Readings, Sessions, error) {
+	var p Period
+	if err := txn.First(&p, id).Error; err != nil {
+		return p, nil, nil, err
+	}
+
+	var prev Period
+	if err := txn.Where("period_end<=?", p.Start).Order("period_end desc").Limit(1).Find(&prev).Error; err != nil {
+		return p, nil, nil, err
+	}
+
+	var sessions Sessions
+	if err := txn.Where("period=?", p.ID).Order("finished").Find(&sessions).Error; err != nil {
+		return p, nil, nil, err
+	}
+
+	return p, prev.Readings, sessions, nil
+}
+
+// MeterEnergy returns the charge meter difference of the loadpoints in kWh, false if readings are incomplete
+func (p Period) MeterEnergy(opening Readings, loadpoints ...string) (float64, bool) {
+	var res float64
+
+	for _, lp := range loadpoints {
+		start, ok := opening[lp]
+		end, ok2 := p.Readings[lp]
+		if !ok || !ok2 {
+			return 0, false
+		}
+		res += end - start
+	}
+
+	return res, true
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBillingPeriods(t *testing.T) {
+	db, err := serverdb.New("sqlite", filepath.Join(t.TempDir(), "evcc.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(new(Session), new(Period)))
+
+	jan := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+	mar := feb.AddDate(0, 1, 0)
+
+	billed := Session{Loadpoint: "lp1", ChargedEnergy: 10, Finished: jan.Add(time.Hour)}
+	active := Session{Loadpoint: "lp1", ChargedEnergy: 5}
+	require.NoError(t, db.Create(&billed).Error)
+	require.NoError(t, db.Create(&active).Error)
+
+	p1, err := ClosePeriod(db, jan, feb, Readings{"lp1": 100})
+	require.NoError(t, err)
+
+	// frozen sessions are not reassigned by persisting
+	require.NoError(t, db.First(&billed, billed.ID).Error)
+	assert.Equal(t, p1.ID, billed.Period)
+	billed.Period = 0
+	require.NoError(t, db.Save(&billed).Error)
+
+	_, err = Annotate(db, billed.ID, Annotation{Note: "late"})
+	assert.ErrorIs(t, err, ErrFrozen)
+
+	// active session is billed in the period it finishes
+	active.Finished = feb.Add(time.Hour)
+	require.NoError(t, db.Save(&active).Error)
+
+	p2, err := ClosePeriod(db, feb, mar, Readings{"lp1": 130})
+	require.NoError(t, err)
+
+	last, err := LastPeriod(db)
+	require.NoError(t, err)
+	assert.Equal(t, p2.ID, last.ID)
+
+	periods, err := Periods(db)
+	require.NoError(t, err)
+	assert.Len(t, periods, 2)
+
+	p, opening, sessions, err := PeriodSessions(db, p2.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, active.ID, sessions[0].ID)
+	assert.Equal(t, Readings{"lp1": 100}, opening)
+
+	energy, ok := p.MeterEnergy(opening, "lp1")
+	assert.True(t, ok)
+	assert.Equal(t, 30.0, energy)
+
+	// first period has no opening readings
+	_, opening, sessions, err = PeriodSessions(db, p1.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, billed.ID, sessions[0].ID)
+
+	_, ok = p1.MeterEnergy(opening, "lp1")
+	assert.False(t, ok)
+}
+
+func TestReadingsAt(t *testing.T) {
+	db, err := serverdb.New("sqlite", filepath.Join(t.TempDir(), "evcc.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(new(Session)))
+
+	feb := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, s := range []Session{
+		{Loadpoint: "lp1", Created: feb.Add(-3 * time.Hour), Finished: feb.Add(-2 * time.Hour), MeterStart: 90, MeterStop: 100},
+		{Loadpoint: "lp2", Created: feb.Add(-3 * time.Hour), Finished: feb.Add(-2 * time.Hour), MeterStart: 10, MeterStop: 20},
+		// in progress at period end
+		{Loadpoint: "lp2", Created: feb.Add(-time.Hour), Finished: feb.Add(time.Hour), MeterStart: 20, MeterStop: 30},
+		// after period end
+		{Loadpoint: "lp1", Created: feb.Add(time.Hour), Finished: feb.Add(2 * time.Hour), MeterStart: 100, MeterStop: 110},
+	} {
+		s := s
+		require.NoError(t, db.Create(&s).Error)
+	}
+
+	res, err := ReadingsAt(db, feb)
+	require.NoError(t, err)
+	assert.Equal(t, Readings{"lp1": 100, "lp2": 20}, res)
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"sync"
 	"time"
 
 	serverdb "github.com/evcc-io/evcc/server/db"
@@ -18,6 +19,7 @@ type DB struct {
 type Database interface {
 	Session(startEnergy float64) *Session
 	Persist(session interface{})
+	Record(typ, detail string)
 }
 
 // New creates a database storage driver
@@ -48,3 +50,53 @@ func (s *DB) Persist(session interface{}) {
 		s.log.ERROR.Printf("persist: %v", err)
 	}
 }
+
+// Record queues an event for the loadpoint's timeline. The event is written
+// asynchronously, so callers may hold locks.
+func (s *DB) Record(typ, detail string) {
+	event := Event{
+		Created:   time.Now(),
+		Loadpoint: s.name,
+		Type:      typ,
+		Detail:    detail,
+	}
+
+	recorderOnce.Do(func() {
+		recorder = make(chan Event, recorderSize)
+		go s.recordEvents(recorder)
+	})
+
+	select {
+	case recorder <- event:
+	default:
+		s.log.ERROR.Printf("record: queue full, dropping %s event", typ)
+	}
+}
+
+const recorderSize = 100
+
+var (
+	recorderOnce sync.Once
+	recorder     chan Event
+)
+
+// recordEvents writes the queued timeline events in batches
+func (s *DB) recordEvents(events <-chan Event) {
+	for event := range events {
+		batch := []Event{event}
+
+	DRAIN:
+		for {
+			select {
+			case event := <-events:
+				batch = append(batch, event)
+			default:
+				break DRAIN
+			}
+		}
+
+		if err := s.db.Create(&batch).Error; err != nil {
+			s.log.ERROR.Printf("record: %v", err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Event is a controller decision on a loadpoint's timeline
+type Event struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Created   time.Time `json:"created" gorm:"index"`
+	Loadpoint string    `json:"loadpoint" gorm:"index"`
+	Type      string    `json:"type"`
+	Detail    string    `json:"detail,omitempty"`
+}
+
+// TableName implements gorm.Tabler
+func (Event) TableName() string {
+	return "events"
+}
+
+// EventQuery filters the timeline
+type EventQuery struct {
+	From, To   time.Time // created within range
+	Loadpoint  string
+	Loadpoints []string // restrict to loadpoints, e.g. for tenants
+	Type       string
+	Limit      int // maximum number of events, MaxLimit if zero
+}
+
+// Find returns the matching events, latest first
+func (q EventQuery) Find(db *gorm.DB) ([]Event, error) {
+	txn := db.Order("created desc, id desc")
+
+	if !q.From.IsZero() {
+		txn = txn.Where("created >= ?", q.From)
+	}
+	if !q.To.IsZero() {
+		txn = txn.Where("created < ?", q.To)
+	}
+	if q.Loadpoint != "" {
+		txn = txn.Where("loadpoint = ?", q.Loadpoint)
+	}
+	if q.Loadpoints != nil {
+		txn = txn.Where("loadpoint IN ?", q.Loadpoints)
+	}
+	if q.Type != "" {
+		txn = txn.Where("type = ?", q.Type)
+	}
+
+	limit := q.Limit
+	if limit <= 0 || limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	res := make([]Event, 0)
+	err := txn.Limit(limit).Find(&res).Error
+
+	return res, err
+}
+
+// PurgeEvents deletes events created before the given time
+func PurgeEvents(db *gorm.DB, before time.Time) (int64, error) {
+	txn := db.Where("created < ?", before).Delete(new(Event))
+	return txn.RowsAffected, txn.Error
+}
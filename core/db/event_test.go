@@ -0,0 +1,66 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents(t *testing.T) {
+	db, err := serverdb.New("sqlite", filepath.Join(t.TempDir(), "evcc.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(new(Event)))
+
+	now := time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, e := range []Event{
+		{Created: now.Add(-48 * time.Hour), Loadpoint: "lp1", Type: "mode", Detail: "pv"},
+		{Created: now.Add(-time.Hour), Loadpoint: "lp1", Type: "pause", Detail: "insufficientSurplus 500W"},
+		{Created: now, Loadpoint: "lp2", Type: "enable", Detail: "now"},
+	} {
+		e := e
+		require.NoError(t, db.Create(&e).Error)
+	}
+
+	res, err := EventQuery{}.Find(db)
+	require.NoError(t, err)
+	require.Len(t, res, 3)
+	assert.Equal(t, "lp2", res[0].Loadpoint, "latest first")
+
+	res, err = EventQuery{Loadpoint: "lp1", From: now.Add(-24 * time.Hour)}.Find(db)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "pause", res[0].Type)
+
+	res, err = EventQuery{Loadpoints: []string{"lp2"}, Type: "mode"}.Find(db)
+	require.NoError(t, err)
+	assert.Len(t, res, 0)
+
+	n, err := PurgeEvents(db, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	res, err = EventQuery{Limit: 1}.Find(db)
+	require.NoError(t, err)
+	assert.Len(t, res, 1)
+}
+
+func TestRecord(t *testing.T) {
+	db, err := serverdb.New("sqlite", filepath.Join(t.TempDir(), "evcc.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(new(Event)))
+
+	s := &DB{log: util.NewLogger("foo"), db: db, name: "lp1"}
+	s.Record("mode", "pv")
+	s.Record("pause", "now")
+
+	assert.Eventually(t, func() bool {
+		res, err := EventQuery{Loadpoint: "lp1"}.Find(db)
+		return err == nil && len(res) == 2
+	}, time.Second, 10*time.Millisecond)
+}
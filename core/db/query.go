@@ -0,0 +1,188 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// likeEscaper escapes LIKE wildcards for use with ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// MaxLimit is the maximum number of sessions returned per page
+const MaxLimit = 1000
+
+// sortColumns maps sortable session fields to database columns
+var sortColumns = map[string]string{
+	"created":       "created",
+	"finished":      "finished",
+	"loadpoint":     "loadpoint",
+	"vehicle":       "vehicle",
+	"odometer":      "odometer",
+	"chargedEnergy": "charged_kwh",
+}
+
+// SessionQuery filters, sorts and paginates charging sessions
+type SessionQuery struct {
+	From, To   time.Time // created within range
+	Loadpoint  string
+	Loadpoints []string // restrict to loadpoints, e.g. for tenants
+	Vehicle    string
+	Tag        string
+	Sort       string // session field, descending if prefixed with -
+	Limit      int    // page size, unlimited if zero
+	Cursor     string // opaque cursor of the previous page's last session
+}
+
+// cursor identifies the position after the last session of a page
+type cursor struct {
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+func (q SessionQuery) sort() (string, bool, error) {
+	field, desc := q.Sort, false
+	if field == "" {
+		field, desc = "created", true
+	}
+
+	if strings.HasPrefix(field, "-") {
+		field, desc = field[1:], true
+	}
+
+	column, ok := sortColumns[field]
+	if !ok {
+		return "", false, fmt.Errorf("invalid sort: %s", q.Sort)
+	}
+
+	return column, desc, nil
+}
+
+// sortValue returns the session's value of the sort column
+func sortValue(s Session, column string) string {
+	switch column {
+	case "created":
+		return s.Created.Format(time.RFC3339Nano)
+	case "finished":
+		return s.Finished.Format(time.RFC3339Nano)
+	case "loadpoint":
+		return s.Loadpoint
+	case "vehicle":
+		return s.Vehicle
+	case "odometer":
+		return strconv.FormatFloat(s.Odometer, 'f', -1, 64)
+	default:
+		return strconv.FormatFloat(s.ChargedEnergy, 'f', -1, 64)
+	}
+}
+
+// parseSortValue converts the cursor value to the sort column's type
+func parseSortValue(column, value string) (any, error) {
+	switch column {
+	case "created", "finished":
+		return time.Parse(time.RFC3339Nano, value)
+	case "loadpoint", "vehicle":
+		return value, nil
+	default:
+		return strconv.ParseFloat(value, 64)
+	}
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(b, &c)
+	}
+	if err != nil {
+		return c, errors.New("invalid cursor")
+	}
+
+	return c, nil
+}
+
+// Find returns the matching sessions and the cursor for the next page if more sessions are available
+func (q SessionQuery) Find(txn *gorm.DB) (Sessions, string, error) {
+	column, desc, err := q.sort()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if q.Limit < 0 || q.Limit > MaxLimit {
+		return nil, "", fmt.Errorf("invalid limit: %d not in [0..%d]", q.Limit, MaxLimit)
+	}
+
+	txn = txn.Where("charged_kwh>=0.05")
+
+	if !q.From.IsZero() {
+		txn = txn.Where("created>=?", q.From)
+	}
+	if !q.To.IsZero() {
+		txn = txn.Where("created<?", q.To)
+	}
+	if q.Loadpoint != "" {
+		txn = txn.Where("loadpoint=?", q.Loadpoint)
+	}
+	if q.Loadpoints != nil {
+		txn = txn.Where("loadpoint IN ?", q.Loadpoints)
+	}
+	if q.Vehicle != "" {
+		txn = txn.Where("vehicle=?", q.Vehicle)
+	}
+	if q.Tag != "" {
+		txn = txn.Where(`','||tags||',' LIKE ? ESCAPE '\'`, "%,"+likeEscaper.Replace(q.Tag)+",%")
+	}
+
+	// sessions after cursor position, id breaks ties between equal sort values
+	op, order := ">", "asc"
+	if desc {
+		op, order = "<", "desc"
+	}
+
+	if q.Cursor != "" {
+		c, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		val, err := parseSortValue(column, c.Value)
+		if err != nil {
+			return nil, "", errors.New("invalid cursor")
+		}
+
+		txn = txn.Where(fmt.Sprintf("(%[1]s%[2]s?) OR (%[1]s=? AND id%[2]s?)", column, op), val, val, c.ID)
+	}
+
+	txn = txn.Order(fmt.Sprintf("%s %s, id %s", column, order, order))
+
+	// fetch one more to detect if next page exists
+	if q.Limit > 0 {
+		txn = txn.Limit(q.Limit + 1)
+	}
+
+	var res Sessions
+	if err := txn.Find(&res).Error; err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if q.Limit > 0 && len(res) > q.Limit {
+		res = res[:q.Limit]
+		last := res[len(res)-1]
+		next = encodeCursor(cursor{Value: sortValue(last, column), ID: last.ID})
+	}
+
+	return res, next, nil
+}
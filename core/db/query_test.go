@@ -0,0 +1,57 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionQuery(t *testing.T) {
+	db, err := serverdb.New("sqlite", filepath.Join(t.TempDir(), "evcc.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(new(Session)))
+
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		vehicle := "a"
+		if i%2 == 1 {
+			vehicle = "b"
+		}
+
+		require.NoError(t, db.Create(&Session{
+			Created:       start.AddDate(0, 0, i),
+			Vehicle:       vehicle,
+			ChargedEnergy: 10,
+		}).Error)
+	}
+
+	// paginate newest first
+	res, next, err := SessionQuery{Limit: 2}.Find(db)
+	require.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.True(t, res[0].Created.Equal(start.AddDate(0, 0, 4)))
+	assert.NotEmpty(t, next)
+
+	res, next, err = SessionQuery{Limit: 2, Cursor: next}.Find(db)
+	require.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.True(t, res[0].Created.Equal(start.AddDate(0, 0, 2)))
+
+	res, next, err = SessionQuery{Limit: 2, Cursor: next}.Find(db)
+	require.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Empty(t, next)
+
+	// filter and sort ties by id
+	res, _, err = SessionQuery{Vehicle: "a", Sort: "chargedEnergy", From: start.AddDate(0, 0, 1)}.Find(db)
+	require.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Less(t, res[0].ID, res[1].ID)
+
+	_, _, err = SessionQuery{Sort: "foo"}.Find(db)
+	assert.Error(t, err)
+}
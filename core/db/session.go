@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql/driver"
 	"encoding/csv"
 	"fmt"
 	"io"
@@ -12,12 +13,12 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util/locale"
 	"github.com/fatih/structs"
-	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"gorm.io/gorm"
 )
 
 // Session is a single charging session
 type Session struct {
-	ID            uint      `json:"-" csv:"-" gorm:"primarykey"`
+	ID            uint      `json:"id" csv:"-" gorm:"primarykey"`
 	Created       time.Time `json:"created"`
 	Finished      time.Time `json:"finished"`
 	Loadpoint     string    `json:"loadpoint"`
@@ -27,6 +28,125 @@ type Session struct {
 	MeterStart    float64   `json:"meterStart" csv:"Meter Start (kWh)" gorm:"column:meter_start_kwh"`
 	MeterStop     float64   `json:"meterStop" csv:"Meter Stop (kWh)" gorm:"column:meter_end_kwh"`
 	ChargedEnergy float64   `json:"chargedEnergy" csv:"Charged Energy (kWh)" gorm:"column:charged_kwh"`
+	GridEnergy    float64   `json:"gridEnergy" csv:"Grid Energy (kWh)" gorm:"column:grid_kwh"` // charged energy imported from the grid
+	Tags          Tags      `json:"tags"`
+	Note          string    `json:"note"`
+	Rate          string    `json:"rate"`
+	PricePerKWh   float64   `json:"pricePerKWh" csv:"Price (per kWh)" gorm:"column:price_per_kwh"`
+	Price         float64   `json:"price"`
+	Cost          float64   `json:"cost"`                                      // energy cost at grid price and feed-in price for self-produced energy
+	GridCost      float64   `json:"gridCost"`                                  // energy cost if charged from grid only
+	Period        uint      `json:"period,omitempty" csv:"-" gorm:"<-:create"` // billing period once frozen
+}
+
+// Annotation are the tags and note attached to a session, e.g. for expense reporting
+type Annotation struct {
+	Tags Tags   `json:"tags"`
+	Note string `json:"note"`
+}
+
+// Annotate attaches the annotation to the session
+func (t *Session) Annotate(a Annotation) {
+	t.Tags = a.Tags
+	t.Note = a.Note
+}
+
+// Annotate attaches the annotation to the persisted session with given id
+func Annotate(txn *gorm.DB, id uint, a Annotation) (Session, error) {
+	var res Session
+	if err := txn.First(&res, id).Error; err != nil {
+		return res, err
+	}
+
+	if res.Period != 0 {
+		return res, ErrFrozen
+	}
+
+	res.Annotate(a)
+
+	return res, txn.Save(&res).Error
+}
+
+// Tags are session tags like business/private or project codes
+type Tags []string
+
+// String implements the fmt.Stringer interface
+func (t Tags) String() string {
+	return strings.Join(t, ",")
+}
+
+// GormDataType stores tags as comma-separated string
+func (Tags) GormDataType() string {
+	return "string"
+}
+
+// Value implements the driver.Valuer interface
+func (t Tags) Value() (driver.Value, error) {
+	for _, tag := range t {
+		if tag == "" || strings.Contains(tag, ",") {
+			return nil, fmt.Errorf("invalid tag: %q", tag)
+		}
+	}
+	return t.String(), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (t *Tags) Scan(val any) error {
+	var s string
+
+	switch v := val.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("invalid tags: %v", val)
+	}
+
+	*t = nil
+	if s != "" {
+		*t = strings.Split(s, ",")
+	}
+
+	return nil
+}
+
+// Has checks if the tag is set
+func (t Tags) Has(tag string) bool {
+	for _, v := range t {
+		if strings.EqualFold(v, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyRate prices the charged energy with the given rate
+func (t *Session) ApplyRate(rate string, pricePerKWh float64) {
+	t.Rate = rate
+	t.PricePerKWh = pricePerKWh
+	t.Price = t.ChargedEnergy * pricePerKWh
+}
+
+// ApplyCost scales the cost accumulated while charging the given energy to the session's charged energy
+func (t *Session) ApplyCost(energy, cost, gridCost float64) {
+	if energy <= 0 {
+		return
+	}
+
+	scale := t.ChargedEnergy / energy
+	t.Cost = cost * scale
+	t.GridCost = gridCost * scale
+}
+
+// ApplyGridEnergy scales the grid energy accumulated while charging the given energy to the session's charged energy
+func (t *Session) ApplyGridEnergy(energy, gridEnergy float64) {
+	if energy <= 0 {
+		return
+	}
+
+	t.GridEnergy = gridEnergy * t.ChargedEnergy / energy
 }
 
 // Stop stops charging session with end meter reading and due total amount
@@ -44,9 +164,9 @@ type Sessions []Session
 var _ api.CsvWriter = (*Sessions)(nil)
 
 func (t *Sessions) writeHeader(ctx context.Context, ww *csv.Writer) {
-	localizer := locale.Localizer
+	localizer := locale.NewLocalizer()
 	if val := ctx.Value(locale.Locale).(string); val != "" {
-		localizer = i18n.NewLocalizer(locale.Bundle, val, locale.Language)
+		localizer = locale.NewLocalizer(val)
 	}
 
 	var row []string
@@ -73,6 +193,17 @@ func (t *Sessions) writeHeader(ctx context.Context, ww *csv.Writer) {
 	_ = ww.Write(row)
 }
 
+// In returns the sessions with timestamps converted to loc
+func (t Sessions) In(loc *time.Location) Sessions {
+	res := make(Sessions, 0, len(t))
+	for _, s := range t {
+		s.Created = s.Created.In(loc)
+		s.Finished = s.Finished.In(loc)
+		res = append(res, s)
+	}
+	return res
+}
+
 func (t *Sessions) writeRow(ww *csv.Writer, r Session) {
 	var row []string
 	for _, f := range structs.Fields(r) {
@@ -87,7 +218,7 @@ func (t *Sessions) writeRow(ww *csv.Writer, r Session) {
 			val = strconv.FormatFloat(v, 'f', 3, 64)
 		case time.Time:
 			if !v.IsZero() {
-				val = v.Local().Format("2006-01-02 15:04:05")
+				val = v.Format("2006-01-02 15:04:05")
 			}
 		default:
 			val = fmt.Sprintf("%v", f.Value())
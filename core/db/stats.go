@@ -0,0 +1,141 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// SessionStats are the aggregated sessions of a group
+type SessionStats struct {
+	Group         string  `json:"group"`
+	Sessions      int     `json:"sessions"`
+	ChargedEnergy float64 `json:"chargedEnergy"`
+	Price         float64 `json:"price"`
+}
+
+// SessionSavings are the aggregated savings of a group compared to a reference price and to charging from grid only
+type SessionSavings struct {
+	Group            string  `json:"group"`
+	Sessions         int     `json:"sessions"`
+	ChargedEnergy    float64 `json:"chargedEnergy"`
+	Cost             float64 `json:"cost"`
+	GridCost         float64 `json:"gridCost"`
+	ReferenceCost    float64 `json:"referenceCost"`
+	SavingsGrid      float64 `json:"savingsGrid"`
+	SavingsReference float64 `json:"savingsReference"`
+}
+
+// groupKeys returns the group keys of a session by total, session, loadpoint, vehicle or tag
+func groupKeys(group string) (func(Session) []string, error) {
+	switch group {
+	case "total":
+		return func(s Session) []string { return []string{""} }, nil
+	case "session":
+		return func(s Session) []string { return []string{strconv.FormatUint(uint64(s.ID), 10)} }, nil
+	case "loadpoint":
+		return func(s Session) []string { return []string{s.Loadpoint} }, nil
+	case "vehicle":
+		return func(s Session) []string { return []string{s.Vehicle} }, nil
+	case "tag":
+		return func(s Session) []string {
+			if len(s.Tags) == 0 {
+				return []string{""}
+			}
+			return s.Tags
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid group: %s", group)
+	}
+}
+
+// groupLess orders groups alphabetically, sessions by id
+func groupLess(group string) func(a, b string) bool {
+	if group == "session" {
+		return func(a, b string) bool {
+			ia, _ := strconv.ParseUint(a, 10, 64)
+			ib, _ := strconv.ParseUint(b, 10, 64)
+			return ia < ib
+		}
+	}
+	return func(a, b string) bool { return a < b }
+}
+
+// Stats groups sessions by total, session, loadpoint, vehicle or tag. Sessions with multiple tags are counted for each tag.
+func (t Sessions) Stats(group string) ([]SessionStats, error) {
+	keys, err := groupKeys(group)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]*SessionStats)
+	for _, s := range t {
+		for _, key := range keys(s) {
+			st, ok := stats[key]
+			if !ok {
+				st = &SessionStats{Group: key}
+				stats[key] = st
+			}
+
+			st.Sessions++
+			st.ChargedEnergy += s.ChargedEnergy
+			st.Price += s.Price
+		}
+	}
+
+	res := make([]SessionStats, 0, len(stats))
+	for _, st := range stats {
+		res = append(res, *st)
+	}
+
+	less := groupLess(group)
+	sort.Slice(res, func(i, j int) bool {
+		return less(res[i].Group, res[j].Group)
+	})
+
+	return res, nil
+}
+
+// Savings groups the sessions' savings like Stats. The reference cost prices the charged energy at the reference price.
+// Sessions recorded before cost tracking are skipped.
+func (t Sessions) Savings(group string, reference float64) ([]SessionSavings, error) {
+	keys, err := groupKeys(group)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]*SessionSavings)
+	for _, s := range t {
+		if s.Cost == 0 && s.GridCost == 0 {
+			continue
+		}
+
+		for _, key := range keys(s) {
+			st, ok := stats[key]
+			if !ok {
+				st = &SessionSavings{Group: key}
+				stats[key] = st
+			}
+
+			st.Sessions++
+			st.ChargedEnergy += s.ChargedEnergy
+			st.Cost += s.Cost
+			st.GridCost += s.GridCost
+			st.ReferenceCost += s.ChargedEnergy * reference
+		}
+	}
+
+	res := make([]SessionSavings, 0, len(stats))
+	for _, st := range stats {
+		st.SavingsGrid = st.GridCost - st.Cost
+		st.SavingsReference = st.ReferenceCost - st.Cost
+		res = append(res, *st)
+	}
+
+	less := groupLess(group)
+	sort.Slice(res, func(i, j int) bool {
+		return less(res[i].Group, res[j].Group)
+	})
+
+	return res, nil
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionAnnotation(t *testing.T) {
+	db, err := serverdb.New("sqlite", filepath.Join(t.TempDir(), "evcc.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(new(Session)))
+
+	for _, vehicle := range []string{"a", "b", "a"} {
+		require.NoError(t, db.Create(&Session{Vehicle: vehicle, ChargedEnergy: 10}).Error)
+	}
+
+	// live annotation
+	s := Session{Vehicle: "b", ChargedEnergy: 5}
+	s.Annotate(Annotation{Tags: Tags{"private"}})
+	require.NoError(t, db.Create(&s).Error)
+
+	// retroactive annotation
+	res, err := Annotate(db, 1, Annotation{Tags: Tags{"business", "p-42"}, Note: "customer visit"})
+	require.NoError(t, err)
+	assert.Equal(t, "customer visit", res.Note)
+
+	_, err = Annotate(db, 2, Annotation{Tags: Tags{"business"}})
+	require.NoError(t, err)
+
+	_, err = Annotate(db, 3, Annotation{Tags: Tags{"a,b"}})
+	assert.Error(t, err)
+
+	sessions, _, err := SessionQuery{Tag: "business", Sort: "created"}.Find(db)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, Tags{"business", "p-42"}, sessions[0].Tags)
+
+	// wildcards match literally
+	for _, tag := range []string{"%", "p_42", "busines_"} {
+		sessions, _, err = SessionQuery{Tag: tag}.Find(db)
+		require.NoError(t, err)
+		assert.Empty(t, sessions, tag)
+	}
+
+	all, _, err := SessionQuery{}.Find(db)
+	require.NoError(t, err)
+
+	stats, err := all.Stats("tag")
+	require.NoError(t, err)
+	assert.Equal(t, []SessionStats{
+		{Group: "", Sessions: 1, ChargedEnergy: 10},
+		{Group: "business", Sessions: 2, ChargedEnergy: 20},
+		{Group: "p-42", Sessions: 1, ChargedEnergy: 10},
+		{Group: "private", Sessions: 1, ChargedEnergy: 5},
+	}, stats)
+
+	_, err = all.Stats("foo")
+	assert.Error(t, err)
+}
+
+func TestSessionSavings(t *testing.T) {
+	sessions := Sessions{
+		{ID: 10, Vehicle: "a", ChargedEnergy: 10},
+		{ID: 2, Vehicle: "a", ChargedEnergy: 20},
+		{ID: 3, Vehicle: "b"},
+	}
+
+	// cost accumulated for 8kWh is scaled to the metered 10kWh
+	sessions[0].ApplyCost(8, 1.6, 2.4)
+	assert.InDelta(t, 2.0, sessions[0].Cost, 1e-6)
+	assert.InDelta(t, 3.0, sessions[0].GridCost, 1e-6)
+
+	sessions[1].ApplyCost(20, 2, 6)
+	sessions[2].ApplyCost(0, 0, 0)
+
+	res, err := sessions.Savings("total", 0.4)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, 2, res[0].Sessions, "sessions without cost are skipped")
+	assert.InDelta(t, 12.0, res[0].ReferenceCost, 1e-6)
+	assert.InDelta(t, 5.0, res[0].SavingsGrid, 1e-6)
+	assert.InDelta(t, 8.0, res[0].SavingsReference, 1e-6)
+
+	res, err = sessions.Savings("session", 0.4)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, "2", res[0].Group)
+	assert.InDelta(t, 6.0, res[0].SavingsReference, 1e-6)
+	assert.Equal(t, "10", res[1].Group)
+	assert.InDelta(t, 2.0, res[1].SavingsReference, 1e-6)
+}
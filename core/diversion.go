@@ -0,0 +1,196 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+// DiversionConfig defines a consumer receiving surplus not used by vehicles and battery, e.g. a hot water element or smart plug
+type DiversionConfig struct {
+	Title      string           `mapstructure:"title"`      // display name
+	Power      *provider.Config `mapstructure:"power"`      // power setpoint in W for continuously controllable consumers
+	Enable     *provider.Config `mapstructure:"enable"`     // on/off control
+	Meter      *provider.Config `mapstructure:"meter"`      // measured power in W, setpoint is assumed if not metered
+	MinPower   float64          `mapstructure:"minPower"`   // minimum power in W, consumption of on/off consumers
+	MaxPower   float64          `mapstructure:"maxPower"`   // maximum power in W of continuously controllable consumers
+	Ramp       float64          `mapstructure:"ramp"`       // maximum power increase per cycle in W
+	Hysteresis float64          `mapstructure:"hysteresis"` // surplus margin in W for switching on and off
+}
+
+// diverter is a surplus consumer in the diversion chain
+type diverter struct {
+	log      *util.Logger
+	power    func(int64) error
+	enable   func(bool) error
+	meter    func() (float64, error)
+	enabled  bool
+	current  float64 // current power setpoint
+	measured float64 // measured power, current setpoint if not metered
+	DiversionConfig
+}
+
+// newDiverterFromConfig creates a diverter
+func newDiverterFromConfig(log *util.Logger, cc DiversionConfig) (*diverter, error) {
+	d := &diverter{
+		log:             log,
+		DiversionConfig: cc,
+	}
+
+	if cc.Power == nil && cc.Enable == nil {
+		return nil, errors.New("missing power or enable control")
+	}
+
+	if cc.Power != nil {
+		power, err := provider.NewIntSetterFromConfig("power", *cc.Power)
+		if err != nil {
+			return nil, fmt.Errorf("power: %w", err)
+		}
+		d.power = power
+
+		if cc.MaxPower < cc.MinPower {
+			return nil, errors.New("maxPower must be larger than minPower")
+		}
+	} else {
+		// on/off consumers always draw minimum power
+		d.MaxPower = cc.MinPower
+	}
+
+	if cc.Enable != nil {
+		enable, err := provider.NewBoolSetterFromConfig("enable", *cc.Enable)
+		if err != nil {
+			return nil, fmt.Errorf("enable: %w", err)
+		}
+		d.enable = enable
+	}
+
+	if cc.Meter != nil {
+		meter, err := provider.NewFloatGetterFromConfig(*cc.Meter)
+		if err != nil {
+			return nil, fmt.Errorf("meter: %w", err)
+		}
+		d.meter = meter
+	}
+
+	if d.MaxPower <= 0 {
+		return nil, errors.New("missing power limits")
+	}
+
+	return d, nil
+}
+
+// measure updates the consumer's measured power
+func (d *diverter) measure() {
+	d.measured = d.current
+	if d.meter == nil {
+		return
+	}
+
+	power, err := d.meter()
+	if err != nil {
+		d.log.ERROR.Printf("diversion %s: meter: %v", d.Title, err)
+		return
+	}
+
+	d.measured = power
+}
+
+// drawn returns the power the consumer is expected to draw at the target setpoint.
+// Consumers not following their unchanged setpoint, e.g. a heater switched off by its thermostat, draw the measured power.
+func (d *diverter) drawn(target, previous float64) float64 {
+	if d.meter != nil && target == previous {
+		return math.Min(target, d.measured)
+	}
+	return target
+}
+
+// target calculates the power setpoint for the available surplus
+func (d *diverter) target(available float64) float64 {
+	if d.enabled {
+		if available < d.MinPower-d.Hysteresis {
+			return 0
+		}
+	} else if available < d.MinPower+d.Hysteresis {
+		return 0
+	}
+
+	target := math.Min(math.Max(available, d.MinPower), d.MaxPower)
+
+	// ramp up only, reductions apply immediately to avoid grid import
+	if d.Ramp > 0 && target > d.current && d.current > 0 {
+		target = math.Min(target, d.current+d.Ramp)
+	}
+
+	return target
+}
+
+// set applies the power setpoint
+func (d *diverter) set(target float64) error {
+	if on := target > 0; d.enable != nil && on != d.enabled {
+		if err := d.enable(on); err != nil {
+			return err
+		}
+	}
+
+	if d.power != nil && target != d.current {
+		if err := d.power(int64(target)); err != nil {
+			return err
+		}
+	}
+
+	if d.enabled != (target > 0) {
+		d.log.DEBUG.Printf("diversion %s: %.0fW", d.Title, target)
+	}
+
+	d.enabled = target > 0
+	d.current = target
+
+	return nil
+}
+
+// DiversionStatus is the published state of a diversion consumer
+type DiversionStatus struct {
+	Title string  `json:"title"`
+	Power float64 `json:"power"`
+}
+
+// divertedPower returns the total power measured at diversion consumers
+func (site *Site) divertedPower() float64 {
+	var res float64
+	for _, d := range site.diverters {
+		res += d.measured
+	}
+	return res
+}
+
+// updateDiversion distributes the surplus not used by vehicles and battery along the diversion chain.
+// Grid export plus the power measured at diversion consumers is available, battery discharge is treated as deficit.
+func (site *Site) updateDiversion() {
+	if len(site.diverters) == 0 {
+		return
+	}
+
+	for _, d := range site.diverters {
+		d.measure()
+	}
+
+	available := site.divertedPower() - site.gridPower - math.Max(0, site.batteryPower)
+
+	var res []DiversionStatus
+	for _, d := range site.diverters {
+		previous := d.current
+		target := d.target(available)
+
+		if err := d.set(target); err != nil {
+			site.log.ERROR.Printf("diversion %s: %v", d.Title, err)
+		}
+
+		available -= d.drawn(d.current, previous)
+		res = append(res, DiversionStatus{Title: d.Title, Power: d.measured})
+	}
+
+	site.publish("diversion", res)
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiversionChain(t *testing.T) {
+	log := util.NewLogger("foo")
+
+	var heaterPower int64
+	heater := &diverter{
+		log:   log,
+		power: func(p int64) error { heaterPower = p; return nil },
+		DiversionConfig: DiversionConfig{
+			Title:      "heater",
+			MinPower:   500,
+			MaxPower:   3000,
+			Ramp:       1000,
+			Hysteresis: 100,
+		},
+	}
+
+	var plugOn bool
+	plug := &diverter{
+		log:    log,
+		enable: func(b bool) error { plugOn = b; return nil },
+		DiversionConfig: DiversionConfig{
+			Title:    "plug",
+			MinPower: 200,
+			MaxPower: 200,
+		},
+	}
+
+	site := &Site{
+		log:       log,
+		diverters: []*diverter{heater, plug},
+	}
+
+	// heater below minimum and hysteresis, plug uses surplus
+	site.gridPower = -550
+	site.updateDiversion()
+	assert.Equal(t, 0.0, heater.current)
+	assert.True(t, plugOn)
+
+	// heater takes precedence and starts without ramp limit
+	site.gridPower = -1000
+	site.updateDiversion()
+	assert.Equal(t, int64(1200), heaterPower)
+	assert.False(t, plugOn)
+
+	// heater ramps up, plug gets remainder
+	site.gridPower = -2000
+	site.updateDiversion()
+	assert.Equal(t, int64(2200), heaterPower)
+	assert.True(t, plugOn)
+
+	// deficit including battery discharge reduces heater immediately and drops plug
+	site.gridPower = 500
+	site.batteryPower = 100
+	site.updateDiversion()
+	assert.Equal(t, int64(1800), heaterPower)
+	assert.False(t, plugOn)
+
+	// heater keeps running within hysteresis
+	heater.current, heaterPower = 500, 500
+	site.gridPower = 50
+	site.batteryPower = 0
+	site.updateDiversion()
+	assert.Equal(t, int64(500), heaterPower)
+
+	// heater drops below hysteresis, plug uses remaining surplus
+	site.gridPower = 200
+	site.updateDiversion()
+	assert.Equal(t, int64(0), heaterPower)
+	assert.True(t, plugOn)
+}
+
+func TestDiversionMeasured(t *testing.T) {
+	log := util.NewLogger("foo")
+
+	var heaterMeasured float64
+	heater := &diverter{
+		log:   log,
+		power: func(p int64) error { return nil },
+		meter: func() (float64, error) { return heaterMeasured, nil },
+		DiversionConfig: DiversionConfig{
+			Title:    "heater",
+			MinPower: 500,
+			MaxPower: 2000,
+		},
+	}
+
+	var plugOn bool
+	plug := &diverter{
+		log:    log,
+		enable: func(b bool) error { plugOn = b; return nil },
+		DiversionConfig: DiversionConfig{
+			Title:    "plug",
+			MinPower: 200,
+			MaxPower: 200,
+		},
+	}
+
+	site := &Site{
+		log:       log,
+		diverters: []*diverter{heater, plug},
+	}
+
+	// heater uses all surplus
+	site.gridPower = -2100
+	site.updateDiversion()
+	assert.Equal(t, 2000.0, heater.current)
+	assert.False(t, plugOn)
+
+	// heater switched off by its thermostat, surplus goes to plug
+	heaterMeasured = 0
+	site.gridPower = -2100
+	site.updateDiversion()
+	assert.Equal(t, 2000.0, heater.current)
+	assert.True(t, plugOn)
+}
@@ -0,0 +1,48 @@
+package forecast
+
+import (
+	"math"
+	"time"
+)
+
+// Slot is a single forecast value
+type Slot struct {
+	Start time.Time `json:"start"`
+	SoC   float64   `json:"soc"`   // battery soc at end of slot in %
+	Power float64   `json:"power"` // expected battery power in W, negative when charging
+}
+
+// Battery forecasts the battery soc in hourly steps over the given horizon.
+// Net power returns the expected excess power (pv minus consumption) for a slot starting at given time.
+// Capacity is in kWh.
+func Battery(start time.Time, soc, capacity float64, horizon time.Duration, net func(time.Time) float64) []Slot {
+	if capacity <= 0 {
+		return nil
+	}
+
+	var res []Slot
+
+	for ts := start.Truncate(time.Hour); ts.Before(start.Add(horizon)); ts = ts.Add(time.Hour) {
+		// partial first slot
+		duration := time.Hour
+		if ts.Before(start) {
+			duration = ts.Add(time.Hour).Sub(start)
+		}
+
+		power := net(ts)
+		soc = math.Max(0, math.Min(100, soc+100*power*duration.Hours()/(capacity*1e3)))
+
+		// battery is idle when full or empty
+		if (soc == 100 && power > 0) || (soc == 0 && power < 0) {
+			power = 0
+		}
+
+		res = append(res, Slot{
+			Start: ts,
+			SoC:   soc,
+			Power: -power,
+		})
+	}
+
+	return res
+}
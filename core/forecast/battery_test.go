@@ -0,0 +1,39 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBattery(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 1kW excess during first 6h, 2kW consumption afterwards
+	net := func(ts time.Time) float64 {
+		if ts.Hour() < 6 {
+			return 1000
+		}
+		return -2000
+	}
+
+	res := Battery(start, 50, 10, 12*time.Hour, net)
+	assert.Len(t, res, 12)
+
+	assert.Equal(t, 60.0, res[0].SoC)
+	assert.Equal(t, -1000.0, res[0].Power)
+
+	// full after 5h
+	assert.Equal(t, 100.0, res[4].SoC)
+	assert.Equal(t, 100.0, res[5].SoC)
+	assert.Equal(t, 0.0, res[5].Power)
+
+	// empty after 5h of discharging
+	assert.Equal(t, 80.0, res[6].SoC)
+	assert.Equal(t, 0.0, res[10].SoC)
+	assert.Equal(t, 0.0, res[11].Power)
+
+	// capacity unknown
+	assert.Nil(t, Battery(start, 50, 0, 12*time.Hour, net))
+}
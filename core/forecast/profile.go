@@ -0,0 +1,101 @@
+package forecast
+
+import (
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+// profileSmoothing is the weight of new samples when updating a slot average
+const profileSmoothing = 0.05
+
+// Profile is an hourly power profile learned from measurements using exponential smoothing.
+// Weekly profiles distinguish the day of week, e.g. for household load.
+type Profile struct {
+	mu     sync.Mutex
+	key    string
+	weekly bool
+	last   int       // slot of the previous sample
+	Slots  []float64 `json:"slots"` // average power per slot in W
+	Valid  []bool    `json:"valid"` // slot has received at least one sample
+}
+
+// NewProfile creates a daily profile persisted under the given settings key
+func NewProfile(key string) *Profile {
+	return newProfile(key, false)
+}
+
+// NewWeeklyProfile creates a weekday-aware profile persisted under the given settings key
+func NewWeeklyProfile(key string) *Profile {
+	return newProfile(key, true)
+}
+
+func newProfile(key string, weekly bool) *Profile {
+	p := &Profile{key: key, weekly: weekly, last: -1}
+
+	// discard stored profile of different layout
+	if err := settings.Json(p.key, p); err != nil || len(p.Slots) != p.size() || len(p.Valid) != p.size() {
+		p.Slots = make([]float64, p.size())
+		p.Valid = make([]bool, p.size())
+	}
+
+	return p
+}
+
+func (p *Profile) size() int {
+	if p.weekly {
+		return 7 * 24
+	}
+	return 24
+}
+
+func (p *Profile) slot(ts time.Time) int {
+	if p.weekly {
+		return int(ts.Weekday())*24 + ts.Hour()
+	}
+	return ts.Hour()
+}
+
+// Add adds a power measurement taken at the given time.
+// Returns true if the previous slot has been completed and the profile should be saved.
+func (p *Profile) Add(ts time.Time, power float64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	slot := p.slot(ts)
+
+	completed := p.last >= 0 && p.last != slot
+	p.last = slot
+
+	if p.Valid[slot] {
+		p.Slots[slot] = (1-profileSmoothing)*p.Slots[slot] + profileSmoothing*power
+	} else {
+		p.Slots[slot] = power
+		p.Valid[slot] = true
+	}
+
+	return completed
+}
+
+// Power returns the expected power at the given time
+func (p *Profile) Power(ts time.Time) float64 {
+	res, _ := p.Expected(ts)
+	return res
+}
+
+// Expected returns the expected power at the given time and if the profile has learned the time slot
+func (p *Profile) Expected(ts time.Time) (float64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	slot := p.slot(ts)
+	return p.Slots[slot], p.Valid[slot]
+}
+
+// Save persists the profile
+func (p *Profile) Save() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_ = settings.SetJson(p.key, p)
+}
@@ -0,0 +1,32 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeeklyProfile(t *testing.T) {
+	p := NewWeeklyProfile("test.weekly")
+
+	// saturday and monday, same hour
+	sat := time.Date(2022, 1, 1, 17, 0, 0, 0, time.UTC)
+	mon := sat.AddDate(0, 0, 2)
+
+	assert.False(t, p.Add(sat, 500))
+
+	res, ok := p.Expected(sat)
+	assert.True(t, ok)
+	assert.Equal(t, 500.0, res)
+
+	_, ok = p.Expected(mon)
+	assert.False(t, ok, "weekdays must be separated")
+
+	// smoothing
+	assert.False(t, p.Add(sat.Add(time.Minute), 1500), "same slot")
+	assert.Equal(t, 550.0, p.Power(sat))
+
+	// save once per completed slot
+	assert.True(t, p.Add(sat.Add(time.Hour), 500), "slot completed")
+}
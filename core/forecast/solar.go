@@ -0,0 +1,251 @@
+package forecast
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+const (
+	biasSmoothing  = 0.1  // weight of new samples when learning bias and error
+	biasMin        = 0.2  // lower bound of bias correction
+	biasMax        = 2.0  // upper bound of bias correction
+	minForecast    = 100  // minimum forecast power in W for learning, excludes night and dusk
+	errorThreshold = 100. // error in W added when weighting to avoid overweighting few perfect hours
+)
+
+// Accuracy is the learned accuracy of a solar forecast source
+type Accuracy struct {
+	Name    string  `json:"name"`
+	Bias    float64 `json:"bias"`    // correction factor applied to the forecast
+	Error   float64 `json:"error"`   // mean absolute error of the corrected forecast in W
+	Weight  float64 `json:"weight"`  // share of the blended forecast
+	Samples int     `json:"samples"` // number of learned hours
+}
+
+type solarSource struct {
+	forecast api.SolarForecast
+	expected *float64 // forecast of the current hour taken while it was ahead
+	Accuracy
+}
+
+// Blender blends multiple solar forecasts weighted by their accuracy and corrects their bias
+// learned from actual production
+type Blender struct {
+	mu      sync.Mutex
+	key     string
+	sources []*solarSource
+	hour    time.Time // current hour of production measurements
+	sum     float64
+	count   int
+}
+
+// NewBlender creates a blender for the named forecasts persisting learned accuracy under the given settings key
+func NewBlender(key string, names []string, forecasts []api.SolarForecast) *Blender {
+	b := &Blender{key: key}
+
+	var stored []Accuracy
+	_ = settings.Json(key, &stored)
+
+	for i, f := range forecasts {
+		s := &solarSource{
+			forecast: f,
+			Accuracy: Accuracy{Name: names[i], Bias: 1},
+		}
+
+		for _, a := range stored {
+			if a.Name == s.Name && a.Bias > 0 {
+				s.Accuracy = a
+			}
+		}
+
+		b.sources = append(b.sources, s)
+	}
+
+	b.weigh()
+
+	return b
+}
+
+// hourly returns the average forecast power of the hour starting at ts and if slots cover the hour
+func hourly(slots []api.ForecastSlot, ts time.Time) (float64, bool) {
+	end := ts.Add(time.Hour)
+
+	var energy, covered float64
+	for _, s := range slots {
+		from, to := s.Start, s.End
+		if from.Before(ts) {
+			from = ts
+		}
+		if to.After(end) {
+			to = end
+		}
+
+		if d := to.Sub(from).Hours(); d > 0 {
+			energy += s.Power * d
+			covered += d
+		}
+	}
+
+	if covered < 0.5 {
+		return 0, false
+	}
+
+	return energy / covered, true
+}
+
+// weigh distributes weights inversely proportional to the sources' errors.
+// Sources without samples are assumed to perform average.
+func (b *Blender) weigh() {
+	var sum float64
+	var sampled int
+	for _, s := range b.sources {
+		if s.Samples > 0 {
+			sum += s.Error
+			sampled++
+		}
+	}
+
+	mean := 0.0
+	if sampled > 0 {
+		mean = sum / float64(sampled)
+	}
+
+	var total float64
+	for _, s := range b.sources {
+		err := s.Error
+		if s.Samples == 0 {
+			err = mean
+		}
+
+		s.Weight = 1 / (err + errorThreshold)
+		total += s.Weight
+	}
+
+	for _, s := range b.sources {
+		s.Weight /= total
+	}
+}
+
+// expect keeps the sources' forecasts of the current hour for learning once the hour has completed.
+// Forecasts may only cover the future, so they must be taken while the hour is still ahead.
+func (b *Blender) expect() {
+	for _, s := range b.sources {
+		if s.expected != nil {
+			continue
+		}
+
+		slots, err := s.forecast.Forecast()
+		if err != nil {
+			continue
+		}
+
+		if f, ok := hourly(slots, b.hour); ok {
+			s.expected = &f
+		}
+	}
+}
+
+// learn updates bias and error of all sources from the hour's actual production
+func (b *Blender) learn(actual float64) {
+	for _, s := range b.sources {
+		if s.expected == nil || *s.expected < minForecast {
+			continue
+		}
+
+		f := *s.expected
+
+		deviation := math.Abs(s.Bias*f - actual)
+		ratio := math.Max(biasMin, math.Min(biasMax, actual/f))
+
+		if s.Samples == 0 {
+			s.Error = deviation
+		} else {
+			s.Error = (1-biasSmoothing)*s.Error + biasSmoothing*deviation
+		}
+
+		s.Bias = (1-biasSmoothing)*s.Bias + biasSmoothing*ratio
+		s.Samples++
+	}
+
+	b.weigh()
+}
+
+// Add adds a production measurement taken at the given time and returns true if
+// the accuracy has been updated from the completed previous hour
+func (b *Blender) Add(ts time.Time, power float64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hour := ts.Truncate(time.Hour)
+
+	var learned bool
+	if !hour.Equal(b.hour) {
+		if !b.hour.IsZero() && b.count > 0 && hour.Sub(b.hour) == time.Hour {
+			b.learn(b.sum / float64(b.count))
+			learned = true
+		}
+
+		b.hour = hour
+		b.sum, b.count = 0, 0
+
+		for _, s := range b.sources {
+			s.expected = nil
+		}
+	}
+
+	b.expect()
+
+	b.sum += power
+	b.count++
+
+	return learned
+}
+
+// Power returns the blended and bias-corrected forecast power for the hour starting at ts
+// and if any source covers the hour
+func (b *Blender) Power(ts time.Time) (float64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var res, weight float64
+	for _, s := range b.sources {
+		slots, err := s.forecast.Forecast()
+		if err != nil {
+			continue
+		}
+
+		if f, ok := hourly(slots, ts.Truncate(time.Hour)); ok {
+			res += s.Weight * s.Bias * f
+			weight += s.Weight
+		}
+	}
+
+	if weight == 0 {
+		return 0, false
+	}
+
+	// renormalize if sources are missing
+	return res / weight, true
+}
+
+// Stats returns the learned accuracy of all sources
+func (b *Blender) Stats() []Accuracy {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	res := make([]Accuracy, 0, len(b.sources))
+	for _, s := range b.sources {
+		res = append(res, s.Accuracy)
+	}
+
+	return res
+}
+
+// Save persists the learned accuracy
+func (b *Blender) Save() {
+	_ = settings.SetJson(b.key, b.Stats())
+}
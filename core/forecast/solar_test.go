@@ -0,0 +1,83 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// constForecast forecasts constant power over the day
+type constForecast float64
+
+func (f constForecast) Forecast() ([]api.ForecastSlot, error) {
+	start := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	return []api.ForecastSlot{{Start: start, End: start.Add(24 * time.Hour), Power: float64(f)}}, nil
+}
+
+func TestBlender(t *testing.T) {
+	b := NewBlender("test.solar", []string{"high", "exact"}, []api.SolarForecast{constForecast(2000), constForecast(1000)})
+
+	ts := time.Date(2022, 6, 1, 8, 0, 0, 0, time.UTC)
+
+	// equal weights before learning
+	res, ok := b.Power(ts)
+	assert.True(t, ok)
+	assert.Equal(t, 1500.0, res)
+
+	// not covered
+	_, ok = b.Power(ts.AddDate(0, 0, 1))
+	assert.False(t, ok)
+
+	// actual production of 1kW
+	for i := 0; i < 10; i++ {
+		assert.False(t, b.Add(ts, 1000))
+		ts = ts.Add(time.Hour)
+		assert.True(t, b.Add(ts, 1000))
+	}
+
+	stats := b.Stats()
+	high, exact := stats[0], stats[1]
+
+	assert.Equal(t, 10, high.Samples)
+	assert.Less(t, high.Bias, 1.0)
+	assert.Equal(t, 1.0, exact.Bias)
+	assert.Equal(t, 0.0, exact.Error)
+	assert.Greater(t, exact.Weight, high.Weight)
+
+	// blended forecast approaches actual production
+	res, _ = b.Power(ts)
+	assert.Less(t, res, 1200.0)
+	assert.GreaterOrEqual(t, res, 1000.0)
+}
+
+// futureForecast forecasts constant power in half-hour periods starting with the current period
+type futureForecast struct {
+	now   *time.Time
+	power float64
+}
+
+func (f futureForecast) Forecast() ([]api.ForecastSlot, error) {
+	var res []api.ForecastSlot
+	for ts := f.now.Truncate(30 * time.Minute); ts.Before(f.now.Add(24 * time.Hour)); ts = ts.Add(30 * time.Minute) {
+		res = append(res, api.ForecastSlot{Start: ts, End: ts.Add(30 * time.Minute), Power: f.power})
+	}
+	return res, nil
+}
+
+func TestBlenderFutureForecast(t *testing.T) {
+	ts := time.Date(2022, 6, 1, 8, 0, 0, 0, time.UTC)
+	b := NewBlender("test.future", []string{"high"}, []api.SolarForecast{futureForecast{&ts, 2000}})
+
+	// measurements during the hour when the forecast no longer covers it
+	assert.False(t, b.Add(ts, 1000))
+	ts = ts.Add(45 * time.Minute)
+	assert.False(t, b.Add(ts, 1000))
+	ts = ts.Add(15 * time.Minute)
+	assert.True(t, b.Add(ts, 1000))
+
+	stats := b.Stats()
+	assert.Equal(t, 1, stats[0].Samples)
+	assert.Less(t, stats[0].Bias, 1.0)
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+// GeneratorConfig defines a controllable generator dispatched when battery and pv cannot meet critical charging demand
+type GeneratorConfig struct {
+	Enable     provider.Config `mapstructure:"enable"`     // start/stop control
+	MinSoC     float64         `mapstructure:"minSoC"`     // dispatch generator below this battery soc
+	WarmUp     time.Duration   `mapstructure:"warmUp"`     // duration after start before the generator is available
+	CoolDown   time.Duration   `mapstructure:"coolDown"`   // duration to keep the generator running unloaded before stop
+	MinRuntime time.Duration   `mapstructure:"minRuntime"` // minimum runtime after start
+}
+
+type generatorState string
+
+const (
+	generatorOff      generatorState = "off"
+	generatorWarmUp   generatorState = "warmup"
+	generatorRunning  generatorState = "running"
+	generatorCoolDown generatorState = "cooldown"
+)
+
+// generator is a dispatchable generator with warm-up, cool-down and minimum runtime
+type generator struct {
+	log    *util.Logger
+	clock  clock.Clock
+	enable func(bool) error
+	GeneratorConfig
+
+	state   generatorState
+	started time.Time // time of generator start
+	updated time.Time // time of last state change
+}
+
+// newGeneratorFromConfig creates a generator
+func newGeneratorFromConfig(log *util.Logger, clck clock.Clock, cc GeneratorConfig) (*generator, error) {
+	enable, err := provider.NewBoolSetterFromConfig("enable", cc.Enable)
+	if err != nil {
+		return nil, fmt.Errorf("generator: %w", err)
+	}
+
+	g := &generator{
+		log:             log,
+		clock:           clck,
+		enable:          enable,
+		GeneratorConfig: cc,
+		state:           generatorOff,
+	}
+
+	return g, nil
+}
+
+func (g *generator) setState(state generatorState) {
+	g.log.DEBUG.Printf("generator: %s", state)
+	g.state = state
+	g.updated = g.clock.Now()
+}
+
+// Update dispatches the generator depending on demand and returns its state
+func (g *generator) Update(demand bool) generatorState {
+	elapsed := g.clock.Since(g.updated)
+
+	switch g.state {
+	case generatorOff:
+		if demand {
+			if err := g.enable(true); err != nil {
+				g.log.ERROR.Printf("generator start: %v", err)
+				break
+			}
+
+			g.log.INFO.Println("generator started")
+			g.started = g.clock.Now()
+			g.setState(generatorWarmUp)
+		}
+
+	case generatorWarmUp:
+		if elapsed >= g.WarmUp {
+			g.setState(generatorRunning)
+		}
+
+	case generatorRunning:
+		if !demand && g.clock.Since(g.started) >= g.MinRuntime {
+			g.setState(generatorCoolDown)
+		}
+
+	case generatorCoolDown:
+		if demand {
+			g.setState(generatorRunning)
+			break
+		}
+
+		if elapsed >= g.CoolDown {
+			if err := g.enable(false); err != nil {
+				g.log.ERROR.Printf("generator stop: %v", err)
+				break
+			}
+
+			g.log.INFO.Println("generator stopped")
+			g.setState(generatorOff)
+		}
+	}
+
+	return g.state
+}
+
+// generatorPolicy blocks charging while the generator is not available for load
+type generatorPolicy interface {
+	generatorBlocked() bool
+}
+
+// generatorBlocked returns if the generator is warming up or cooling down and must not be loaded
+func (site *Site) generatorBlocked() bool {
+	site.Lock()
+	defer site.Unlock()
+	return site.generator.state == generatorWarmUp || site.generator.state == generatorCoolDown
+}
+
+// generatorCurrent stops charging while the generator is warming up or cooling down
+func (lp *LoadPoint) generatorCurrent(chargeCurrent float64) (float64, bool) {
+	if lp.dispatcher == nil || chargeCurrent == 0 || !lp.dispatcher.generatorBlocked() {
+		return chargeCurrent, false
+	}
+
+	lp.log.DEBUG.Println("generator: waiting for warm-up or cool-down")
+
+	return 0, true
+}
+
+// generatorDemand checks if critical charging demand cannot be met by battery and pv
+func (site *Site) generatorDemand(pvPower, homePower, chargePower float64) bool {
+	var critical bool
+	for _, lp := range site.loadpoints {
+		if lp.minSocNotReached() {
+			critical = true
+		}
+	}
+
+	site.Lock()
+	soc := site.batterySoC
+	site.Unlock()
+
+	return critical && soc < site.generator.MinSoC && pvPower < homePower+chargePower
+}
+
+// updateGenerator dispatches the generator
+func (site *Site) updateGenerator(pvPower, homePower, chargePower float64) {
+	if site.generator == nil {
+		return
+	}
+
+	demand := site.generatorDemand(pvPower, homePower, chargePower)
+
+	site.Lock()
+	state := site.generator.Update(demand)
+	site.Unlock()
+
+	site.publish("generator", state)
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerator(t *testing.T) {
+	clck := clock.NewMock()
+
+	var enabled bool
+	g := &generator{
+		log:   util.NewLogger("foo"),
+		clock: clck,
+		enable: func(b bool) error {
+			enabled = b
+			return nil
+		},
+		GeneratorConfig: GeneratorConfig{
+			WarmUp:     time.Minute,
+			CoolDown:   time.Minute,
+			MinRuntime: time.Hour,
+		},
+		state: generatorOff,
+	}
+
+	assert.Equal(t, generatorOff, g.Update(false))
+
+	// start and warm up
+	assert.Equal(t, generatorWarmUp, g.Update(true))
+	assert.True(t, enabled)
+
+	clck.Add(time.Minute)
+	assert.Equal(t, generatorRunning, g.Update(true))
+
+	// minimum runtime
+	assert.Equal(t, generatorRunning, g.Update(false))
+
+	clck.Add(time.Hour)
+	assert.Equal(t, generatorCoolDown, g.Update(false))
+
+	// demand returns during cool down
+	assert.Equal(t, generatorRunning, g.Update(true))
+	assert.Equal(t, generatorCoolDown, g.Update(false))
+
+	clck.Add(time.Minute)
+	assert.Equal(t, generatorOff, g.Update(false))
+	assert.False(t, enabled)
+}
+
+func TestGeneratorCurrent(t *testing.T) {
+	site := &Site{
+		generator: &generator{state: generatorOff},
+	}
+
+	lp := &LoadPoint{
+		log:        util.NewLogger("foo"),
+		dispatcher: site,
+	}
+
+	for _, tc := range []struct {
+		state   generatorState
+		blocked bool
+	}{
+		{generatorOff, false},
+		{generatorWarmUp, true},
+		{generatorRunning, false},
+		{generatorCoolDown, true},
+	} {
+		site.generator.state = tc.state
+
+		current, blocked := lp.generatorCurrent(maxA)
+		assert.Equal(t, tc.blocked, blocked, tc.state)
+		if tc.blocked {
+			assert.Equal(t, 0.0, current, tc.state)
+		}
+	}
+}
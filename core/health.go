@@ -3,18 +3,21 @@ package core
 import (
 	"sync"
 	"time"
+
+	"github.com/benbjohnson/clock"
 )
 
 // Health is a health checker that needs regular updates to stay healthy
 type Health struct {
 	mux     sync.Mutex
+	clock   clock.Clock
 	updated time.Time
 	timeout time.Duration
 }
 
 // NewHealth creates new health checker
 func NewHealth(timeout time.Duration) (health *Health) {
-	return &Health{timeout: timeout}
+	return &Health{clock: clock.New(), timeout: timeout}
 }
 
 // Healthy returns health status based on last update timestamp
@@ -26,7 +29,7 @@ func (health *Health) Healthy() bool {
 	health.mux.Lock()
 	defer health.mux.Unlock()
 
-	return time.Since(health.updated) < health.timeout
+	return health.clock.Since(health.updated) < health.timeout
 }
 
 // Update updates the health timer on each loadpoint update
@@ -38,5 +41,5 @@ func (health *Health) Update() {
 	health.mux.Lock()
 	defer health.mux.Unlock()
 
-	health.updated = time.Now()
+	health.updated = health.clock.Now()
 }
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"context"
+
 	"github.com/avast/retry-go/v3"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
@@ -46,3 +48,21 @@ func vehicleTitles(vehicles []api.Vehicle) []string {
 		return v.Title()
 	})
 }
+
+// currentPower reads the meter's power, cancelled when ctx is done.
+// Calls abandoned on cancellation are serialized using ex.
+func currentPower(ctx context.Context, ex *util.Exclusive, m api.Meter) (float64, error) {
+	if mc, ok := m.(api.MeterContext); ok {
+		return mc.CurrentPowerContext(ctx)
+	}
+	return util.WithContext(ctx, ex, m.CurrentPower)
+}
+
+// chargerStatus reads the charger's status, cancelled when ctx is done.
+// Calls abandoned on cancellation are serialized using ex.
+func chargerStatus(ctx context.Context, ex *util.Exclusive, c api.ChargeState) (api.ChargeStatus, error) {
+	if cc, ok := c.(api.ChargeStateContext); ok {
+		return cc.StatusContext(ctx)
+	}
+	return util.WithContext(ctx, ex, c.Status)
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -19,6 +20,7 @@ import (
 	"github.com/evcc-io/evcc/provider"
 	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/nfc"
 
 	evbus "github.com/asaskevich/EventBus"
 	"github.com/avast/retry-go/v3"
@@ -37,6 +39,9 @@ const (
 	evVehicleDisconnect   = "disconnect" // vehicle disconnected
 	evVehicleSoC          = "soc"        // vehicle soc progress
 	evVehicleUnidentified = "guest"      // vehicle unidentified
+	evVehicleAuthExpired  = "auth"       // vehicle authorization expired
+	evChargerFault        = "fault"      // charger entered fault state
+	evWriteIgnored        = "ignored"    // charger ignored the current limit
 
 	pvTimer   = "pv"
 	pvEnable  = "enable"
@@ -66,12 +71,13 @@ type PollConfig struct {
 
 // SoCConfig defines soc settings, estimation and update behaviour
 type SoCConfig struct {
-	Poll     PollConfig `mapstructure:"poll"`
-	Estimate *bool      `mapstructure:"estimate"`
-	Min_     int        `mapstructure:"min"`    // TODO deprecated
-	Target_  int        `mapstructure:"target"` // TODO deprecated
-	min      int        // Default minimum SoC, guarded by mutex
-	target   int        // Default target SoC, guarded by mutex
+	Poll     PollConfig    `mapstructure:"poll"`
+	Estimate *bool         `mapstructure:"estimate"`
+	MaxAge   time.Duration `mapstructure:"maxAge"` // soc readings older than this neither shorten nor cancel charge plans
+	Min_     int           `mapstructure:"min"`    // TODO deprecated
+	Target_  int           `mapstructure:"target"` // TODO deprecated
+	min      int           // Default minimum SoC, guarded by mutex
+	target   int           // Default target SoC, guarded by mutex
 }
 
 // Poll modes
@@ -81,6 +87,8 @@ const (
 	pollAlways    = "always"
 
 	pollInterval = 60 * time.Minute
+
+	socRateLimitBackoff = 15 * time.Minute // suspend polling when rate limited
 )
 
 // ThresholdConfig defines enable/disable hysteresis parameters
@@ -93,6 +101,7 @@ type ThresholdConfig struct {
 // SoC needs and power availability.
 type LoadPoint struct {
 	clock    clock.Clock       // mockable time
+	location *time.Location    // site timezone for planning and statistics
 	bus      evbus.Bus         // event bus
 	pushChan chan<- push.Event // notifications
 	uiChan   chan<- util.Param // client push messages
@@ -111,34 +120,91 @@ type LoadPoint struct {
 	MeterRef          string   `mapstructure:"meter"`    // Charge meter reference
 	SoC               SoCConfig
 	Enable, Disable   ThresholdConfig
-	ResetOnDisconnect bool `mapstructure:"resetOnDisconnect"`
+	ResetOnDisconnect bool       `mapstructure:"resetOnDisconnect"`
+	NFC               nfc.Config `mapstructure:"nfc"`             // host attached NFC reader for identification
+	VehicleProfiles   bool       `mapstructure:"vehicleProfiles"` // remember settings per identified vehicle
+	Priority          int        `mapstructure:"priority"`        // loadpoint priority, higher priority loadpoints are served first by priority circuits
 	onDisconnect      api.ActionConfig
-	targetEnergy      int // Target charge energy for dumb vehicles
-
-	MinCurrent    float64       // PV mode: start current	Min+PV mode: min current
-	MaxCurrent    float64       // Max allowed current. Physically ensured by the charger
-	GuardDuration time.Duration // charger enable/disable minimum holding time
+	automationMode    *api.ChargeMode          // mode restored on disconnect while an automation rule applies
+	vehicleName       func(api.Vehicle) string // configured vehicle name for its profile
+	targetEnergy      int                      // Target charge energy for the current session in kWh
+	targetRange       int                      // Target range in km, converted to target soc
+
+	MinCurrent    float64          // PV mode: start current	Min+PV mode: min current
+	MaxCurrent    float64          // Max allowed current. Physically ensured by the charger
+	GuardDuration time.Duration    // charger enable/disable minimum holding time
+	Switching     SwitchingConfig  // contactor cycle limits
+	Physical      PhysicalConfig   // cable, socket and breaker ratings
+	Standby       StandbyConfig    // idle charger power down
+	SessionGap    time.Duration    // reconnect within this duration continues the session
+	MeterCheck    MeterCheckConfig // cross check of charge meter and charger meter
+	Budget        *BudgetConfig    // monthly grid energy or cost budget
+	Strict        *StrictConfig    // solar-only charging in pv mode
+
+	Indications map[string]api.Indication `mapstructure:"indicator"` // charger led or display state by loadpoint state
 
 	enabled             bool      // Charger enabled state
+	disconnected        time.Time // deferred disconnect waiting for reconnect
+	disconnectedID      string    // vehicle identifier of the deferred disconnect
 	phases              int       // Charger enabled phases, guarded by mutex
 	measuredPhases      int       // Charger physically measured phases
 	chargeCurrent       float64   // Charger current limit
+	physicalLimited     float64   // Charge current last limited to physical rating
 	guardUpdated        time.Time // Charger enabled/disabled timestamp
 	socUpdated          time.Time // SoC updated timestamp (poll: connected)
+	socRead             time.Time // SoC successfully read timestamp
+	socRefresh          time.Time // SoC refresh requested timestamp for stale soc
+	socBackoff          time.Time // SoC polling suspended until timestamp (rate limit)
+	authExpired         bool      // Vehicle authorization expired alert sent
 	vehicleDetect       time.Time // Vehicle connected timestamp
 	vehicleDetectTicker *clock.Ticker
 	vehicleIdentifier   string
+	cycles              int              // Charger enable cycles today
+	cyclesTotal         int              // Charger enable cycles total
+	cyclesDay           string           // Day of cycles counter
+	standbyS            func(bool) error // Charger standby control
+	standby             bool             // Charger powered down
+	standbyUpdated      time.Time        // Charger standby changed timestamp
+	idleSince           time.Time        // Charger idle timestamp
+	wakeRequested       bool             // Charger wake up requested via api
+	trip                bool             // Vehicle on road trip
+	pricing             *pricing         // Session pricing by identification
 
 	charger     api.Charger
 	chargeTimer api.ChargeTimer
 	chargeRater api.ChargeRater
 
+	chargerPoll     *pollTimer     // charger status polling interval
+	chargeMeterPoll *pollTimer     // charge meter polling interval
+	chargerEx       util.Exclusive // serializes abandoned charger status reads
+	chargeMeterEx   util.Exclusive // serializes abandoned charge meter reads
+	vehicleInterval time.Duration  // SoC polling interval while charging
+
 	chargeMeter    api.Meter   // Charger usage meter
 	vehicle        api.Vehicle // Currently active vehicle
 	defaultVehicle api.Vehicle // Default vehicle (disables detection)
 	coordinator    coordinator.API
 	socEstimator   *soc.Estimator
 	socTimer       *soc.Timer
+	predictor      surplusPredictor // Optional pv surplus prediction
+	supply         *sharedSupply    // Optional shared supply with other loadpoints
+	rotation       *rotation        // Optional rotation of charging rights with other loadpoints
+	circuit        *circuit         // Optional circuit limiting the load
+	circuitLimit   float64          // Current assigned by the circuit
+	shedder        loadShedder      // Optional load shedding
+	islander       islandPolicy     // Optional island operation policy
+	dispatcher     generatorPolicy  // Optional generator dispatch
+	planner        planLocker       // Optional price lock of committed target charge plans
+	planLock       planLock         // Locked rates of the committed target charge plan
+	identifier     api.Identifier   // Optional identification source if charger does not identify
+	meterCheck     *meterCheck      // Optional cross check of charge meter and charger meter
+	budgets        []*budget        // Optional loadpoint and site charging budgets
+	indicator      api.Indicator    // Optional charger led or display control
+	indicated      *api.Indication  // Last indication sent to the charger
+	prepared       bool             // Attached to a site before, reused on reload
+	repeating      []*plan          // Weekly recurring target charges
+	repeatingKey   string           // Settings key of the recurring target charges' vehicle
+	repeatingNext  time.Time        // Last applied recurring target charge
 
 	// cached state
 	status         api.ChargeStatus       // Charger status
@@ -149,6 +215,9 @@ type LoadPoint struct {
 	pvTimer        time.Time              // PV enabled/disable timer
 	phaseTimer     time.Time              // 1p3p switch timer
 	wakeUpTimer    *Timer                 // Vehicle wake-up timeout
+	pause          *PauseReason           // Reason for not charging in current cycle
+	pauseRecorded  string                 // Reason for not charging last recorded to the timeline
+	writeIgnored   bool                   // Charger ignored the last current limit
 
 	// charge progress
 	vehicleSoc              float64       // Vehicle SoC
@@ -156,11 +225,15 @@ type LoadPoint struct {
 	chargedEnergy           float64       // Charged energy while connected in Wh
 	chargeRemainingDuration time.Duration // Remaining charge duration
 	chargeRemainingEnergy   float64       // Remaining charge energy in Wh
+	completion              *Completion   // Estimated end of charging
 	progress                *Progress     // Step-wise progress indicator
 
 	// session log
-	db      db.Database
-	session *db.Session
+	db                db.Database
+	session           *db.Session
+	annotation        db.Annotation // tags and note of the current session
+	annotationUpdated bool          // annotation must be persisted
+	sessionCost       sessionCost   // energy cost of the current session
 
 	tasks queues.Queue // tasks to be executed
 }
@@ -172,6 +245,8 @@ func NewLoadPointFromConfig(log *util.Logger, cp configProvider, other map[strin
 		return nil, err
 	}
 
+	lp.vehicleName = cp.VehicleName
+
 	// set vehicle polling mode
 	switch lp.SoC.Poll.Mode = strings.ToLower(lp.SoC.Poll.Mode); lp.SoC.Poll.Mode {
 	case pollCharging:
@@ -241,6 +316,37 @@ func NewLoadPointFromConfig(log *util.Logger, cp configProvider, other map[strin
 	}
 	lp.configureChargerType(lp.charger)
 
+	if err := lp.configureStandby(); err != nil {
+		return nil, err
+	}
+
+	if err := lp.configureMeterCheck(); err != nil {
+		return nil, err
+	}
+
+	lp.configureStrict()
+
+	if err := lp.configureIndicator(); err != nil {
+		return nil, err
+	}
+
+	if lp.Budget != nil {
+		b, err := newBudgetFromConfig(fmt.Sprintf("loadpoint.%s.budget", lp.Title), *lp.Budget)
+		if err != nil {
+			return nil, fmt.Errorf("budget: %w", err)
+		}
+
+		b.publish = lp.publish
+		b.notify = lp.pushEvent
+		lp.budgets = append(lp.budgets, b)
+	}
+
+	if lp.NFC.Device != "" {
+		if lp.identifier, err = nfc.NewReaderFromConfig(lp.NFC); err != nil {
+			return nil, err
+		}
+	}
+
 	// setup fixed phases:
 	// - simple charger starts with phases config if specified or 3p
 	// - switchable charger starts at 0p since we don't know the current setting
@@ -254,6 +360,10 @@ func NewLoadPointFromConfig(log *util.Logger, cp configProvider, other map[strin
 		lp.log.WARN.Printf("locking phase config to %dp for switchable charger", lp.ConfiguredPhases)
 	}
 
+	if err := lp.Physical.validate(lp.MinCurrent, lp.MaxCurrent, lp.ConfiguredPhases); err != nil {
+		return nil, fmt.Errorf("physical: %w", err)
+	}
+
 	// validate thresholds
 	if lp.Enable.Threshold > lp.Disable.Threshold {
 		lp.log.WARN.Printf("PV mode enable threshold (%.0fW) is larger than disable threshold (%.0fW)", lp.Enable.Threshold, lp.Disable.Threshold)
@@ -270,14 +380,15 @@ func NewLoadPoint(log *util.Logger) *LoadPoint {
 	bus := evbus.New()
 
 	lp := &LoadPoint{
-		log:           log,   // logger
-		clock:         clock, // mockable time
-		bus:           bus,   // event bus
+		log:           log,        // logger
+		clock:         clock,      // mockable time
+		location:      time.Local, // site timezone
+		bus:           bus,        // event bus
 		Mode:          api.ModeOff,
 		status:        api.StatusNone,
 		MinCurrent:    6,                                                     // A
 		MaxCurrent:    16,                                                    // A
-		SoC:           SoCConfig{min: 0, target: 100},                        // %
+		SoC:           SoCConfig{min: 0, target: 100, MaxAge: 4 * time.Hour}, // %
 		Enable:        ThresholdConfig{Delay: time.Minute, Threshold: 0},     // t, W
 		Disable:       ThresholdConfig{Delay: 3 * time.Minute, Threshold: 0}, // t, W
 		GuardDuration: 5 * time.Minute,
@@ -288,6 +399,7 @@ func NewLoadPoint(log *util.Logger) *LoadPoint {
 
 	// allow target charge handler to access loadpoint
 	lp.socTimer = soc.NewTimer(lp.log, &adapter{LoadPoint: lp})
+	lp.socTimer.SetClock(clock)
 
 	return lp
 }
@@ -343,6 +455,7 @@ func (lp *LoadPoint) configureChargerType(charger api.Charger) {
 		lp.chargeRater = rt
 	} else {
 		rt := wrapper.NewChargeRater(lp.log, lp.chargeMeter)
+		rt.SetClock(lp.clock)
 		_ = lp.bus.Subscribe(evChargePower, rt.SetChargePower)
 		_ = lp.bus.Subscribe(evVehicleConnect, func() { rt.StartCharge(false) })
 		_ = lp.bus.Subscribe(evChargeStart, func() { rt.StartCharge(true) })
@@ -355,6 +468,7 @@ func (lp *LoadPoint) configureChargerType(charger api.Charger) {
 		lp.chargeTimer = ct
 	} else {
 		ct := wrapper.NewChargeTimer()
+		ct.SetClock(lp.clock)
 		_ = lp.bus.Subscribe(evVehicleConnect, func() { ct.StartCharge(false) })
 		_ = lp.bus.Subscribe(evChargeStart, func() { ct.StartCharge(true) })
 		_ = lp.bus.Subscribe(evChargeStop, ct.StopCharge)
@@ -363,6 +477,23 @@ func (lp *LoadPoint) configureChargerType(charger api.Charger) {
 
 	// add wakeup timer
 	lp.wakeUpTimer = NewTimer()
+	lp.wakeUpTimer.clck = lp.clock
+}
+
+// setClock replaces the loadpoint's time source. Must be called before Prepare.
+func (lp *LoadPoint) setClock(clck clock.Clock) {
+	lp.clock = clck
+	if lp.socTimer != nil {
+		lp.socTimer.SetClock(clck)
+	}
+}
+
+// now returns the current time in the site's timezone
+func (lp *LoadPoint) now() time.Time {
+	if lp.location == nil {
+		return lp.clock.Now()
+	}
+	return lp.clock.Now().In(lp.location)
 }
 
 // pushEvent sends push messages to clients
@@ -442,6 +573,11 @@ func (lp *LoadPoint) evVehicleDisconnectHandler() {
 	lp.stopSession()
 	lp.finalizeSession()
 
+	// learn consumption from energy charged during this session
+	if lp.vehicle != nil {
+		soc.VehicleConsumption(lp.vehicle.Title()).AddEnergy(lp.getChargedEnergy() / 1e3)
+	}
+
 	// phases are unknown when vehicle disconnects
 	lp.resetMeasuredPhases()
 
@@ -461,6 +597,9 @@ func (lp *LoadPoint) evVehicleDisconnectHandler() {
 	// set default mode on disconnect
 	if lp.ResetOnDisconnect {
 		actionCfg := lp.onDisconnect
+		if lp.automationMode != nil {
+			actionCfg.Mode = lp.automationMode
+		}
 		if v := lp.defaultVehicle; v != nil {
 			actionCfg = actionCfg.Merge(v.OnIdentified())
 		}
@@ -470,6 +609,11 @@ func (lp *LoadPoint) evVehicleDisconnectHandler() {
 	// soc update reset
 	lp.socUpdated = time.Time{}
 
+	// target energy is valid for a single session only
+	lp.Lock()
+	lp.setTargetEnergy(0)
+	lp.Unlock()
+
 	// reset timer when vehicle is removed
 	lp.socTimer.Reset()
 }
@@ -537,26 +681,39 @@ func (lp *LoadPoint) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Even
 	lp.lpChan = lpChan
 
 	// event handlers
-	_ = lp.bus.Subscribe(evChargeStart, lp.evChargeStartHandler)
-	_ = lp.bus.Subscribe(evChargeStop, lp.evChargeStopHandler)
-	_ = lp.bus.Subscribe(evVehicleConnect, lp.evVehicleConnectHandler)
-	_ = lp.bus.Subscribe(evVehicleDisconnect, lp.evVehicleDisconnectHandler)
-	_ = lp.bus.Subscribe(evChargeCurrent, lp.evChargeCurrentHandler)
-	_ = lp.bus.Subscribe(evVehicleSoC, lp.evVehicleSoCProgressHandler)
+	if !lp.prepared {
+		_ = lp.bus.Subscribe(evChargeStart, lp.evChargeStartHandler)
+		_ = lp.bus.Subscribe(evChargeStop, lp.evChargeStopHandler)
+		_ = lp.bus.Subscribe(evVehicleConnect, lp.evVehicleConnectHandler)
+		_ = lp.bus.Subscribe(evVehicleDisconnect, lp.evVehicleDisconnectHandler)
+		_ = lp.bus.Subscribe(evChargeCurrent, lp.evChargeCurrentHandler)
+		_ = lp.bus.Subscribe(evVehicleSoC, lp.evVehicleSoCProgressHandler)
+	}
 
 	// publish initial values
 	lp.publish("title", lp.Title)
 	lp.publish("minCurrent", lp.MinCurrent)
 	lp.publish("maxCurrent", lp.MaxCurrent)
+	lp.publish("physicalCurrent", lp.Physical.limit())
 
 	lp.setConfiguredPhases(lp.ConfiguredPhases)
 	lp.publish(phasesEnabled, lp.phases)
 	lp.publish(phasesActive, lp.activePhases())
 	lp.publishTimer(phaseTimer, 0, timerInactive)
 	lp.publishTimer(pvTimer, 0, timerInactive)
+	lp.publishCycles()
 
-	// assign and publish default vehicle
-	if lp.defaultVehicle != nil {
+	lp.Lock()
+	if lp.repeatingKey == "" {
+		lp.repeatingKey = lp.repeatingPlansKey(nil)
+	}
+	lp.loadRepeatingPlans()
+	lp.Unlock()
+
+	// assign and publish default vehicle, reused loadpoints keep their vehicle
+	if lp.prepared && lp.vehicle != nil {
+		lp.coordinator.Acquire(lp.vehicle)
+	} else if lp.defaultVehicle != nil {
 		lp.setActiveVehicle(lp.defaultVehicle)
 	}
 
@@ -584,6 +741,40 @@ func (lp *LoadPoint) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Even
 	if ctrl, ok := lp.charger.(loadpoint.Controller); ok {
 		ctrl.LoadpointControl(lp)
 	}
+
+	lp.prepared = true
+}
+
+// detachSite removes the dependencies provided by the site before the loadpoint is attached to a reloaded site
+func (lp *LoadPoint) detachSite() {
+	lp.predictor = nil
+	lp.supply = nil
+	lp.rotation = nil
+	lp.circuit = nil
+	lp.shedder = nil
+	lp.islander = nil
+	lp.dispatcher = nil
+	lp.planner = nil
+	lp.pricing = nil
+
+	// keep the loadpoint's own budget
+	if lp.Budget == nil {
+		lp.budgets = nil
+	} else if len(lp.budgets) > 1 {
+		lp.budgets = lp.budgets[:1]
+	}
+}
+
+// Close ends the loadpoint's session and releases its resources when the loadpoint has been removed on reload
+func (lp *LoadPoint) Close() {
+	lp.stopSession()
+	lp.session = nil
+
+	if c, ok := lp.identifier.(api.Closer); ok {
+		if err := c.Close(); err != nil {
+			lp.log.ERROR.Printf("identifier: %v", err)
+		}
+	}
 }
 
 // syncCharger updates charger status and synchronizes it with expectations
@@ -591,7 +782,7 @@ func (lp *LoadPoint) syncCharger() {
 	enabled, err := lp.charger.Enabled()
 	if err == nil {
 		if enabled != lp.enabled {
-			if time.Since(lp.guardUpdated) > guardGracePeriod {
+			if lp.clock.Since(lp.guardUpdated) > guardGracePeriod {
 				lp.log.WARN.Printf("charger out of sync: expected %vd, got %vd", status[lp.enabled], status[enabled])
 			}
 			err = lp.charger.Enable(lp.enabled)
@@ -609,6 +800,53 @@ func (lp *LoadPoint) syncCharger() {
 
 // setLimit applies charger current limits and enables/disables accordingly
 func (lp *LoadPoint) setLimit(chargeCurrent float64, force bool) error {
+	// load shedding on grid frequency deviation applies immediately, bypassing contactor guards
+	if current, shed := lp.shedCurrent(chargeCurrent); shed {
+		chargeCurrent, force = current, true
+		if current == 0 {
+			lp.setPause(PauseReason{Reason: pauseRestricted, Detail: "load shedding"})
+		}
+	}
+
+	// restricted island policy
+	if current, restricted := lp.islandCurrent(chargeCurrent); restricted {
+		chargeCurrent, force = current, force || current == 0
+		if current == 0 {
+			lp.setPause(PauseReason{Reason: pauseRestricted, Detail: "island operation"})
+		}
+	}
+
+	// generator warming up or cooling down unloaded
+	if current, blocked := lp.generatorCurrent(chargeCurrent); blocked {
+		chargeCurrent, force = current, true
+		lp.setPause(PauseReason{Reason: pauseRestricted, Detail: "generator"})
+	}
+
+	// throttled by charging budget
+	if current, throttled := lp.budgetCurrent(chargeCurrent); throttled {
+		chargeCurrent, force = current, force || current == 0
+		if current == 0 {
+			lp.setPause(PauseReason{Reason: pauseRestricted, Detail: "budget"})
+		}
+	}
+
+	// waiting for turn in rotation
+	if current, blocked := lp.rotationCurrent(chargeCurrent); blocked {
+		chargeCurrent, force = current, true
+		lp.setPause(PauseReason{Reason: pauseRestricted, Detail: "rotation"})
+	}
+
+	// share of circuit headroom
+	if current, limited := lp.circuitCurrent(chargeCurrent); limited {
+		chargeCurrent, force = current, force || current == 0
+		if current == 0 {
+			lp.setPause(PauseReason{Reason: pauseRestricted, Detail: "circuit"})
+		}
+	}
+
+	// never exceed cable, socket or breaker rating
+	chargeCurrent = lp.physicalChargeCurrent(chargeCurrent)
+
 	// set current
 	if chargeCurrent != lp.chargeCurrent && chargeCurrent >= lp.GetMinCurrent() {
 		var err error
@@ -619,6 +857,14 @@ func (lp *LoadPoint) setLimit(chargeCurrent float64, force bool) error {
 			err = lp.charger.MaxCurrent(int64(chargeCurrent))
 		}
 
+		// notify once until the charger accepts the limit again
+		if ignored := errors.Is(err, api.ErrWriteIgnored); ignored != lp.writeIgnored {
+			lp.writeIgnored = ignored
+			if ignored {
+				lp.pushEvent(evWriteIgnored)
+			}
+		}
+
 		if err != nil {
 			return fmt.Errorf("max charge current %.3gA: %w", chargeCurrent, err)
 		}
@@ -630,8 +876,24 @@ func (lp *LoadPoint) setLimit(chargeCurrent float64, force bool) error {
 
 	// set enabled/disabled
 	if enabled := chargeCurrent >= lp.GetMinCurrent(); enabled != lp.enabled {
-		if remaining := (lp.GuardDuration - lp.clock.Since(lp.guardUpdated)).Truncate(time.Second); remaining > 0 && !force {
+		if remaining := (lp.guardDuration(enabled) - lp.clock.Since(lp.guardUpdated)).Truncate(time.Second); remaining > 0 && !force {
 			lp.log.DEBUG.Printf("charger %s: contactor delay %v", status[enabled], remaining)
+			if enabled {
+				lp.pauseUntil(pauseContactor, lp.clock.Now().Add(remaining))
+			}
+			return nil
+		}
+
+		if enabled && !force && lp.GetMode() == api.ModePV && lp.cycleLimitReached() {
+			lp.log.DEBUG.Printf("charger %s: daily cycle limit reached (%d)", status[enabled], lp.Switching.MaxCycles)
+			lp.setPause(PauseReason{Reason: pauseCycleLimit})
+			return nil
+		}
+
+		// shared supply must be connected, even if forced
+		if enabled && lp.supply != nil && !lp.supply.acquire(lp) {
+			lp.log.DEBUG.Printf("charger %s: waiting for shared supply", status[enabled])
+			lp.setPause(PauseReason{Reason: pauseRestricted, Detail: "shared supply"})
 			return nil
 		}
 
@@ -652,10 +914,18 @@ func (lp *LoadPoint) setLimit(chargeCurrent float64, force bool) error {
 		lp.enabled = enabled
 		lp.guardUpdated = lp.clock.Now()
 
+		if enabled {
+			lp.record(eventEnable, string(lp.GetMode()))
+		} else {
+			lp.record(eventDisable, lp.pause.String())
+		}
+
 		lp.bus.Publish(evChargeCurrent, chargeCurrent)
 
 		// start/stop vehicle wake-up timer
 		if enabled {
+			lp.countCycle()
+
 			lp.log.DEBUG.Printf("wake-up timer: start")
 			lp.wakeUpTimer.Start()
 		} else {
@@ -694,10 +964,10 @@ func (lp *LoadPoint) setStatus(status api.ChargeStatus) {
 	lp.status = status
 }
 
-// targetEnergyReached checks if target is configured and reached
+// targetEnergyReached checks if target is configured and reached.
+// Target energy applies to the current session independent of vehicle soc.
 func (lp *LoadPoint) targetEnergyReached() bool {
-	return (lp.vehicle == nil || lp.vehicleHasFeature(api.Offline)) &&
-		lp.targetEnergy > 0 &&
+	return lp.targetEnergy > 0 &&
 		lp.getChargedEnergy()/1e3 >= float64(lp.targetEnergy)
 }
 
@@ -749,6 +1019,19 @@ func (lp *LoadPoint) climateActive() bool {
 	return false
 }
 
+// plannedCharge returns target time and remaining energy in Wh of an active charge plan
+func (lp *LoadPoint) plannedCharge() (time.Time, float64) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	// test guard
+	if lp.socTimer == nil || lp.socTimer.Time.Before(lp.clock.Now()) || lp.tripSuspended() {
+		return time.Time{}, 0
+	}
+
+	return lp.socTimer.Time, lp.chargeRemainingEnergy
+}
+
 // disableUnlessClimater disables the charger unless climate is active
 func (lp *LoadPoint) disableUnlessClimater() error {
 	var current float64 // zero disables
@@ -776,9 +1059,17 @@ func (lp *LoadPoint) setVehicleIdentifier(id string) {
 	}
 }
 
+// vehicleIdentification returns the charger's identification or the optional identification source
+func (lp *LoadPoint) vehicleIdentification() (api.Identifier, bool) {
+	if identifier, ok := lp.charger.(api.Identifier); ok {
+		return identifier, true
+	}
+	return lp.identifier, lp.identifier != nil
+}
+
 // identifyVehicle reads vehicle identification from charger
 func (lp *LoadPoint) identifyVehicle() {
-	identifier, ok := lp.charger.(api.Identifier)
+	identifier, ok := lp.vehicleIdentification()
 	if !ok {
 		return
 	}
@@ -867,6 +1158,8 @@ func (lp *LoadPoint) setActiveVehicle(vehicle api.Vehicle) {
 
 	if lp.vehicle = vehicle; vehicle != nil {
 		lp.socUpdated = time.Time{}
+		lp.socBackoff = time.Time{}
+		lp.authExpired = false
 
 		// resolve optional config
 		var estimate bool
@@ -874,6 +1167,15 @@ func (lp *LoadPoint) setActiveVehicle(vehicle api.Vehicle) {
 			estimate = true
 		}
 		lp.socEstimator = soc.NewEstimator(lp.log, lp.charger, vehicle, estimate)
+		lp.socEstimator.SetClock(lp.clock)
+
+		if vm, ok := vehicle.(api.SocModeler); ok {
+			if model, err := soc.NewModel(vm.SocModel()); err == nil {
+				lp.socEstimator.SetModel(model)
+			} else {
+				lp.log.ERROR.Printf("vehicle %s: %v", vehicle.Title(), err)
+			}
+		}
 
 		lp.publish("vehiclePresent", true)
 		lp.publish("vehicleTitle", lp.vehicle.Title())
@@ -882,6 +1184,7 @@ func (lp *LoadPoint) setActiveVehicle(vehicle api.Vehicle) {
 		// unblock api
 		lp.Unlock()
 		lp.applyAction(vehicle.OnIdentified())
+		lp.applyVehicleProfile(vehicle)
 		lp.Lock()
 
 		lp.addTask(lp.vehicleOdometer)
@@ -896,8 +1199,13 @@ func (lp *LoadPoint) setActiveVehicle(vehicle api.Vehicle) {
 		lp.publish(vehicleOdometer, 0.0)
 	}
 
-	// reset target energy
+	// reset target energy and range
 	lp.setTargetEnergy(0)
+	lp.setTargetRange(0)
+
+	// recurring target charges belong to the vehicle
+	lp.repeatingKey = lp.repeatingPlansKey(vehicle)
+	lp.loadRepeatingPlans()
 
 	// re-publish vehicle settings
 	lp.Unlock()
@@ -936,6 +1244,7 @@ func (lp *LoadPoint) wakeUpVehicle() {
 // unpublishVehicle resets published vehicle data
 func (lp *LoadPoint) unpublishVehicle() {
 	lp.vehicleSoc = 0
+	lp.socRead = time.Time{}
 
 	lp.publish("vehicleSoC", 0.0)
 	lp.publish(vehicleRange, int64(0))
@@ -1027,7 +1336,7 @@ func (lp *LoadPoint) identifyVehicleByStatus() {
 		return
 	}
 
-	_, ok := lp.charger.(api.Identifier)
+	_, ok := lp.vehicleIdentification()
 
 	if vehicle := lp.coordinator.IdentifyVehicleByStatus(!ok); vehicle != nil {
 		lp.stopVehicleDetection()
@@ -1048,6 +1357,11 @@ func (lp *LoadPoint) vehicleOdometer() {
 			lp.log.DEBUG.Printf("vehicle odometer: %.0fkm", odo)
 			lp.publish(vehicleOdometer, odo)
 
+			// odometer readings between sessions provide the driven distance for learning consumption
+			consumption := soc.VehicleConsumption(lp.vehicle.Title())
+			consumption.AddOdometer(odo)
+			lp.publish(vehicleConsumption, consumption.Value())
+
 			// update session once odometer is read
 			lp.updateSession(func(session *db.Session) {
 				session.Odometer = odo
@@ -1086,18 +1400,31 @@ func statusEvents(prevStatus, status api.ChargeStatus) []string {
 }
 
 // updateChargerStatus updates charger status and detects car connected/disconnected events
-func (lp *LoadPoint) updateChargerStatus() error {
-	status, err := lp.charger.Status()
+func (lp *LoadPoint) updateChargerStatus(ctx context.Context) error {
+	if !lp.chargerPoll.due() {
+		return nil
+	}
+
+	status, err := chargerStatus(ctx, &lp.chargerEx, lp.charger)
 	if err != nil {
 		return err
 	}
 
+	lp.chargerPoll.done()
+
 	lp.log.DEBUG.Printf("charger status: %s", status)
 
 	if prevStatus := lp.GetStatus(); status != prevStatus {
 		lp.setStatus(status)
 
 		for _, ev := range statusEvents(prevStatus, status) {
+			// brief disconnects continue the session
+			if prevStatus != api.StatusNone {
+				if ev == evVehicleDisconnect && lp.deferDisconnect() || ev == evVehicleConnect && lp.stitchSession() {
+					continue
+				}
+			}
+
 			lp.bus.Publish(ev)
 
 			// send connect/disconnect events except during startup
@@ -1288,7 +1615,7 @@ func (lp *LoadPoint) pvScalePhases(availablePower, minCurrent, maxCurrent float6
 	scalable := maxPhases > 1 && phases < maxPhases && target1pCurrent > maxCurrent
 
 	// scale up phases
-	if targetCurrent := powerToCurrent(availablePower, maxPhases); targetCurrent >= minCurrent && scalable {
+	if targetCurrent := powerToCurrent(availablePower, maxPhases); targetCurrent >= minCurrent && scalable && lp.surplusSustained(maxPhases, minCurrent) {
 		lp.log.DEBUG.Printf("available power %.0fW > %.0fW min %dp threshold", availablePower, 3*Voltage*minCurrent, maxPhases)
 
 		if lp.phaseTimer.IsZero() {
@@ -1355,12 +1682,19 @@ func (lp *LoadPoint) pvMaxCurrent(mode api.ChargeMode, sitePower float64, batter
 	minCurrent := lp.GetMinCurrent()
 	maxCurrent := lp.GetMaxCurrent()
 
+	// hold back the safety margin in strict pv mode
+	strict := lp.strictActive(mode)
+	if strict {
+		sitePower += lp.Strict.Margin
+	}
+
 	// switch phases up/down
 	if _, ok := lp.charger.(api.PhaseSwitcher); ok {
 		availablePower := -sitePower + lp.chargePower
 
 		// in case of scaling, keep charger disabled for this cycle
 		if lp.pvScalePhases(availablePower, minCurrent, maxCurrent) {
+			lp.setPause(PauseReason{Reason: pausePhaseSwitch})
 			return 0
 		}
 	}
@@ -1373,11 +1707,24 @@ func (lp *LoadPoint) pvMaxCurrent(mode api.ChargeMode, sitePower float64, batter
 
 	lp.log.DEBUG.Printf("pv charge current: %.3gA = %.3gA + %.3gA (%.0fW @ %dp)", targetCurrent, effectiveCurrent, deltaCurrent, sitePower, activePhases)
 
+	// surplus missing for charging at minimum current
+	insufficient := PauseReason{Reason: pauseSurplus, Power: (minCurrent - targetCurrent) * Voltage * float64(activePhases)}
+
 	// in MinPV mode or under special conditions return at least minCurrent
-	if (mode == api.ModeMinPV || batteryBuffered || lp.climateActive()) && targetCurrent < minCurrent {
+	if (mode == api.ModeMinPV || batteryBuffered || lp.climateActive() && !strict) && targetCurrent < minCurrent {
 		return minCurrent
 	}
 
+	// stop immediately on grid import in strict pv mode
+	if strict && targetCurrent < minCurrent {
+		if lp.enabled {
+			lp.log.DEBUG.Printf("strict pv mode: site power %.0fW requires grid import", sitePower)
+		}
+		lp.resetPVTimerIfRunning()
+		lp.setPause(insufficient)
+		return 0
+	}
+
 	if mode == api.ModePV && lp.enabled && targetCurrent < minCurrent {
 		// kick off disable sequence
 		if sitePower >= lp.Disable.Threshold && lp.phaseTimer.IsZero() {
@@ -1393,6 +1740,7 @@ func (lp *LoadPoint) pvMaxCurrent(mode api.ChargeMode, sitePower float64, batter
 			elapsed := lp.clock.Since(lp.pvTimer)
 			if elapsed >= lp.Disable.Delay {
 				lp.log.DEBUG.Println("pv disable timer elapsed")
+				lp.setPause(insufficient)
 				return 0
 			}
 
@@ -1432,9 +1780,16 @@ func (lp *LoadPoint) pvMaxCurrent(mode api.ChargeMode, sitePower float64, batter
 			if elapsed > time.Second {
 				lp.log.DEBUG.Printf("pv enable timer remaining: %v", (lp.Enable.Delay - elapsed).Round(time.Second))
 			}
+
+			lp.pauseUntil(pauseEnableDelay, lp.pvTimer.Add(lp.Enable.Delay))
 		} else {
 			// reset timer
 			lp.resetPVTimerIfRunning("enable")
+
+			if lp.Enable.Threshold != 0 {
+				insufficient.Power = sitePower - lp.Enable.Threshold
+			}
+			lp.setPause(insufficient)
 		}
 
 		// lp.log.DEBUG.Println("pv enable timer: keep disabled")
@@ -1451,9 +1806,14 @@ func (lp *LoadPoint) pvMaxCurrent(mode api.ChargeMode, sitePower float64, batter
 }
 
 // UpdateChargePower updates charge meter power
-func (lp *LoadPoint) UpdateChargePower() {
+func (lp *LoadPoint) UpdateChargePower(ctx context.Context) {
+	// charger powered down
+	if lp.standby || !lp.chargeMeterPoll.due() {
+		return
+	}
+
 	err := retry.Do(func() error {
-		value, err := lp.chargeMeter.CurrentPower()
+		value, err := currentPower(ctx, &lp.chargeMeterEx, lp.chargeMeter)
 		if err != nil {
 			return err
 		}
@@ -1471,9 +1831,11 @@ func (lp *LoadPoint) UpdateChargePower() {
 		}
 
 		return nil
-	}, retryOptions...)
+	}, append(retryOptions, retry.Context(ctx))...)
 	if err != nil {
 		lp.log.ERROR.Printf("charge meter: %v", err)
+	} else {
+		lp.chargeMeterPoll.done()
 	}
 }
 
@@ -1539,10 +1901,34 @@ func (lp *LoadPoint) publishChargeProgress() {
 	if _, ok := lp.chargeMeter.(api.MeterEnergy); ok {
 		lp.publish("chargeTotalImport", lp.chargeMeterTotal())
 	}
+
+	// energy target takes precedence over soc based estimates
+	if lp.GetTargetEnergy() > 0 {
+		remaining := math.Max(float64(lp.GetTargetEnergy())*1e3-lp.getChargedEnergy(), 0)
+		lp.setRemainingEnergy(remaining)
+
+		if lp.charging() && lp.chargePower > 0 {
+			lp.setRemainingDuration(time.Duration(float64(time.Hour) * remaining / lp.chargePower).Round(time.Second))
+		} else {
+			lp.setRemainingDuration(-1)
+		}
+	}
 }
 
 // socPollAllowed validates charging state against polling mode
 func (lp *LoadPoint) socPollAllowed() bool {
+	// back off while rate limited
+	if lp.clock.Now().Before(lp.socBackoff) {
+		lp.log.DEBUG.Printf("soc poll rate limited until: %v", lp.socBackoff.Round(time.Second))
+		return false
+	}
+
+	// vehicle api is not woken up during road trips
+	if lp.tripSuspended() {
+		lp.log.DEBUG.Println("soc poll suspended during road trip")
+		return false
+	}
+
 	remaining := lp.SoC.Poll.Interval - lp.clock.Since(lp.socUpdated)
 
 	honourUpdateInterval := lp.SoC.Poll.Mode == pollAlways ||
@@ -1553,7 +1939,10 @@ func (lp *LoadPoint) socPollAllowed() bool {
 		lp.log.DEBUG.Printf("next soc poll remaining time: %v", remaining.Truncate(time.Second))
 	}
 
-	return lp.charging() || honourUpdateInterval && (remaining <= 0) || lp.connected() && lp.socUpdated.IsZero()
+	// poll while charging unless limited by the vehicle interval
+	charging := lp.charging() && (lp.vehicleInterval == 0 || lp.clock.Since(lp.socUpdated) >= lp.vehicleInterval-pollTolerance)
+
+	return charging || honourUpdateInterval && (remaining <= 0) || lp.connected() && lp.socUpdated.IsZero()
 }
 
 // checks if the connected charger can provide SoC to the connected vehicle
@@ -1566,6 +1955,25 @@ func (lp *LoadPoint) socProvidedByCharger() bool {
 	return false
 }
 
+// socError handles vehicle soc read errors
+func (lp *LoadPoint) socError(err error) {
+	switch {
+	case errors.Is(err, api.ErrMustRetry):
+		lp.socUpdated = time.Time{}
+	case errors.Is(err, api.ErrRateLimited):
+		lp.socBackoff = lp.clock.Now().Add(socRateLimitBackoff)
+		lp.log.WARN.Printf("vehicle soc: %v, backing off for %v", err, socRateLimitBackoff)
+	case errors.Is(err, api.ErrAuthExpired):
+		lp.log.ERROR.Printf("vehicle soc: %v", err)
+		if !lp.authExpired {
+			lp.authExpired = true
+			lp.pushEvent(evVehicleAuthExpired)
+		}
+	default:
+		lp.log.ERROR.Printf("vehicle soc: %v", err)
+	}
+}
+
 // publish state of charge, remaining charge duration and range
 func (lp *LoadPoint) publishSoCAndRange() {
 	if lp.socEstimator == nil {
@@ -1585,28 +1993,42 @@ func (lp *LoadPoint) publishSoCAndRange() {
 		}
 
 		if err != nil {
-			if errors.Is(err, api.ErrMustRetry) {
-				lp.socUpdated = time.Time{}
-			} else {
-				lp.log.ERROR.Printf("vehicle soc: %v", err)
-			}
-
+			lp.socError(err)
 			return
 		}
 
+		// estimated from the previous soc if the read failed
+		read, err := lp.socEstimator.Read()
+		if err != nil {
+			lp.socError(err)
+		} else {
+			lp.authExpired = false
+		}
+
 		lp.vehicleSoc = math.Trunc(f)
 		lp.log.DEBUG.Printf("vehicle soc: %.0f%%", lp.vehicleSoc)
 		lp.publish("vehicleSoC", lp.vehicleSoc)
 
+		// estimation is exposed separately from the measurement
 		if se := lp.socEstimator; se != nil {
+			lp.publish("vehicleSoCMeasured", se.Measured())
+			lp.publish("vehicleSoCEstimated", f)
+			lp.publish("vehicleSoCConfidence", se.Confidence())
+		}
+
+		if !read.Equal(lp.socRead) {
+			lp.socRead = read
+			lp.publish("vehicleSoCUpdated", lp.socRead)
+		}
+
+		// remaining values are derived from charged energy if energy target is set
+		if se := lp.socEstimator; se != nil && lp.GetTargetEnergy() == 0 {
 			if lp.charging() {
 				lp.setRemainingDuration(se.RemainingChargeDuration(lp.chargePower, lp.SoC.target))
 			} else {
 				lp.setRemainingDuration(-1)
 			}
-		}
 
-		if se := lp.socEstimator; se != nil {
 			lp.setRemainingEnergy(1e3 * se.RemainingChargeEnergy(lp.SoC.target))
 		}
 
@@ -1655,8 +2077,24 @@ func (lp *LoadPoint) processTasks() {
 }
 
 // Update is the main control function. It reevaluates meters and charger state
-func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
+func (lp *LoadPoint) Update(ctx context.Context, sitePower float64, cheap, batteryBuffered bool) {
 	lp.processTasks()
+	lp.updateAnnotation()
+	lp.applyRepeatingPlans()
+	lp.updatePlanLock()
+	cheap = lp.lockedCheap(lp.clock.Now(), cheap)
+
+	// explain why not charging and estimate completion, then reflect the state on the charger
+	lp.pause = nil
+	defer lp.updateIndicator()
+	defer lp.publishPause()
+	defer lp.publishCompletion()
+
+	// charger powered down
+	if lp.updateStandby() {
+		lp.setPause(PauseReason{Reason: pauseStandby})
+		return
+	}
 
 	mode := lp.GetMode()
 	lp.publish("mode", mode)
@@ -1670,13 +2108,17 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 
 	// update progress and soc before status is updated
 	lp.publishChargeProgress()
+	lp.updateMeterCheck()
 
 	// read and publish status
-	if err := lp.updateChargerStatus(); err != nil {
+	if err := lp.updateChargerStatus(ctx); err != nil {
 		lp.log.ERROR.Printf("charger: %v", err)
+		lp.setPause(PauseReason{Reason: pauseChargerFault, Detail: err.Error()})
 		return
 	}
 
+	lp.expireDisconnect()
+
 	lp.publish("connected", lp.connected())
 	lp.publish("charging", lp.charging())
 	lp.publish("enabled", lp.enabled)
@@ -1713,27 +2155,33 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 	case !lp.connected():
 		// always disable charger if not connected
 		// https://github.com/evcc-io/evcc/issues/105
+		lp.setPause(PauseReason{Reason: pauseDisconnected})
 		err = lp.setLimit(0, false)
 
 	case lp.scalePhasesRequired():
+		lp.setPause(PauseReason{Reason: pausePhaseSwitch})
 		if err = lp.scalePhases(lp.ConfiguredPhases); err == nil {
 			lp.log.DEBUG.Printf("switched phases: %dp", lp.ConfiguredPhases)
 		}
 
 	case lp.targetEnergyReached():
 		lp.log.DEBUG.Printf("targetEnergy reached: %.0fkWh > %dkWh", lp.getChargedEnergy()/1e3, lp.targetEnergy)
+		lp.setPause(PauseReason{Reason: pauseTargetReached, Detail: fmt.Sprintf("%dkWh", lp.targetEnergy)})
 		err = lp.disableUnlessClimater()
 
-	case lp.targetSocReached():
+	case lp.targetSocReached() && !lp.staleSoCPlan():
 		lp.log.DEBUG.Printf("targetSoC reached: %.1f%% > %d%%", lp.vehicleSoc, lp.SoC.target)
+		lp.setPause(PauseReason{Reason: pauseTargetReached, Detail: fmt.Sprintf("%d%%", lp.SoC.target)})
 		err = lp.disableUnlessClimater()
 
 	// OCPP has priority over target charging
 	case lp.remoteControlled(loadpoint.RemoteHardDisable):
 		remoteDisabled = loadpoint.RemoteHardDisable
+		lp.setPause(PauseReason{Reason: pauseRemote, Detail: string(remoteDisabled)})
 		fallthrough
 
 	case mode == api.ModeOff:
+		lp.setPause(PauseReason{Reason: pauseModeOff})
 		err = lp.setLimit(0, true)
 
 	case lp.minSocNotReached():
@@ -1760,15 +2208,18 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 	case mode == api.ModeMinPV || mode == api.ModePV:
 		targetCurrent := lp.pvMaxCurrent(mode, sitePower, batteryBuffered)
 
-		var required bool // false
-		if targetCurrent == 0 && lp.climateActive() {
+		// strict pv mode bypasses the guard duration to stop on grid import
+		strict := lp.strictActive(mode)
+		required := strict && targetCurrent == 0
+
+		if targetCurrent == 0 && lp.climateActive() && !strict {
 			lp.log.DEBUG.Println("climater active")
 			targetCurrent = lp.GetMinCurrent()
 			required = true
 		}
 
 		// tariff
-		if cheap {
+		if cheap && !strict {
 			targetCurrent = lp.GetMaxCurrent()
 			lp.log.DEBUG.Printf("cheap tariff: %.3gA", targetCurrent)
 			required = true
@@ -1777,6 +2228,7 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 		// Sunny Home Manager
 		if lp.remoteControlled(loadpoint.RemoteSoftDisable) {
 			remoteDisabled = loadpoint.RemoteSoftDisable
+			lp.pause = &PauseReason{Reason: pauseRemote, Detail: string(remoteDisabled)}
 			targetCurrent = 0
 			required = true
 		}
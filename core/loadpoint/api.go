@@ -35,6 +35,10 @@ type API interface {
 	GetTargetEnergy() int
 	// SetTargetEnergy sets the charge target energy
 	SetTargetEnergy(int)
+	// GetTargetRange returns the charge target range
+	GetTargetRange() int
+	// SetTargetRange sets the charge target range as absolute vehicle range in km, converted to a target soc
+	SetTargetRange(int) error
 	// GetTargetSoC returns the charge target soc
 	GetTargetSoC() int
 	// SetTargetSoC sets the charge target soc
@@ -48,8 +52,14 @@ type API interface {
 	// SetPhases sets the enabled phases
 	SetPhases(int) error
 
+	// GetTargetTime returns the target charging time
+	GetTargetTime() time.Time
 	// SetTargetCharge sets the charge targetSoC
 	SetTargetCharge(time.Time, int)
+	// GetRepeatingPlans returns the active vehicle's weekly recurring target charges
+	GetRepeatingPlans() []RepeatingPlan
+	// SetRepeatingPlans sets the active vehicle's weekly recurring target charges
+	SetRepeatingPlans([]RepeatingPlan) error
 	// RemoteControl sets remote status demand
 	RemoteControl(string, RemoteDemand)
 
@@ -82,6 +92,8 @@ type API interface {
 	GetRemainingDuration() time.Duration
 	// GetRemainingEnergy is the remaining charge energy in Wh
 	GetRemainingEnergy() float64
+	// GetChargedEnergy is the energy charged during the current session in Wh
+	GetChargedEnergy() float64
 
 	//
 	// vehicles
@@ -91,4 +103,13 @@ type API interface {
 	SetVehicle(vehicle api.Vehicle)
 	// StartVehicleDetection allows triggering vehicle detection for debugging purposes
 	StartVehicleDetection()
+	// WakeCharger powers up the charger from standby
+	WakeCharger()
+
+	//
+	// session
+	//
+
+	// AnnotateSession attaches tags and note to the current session
+	AnnotateSession(tags []string, note string)
 }
@@ -0,0 +1,8 @@
+package loadpoint
+
+// RepeatingPlan is a weekly recurring target charge
+type RepeatingPlan struct {
+	Days []string `json:"days"` // mon..sun, weekdays or weekend, every day if empty
+	Time string   `json:"time"` // departure time of day hh:mm
+	SoC  int      `json:"soc"`  // target soc
+}
@@ -17,3 +17,7 @@ func (a *adapter) Publish(key string, val interface{}) {
 func (a *adapter) SocEstimator() *soc.Estimator {
 	return a.LoadPoint.socEstimator
 }
+
+func (a *adapter) SocStale() bool {
+	return a.LoadPoint.socStale()
+}
@@ -1,11 +1,14 @@
 package core
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/soc"
 	"github.com/evcc-io/evcc/core/wrapper"
 )
 
@@ -41,6 +44,7 @@ func (lp *LoadPoint) SetMode(mode api.ChargeMode) {
 	if lp.Mode != mode {
 		lp.Mode = mode
 		lp.publish("mode", mode)
+		lp.record(eventMode, string(mode))
 
 		// immediately allow pv mode activity
 		lp.elapsePVTimer()
@@ -63,20 +67,25 @@ func (lp *LoadPoint) setChargedEnergy(energy float64) {
 	lp.chargedEnergy = energy
 }
 
+// GetChargedEnergy returns the energy charged during the current session in Wh
+func (lp *LoadPoint) GetChargedEnergy() float64 {
+	return lp.getChargedEnergy()
+}
+
 // GetTargetEnergy returns loadpoint charge target energy
 func (lp *LoadPoint) GetTargetEnergy() int {
 	lp.Lock()
 	defer lp.Unlock()
-	return lp.SoC.target
+	return lp.targetEnergy
 }
 
 // setTargetEnergy sets loadpoint charge target energy (no mutex)
 func (lp *LoadPoint) setTargetEnergy(energy int) {
 	lp.targetEnergy = energy
-	// TODO soctimer
-	// if lp.socTimer != nil {
-	// lp.socTimer.Energy = energy
-	// }
+	// test guard
+	if lp.socTimer != nil {
+		lp.socTimer.Energy = energy
+	}
 	lp.publish("targetEnergy", energy)
 }
 
@@ -94,6 +103,59 @@ func (lp *LoadPoint) SetTargetEnergy(energy int) {
 	}
 }
 
+// GetTargetRange returns loadpoint charge target range
+func (lp *LoadPoint) GetTargetRange() int {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.targetRange
+}
+
+// setTargetRange sets loadpoint charge target range (no mutex)
+func (lp *LoadPoint) setTargetRange(km int) {
+	lp.targetRange = km
+	lp.publish("targetRange", km)
+}
+
+// SetTargetRange sets loadpoint charge target range. The range is the absolute vehicle range after charging,
+// converted into a target soc using the learned vehicle consumption and the vehicle's capacity.
+func (lp *LoadPoint) SetTargetRange(km int) error {
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.log.DEBUG.Println("set target range:", km)
+
+	if km == 0 {
+		lp.setTargetRange(0)
+		return nil
+	}
+
+	if lp.vehicle == nil {
+		return errors.New("vehicle not identified")
+	}
+
+	consumption := soc.VehicleConsumption(lp.vehicle.Title())
+	if consumption.Value() == 0 {
+		return errors.New("vehicle consumption not yet learned")
+	}
+
+	capacity := lp.vehicle.Capacity()
+	if capacity == 0 || lp.vehicleHasFeature(api.Offline) {
+		return errors.New("target range requires vehicle soc and capacity")
+	}
+
+	socTarget := int(math.Min(math.Ceil(100*consumption.Energy(km)/capacity), 100))
+	lp.log.DEBUG.Printf("target range: %dkm = %d%% @ %.1fkWh/100km", km, socTarget, consumption.Value())
+	lp.setTargetSoC(socTarget)
+
+	// apply immediately
+	if lp.targetRange != km {
+		lp.setTargetRange(km)
+		lp.requestUpdate()
+	}
+
+	return nil
+}
+
 // GetTargetSoC returns loadpoint charge target soc
 func (lp *LoadPoint) GetTargetSoC() int {
 	lp.Lock()
@@ -170,6 +232,10 @@ func (lp *LoadPoint) SetPhases(phases int) error {
 		return fmt.Errorf("invalid number of phases: %d", phases)
 	}
 
+	if err := lp.Physical.validatePhases(phases); err != nil {
+		return err
+	}
+
 	// set new default
 	lp.log.DEBUG.Println("set phases:", phases)
 	lp.setConfiguredPhases(phases)
@@ -184,6 +250,13 @@ func (lp *LoadPoint) SetPhases(phases int) error {
 	return nil
 }
 
+// GetTargetTime returns the target charging time
+func (lp *LoadPoint) GetTargetTime() time.Time {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.socTimer.Time
+}
+
 // SetTargetCharge sets loadpoint charge targetSoC
 func (lp *LoadPoint) SetTargetCharge(finishAt time.Time, soc int) {
 	lp.Lock()
@@ -195,6 +268,12 @@ func (lp *LoadPoint) SetTargetCharge(finishAt time.Time, soc int) {
 	if lp.socTimer.Time != finishAt || lp.SoC.target != soc {
 		lp.socTimer.Set(finishAt)
 
+		if finishAt.IsZero() {
+			lp.record(eventPlan, "removed")
+		} else {
+			lp.record(eventPlan, fmt.Sprintf("%d%% at %s", soc, finishAt.Format(time.RFC3339)))
+		}
+
 		// don't remove soc
 		if !finishAt.IsZero() {
 			lp.setTargetSoC(soc)
@@ -247,6 +326,7 @@ func (lp *LoadPoint) SetMinCurrent(current float64) {
 	defer lp.Unlock()
 
 	lp.log.DEBUG.Println("set min current:", current)
+	current = lp.physicalCurrent(current)
 
 	if current != lp.MinCurrent {
 		lp.MinCurrent = current
@@ -267,6 +347,7 @@ func (lp *LoadPoint) SetMaxCurrent(current float64) {
 	defer lp.Unlock()
 
 	lp.log.DEBUG.Println("set max current:", current)
+	current = lp.physicalCurrent(current)
 
 	if current != lp.MaxCurrent {
 		lp.MaxCurrent = current
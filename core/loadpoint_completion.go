@@ -0,0 +1,101 @@
+package core
+
+import (
+	"math"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// completion uncertainties relative to the remaining duration
+const (
+	completionEnergyUncertainty  = 0.05 // energy measured at the charger
+	completionSocUncertainty     = 0.1  // energy derived from the vehicle soc and learned charge curve
+	completionEstimatePenalty    = 0.3  // added for estimated soc without confidence
+	completionSurplusUncertainty = 0.25 // charge power following pv surplus
+)
+
+// Completion is the estimated end of charging with its confidence range
+type Completion struct {
+	Time       time.Time `json:"time"`
+	Earliest   time.Time `json:"earliest"`
+	Latest     time.Time `json:"latest"`
+	Confidence float64   `json:"confidence"` // between 0 and 1
+}
+
+// remainingCompletionEnergy returns the remaining energy in Wh and its relative uncertainty
+func (lp *LoadPoint) remainingCompletionEnergy() (float64, float64) {
+	if lp.GetTargetEnergy() > 0 {
+		return lp.GetRemainingEnergy(), completionEnergyUncertainty
+	}
+
+	se := lp.socEstimator
+	if se == nil {
+		return 0, 0
+	}
+
+	return 1e3 * se.RemainingModelEnergy(lp.SoC.target), completionSocUncertainty + completionEstimatePenalty*(1-se.Confidence())
+}
+
+// estimateCompletion estimates the end of charging from current power or the planned start at max power
+func (lp *LoadPoint) estimateCompletion() *Completion {
+	if !lp.connected() {
+		return nil
+	}
+
+	energy, uncertainty := lp.remainingCompletionEnergy()
+	if energy <= 0 {
+		return nil
+	}
+
+	start := lp.clock.Now()
+	var power float64
+
+	switch ts := lp.socTimer.ProjectedStart(); {
+	case !ts.IsZero():
+		// planner charges at max power
+		start, power = ts, lp.GetMaxPower()
+
+	case lp.charging() && lp.chargePower > 0:
+		power = lp.chargePower
+		if mode := lp.GetMode(); mode == api.ModePV || mode == api.ModeMinPV {
+			uncertainty += completionSurplusUncertainty
+		}
+
+	default:
+		// not charging, completion unknown
+		return nil
+	}
+
+	if power <= 0 {
+		return nil
+	}
+
+	duration := time.Duration(float64(time.Hour) * energy / power)
+	spread := time.Duration(float64(duration) * math.Min(uncertainty, 1))
+
+	return &Completion{
+		Time:       start.Add(duration).Round(time.Minute),
+		Earliest:   start.Add(duration - spread).Round(time.Minute),
+		Latest:     start.Add(duration + spread).Round(time.Minute),
+		Confidence: math.Round(100*math.Max(0, 1-uncertainty)) / 100,
+	}
+}
+
+// publishCompletion publishes the estimated end of charging if changed
+func (lp *LoadPoint) publishCompletion() {
+	completion := lp.estimateCompletion()
+
+	if completion == nil && lp.completion == nil ||
+		completion != nil && lp.completion != nil && *completion == *lp.completion {
+		return
+	}
+
+	lp.completion = completion
+
+	if completion == nil {
+		lp.publish("chargeCompletion", nil)
+	} else {
+		lp.publish("chargeCompletion", completion)
+	}
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletion(t *testing.T) {
+	clck := clock.NewMock()
+
+	Voltage = 100
+	lp := &LoadPoint{
+		log:                   util.NewLogger("foo"),
+		clock:                 clck,
+		MaxCurrent:            10,
+		phases:                1,
+		measuredPhases:        1,
+		status:                api.StatusB,
+		Mode:                  api.ModeNow,
+		targetEnergy:          10,
+		chargeRemainingEnergy: 5000,
+	}
+
+	// not charging
+	assert.Nil(t, lp.estimateCompletion())
+
+	// charging 5kWh remaining at 1kW
+	lp.status = api.StatusC
+	lp.chargePower = 1000
+
+	c := lp.estimateCompletion()
+	require.NotNil(t, c)
+	assert.Equal(t, clck.Now().Add(5*time.Hour), c.Time)
+	assert.Equal(t, clck.Now().Add(5*time.Hour-15*time.Minute), c.Earliest)
+	assert.Equal(t, clck.Now().Add(5*time.Hour+15*time.Minute), c.Latest)
+	assert.Equal(t, 0.95, c.Confidence)
+
+	// surplus charging widens range
+	lp.Mode = api.ModePV
+
+	c = lp.estimateCompletion()
+	require.NotNil(t, c)
+	assert.Equal(t, clck.Now().Add(5*time.Hour-90*time.Minute), c.Earliest)
+	assert.Equal(t, 0.7, c.Confidence)
+
+	// planned start at max power
+	lp.status = api.StatusB
+	lp.chargePower = 0
+	lp.chargeRemainingEnergy = 10000
+	lp.socTimer = soc.NewTimer(lp.log, &adapter{LoadPoint: lp})
+	lp.socTimer.SetClock(clck)
+	lp.socTimer.Energy = 10
+	lp.socTimer.Set(clck.Now().Add(24 * time.Hour))
+	require.False(t, lp.socTimer.DemandActive())
+
+	c = lp.estimateCompletion()
+	require.NotNil(t, c)
+	assert.Equal(t, clck.Now().Add(24*time.Hour), c.Time)
+
+	// disconnected
+	lp.status = api.StatusA
+	assert.Nil(t, lp.estimateCompletion())
+}
+
+func TestCompletionSoC(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	charger := mock.NewMockCharger(ctrl)
+	vehicle := mock.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Capacity().Return(float64(9)).AnyTimes()
+	vehicle.EXPECT().SoC().Return(20.0, nil)
+
+	Voltage = 100
+	lp := &LoadPoint{
+		log:            util.NewLogger("foo"),
+		clock:          clck,
+		MaxCurrent:     10,
+		phases:         1,
+		measuredPhases: 1,
+		status:         api.StatusC,
+		chargePower:    1000,
+		Mode:           api.ModeNow,
+		SoC:            SoCConfig{target: 80},
+	}
+
+	// 100Wh per percent including charge efficiency
+	lp.socEstimator = soc.NewEstimator(lp.log, charger, vehicle, false)
+	_, err := lp.socEstimator.SoC(0)
+	require.NoError(t, err)
+
+	c := lp.estimateCompletion()
+	require.NotNil(t, c)
+	assert.Equal(t, clck.Now().Add(6*time.Hour), c.Time)
+	assert.Equal(t, 0.9, c.Confidence)
+
+	// learned charge curve requires more energy above the knee
+	lp.SoC.target = 100
+	linear := lp.estimateCompletion()
+	lp.socEstimator.SetModel(soc.Curve{Knee: 80, Factor: 2})
+
+	c = lp.estimateCompletion()
+	require.NotNil(t, c)
+	assert.True(t, c.Time.After(linear.Time))
+}
+
+func TestPublishCompletion(t *testing.T) {
+	clck := clock.NewMock()
+	uiChan := make(chan util.Param, 2)
+
+	Voltage = 100
+	lp := &LoadPoint{
+		log:                   util.NewLogger("foo"),
+		clock:                 clck,
+		uiChan:                uiChan,
+		status:                api.StatusC,
+		chargePower:           1000,
+		Mode:                  api.ModeNow,
+		targetEnergy:          10,
+		chargeRemainingEnergy: 5000,
+	}
+
+	// published once
+	lp.publishCompletion()
+	lp.publishCompletion()
+	require.Len(t, uiChan, 1)
+	p := <-uiChan
+	assert.Equal(t, "chargeCompletion", p.Key)
+	assert.NotNil(t, p.Val)
+
+	// cleared when disconnected
+	lp.status = api.StatusA
+	lp.publishCompletion()
+	require.Len(t, uiChan, 1)
+	assert.Nil(t, (<-uiChan).Val)
+}
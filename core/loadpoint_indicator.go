@@ -0,0 +1,97 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// indicator states in addition to the pause reasons
+const (
+	indicateCharging = "charging"
+	indicateDefault  = "default" // states without configured indication
+)
+
+// indicatorStates are the states an indication can be configured for
+var indicatorStates = []string{
+	indicateCharging, indicateDefault,
+	pauseDisconnected, pauseChargerFault, pauseModeOff, pauseTargetReached, pauseRemote, pausePhaseSwitch,
+	pauseSurplus, pauseEnableDelay, pausePlanned, pauseContactor, pauseCycleLimit, pauseRestricted, pauseVehicle,
+}
+
+// configureIndicator validates the indications by state. Config keys are case-insensitive.
+func (lp *LoadPoint) configureIndicator() error {
+	if len(lp.Indications) == 0 {
+		return nil
+	}
+
+	indicator, ok := lp.charger.(api.Indicator)
+	if !ok {
+		return errors.New("indicator: charger does not support led or display control")
+	}
+
+	indications := make(map[string]api.Indication, len(lp.Indications))
+
+IND:
+	for key, ind := range lp.Indications {
+		for _, state := range indicatorStates {
+			if strings.EqualFold(key, state) {
+				indications[state] = ind
+				continue IND
+			}
+		}
+
+		return fmt.Errorf("indicator: invalid state: %s", key)
+	}
+
+	lp.indicator = indicator
+	lp.Indications = indications
+
+	return nil
+}
+
+// indicatorState returns the loadpoint state reflected by the indicator
+func (lp *LoadPoint) indicatorState() string {
+	if lp.charging() {
+		return indicateCharging
+	}
+
+	if lp.pause != nil {
+		return lp.pause.Reason
+	}
+
+	return indicateDefault
+}
+
+// updateIndicator drives the charger's LED or display to reflect the loadpoint state once it changed
+func (lp *LoadPoint) updateIndicator() {
+	if lp.indicator == nil || lp.standby {
+		return
+	}
+
+	ind, ok := lp.Indications[lp.indicatorState()]
+	if !ok {
+		if ind, ok = lp.Indications[indicateDefault]; !ok {
+			return
+		}
+	}
+
+	if lp.indicated != nil && *lp.indicated == ind {
+		return
+	}
+
+	if err := lp.indicator.Indicate(ind); err != nil {
+		if errors.Is(err, api.ErrNotAvailable) {
+			lp.log.WARN.Println("indicator: not supported by charger")
+			lp.indicator = nil
+			return
+		}
+
+		lp.log.ERROR.Printf("indicator: %v", err)
+		return
+	}
+
+	lp.indicated = &ind
+}
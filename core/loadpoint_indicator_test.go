@@ -0,0 +1,68 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type indicatorCharger struct {
+	api.Charger
+	err         error
+	indications []api.Indication
+}
+
+func (c *indicatorCharger) Indicate(ind api.Indication) error {
+	c.indications = append(c.indications, ind)
+	return c.err
+}
+
+func TestIndicator(t *testing.T) {
+	sun := api.Indication{Color: "#ffff00", Text: "Waiting for sun"}
+	charging := api.Indication{Color: "#00ff00", Brightness: 100}
+	idle := api.Indication{Brightness: 10}
+
+	charger := new(indicatorCharger)
+	lp := &LoadPoint{
+		log:     util.NewLogger("foo"),
+		charger: charger,
+		status:  api.StatusB,
+		Indications: map[string]api.Indication{
+			"insufficientsurplus": sun,
+			"Charging":            charging,
+			"default":             idle,
+		},
+	}
+	require.NoError(t, lp.configureIndicator())
+
+	// indication is sent once per state
+	lp.pause = &PauseReason{Reason: pauseSurplus}
+	lp.updateIndicator()
+	lp.updateIndicator()
+	assert.Equal(t, []api.Indication{sun}, charger.indications)
+
+	lp.status = api.StatusC
+	lp.updateIndicator()
+	assert.Equal(t, charging, charger.indications[1])
+
+	// unmapped states use the default indication
+	lp.status = api.StatusB
+	lp.pause = &PauseReason{Reason: pausePlanned}
+	lp.updateIndicator()
+	assert.Equal(t, idle, charger.indications[2])
+
+	// unsupported indicator is disabled
+	charger.err = api.ErrNotAvailable
+	lp.pause = &PauseReason{Reason: pauseSurplus}
+	lp.updateIndicator()
+	assert.Nil(t, lp.indicator)
+
+	lp.Indications = map[string]api.Indication{"sunny": sun}
+	assert.Error(t, lp.configureIndicator(), "invalid state")
+
+	lp.charger = struct{ api.Charger }{}
+	assert.Error(t, lp.configureIndicator(), "charger without indicator")
+}
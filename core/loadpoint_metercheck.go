@@ -0,0 +1,175 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+const (
+	meterPrimaryMeter   = "meter"
+	meterPrimaryCharger = "charger"
+
+	meterCheckInterval = 5 * time.Minute // interval of meter checks while charging
+)
+
+// MeterCheckConfig compares a separate charge meter with the charger's internal meter
+type MeterCheckConfig struct {
+	Primary   string  `mapstructure:"primary"`   // meter used for session billing, meter (default) or charger
+	Threshold float64 `mapstructure:"threshold"` // maximum deviation in %
+	MinEnergy float64 `mapstructure:"minEnergy"` // energy in kWh charged before comparing
+}
+
+// meterCheck tracks the energy charged during a session as measured by both meters
+type meterCheck struct {
+	MeterCheckConfig
+	meter, charger           api.MeterEnergy
+	meterStart, chargerStart float64
+	divergent                bool      // deviation already reported for the session
+	checked                  time.Time // last check while charging
+}
+
+// configureMeterCheck sets up the cross check if both charge meter and charger measure energy
+func (lp *LoadPoint) configureMeterCheck() error {
+	cc := lp.MeterCheck
+
+	switch cc.Primary {
+	case "", meterPrimaryMeter, meterPrimaryCharger:
+	default:
+		return fmt.Errorf("meter check: invalid primary meter: %s", cc.Primary)
+	}
+
+	me, ok := lp.chargeMeter.(api.MeterEnergy)
+	ce, ok2 := lp.charger.(api.MeterEnergy)
+
+	if !ok || !ok2 || lp.MeterRef == "" {
+		if cc.Primary == meterPrimaryCharger {
+			return errors.New("meter check: requires charge meter and charger both measuring energy")
+		}
+		return nil
+	}
+
+	if cc.Threshold == 0 {
+		cc.Threshold = 5
+	}
+	if cc.MinEnergy == 0 {
+		cc.MinEnergy = 1
+	}
+
+	lp.meterCheck = &meterCheck{
+		MeterCheckConfig: cc,
+		meter:            me,
+		charger:          ce,
+	}
+
+	return nil
+}
+
+// totals returns both meter readings in kWh
+func (mc *meterCheck) totals() (float64, float64, error) {
+	m, err := mc.meter.TotalEnergy()
+	if err != nil {
+		return 0, 0, fmt.Errorf("charge meter: %w", err)
+	}
+
+	c, err := mc.charger.TotalEnergy()
+	if err != nil {
+		return 0, 0, fmt.Errorf("charger meter: %w", err)
+	}
+
+	return m, c, nil
+}
+
+// start records the meter readings at session start
+func (mc *meterCheck) start() error {
+	m, c, err := mc.totals()
+	if err == nil {
+		mc.meterStart, mc.chargerStart = m, c
+		mc.divergent = false
+	}
+	return err
+}
+
+// deviation returns the energy charged by both meters and their deviation in % of the larger amount.
+// Deviation is only valid once the minimum energy has been charged.
+func (mc *meterCheck) deviation() (float64, float64, float64, bool, error) {
+	m, c, err := mc.totals()
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+
+	m -= mc.meterStart
+	c -= mc.chargerStart
+
+	max := math.Max(m, c)
+	if max < mc.MinEnergy {
+		return m, c, 0, false, nil
+	}
+
+	return m, c, 100 * math.Abs(m-c) / max, true, nil
+}
+
+// billingMeterTotal returns the reading of the meter used for session billing
+func (lp *LoadPoint) billingMeterTotal() (float64, error) {
+	if lp.meterCheck == nil || lp.meterCheck.Primary != meterPrimaryCharger {
+		return lp.chargeMeterTotal(), nil
+	}
+
+	f, err := lp.meterCheck.charger.TotalEnergy()
+	if err != nil {
+		return 0, fmt.Errorf("charger meter energy: %w", err)
+	}
+
+	return f, nil
+}
+
+// startMeterCheck records the meter readings at session start
+func (lp *LoadPoint) startMeterCheck() {
+	if lp.meterCheck == nil {
+		return
+	}
+
+	if err := lp.meterCheck.start(); err != nil {
+		lp.log.ERROR.Printf("meter check: %v", err)
+	}
+}
+
+// updateMeterCheck compares the meters periodically while charging
+func (lp *LoadPoint) updateMeterCheck() {
+	if lp.meterCheck == nil || lp.session == nil || !lp.charging() || lp.clock.Since(lp.meterCheck.checked) < meterCheckInterval {
+		return
+	}
+
+	lp.meterCheck.checked = lp.clock.Now()
+	lp.checkMeters()
+}
+
+// checkMeters compares the energy charged during the session and reports divergent meters
+func (lp *LoadPoint) checkMeters() {
+	if lp.meterCheck == nil {
+		return
+	}
+
+	m, c, deviation, ok, err := lp.meterCheck.deviation()
+	if err != nil {
+		lp.log.ERROR.Printf("meter check: %v", err)
+		return
+	}
+
+	if !ok {
+		return
+	}
+
+	lp.publish("meterDeviation", deviation)
+
+	if deviation > lp.meterCheck.Threshold && !lp.meterCheck.divergent {
+		lp.meterCheck.divergent = true
+
+		detail := fmt.Sprintf("charge meter %.3fkWh, charger %.3fkWh (%.1f%%)", m, c, deviation)
+		lp.log.WARN.Println("meter check: divergent meters:", detail)
+		lp.record(eventMeter, detail)
+	}
+}
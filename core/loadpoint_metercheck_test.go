@@ -0,0 +1,85 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeterCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	meter := mock.NewMockMeterEnergy(ctrl)
+	charger := mock.NewMockMeterEnergy(ctrl)
+
+	mc := &meterCheck{
+		MeterCheckConfig: MeterCheckConfig{Threshold: 5, MinEnergy: 1},
+		meter:            meter,
+		charger:          charger,
+	}
+
+	meter.EXPECT().TotalEnergy().Return(100.0, nil)
+	charger.EXPECT().TotalEnergy().Return(2000.0, nil)
+	require.NoError(t, mc.start())
+
+	// below minimum energy
+	meter.EXPECT().TotalEnergy().Return(100.5, nil)
+	charger.EXPECT().TotalEnergy().Return(2000.4, nil)
+	_, _, _, ok, err := mc.deviation()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// deviation relative to larger amount
+	meter.EXPECT().TotalEnergy().Return(110.0, nil)
+	charger.EXPECT().TotalEnergy().Return(2009.0, nil)
+	m, c, deviation, ok, err := mc.deviation()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 10.0, m)
+	assert.Equal(t, 9.0, c)
+	assert.InDelta(t, 10.0, deviation, 1e-9)
+}
+
+func TestMeterCheckPeriodic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	meter := mock.NewMockMeterEnergy(ctrl)
+	charger := mock.NewMockMeterEnergy(ctrl)
+
+	lp := &LoadPoint{
+		log:     util.NewLogger("foo"),
+		clock:   clck,
+		status:  api.StatusC,
+		session: new(db.Session),
+		meterCheck: &meterCheck{
+			MeterCheckConfig: MeterCheckConfig{Primary: meterPrimaryCharger, Threshold: 5, MinEnergy: 1},
+			meter:            meter,
+			charger:          charger,
+		},
+	}
+
+	// divergence detected while charging
+	meter.EXPECT().TotalEnergy().Return(10.0, nil)
+	charger.EXPECT().TotalEnergy().Return(8.0, nil)
+	lp.updateMeterCheck()
+	assert.True(t, lp.meterCheck.divergent)
+
+	// not before interval
+	clck.Add(time.Minute)
+	lp.updateMeterCheck()
+
+	// billing reading errors are propagated
+	charger.EXPECT().TotalEnergy().Return(0.0, errors.New("foo"))
+	_, err := lp.billingMeterTotal()
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// pause reasons explaining why the loadpoint is not charging
+const (
+	pauseDisconnected  = "disconnected"
+	pauseStandby       = "standby"
+	pauseChargerFault  = "chargerFault"
+	pauseModeOff       = "modeOff"
+	pauseTargetReached = "targetReached"
+	pauseRemote        = "remoteDisabled"
+	pausePhaseSwitch   = "phaseSwitch"
+	pauseSurplus       = "insufficientSurplus"
+	pauseEnableDelay   = "enableDelay"
+	pausePlanned       = "plannedStart"
+	pauseContactor     = "contactorDelay"
+	pauseCycleLimit    = "cycleLimit"
+	pauseRestricted    = "restricted" // load shedding, island operation, shared supply, rotation or budget
+	pauseVehicle       = "vehicle"    // charger enabled but vehicle not charging
+)
+
+// PauseReason explains why the loadpoint is not charging
+type PauseReason struct {
+	Reason string     `json:"reason"`
+	Detail string     `json:"detail,omitempty"`
+	Power  float64    `json:"power,omitempty"` // missing surplus power in W
+	Time   *time.Time `json:"time,omitempty"`  // end of delay or planned start
+}
+
+// setPause records the reason for not charging unless a reason has already been recorded this cycle
+func (lp *LoadPoint) setPause(pause PauseReason) {
+	if lp.pause == nil {
+		lp.pause = &pause
+	}
+}
+
+// pauseUntil records the reason for not charging until the given time
+func (lp *LoadPoint) pauseUntil(reason string, ts time.Time) {
+	lp.setPause(PauseReason{Reason: reason, Time: &ts})
+}
+
+// publishPause publishes the cycle's reason for not charging
+func (lp *LoadPoint) publishPause() {
+	switch {
+	case lp.charging():
+		lp.pause = nil
+
+	case lp.status == api.StatusE || lp.status == api.StatusF:
+		lp.pause = &PauseReason{Reason: pauseChargerFault, Detail: fmt.Sprintf("status %s", lp.status)}
+
+	case lp.pause == nil || lp.pause.Reason == pauseSurplus:
+		// waiting for planned start takes precedence over surplus
+		if ts := lp.socTimer.ProjectedStart(); !ts.IsZero() {
+			lp.pause = &PauseReason{Reason: pausePlanned, Time: &ts}
+		} else if lp.pause == nil && lp.enabled && lp.connected() {
+			lp.pause = &PauseReason{Reason: pauseVehicle}
+		}
+	}
+
+	lp.publish("pauseReason", lp.pause)
+
+	// record changed reasons ignoring varying power and time
+	var reason string
+	if lp.pause != nil {
+		reason = lp.pause.Reason + lp.pause.Detail
+	}
+
+	if reason != lp.pauseRecorded {
+		if reason != "" {
+			lp.record(eventPause, lp.pause.String())
+		}
+
+		// notify once when entering fault state, not on changing details
+		if lp.pause != nil && lp.pause.Reason == pauseChargerFault && !strings.HasPrefix(lp.pauseRecorded, pauseChargerFault) {
+			lp.pushEvent(evChargerFault)
+		}
+
+		lp.pauseRecorded = reason
+	}
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseReason(t *testing.T) {
+	clck := clock.NewMock()
+
+	Voltage = 100
+	lp := &LoadPoint{
+		log:            util.NewLogger("foo"),
+		clock:          clck,
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         1,
+		measuredPhases: 1,
+		status:         api.StatusB,
+	}
+	lp.Enable.Delay = time.Minute
+
+	// 100W missing for minimum current
+	lp.pvMaxCurrent(api.ModePV, -float64(minA)*Voltage+100, false)
+	require.NotNil(t, lp.pause)
+	assert.Equal(t, pauseSurplus, lp.pause.Reason)
+	assert.Equal(t, 100.0, lp.pause.Power)
+
+	// enable timer running
+	lp.pause = nil
+	lp.pvMaxCurrent(api.ModePV, -float64(minA)*Voltage, false)
+	require.NotNil(t, lp.pause)
+	assert.Equal(t, pauseEnableDelay, lp.pause.Reason)
+	assert.Equal(t, clck.Now().Add(time.Minute), *lp.pause.Time)
+
+	// first reason wins
+	lp.setPause(PauseReason{Reason: pauseModeOff})
+	assert.Equal(t, pauseEnableDelay, lp.pause.Reason)
+
+	// enabled without reason
+	lp.pause = nil
+	lp.enabled = true
+	lp.publishPause()
+	require.NotNil(t, lp.pause)
+	assert.Equal(t, pauseVehicle, lp.pause.Reason)
+
+	// charging clears reason
+	lp.status = api.StatusC
+	lp.publishPause()
+	assert.Nil(t, lp.pause)
+}
+
+func TestPauseChargerFaultEvent(t *testing.T) {
+	pushChan := make(chan push.Event, 1)
+
+	lp := &LoadPoint{
+		log:      util.NewLogger("foo"),
+		clock:    clock.NewMock(),
+		status:   api.StatusE,
+		pushChan: pushChan,
+	}
+
+	lp.publishPause()
+	assert.Equal(t, push.Event{Event: evChargerFault}, <-pushChan)
+
+	// changing details don't repeat the notification
+	lp.status = api.StatusF
+	lp.publishPause()
+	assert.Len(t, pushChan, 0)
+
+	// recovery re-arms the notification
+	lp.pause = nil
+	lp.status = api.StatusB
+	lp.publishPause()
+	lp.status = api.StatusE
+	lp.publishPause()
+	assert.Len(t, pushChan, 1)
+}
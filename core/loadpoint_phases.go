@@ -2,6 +2,7 @@ package core
 
 import (
 	"math"
+	"time"
 
 	"github.com/evcc-io/evcc/api"
 )
@@ -22,6 +23,30 @@ func (lp *LoadPoint) getMeasuredPhases() int {
 	return lp.measuredPhases
 }
 
+// surplusLookahead is the time ahead for checking predicted surplus before scaling up phases
+const surplusLookahead = 30 * time.Minute
+
+// surplusPredictor predicts the pv surplus from learned pv and home power profiles
+type surplusPredictor interface {
+	predictSurplus(time.Time) (float64, bool)
+}
+
+// surplusSustained checks if the predicted surplus will still allow charging at the given phases and current.
+// Without prediction or unlearned profiles the surplus is assumed to be sustained.
+func (lp *LoadPoint) surplusSustained(phases int, minCurrent float64) bool {
+	if lp.predictor == nil {
+		return true
+	}
+
+	surplus, ok := lp.predictor.predictSurplus(lp.clock.Now().Add(surplusLookahead))
+	if threshold := float64(phases) * Voltage * minCurrent; ok && surplus < threshold {
+		lp.log.DEBUG.Printf("predicted surplus %.0fW < %.0fW min %dp threshold in %v", surplus, threshold, phases, surplusLookahead)
+		return false
+	}
+
+	return true
+}
+
 // assume 3p for switchable charger during startup
 const unknownPhases = 3
 
@@ -427,3 +427,64 @@ func TestScalePhasesIfAvailable(t *testing.T) {
 		ctrl.Finish()
 	}
 }
+
+type predictor float64
+
+func (p predictor) predictSurplus(time.Time) (float64, bool) {
+	return float64(p), true
+}
+
+func TestPvScalePhasesPrediction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := &struct {
+		*mock.MockCharger
+		*mock.MockPhaseSwitcher
+	}{
+		mock.NewMockCharger(ctrl),
+		mock.NewMockPhaseSwitcher(ctrl),
+	}
+
+	Voltage = 230 // V
+
+	tc := []struct {
+		desc      string
+		predicted float64
+		res       bool
+	}{
+		{"surplus drops below 3p minimum", 1 * Voltage * minA, false},
+		{"surplus sustained", 3 * Voltage * minA, true},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc.desc)
+
+		clck := clock.NewMock()
+		clck.Add(time.Hour) // avoid time.IsZero
+
+		lp := &LoadPoint{
+			log:        util.NewLogger("foo"),
+			clock:      clck,
+			charger:    charger,
+			MinCurrent: minA,
+			MaxCurrent: maxA,
+			phases:     1,
+			predictor:  predictor(tc.predicted),
+			Enable: ThresholdConfig{
+				Delay: time.Minute,
+			},
+		}
+
+		// timer elapsed
+		lp.phaseTimer = clck.Now().Add(-time.Minute)
+
+		if tc.res {
+			charger.MockPhaseSwitcher.EXPECT().Phases1p3p(3).Return(nil)
+		}
+
+		if res := lp.pvScalePhases(3*Voltage*minA, minA, maxA); res != tc.res {
+			t.Errorf("expected %v, got %v", tc.res, res)
+		}
+
+		ctrl.Finish()
+	}
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PhysicalConfig defines the installation's current ratings, never exceeded regardless of dynamic limits
+type PhysicalConfig struct {
+	Cable   float64 `mapstructure:"cable"`   // cable rating in A
+	Socket  string  `mapstructure:"socket"`  // socket type, see socketRatings
+	Breaker float64 `mapstructure:"breaker"` // upstream breaker rating in A
+}
+
+// socketRating is the maximum continuous current and number of phases of a socket type
+type socketRating struct {
+	current float64
+	phases  int
+}
+
+// socketRatings is the registry of known socket types
+var socketRatings = map[string]socketRating{
+	"schuko": {10, 1}, // continuous load, nominal 16A
+	"cee16":  {16, 3},
+	"cee32":  {32, 3},
+	"type2":  {32, 3},
+	"tesla":  {32, 3}, // wall connector
+}
+
+// limit returns the maximum current permitted by the physical installation or zero if unrestricted
+func (c PhysicalConfig) limit() float64 {
+	var res float64
+
+	for _, current := range []float64{c.Cable, c.Breaker, socketRatings[c.Socket].current} {
+		if current > 0 && (res == 0 || current < res) {
+			res = current
+		}
+	}
+
+	return res
+}
+
+// validate normalizes the configuration and checks the configured currents and phases against the physical ratings
+func (c *PhysicalConfig) validate(minCurrent, maxCurrent float64, phases int) error {
+	if c.Socket = strings.ToLower(c.Socket); c.Socket != "" {
+		if _, ok := socketRatings[c.Socket]; !ok {
+			return fmt.Errorf("unknown socket type: %s", c.Socket)
+		}
+
+		if err := c.validatePhases(phases); err != nil {
+			return err
+		}
+	}
+
+	if limit := c.limit(); limit > 0 {
+		if maxCurrent > limit {
+			return fmt.Errorf("maxCurrent %.3gA exceeds physical limit of %.3gA", maxCurrent, limit)
+		}
+
+		if minCurrent > limit {
+			return fmt.Errorf("minCurrent %.3gA exceeds physical limit of %.3gA", minCurrent, limit)
+		}
+	}
+
+	return nil
+}
+
+// validatePhases checks the phases against the socket rating. Automatic switching (0p) may use 3p.
+func (c PhysicalConfig) validatePhases(phases int) error {
+	rating, ok := socketRatings[c.Socket]
+	if !ok {
+		return nil
+	}
+
+	if phases == 0 && rating.phases < 3 {
+		return fmt.Errorf("automatic phase switching exceeds %s socket rating of %dp", c.Socket, rating.phases)
+	}
+
+	if phases > rating.phases {
+		return fmt.Errorf("%dp exceeds %s socket rating of %dp", phases, c.Socket, rating.phases)
+	}
+
+	return nil
+}
+
+// physicalCurrent caps the current at the physical limit
+func (lp *LoadPoint) physicalCurrent(current float64) float64 {
+	if limit := lp.Physical.limit(); limit > 0 && current > limit {
+		lp.log.WARN.Printf("limiting %.3gA to physical limit of %.3gA", current, limit)
+		return limit
+	}
+
+	return current
+}
+
+// physicalChargeCurrent caps the charge current at the physical limit, logging only when the limited current changes
+func (lp *LoadPoint) physicalChargeCurrent(current float64) float64 {
+	limit := lp.Physical.limit()
+	if limit == 0 || current <= limit {
+		lp.physicalLimited = 0
+		return current
+	}
+
+	if current != lp.physicalLimited {
+		lp.physicalLimited = current
+		lp.log.WARN.Printf("limiting charge current %.3gA to physical limit of %.3gA", current, limit)
+	}
+
+	return limit
+}
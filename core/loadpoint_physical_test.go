@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhysicalLimit(t *testing.T) {
+	tc := []struct {
+		cc    PhysicalConfig
+		limit float64
+	}{
+		{PhysicalConfig{}, 0},
+		{PhysicalConfig{Cable: 20}, 20},
+		{PhysicalConfig{Cable: 20, Breaker: 16}, 16},
+		{PhysicalConfig{Cable: 32, Socket: "schuko"}, 10},
+	}
+
+	for _, tc := range tc {
+		assert.Equal(t, tc.limit, tc.cc.limit(), tc.cc)
+	}
+}
+
+func TestPhysicalValidate(t *testing.T) {
+	cc := PhysicalConfig{Cable: 16}
+	assert.NoError(t, cc.validate(6, 16, 3))
+	assert.Error(t, cc.validate(6, 32, 3))
+
+	cc = PhysicalConfig{Socket: "Schuko"}
+	assert.NoError(t, cc.validate(6, 10, 1))
+	assert.Equal(t, "schuko", cc.Socket)
+	assert.Error(t, cc.validate(6, 10, 3), "phases")
+	assert.Error(t, cc.validate(6, 10, 0), "automatic phases")
+
+	cc = PhysicalConfig{Socket: "cee16"}
+	assert.NoError(t, cc.validate(6, 16, 0))
+
+	cc = PhysicalConfig{Socket: "foo"}
+	assert.Error(t, cc.validate(6, 16, 3))
+}
+
+func TestPhysicalMaxCurrent(t *testing.T) {
+	lp := &LoadPoint{
+		log:        util.NewLogger("foo"),
+		MinCurrent: minA,
+		MaxCurrent: maxA,
+		Physical:   PhysicalConfig{Breaker: 13},
+	}
+
+	lp.SetMaxCurrent(32)
+	assert.Equal(t, 13.0, lp.GetMaxCurrent())
+
+	lp.SetMaxCurrent(10)
+	assert.Equal(t, 10.0, lp.GetMaxCurrent())
+}
+
+func TestPhysicalChargeCurrent(t *testing.T) {
+	lp := &LoadPoint{
+		log:      util.NewLogger("foo"),
+		Physical: PhysicalConfig{Breaker: 13},
+	}
+
+	assert.Equal(t, 10.0, lp.physicalChargeCurrent(10))
+	assert.Equal(t, 0.0, lp.physicalLimited)
+
+	assert.Equal(t, 13.0, lp.physicalChargeCurrent(16))
+	assert.Equal(t, 16.0, lp.physicalLimited)
+
+	assert.Equal(t, 13.0, lp.physicalChargeCurrent(13))
+	assert.Equal(t, 0.0, lp.physicalLimited)
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// lockedRate is a grid rate locked for the committed target charge plan
+type lockedRate struct {
+	api.Rate
+	Cheap bool `json:"cheap"` // cheap at the time of locking
+}
+
+// planLocker provides the grid rates for locking committed target charge plans
+type planLocker interface {
+	lockRates(until time.Time) ([]lockedRate, error)
+}
+
+// planLock holds the grid rates of the committed target charge plan
+type planLock struct {
+	target   time.Time    // target time the rates have been locked for
+	rates    []lockedRate // locked rates, nil if not locked
+	revision int          // number of plan revisions
+	reason   string       // reason of the last revision
+}
+
+// updatePlanLock locks the grid rates once a target charge plan is committed and re-plans
+// only if the target has changed. Tariff updates don't shift a committed plan.
+func (lp *LoadPoint) updatePlanLock() {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.planner == nil || lp.socTimer == nil {
+		return
+	}
+
+	target := lp.socTimer.Time
+	if !target.IsZero() && !target.After(lp.now()) {
+		target = time.Time{}
+	}
+
+	if target.Equal(lp.planLock.target) {
+		return
+	}
+
+	var reason string
+	switch {
+	case target.IsZero() && lp.socTimer.Time.IsZero():
+		reason = "removed"
+	case target.IsZero():
+		reason = "expired"
+	case lp.planLock.target.IsZero():
+		reason = "committed"
+	default:
+		reason = "target changed"
+	}
+
+	lp.planLock.target = target
+	lp.planLock.rates = nil
+
+	if !target.IsZero() {
+		rates, err := lp.planner.lockRates(target)
+		if err != nil {
+			lp.log.ERROR.Printf("plan lock: %v", err)
+			reason += ": rates unavailable"
+		}
+		lp.planLock.rates = rates
+	}
+
+	lp.planLock.revision++
+	lp.planLock.reason = reason
+
+	lp.log.DEBUG.Printf("plan lock: revision %d (%s)", lp.planLock.revision, reason)
+	lp.record(eventPlan, fmt.Sprintf("revision %d: %s", lp.planLock.revision, reason))
+
+	lp.publish("planRevision", lp.planLock.revision)
+	lp.publish("planReason", reason)
+	lp.publish("planRates", lp.planLock.rates)
+}
+
+// lockedCheap returns the cheap state of the locked rate at the given time, or the live state if not locked
+func (lp *LoadPoint) lockedCheap(now time.Time, cheap bool) bool {
+	lp.Lock()
+	defer lp.Unlock()
+
+	for _, r := range lp.planLock.rates {
+		if !now.Before(r.Start) && now.Before(r.End) {
+			if r.Cheap != cheap {
+				lp.log.DEBUG.Printf("plan lock: keeping locked rate %.3f (cheap: %t)", r.Price, r.Cheap)
+			}
+			return r.Cheap
+		}
+	}
+
+	return cheap
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type lockTariff struct {
+	rates []api.Rate
+}
+
+func (t *lockTariff) IsCheap() (bool, error)            { return false, nil }
+func (t *lockTariff) CurrentPrice() (float64, error)    { return 0, nil }
+func (t *lockTariff) Rates() ([]api.Rate, error)        { return t.rates, nil }
+func (t *lockTariff) CheapLimit() float64               { return 0.2 }
+func (t *lockTariff) set(start time.Time, p ...float64) { t.rates = hourlyRates(start, p...) }
+
+func hourlyRates(start time.Time, prices ...float64) []api.Rate {
+	res := make([]api.Rate, 0, len(prices))
+	for i, p := range prices {
+		ts := start.Add(time.Duration(i) * time.Hour)
+		res = append(res, api.Rate{Start: ts, End: ts.Add(time.Hour), Price: p})
+	}
+	return res
+}
+
+func TestPlanLock(t *testing.T) {
+	clck := clock.NewMock()
+	now := time.Date(2022, 10, 7, 12, 0, 0, 0, time.UTC)
+	clck.Set(now)
+
+	tariff := new(lockTariff)
+	tariff.set(now, 0.3, 0.1, 0.1, 0.3)
+
+	site := &Site{log: util.NewLogger("foo"), clock: clck}
+	site.tariffs.Grid = tariff
+
+	lp := &LoadPoint{log: util.NewLogger("foo"), clock: clck, planner: site}
+	lp.socTimer = soc.NewTimer(lp.log, &adapter{LoadPoint: lp})
+	lp.socTimer.SetClock(clck)
+
+	// not locked
+	lp.updatePlanLock()
+	assert.Equal(t, 0, lp.planLock.revision)
+	assert.True(t, lp.lockedCheap(now, true))
+
+	// commit plan
+	lp.socTimer.Set(now.Add(3 * time.Hour))
+	lp.updatePlanLock()
+	assert.Equal(t, 1, lp.planLock.revision)
+	assert.Equal(t, "committed", lp.planLock.reason)
+	assert.Len(t, lp.planLock.rates, 3)
+
+	// tariff update doesn't shift the plan
+	tariff.set(now, 0.1, 0.3, 0.3, 0.1)
+	lp.updatePlanLock()
+	assert.Equal(t, 1, lp.planLock.revision)
+	assert.False(t, lp.lockedCheap(now, true))
+	assert.True(t, lp.lockedCheap(now.Add(time.Hour), false))
+
+	// outside of locked rates
+	assert.True(t, lp.lockedCheap(now.Add(3*time.Hour), true))
+
+	// re-plan on target change
+	lp.socTimer.Set(now.Add(2 * time.Hour))
+	lp.updatePlanLock()
+	assert.Equal(t, 2, lp.planLock.revision)
+	assert.Equal(t, "target changed", lp.planLock.reason)
+	assert.True(t, lp.lockedCheap(now, false))
+
+	// expired
+	clck.Add(2 * time.Hour)
+	lp.updatePlanLock()
+	assert.Equal(t, 3, lp.planLock.revision)
+	assert.Equal(t, "expired", lp.planLock.reason)
+	assert.Nil(t, lp.planLock.rates)
+
+	// removed
+	lp.socTimer.Set(clck.Now().Add(time.Hour))
+	lp.updatePlanLock()
+	lp.socTimer.Reset()
+	lp.updatePlanLock()
+	assert.Equal(t, 5, lp.planLock.revision)
+	assert.Equal(t, "removed", lp.planLock.reason)
+}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+// vehicleProfile contains the loadpoint settings last chosen for a vehicle
+type vehicleProfile struct {
+	MinSoC     *int       `json:"minSoC,omitempty"`
+	TargetSoC  *int       `json:"targetSoC,omitempty"`
+	TargetTime *time.Time `json:"targetTime,omitempty"`
+	MinCurrent *float64   `json:"minCurrent,omitempty"`
+	MaxCurrent *float64   `json:"maxCurrent,omitempty"`
+	Phases     *int       `json:"phases,omitempty"`
+}
+
+// vehicleKey is the settings key prefix of the vehicle, using its configured name if known
+func (lp *LoadPoint) vehicleKey(vehicle api.Vehicle) string {
+	var name string
+	if lp.vehicleName != nil {
+		name = lp.vehicleName(vehicle)
+	}
+	if name == "" {
+		name = vehicle.Title()
+	}
+	return "vehicle." + name
+}
+
+// vehicleProfileKey is the settings key of the vehicle's profile
+func (lp *LoadPoint) vehicleProfileKey(vehicle api.Vehicle) string {
+	return lp.vehicleKey(vehicle) + ".profile"
+}
+
+// loadVehicleProfile returns the vehicle's stored profile
+func (lp *LoadPoint) loadVehicleProfile(vehicle api.Vehicle) vehicleProfile {
+	var res vehicleProfile
+	_ = settings.Json(lp.vehicleProfileKey(vehicle), &res)
+	return res
+}
+
+// updateVehicleProfile records a setting change in the active vehicle's profile
+func (lp *LoadPoint) updateVehicleProfile(fun func(p *vehicleProfile)) {
+	lp.Lock()
+	vehicle := lp.vehicle
+	lp.Unlock()
+
+	if !lp.VehicleProfiles || vehicle == nil {
+		return
+	}
+
+	key := lp.vehicleProfileKey(vehicle)
+	profile := lp.loadVehicleProfile(vehicle)
+	fun(&profile)
+
+	if err := settings.SetJson(key, profile); err != nil {
+		lp.log.ERROR.Printf("vehicle profile: %v", err)
+	}
+}
+
+// apiLoadPoint is the loadpoint as exposed to api and ui. Only setting changes made
+// by the user are recorded in the vehicle profile, not those of actions or automations.
+type apiLoadPoint struct {
+	*LoadPoint
+}
+
+// SetTargetSoC implements loadpoint.API
+func (lp apiLoadPoint) SetTargetSoC(soc int) {
+	lp.LoadPoint.SetTargetSoC(soc)
+	lp.updateVehicleProfile(func(p *vehicleProfile) { p.TargetSoC = &soc })
+}
+
+// SetMinSoC implements loadpoint.API
+func (lp apiLoadPoint) SetMinSoC(soc int) {
+	lp.LoadPoint.SetMinSoC(soc)
+	lp.updateVehicleProfile(func(p *vehicleProfile) { p.MinSoC = &soc })
+}
+
+// SetPhases implements loadpoint.API
+func (lp apiLoadPoint) SetPhases(phases int) error {
+	err := lp.LoadPoint.SetPhases(phases)
+	if err == nil {
+		lp.updateVehicleProfile(func(p *vehicleProfile) { p.Phases = &phases })
+	}
+	return err
+}
+
+// SetTargetCharge implements loadpoint.API
+func (lp apiLoadPoint) SetTargetCharge(finishAt time.Time, soc int) {
+	lp.LoadPoint.SetTargetCharge(finishAt, soc)
+	lp.updateVehicleProfile(func(p *vehicleProfile) {
+		p.TargetTime = nil
+		if !finishAt.IsZero() {
+			p.TargetTime, p.TargetSoC = &finishAt, &soc
+		}
+	})
+}
+
+// SetMinCurrent implements loadpoint.API
+func (lp apiLoadPoint) SetMinCurrent(current float64) {
+	lp.LoadPoint.SetMinCurrent(current)
+	current = lp.GetMinCurrent()
+	lp.updateVehicleProfile(func(p *vehicleProfile) { p.MinCurrent = &current })
+}
+
+// SetMaxCurrent implements loadpoint.API
+func (lp apiLoadPoint) SetMaxCurrent(current float64) {
+	lp.LoadPoint.SetMaxCurrent(current)
+	current = lp.GetMaxCurrent()
+	lp.updateVehicleProfile(func(p *vehicleProfile) { p.MaxCurrent = &current })
+}
+
+// applyVehicleProfile applies the identified vehicle's stored profile
+func (lp *LoadPoint) applyVehicleProfile(vehicle api.Vehicle) {
+	if !lp.VehicleProfiles {
+		return
+	}
+
+	profile := lp.loadVehicleProfile(vehicle)
+
+	if profile.MinCurrent != nil {
+		lp.SetMinCurrent(*profile.MinCurrent)
+	}
+	if profile.MaxCurrent != nil {
+		lp.SetMaxCurrent(*profile.MaxCurrent)
+	}
+	if profile.Phases != nil {
+		if err := lp.SetPhases(*profile.Phases); err != nil {
+			lp.log.WARN.Printf("vehicle profile: %v", err)
+		}
+	}
+	if profile.MinSoC != nil {
+		lp.SetMinSoC(*profile.MinSoC)
+	}
+	if profile.TargetSoC != nil {
+		lp.SetTargetSoC(*profile.TargetSoC)
+	}
+
+	// restore plan unless already expired
+	if ts := profile.TargetTime; ts != nil && ts.After(lp.clock.Now()) {
+		lp.SetTargetCharge(*ts, lp.GetTargetSoC())
+	}
+
+	lp.log.DEBUG.Printf("vehicle profile applied: %s", vehicle.Title())
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVehicleProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	vehicle := mock.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Title().Return("Profile Test").AnyTimes()
+
+	lp := &LoadPoint{
+		log:             util.NewLogger("foo"),
+		clock:           clck,
+		vehicle:         vehicle,
+		VehicleProfiles: true,
+		vehicleName:     func(api.Vehicle) string { return "profile-test" },
+		MinCurrent:      minA,
+		MaxCurrent:      maxA,
+		SoC:             SoCConfig{target: 100},
+	}
+	lp.socTimer = soc.NewTimer(lp.log, &adapter{LoadPoint: lp})
+	lp.socTimer.SetClock(clck)
+
+	// user settings are recorded
+	finishAt := clck.Now().Add(8 * time.Hour)
+	user := apiLoadPoint{lp}
+	user.SetMinCurrent(8)
+	user.SetMinSoC(20)
+	user.SetTargetCharge(finishAt, 80)
+
+	// programmatic changes are not recorded
+	lp.SetMinSoC(50)
+
+	// actions are not recorded
+	maxCurrent := 10.0
+	lp.onDisconnect = api.ActionConfig{MinCurrent: &lp.MinCurrent, MaxCurrent: &lp.MaxCurrent}
+	lp.applyAction(api.ActionConfig{MaxCurrent: &maxCurrent})
+
+	profile := lp.loadVehicleProfile(vehicle)
+	assert.Equal(t, "vehicle.profile-test.profile", lp.vehicleProfileKey(vehicle))
+	assert.Equal(t, 8.0, *profile.MinCurrent)
+	assert.Nil(t, profile.MaxCurrent)
+	assert.Equal(t, 20, *profile.MinSoC)
+	assert.Equal(t, 80, *profile.TargetSoC)
+	assert.True(t, finishAt.Equal(*profile.TargetTime))
+
+	// profile is applied when the vehicle returns
+	lp.MinCurrent = minA
+	lp.setMinSoC(0)
+	lp.setTargetSoC(100)
+	lp.socTimer.Set(time.Time{})
+
+	lp.applyVehicleProfile(vehicle)
+	assert.Equal(t, 8.0, lp.MinCurrent)
+	assert.Equal(t, 20, lp.SoC.min)
+	assert.Equal(t, 80, lp.SoC.target)
+	assert.True(t, finishAt.Equal(lp.socTimer.Time))
+
+	// expired plan is not restored
+	lp.socTimer.Set(time.Time{})
+	clck.Add(9 * time.Hour)
+
+	lp.applyVehicleProfile(vehicle)
+	assert.True(t, lp.socTimer.Time.IsZero())
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTargetRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	vehicle := mock.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Title().Return("range").AnyTimes()
+
+	// 20kWh/100km
+	c := soc.VehicleConsumption("range")
+	c.AddOdometer(1000)
+	c.AddEnergy(20)
+	c.AddOdometer(1100)
+
+	lp := &LoadPoint{
+		log:     util.NewLogger("foo"),
+		vehicle: vehicle,
+	}
+
+	// absolute range as target soc
+	vehicle.EXPECT().Capacity().Return(50.0)
+	assert.NoError(t, lp.SetTargetRange(200))
+	assert.Equal(t, 80, lp.GetTargetSoC())
+	assert.Equal(t, 200, lp.GetTargetRange())
+
+	// vehicle without capacity
+	vehicle.EXPECT().Capacity().Return(0.0)
+	assert.Error(t, lp.SetTargetRange(100))
+	assert.Equal(t, 200, lp.GetTargetRange())
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+// repeatingPlansKey is the settings key of the vehicle's recurring target charges,
+// or of the loadpoint's if no vehicle is identified
+func (lp *LoadPoint) repeatingPlansKey(vehicle api.Vehicle) string {
+	if vehicle != nil {
+		return lp.vehicleKey(vehicle) + ".plans"
+	}
+	return fmt.Sprintf("loadpoint.%s.plans", lp.Title)
+}
+
+// newRepeatingPlans parses the recurring target charges
+func newRepeatingPlans(plans []loadpoint.RepeatingPlan) ([]*plan, error) {
+	res := make([]*plan, 0, len(plans))
+
+	for i, rp := range plans {
+		p, err := newPlanFromConfig(PlanConfig{
+			Name: fmt.Sprintf("repeating %d", i+1),
+			SoC:  rp.SoC,
+			Time: rp.Time,
+			Days: rp.Days,
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, p)
+	}
+
+	return res, nil
+}
+
+// loadRepeatingPlans restores the persisted recurring target charges of the active vehicle.
+// Must be called with lock held.
+func (lp *LoadPoint) loadRepeatingPlans() {
+	plans := []loadpoint.RepeatingPlan{}
+	if err := settings.Json(lp.repeatingKey, &plans); err != nil {
+		plans = []loadpoint.RepeatingPlan{}
+	}
+
+	repeating, err := newRepeatingPlans(plans)
+	if err != nil {
+		lp.log.ERROR.Printf("repeating plans: %v", err)
+		plans, repeating = []loadpoint.RepeatingPlan{}, nil
+	}
+
+	lp.repeating = repeating
+	lp.repeatingNext = time.Time{}
+
+	lp.publish("repeatingPlans", plans)
+}
+
+// GetRepeatingPlans returns the active vehicle's weekly recurring target charges
+func (lp *LoadPoint) GetRepeatingPlans() []loadpoint.RepeatingPlan {
+	lp.Lock()
+	defer lp.Unlock()
+
+	res := make([]loadpoint.RepeatingPlan, 0, len(lp.repeating))
+	for _, p := range lp.repeating {
+		res = append(res, loadpoint.RepeatingPlan{Days: p.Days, Time: p.Time, SoC: p.SoC})
+	}
+
+	return res
+}
+
+// SetRepeatingPlans sets the active vehicle's weekly recurring target charges
+func (lp *LoadPoint) SetRepeatingPlans(plans []loadpoint.RepeatingPlan) error {
+	repeating, err := newRepeatingPlans(plans)
+	if err != nil {
+		return err
+	}
+
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.log.DEBUG.Printf("set repeating plans: %v", plans)
+
+	if err := settings.SetJson(lp.repeatingKey, plans); err != nil {
+		return err
+	}
+
+	lp.repeating = repeating
+	lp.repeatingNext = time.Time{}
+	lp.publish("repeatingPlans", plans)
+	lp.record(eventPlan, fmt.Sprintf("%d repeating", len(plans)))
+
+	lp.requestUpdate()
+
+	return nil
+}
+
+// nextRepeatingPlan returns the recurring target charge with the earliest departure after given time
+func (lp *LoadPoint) nextRepeatingPlan(after time.Time) (*plan, time.Time) {
+	var (
+		res *plan
+		ts  time.Time
+	)
+
+	for _, p := range lp.repeating {
+		if next := p.next(after); res == nil || next.Before(ts) {
+			res, ts = p, next
+		}
+	}
+
+	return res, ts
+}
+
+// applyRepeatingPlans sets the next recurring target charge once the current target charge has been reached, removed or expired.
+// Each departure is applied only once, it is not restored after removing it.
+func (lp *LoadPoint) applyRepeatingPlans() {
+	lp.Lock()
+	defer lp.Unlock()
+
+	now := lp.now()
+	if len(lp.repeating) == 0 || lp.socTimer == nil || lp.socTimer.Time.After(now) {
+		return
+	}
+
+	after := now
+	if lp.repeatingNext.After(after) {
+		after = lp.repeatingNext
+	}
+
+	p, ts := lp.nextRepeatingPlan(after)
+	lp.repeatingNext = ts
+
+	lp.log.INFO.Printf("repeating plan: target charge %d%% at %v", p.SoC, ts.Round(time.Minute))
+	lp.socTimer.Set(ts)
+	lp.setTargetSoC(p.SoC)
+	lp.record(eventPlan, fmt.Sprintf("%d%% at %s (repeating)", p.SoC, ts.Format(time.RFC3339)))
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepeatingPlans(t *testing.T) {
+	clck := clock.NewMock()
+
+	// friday 2022-10-07
+	fri := func(h int) time.Time { return time.Date(2022, 10, 7, h, 0, 0, 0, time.UTC) }
+	clck.Set(fri(12))
+
+	lp := &LoadPoint{log: util.NewLogger("foo"), clock: clck, Title: "Garage"}
+	lp.repeatingKey = lp.repeatingPlansKey(nil)
+	lp.socTimer = soc.NewTimer(lp.log, &adapter{LoadPoint: lp})
+	lp.socTimer.SetClock(clck)
+
+	assert.Error(t, lp.SetRepeatingPlans([]loadpoint.RepeatingPlan{{SoC: 80}}))
+
+	plans := []loadpoint.RepeatingPlan{
+		{Days: []string{"weekdays"}, Time: "07:00", SoC: 80},
+		{Days: []string{"sat"}, Time: "09:00", SoC: 100},
+	}
+	require.NoError(t, lp.SetRepeatingPlans(plans))
+	assert.Equal(t, plans, lp.GetRepeatingPlans())
+
+	// saturday departure comes first
+	lp.applyRepeatingPlans()
+	assert.Equal(t, fri(24+9), lp.socTimer.Time)
+	assert.Equal(t, 100, lp.SoC.target)
+
+	// one-shot target charge is kept
+	lp.socTimer.Set(fri(20))
+	lp.applyRepeatingPlans()
+	assert.Equal(t, fri(20), lp.socTimer.Time)
+
+	// removed target charge applies the following departure
+	lp.socTimer.Reset()
+	lp.applyRepeatingPlans()
+	assert.Equal(t, fri(3*24+7), lp.socTimer.Time)
+	assert.Equal(t, 80, lp.SoC.target)
+
+	// expired departure applies the next one
+	clck.Set(fri(3*24 + 8))
+	lp.applyRepeatingPlans()
+	assert.Equal(t, fri(4*24+7), lp.socTimer.Time)
+}
+
+func TestRepeatingPlansPerVehicle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	vehicle := mock.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Title().Return("Repeating Test").AnyTimes()
+
+	lp := &LoadPoint{log: util.NewLogger("foo"), clock: clock.NewMock(), Title: "Carport"}
+	lp.repeatingKey = lp.repeatingPlansKey(nil)
+
+	guest := []loadpoint.RepeatingPlan{{Time: "07:00", SoC: 80}}
+	require.NoError(t, lp.SetRepeatingPlans(guest))
+
+	// vehicle has its own plans
+	lp.repeatingKey = lp.repeatingPlansKey(vehicle)
+	lp.loadRepeatingPlans()
+	assert.Empty(t, lp.GetRepeatingPlans())
+
+	plans := []loadpoint.RepeatingPlan{{Days: []string{"sat"}, Time: "09:00", SoC: 100}}
+	require.NoError(t, lp.SetRepeatingPlans(plans))
+	assert.Equal(t, "vehicle.Repeating Test.plans", lp.repeatingKey)
+
+	// plans are restored when the vehicle leaves and returns
+	lp.repeatingKey = lp.repeatingPlansKey(nil)
+	lp.loadRepeatingPlans()
+	assert.Equal(t, guest, lp.GetRepeatingPlans())
+
+	lp.repeatingKey = lp.repeatingPlansKey(vehicle)
+	lp.loadRepeatingPlans()
+	assert.Equal(t, plans, lp.GetRepeatingPlans())
+}
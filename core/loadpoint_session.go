@@ -1,6 +1,8 @@
 package core
 
 import (
+	"time"
+
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/db"
 )
@@ -27,18 +29,30 @@ func (lp *LoadPoint) startSession() {
 	}
 
 	if lp.session == nil {
-		lp.session = lp.db.Session(lp.chargeMeterTotal())
+		total, err := lp.billingMeterTotal()
+		if err != nil {
+			lp.log.ERROR.Printf("session: %v", err)
+		}
+
+		lp.session = lp.db.Session(total)
+		lp.sessionCost = sessionCost{}
+		lp.startMeterCheck()
 
 		if lp.vehicle != nil {
 			lp.session.Vehicle = lp.vehicle.Title()
 		}
 
-		if c, ok := lp.charger.(api.Identifier); ok {
+		if c, ok := lp.vehicleIdentification(); ok {
 			if id, err := c.Identify(); err == nil {
 				lp.session.Identifier = id
 			}
 		}
 
+		lp.Lock()
+		lp.session.Annotate(lp.annotation)
+		lp.annotationUpdated = false
+		lp.Unlock()
+
 		lp.db.Persist(lp.session)
 	}
 }
@@ -49,7 +63,31 @@ func (lp *LoadPoint) stopSession() {
 		return
 	}
 
-	lp.session.Stop(lp.getChargedEnergy(), lp.chargeMeterTotal())
+	chargedWh := lp.getChargedEnergy()
+
+	// charged energy is measured by the charge meter unless billing uses the charger
+	total, err := lp.billingMeterTotal()
+	switch {
+	case err != nil:
+		// skip the delta, keeping the energy and reading already recorded
+		lp.log.ERROR.Printf("session: %v", err)
+		chargedWh, total = 0, lp.session.MeterStop
+	case lp.meterCheck != nil && lp.meterCheck.Primary == meterPrimaryCharger:
+		if lp.session.MeterStart > 0 {
+			chargedWh = (total - lp.session.MeterStart) * 1e3
+		} else {
+			chargedWh = 0
+		}
+	}
+
+	lp.session.Stop(chargedWh, total)
+	lp.session.ApplyCost(lp.sessionCost.energy, lp.sessionCost.cost, lp.sessionCost.gridCost)
+	lp.session.ApplyGridEnergy(lp.sessionCost.energy, lp.sessionCost.gridEnergy)
+	lp.checkMeters()
+
+	if lp.pricing != nil {
+		lp.session.ApplyRate(lp.pricing.rate(lp.session.Identifier))
+	}
 
 	lp.db.Persist(lp.session)
 }
@@ -76,4 +114,79 @@ func (lp *LoadPoint) finalizeSession() {
 	}
 
 	lp.session = nil
+
+	lp.Lock()
+	lp.annotation = db.Annotation{}
+	lp.annotationUpdated = false
+	lp.Unlock()
+}
+
+// AnnotateSession attaches tags and note to the current session
+func (lp *LoadPoint) AnnotateSession(tags []string, note string) {
+	lp.Lock()
+	lp.annotation = db.Annotation{Tags: tags, Note: note}
+	lp.annotationUpdated = true
+	lp.Unlock()
+
+	lp.requestUpdate()
+}
+
+// updateAnnotation persists an updated annotation of the running session
+func (lp *LoadPoint) updateAnnotation() {
+	lp.Lock()
+	annotation, updated := lp.annotation, lp.annotationUpdated
+	lp.annotationUpdated = false
+	lp.Unlock()
+
+	if updated {
+		lp.updateSession(func(session *db.Session) {
+			session.Annotate(annotation)
+		})
+	}
+}
+
+// sessionCost is the energy cost accumulated while charging
+type sessionCost struct {
+	updated          time.Time
+	energy           float64 // kWh
+	cost             float64 // grid energy at grid price, self-produced energy at feed-in price
+	gridCost         float64 // all energy at grid price
+	gridEnergy       float64 // energy imported from the grid in kWh
+	strictGridEnergy float64 // energy imported from the grid in strict pv mode in kWh
+	strictAlarm      bool    // strict pv mode grid usage notified
+}
+
+// updateSessionCost accumulates the current session's energy cost and grid energy given the self-produced share of the charge power
+// and adds the charged grid energy to the budgets
+func (lp *LoadPoint) updateSessionCost(share, gridPrice, feedinPrice float64) {
+	now := lp.clock.Now()
+	updated := lp.sessionCost.updated
+	lp.sessionCost.updated = now
+
+	if updated.IsZero() {
+		return
+	}
+
+	// assume charge power as constant over the duration
+	energy := now.Sub(updated).Hours() * lp.GetChargePower() / 1e3
+	if energy <= 0 {
+		return
+	}
+
+	gridEnergy := energy * (1 - share)
+	lp.updateBudgets(gridEnergy, gridEnergy*gridPrice)
+
+	if lp.session == nil {
+		return
+	}
+
+	lp.sessionCost.energy += energy
+	lp.sessionCost.cost += energy * (share*feedinPrice + (1-share)*gridPrice)
+	lp.sessionCost.gridCost += energy * gridPrice
+	lp.sessionCost.gridEnergy += gridEnergy
+	lp.publish("sessionGridEnergy", lp.sessionCost.gridEnergy)
+
+	if gridEnergy > 0 && lp.strictActive(lp.GetMode()) {
+		lp.updateStrictGridEnergy(gridEnergy)
+	}
 }
@@ -0,0 +1,29 @@
+package core
+
+import "time"
+
+// socRefreshInterval is the minimum interval between refresh requests for a stale soc
+const socRefreshInterval = 15 * time.Minute
+
+// socStale returns true if the vehicle soc is older than the configured maximum age
+func (lp *LoadPoint) socStale() bool {
+	return lp.vehicle != nil && lp.SoC.MaxAge > 0 && !lp.socRead.IsZero() &&
+		lp.clock.Since(lp.socRead) > lp.SoC.MaxAge
+}
+
+// staleSoCPlan returns true if a charge plan is set and the vehicle soc is too old to cancel it.
+// Vehicle wake-up and soc refresh are requested instead.
+func (lp *LoadPoint) staleSoCPlan() bool {
+	if lp.socTimer == nil || lp.socTimer.Time.IsZero() || !lp.socStale() {
+		return false
+	}
+
+	if lp.clock.Since(lp.socRefresh) >= socRefreshInterval {
+		lp.log.DEBUG.Printf("vehicle soc older than %v: refreshing before plan decision", lp.SoC.MaxAge)
+		lp.socRefresh = lp.clock.Now()
+		lp.socUpdated = time.Time{} // poll immediately
+		lp.wakeUpVehicle()
+	}
+
+	return true
+}
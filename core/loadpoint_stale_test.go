@@ -0,0 +1,50 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaleSoCPlan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	lp := &LoadPoint{
+		log:     util.NewLogger("foo"),
+		clock:   clck,
+		vehicle: mock.NewMockVehicle(ctrl),
+		SoC:     SoCConfig{MaxAge: time.Hour, target: 80},
+	}
+	lp.socTimer = soc.NewTimer(lp.log, &adapter{LoadPoint: lp})
+	lp.socTimer.SetClock(clck)
+
+	lp.vehicleSoc = 100
+	lp.socRead = clck.Now()
+	lp.socUpdated = clck.Now()
+
+	// no plan
+	clck.Add(2 * time.Hour)
+	assert.False(t, lp.staleSoCPlan())
+
+	// fresh soc cancels plan
+	lp.socTimer.Set(clck.Now().Add(8 * time.Hour))
+	lp.socRead = clck.Now()
+	assert.False(t, lp.staleSoCPlan())
+
+	// stale soc keeps plan and requests refresh
+	clck.Add(2 * time.Hour)
+	assert.True(t, lp.staleSoCPlan())
+	assert.True(t, lp.socUpdated.IsZero())
+
+	// refresh not repeated immediately
+	lp.socUpdated = clck.Now()
+	assert.True(t, lp.staleSoCPlan())
+	assert.False(t, lp.socUpdated.IsZero())
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+)
+
+// StandbyConfig powers down idle chargers to save their standby consumption
+type StandbyConfig struct {
+	After  time.Duration    `mapstructure:"after"`  // idle duration without vehicle before powering down
+	Supply *provider.Config `mapstructure:"supply"` // supply contactor or smart relay, uses charger standby if not configured
+	Wake   []string         `mapstructure:"wake"`   // daily wake up times for vehicle detection like 17:00
+}
+
+// configureStandby creates the standby control from config
+func (lp *LoadPoint) configureStandby() error {
+	if lp.Standby.After == 0 {
+		return nil
+	}
+
+	for _, wake := range lp.Standby.Wake {
+		if _, err := time.Parse("15:04", wake); err != nil {
+			return fmt.Errorf("standby: invalid wake time: %s", wake)
+		}
+	}
+
+	if lp.Standby.Supply != nil {
+		supply, err := provider.NewBoolSetterFromConfig("supply", *lp.Standby.Supply)
+		if err != nil {
+			return fmt.Errorf("standby: %w", err)
+		}
+
+		lp.standbyS = func(standby bool) error {
+			return supply(!standby)
+		}
+
+		return nil
+	}
+
+	sc, ok := lp.charger.(api.StandbyController)
+	if !ok {
+		return errors.New("standby: charger does not support standby, configure supply")
+	}
+
+	lp.standbyS = sc.Standby
+
+	return nil
+}
+
+// WakeCharger powers up the charger from standby
+func (lp *LoadPoint) WakeCharger() {
+	lp.Lock()
+	lp.wakeRequested = true
+	lp.Unlock()
+
+	lp.requestUpdate()
+}
+
+// setStandby powers the charger down or up
+func (lp *LoadPoint) setStandby(standby bool) error {
+	if err := lp.standbyS(standby); err != nil {
+		return err
+	}
+
+	lp.standby = standby
+	lp.standbyUpdated = lp.clock.Now()
+	lp.idleSince = time.Time{}
+
+	lp.log.DEBUG.Printf("charger standby: %v", standby)
+	lp.publish("standby", standby)
+
+	return nil
+}
+
+// wakeDue checks if wake up was requested or a wake up time has passed since entering standby
+func (lp *LoadPoint) wakeDue() bool {
+	lp.Lock()
+	requested := lp.wakeRequested
+	lp.wakeRequested = false
+	lp.Unlock()
+
+	if requested {
+		return true
+	}
+
+	now := lp.now()
+
+	for _, wake := range lp.Standby.Wake {
+		t, _ := time.Parse("15:04", wake)
+
+		// check today's and yesterday's occurrence to handle day change
+		for _, day := range []time.Time{now, now.AddDate(0, 0, -1)} {
+			at := time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+			if at.After(lp.standbyUpdated) && !at.After(now) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// updateStandby powers down the idle charger and wakes it up when due. Returns true while the charger is in standby.
+func (lp *LoadPoint) updateStandby() bool {
+	if lp.standbyS == nil {
+		return false
+	}
+
+	if lp.standby {
+		if !lp.wakeDue() {
+			return true
+		}
+
+		if err := lp.setStandby(false); err != nil {
+			lp.log.ERROR.Printf("charger wake up: %v", err)
+			return true
+		}
+
+		lp.log.INFO.Println("charger woken up from standby")
+
+		// give charger time to boot
+		return true
+	}
+
+	// vehicle connected or charger still enabled
+	if lp.GetStatus() != api.StatusA || lp.enabled {
+		lp.idleSince = time.Time{}
+		return false
+	}
+
+	if lp.idleSince.IsZero() {
+		lp.idleSince = lp.clock.Now()
+	}
+
+	if lp.clock.Since(lp.idleSince) < lp.Standby.After {
+		return false
+	}
+
+	if err := lp.setStandby(true); err != nil {
+		lp.log.ERROR.Printf("charger standby: %v", err)
+		return false
+	}
+
+	lp.log.INFO.Printf("charger idle for %v, entering standby", lp.Standby.After)
+
+	return true
+}
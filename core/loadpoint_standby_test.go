@@ -0,0 +1,72 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+func TestStandby(t *testing.T) {
+	clck := clock.NewMock()
+	clck.Set(time.Date(2022, 1, 1, 12, 0, 0, 0, time.Local))
+
+	var supply []bool
+
+	lp := &LoadPoint{
+		log:    util.NewLogger("foo"),
+		clock:  clck,
+		status: api.StatusA,
+		Standby: StandbyConfig{
+			After: time.Hour,
+			Wake:  []string{"17:00"},
+		},
+		standbyS: func(standby bool) error {
+			supply = append(supply, !standby)
+			return nil
+		},
+	}
+
+	if lp.updateStandby() {
+		t.Fatal("unexpected standby")
+	}
+
+	clck.Add(time.Hour)
+	if !lp.updateStandby() || len(supply) != 1 || supply[0] {
+		t.Fatal("expected standby after idle duration")
+	}
+
+	clck.Add(time.Hour)
+	if !lp.updateStandby() || len(supply) != 1 {
+		t.Fatal("expected standby")
+	}
+
+	// wake up on schedule
+	clck.Set(time.Date(2022, 1, 1, 17, 0, 0, 0, time.Local))
+	if !lp.updateStandby() || len(supply) != 2 || !supply[1] {
+		t.Fatal("expected wake up at schedule")
+	}
+
+	// vehicle connected after wake up
+	lp.status = api.StatusB
+	clck.Add(2 * time.Hour)
+	if lp.updateStandby() {
+		t.Fatal("unexpected standby with vehicle connected")
+	}
+
+	// disconnected and idle again
+	lp.status = api.StatusA
+	lp.updateStandby()
+	clck.Add(time.Hour)
+	if !lp.updateStandby() || len(supply) != 3 {
+		t.Fatal("expected standby after idle duration")
+	}
+
+	// wake up via api
+	lp.WakeCharger()
+	if !lp.updateStandby() || lp.standby || len(supply) != 4 {
+		t.Fatal("expected wake up on request")
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"strings"
+	"time"
+)
+
+// deferDisconnect holds back the disconnect event while a reconnect of the identified vehicle
+// may still continue the session
+func (lp *LoadPoint) deferDisconnect() bool {
+	if lp.SessionGap <= 0 || lp.vehicleIdentifier == "" {
+		return false
+	}
+
+	lp.disconnected = lp.clock.Now()
+	lp.disconnectedID = lp.vehicleIdentifier
+	lp.log.DEBUG.Printf("car disconnected, waiting %v for reconnect", lp.SessionGap)
+
+	return true
+}
+
+// stitchSession continues the previous session if the same vehicle reconnects within the gap.
+// Otherwise the deferred disconnect is published before the new session starts.
+func (lp *LoadPoint) stitchSession() bool {
+	if lp.disconnected.IsZero() {
+		return false
+	}
+
+	if id := lp.reconnectedID(); !strings.EqualFold(id, lp.disconnectedID) {
+		lp.log.DEBUG.Printf("car reconnected with different identity, starting new session")
+		lp.publishDisconnect()
+		return false
+	}
+
+	gap := lp.clock.Since(lp.disconnected)
+	lp.disconnected = time.Time{}
+
+	lp.log.INFO.Printf("car reconnected after %v, continuing session", gap.Round(time.Second))
+	lp.record(eventStitch, gap.Round(time.Second).String())
+
+	return true
+}
+
+// expireDisconnect publishes the deferred disconnect once the gap has elapsed without reconnect
+func (lp *LoadPoint) expireDisconnect() {
+	if lp.disconnected.IsZero() || lp.clock.Since(lp.disconnected) < lp.SessionGap {
+		return
+	}
+
+	lp.publishDisconnect()
+}
+
+// publishDisconnect publishes the deferred disconnect
+func (lp *LoadPoint) publishDisconnect() {
+	lp.disconnected = time.Time{}
+	lp.disconnectedID = ""
+
+	lp.bus.Publish(evVehicleDisconnect)
+	lp.pushEvent(evVehicleDisconnect)
+}
+
+// reconnectedID returns the identifier of the reconnected vehicle, empty if unknown
+func (lp *LoadPoint) reconnectedID() string {
+	identifier, ok := lp.vehicleIdentification()
+	if !ok {
+		return ""
+	}
+
+	id, err := identifier.Identify()
+	if err != nil {
+		lp.log.ERROR.Println("charger vehicle id:", err)
+		return ""
+	}
+
+	return id
+}
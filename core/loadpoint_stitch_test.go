@@ -0,0 +1,86 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionStitch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	type identifyingCharger struct {
+		*mock.MockCharger
+		*mock.MockIdentifier
+	}
+
+	charger := &identifyingCharger{mock.NewMockCharger(ctrl), mock.NewMockIdentifier(ctrl)}
+
+	lp := &LoadPoint{
+		log:               util.NewLogger("foo"),
+		bus:               evbus.New(),
+		pushChan:          make(chan push.Event, 10),
+		clock:             clck,
+		charger:           charger,
+		status:            api.StatusC,
+		SessionGap:        time.Minute,
+		vehicleIdentifier: "car1",
+	}
+
+	var events []string
+	_ = lp.bus.Subscribe(evVehicleConnect, func() { events = append(events, evVehicleConnect) })
+	_ = lp.bus.Subscribe(evVehicleDisconnect, func() { events = append(events, evVehicleDisconnect) })
+
+	status := func(status api.ChargeStatus) {
+		charger.MockCharger.EXPECT().Status().Return(status, nil)
+		assert.NoError(t, lp.updateChargerStatus(context.Background()))
+	}
+
+	// brief disconnect continues the session
+	status(api.StatusA)
+	assert.Empty(t, events)
+
+	clck.Add(30 * time.Second)
+	charger.MockIdentifier.EXPECT().Identify().Return("car1", nil)
+	status(api.StatusB)
+	assert.Empty(t, events)
+	assert.True(t, lp.disconnected.IsZero())
+
+	// disconnect published once the gap has elapsed
+	status(api.StatusA)
+	clck.Add(30 * time.Second)
+	lp.expireDisconnect()
+	assert.Empty(t, events)
+
+	clck.Add(30 * time.Second)
+	lp.expireDisconnect()
+	assert.Equal(t, []string{evVehicleDisconnect}, events)
+
+	// connect after gap starts a new session
+	status(api.StatusB)
+	assert.Equal(t, []string{evVehicleDisconnect, evVehicleConnect}, events)
+
+	// different vehicle within the gap starts a new session
+	events = nil
+	status(api.StatusA)
+	charger.MockIdentifier.EXPECT().Identify().Return("car2", nil)
+	status(api.StatusB)
+	assert.Equal(t, []string{evVehicleDisconnect, evVehicleConnect}, events)
+	assert.True(t, lp.disconnected.IsZero())
+
+	// unidentified vehicle doesn't defer the disconnect
+	events = nil
+	lp.vehicleIdentifier = ""
+	status(api.StatusA)
+	assert.Equal(t, []string{evVehicleDisconnect}, events)
+}
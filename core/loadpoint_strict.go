@@ -0,0 +1,46 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+const evStrictGridUsage = "strictGridUsage" // grid energy charged in strict pv mode
+
+// StrictConfig defines solar-only charging in PV mode for policies that don't allow charging from the grid.
+// Surplus is used with a safety margin and charging stops immediately on grid import instead of after the disable delay.
+type StrictConfig struct {
+	Margin float64 `mapstructure:"margin"` // surplus in W held back from charging, 100W if not set
+	Alarm  float64 `mapstructure:"alarm"`  // grid energy in kWh charged during a session that triggers a notification, 0.1kWh if not set
+}
+
+// configureStrict applies the strict pv mode defaults
+func (lp *LoadPoint) configureStrict() {
+	if lp.Strict == nil {
+		return
+	}
+
+	if lp.Strict.Margin == 0 {
+		lp.Strict.Margin = 100
+	}
+	if lp.Strict.Alarm == 0 {
+		lp.Strict.Alarm = 0.1
+	}
+}
+
+// strictActive returns if solar-only charging applies to the charge mode
+func (lp *LoadPoint) strictActive(mode api.ChargeMode) bool {
+	return lp.Strict != nil && mode == api.ModePV
+}
+
+// updateStrictGridEnergy adds grid energy charged in strict pv mode to the session and notifies once the alarm threshold is reached
+func (lp *LoadPoint) updateStrictGridEnergy(energy float64) {
+	lp.sessionCost.strictGridEnergy += energy
+
+	if lp.sessionCost.strictAlarm || lp.sessionCost.strictGridEnergy < lp.Strict.Alarm {
+		return
+	}
+
+	lp.log.WARN.Printf("strict pv mode: %.2fkWh charged from grid", lp.sessionCost.strictGridEnergy)
+	lp.sessionCost.strictAlarm = true
+	lp.pushEvent(evStrictGridUsage)
+}
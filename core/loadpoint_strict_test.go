@@ -0,0 +1,84 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictCurrent(t *testing.T) {
+	Voltage = 100
+	lp := &LoadPoint{
+		log:            util.NewLogger("foo"),
+		clock:          clock.NewMock(),
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		Strict:         &StrictConfig{},
+		phases:         1,
+		measuredPhases: 1,
+		status:         api.StatusC,
+		enabled:        true,
+		chargeCurrent:  8,
+	}
+	lp.configureStrict()
+	lp.Disable.Delay = time.Hour
+
+	// margin is held back from the surplus
+	assert.Equal(t, 10.0, lp.pvMaxCurrent(api.ModePV, -300, false))
+	assert.Equal(t, 6.5, lp.pvMaxCurrent(api.ModePV, 50, false))
+
+	// grid import stops charging without disable delay
+	assert.Equal(t, 0.0, lp.pvMaxCurrent(api.ModePV, 250, false))
+	assert.Equal(t, pauseSurplus, lp.pause.Reason)
+
+	// other modes are not affected
+	assert.Equal(t, minA, lp.pvMaxCurrent(api.ModeMinPV, 250, false))
+
+	lp.Strict = nil
+	assert.Equal(t, minA, lp.pvMaxCurrent(api.ModePV, 250, false))
+}
+
+func TestStrictGridEnergy(t *testing.T) {
+	clck := clock.NewMock()
+	pushChan := make(chan push.Event, 1)
+
+	lp := &LoadPoint{
+		log:         util.NewLogger("foo"),
+		clock:       clck,
+		pushChan:    pushChan,
+		Mode:        api.ModePV,
+		Strict:      &StrictConfig{},
+		session:     &db.Session{},
+		chargePower: 10e3,
+	}
+	lp.configureStrict()
+	lp.updateSessionCost(0.5, 0.3, 0.1)
+
+	// 10kW for one minute, half from grid
+	clck.Add(time.Minute)
+	lp.updateSessionCost(0.5, 0.3, 0.1)
+	assert.InDelta(t, 10.0/60/2, lp.sessionCost.gridEnergy, 1e-6)
+	assert.Len(t, pushChan, 0)
+
+	// alarm threshold reached
+	clck.Add(time.Minute)
+	lp.updateSessionCost(0.5, 0.3, 0.1)
+	assert.Equal(t, push.Event{Event: evStrictGridUsage}, <-pushChan)
+
+	// notified once per session
+	clck.Add(time.Minute)
+	lp.updateSessionCost(0.5, 0.3, 0.1)
+	assert.Len(t, pushChan, 0)
+	assert.InDelta(t, 10.0/60/2*3, lp.sessionCost.gridEnergy, 1e-6)
+
+	// grid energy is scaled to the charged energy
+	lp.session.ChargedEnergy = 1
+	lp.session.ApplyGridEnergy(lp.sessionCost.energy, lp.sessionCost.gridEnergy)
+	assert.InDelta(t, 0.5, lp.session.GridEnergy, 1e-6)
+}
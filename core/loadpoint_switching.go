@@ -0,0 +1,80 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+const (
+	chargerCycles      = "chargerCycles"      // contactor cycles today
+	chargerCyclesTotal = "chargerCyclesTotal" // contactor cycles since tracking started
+)
+
+// SwitchingConfig defines the contactor switching behaviour to reduce noise and relay wear
+type SwitchingConfig struct {
+	MinOn     time.Duration `mapstructure:"minOn"`     // minimum duration the charger stays enabled
+	MinOff    time.Duration `mapstructure:"minOff"`    // minimum duration the charger stays disabled, merges short surplus gaps
+	MaxCycles int           `mapstructure:"maxCycles"` // maximum number of daily enable cycles in PV mode
+}
+
+// guardDuration returns the minimum holding time before the charger may be switched to enabled state
+func (lp *LoadPoint) guardDuration(enable bool) time.Duration {
+	guard := lp.GuardDuration
+
+	if enable && lp.Switching.MinOff > guard {
+		guard = lp.Switching.MinOff
+	}
+	if !enable && lp.Switching.MinOn > guard {
+		guard = lp.Switching.MinOn
+	}
+
+	return guard
+}
+
+// cyclesKey is the settings key for persisting total cycles
+func (lp *LoadPoint) cyclesKey() string {
+	return fmt.Sprintf("loadpoint.%s.cycles", lp.Title)
+}
+
+// publishCycles loads and publishes the persisted cycle counters
+func (lp *LoadPoint) publishCycles() {
+	if total, err := settings.Int(lp.cyclesKey()); err == nil {
+		lp.cyclesTotal = int(total)
+	}
+
+	lp.publish(chargerCycles, lp.cycles)
+	lp.publish(chargerCyclesTotal, lp.cyclesTotal)
+}
+
+// cycleDay returns the current day used for resetting the daily cycle counter
+func (lp *LoadPoint) cycleDay() string {
+	return lp.now().Format("2006-01-02")
+}
+
+// resetCyclesIfNewDay resets the daily cycle counter on day change
+func (lp *LoadPoint) resetCyclesIfNewDay() {
+	if day := lp.cycleDay(); lp.cyclesDay != day {
+		lp.cyclesDay = day
+		lp.cycles = 0
+	}
+}
+
+// cycleLimitReached checks if the daily cycle limit is configured and reached
+func (lp *LoadPoint) cycleLimitReached() bool {
+	lp.resetCyclesIfNewDay()
+	return lp.Switching.MaxCycles > 0 && lp.cycles >= lp.Switching.MaxCycles
+}
+
+// countCycle counts and publishes a contactor cycle
+func (lp *LoadPoint) countCycle() {
+	lp.resetCyclesIfNewDay()
+
+	lp.cycles++
+	lp.cyclesTotal++
+	settings.SetInt(lp.cyclesKey(), int64(lp.cyclesTotal))
+
+	lp.publish(chargerCycles, lp.cycles)
+	lp.publish(chargerCyclesTotal, lp.cyclesTotal)
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+)
+
+func TestSwitchingGuardDuration(t *testing.T) {
+	lp := &LoadPoint{
+		GuardDuration: 5 * time.Minute,
+		Switching: SwitchingConfig{
+			MinOn:  10 * time.Minute,
+			MinOff: 2 * time.Minute,
+		},
+	}
+
+	if d := lp.guardDuration(true); d != 5*time.Minute {
+		t.Errorf("expected guard duration, got %v", d)
+	}
+	if d := lp.guardDuration(false); d != 10*time.Minute {
+		t.Errorf("expected min on duration, got %v", d)
+	}
+}
+
+func TestSwitchingCycleLimit(t *testing.T) {
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+	charger := mock.NewMockCharger(ctrl)
+
+	lp := &LoadPoint{
+		log:           util.NewLogger("foo"),
+		bus:           evbus.New(),
+		clock:         clock,
+		charger:       charger,
+		wakeUpTimer:   NewTimer(),
+		MinCurrent:    minA,
+		MaxCurrent:    maxA,
+		GuardDuration: time.Minute,
+		Mode:          api.ModePV,
+		Switching: SwitchingConfig{
+			MaxCycles: 1,
+		},
+	}
+
+	charger.EXPECT().MaxCurrent(int64(minA)).Return(nil)
+	charger.EXPECT().Enable(true).Return(nil)
+	if err := lp.setLimit(minA, false); err != nil || !lp.enabled {
+		t.Fatal("expected enabled")
+	}
+
+	clock.Add(time.Hour)
+	charger.EXPECT().Enable(false).Return(nil)
+	if err := lp.setLimit(0, false); err != nil || lp.enabled {
+		t.Fatal("expected disabled")
+	}
+
+	// limit reached
+	clock.Add(time.Hour)
+	if err := lp.setLimit(minA, false); err != nil || lp.enabled {
+		t.Fatal("expected disabled due to cycle limit")
+	}
+
+	// forced enable ignores limit
+	charger.EXPECT().Enable(true).Return(nil)
+	if err := lp.setLimit(minA, true); err != nil || !lp.enabled {
+		t.Fatal("expected enabled")
+	}
+
+	// min+pv is not limited
+	clock.Add(time.Hour)
+	charger.EXPECT().Enable(false).Return(nil)
+	if err := lp.setLimit(0, false); err != nil || lp.enabled {
+		t.Fatal("expected disabled")
+	}
+
+	lp.Mode = api.ModeMinPV
+	clock.Add(time.Hour)
+	charger.EXPECT().Enable(true).Return(nil)
+	if err := lp.setLimit(minA, false); err != nil || !lp.enabled {
+		t.Fatal("expected enabled in min+pv mode")
+	}
+
+	if lp.cycles != 3 || lp.cyclesTotal != 3 {
+		t.Errorf("expected 3 cycles, got %d/%d", lp.cycles, lp.cyclesTotal)
+	}
+
+	// reset on next day
+	clock.Add(24 * time.Hour)
+	if lp.cycleLimitReached() || lp.cycles != 0 {
+		t.Error("expected cycles reset")
+	}
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -180,7 +181,7 @@ func TestUpdatePowerZero(t *testing.T) {
 		}
 
 		lp.Mode = tc.mode
-		lp.Update(0, false, false) // sitePower 0
+		lp.Update(context.Background(), 0, false, false) // sitePower 0
 
 		ctrl.Finish()
 	}
@@ -425,7 +426,7 @@ func TestDisableAndEnableAtTargetSoC(t *testing.T) {
 	charger.EXPECT().Status().Return(api.StatusC, nil)
 	charger.EXPECT().Enabled().Return(lp.enabled, nil)
 	charger.EXPECT().MaxCurrent(int64(maxA)).Return(nil)
-	lp.Update(500, false, false)
+	lp.Update(context.Background(), 500, false, false)
 
 	t.Log("charging above target - soc deactivates charger")
 	clock.Add(5 * time.Minute)
@@ -433,20 +434,20 @@ func TestDisableAndEnableAtTargetSoC(t *testing.T) {
 	charger.EXPECT().Status().Return(api.StatusC, nil)
 	charger.EXPECT().Enabled().Return(lp.enabled, nil)
 	charger.EXPECT().Enable(false).Return(nil)
-	lp.Update(500, false, false)
+	lp.Update(context.Background(), 500, false, false)
 
 	t.Log("deactivated charger changes status to B")
 	clock.Add(5 * time.Minute)
 	vehicle.EXPECT().SoC().Return(95.0, nil)
 	charger.EXPECT().Status().Return(api.StatusB, nil)
 	charger.EXPECT().Enabled().Return(lp.enabled, nil)
-	lp.Update(-5000, false, false)
+	lp.Update(context.Background(), -5000, false, false)
 
 	t.Log("soc has fallen below target - soc update prevented by timer")
 	clock.Add(5 * time.Minute)
 	charger.EXPECT().Status().Return(api.StatusB, nil)
 	charger.EXPECT().Enabled().Return(lp.enabled, nil)
-	lp.Update(-5000, false, false)
+	lp.Update(context.Background(), -5000, false, false)
 
 	t.Log("soc has fallen below target - soc update timer expired")
 	clock.Add(pollInterval)
@@ -454,7 +455,7 @@ func TestDisableAndEnableAtTargetSoC(t *testing.T) {
 	charger.EXPECT().Status().Return(api.StatusB, nil)
 	charger.EXPECT().Enabled().Return(lp.enabled, nil)
 	charger.EXPECT().Enable(true).Return(nil)
-	lp.Update(-5000, false, false)
+	lp.Update(context.Background(), -5000, false, false)
 
 	ctrl.Finish()
 }
@@ -494,14 +495,14 @@ func TestSetModeAndSocAtDisconnect(t *testing.T) {
 	charger.EXPECT().Enabled().Return(lp.enabled, nil)
 	charger.EXPECT().Status().Return(api.StatusC, nil)
 	charger.EXPECT().MaxCurrent(int64(maxA)).Return(nil)
-	lp.Update(500, false, false)
+	lp.Update(context.Background(), 500, false, false)
 
 	t.Log("switch off when disconnected")
 	clock.Add(5 * time.Minute)
 	charger.EXPECT().Enabled().Return(lp.enabled, nil)
 	charger.EXPECT().Status().Return(api.StatusA, nil)
 	charger.EXPECT().Enable(false).Return(nil)
-	lp.Update(-3000, false, false)
+	lp.Update(context.Background(), -3000, false, false)
 
 	if lp.Mode != api.ModeOff {
 		t.Error("unexpected mode", lp.Mode)
@@ -563,14 +564,14 @@ func TestChargedEnergyAtDisconnect(t *testing.T) {
 	rater.EXPECT().ChargedEnergy().Return(0.0, nil)
 	charger.EXPECT().Enabled().Return(lp.enabled, nil)
 	charger.EXPECT().Status().Return(api.StatusC, nil)
-	lp.Update(-1, false, false)
+	lp.Update(context.Background(), -1, false, false)
 
 	t.Log("at 1:00h charging at 5 kWh")
 	clock.Add(time.Hour)
 	rater.EXPECT().ChargedEnergy().Return(5.0, nil)
 	charger.EXPECT().Enabled().Return(lp.enabled, nil)
 	charger.EXPECT().Status().Return(api.StatusC, nil)
-	lp.Update(-1, false, false)
+	lp.Update(context.Background(), -1, false, false)
 	expectCache("chargedEnergy", 5000.0)
 
 	t.Log("at 1:00h stop charging at 5 kWh")
@@ -578,7 +579,7 @@ func TestChargedEnergyAtDisconnect(t *testing.T) {
 	rater.EXPECT().ChargedEnergy().Return(5.0, nil)
 	charger.EXPECT().Enabled().Return(lp.enabled, nil)
 	charger.EXPECT().Status().Return(api.StatusB, nil)
-	lp.Update(-1, false, false)
+	lp.Update(context.Background(), -1, false, false)
 	expectCache("chargedEnergy", 5000.0)
 
 	t.Log("at 1:00h restart charging at 5 kWh")
@@ -586,7 +587,7 @@ func TestChargedEnergyAtDisconnect(t *testing.T) {
 	rater.EXPECT().ChargedEnergy().Return(5.0, nil)
 	charger.EXPECT().Enabled().Return(lp.enabled, nil)
 	charger.EXPECT().Status().Return(api.StatusC, nil)
-	lp.Update(-1, false, false)
+	lp.Update(context.Background(), -1, false, false)
 	expectCache("chargedEnergy", 5000.0)
 
 	t.Log("at 1:30h continue charging at 7.5 kWh")
@@ -594,7 +595,7 @@ func TestChargedEnergyAtDisconnect(t *testing.T) {
 	rater.EXPECT().ChargedEnergy().Return(7.5, nil)
 	charger.EXPECT().Enabled().Return(lp.enabled, nil)
 	charger.EXPECT().Status().Return(api.StatusC, nil)
-	lp.Update(-1, false, false)
+	lp.Update(context.Background(), -1, false, false)
 	expectCache("chargedEnergy", 7500.0)
 
 	t.Log("at 2:00h stop charging at 10 kWh")
@@ -602,7 +603,7 @@ func TestChargedEnergyAtDisconnect(t *testing.T) {
 	rater.EXPECT().ChargedEnergy().Return(10.0, nil)
 	charger.EXPECT().Enabled().Return(lp.enabled, nil)
 	charger.EXPECT().Status().Return(api.StatusB, nil)
-	lp.Update(-1, false, false)
+	lp.Update(context.Background(), -1, false, false)
 	expectCache("chargedEnergy", 10000.0)
 
 	ctrl.Finish()
@@ -733,6 +734,26 @@ func TestSoCPoll(t *testing.T) {
 	}
 }
 
+func TestSoCPollRateLimited(t *testing.T) {
+	clock := clock.NewMock()
+
+	lp := &LoadPoint{
+		clock:  clock,
+		log:    util.NewLogger("foo"),
+		status: api.StatusC,
+	}
+
+	lp.socBackoff = clock.Now().Add(socRateLimitBackoff)
+	if lp.socPollAllowed() {
+		t.Error("expected no poll while rate limited")
+	}
+
+	clock.Add(socRateLimitBackoff)
+	if !lp.socPollAllowed() {
+		t.Error("expected poll after backoff")
+	}
+}
+
 func TestMinSoC(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	vhc := mock.NewMockVehicle(ctrl)
@@ -771,3 +792,39 @@ func TestMinSoC(t *testing.T) {
 		}
 	}
 }
+
+func TestTargetEnergy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	vhc := mock.NewMockVehicle(ctrl)
+
+	tc := []struct {
+		vehicle api.Vehicle
+		target  int
+		charged float64
+		res     bool
+	}{
+		{nil, 0, 0, false},      // target disabled
+		{nil, 0, 10e3, false},   // target disabled
+		{nil, 10, 0, false},     // target not reached
+		{nil, 10, 10e3, true},   // target reached
+		{nil, 10, 20e3, true},   // target reached
+		{vhc, 0, 10e3, false},   // target disabled
+		{vhc, 10, 5e3, false},   // target not reached
+		{vhc, 10, 10e3, true},   // target reached independent of vehicle soc
+		{vhc, 10, 11.5e3, true}, // target reached independent of vehicle soc
+	}
+
+	for _, tc := range tc {
+		t.Logf("%+v", tc)
+
+		lp := &LoadPoint{
+			vehicle:       tc.vehicle,
+			targetEnergy:  tc.target,
+			chargedEnergy: tc.charged,
+		}
+
+		if res := lp.targetEnergyReached(); tc.res != res {
+			t.Errorf("expected %v, got %v", tc.res, res)
+		}
+	}
+}
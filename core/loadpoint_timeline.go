@@ -0,0 +1,45 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timeline event types
+const (
+	eventMode    = "mode"
+	eventPlan    = "plan"
+	eventEnable  = "enable"
+	eventDisable = "disable"
+	eventPause   = "pause"
+	eventStitch  = "stitch"
+	eventMeter   = "meter"
+)
+
+// record adds a controller decision to the loadpoint's timeline
+func (lp *LoadPoint) record(typ, detail string) {
+	if lp.db != nil {
+		lp.db.Record(typ, detail)
+	}
+}
+
+// String returns the pause reason including its details
+func (p *PauseReason) String() string {
+	if p == nil {
+		return ""
+	}
+
+	res := []string{p.Reason}
+	if p.Detail != "" {
+		res = append(res, p.Detail)
+	}
+	if p.Power != 0 {
+		res = append(res, fmt.Sprintf("%.0fW", p.Power))
+	}
+	if p.Time != nil {
+		res = append(res, p.Time.Format(time.RFC3339))
+	}
+
+	return strings.Join(res, " ")
+}
@@ -0,0 +1,31 @@
+package core
+
+import "github.com/evcc-io/evcc/api"
+
+// setTrip suspends home planning and vehicle polling while the vehicle is away on a road trip
+func (lp *LoadPoint) setTrip(trip bool) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.trip == trip {
+		return
+	}
+
+	if lp.trip = trip; trip {
+		lp.log.INFO.Println("road trip: suspending planning and vehicle polling")
+	} else {
+		lp.log.INFO.Println("road trip: resuming")
+
+		// remove plan that expired during the trip
+		if t := lp.socTimer.Time; !t.IsZero() && t.Before(lp.clock.Now()) {
+			lp.socTimer.Reset()
+		}
+	}
+
+	lp.publish("vehicleTrip", trip)
+}
+
+// tripSuspended returns true if the disconnected vehicle is on a road trip
+func (lp *LoadPoint) tripSuspended() bool {
+	return lp.trip && lp.status != api.StatusB && lp.status != api.StatusC
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -357,7 +358,7 @@ func TestReconnectVehicle(t *testing.T) {
 	// vehicle not updated yet
 	vehicle.MockChargeState.EXPECT().Status().Return(api.StatusA, nil)
 
-	lp.Update(0, false, false)
+	lp.Update(context.Background(), 0, false, false)
 	ctrl.Finish()
 
 	// detection started
@@ -375,7 +376,7 @@ func TestReconnectVehicle(t *testing.T) {
 	// vehicle not updated yet
 	vehicle.MockChargeState.EXPECT().Status().Return(api.StatusB, nil)
 
-	lp.Update(0, false, false)
+	lp.Update(context.Background(), 0, false, false)
 	ctrl.Finish()
 
 	// vehicle detected
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// guestRate is the rate applied for unknown or missing identification
+const guestRate = "guest"
+
+// RateConfig defines a charging rate for identified users, e.g. employees or owner
+type RateConfig struct {
+	Title       string   `mapstructure:"title"`
+	Price       float64  `mapstructure:"price"`       // price per kWh
+	Identifiers []string `mapstructure:"identifiers"` // identification tokens, * as placeholder
+}
+
+// PricingConfig defines session pricing depending on the identification token
+type PricingConfig struct {
+	Guest float64      `mapstructure:"guest"` // price per kWh for unknown or missing identification
+	Rates []RateConfig `mapstructure:"rates"`
+}
+
+// pricing resolves the rate of identification tokens
+type pricing struct {
+	PricingConfig
+	patterns [][]*regexp.Regexp
+}
+
+// newPricingFromConfig creates pricing
+func newPricingFromConfig(cc PricingConfig) (*pricing, error) {
+	p := &pricing{PricingConfig: cc}
+
+	for _, rate := range cc.Rates {
+		if rate.Title == "" {
+			return nil, fmt.Errorf("rate: missing title")
+		}
+
+		var patterns []*regexp.Regexp
+		for _, id := range rate.Identifiers {
+			// case insensitive match
+			re, err := regexp.Compile("(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(id), `\*`, ".*?") + "$")
+			if err != nil {
+				return nil, fmt.Errorf("rate %s: %w", rate.Title, err)
+			}
+			patterns = append(patterns, re)
+		}
+
+		p.patterns = append(p.patterns, patterns)
+	}
+
+	return p, nil
+}
+
+// rate returns title and price per kWh of the identification's rate
+func (p *pricing) rate(id string) (string, float64) {
+	if id != "" {
+		// find exact match
+		for _, rate := range p.Rates {
+			for _, rid := range rate.Identifiers {
+				if strings.EqualFold(id, rid) {
+					return rate.Title, rate.Price
+				}
+			}
+		}
+
+		// find placeholder match
+		for i, rate := range p.Rates {
+			for _, re := range p.patterns[i] {
+				if re.MatchString(id) {
+					return rate.Title, rate.Price
+				}
+			}
+		}
+	}
+
+	return guestRate, p.Guest
+}
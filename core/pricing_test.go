@@ -0,0 +1,38 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPricingRate(t *testing.T) {
+	p, err := newPricingFromConfig(PricingConfig{
+		Guest: 0.45,
+		Rates: []RateConfig{
+			{Title: "employee", Price: 0.2, Identifiers: []string{"04A1*", "04B2C3"}},
+			{Title: "owner", Price: 0, Identifiers: []string{"04a1ffee"}},
+		},
+	})
+	require.NoError(t, err)
+
+	tc := []struct {
+		id    string
+		rate  string
+		price float64
+	}{
+		{"", guestRate, 0.45},
+		{"DEADBEEF", guestRate, 0.45},
+		{"04B2C3", "employee", 0.2},
+		{"04a1beef", "employee", 0.2},
+		{"04A1FFEE", "owner", 0}, // exact match before placeholder
+		{"X04A1", guestRate, 0.45},
+	}
+
+	for _, tc := range tc {
+		rate, price := p.rate(tc.id)
+		assert.Equal(t, tc.rate, rate, tc.id)
+		assert.Equal(t, tc.price, price, tc.id)
+	}
+}
@@ -0,0 +1,180 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"golang.org/x/exp/slices"
+)
+
+// RotationConfig defines a group of loadpoints taking turns when their demand exceeds a limited supply
+type RotationConfig struct {
+	Loadpoints []string      `mapstructure:"loadpoints"` // loadpoint titles, all loadpoints if empty
+	MaxPower   float64       `mapstructure:"maxPower"`   // power available to the group
+	Delay      time.Duration `mapstructure:"delay"`      // duration demand must exceed maximum power before rotating
+	Slice      time.Duration `mapstructure:"slice"`      // duration of each loadpoint's turn
+}
+
+// rotation cycles full charging rights among a group of loadpoints in time slices
+type rotation struct {
+	loadpoints []*LoadPoint
+	maxPower   float64
+	delay      time.Duration
+	slice      time.Duration
+	exceeded   time.Time           // time demand started to exceed maximum power
+	sliced     time.Time           // time of last rotation
+	next       int                 // index of loadpoint starting the next slice
+	granted    map[*LoadPoint]bool // loadpoints allowed to charge, nil if rotation inactive
+}
+
+// newRotationFromConfig creates a rotation and attaches it to the referenced loadpoints
+func newRotationFromConfig(cc RotationConfig, loadpoints []*LoadPoint) (*rotation, error) {
+	if cc.MaxPower <= 0 {
+		return nil, errors.New("rotation: missing maxPower")
+	}
+
+	r := &rotation{
+		maxPower: cc.MaxPower,
+		delay:    cc.Delay,
+		slice:    cc.Slice,
+	}
+
+	if r.delay == 0 {
+		r.delay = 15 * time.Minute
+	}
+	if r.slice == 0 {
+		r.slice = 30 * time.Minute
+	}
+
+	if len(cc.Loadpoints) == 0 {
+		r.loadpoints = loadpoints
+	}
+
+	for _, title := range cc.Loadpoints {
+		idx := slices.IndexFunc(loadpoints, func(lp *LoadPoint) bool {
+			return lp.Title == title
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("rotation: loadpoint not found: %s", title)
+		}
+
+		if slices.Contains(r.loadpoints, loadpoints[idx]) {
+			return nil, fmt.Errorf("rotation: duplicate loadpoint: %s", title)
+		}
+
+		r.loadpoints = append(r.loadpoints, loadpoints[idx])
+	}
+
+	if len(r.loadpoints) < 2 {
+		return nil, errors.New("rotation: need at least two loadpoints")
+	}
+
+	for _, lp := range r.loadpoints {
+		if lp.rotation != nil {
+			return nil, fmt.Errorf("rotation: loadpoint already assigned: %s", lp.Title)
+		}
+	}
+
+	for _, lp := range r.loadpoints {
+		lp.rotation = r
+	}
+
+	return r, nil
+}
+
+// demanding returns if the loadpoint wants to charge.
+// Enabled loadpoints with connected but not charging vehicles are considered complete.
+func (r *rotation) demanding(lp *LoadPoint) bool {
+	return lp.GetMode() != api.ModeOff &&
+		(lp.GetStatus() == api.StatusC || lp.GetStatus() == api.StatusB && !lp.enabled)
+}
+
+// update starts, advances or ends the rotation depending on the group's demand.
+// It is called from the site's update loop and therefore accesses loadpoints' state without locking.
+func (r *rotation) update(now time.Time) {
+	var demand float64
+	var active bool
+
+	for _, lp := range r.loadpoints {
+		if r.demanding(lp) {
+			demand += lp.GetMaxPower()
+			active = active || r.granted[lp]
+		}
+	}
+
+	if demand <= r.maxPower {
+		if r.granted != nil {
+			r.loadpoints[0].log.INFO.Println("rotation: demand within limit, resuming all loadpoints")
+		}
+
+		r.exceeded = time.Time{}
+		r.granted = nil
+		return
+	}
+
+	if r.exceeded.IsZero() {
+		r.exceeded = now
+	}
+
+	if now.Sub(r.exceeded) < r.delay {
+		return
+	}
+
+	// rotate after the slice has elapsed or if no granted loadpoint is demanding anymore
+	if r.granted != nil && active && now.Sub(r.sliced) < r.slice {
+		return
+	}
+
+	r.rotate()
+	r.sliced = now
+}
+
+// rotate grants charging rights to the next demanding loadpoints fitting into maximum power
+func (r *rotation) rotate() {
+	var power float64
+	var titles []string
+
+	granted := make(map[*LoadPoint]bool)
+	start := r.next
+
+	for i := 0; i < len(r.loadpoints); i++ {
+		idx := (start + i) % len(r.loadpoints)
+		lp := r.loadpoints[idx]
+
+		if !r.demanding(lp) {
+			continue
+		}
+
+		// first loadpoint is always granted
+		if len(granted) > 0 && power+lp.GetMaxPower() > r.maxPower {
+			break
+		}
+
+		power += lp.GetMaxPower()
+		granted[lp] = true
+		titles = append(titles, lp.Title)
+
+		r.next = (idx + 1) % len(r.loadpoints)
+	}
+
+	r.granted = granted
+	r.loadpoints[0].log.INFO.Printf("rotation: demand exceeds %.0fW, granting %v", r.maxPower, titles)
+}
+
+// blocked returns if the loadpoint is waiting for its turn
+func (r *rotation) blocked(lp *LoadPoint) bool {
+	return r.granted != nil && !r.granted[lp]
+}
+
+// rotationCurrent disables charging while the loadpoint is waiting for its turn
+func (lp *LoadPoint) rotationCurrent(chargeCurrent float64) (float64, bool) {
+	if lp.rotation == nil || chargeCurrent == 0 || !lp.rotation.blocked(lp) {
+		return chargeCurrent, false
+	}
+
+	lp.log.DEBUG.Println("rotation: waiting for turn")
+
+	return 0, true
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotation(t *testing.T) {
+	Voltage = 230 // V
+
+	clck := clock.NewMock()
+
+	// 11kW each
+	var loadpoints []*LoadPoint
+	for _, title := range []string{"lp1", "lp2", "lp3"} {
+		loadpoints = append(loadpoints, &LoadPoint{
+			log:        util.NewLogger(title),
+			clock:      clck,
+			Title:      title,
+			Mode:       api.ModeNow,
+			MaxCurrent: 16,
+			phases:     3,
+			status:     api.StatusA,
+		})
+	}
+	lp1, lp2, lp3 := loadpoints[0], loadpoints[1], loadpoints[2]
+
+	r, err := newRotationFromConfig(RotationConfig{MaxPower: 25e3, Delay: time.Minute, Slice: time.Hour}, loadpoints)
+	require.NoError(t, err)
+
+	_, err = newRotationFromConfig(RotationConfig{MaxPower: 25e3}, loadpoints)
+	assert.Error(t, err, "already assigned")
+
+	blocked := func() (res []bool) {
+		for _, lp := range loadpoints {
+			res = append(res, r.blocked(lp))
+		}
+		return res
+	}
+
+	// demand within limit
+	lp1.status, lp2.status = api.StatusC, api.StatusC
+	r.update(clck.Now())
+	assert.Equal(t, []bool{false, false, false}, blocked())
+
+	// demand exceeds limit, waiting for delay
+	lp3.status = api.StatusB
+	r.update(clck.Now())
+	assert.Equal(t, []bool{false, false, false}, blocked())
+
+	clck.Add(time.Minute)
+	r.update(clck.Now())
+	assert.Equal(t, []bool{false, false, true}, blocked())
+
+	current, ok := lp3.rotationCurrent(16)
+	assert.True(t, ok)
+	assert.Equal(t, 0.0, current)
+
+	// slice not elapsed
+	clck.Add(30 * time.Minute)
+	r.update(clck.Now())
+	assert.Equal(t, []bool{false, false, true}, blocked())
+
+	// next slice starts with third loadpoint
+	lp1.enabled, lp2.enabled = true, true
+	clck.Add(30 * time.Minute)
+	r.update(clck.Now())
+	assert.Equal(t, []bool{false, true, false}, blocked())
+
+	// blocked loadpoint disabled
+	lp2.status, lp3.status = api.StatusB, api.StatusC
+	lp2.enabled, lp3.enabled = false, true
+	r.update(clck.Now())
+	assert.Equal(t, []bool{false, true, false}, blocked())
+
+	// vehicle complete, demand within limit ends rotation
+	lp1.status = api.StatusB
+	r.update(clck.Now())
+	assert.Equal(t, []bool{false, false, false}, blocked())
+
+	_, ok = lp2.rotationCurrent(16)
+	assert.False(t, ok)
+
+	// restarted rotation continues with loadpoint that waited last
+	lp1.enabled = false
+	r.update(clck.Now())
+	clck.Add(time.Minute)
+	r.update(clck.Now())
+	assert.Equal(t, []bool{true, false, false}, blocked())
+}
@@ -131,6 +131,11 @@ func (s *Savings) currentFeedInPrice() float64 {
 	return DefaultFeedInPrice
 }
 
+// prices returns the grid and feed-in prices of the last update
+func (s *Savings) prices() (float64, float64) {
+	return s.lastGridPrice, s.lastFeedInPrice
+}
+
 func (s *Savings) updatePrices(p publisher) (float64, float64) {
 	gridPrice := s.currentGridPrice()
 	if gridPrice != s.lastGridPrice {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/db"
 )
 
 func assertEnergy(t *testing.T, s *Savings, total, self, percentage float64) {
@@ -224,3 +225,32 @@ func TestEffectiveEnergyPriceAndSavingsAmount(t *testing.T) {
 		assertPrices(t, s, tc.effectivePrice, tc.savingsAmount)
 	}
 }
+
+func TestSessionCost(t *testing.T) {
+	clck := clock.NewMock()
+	lp := &LoadPoint{clock: clck, chargePower: 10e3, session: new(db.Session)}
+
+	// first update starts accounting
+	lp.updateSessionCost(0.5, 0.3, 0.1)
+	clck.Add(time.Hour)
+	lp.updateSessionCost(0.5, 0.3, 0.1)
+
+	if !compareWithTolerane(lp.sessionCost.energy, 10) {
+		t.Errorf("energy was incorrect, got: %.3f, want: %.3f.", lp.sessionCost.energy, 10.0)
+	}
+	if !compareWithTolerane(lp.sessionCost.cost, 2) {
+		t.Errorf("cost was incorrect, got: %.3f, want: %.3f.", lp.sessionCost.cost, 2.0)
+	}
+	if !compareWithTolerane(lp.sessionCost.gridCost, 3) {
+		t.Errorf("grid cost was incorrect, got: %.3f, want: %.3f.", lp.sessionCost.gridCost, 3.0)
+	}
+
+	// no session, no cost
+	lp.session = nil
+	clck.Add(time.Hour)
+	lp.updateSessionCost(0.5, 0.3, 0.1)
+
+	if !compareWithTolerane(lp.sessionCost.energy, 10) {
+		t.Errorf("energy was incorrect, got: %.3f, want: %.3f.", lp.sessionCost.energy, 10.0)
+	}
+}
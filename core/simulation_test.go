@@ -0,0 +1,51 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/coordinator"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type simulatedUpdater struct {
+	clock   clock.Clock
+	updates []time.Time
+}
+
+func (u *simulatedUpdater) Update(context.Context, float64, bool, bool) {
+	u.updates = append(u.updates, u.clock.Now())
+}
+
+func TestSimulation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	grid := mock.NewMockMeter(ctrl)
+	grid.EXPECT().CurrentPower().Return(-1000.0, nil).AnyTimes()
+
+	site := NewSite()
+	site.gridMeter = grid
+	site.savings = NewSavings(tariff.Tariffs{})
+	site.coordinator = coordinator.New(util.NewLogger("foo"), nil)
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	sim := NewSimulation(site, start, 10*time.Second)
+
+	u := &simulatedUpdater{clock: sim.Clock}
+	sim.updaters = []Updater{u}
+
+	sim.Run(time.Hour)
+
+	assert.Equal(t, start.Add(time.Hour), sim.Now())
+	assert.Len(t, u.updates, 360)
+	assert.Equal(t, start.Add(10*time.Second), u.updates[0])
+
+	// savings use simulated time
+	assert.Equal(t, sim.Now(), site.savings.updated)
+}
@@ -1,20 +1,26 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
 	"sync"
 	"time"
+	_ "time/tzdata" // embedded timezone database for hosts without zoneinfo
 
 	"github.com/avast/retry-go/v3"
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core/coordinator"
 	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/core/forecast"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/push"
 	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/solar"
 	"github.com/evcc-io/evcc/tariff"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/telemetry"
@@ -24,43 +30,99 @@ const standbyPower = 10 // consider less than 10W as charger in standby
 
 // Updater abstracts the LoadPoint implementation for testing
 type Updater interface {
-	Update(availablePower float64, cheapRate, batteryBuffered bool)
+	Update(ctx context.Context, availablePower float64, cheapRate, batteryBuffered bool)
 }
 
 // Site is the main configuration container. A site can host multiple loadpoints.
 type Site struct {
 	uiChan       chan<- util.Param // client push messages
+	pushChan     chan<- push.Event // site notifications
 	lpUpdateChan chan *LoadPoint
+	closeC       chan struct{} // stops the loadpoints' message pipes
 
 	*Health
 
 	sync.Mutex
-	log *util.Logger
+	log          *util.Logger
+	clock        clock.Clock    // mockable time
+	location     *time.Location // timezone for planning, tariffs and statistics
+	cycleTimeout time.Duration  // deadline for device requests per cycle
 
 	// configuration
-	Title                             string       `mapstructure:"title"`         // UI title
-	Voltage                           float64      `mapstructure:"voltage"`       // Operating voltage. 230V for Germany.
-	ResidualPower                     float64      `mapstructure:"residualPower"` // PV meter only: household usage. Grid meter: household safety margin
-	Meters                            MetersConfig // Meter references
-	PrioritySoC                       float64      `mapstructure:"prioritySoC"`                       // prefer battery up to this SoC
-	BufferSoC                         float64      `mapstructure:"bufferSoC"`                         // ignore battery above this SoC
-	MaxGridSupplyWhileBatteryCharging float64      `mapstructure:"maxGridSupplyWhileBatteryCharging"` // ignore battery charging if AC consumption is above this value
+	Title                             string               `mapstructure:"title"`         // UI title
+	Voltage                           float64              `mapstructure:"voltage"`       // Operating voltage. 230V for Germany.
+	ResidualPower                     float64              `mapstructure:"residualPower"` // PV meter only: household usage. Grid meter: household safety margin
+	Meters                            MetersConfig         // Meter references
+	PrioritySoC                       float64              `mapstructure:"prioritySoC"`                       // prefer battery up to this SoC
+	BufferSoC                         float64              `mapstructure:"bufferSoC"`                         // ignore battery above this SoC
+	BatteryPriority                   site.BatteryPriority `mapstructure:"batteryPriority"`                   // order of battery and vehicle charging, replaces prioritySoC
+	MaxGridSupplyWhileBatteryCharging float64              `mapstructure:"maxGridSupplyWhileBatteryCharging"` // ignore battery charging if AC consumption is above this value
+	Timezone                          string               `mapstructure:"timezone"`                          // IANA timezone for planning, tariffs and statistics
+	BatteryCapacity                   float64              `mapstructure:"batteryCapacity"`                   // usable battery capacity in kWh for forecasting
+	PredictSurplus                    bool                 `mapstructure:"predictSurplus"`                    // use learned profiles to avoid phase switching before surplus drops
+	SharedSupply                      []SharedSupplyConfig `mapstructure:"sharedSupply"`                      // loadpoints sharing a single supply line
+	Frequency                         FrequencyConfig      `mapstructure:"frequency"`                         // load shedding on grid frequency deviation
+	Island                            IslandConfig         `mapstructure:"island"`                            // restricted charging during island operation
+	Generator                         *GeneratorConfig     `mapstructure:"generator"`                         // dispatchable generator for off-grid operation
+	Diversion                         []DiversionConfig    `mapstructure:"diversion"`                         // ordered surplus consumers after vehicles and battery
+	Billing                           *BillingConfig       `mapstructure:"billing"`                           // billing periods
+	Budget                            *BudgetConfig        `mapstructure:"budget"`                            // monthly grid energy or cost budget for charging
+	Pricing                           *PricingConfig       `mapstructure:"pricing"`                           // session pricing by identification
+	ReferencePrice                    float64              `mapstructure:"referencePrice"`                    // static household price per kWh for savings comparison
+	Rotation                          []RotationConfig     `mapstructure:"rotation"`                          // loadpoints taking turns on limited supply
+	Circuits                          []CircuitConfig      `mapstructure:"circuits"`                          // nested circuits limiting their loadpoints' load
+	Timeline                          TimelineConfig       `mapstructure:"timeline"`                          // decision timeline retention
+	Forecasts                         []solar.Config       `mapstructure:"forecasts"`                         // solar forecast providers
+	Forecasters                       ForecastersConfig    `mapstructure:"forecasters"`                       // pluggable production, consumption and price forecasts
+	Storm                             *StormConfig         `mapstructure:"storm"`                             // pre-charging ahead of severe weather
+	Frost                             *FrostConfig         `mapstructure:"frost"`                             // home battery protection at low temperatures
+	Automation                        []AutomationConfig   `mapstructure:"automation"`                        // loadpoint mode rules by schedule and presence
+	Intervals                         IntervalsConfig      `mapstructure:"intervals"`                         // polling intervals by device class
+	Plans                             []PlanConfig         `mapstructure:"plans"`                             // named target charge plan templates
+	PlanLock                          bool                 `mapstructure:"planLock"`                          // lock grid rates of committed target charge plans
 
 	// meters
-	gridMeter     api.Meter   // Grid usage meter
-	pvMeters      []api.Meter // PV generation meters
-	batteryMeters []api.Meter // Battery charging meters
-
-	tariffs     tariff.Tariffs           // Tariff
-	loadpoints  []*LoadPoint             // Loadpoints
-	coordinator *coordinator.Coordinator // Savings
-	savings     *Savings                 // Savings
+	gridMeter      api.Meter          // Grid usage meter
+	pvMeters       []api.Meter        // PV generation meters
+	batteryMeters  []api.Meter        // Battery charging meters
+	frequency      api.MeterFrequency // Grid frequency meter
+	gridPoll       *pollTimer         // grid meter polling interval
+	pvPoll         *pollTimer         // pv meters polling interval
+	batteryPoll    *pollTimer         // battery meters polling interval
+	islandDetector api.MeterIsland    // Island operation detection
+	meterEx        sync.Map           // serializes abandoned reads per meter
+
+	tariffs        tariff.Tariffs           // Tariff
+	loadpoints     []*LoadPoint             // Loadpoints
+	coordinator    *coordinator.Coordinator // Savings
+	savings        *Savings                 // Savings
+	pvProfile      *forecast.Profile        // Learned pv power profile
+	homeProfile    *forecast.Profile        // Learned home power profile
+	pvForecast     *forecast.Blender        // Blended solar forecast
+	homeForecast   *forecast.Blender        // Blended consumption forecast
+	priceForecasts []api.Forecaster         // Grid price forecasts
+	generator      *generator               // Dispatchable generator
+	diverters      []*diverter              // Surplus diversion chain
+	geofences      []*geofence              // Vehicle geofences
+	billing        *billing                 // Billing periods
+	budget         *budget                  // Charging budget
+	rotations      []*rotation              // Loadpoint rotation groups
+	circuits       []*circuit               // Circuit hierarchy
+	storm          *storm                   // Severe weather pre-charging
+	frost          *frost                   // Home battery frost protection
+	automation     *automation              // Loadpoint mode rules
+	plans          []*plan                  // Target charge plan templates
 
 	// cached state
-	gridPower       float64 // Grid power
-	pvPower         float64 // PV power
-	batteryPower    float64 // Battery charge power
-	batteryBuffered bool    // Battery buffer active
+	gridPower       float64   // Grid power
+	pvPower         float64   // PV power
+	batteryPower    float64   // Battery charge power
+	batterySoC      float64   // Battery soc
+	batteryBuffered bool      // Battery buffer active
+	shed            float64   // Share of charging load allowed by load shedding
+	recovered       time.Time // Time of grid frequency recovery
+	island          bool      // Island operation active
+	timelinePurged  time.Time // Time of last timeline purge
 }
 
 // MetersConfig contains the loadpoint's meter configuration
@@ -86,11 +148,69 @@ func NewSiteFromConfig(
 		return nil, err
 	}
 
+	// site timezone independent of host timezone
+	if site.Timezone != "" {
+		loc, err := time.LoadLocation(site.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("timezone: %w", err)
+		}
+		site.location = loc
+	}
+
+	if err := site.BatteryPriority.Validate(); err != nil {
+		return nil, err
+	}
+
 	Voltage = site.Voltage
 	site.loadpoints = loadpoints
 	site.tariffs = tariffs
+
+	for _, lp := range loadpoints {
+		lp.location = site.location
+	}
+
+	if site.location != time.Local {
+		for _, t := range []api.Tariff{tariffs.Grid, tariffs.FeedIn} {
+			if tl, ok := t.(api.TariffLocation); ok {
+				tl.SetLocation(site.location)
+			}
+		}
+	}
+	site.prepareIntervals()
 	site.coordinator = coordinator.New(log, vehicles)
 	site.savings = NewSavings(tariffs)
+	site.pvProfile = forecast.NewProfile("forecast.pv")
+	site.homeProfile = forecast.NewWeeklyProfile("forecast.home.weekly")
+
+	var names []string
+	var forecasts []api.SolarForecast
+
+	for i, cc := range site.Forecasts {
+		f, err := solar.NewFromConfig(cc.Type, cc.Other)
+		if err != nil {
+			return nil, fmt.Errorf("forecasts[%d]: %w", i, err)
+		}
+
+		names = append(names, fmt.Sprintf("%d:%s", i, cc.Type))
+		forecasts = append(forecasts, f)
+	}
+
+	// pluggable production forecasts are blended with the solar forecasts
+	productionNames, production, err := newPowerForecasts("production", site.Forecasters.Production)
+	if err != nil {
+		return nil, err
+	}
+
+	names = append(names, productionNames...)
+	forecasts = append(forecasts, production...)
+
+	if len(forecasts) > 0 {
+		site.pvForecast = forecast.NewBlender("forecast.solar", names, forecasts)
+	}
+
+	if err := site.prepareForecasters(); err != nil {
+		return nil, err
+	}
 
 	// migrate session log
 	if serverdb.Instance != nil {
@@ -100,7 +220,7 @@ func NewSiteFromConfig(
 			err = serverdb.Instance.Migrator().RenameTable(table, new(db.Session))
 		}
 		if err == nil {
-			err = serverdb.Instance.AutoMigrate(new(db.Session))
+			err = serverdb.Instance.AutoMigrate(new(db.Session), new(db.Period), new(db.Event))
 		}
 		if err != nil {
 			return nil, err
@@ -109,8 +229,17 @@ func NewSiteFromConfig(
 
 	// give loadpoints access to vehicles and database
 	for _, lp := range loadpoints {
+		lp.detachSite()
 		lp.coordinator = coordinator.NewAdapter(lp, site.coordinator)
 
+		if site.PredictSurplus {
+			lp.predictor = site
+		}
+
+		if site.Frequency.Threshold > 0 {
+			lp.shedder = site
+		}
+
 		if serverdb.Instance != nil {
 			var err error
 			if lp.db, err = db.New(lp.Title); err != nil {
@@ -122,6 +251,44 @@ func NewSiteFromConfig(
 		}
 	}
 
+	// mutually exclusive loadpoints on shared supply
+	for _, cc := range site.SharedSupply {
+		if _, err := newSharedSupplyFromConfig(cc, loadpoints); err != nil {
+			return nil, err
+		}
+	}
+
+	// loadpoints taking turns on limited supply
+	for _, cc := range site.Rotation {
+		r, err := newRotationFromConfig(cc, loadpoints)
+		if err != nil {
+			return nil, err
+		}
+		site.rotations = append(site.rotations, r)
+	}
+
+	// nested circuits distributing their headroom
+	if len(site.Circuits) > 0 {
+		var err error
+		if site.circuits, err = newCircuitsFromConfig(site.log, cp, site.Circuits, loadpoints); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(site.Automation) > 0 {
+		var err error
+		if site.automation, err = newAutomationFromConfig(site.log, site.Automation, loadpoints); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(site.Plans) > 0 {
+		var err error
+		if site.plans, err = newPlansFromConfig(site.Plans, loadpoints); err != nil {
+			return nil, err
+		}
+	}
+
 	if site.Meters.GridMeterRef != "" {
 		var err error
 		if site.gridMeter, err = cp.Meter(site.Meters.GridMeterRef); err != nil {
@@ -176,24 +343,165 @@ func NewSiteFromConfig(
 		return nil, errors.New("missing either grid or pv meter")
 	}
 
+	if site.Frequency.Threshold > 0 {
+		if site.frequency = site.frequencyMeter(); site.frequency == nil {
+			return nil, errors.New("frequency: missing meter with grid frequency")
+		}
+	}
+
+	if site.Generator != nil {
+		var err error
+		if site.generator, err = newGeneratorFromConfig(site.log, site.clock, *site.Generator); err != nil {
+			return nil, err
+		}
+
+		for _, lp := range loadpoints {
+			lp.dispatcher = site
+		}
+	}
+
+	if site.Pricing != nil {
+		p, err := newPricingFromConfig(*site.Pricing)
+		if err != nil {
+			return nil, fmt.Errorf("pricing: %w", err)
+		}
+
+		for _, lp := range loadpoints {
+			lp.pricing = p
+		}
+	}
+
+	if site.Storm != nil {
+		var err error
+		if site.storm, err = newStormFromConfig(site.log, *site.Storm); err != nil {
+			return nil, err
+		}
+	}
+
+	if site.Frost != nil {
+		var err error
+		if site.frost, err = newFrostFromConfig(site.log, *site.Frost); err != nil {
+			return nil, err
+		}
+	}
+
+	if site.Billing != nil {
+		var err error
+		if site.billing, err = newBillingFromConfig(*site.Billing); err != nil {
+			return nil, fmt.Errorf("billing: %w", err)
+		}
+	}
+
+	if site.Budget != nil {
+		var err error
+		if site.budget, err = newBudgetFromConfig("budget", *site.Budget); err != nil {
+			return nil, fmt.Errorf("budget: %w", err)
+		}
+
+		site.budget.publish = site.publish
+		site.budget.notify = func(event string) {
+			if site.pushChan != nil {
+				site.pushChan <- push.Event{Event: event}
+			}
+		}
+
+		for _, lp := range loadpoints {
+			lp.budgets = append(lp.budgets, site.budget)
+		}
+	}
+
+	for i, cc := range site.Diversion {
+		d, err := newDiverterFromConfig(site.log, cc)
+		if err != nil {
+			return nil, fmt.Errorf("diversion %d: %w", i, err)
+		}
+		site.diverters = append(site.diverters, d)
+	}
+
+	for _, v := range vehicles {
+		g, err := newGeofence(v, loadpoints)
+		if err != nil {
+			return nil, fmt.Errorf("geofence %s: %w", v.Title(), err)
+		}
+		if g != nil {
+			site.geofences = append(site.geofences, g)
+		}
+	}
+
+	// restrict charging during island operation
+	if site.islandDetector = site.islandMeter(); site.islandDetector != nil {
+		for _, lp := range loadpoints {
+			lp.islander = site
+		}
+	}
+
+	if site.PlanLock {
+		_, rates := site.tariffs.Grid.(api.TariffRates)
+		_, limit := site.tariffs.Grid.(api.TariffCheapLimit)
+		if !rates || !limit {
+			return nil, errors.New("planLock: grid tariff without rates")
+		}
+
+		for _, lp := range loadpoints {
+			lp.planner = site
+		}
+	}
+
 	return site, nil
 }
 
 // NewSite creates a Site with sane defaults
 func NewSite() *Site {
 	lp := &Site{
-		log:     util.NewLogger("site"),
-		Voltage: 230, // V
+		log:      util.NewLogger("site"),
+		clock:    clock.New(),
+		location: time.Local,
+		Voltage:  230, // V
+		shed:     1,
 	}
 
 	return lp
 }
 
-// LoadPoints returns the array of associated loadpoints
+// SetClock replaces the time source of site and loadpoints, e.g. for simulation. Must be called before Prepare.
+func (site *Site) SetClock(clck clock.Clock) {
+	site.clock = clck
+
+	if site.savings != nil {
+		site.savings.clock = clck
+		site.savings.updated = clck.Now()
+	}
+
+	if site.generator != nil {
+		site.generator.clock = clck
+	}
+
+	for _, lp := range site.loadpoints {
+		lp.setClock(clck)
+	}
+}
+
+// now returns the current time in the site's timezone
+func (site *Site) now() time.Time {
+	if site.location == nil {
+		return site.clock.Now()
+	}
+	return site.clock.Now().In(site.location)
+}
+
+// Location returns the site's timezone
+func (site *Site) Location() *time.Location {
+	if site.location == nil {
+		return time.Local
+	}
+	return site.location
+}
+
+// LoadPoints returns the array of associated loadpoints as exposed to api and ui
 func (site *Site) LoadPoints() []loadpoint.API {
 	res := make([]loadpoint.API, len(site.loadpoints))
 	for id, lp := range site.loadpoints {
-		res[id] = lp
+		res[id] = apiLoadPoint{lp}
 	}
 	return res
 }
@@ -306,9 +614,10 @@ func (site *Site) publish(key string, val interface{}) {
 }
 
 // updateMeter updates and publishes single meter
-func (site *Site) updateMeter(meter api.Meter, power *float64) func() error {
+func (site *Site) updateMeter(ctx context.Context, meter api.Meter, power *float64) func() error {
 	return func() error {
-		value, err := meter.CurrentPower()
+		ex, _ := site.meterEx.LoadOrStore(meter, new(util.Exclusive))
+		value, err := currentPower(ctx, ex.(*util.Exclusive), meter)
 		if err == nil {
 			*power = value // update value if no error
 		}
@@ -318,13 +627,15 @@ func (site *Site) updateMeter(meter api.Meter, power *float64) func() error {
 }
 
 // updateMeter updates and publishes single meter
-func (site *Site) updateMeters() error {
+func (site *Site) updateMeters(ctx context.Context) error {
+	opts := append(retryOptions, retry.Context(ctx))
+
 	retryMeter := func(name string, meter api.Meter, power *float64) error {
 		if meter == nil {
 			return nil
 		}
 
-		err := retry.Do(site.updateMeter(meter, power), retryOptions...)
+		err := retry.Do(site.updateMeter(ctx, meter, power), opts...)
 
 		if err == nil {
 			site.log.DEBUG.Printf("%s power: %.0fW", name, *power)
@@ -337,47 +648,65 @@ func (site *Site) updateMeters() error {
 		return err
 	}
 
-	if len(site.pvMeters) > 0 {
-		site.pvPower = 0
+	if len(site.pvMeters) > 0 && site.pvPoll.due() {
+		var pvPower float64
+		var pvErr error
 
 		for id, meter := range site.pvMeters {
 			var power float64
-			err := retry.Do(site.updateMeter(meter, &power), retryOptions...)
+			err := retry.Do(site.updateMeter(ctx, meter, &power), opts...)
 
 			if err == nil {
 				// ignore negative values which represent self-consumption
-				site.pvPower += math.Max(0, power)
+				pvPower += math.Max(0, power)
 				if power < -500 {
 					site.log.WARN.Printf("pv %d power: %.0fW is negative - check configuration if sign is correct", id, power)
 				}
 			} else {
-				err = fmt.Errorf("pv meter %d: %v", id, err)
-				site.log.ERROR.Println(err)
+				pvErr = fmt.Errorf("pv meter %d: %v", id, err)
+				site.log.ERROR.Println(pvErr)
 			}
 		}
 
+		site.pvPower = pvPower
+		if pvErr == nil {
+			site.pvPoll.done()
+		}
+
 		site.log.DEBUG.Printf("pv power: %.0fW", site.pvPower)
 		site.publish("pvPower", site.pvPower)
 	}
 
-	if len(site.batteryMeters) > 0 {
-		site.batteryPower = 0
+	if len(site.batteryMeters) > 0 && site.batteryPoll.due() {
+		var batteryPower float64
+		var batteryErr error
 
 		for id, meter := range site.batteryMeters {
 			var power float64
-			err := retry.Do(site.updateMeter(meter, &power), retryOptions...)
+			err := retry.Do(site.updateMeter(ctx, meter, &power), opts...)
 
 			if err == nil {
-				site.batteryPower += power
+				batteryPower += power
 			} else {
+				batteryErr = err
 				site.log.ERROR.Printf("battery meter %d: %v", id, err)
 			}
 		}
 
+		site.batteryPower = batteryPower
+		if batteryErr == nil {
+			site.batteryPoll.done()
+		}
+
 		site.log.DEBUG.Printf("battery power: %.0fW", site.batteryPower)
 		site.publish("batteryPower", site.batteryPower)
 	}
 
+	// grid meter values are kept until due
+	if !site.gridPoll.due() {
+		return nil
+	}
+
 	err := retryMeter("grid", site.gridMeter, &site.gridPower)
 
 	// currents
@@ -401,13 +730,17 @@ func (site *Site) updateMeters() error {
 		}
 	}
 
+	if err == nil {
+		site.gridPoll.done()
+	}
+
 	return err
 }
 
 // sitePower returns the net power exported by the site minus a residual margin.
 // negative values mean grid: export, battery: charging
-func (site *Site) sitePower(totalChargePower float64) (float64, error) {
-	if err := site.updateMeters(); err != nil {
+func (site *Site) sitePower(ctx context.Context, totalChargePower float64) (float64, error) {
+	if err := site.updateMeters(ctx); err != nil {
 		return 0, err
 	}
 
@@ -446,8 +779,10 @@ func (site *Site) sitePower(totalChargePower float64) (float64, error) {
 		site.Lock()
 		defer site.Unlock()
 
-		// if battery is charging below prioritySoC give it priority
-		if socs < site.PrioritySoC && batteryPower < 0 {
+		site.batterySoC = socs
+
+		// if battery is charging with priority ignore its charge power as surplus
+		if site.batteryPrioritized(socs) && batteryPower < 0 {
 			site.log.DEBUG.Printf("giving priority to battery charging at soc: %.0f%%", socs)
 			batteryPower = 0
 		}
@@ -463,9 +798,20 @@ func (site *Site) sitePower(totalChargePower float64) (float64, error) {
 	return sitePower, nil
 }
 
+// cycleContext returns the context cancelling device requests that exceed the control cycle
+func (site *Site) cycleContext() (context.Context, context.CancelFunc) {
+	if site.cycleTimeout > 0 {
+		return context.WithTimeout(context.Background(), site.cycleTimeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
 func (site *Site) update(lp Updater) {
 	site.log.DEBUG.Println("----")
 
+	ctx, cancel := site.cycleContext()
+	defer cancel()
+
 	var cheap bool
 	var err error
 	if site.tariffs.Grid != nil {
@@ -478,38 +824,78 @@ func (site *Site) update(lp Updater) {
 	// update all loadpoint's charge power
 	var totalChargePower float64
 	for _, lp := range site.loadpoints {
-		lp.UpdateChargePower()
+		lp.UpdateChargePower(ctx)
 		totalChargePower += lp.GetChargePower()
 	}
 
-	if sitePower, err := site.sitePower(totalChargePower); err == nil {
-		lp.Update(sitePower, cheap, site.batteryBuffered)
+	site.updateFrequency()
+	site.updateIsland()
+	site.updateFrost()
+	site.updateStorm()
+	site.updateGeofences()
+
+	if site.automation != nil {
+		site.automation.update(site.now(), site.loadpoints)
+	}
+
+	for _, r := range site.rotations {
+		r.update(site.clock.Now())
+	}
+
+	site.updateCircuits(lp)
+
+	if sitePower, err := site.sitePower(ctx, totalChargePower); err == nil {
+		// diverted power is available to vehicles first
+		lp.Update(ctx, sitePower-site.divertedPower(), cheap, site.batteryBuffered)
+		site.updateDiversion()
 
 		// ignore negative pvPower values as that means it is not an energy source but consumption
 		homePower := site.gridPower + math.Max(0, site.pvPower) + site.batteryPower - totalChargePower
 		homePower = math.Max(homePower, 0)
 		site.publish("homePower", homePower)
 
+		site.updateProfiles(math.Max(0, site.pvPower), homePower)
+		site.updateGenerator(math.Max(0, site.pvPower), homePower, totalChargePower)
+
 		site.Health.Update()
 	}
 
+	site.updateBilling()
+	site.updateTimeline()
+
 	// update savings and aggregate telemetry
 	// TODO: use energy instead of current power for better results
 	deltaCharged, deltaSelf := site.savings.Update(site, site.gridPower, site.pvPower, site.batteryPower, totalChargePower)
-	if telemetry.Enabled() && totalChargePower > standbyPower {
-		go telemetry.UpdateChargeProgress(site.log, totalChargePower, deltaCharged, deltaSelf)
+	site.updateSessionCost()
+	if totalChargePower > standbyPower {
+		data := telemetry.RecordChargeProgress(totalChargePower, deltaCharged, deltaSelf)
+		if telemetry.Enabled() {
+			go telemetry.UpdateChargeProgress(site.log, data)
+		}
+	}
+}
+
+// updateSessionCost accumulates the loadpoints' session cost using the site's self-produced energy share
+func (site *Site) updateSessionCost() {
+	share := site.savings.shareOfSelfProducedEnergy(site.gridPower, site.pvPower, site.batteryPower)
+	gridPrice, feedinPrice := site.savings.prices()
+
+	for _, lp := range site.loadpoints {
+		lp.updateSessionCost(share, gridPrice, feedinPrice)
 	}
 }
 
 // prepare publishes initial values
 func (site *Site) prepare() {
 	site.publish("siteTitle", site.Title)
+	site.publish("timezone", site.Location().String())
 
 	site.publish("gridConfigured", site.gridMeter != nil)
 	site.publish("pvConfigured", len(site.pvMeters) > 0)
 	site.publish("batteryConfigured", len(site.batteryMeters) > 0)
 	site.publish("bufferSoC", site.BufferSoC)
 	site.publish("prioritySoC", site.PrioritySoC)
+	site.publish("batteryPriority", site.BatteryPriority.Mode)
 	site.publish("residualPower", site.ResidualPower)
 
 	site.publish("currency", site.tariffs.Currency.String())
@@ -521,7 +907,9 @@ func (site *Site) prepare() {
 // Prepare attaches communication channels to site and loadpoints
 func (site *Site) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Event) {
 	site.uiChan = uiChan
+	site.pushChan = pushChan
 	site.lpUpdateChan = make(chan *LoadPoint, 1) // 1 capacity to avoid deadlock
+	site.closeC = make(chan struct{})
 
 	site.prepare()
 
@@ -539,6 +927,8 @@ func (site *Site) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Event)
 				case ev := <-lpPushChan:
 					ev.LoadPoint = &id
 					pushChan <- ev
+				case <-site.closeC:
+					return
 				}
 			}
 		}(id)
@@ -547,11 +937,22 @@ func (site *Site) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Event)
 	}
 }
 
-// loopLoadpoints keeps iterating across loadpoints sending the next to the given channel
-func (site *Site) loopLoadpoints(next chan<- Updater) {
+// Close stops the loadpoints' message pipes, e.g. when the site has been replaced on reload
+func (site *Site) Close() {
+	if site.closeC != nil {
+		close(site.closeC)
+	}
+}
+
+// loopLoadpoints keeps iterating across loadpoints sending the next to the given channel until stopped
+func (site *Site) loopLoadpoints(stopC <-chan struct{}, next chan<- Updater) {
 	for {
 		for _, lp := range site.loadpoints {
-			next <- lp
+			select {
+			case next <- lp:
+			case <-stopC:
+				return
+			}
 		}
 	}
 }
@@ -560,11 +961,17 @@ func (site *Site) loopLoadpoints(next chan<- Updater) {
 // updating measurements and executing control logic.
 func (site *Site) Run(stopC chan struct{}, interval time.Duration) {
 	site.Health = NewHealth(time.Minute + interval)
+	site.cycleTimeout = interval
+	site.Health.clock = site.clock
 
 	loadpointChan := make(chan Updater)
-	go site.loopLoadpoints(loadpointChan)
+	go site.loopLoadpoints(stopC, loadpointChan)
+
+	if site.automation != nil {
+		go site.automation.run(stopC)
+	}
 
-	ticker := time.NewTicker(interval)
+	ticker := site.clock.Ticker(interval)
 	site.update(<-loadpointChan) // start immediately
 
 	for {
@@ -1,7 +1,10 @@
 package site
 
 import (
+	"time"
+
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/forecast"
 	"github.com/evcc-io/evcc/core/loadpoint"
 )
 
@@ -18,6 +21,18 @@ type API interface {
 	SetBufferSoC(float64) error
 	GetPrioritySoC() float64
 	SetPrioritySoC(float64) error
+	GetBatteryPriority() BatteryPriority
+	SetBatteryPriority(BatteryPriority) error
+	GetBatteryForecast(time.Duration) []forecast.Slot
+
+	//
+	// circuits
+	//
+
+	// GetCircuits returns the circuits' limits and assigned load
+	GetCircuits() []Circuit
+	// SetCircuitLimits changes the limits of the named circuit
+	SetCircuitLimits(string, CircuitLimits) error
 
 	//
 	// power and energy
@@ -26,10 +41,34 @@ type API interface {
 	GetResidualPower() float64
 	SetResidualPower(float64) error
 
+	//
+	// forecasts
+	//
+
+	// GetGridRates returns the grid prices for the given horizon
+	GetGridRates(time.Duration) []api.Rate
+	// ImportGridRates imports a custom grid price schedule
+	ImportGridRates([]byte) error
+	// GetSolarForecast returns the expected pv power for the given horizon
+	GetSolarForecast(time.Duration) []api.ForecastSlot
+	// GetReferencePrice returns the reference price per kWh for savings comparison
+	GetReferencePrice() float64
+	// Location returns the timezone for planning, tariffs and statistics
+	Location() *time.Location
+
 	//
 	// vehicles
 	//
 
 	// GetVehicles is the list of vehicles
 	GetVehicles() []api.Vehicle
+
+	//
+	// plans
+	//
+
+	// GetPlans returns the names of the plan templates
+	GetPlans() []string
+	// ApplyPlan sets the target charge of the loadpoint, or of all loadpoints attached to the plan if nil
+	ApplyPlan(string, loadpoint.API) (time.Time, error)
 }
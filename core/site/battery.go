@@ -0,0 +1,33 @@
+package site
+
+import "fmt"
+
+// battery priority modes
+const (
+	BatteryPriorityBattery     = "battery"     // battery charging before vehicle charging
+	BatteryPriorityVehicle     = "vehicle"     // vehicle charging before battery charging
+	BatteryPriorityInterleaved = "interleaved" // battery up to battery soc, then vehicles up to vehicle soc, then battery
+)
+
+// BatteryPriority defines the order of home battery and vehicle charging from pv surplus.
+// Without mode the site's priority soc applies.
+type BatteryPriority struct {
+	Mode       string  `mapstructure:"mode" json:"mode"`
+	BatterySoC float64 `mapstructure:"batterySoC" json:"batterySoC,omitempty"` // interleaved: battery first up to this soc
+	VehicleSoC float64 `mapstructure:"vehicleSoC" json:"vehicleSoC,omitempty"` // interleaved: vehicles before battery up to this soc, 100% if empty
+}
+
+// Validate checks mode and soc thresholds
+func (p BatteryPriority) Validate() error {
+	switch p.Mode {
+	case "", BatteryPriorityBattery, BatteryPriorityVehicle, BatteryPriorityInterleaved:
+	default:
+		return fmt.Errorf("invalid battery priority mode: %s", p.Mode)
+	}
+
+	if p.BatterySoC < 0 || p.BatterySoC > 100 || p.VehicleSoC < 0 || p.VehicleSoC > 100 {
+		return fmt.Errorf("invalid battery priority soc: %.0f%%/%.0f%%", p.BatterySoC, p.VehicleSoC)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package site
+
+import "fmt"
+
+// circuit strategies
+const (
+	CircuitProportional = "proportional" // headroom shared in proportion to demand
+	CircuitPriority     = "priority"     // headroom assigned by loadpoint priority
+)
+
+// CircuitLimits are the runtime adjustable limits of a circuit. Zero limits are not enforced.
+type CircuitLimits struct {
+	MaxCurrent float64 `mapstructure:"maxCurrent" json:"maxCurrent,omitempty"` // per phase current limit
+	MaxPower   float64 `mapstructure:"maxPower" json:"maxPower,omitempty"`     // power limit
+	Strategy   string  `mapstructure:"strategy" json:"strategy,omitempty"`     // proportional if empty
+}
+
+// Validate checks limits and strategy
+func (l CircuitLimits) Validate() error {
+	switch l.Strategy {
+	case "", CircuitProportional, CircuitPriority:
+	default:
+		return fmt.Errorf("invalid circuit strategy: %s", l.Strategy)
+	}
+
+	if l.MaxCurrent < 0 || l.MaxPower < 0 || l.MaxCurrent == 0 && l.MaxPower == 0 {
+		return fmt.Errorf("invalid circuit limits: %.3gA/%.0fW", l.MaxCurrent, l.MaxPower)
+	}
+
+	return nil
+}
+
+// Clamp limits the runtime limits to the configured limits. Unlimited values take the configured limit.
+func (l CircuitLimits) Clamp(config CircuitLimits) CircuitLimits {
+	if config.MaxCurrent > 0 && (l.MaxCurrent == 0 || l.MaxCurrent > config.MaxCurrent) {
+		l.MaxCurrent = config.MaxCurrent
+	}
+	if config.MaxPower > 0 && (l.MaxPower == 0 || l.MaxPower > config.MaxPower) {
+		l.MaxPower = config.MaxPower
+	}
+	return l
+}
+
+// Circuit is the configuration and state of a circuit
+type Circuit struct {
+	Name   string `json:"name"`
+	Parent string `json:"parent,omitempty"`
+	CircuitLimits
+	Circuits   []string `json:"circuits,omitempty"`   // sub-circuits
+	Loadpoints []string `json:"loadpoints,omitempty"` // loadpoint titles
+	Current    float64  `json:"current"`              // per phase current assigned to loadpoints
+	Power      float64  `json:"power"`                // power assigned to loadpoints
+	Headroom   float64  `json:"headroom"`             // per phase current available after other loads
+}
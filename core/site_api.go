@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"time"
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/site"
@@ -71,9 +72,76 @@ func (site *Site) SetResidualPower(power float64) error {
 	return nil
 }
 
+// GetReferencePrice returns the reference price per kWh for savings comparison, the default grid price if not configured
+func (site *Site) GetReferencePrice() float64 {
+	if site.ReferencePrice == 0 {
+		return DefaultGridPrice
+	}
+	return site.ReferencePrice
+}
+
 // GetVehicles is the list of vehicles
 func (site *Site) GetVehicles() []api.Vehicle {
 	site.Lock()
 	defer site.Unlock()
 	return site.coordinator.GetVehicles()
 }
+
+// GetGridRates returns the grid prices for the given horizon starting with the current price.
+// Known tariff rates are extended by the price forecasts.
+func (site *Site) GetGridRates(horizon time.Duration) []api.Rate {
+	now := site.clock.Now()
+	end := now.Add(horizon)
+
+	var rates []api.Rate
+
+	if site.tariffs.Grid != nil {
+		tr, ok := site.tariffs.Grid.(api.TariffRates)
+		if !ok {
+			// fixed price
+			price, err := site.tariffs.Grid.CurrentPrice()
+			if err != nil {
+				return nil
+			}
+			return []api.Rate{{Start: now, End: end, Price: price}}
+		}
+
+		var err error
+		if rates, err = tr.Rates(); err != nil {
+			site.log.ERROR.Println("rates:", err)
+			return nil
+		}
+	}
+
+	// extend known rates by price forecast, the tariff's rates are not modified
+	from := now
+	if len(rates) > 0 {
+		if last := rates[len(rates)-1].End; last.After(from) {
+			from = last
+		}
+	}
+
+	var forecast []api.Rate
+	if from.Before(end) {
+		forecast = site.forecastRates(from)
+	}
+
+	res := make([]api.Rate, 0, len(rates)+len(forecast))
+	for _, r := range append(rates[:len(rates):len(rates)], forecast...) {
+		if r.End.After(now) && r.Start.Before(end) {
+			res = append(res, r)
+		}
+	}
+
+	return res
+}
+
+// ImportGridRates imports a custom grid price schedule
+func (site *Site) ImportGridRates(b []byte) error {
+	ti, ok := site.tariffs.Grid.(api.TariffImporter)
+	if !ok {
+		return errors.New("grid tariff does not support import")
+	}
+
+	return ti.Import(b)
+}
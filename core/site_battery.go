@@ -0,0 +1,77 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/site"
+)
+
+// batteryPrioritized checks if battery charging takes precedence over vehicle charging at the given battery soc (no mutex)
+func (site *Site) batteryPrioritized(soc float64) bool {
+	p := site.BatteryPriority
+
+	switch p.Mode {
+	case "":
+		return soc < site.PrioritySoC
+
+	case "battery":
+		return true
+
+	case "vehicle":
+		return false
+
+	default:
+		// battery first, then vehicles, then battery
+		if soc < p.BatterySoC {
+			return true
+		}
+
+		vehicleSoC := p.VehicleSoC
+		if vehicleSoC == 0 {
+			vehicleSoC = 100
+		}
+
+		return site.vehiclesCharged(vehicleSoC)
+	}
+}
+
+// vehiclesCharged checks if all connected vehicles with known soc have reached the given soc.
+// Vehicles of loadpoints that are switched off are ignored.
+func (site *Site) vehiclesCharged(soc float64) bool {
+	for _, lp := range site.loadpoints {
+		if lp.connected() && lp.vehicleSoc > 0 && lp.vehicleSoc < soc && lp.GetMode() != api.ModeOff {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetBatteryPriority returns the battery priority
+func (site *Site) GetBatteryPriority() site.BatteryPriority {
+	site.Lock()
+	defer site.Unlock()
+	return site.BatteryPriority
+}
+
+// SetBatteryPriority sets the battery priority
+func (site *Site) SetBatteryPriority(p site.BatteryPriority) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	site.Lock()
+	defer site.Unlock()
+
+	if len(site.batteryMeters) == 0 {
+		return errors.New("battery not configured")
+	}
+
+	site.log.DEBUG.Printf("set battery priority: %s", p.Mode)
+
+	site.BatteryPriority = p
+	site.publish("batteryPriority", p.Mode)
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatteryPrioritized(t *testing.T) {
+	lp := &LoadPoint{Mode: api.ModePV, status: api.StatusC, vehicleSoc: 60}
+	s := &Site{log: util.NewLogger("foo"), PrioritySoC: 30, loadpoints: []*LoadPoint{lp}}
+
+	// priority soc without mode
+	assert.True(t, s.batteryPrioritized(20))
+	assert.False(t, s.batteryPrioritized(40))
+
+	s.BatteryPriority = site.BatteryPriority{Mode: site.BatteryPriorityBattery}
+	assert.True(t, s.batteryPrioritized(90))
+
+	s.BatteryPriority = site.BatteryPriority{Mode: site.BatteryPriorityVehicle}
+	assert.False(t, s.batteryPrioritized(10))
+
+	// battery to 50%, then vehicle to 80%, then battery
+	s.BatteryPriority = site.BatteryPriority{Mode: site.BatteryPriorityInterleaved, BatterySoC: 50, VehicleSoC: 80}
+	assert.True(t, s.batteryPrioritized(40))
+	assert.False(t, s.batteryPrioritized(60))
+
+	lp.vehicleSoc = 80
+	assert.True(t, s.batteryPrioritized(60))
+
+	// vehicles charge to 100% without vehicle soc
+	s.BatteryPriority.VehicleSoC = 0
+	assert.False(t, s.batteryPrioritized(60))
+
+	// switched off and disconnected vehicles are ignored
+	lp.Mode = api.ModeOff
+	assert.True(t, s.batteryPrioritized(60))
+
+	lp.Mode = api.ModePV
+	lp.status = api.StatusA
+	assert.True(t, s.batteryPrioritized(60))
+}
+
+func TestSetBatteryPriority(t *testing.T) {
+	s := &Site{log: util.NewLogger("foo")}
+	assert.Error(t, s.SetBatteryPriority(site.BatteryPriority{Mode: site.BatteryPriorityVehicle}), "battery not configured")
+
+	s.batteryMeters = []api.Meter{nil}
+	assert.Error(t, s.SetBatteryPriority(site.BatteryPriority{Mode: "foo"}))
+	assert.Error(t, s.SetBatteryPriority(site.BatteryPriority{Mode: site.BatteryPriorityInterleaved, BatterySoC: 120}))
+	assert.NoError(t, s.SetBatteryPriority(site.BatteryPriority{Mode: site.BatteryPriorityVehicle}))
+	assert.Equal(t, site.BatteryPriorityVehicle, s.GetBatteryPriority().Mode)
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/db"
+	serverdb "github.com/evcc-io/evcc/server/db"
+	"gorm.io/gorm"
+)
+
+// BillingConfig defines billing periods closed with charge meter snapshots
+type BillingConfig struct {
+	Period     string `mapstructure:"period"`     // monthly or quarterly
+	StartDay   int    `mapstructure:"startDay"`   // first day of period (1-28)
+	StartMonth int    `mapstructure:"startMonth"` // first month of a quarterly period (1-12)
+}
+
+// billing tracks the current billing period
+type billing struct {
+	months, offset, day int
+	start, end          time.Time
+}
+
+// newBillingFromConfig creates billing period tracking
+func newBillingFromConfig(cc BillingConfig) (*billing, error) {
+	b := &billing{day: cc.StartDay}
+
+	switch cc.Period {
+	case "monthly":
+		b.months = 1
+	case "quarterly":
+		b.months = 3
+	default:
+		return nil, fmt.Errorf("invalid period: %s", cc.Period)
+	}
+
+	if b.day == 0 {
+		b.day = 1
+	}
+	if b.day < 1 || b.day > 28 {
+		return nil, errors.New("startDay must be in [1..28]")
+	}
+
+	if month := cc.StartMonth; month != 0 {
+		if month < 1 || month > 12 {
+			return nil, errors.New("startMonth must be in [1..12]")
+		}
+		b.offset = (month - 1) % b.months
+	}
+
+	return b, nil
+}
+
+// bounds returns the billing period containing t
+func (b *billing) bounds(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), b.day, 0, 0, 0, 0, t.Location())
+	if start.After(t) {
+		start = start.AddDate(0, -1, 0)
+	}
+
+	for (int(start.Month())-1-b.offset+12)%b.months != 0 {
+		start = start.AddDate(0, -1, 0)
+	}
+
+	return start, start.AddDate(0, b.months, 0)
+}
+
+// meterReadings returns the loadpoints' charge meter readings in kWh
+func (site *Site) meterReadings() db.Readings {
+	res := make(db.Readings)
+
+	for _, lp := range site.loadpoints {
+		if m, ok := lp.chargeMeter.(api.MeterEnergy); ok {
+			if f, err := m.TotalEnergy(); err == nil {
+				res[lp.Title] = f
+			} else {
+				site.log.ERROR.Printf("billing: %s: %v", lp.Title, err)
+			}
+		}
+	}
+
+	return res
+}
+
+// billingTolerance is the delay after the period end up to which current meter readings are used for closing the period
+const billingTolerance = 5 * time.Minute
+
+// billingReadings returns the meter readings at the period end. Once the period end has passed
+// for longer than the tolerance, e.g. after downtime, readings are derived from the sessions.
+func (site *Site) billingReadings(txn *gorm.DB, end time.Time) db.Readings {
+	if site.clock.Since(end) <= billingTolerance {
+		return site.meterReadings()
+	}
+
+	res, err := db.ReadingsAt(txn, end)
+	if err != nil {
+		site.log.ERROR.Printf("billing: %v", err)
+	}
+
+	return res
+}
+
+// closeBillingPeriod closes the period, snapshotting meter readings at its end and freezing its sessions
+func (site *Site) closeBillingPeriod(txn *gorm.DB, start, end time.Time) {
+	p, err := db.ClosePeriod(txn, start, end, site.billingReadings(txn, end))
+	if err != nil {
+		site.log.ERROR.Printf("billing: %v", err)
+		return
+	}
+
+	site.log.INFO.Printf("billing: closed period %s - %s", p.Start.Format("2006-01-02"), p.End.Format("2006-01-02"))
+}
+
+// updateBilling closes the current billing period once it has ended
+func (site *Site) updateBilling() {
+	b := site.billing
+	if b == nil || serverdb.Instance == nil {
+		return
+	}
+
+	now := site.now()
+
+	// initialize current period, closing the gap to the last period
+	if b.end.IsZero() {
+		b.start, b.end = b.bounds(now)
+
+		if last, err := db.LastPeriod(serverdb.Instance); err == nil && last.End.Before(b.start) {
+			site.closeBillingPeriod(serverdb.Instance, last.End, b.start)
+		}
+
+		site.publish("billingPeriodEnd", b.end)
+	}
+
+	if now.Before(b.end) {
+		return
+	}
+
+	site.closeBillingPeriod(serverdb.Instance, b.start, b.end)
+	b.start, b.end = b.bounds(now)
+
+	site.publish("billingPeriodEnd", b.end)
+}
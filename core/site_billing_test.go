@@ -0,0 +1,43 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBillingBounds(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tc := []struct {
+		cc         BillingConfig
+		t          time.Time
+		start, end time.Time
+	}{
+		{BillingConfig{Period: "monthly"}, date(2022, 3, 15), date(2022, 3, 1), date(2022, 4, 1)},
+		{BillingConfig{Period: "monthly", StartDay: 15}, date(2022, 3, 14), date(2022, 2, 15), date(2022, 3, 15)},
+		{BillingConfig{Period: "monthly", StartDay: 15}, date(2022, 1, 1), date(2021, 12, 15), date(2022, 1, 15)},
+		{BillingConfig{Period: "quarterly"}, date(2022, 6, 30), date(2022, 4, 1), date(2022, 7, 1)},
+		{BillingConfig{Period: "quarterly", StartMonth: 2, StartDay: 10}, date(2022, 2, 9), date(2021, 11, 10), date(2022, 2, 10)},
+		{BillingConfig{Period: "quarterly", StartMonth: 12}, date(2022, 3, 1), date(2022, 3, 1), date(2022, 6, 1)},
+	}
+
+	for _, tc := range tc {
+		b, err := newBillingFromConfig(tc.cc)
+		require.NoError(t, err)
+
+		start, end := b.bounds(tc.t)
+		assert.Equal(t, tc.start, start, tc)
+		assert.Equal(t, tc.end, end, tc)
+	}
+
+	_, err := newBillingFromConfig(BillingConfig{Period: "yearly"})
+	assert.Error(t, err)
+
+	_, err = newBillingFromConfig(BillingConfig{Period: "monthly", StartDay: 31})
+	assert.Error(t, err)
+}
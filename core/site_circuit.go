@@ -0,0 +1,154 @@
+package core
+
+import (
+	"errors"
+	"math"
+
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"golang.org/x/exp/slices"
+)
+
+// updateCircuits distributes the circuits' headroom among their loadpoints.
+// The updated loadpoint is further limited to the headroom left by the other loadpoints until their next update.
+func (site *Site) updateCircuits(updated Updater) {
+	if len(site.circuits) == 0 {
+		return
+	}
+
+	demands := make(map[*LoadPoint]circuitDemand)
+	for _, lp := range site.loadpoints {
+		if lp.circuit != nil {
+			demands[lp] = lp.circuitDemand()
+		}
+	}
+
+	measured := make(map[*circuit]circuitMeasurement)
+	for _, c := range site.circuits {
+		if c.meter == nil {
+			continue
+		}
+
+		current, power, err := c.measure(demands)
+		if err != nil {
+			// keep the last known load
+			c.log.ERROR.Printf("circuit %s: %v", c.name, err)
+			continue
+		}
+
+		measured[c] = circuitMeasurement{current, power}
+	}
+
+	site.publish("circuits", site.distributeCircuits(updated, demands, measured))
+}
+
+// circuitMeasurement is a circuit's load not caused by its loadpoints
+type circuitMeasurement struct {
+	current, power float64
+}
+
+// distributeCircuits applies the measurements and assigns the loadpoints' circuit limits
+func (site *Site) distributeCircuits(updated Updater, demands map[*LoadPoint]circuitDemand, measured map[*circuit]circuitMeasurement) []site.Circuit {
+	site.Lock()
+	defer site.Unlock()
+
+	for c, m := range measured {
+		c.otherCurrent, c.otherPower = m.current, m.power
+	}
+
+	res := make(map[*LoadPoint]float64)
+	for _, c := range site.circuits {
+		if c.parent == nil {
+			c.distribute(math.Inf(1), math.Inf(1), demands, res)
+		}
+	}
+
+	for lp, limit := range res {
+		lp.circuitLimit = limit
+	}
+
+	if lp, ok := updated.(*LoadPoint); ok && lp.circuit != nil {
+		if headroom := circuitHeadroom(lp, demands); headroom < lp.circuitLimit {
+			lp.log.DEBUG.Printf("circuit %s: waiting for other loadpoints to reduce current, headroom %.3gA", lp.circuit.name, headroom)
+			lp.circuitLimit = headroom
+		}
+	}
+
+	return circuitStates(site.circuits)
+}
+
+// circuitStates returns the circuits' configuration and state
+func circuitStates(circuits []*circuit) []site.Circuit {
+	res := make([]site.Circuit, 0, len(circuits))
+
+	for _, c := range circuits {
+		s := site.Circuit{
+			Name:          c.name,
+			CircuitLimits: c.limits,
+			Current:       c.current,
+			Power:         c.power,
+			Headroom:      c.headCurrent,
+		}
+
+		if math.IsInf(s.Headroom, 1) {
+			s.Headroom = 0
+		}
+
+		if c.parent != nil {
+			s.Parent = c.parent.name
+		}
+
+		for _, child := range c.children {
+			s.Circuits = append(s.Circuits, child.name)
+		}
+
+		for _, lp := range c.loadpoints {
+			s.Loadpoints = append(s.Loadpoints, lp.Title)
+		}
+
+		res = append(res, s)
+	}
+
+	return res
+}
+
+// GetCircuits returns the circuits' limits and assigned load
+func (site *Site) GetCircuits() []site.Circuit {
+	site.Lock()
+	defer site.Unlock()
+	return circuitStates(site.circuits)
+}
+
+// SetCircuitLimits changes the limits of the named circuit within its configured limits
+func (site *Site) SetCircuitLimits(name string, limits site.CircuitLimits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+
+	site.Lock()
+	defer site.Unlock()
+
+	idx := slices.IndexFunc(site.circuits, func(c *circuit) bool {
+		return c.name == name
+	})
+	if idx < 0 {
+		return errors.New("circuit not found")
+	}
+
+	c := site.circuits[idx]
+
+	if clamped := limits.Clamp(c.config); clamped != limits {
+		site.log.WARN.Printf("circuit %s: limits exceed configuration, using %.3gA/%.0fW", name, clamped.MaxCurrent, clamped.MaxPower)
+		limits = clamped
+	}
+
+	site.log.DEBUG.Printf("set circuit %s limits: %.3gA/%.0fW", name, limits.MaxCurrent, limits.MaxPower)
+
+	if err := settings.SetJson(circuitKey(name), circuitSettings{Config: c.config, Limits: limits}); err != nil {
+		return err
+	}
+
+	c.limits = limits
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/forecast"
+)
+
+const maxForecastHorizon = 48 * time.Hour
+
+// updateProfiles learns the pv and home power profiles from current measurements
+func (site *Site) updateProfiles(pvPower, homePower float64) {
+	// test guard
+	if site.pvProfile == nil || site.homeProfile == nil {
+		return
+	}
+
+	now := site.now()
+
+	if site.pvProfile.Add(now, pvPower) {
+		site.pvProfile.Save()
+	}
+	if site.homeProfile.Add(now, homePower) {
+		site.homeProfile.Save()
+	}
+
+	if site.pvForecast != nil && site.pvForecast.Add(now, pvPower) {
+		site.pvForecast.Save()
+		site.publish("solarForecastAccuracy", site.pvForecast.Stats())
+	}
+
+	if site.homeForecast != nil && site.homeForecast.Add(now, homePower) {
+		site.homeForecast.Save()
+		site.publish("consumptionForecastAccuracy", site.homeForecast.Stats())
+	}
+}
+
+// expectedPV returns the expected pv power at the given time from the blended solar forecast,
+// falling back to the learned pv profile
+func (site *Site) expectedPV(ts time.Time) (float64, bool) {
+	if site.pvForecast != nil {
+		if pv, ok := site.pvForecast.Power(ts); ok {
+			return pv, true
+		}
+	}
+
+	return site.pvProfile.Expected(ts)
+}
+
+// expectedHome returns the expected home power at the given time from the consumption forecast,
+// falling back to the learned home profile
+func (site *Site) expectedHome(ts time.Time) (float64, bool) {
+	if site.homeForecast != nil {
+		if home, ok := site.homeForecast.Power(ts); ok {
+			return home, true
+		}
+	}
+
+	return site.homeProfile.Expected(ts)
+}
+
+// predictSurplus returns the expected pv surplus at the given time and if both pv and home power are known for the time slot
+func (site *Site) predictSurplus(ts time.Time) (float64, bool) {
+	pv, pvOk := site.expectedPV(ts)
+	home, homeOk := site.expectedHome(ts)
+	return pv - home, pvOk && homeOk
+}
+
+// plannedChargePower returns the expected average charge power of all loadpoints
+// with active charge plans for the hour starting at ts
+func (site *Site) plannedChargePower(ts time.Time) float64 {
+	var res float64
+
+	for _, lp := range site.loadpoints {
+		finishAt, energy := lp.plannedCharge()
+		if finishAt.IsZero() || energy <= 0 {
+			continue
+		}
+
+		power := lp.GetMaxPower()
+		if power <= 0 {
+			continue
+		}
+
+		// assume charging at max power right before target time
+		start := finishAt.Add(-time.Duration(float64(time.Hour) * energy / power))
+
+		// overlap of charging window with slot
+		from, to := start, finishAt
+		if ts.After(from) {
+			from = ts
+		}
+		if end := ts.Add(time.Hour); end.Before(to) {
+			to = end
+		}
+
+		if overlap := to.Sub(from); overlap > 0 {
+			res += power * overlap.Hours()
+		}
+	}
+
+	return res
+}
+
+// GetBatteryForecast returns the expected battery soc for the given horizon based on solar forecast
+// or learned pv profile, consumption forecast or learned home power profile and planned vehicle charging
+func (site *Site) GetBatteryForecast(horizon time.Duration) []forecast.Slot {
+	site.Lock()
+	soc, capacity := site.batterySoC, site.BatteryCapacity
+	site.Unlock()
+
+	if len(site.batteryMeters) == 0 || site.pvProfile == nil || site.homeProfile == nil {
+		return nil
+	}
+
+	if horizon > maxForecastHorizon {
+		horizon = maxForecastHorizon
+	}
+
+	net := func(ts time.Time) float64 {
+		pv, _ := site.expectedPV(ts)
+
+		home, ok := site.expectedHome(ts)
+		if !ok {
+			home = site.homeProfile.Power(ts)
+		}
+
+		return pv - home - site.plannedChargePower(ts)
+	}
+
+	return forecast.Battery(site.clock.Now(), soc, capacity, horizon, net)
+}
+
+// GetSolarForecast returns the expected hourly pv power for the given horizon
+func (site *Site) GetSolarForecast(horizon time.Duration) []api.ForecastSlot {
+	if site.pvProfile == nil {
+		return nil
+	}
+
+	if horizon > maxForecastHorizon {
+		horizon = maxForecastHorizon
+	}
+
+	now := site.now()
+
+	var res []api.ForecastSlot
+	for ts := now.Truncate(time.Hour); ts.Before(now.Add(horizon)); ts = ts.Add(time.Hour) {
+		pv, _ := site.expectedPV(ts)
+		res = append(res, api.ForecastSlot{Start: ts, End: ts.Add(time.Hour), Power: pv})
+	}
+
+	return res
+}
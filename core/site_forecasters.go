@@ -0,0 +1,108 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/forecast"
+	"github.com/evcc-io/evcc/forecaster"
+)
+
+// ForecastersConfig defines pluggable forecasts used for planning in addition to the built-in sources
+type ForecastersConfig struct {
+	Production  []forecaster.Config `mapstructure:"production"`  // pv power, blended with the solar forecasts
+	Consumption []forecaster.Config `mapstructure:"consumption"` // home power, preferred over the learned home profile
+	Price       []forecaster.Config `mapstructure:"price"`       // grid price, extends the tariff's known rates
+}
+
+// powerForecast adapts a power forecaster for blending
+type powerForecast struct {
+	forecaster api.Forecaster
+}
+
+func (f powerForecast) Forecast() ([]api.ForecastSlot, error) {
+	values, err := f.forecaster.Forecast()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]api.ForecastSlot, 0, len(values))
+	for _, v := range values {
+		res = append(res, api.ForecastSlot{Start: v.Start, End: v.End, Power: v.Value})
+	}
+
+	return res, nil
+}
+
+// newPowerForecasts creates the named power forecasters of the given kind
+func newPowerForecasts(kind string, configs []forecaster.Config) ([]string, []api.SolarForecast, error) {
+	var names []string
+	var forecasts []api.SolarForecast
+
+	for i, cc := range configs {
+		f, err := forecaster.NewFromConfig(cc.Type, cc.Other)
+		if err != nil {
+			return nil, nil, fmt.Errorf("forecasters.%s[%d]: %w", kind, i, err)
+		}
+
+		names = append(names, fmt.Sprintf("%s:%d:%s", kind, i, cc.Type))
+		forecasts = append(forecasts, powerForecast{f})
+	}
+
+	return names, forecasts, nil
+}
+
+// prepareForecasters creates the consumption and price forecasters
+func (site *Site) prepareForecasters() error {
+	names, forecasts, err := newPowerForecasts("consumption", site.Forecasters.Consumption)
+	if err != nil {
+		return err
+	}
+
+	if len(forecasts) > 0 {
+		site.homeForecast = forecast.NewBlender("forecast.consumption", names, forecasts)
+	}
+
+	for i, cc := range site.Forecasters.Price {
+		f, err := forecaster.NewFromConfig(cc.Type, cc.Other)
+		if err != nil {
+			return fmt.Errorf("forecasters.price[%d]: %w", i, err)
+		}
+
+		site.priceForecasts = append(site.priceForecasts, f)
+	}
+
+	return nil
+}
+
+// forecastRates returns the forecasted grid rates starting at the given time from the first price forecast covering it
+func (site *Site) forecastRates(from time.Time) []api.Rate {
+	for _, f := range site.priceForecasts {
+		values, err := f.Forecast()
+		if err != nil {
+			site.log.ERROR.Println("price forecast:", err)
+			continue
+		}
+
+		var res []api.Rate
+		for _, v := range values {
+			if !v.End.After(from) {
+				continue
+			}
+
+			start := v.Start
+			if start.Before(from) {
+				start = from
+			}
+
+			res = append(res, api.Rate{Start: start, End: v.End, Price: v.Value})
+		}
+
+		if len(res) > 0 {
+			return res
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type forecasterFunc func() ([]api.ForecastValue, error)
+
+func (f forecasterFunc) Forecast() ([]api.ForecastValue, error) {
+	return f()
+}
+
+type ratesTariff []api.Rate
+
+func (t ratesTariff) IsCheap() (bool, error) {
+	return false, nil
+}
+
+func (t ratesTariff) CurrentPrice() (float64, error) {
+	return t[0].Price, nil
+}
+
+func (t ratesTariff) Rates() ([]api.Rate, error) {
+	return t, nil
+}
+
+func TestForecastGridRates(t *testing.T) {
+	clck := clock.NewMock()
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	clck.Set(now)
+
+	hour := func(h int) time.Time { return now.Add(time.Duration(h) * time.Hour) }
+
+	price := forecasterFunc(func() ([]api.ForecastValue, error) {
+		var res []api.ForecastValue
+		for h := 0; h < 4; h++ {
+			res = append(res, api.ForecastValue{Start: hour(h), End: hour(h + 1), Value: 0.1})
+		}
+		return res, nil
+	})
+
+	site := &Site{log: util.NewLogger("foo"), clock: clck, priceForecasts: []api.Forecaster{price}}
+
+	// forecast only
+	assert.Len(t, site.GetGridRates(3*time.Hour), 3)
+
+	// forecast extends known rates
+	site.tariffs = tariff.Tariffs{Grid: ratesTariff{
+		{Start: hour(0), End: hour(1), Price: 0.3},
+		{Start: hour(1), End: hour(2), Price: 0.2},
+	}}
+
+	assert.Equal(t, []api.Rate{
+		{Start: hour(0), End: hour(1), Price: 0.3},
+		{Start: hour(1), End: hour(2), Price: 0.2},
+		{Start: hour(2), End: hour(3), Price: 0.1},
+		{Start: hour(3), End: hour(4), Price: 0.1},
+	}, site.GetGridRates(8*time.Hour))
+
+	// fixed price is not extended
+	site.tariffs = tariff.Tariffs{Grid: &tariff.Fixed{Price: 0.25}}
+	assert.Len(t, site.GetGridRates(8*time.Hour), 1)
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"math"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// FrequencyConfig defines charging load shedding on grid frequency deviation, e.g. in island or backup power scenarios
+type FrequencyConfig struct {
+	Threshold float64       `mapstructure:"threshold"` // shed charging load below this frequency in Hz
+	Restore   time.Duration `mapstructure:"restore"`   // duration for gradually restoring charging load after recovery
+}
+
+// loadShedder limits the charging load
+type loadShedder interface {
+	shedLimit() float64
+}
+
+// frequencyMeter returns the first site meter able to provide grid frequency
+func (site *Site) frequencyMeter() api.MeterFrequency {
+	meters := append([]api.Meter{site.gridMeter}, site.batteryMeters...)
+	meters = append(meters, site.pvMeters...)
+
+	for _, m := range meters {
+		if fm, ok := m.(api.MeterFrequency); ok {
+			return fm
+		}
+	}
+
+	return nil
+}
+
+// updateFrequency reads the grid frequency and updates load shedding
+func (site *Site) updateFrequency() {
+	if site.Frequency.Threshold == 0 || site.frequency == nil {
+		return
+	}
+
+	frequency, err := site.frequency.Frequency()
+	if err != nil {
+		site.log.ERROR.Printf("grid frequency: %v", err)
+		return
+	}
+
+	site.publish("gridFrequency", frequency)
+	site.publish("shedLimit", site.updateShedding(frequency, site.clock.Now()))
+}
+
+// updateShedding calculates the share of charging load allowed at given frequency
+func (site *Site) updateShedding(frequency float64, now time.Time) float64 {
+	site.Lock()
+	defer site.Unlock()
+
+	switch {
+	case frequency < site.Frequency.Threshold:
+		if site.shed > 0 || !site.recovered.IsZero() {
+			site.log.WARN.Printf("grid frequency %.2fHz < %.2fHz: shedding charging load", frequency, site.Frequency.Threshold)
+		}
+
+		site.shed = 0
+		site.recovered = time.Time{}
+
+	case site.shed < 1:
+		if site.recovered.IsZero() {
+			site.log.INFO.Printf("grid frequency %.2fHz recovered: restoring charging load", frequency)
+			site.recovered = now
+		}
+
+		site.shed = 1
+		if site.Frequency.Restore > 0 {
+			site.shed = math.Min(1, float64(now.Sub(site.recovered))/float64(site.Frequency.Restore))
+		}
+	}
+
+	return site.shed
+}
+
+// shedLimit returns the share of maximum charging load currently allowed
+func (site *Site) shedLimit() float64 {
+	site.Lock()
+	defer site.Unlock()
+	return site.shed
+}
+
+// shedCurrent limits the charge current according to load shedding
+func (lp *LoadPoint) shedCurrent(chargeCurrent float64) (float64, bool) {
+	if lp.shedder == nil {
+		return chargeCurrent, false
+	}
+
+	limit := lp.shedder.shedLimit() * lp.GetMaxCurrent()
+	if chargeCurrent <= limit {
+		return chargeCurrent, false
+	}
+
+	if limit < lp.GetMinCurrent() {
+		limit = 0
+	}
+
+	lp.log.DEBUG.Printf("load shedding: limit charge current to %.3gA", limit)
+
+	return limit, true
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateShedding(t *testing.T) {
+	site := &Site{
+		log:  util.NewLogger("foo"),
+		shed: 1,
+		Frequency: FrequencyConfig{
+			Threshold: 49.8,
+			Restore:   10 * time.Minute,
+		},
+	}
+
+	now := time.Now()
+
+	assert.Equal(t, 1.0, site.updateShedding(50, now))
+	assert.Equal(t, 0.0, site.updateShedding(49.7, now))
+
+	// gradual restore
+	assert.Equal(t, 0.0, site.updateShedding(50, now))
+	assert.Equal(t, 0.5, site.updateShedding(50, now.Add(5*time.Minute)))
+
+	// deviation during restore
+	assert.Equal(t, 0.0, site.updateShedding(49.7, now.Add(6*time.Minute)))
+	assert.Equal(t, 0.0, site.updateShedding(50, now.Add(7*time.Minute)))
+	assert.Equal(t, 1.0, site.updateShedding(50, now.Add(20*time.Minute)))
+}
+
+func TestShedForce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := mock.NewMockCharger(ctrl)
+
+	clck := clock.NewMock()
+
+	site := &Site{shed: 0.5}
+
+	lp := &LoadPoint{
+		log:           util.NewLogger("foo"),
+		bus:           evbus.New(),
+		clock:         clck,
+		charger:       charger,
+		MinCurrent:    minA,
+		MaxCurrent:    maxA,
+		GuardDuration: time.Hour,
+		chargeCurrent: maxA,
+		enabled:       true,
+		guardUpdated:  clck.Now(),
+		shedder:       site,
+		wakeUpTimer:   NewTimer(),
+	}
+
+	// reduced current within guard duration
+	charger.EXPECT().MaxCurrent(int64(maxA / 2)).Return(nil)
+	assert.NoError(t, lp.setLimit(maxA, false))
+
+	// shed to zero disables despite guard duration
+	site.shed = 0
+	charger.EXPECT().Enable(false).Return(nil)
+	assert.NoError(t, lp.setLimit(maxA, false))
+	assert.False(t, lp.enabled)
+}
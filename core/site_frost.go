@@ -0,0 +1,148 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+// FrostConfig defines home battery protection at low temperatures, e.g. for LFP batteries in unheated garages
+type FrostConfig struct {
+	Temperature       provider.Config  `mapstructure:"temperature"`       // battery temperature source in °C
+	MinTemperature    float64          `mapstructure:"minTemperature"`    // no forced grid charging below this temperature, 0°C if not set
+	Hysteresis        float64          `mapstructure:"hysteresis"`        // resume forced charging above min temperature plus hysteresis
+	Block             *provider.Config `mapstructure:"block"`             // enabled while forced grid charging is blocked, e.g. disabling the inverter's price-based charging
+	Heater            *provider.Config `mapstructure:"heater"`            // battery heater control
+	HeaterTemperature float64          `mapstructure:"heaterTemperature"` // heater enabled below this temperature, min temperature plus hysteresis if not set
+}
+
+// frost blocks forced home battery charging while the battery is too cold and heats the battery to keep it chargeable
+type frost struct {
+	log         *util.Logger
+	temperature func() (float64, error)
+	block       func(bool) error
+	heater      func(bool) error
+	FrostConfig
+
+	cold     bool // battery below min temperature
+	failed   bool // battery temperature unknown
+	blocking bool // block control enabled
+	heating  bool // heater enabled
+}
+
+// newFrostFromConfig creates a frost protection
+func newFrostFromConfig(log *util.Logger, cc FrostConfig) (*frost, error) {
+	if cc.Hysteresis == 0 {
+		cc.Hysteresis = 2
+	}
+	if cc.HeaterTemperature == 0 {
+		cc.HeaterTemperature = cc.MinTemperature + cc.Hysteresis
+	}
+
+	temperature, err := provider.NewFloatGetterFromConfig(cc.Temperature)
+	if err != nil {
+		return nil, fmt.Errorf("frost: temperature: %w", err)
+	}
+
+	f := &frost{
+		log:         log,
+		temperature: temperature,
+		FrostConfig: cc,
+	}
+
+	if cc.Block != nil {
+		if f.block, err = provider.NewBoolSetterFromConfig("block", *cc.Block); err != nil {
+			return nil, fmt.Errorf("frost: %w", err)
+		}
+	}
+
+	if cc.Heater != nil {
+		if f.heater, err = provider.NewBoolSetterFromConfig("heater", *cc.Heater); err != nil {
+			return nil, fmt.Errorf("frost: %w", err)
+		}
+	}
+
+	return f, nil
+}
+
+// blocked returns true if forced grid charging must not run. A nil frost protection never blocks.
+func (f *frost) blocked() bool {
+	return f != nil && (f.cold || f.failed)
+}
+
+// setBlock enables or disables the forced charge block control
+func (f *frost) setBlock(enable bool) {
+	if f.block == nil || enable == f.blocking {
+		return
+	}
+
+	if err := f.block(enable); err != nil {
+		f.log.ERROR.Printf("frost: block: %v", err)
+		return
+	}
+
+	f.blocking = enable
+}
+
+// setHeater enables or disables the battery heater
+func (f *frost) setHeater(enable bool) {
+	if f.heater == nil || enable == f.heating {
+		return
+	}
+
+	if err := f.heater(enable); err != nil {
+		f.log.ERROR.Printf("frost: heater: %v", err)
+		return
+	}
+
+	f.heating = enable
+}
+
+// update reads the battery temperature and returns it. Forced charging is blocked while the temperature is unknown.
+func (f *frost) update() (float64, error) {
+	temp, err := f.temperature()
+	if err != nil {
+		f.failed = true
+		f.setBlock(true)
+		return 0, err
+	}
+	f.failed = false
+
+	switch {
+	case !f.cold && temp < f.MinTemperature:
+		f.log.WARN.Printf("frost: battery temperature %.1f°C: forced charging blocked", temp)
+		f.cold = true
+	case f.cold && temp >= f.MinTemperature+f.Hysteresis:
+		f.log.INFO.Printf("frost: battery temperature %.1f°C: forced charging resumed", temp)
+		f.cold = false
+	}
+
+	f.setBlock(f.cold)
+
+	// heat independent of blocking to keep the battery chargeable
+	switch {
+	case temp < f.HeaterTemperature:
+		f.setHeater(true)
+	case temp >= f.HeaterTemperature+f.Hysteresis:
+		f.setHeater(false)
+	}
+
+	return temp, nil
+}
+
+// updateFrost protects the home battery from forced charging at low temperatures
+func (site *Site) updateFrost() {
+	if site.frost == nil {
+		return
+	}
+
+	temp, err := site.frost.update()
+	if err != nil {
+		site.log.ERROR.Printf("frost: battery temperature: %v", err)
+	} else {
+		site.publish("batteryTemperature", temp)
+	}
+
+	site.publish("batteryFrost", site.frost.blocked())
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrost(t *testing.T) {
+	temp := 3.0
+	var err error
+	var block, heater []bool
+
+	f := &frost{
+		log:         util.NewLogger("foo"),
+		temperature: func() (float64, error) { return temp, err },
+		block: func(enable bool) error {
+			block = append(block, enable)
+			return nil
+		},
+		heater: func(enable bool) error {
+			heater = append(heater, enable)
+			return nil
+		},
+		FrostConfig: FrostConfig{MinTemperature: 5, Hysteresis: 2, HeaterTemperature: 7},
+	}
+
+	assert.False(t, (*frost)(nil).blocked())
+
+	_, _ = f.update()
+	assert.True(t, f.blocked())
+	assert.Equal(t, []bool{true}, block)
+	assert.Equal(t, []bool{true}, heater)
+
+	// hysteresis
+	temp = 6
+	_, _ = f.update()
+	assert.True(t, f.blocked())
+
+	temp = 7
+	_, _ = f.update()
+	assert.False(t, f.blocked())
+	assert.Equal(t, []bool{true, false}, block)
+
+	// heater keeps running while charging is no longer blocked
+	assert.Equal(t, []bool{true}, heater)
+
+	temp = 9
+	_, _ = f.update()
+	assert.Equal(t, []bool{true, false}, heater)
+
+	// heater starts before charging is blocked
+	temp = 6.5
+	_, _ = f.update()
+	assert.False(t, f.blocked())
+	assert.Equal(t, []bool{true, false, true}, heater)
+
+	// unknown temperature blocks charging
+	err = errors.New("sensor offline")
+	_, _ = f.update()
+	assert.True(t, f.blocked())
+	assert.Equal(t, []bool{true, false, true}, block)
+}
@@ -0,0 +1,244 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+type geofenceState string
+
+const (
+	geofenceUnknown geofenceState = ""
+	geofenceHome    geofenceState = "home"
+	geofenceNear    geofenceState = "near"
+	geofenceAway    geofenceState = "away"
+	geofenceTrip    geofenceState = "trip"
+)
+
+const (
+	geofenceSpeed       = 150 / 3.6 // m/s, maximum approach speed for backing off position polling
+	geofenceMaxInterval = time.Hour // maximum position polling interval while away
+)
+
+// geofence triggers loadpoint actions depending on the vehicle position relative to home
+type geofence struct {
+	vehicle  api.Vehicle
+	position api.VehiclePosition
+	lp       *LoadPoint
+	api.GeofenceConfig
+
+	planTime time.Time // plan time of day
+	state    geofenceState
+	next     time.Time // time of next position update
+	odometer float64   // odometer when leaving home
+}
+
+// newGeofence creates a geofence if configured for the vehicle
+func newGeofence(v api.Vehicle, loadpoints []*LoadPoint) (*geofence, error) {
+	gv, ok := v.(api.Geofencer)
+	if !ok {
+		return nil, nil
+	}
+
+	cc := gv.Geofence()
+	if cc.Latitude == 0 && cc.Longitude == 0 {
+		return nil, nil
+	}
+
+	position, ok := v.(api.VehiclePosition)
+	if !ok {
+		return nil, errors.New("vehicle does not provide position")
+	}
+
+	if cc.Radius == 0 {
+		cc.Radius = 200
+	}
+	if cc.Approach == 0 {
+		cc.Approach = 5000
+	}
+	if cc.Approach <= cc.Radius {
+		return nil, errors.New("approach must be larger than radius")
+	}
+	if cc.Interval == 0 {
+		cc.Interval = 5 * time.Minute
+	}
+
+	g := &geofence{
+		vehicle:        v,
+		position:       position,
+		GeofenceConfig: cc,
+	}
+
+	if cc.Plan.SoC > 0 {
+		t, err := time.Parse("15:04", cc.Plan.Time)
+		if err != nil {
+			return nil, fmt.Errorf("plan time: %w", err)
+		}
+		g.planTime = t
+	}
+
+	for _, lp := range loadpoints {
+		if cc.Loadpoint != "" && lp.Title == cc.Loadpoint ||
+			cc.Loadpoint == "" && lp.defaultVehicle == v {
+			g.lp = lp
+			break
+		}
+	}
+
+	if g.lp == nil && cc.Loadpoint == "" && len(loadpoints) == 1 {
+		g.lp = loadpoints[0]
+	}
+
+	if g.lp == nil {
+		return nil, errors.New("missing loadpoint")
+	}
+
+	return g, nil
+}
+
+// distance returns the great circle distance between two positions in m
+func distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371e3 // m
+
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := rad(lat2 - lat1)
+	dLon := rad(lon2 - lon1)
+
+	a := math.Pow(math.Sin(dLat/2), 2) + math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Pow(math.Sin(dLon/2), 2)
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// classify returns the geofence state for the distance from home
+func (g *geofence) classify(dist float64) geofenceState {
+	switch {
+	case dist <= g.Radius:
+		return geofenceHome
+	case dist <= g.Approach:
+		return geofenceNear
+	case g.Trip > 0 && dist > 1e3*g.Trip:
+		return geofenceTrip
+	default:
+		return geofenceAway
+	}
+}
+
+// interval returns the position polling interval for the distance from home.
+// While away or driving, polling backs off until the vehicle could reach the approach radius.
+func (g *geofence) interval(dist float64) time.Duration {
+	res := time.Duration((dist - g.Approach) / geofenceSpeed * float64(time.Second))
+	if res > geofenceMaxInterval {
+		res = geofenceMaxInterval
+	}
+	if res < g.Interval {
+		res = g.Interval
+	}
+	return res
+}
+
+// nextPlanTime returns the next occurrence of the plan time of day
+func (g *geofence) nextPlanTime(now time.Time) time.Time {
+	res := time.Date(now.Year(), now.Month(), now.Day(), g.planTime.Hour(), g.planTime.Minute(), 0, 0, now.Location())
+	if !res.After(now) {
+		res = res.AddDate(0, 0, 1)
+	}
+	return res
+}
+
+// approach applies the approach action and creates a charge plan if the vehicle soc is low
+func (g *geofence) approach(now time.Time) {
+	g.lp.applyAction(g.OnApproach)
+
+	if g.Plan.SoC == 0 {
+		return
+	}
+
+	soc, err := g.vehicle.SoC()
+	if err != nil {
+		g.lp.log.ERROR.Printf("geofence %s: %v", g.vehicle.Title(), err)
+		return
+	}
+
+	if soc < float64(g.Plan.Below) {
+		g.lp.SetTargetCharge(g.nextPlanTime(now), g.Plan.SoC)
+	}
+}
+
+// tripDriven records the odometer when leaving home and returns true if the distance driven since exceeds the road trip distance
+func (g *geofence) tripDriven(prev, state geofenceState) bool {
+	vo, ok := g.vehicle.(api.VehicleOdometer)
+	if !ok || g.Trip == 0 || state == geofenceHome || state == geofenceNear && prev != geofenceHome {
+		return false
+	}
+
+	odo, err := vo.Odometer()
+	if err != nil {
+		return false
+	}
+
+	if prev == geofenceHome {
+		g.odometer = odo
+		return false
+	}
+
+	return g.odometer > 0 && odo-g.odometer > g.Trip
+}
+
+// updateGeofences polls vehicle positions and triggers geofence actions on leaving or approaching home
+func (site *Site) updateGeofences() {
+	for _, g := range site.geofences {
+		if site.clock.Now().Before(g.next) {
+			continue
+		}
+		g.next = site.clock.Now().Add(g.Interval)
+
+		lat, lon, err := g.position.Position()
+		if err != nil {
+			site.log.ERROR.Printf("geofence %s: %v", g.vehicle.Title(), err)
+			continue
+		}
+
+		dist := distance(g.Latitude, g.Longitude, lat, lon)
+		g.next = site.clock.Now().Add(g.interval(dist))
+
+		prev := g.state
+		state := g.classify(dist)
+
+		// road trip ends only when approaching home
+		if prev == geofenceTrip && state == geofenceAway || g.tripDriven(prev, state) {
+			state = geofenceTrip
+		}
+
+		if g.state = state; prev == state {
+			continue
+		}
+
+		site.log.DEBUG.Printf("geofence %s: %s", g.vehicle.Title(), state)
+
+		if state == geofenceTrip {
+			g.lp.setTrip(true)
+		} else if prev == geofenceTrip {
+			g.lp.setTrip(false)
+		}
+
+		// initial position and connected vehicles don't trigger actions
+		if prev == geofenceUnknown || g.lp.connected() {
+			continue
+		}
+
+		switch {
+		case prev == geofenceHome:
+			site.log.INFO.Printf("geofence %s: left home", g.vehicle.Title())
+			g.lp.applyAction(g.OnLeave)
+
+		case prev == geofenceAway && state != geofenceTrip || prev == geofenceTrip:
+			site.log.INFO.Printf("geofence %s: approaching home", g.vehicle.Title())
+			g.approach(site.now())
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type geofenceVehicle struct {
+	api.Vehicle
+	lat, lon, soc float64
+}
+
+func (v *geofenceVehicle) Title() string {
+	return "car"
+}
+
+func (v *geofenceVehicle) SoC() (float64, error) {
+	return v.soc, nil
+}
+
+func (v *geofenceVehicle) Position() (float64, float64, error) {
+	return v.lat, v.lon, nil
+}
+
+func TestGeofence(t *testing.T) {
+	clck := clock.NewMock()
+	clck.Set(time.Date(2022, 6, 1, 18, 0, 0, 0, time.UTC))
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.Mode = api.ModePV
+
+	// home
+	v := &geofenceVehicle{lat: 52.52, lon: 13.405, soc: 20}
+
+	off, pv := api.ModeOff, api.ModePV
+	site := &Site{
+		log:   util.NewLogger("foo"),
+		clock: clck,
+		geofences: []*geofence{{
+			vehicle:  v,
+			position: v,
+			lp:       lp,
+			planTime: time.Date(0, 1, 1, 7, 0, 0, 0, time.UTC),
+			GeofenceConfig: api.GeofenceConfig{
+				Latitude:   52.52,
+				Longitude:  13.405,
+				Radius:     200,
+				Approach:   5000,
+				Interval:   time.Minute,
+				OnLeave:    api.ActionConfig{Mode: &off},
+				OnApproach: api.ActionConfig{Mode: &pv},
+				Plan:       api.GeofencePlan{Below: 30, SoC: 80, Time: "07:00"},
+			},
+		}},
+	}
+
+	// initial position does not trigger
+	site.updateGeofences()
+	assert.Equal(t, api.ModePV, lp.GetMode())
+
+	// within interval position is not polled
+	v.lat = 52.6
+	site.updateGeofences()
+	assert.Equal(t, geofenceHome, site.geofences[0].state)
+
+	// leaving home
+	clck.Add(time.Minute)
+	site.updateGeofences()
+	assert.Equal(t, geofenceAway, site.geofences[0].state)
+	assert.Equal(t, api.ModeOff, lp.GetMode())
+
+	// polling backs off while away
+	v.lat = 52.55
+	clck.Add(time.Minute)
+	site.updateGeofences()
+	assert.Equal(t, geofenceAway, site.geofences[0].state)
+
+	// approaching home with low soc creates plan
+	clck.Add(time.Minute)
+	site.updateGeofences()
+	assert.Equal(t, geofenceNear, site.geofences[0].state)
+	assert.Equal(t, api.ModePV, lp.GetMode())
+	assert.Equal(t, time.Date(2022, 6, 2, 7, 0, 0, 0, time.UTC), lp.GetTargetTime())
+	assert.Equal(t, 80, lp.GetTargetSoC())
+}
+
+func TestGeofenceDistance(t *testing.T) {
+	// Berlin to Hamburg
+	assert.InDelta(t, 255e3, distance(52.52, 13.405, 53.551, 9.994), 1e3)
+	assert.Equal(t, 0.0, distance(52.52, 13.405, 52.52, 13.405))
+}
+
+type tripVehicle struct {
+	geofenceVehicle
+	odo float64
+}
+
+func (v *tripVehicle) Odometer() (float64, error) {
+	return v.odo, nil
+}
+
+func TestGeofenceTrip(t *testing.T) {
+	clck := clock.NewMock()
+	clck.Set(time.Date(2022, 6, 1, 18, 0, 0, 0, time.UTC))
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.status = api.StatusA
+	lp.socTimer.SetClock(clck)
+
+	v := &tripVehicle{geofenceVehicle: geofenceVehicle{lat: 52.52, lon: 13.405}, odo: 1000}
+
+	g := &geofence{
+		vehicle:  v,
+		position: v,
+		lp:       lp,
+		GeofenceConfig: api.GeofenceConfig{
+			Latitude:  52.52,
+			Longitude: 13.405,
+			Radius:    200,
+			Approach:  5000,
+			Interval:  time.Minute,
+			Trip:      50,
+		},
+	}
+
+	site := &Site{
+		log:       util.NewLogger("foo"),
+		clock:     clck,
+		geofences: []*geofence{g},
+	}
+
+	site.updateGeofences()
+
+	// plan expires during trip
+	lp.SetTargetCharge(clck.Now().Add(time.Hour), 80)
+
+	// leaving home
+	v.lat, v.odo = 52.6, 1010
+	clck.Add(time.Minute)
+	site.updateGeofences()
+	assert.Equal(t, geofenceAway, g.state)
+	assert.Equal(t, 1010.0, g.odometer)
+	assert.False(t, lp.tripSuspended())
+
+	// driven distance exceeds trip although position is within trip distance
+	v.odo = 1100
+	clck.Add(2 * time.Minute)
+	site.updateGeofences()
+	assert.Equal(t, geofenceTrip, g.state)
+	assert.True(t, lp.tripSuspended())
+	assert.False(t, lp.socPollAllowed())
+
+	planTime, _ := lp.plannedCharge()
+	assert.True(t, planTime.IsZero())
+
+	// trip continues
+	clck.Add(2 * time.Hour)
+	site.updateGeofences()
+	assert.Equal(t, geofenceTrip, g.state)
+
+	// approaching home resumes and removes expired plan
+	v.lat = 52.55
+	clck.Add(2 * time.Minute)
+	site.updateGeofences()
+	assert.Equal(t, geofenceNear, g.state)
+	assert.False(t, lp.tripSuspended())
+	assert.True(t, lp.GetTargetTime().IsZero())
+}
+
+func TestGeofenceInterval(t *testing.T) {
+	g := &geofence{GeofenceConfig: api.GeofenceConfig{Approach: 5000, Interval: time.Minute}}
+
+	assert.Equal(t, time.Minute, g.interval(0))
+	assert.Equal(t, time.Minute, g.interval(6000))
+	assert.Equal(t, 6*time.Minute, g.interval(5000+6*60*geofenceSpeed))
+	assert.Equal(t, geofenceMaxInterval, g.interval(1000e3))
+}
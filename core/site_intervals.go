@@ -0,0 +1,75 @@
+package core
+
+import (
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// pollTolerance prevents skipping a whole cycle since poll timestamps jitter with the cycle
+const pollTolerance = time.Second
+
+// maxControlInterval limits the grid meter and charger intervals. The charge control keeps using their
+// last values in between, delaying its reaction to load changes and vehicles being disconnected.
+const maxControlInterval = 30 * time.Second
+
+// IntervalsConfig defines the polling intervals by device class. Zero intervals poll every cycle.
+// Intervals shorter than the site's cycle interval are rounded up to the cycle interval.
+// Grid meter and charger are polled every cycle by default, their intervals are limited to maxControlInterval.
+type IntervalsConfig struct {
+	Grid    time.Duration `mapstructure:"grid"`    // grid meter
+	PV      time.Duration `mapstructure:"pv"`      // pv meters
+	Battery time.Duration `mapstructure:"battery"` // battery meters
+	Charger time.Duration `mapstructure:"charger"` // charger status and charge meters
+	Vehicle time.Duration `mapstructure:"vehicle"` // vehicle soc while charging
+}
+
+// pollTimer limits polling a device class to its interval. A nil timer is always due.
+type pollTimer struct {
+	clock    clock.Clock
+	interval time.Duration
+	updated  time.Time
+}
+
+func newPollTimer(clock clock.Clock, interval time.Duration) *pollTimer {
+	return &pollTimer{clock: clock, interval: interval}
+}
+
+// due returns true if the device class must be polled
+func (t *pollTimer) due() bool {
+	if t == nil || t.interval == 0 || t.updated.IsZero() {
+		return true
+	}
+	return t.clock.Since(t.updated) >= t.interval-pollTolerance
+}
+
+// done marks the device class as successfully polled
+func (t *pollTimer) done() {
+	if t != nil {
+		t.updated = t.clock.Now()
+	}
+}
+
+// controlInterval limits the interval of devices driving the charge control
+func (site *Site) controlInterval(class string, interval time.Duration) time.Duration {
+	if interval > maxControlInterval {
+		site.log.WARN.Printf("%s interval limited to %v", class, maxControlInterval)
+		return maxControlInterval
+	}
+	return interval
+}
+
+// prepareIntervals creates the site's and loadpoints' poll timers
+func (site *Site) prepareIntervals() {
+	site.gridPoll = newPollTimer(site.clock, site.controlInterval("grid", site.Intervals.Grid))
+	site.pvPoll = newPollTimer(site.clock, site.Intervals.PV)
+	site.batteryPoll = newPollTimer(site.clock, site.Intervals.Battery)
+
+	charger := site.controlInterval("charger", site.Intervals.Charger)
+
+	for _, lp := range site.loadpoints {
+		lp.chargerPoll = newPollTimer(lp.clock, charger)
+		lp.chargeMeterPoll = newPollTimer(lp.clock, charger)
+		lp.vehicleInterval = site.Intervals.Vehicle
+	}
+}
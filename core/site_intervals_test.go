@@ -0,0 +1,73 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollTimer(t *testing.T) {
+	clck := clock.NewMock()
+
+	var nilTimer *pollTimer
+	assert.True(t, nilTimer.due())
+
+	pt := newPollTimer(clck, time.Minute)
+	assert.True(t, pt.due(), "initial poll")
+
+	pt.done()
+	assert.False(t, pt.due())
+
+	clck.Add(time.Minute - pollTolerance)
+	assert.True(t, pt.due(), "jitter tolerance")
+
+	pt = newPollTimer(clck, 0)
+	pt.done()
+	assert.True(t, pt.due(), "every cycle")
+}
+
+func TestSiteIntervals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	grid := mock.NewMockMeter(ctrl)
+	pv := mock.NewMockMeter(ctrl)
+
+	site := &Site{
+		log:       util.NewLogger("foo"),
+		clock:     clck,
+		gridMeter: grid,
+		pvMeters:  []api.Meter{pv},
+		Intervals: IntervalsConfig{PV: 30 * time.Second},
+	}
+	site.prepareIntervals()
+
+	grid.EXPECT().CurrentPower().Return(1000.0, nil).Times(4)
+	pv.EXPECT().CurrentPower().Return(2000.0, nil).Times(2)
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, site.updateMeters(context.Background()))
+		assert.Equal(t, 2000.0, site.pvPower)
+		clck.Add(10 * time.Second)
+	}
+
+	// grid interval is opt-in and limited
+	site.Intervals = IntervalsConfig{Grid: 5 * time.Minute}
+	site.prepareIntervals()
+	assert.Equal(t, maxControlInterval, site.gridPoll.interval)
+
+	grid.EXPECT().CurrentPower().Return(1000.0, nil).Times(2)
+	pv.EXPECT().CurrentPower().Return(2000.0, nil).Times(4)
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, site.updateMeters(context.Background()))
+		clck.Add(10 * time.Second)
+	}
+}
@@ -0,0 +1,83 @@
+package core
+
+import "github.com/evcc-io/evcc/api"
+
+// IslandConfig defines the restricted charging policy during backup power/ island operation
+type IslandConfig struct {
+	MinSoC float64 `mapstructure:"minSoC"` // stop charging below this battery soc during island operation
+}
+
+// islandPolicy restricts charging during island operation
+type islandPolicy interface {
+	islandRestriction() (active, blocked bool)
+}
+
+// islandMeter returns the first site meter able to detect island operation
+func (site *Site) islandMeter() api.MeterIsland {
+	meters := append([]api.Meter{site.gridMeter}, site.batteryMeters...)
+	meters = append(meters, site.pvMeters...)
+
+	for _, m := range meters {
+		if im, ok := m.(api.MeterIsland); ok {
+			return im
+		}
+	}
+
+	return nil
+}
+
+// updateIsland detects island operation
+func (site *Site) updateIsland() {
+	if site.islandDetector == nil {
+		return
+	}
+
+	island, err := site.islandDetector.Island()
+	if err != nil {
+		site.log.ERROR.Printf("island: %v", err)
+		return
+	}
+
+	site.Lock()
+	if island != site.island {
+		if island {
+			site.log.WARN.Println("island operation detected: restricting charging")
+		} else {
+			site.log.INFO.Println("grid returned: resuming normal operation")
+		}
+	}
+	site.island = island
+	site.Unlock()
+
+	site.publish("island", island)
+}
+
+// islandRestriction returns if island operation is active and if charging is blocked by the battery soc floor
+func (site *Site) islandRestriction() (bool, bool) {
+	site.Lock()
+	defer site.Unlock()
+	return site.island, site.island && len(site.batteryMeters) > 0 && site.batterySoC < site.Island.MinSoC
+}
+
+// islandCurrent limits the charge current to minimum current during island operation
+func (lp *LoadPoint) islandCurrent(chargeCurrent float64) (float64, bool) {
+	if lp.islander == nil {
+		return chargeCurrent, false
+	}
+
+	active, blocked := lp.islander.islandRestriction()
+	switch {
+	case !active || chargeCurrent == 0:
+		return chargeCurrent, false
+
+	case blocked:
+		lp.log.DEBUG.Println("island operation: battery below minimum soc")
+		return 0, true
+
+	case chargeCurrent > lp.GetMinCurrent():
+		lp.log.DEBUG.Printf("island operation: limit charge current to %.3gA", lp.GetMinCurrent())
+		return lp.GetMinCurrent(), true
+	}
+
+	return chargeCurrent, false
+}
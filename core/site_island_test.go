@@ -0,0 +1,51 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIslandCurrent(t *testing.T) {
+	site := &Site{
+		log:           util.NewLogger("foo"),
+		batteryMeters: []api.Meter{&Null{}},
+		Island: IslandConfig{
+			MinSoC: 30,
+		},
+	}
+
+	lp := &LoadPoint{
+		log:        util.NewLogger("foo"),
+		MinCurrent: minA,
+		MaxCurrent: maxA,
+		islander:   site,
+	}
+
+	tc := []struct {
+		island     bool
+		soc        float64
+		current    float64
+		expected   float64
+		restricted bool
+	}{
+		{false, 10, maxA, maxA, false},
+		{true, 50, maxA, minA, true},
+		{true, 50, minA, minA, false},
+		{true, 50, 0, 0, false},
+		{true, 20, maxA, 0, true},
+	}
+
+	for _, tc := range tc {
+		t.Logf("%+v", tc)
+
+		site.island = tc.island
+		site.batterySoC = tc.soc
+
+		current, restricted := lp.islandCurrent(tc.current)
+		assert.Equal(t, tc.expected, current)
+		assert.Equal(t, tc.restricted, restricted)
+	}
+}
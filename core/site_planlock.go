@@ -0,0 +1,27 @@
+package core
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// lockRates returns the grid rates until the target time with their cheap state at the time of locking
+func (site *Site) lockRates(until time.Time) ([]lockedRate, error) {
+	rates, err := site.tariffs.Grid.(api.TariffRates).Rates()
+	if err != nil {
+		return nil, err
+	}
+
+	limit := site.tariffs.Grid.(api.TariffCheapLimit).CheapLimit()
+	now := site.clock.Now()
+
+	var res []lockedRate
+	for _, r := range rates {
+		if r.End.After(now) && r.Start.Before(until) {
+			res = append(res, lockedRate{Rate: r, Cheap: r.Price <= limit})
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"golang.org/x/exp/slices"
+)
+
+// PlanConfig defines a named charging plan template for recurring scenarios, e.g. the workday commute.
+// Applying the plan sets the loadpoint's target charge to the plan's next departure.
+type PlanConfig struct {
+	Name       string   `mapstructure:"name"`       // plan name used for activation
+	Loadpoints []string `mapstructure:"loadpoints"` // loadpoint titles, all loadpoints if empty
+	SoC        int      `mapstructure:"soc"`        // target soc
+	Time       string   `mapstructure:"time"`       // departure time of day hh:mm
+	Days       []string `mapstructure:"days"`       // mon..sun, weekdays or weekend, every day if empty
+}
+
+// plan is a parsed plan template
+type plan struct {
+	PlanConfig
+	loadpoints []*LoadPoint
+	days       map[time.Weekday]bool // nil for every day
+	tod        time.Duration         // departure time of day
+}
+
+// newPlanFromConfig creates a plan template
+func newPlanFromConfig(cc PlanConfig, loadpoints []*LoadPoint) (*plan, error) {
+	if cc.Name == "" {
+		return nil, fmt.Errorf("plan: missing name")
+	}
+
+	p := &plan{PlanConfig: cc}
+
+	if cc.SoC <= 0 || cc.SoC > 100 {
+		return nil, fmt.Errorf("plan %s: invalid soc: %d", cc.Name, cc.SoC)
+	}
+
+	var err error
+	if cc.Time == "" {
+		err = fmt.Errorf("missing time")
+	} else {
+		p.tod, err = parseTimeOfDay(cc.Time)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("plan %s: %w", cc.Name, err)
+	}
+
+	if len(cc.Loadpoints) == 0 {
+		p.loadpoints = loadpoints
+	}
+
+	for _, title := range cc.Loadpoints {
+		idx := slices.IndexFunc(loadpoints, func(lp *LoadPoint) bool {
+			return lp.Title == title
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("plan %s: loadpoint not found: %s", cc.Name, title)
+		}
+		p.loadpoints = append(p.loadpoints, loadpoints[idx])
+	}
+
+	for _, day := range cc.Days {
+		wd, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return nil, fmt.Errorf("plan %s: invalid day: %s", cc.Name, day)
+		}
+
+		if p.days == nil {
+			p.days = make(map[time.Weekday]bool)
+		}
+		for _, d := range wd {
+			p.days[d] = true
+		}
+	}
+
+	return p, nil
+}
+
+// newPlansFromConfig creates the plan templates
+func newPlansFromConfig(plans []PlanConfig, loadpoints []*LoadPoint) ([]*plan, error) {
+	var res []*plan
+
+	for _, cc := range plans {
+		p, err := newPlanFromConfig(cc, loadpoints)
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.IndexFunc(res, func(o *plan) bool {
+			return strings.EqualFold(o.Name, p.Name)
+		}) >= 0 {
+			return nil, fmt.Errorf("plan %s: duplicate name", p.Name)
+		}
+
+		res = append(res, p)
+	}
+
+	return res, nil
+}
+
+// next returns the plan's next departure after now
+func (p *plan) next(now time.Time) time.Time {
+	hour, min := int(p.tod/time.Hour), int(p.tod%time.Hour/time.Minute)
+
+	// build the wall clock time of each day since days may not have 24 hours when daylight saving time changes
+	for i := 0; i <= 7; i++ {
+		ts := time.Date(now.Year(), now.Month(), now.Day()+i, hour, min, 0, 0, now.Location())
+		if ts.After(now) && (p.days == nil || p.days[ts.Weekday()]) {
+			return ts
+		}
+	}
+
+	// unreachable since every plan has at least one day
+	return time.Time{}
+}
+
+// attached returns if the plan applies to the loadpoint
+func (p *plan) attached(lp loadpoint.API) bool {
+	return slices.IndexFunc(p.loadpoints, func(l *LoadPoint) bool {
+		return loadpoint.API(l) == lp
+	}) >= 0
+}
+
+// GetPlans returns the names of the plan templates
+func (site *Site) GetPlans() []string {
+	res := make([]string, 0, len(site.plans))
+	for _, p := range site.plans {
+		res = append(res, p.Name)
+	}
+	return res
+}
+
+// ApplyPlan sets the target charge of the loadpoint, or of all loadpoints attached to the plan if nil, to the plan's next departure
+func (site *Site) ApplyPlan(name string, lp loadpoint.API) (time.Time, error) {
+	idx := slices.IndexFunc(site.plans, func(p *plan) bool {
+		return strings.EqualFold(p.Name, name)
+	})
+	if idx < 0 {
+		return time.Time{}, fmt.Errorf("plan not found: %s", name)
+	}
+
+	p := site.plans[idx]
+	if lp != nil && !p.attached(lp) {
+		return time.Time{}, fmt.Errorf("plan %s: not attached to loadpoint", p.Name)
+	}
+
+	ts := p.next(site.now())
+
+	for _, l := range p.loadpoints {
+		if lp == nil || loadpoint.API(l) == lp {
+			site.log.INFO.Printf("plan %s: target charge %d%% at %v (%s)", p.Name, p.SoC, ts.Round(time.Minute), l.Title)
+			l.SetTargetCharge(ts, p.SoC)
+		}
+	}
+
+	return ts, nil
+}
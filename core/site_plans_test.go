@@ -0,0 +1,90 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanNext(t *testing.T) {
+	p, err := newPlanFromConfig(PlanConfig{Name: "commute", SoC: 80, Time: "07:30", Days: []string{"weekdays"}}, nil)
+	require.NoError(t, err)
+
+	// friday 2022-10-07
+	fri := func(h int) time.Time { return time.Date(2022, 10, 7, h, 0, 0, 0, time.UTC) }
+
+	assert.Equal(t, fri(7).Add(30*time.Minute), p.next(fri(6)))
+	assert.Equal(t, fri(3*24+7).Add(30*time.Minute), p.next(fri(8)), "weekend is skipped")
+
+	// departure keeps its wall clock time when daylight saving time ends
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	p, err = newPlanFromConfig(PlanConfig{Name: "daily", SoC: 80, Time: "07:00"}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2022, 10, 30, 7, 0, 0, 0, loc), p.next(time.Date(2022, 10, 29, 12, 0, 0, 0, loc)))
+	assert.Equal(t, time.Date(2022, 3, 27, 7, 0, 0, 0, loc), p.next(time.Date(2022, 3, 26, 12, 0, 0, 0, loc)))
+
+	_, err = newPlanFromConfig(PlanConfig{Name: "trip", SoC: 80}, nil)
+	assert.Error(t, err)
+
+	_, err = newPlanFromConfig(PlanConfig{Name: "trip", SoC: 80, Time: "09:00", Loadpoints: []string{"Garage"}}, nil)
+	assert.Error(t, err)
+
+	_, err = newPlansFromConfig([]PlanConfig{
+		{Name: "trip", SoC: 80, Time: "09:00"},
+		{Name: "Trip", SoC: 100, Time: "10:00"},
+	}, nil)
+	assert.Error(t, err)
+}
+
+func TestApplyPlan(t *testing.T) {
+	clck := clock.NewMock()
+	clck.Set(time.Date(2022, 10, 7, 12, 0, 0, 0, time.UTC))
+
+	var lps []*LoadPoint
+	for _, title := range []string{"Garage", "Carport"} {
+		lp := &LoadPoint{log: util.NewLogger("foo"), clock: clck, Title: title}
+		lp.socTimer = soc.NewTimer(lp.log, &adapter{LoadPoint: lp})
+		lp.socTimer.SetClock(clck)
+		lps = append(lps, lp)
+	}
+
+	plans, err := newPlansFromConfig([]PlanConfig{
+		{Name: "weekend trip", SoC: 100, Time: "09:00", Days: []string{"sat"}},
+		{Name: "commute", SoC: 60, Time: "07:00", Loadpoints: []string{"Carport"}},
+	}, lps)
+	require.NoError(t, err)
+
+	site := &Site{log: util.NewLogger("foo"), clock: clck, loadpoints: lps, plans: plans}
+	assert.Equal(t, []string{"weekend trip", "commute"}, site.GetPlans())
+
+	// plan applies to all attached loadpoints
+	ts, err := site.ApplyPlan("Weekend Trip", nil)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2022, 10, 8, 9, 0, 0, 0, time.UTC), ts)
+
+	for _, lp := range lps {
+		assert.Equal(t, ts, lp.GetTargetTime())
+		assert.Equal(t, 100, lp.GetTargetSoC())
+	}
+
+	// plan applies to single loadpoint
+	ts, err = site.ApplyPlan("commute", lps[1])
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2022, 10, 8, 7, 0, 0, 0, time.UTC), ts)
+	assert.Equal(t, 60, lps[1].GetTargetSoC())
+	assert.Equal(t, 100, lps[0].GetTargetSoC())
+
+	_, err = site.ApplyPlan("commute", lps[0])
+	assert.Error(t, err, "plan not attached to loadpoint")
+
+	_, err = site.ApplyPlan("vacation", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,172 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/weather"
+)
+
+const evStorm = "storm" // severe weather pre-charge started
+
+// StormConfig defines pre-charging of home battery and vehicles ahead of severe weather warnings
+type StormConfig struct {
+	Warnings   weather.Config   `mapstructure:"warnings"`   // weather warning provider
+	Severity   int              `mapstructure:"severity"`   // minimum warning severity from 1 minor to 4 extreme
+	Lead       time.Duration    `mapstructure:"lead"`       // start pre-charging before warning onset
+	BatterySoC float64          `mapstructure:"batterySoC"` // pre-charge home battery to this soc
+	Battery    *provider.Config `mapstructure:"battery"`    // home battery forced charge control
+	VehicleSoC int              `mapstructure:"vehicleSoC"` // raise vehicles' min soc to this soc
+}
+
+// storm pre-charges home battery and vehicles while a severe weather warning is imminent or active
+type storm struct {
+	log      *util.Logger
+	warnings api.WeatherWarnings
+	charge   func(bool) error
+	StormConfig
+
+	warning  *api.WeatherWarning // active warning
+	minSoCs  map[*LoadPoint]int  // loadpoints' min soc before pre-charging
+	charging bool                // home battery forced charge
+	inhibit  bool                // home battery forced charge blocked, e.g. by frost protection
+}
+
+// newStormFromConfig creates a storm pre-charge controller
+func newStormFromConfig(log *util.Logger, cc StormConfig) (*storm, error) {
+	if cc.Severity == 0 {
+		cc.Severity = 3 // severe
+	}
+	if cc.Lead == 0 {
+		cc.Lead = 6 * time.Hour
+	}
+
+	warnings, err := weather.NewFromConfig(cc.Warnings.Type, cc.Warnings.Other)
+	if err != nil {
+		return nil, fmt.Errorf("storm: %w", err)
+	}
+
+	s := &storm{
+		log:         log,
+		warnings:    warnings,
+		StormConfig: cc,
+	}
+
+	if cc.Battery != nil {
+		if s.charge, err = provider.NewBoolSetterFromConfig("battery", *cc.Battery); err != nil {
+			return nil, fmt.Errorf("storm: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// imminent returns the earliest warning of sufficient severity that is active or starts within lead time
+func (s *storm) imminent(now time.Time) *api.WeatherWarning {
+	res, err := s.warnings.Warnings()
+	if err != nil {
+		return s.warning
+	}
+
+	var warning *api.WeatherWarning
+	for i, w := range res {
+		if w.Severity < s.Severity || now.Before(w.Start.Add(-s.Lead)) || (!w.End.IsZero() && !now.Before(w.End)) {
+			continue
+		}
+
+		if warning == nil || w.Start.Before(warning.Start) {
+			warning = &res[i]
+		}
+	}
+
+	return warning
+}
+
+// setCharge enables or disables forced home battery charging
+func (s *storm) setCharge(enable bool) {
+	if s.charge == nil || enable == s.charging {
+		return
+	}
+
+	if err := s.charge(enable); err != nil {
+		s.log.ERROR.Printf("storm: battery charge: %v", err)
+		return
+	}
+
+	s.charging = enable
+}
+
+// update starts or stops pre-charging and returns the newly activated warning
+func (s *storm) update(now time.Time, batterySoC float64, loadpoints []*LoadPoint) *api.WeatherWarning {
+	warning := s.imminent(now)
+	started := warning != nil && s.warning == nil
+
+	switch {
+	case started:
+		s.log.WARN.Printf("storm: %s from %s: pre-charging", warning.Event, warning.Start.Round(time.Minute))
+		s.minSoCs = make(map[*LoadPoint]int)
+
+	case warning == nil && s.warning != nil:
+		s.log.INFO.Println("storm: warning ended: resuming normal operation")
+		s.setCharge(false)
+
+		// restore min soc unless changed meanwhile
+		for lp, soc := range s.minSoCs {
+			if lp.GetMinSoC() == s.VehicleSoC {
+				lp.SetMinSoC(soc)
+			}
+		}
+		s.minSoCs = nil
+	}
+
+	s.warning = warning
+	if warning == nil {
+		return nil
+	}
+
+	s.setCharge(batterySoC < s.BatterySoC && !s.inhibit)
+
+	for _, lp := range loadpoints {
+		if soc := lp.GetMinSoC(); soc < s.VehicleSoC {
+			if _, ok := s.minSoCs[lp]; !ok {
+				s.minSoCs[lp] = soc
+			}
+			lp.SetMinSoC(s.VehicleSoC)
+		}
+	}
+
+	if started {
+		return warning
+	}
+
+	return nil
+}
+
+// updateStorm pre-charges ahead of severe weather and notifies when pre-charging starts
+func (site *Site) updateStorm() {
+	if site.storm == nil {
+		return
+	}
+
+	site.Lock()
+	soc := site.batterySoC
+	site.Unlock()
+
+	site.storm.inhibit = site.frost.blocked()
+	started := site.storm.update(site.clock.Now(), soc, site.loadpoints)
+
+	var event string
+	if site.storm.warning != nil {
+		event = site.storm.warning.Event
+	}
+	site.publish("stormWarning", event)
+
+	if started != nil && site.pushChan != nil {
+		site.publish("stormStart", started.Start)
+		site.pushChan <- push.Event{Event: evStorm}
+	}
+}
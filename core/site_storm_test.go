@@ -0,0 +1,86 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type warnings []api.WeatherWarning
+
+func (w warnings) Warnings() ([]api.WeatherWarning, error) {
+	return w, nil
+}
+
+func TestStorm(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	warning := api.WeatherWarning{Event: "storm", Severity: 3, Start: now.Add(4 * time.Hour), End: now.Add(8 * time.Hour)}
+
+	var charge []bool
+	s := &storm{
+		log:      util.NewLogger("foo"),
+		warnings: warnings{warning, {Event: "fog", Severity: 1, Start: now}},
+		charge: func(enable bool) error {
+			charge = append(charge, enable)
+			return nil
+		},
+		StormConfig: StormConfig{
+			Severity:   3,
+			Lead:       2 * time.Hour,
+			BatterySoC: 90,
+			VehicleSoC: 80,
+		},
+	}
+
+	lp := &LoadPoint{log: util.NewLogger("foo")}
+	lp.SoC.min = 20
+
+	// minor warning ignored, storm beyond lead time
+	assert.Nil(t, s.update(now, 50, []*LoadPoint{lp}))
+	assert.Equal(t, 20, lp.GetMinSoC())
+
+	// pre-charging starts within lead time
+	assert.Equal(t, "storm", s.update(now.Add(2*time.Hour), 50, []*LoadPoint{lp}).Event)
+	assert.Equal(t, 80, lp.GetMinSoC())
+	assert.Equal(t, []bool{true}, charge)
+
+	// notified once, battery charge stops at target soc
+	assert.Nil(t, s.update(now.Add(5*time.Hour), 90, []*LoadPoint{lp}))
+	assert.Equal(t, []bool{true, false}, charge)
+
+	// min soc restored after warning
+	assert.Nil(t, s.update(now.Add(8*time.Hour), 90, []*LoadPoint{lp}))
+	assert.Equal(t, 20, lp.GetMinSoC())
+	assert.Nil(t, s.warning)
+}
+
+func TestStormInhibit(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	var charge []bool
+	s := &storm{
+		log:      util.NewLogger("foo"),
+		warnings: warnings{{Event: "storm", Severity: 3, Start: now}},
+		charge: func(enable bool) error {
+			charge = append(charge, enable)
+			return nil
+		},
+		StormConfig: StormConfig{Severity: 3, BatterySoC: 90},
+		inhibit:     true,
+	}
+
+	// frost protection blocks forced charging
+	assert.Equal(t, "storm", s.update(now, 50, nil).Event)
+	assert.Empty(t, charge)
+
+	s.inhibit = false
+	assert.Nil(t, s.update(now, 50, nil))
+	assert.Equal(t, []bool{true}, charge)
+
+	s.inhibit = true
+	assert.Nil(t, s.update(now, 50, nil))
+	assert.Equal(t, []bool{true, false}, charge)
+}
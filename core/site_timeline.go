@@ -0,0 +1,40 @@
+package core
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/core/db"
+	serverdb "github.com/evcc-io/evcc/server/db"
+)
+
+// timelinePurgeInterval is the interval for deleting expired timeline events
+const timelinePurgeInterval = time.Hour
+
+// TimelineConfig defines the retention of the loadpoints' decision timeline
+type TimelineConfig struct {
+	Retention time.Duration `mapstructure:"retention"` // keep events for this duration, 30 days if zero
+}
+
+// updateTimeline deletes timeline events exceeding the retention
+func (site *Site) updateTimeline() {
+	if serverdb.Instance == nil || site.clock.Since(site.timelinePurged) < timelinePurgeInterval {
+		return
+	}
+
+	retention := site.Timeline.Retention
+	if retention == 0 {
+		retention = 30 * 24 * time.Hour
+	}
+
+	site.timelinePurged = site.clock.Now()
+
+	n, err := db.PurgeEvents(serverdb.Instance, site.timelinePurged.Add(-retention))
+	if err != nil {
+		site.log.ERROR.Printf("timeline: %v", err)
+		return
+	}
+
+	if n > 0 {
+		site.log.DEBUG.Printf("timeline: deleted %d expired events", n)
+	}
+}
@@ -7,4 +7,5 @@ type Adapter interface {
 	loadpoint.API
 	Publish(key string, val interface{})
 	SocEstimator() *Estimator
+	SocStale() bool
 }
@@ -0,0 +1,124 @@
+package soc
+
+import (
+	"math"
+	"sync"
+
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+const (
+	consumptionMin       = 5.0  // kWh/100km, lower plausibility bound
+	consumptionMax       = 60.0 // kWh/100km, upper plausibility bound
+	consumptionSmoothing = 0.3  // weight of new samples
+	consumptionDistance  = 10.0 // km, minimum distance for valid sample
+)
+
+var (
+	consumptionMu sync.Mutex
+	consumptions  = make(map[string]*Consumption)
+)
+
+// Consumption learns a vehicle's energy consumption in kWh/100km from
+// odometer readings and the energy charged between them
+type Consumption struct {
+	mu       sync.Mutex
+	key      string
+	value    float64 // learned consumption in kWh/100km
+	odometer float64 // odometer at last sample in km
+	energy   float64 // energy charged since last sample in kWh
+}
+
+// VehicleConsumption returns the shared consumption tracker for the given vehicle title
+func VehicleConsumption(title string) *Consumption {
+	consumptionMu.Lock()
+	defer consumptionMu.Unlock()
+
+	if c, ok := consumptions[title]; ok {
+		return c
+	}
+
+	c := &Consumption{key: "consumption." + title}
+	c.load()
+	consumptions[title] = c
+
+	return c
+}
+
+func (c *Consumption) load() {
+	c.value, _ = settings.Float(c.key + ".value")
+	c.odometer, _ = settings.Float(c.key + ".odometer")
+	c.energy, _ = settings.Float(c.key + ".energy")
+}
+
+func (c *Consumption) save() {
+	settings.SetFloat(c.key+".value", c.value)
+	settings.SetFloat(c.key+".odometer", c.odometer)
+	settings.SetFloat(c.key+".energy", c.energy)
+}
+
+// Value returns the learned consumption in kWh/100km or 0 if unknown
+func (c *Consumption) Value() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+// AddEnergy adds charged energy in kWh
+func (c *Consumption) AddEnergy(energy float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if energy <= 0 {
+		return
+	}
+
+	c.energy += energy
+	c.save()
+}
+
+// AddOdometer adds an odometer reading in km and updates the learned consumption
+// using the energy charged since the previous reading
+func (c *Consumption) AddOdometer(odometer float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if odometer <= 0 || odometer == c.odometer {
+		return
+	}
+
+	distance := odometer - c.odometer
+
+	// accumulate short trips
+	if c.odometer > 0 && distance > 0 && distance < consumptionDistance {
+		return
+	}
+
+	// first reading or odometer reset are used as baseline only
+	if c.odometer > 0 && distance > 0 && c.energy > 0 {
+		if sample := 100 * c.energy / distance; sample >= consumptionMin && sample <= consumptionMax {
+			if c.value == 0 {
+				c.value = sample
+			} else {
+				c.value = (1-consumptionSmoothing)*c.value + consumptionSmoothing*sample
+			}
+		}
+	}
+
+	c.odometer = odometer
+	c.energy = 0
+	c.save()
+}
+
+// Energy converts range in km to energy in kWh
+func (c *Consumption) Energy(km int) float64 {
+	return float64(km) * c.Value() / 100
+}
+
+// Range converts energy in kWh to range in km
+func (c *Consumption) Range(energy float64) int {
+	if v := c.Value(); v > 0 {
+		return int(math.Round(100 * energy / v))
+	}
+	return 0
+}
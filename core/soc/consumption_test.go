@@ -0,0 +1,43 @@
+package soc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumption(t *testing.T) {
+	c := VehicleConsumption("test")
+
+	// first odometer reading is baseline only
+	c.AddEnergy(10)
+	c.AddOdometer(1000)
+	assert.Equal(t, 0.0, c.Value())
+
+	// 20 kWh for 100 km
+	c.AddEnergy(20)
+	c.AddOdometer(1100)
+	assert.Equal(t, 20.0, c.Value())
+
+	// short trips are accumulated
+	c.AddEnergy(1)
+	c.AddOdometer(1105)
+	assert.Equal(t, 20.0, c.Value())
+
+	// 30 kWh for 100 km is smoothed
+	c.AddEnergy(29)
+	c.AddOdometer(1200)
+	assert.InDelta(t, 23.0, c.Value(), 1e-9)
+
+	// implausible values are ignored
+	c.AddEnergy(1)
+	c.AddOdometer(1300)
+	assert.InDelta(t, 23.0, c.Value(), 1e-9)
+
+	// conversion
+	assert.InDelta(t, 23.0, c.Energy(100), 1e-9)
+	assert.Equal(t, 100, c.Range(23))
+
+	// shared instance
+	assert.Same(t, c, VehicleConsumption("test"))
+}
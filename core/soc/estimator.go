@@ -5,6 +5,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 )
@@ -15,27 +16,35 @@ const chargeEfficiency = 0.9 // assume charge 90% efficiency
 // Vehicle SoC can be estimated to provide more granularity
 type Estimator struct {
 	log      *util.Logger
+	clock    clock.Clock
 	charger  api.Charger
 	vehicle  api.Vehicle
 	estimate bool
-
-	capacity          float64 // vehicle capacity in Wh cached to simplify testing
-	virtualCapacity   float64 // estimated virtual vehicle capacity in Wh
-	vehicleSoc        float64 // estimated vehicle SoC
-	initialSoc        float64 // first received valid vehicle SoC
-	initialEnergy     float64 // energy counter at first valid SoC
-	prevSoc           float64 // previous vehicle SoC in %
-	prevChargedEnergy float64 // previous charged energy in Wh
-	energyPerSocStep  float64 // Energy per SoC percent in Wh
+	model    Model
+
+	capacity          float64   // vehicle capacity in Wh cached to simplify testing
+	virtualCapacity   float64   // estimated virtual vehicle capacity in Wh
+	vehicleSoc        float64   // estimated vehicle SoC
+	measuredSoc       float64   // last measured vehicle SoC
+	confidence        float64   // confidence of the estimated vehicle SoC
+	initialSoc        float64   // first received valid vehicle SoC
+	initialEnergy     float64   // energy counter at first valid SoC
+	prevSoc           float64   // previous vehicle SoC in %
+	prevChargedEnergy float64   // previous charged energy in Wh
+	energyPerSocStep  float64   // Energy per SoC percent in Wh
+	read              time.Time // last successful soc read
+	readErr           error     // error of the last soc read, if ignored by the estimator
 }
 
 // NewEstimator creates new estimator
 func NewEstimator(log *util.Logger, charger api.Charger, vehicle api.Vehicle, estimate bool) *Estimator {
 	s := &Estimator{
 		log:      log,
+		clock:    clock.New(),
 		charger:  charger,
 		vehicle:  vehicle,
 		estimate: estimate,
+		model:    Linear{},
 	}
 
 	s.Reset()
@@ -43,6 +52,32 @@ func NewEstimator(log *util.Logger, charger api.Charger, vehicle api.Vehicle, es
 	return s
 }
 
+// SetClock replaces the realtime clock, e.g. for simulation
+func (s *Estimator) SetClock(clck clock.Clock) {
+	s.clock = clck
+}
+
+// SetModel replaces the linear soc estimation model
+func (s *Estimator) SetModel(model Model) {
+	s.model = model
+}
+
+// Measured returns the last measured soc
+func (s *Estimator) Measured() float64 {
+	return s.measuredSoc
+}
+
+// Confidence returns the confidence of the estimated soc between 0 and 1
+func (s *Estimator) Confidence() float64 {
+	return s.confidence
+}
+
+// Read returns the time of the last successful soc read and the error of the last read
+// if it failed and the previous soc has been used instead
+func (s *Estimator) Read() (time.Time, error) {
+	return s.read, s.readErr
+}
+
 // Reset resets the estimation process to default values
 func (s *Estimator) Reset() {
 	s.prevSoc = 0
@@ -77,7 +112,7 @@ func (s *Estimator) RemainingChargeDuration(chargePower float64, targetSoC int)
 		if vr, ok := s.vehicle.(api.VehicleFinishTimer); ok {
 			finishTime, err := vr.FinishTime()
 			if err == nil {
-				timeRemaining := time.Until(finishTime)
+				timeRemaining := s.clock.Until(finishTime)
 				return time.Duration(float64(timeRemaining) * percentRemaining / (100 - s.vehicleSoc))
 			}
 
@@ -104,6 +139,11 @@ func (s *Estimator) RemainingChargeEnergy(targetSoC int) float64 {
 	return whRemaining / 1e3
 }
 
+// RemainingModelEnergy returns the remaining charge energy in kWh following the charge curve of the soc model
+func (s *Estimator) RemainingModelEnergy(targetSoC int) float64 {
+	return RemainingEnergy(s.model, s.vehicleSoc, float64(targetSoC), s.energyPerSocStep) / 1e3
+}
+
 // SoC replaces the api.Vehicle.SoC interface to take charged energy into account
 func (s *Estimator) SoC(chargedEnergy float64) (float64, error) {
 	var fetchedSoC *float64
@@ -124,6 +164,8 @@ func (s *Estimator) SoC(chargedEnergy float64) (float64, error) {
 				s.log.WARN.Printf("vehicle soc (charger): %v (ignored by estimator)", err)
 			}
 
+			s.readDone(err)
+
 			fetchedSoC = &f
 			s.vehicleSoc = f
 		}
@@ -147,10 +189,15 @@ func (s *Estimator) SoC(chargedEnergy float64) (float64, error) {
 			s.log.WARN.Printf("vehicle soc: %v (ignored by estimator)", err)
 		}
 
+		s.readDone(err)
+
 		fetchedSoC = &f
 		s.vehicleSoc = f
 	}
 
+	s.measuredSoc = *fetchedSoC
+	s.confidence = 1
+
 	if s.estimate && s.virtualCapacity > 0 {
 		socDelta := s.vehicleSoc - s.prevSoc
 		energyDelta := math.Max(chargedEnergy, 0) - s.prevChargedEnergy
@@ -174,6 +221,10 @@ func (s *Estimator) SoC(chargedEnergy float64) (float64, error) {
 			}
 
 			if !invalid {
+				if t, ok := s.model.(Trainer); ok && s.prevSoc > 0 && socDelta > 0 {
+					t.Train(s.prevSoc, s.vehicleSoc, energyDelta)
+				}
+
 				if s.initialSoc == 0 {
 					s.initialSoc = s.vehicleSoc
 					s.initialEnergy = chargedEnergy
@@ -194,10 +245,18 @@ func (s *Estimator) SoC(chargedEnergy float64) (float64, error) {
 			s.prevChargedEnergy = math.Max(chargedEnergy, 0)
 			s.prevSoc = s.vehicleSoc
 		} else {
-			s.vehicleSoc = math.Min(*fetchedSoC+energyDelta/s.energyPerSocStep, 100)
-			s.log.DEBUG.Printf("soc estimated: %.2f%% (vehicle: %.2f%%)", s.vehicleSoc, *fetchedSoC)
+			s.vehicleSoc, s.confidence = s.model.Estimate(*fetchedSoC, energyDelta, s.energyPerSocStep)
+			s.log.DEBUG.Printf("soc estimated: %.2f%% (vehicle: %.2f%%, confidence: %.2f)", s.vehicleSoc, *fetchedSoC, s.confidence)
 		}
 	}
 
 	return s.vehicleSoc, nil
 }
+
+// readDone stamps successful soc reads and keeps the error of failed reads
+func (s *Estimator) readDone(err error) {
+	s.readErr = err
+	if err == nil {
+		s.read = s.clock.Now()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/mock"
 	"github.com/evcc-io/evcc/util"
@@ -207,3 +208,36 @@ func TestSoCFromChargerAndVehicleWithErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestSoCRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	vehicle := mock.NewMockVehicle(ctrl)
+	charger := mock.NewMockCharger(ctrl)
+
+	clck := clock.NewMock()
+	vehicle.EXPECT().Capacity().Return(float64(10))
+
+	ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, true)
+	ce.SetClock(clck)
+
+	vehicle.EXPECT().SoC().Return(50.0, nil)
+	if _, err := ce.SoC(0); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ce.Read()
+	if err != nil || !read.Equal(clck.Now()) {
+		t.Errorf("expected read at %v, got: %v (%v)", clck.Now(), read, err)
+	}
+
+	// failed read keeps previous soc but not its time
+	clck.Add(time.Hour)
+	vehicle.EXPECT().SoC().Return(0.0, api.ErrAuthExpired)
+	if soc, err := ce.SoC(0); err != nil || soc != 50 {
+		t.Errorf("expected previous soc, got: %g (%v)", soc, err)
+	}
+
+	if res, err := ce.Read(); !errors.Is(err, api.ErrAuthExpired) || !res.Equal(read) {
+		t.Errorf("expected read at %v with error, got: %v (%v)", read, res, err)
+	}
+}
@@ -0,0 +1,147 @@
+package soc
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Model interpolates the vehicle soc between api updates
+type Model interface {
+	// Estimate returns the soc after charging energy in Wh on top of the measured soc
+	// given the learned energy per soc percent, and the confidence of the estimate between 0 and 1
+	Estimate(measured, energy, energyPerSoc float64) (soc, confidence float64)
+}
+
+// Trainer is implemented by models learning from observed soc changes
+type Trainer interface {
+	// Train is called when the measured soc changed after charging energy in Wh
+	Train(from, to, energy float64)
+}
+
+// confidenceSpan is the estimated soc increase after which confidence is lost
+const confidenceSpan = 25.0
+
+func confidence(measured, estimated float64) float64 {
+	return math.Max(0, 1-(estimated-measured)/confidenceSpan)
+}
+
+// Linear assumes constant energy per soc percent
+type Linear struct{}
+
+// Estimate implements the Model interface
+func (Linear) Estimate(measured, energy, energyPerSoc float64) (float64, float64) {
+	soc := math.Min(measured+energy/energyPerSoc, 100)
+	return soc, confidence(measured, soc)
+}
+
+// Curve assumes increasing energy per soc percent above the knee of the charge curve
+// where charging losses grow with tapering charge power
+type Curve struct {
+	Knee   float64 // soc where tapering starts
+	Factor float64 // energy per soc percent at 100% relative to below knee
+}
+
+// curveStep is the soc integration step in %
+const curveStep = 0.1
+
+// Estimate implements the Model interface
+func (m Curve) Estimate(measured, energy, energyPerSoc float64) (float64, float64) {
+	soc := measured
+
+	for energy > 0 && soc < 100 {
+		step := energyPerSoc * curveStep
+		if soc > m.Knee {
+			step *= 1 + (m.Factor-1)*(soc-m.Knee)/(100-m.Knee)
+		}
+
+		if energy < step {
+			soc += curveStep * energy / step
+			break
+		}
+
+		energy -= step
+		soc += curveStep
+	}
+
+	soc = math.Min(soc, 100)
+
+	// less confident on the curve
+	conf := confidence(measured, soc)
+	if soc > m.Knee {
+		conf *= 0.8
+	}
+
+	return soc, conf
+}
+
+// RemainingEnergy returns the energy in Wh required by the model to charge from measured to target soc
+func RemainingEnergy(model Model, measured, target, energyPerSoc float64) float64 {
+	if target <= measured || energyPerSoc <= 0 {
+		return 0
+	}
+
+	// find upper bound starting from linear assumption, limited for models never reaching the target
+	hi := (target - measured) * energyPerSoc
+	for i := 0; i < 4; i++ {
+		if soc, _ := model.Estimate(measured, hi, energyPerSoc); soc >= target {
+			break
+		}
+		hi *= 2
+	}
+
+	// bisect to 1Wh
+	var lo float64
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if soc, _ := model.Estimate(measured, mid, energyPerSoc); soc >= target {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	return hi
+}
+
+var models = struct {
+	sync.Mutex
+	factories map[string]func() Model
+}{
+	factories: map[string]func() Model{
+		"linear": func() Model { return Linear{} },
+		"curve":  func() Model { return Curve{Knee: 80, Factor: 1.3} },
+	},
+}
+
+// RegisterModel adds a named soc estimation model, e.g. a learned model
+func RegisterModel(name string, factory func() Model) {
+	models.Lock()
+	defer models.Unlock()
+	models.factories[strings.ToLower(name)] = factory
+}
+
+// NewModel creates a soc estimation model by name, linear if empty
+func NewModel(name string) (Model, error) {
+	if name == "" {
+		name = "linear"
+	}
+
+	models.Lock()
+	defer models.Unlock()
+
+	factory, ok := models.factories[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(models.factories))
+		for k := range models.factories {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("unknown soc model: %s, supported: %s", name, strings.Join(names, ", "))
+	}
+
+	return factory(), nil
+}
@@ -0,0 +1,78 @@
+package soc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestModels(t *testing.T) {
+	tc := []struct {
+		model        Model
+		measured     float64
+		energy       float64
+		soc, minConf float64
+	}{
+		{Linear{}, 20, 0, 20, 1},
+		{Linear{}, 20, 1000, 30, 0.55},
+		{Linear{}, 95, 1000, 100, 0.8},
+		{Curve{Knee: 80, Factor: 1.3}, 20, 1000, 30, 0.55},
+		{Curve{Knee: 80, Factor: 2}, 90, 1000, 96.1, 0.5},
+	}
+
+	for _, tc := range tc {
+		// 100Wh per percent
+		soc, conf := tc.model.Estimate(tc.measured, tc.energy, 100)
+
+		if math.Abs(soc-tc.soc) > 0.1 {
+			t.Errorf("%T: expected soc %.1f, got %.1f", tc.model, tc.soc, soc)
+		}
+
+		if conf < tc.minConf || conf > 1 {
+			t.Errorf("%T: unexpected confidence %.2f", tc.model, conf)
+		}
+	}
+}
+
+type constModel float64
+
+func (m constModel) Estimate(measured, energy, energyPerSoc float64) (float64, float64) {
+	return float64(m), 0.5
+}
+
+func TestNewModel(t *testing.T) {
+	if m, err := NewModel(""); err != nil || m != (Linear{}) {
+		t.Errorf("expected linear default model, got %v %v", m, err)
+	}
+
+	if _, err := NewModel("foo"); err == nil {
+		t.Error("expected unknown model error")
+	}
+
+	RegisterModel("Const", func() Model { return constModel(42) })
+	if m, err := NewModel("const"); err != nil || m != constModel(42) {
+		t.Errorf("expected registered model, got %v %v", m, err)
+	}
+}
+
+func TestRemainingEnergy(t *testing.T) {
+	tc := []struct {
+		model            Model
+		measured, target float64
+		energy           float64
+	}{
+		{Linear{}, 20, 20, 0},
+		{Linear{}, 20, 80, 6000},
+		{Curve{Knee: 80, Factor: 1.3}, 20, 80, 6000},
+		{Curve{Knee: 80, Factor: 2}, 80, 100, 3000},
+		{constModel(42), 20, 80, 96000}, // limited
+	}
+
+	for _, tc := range tc {
+		// 100Wh per percent
+		energy := RemainingEnergy(tc.model, tc.measured, tc.target, 100)
+
+		if math.Abs(energy-tc.energy) > 10 {
+			t.Errorf("%T: expected energy %.0f, got %.0f", tc.model, tc.energy, energy)
+		}
+	}
+}
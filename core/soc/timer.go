@@ -4,6 +4,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 )
@@ -16,10 +17,14 @@ const (
 type Timer struct {
 	Adapter
 	log       *util.Logger
+	clock     clock.Clock
 	current   float64
 	SoC       int
+	Energy    int // target energy in kWh, takes precedence over SoC
 	Time      time.Time
 	finishAt  time.Time
+	start     time.Time     // projected start
+	duration  time.Duration // remaining duration estimated from last fresh soc
 	active    bool
 	validated bool
 }
@@ -28,12 +33,18 @@ type Timer struct {
 func NewTimer(log *util.Logger, api Adapter) *Timer {
 	lp := &Timer{
 		log:     log,
+		clock:   clock.New(),
 		Adapter: api,
 	}
 
 	return lp
 }
 
+// SetClock replaces the realtime clock, e.g. for simulation
+func (lp *Timer) SetClock(clck clock.Clock) {
+	lp.clock = clck
+}
+
 // MustValidateDemand resets the flag for detecting if DemandActive has been called
 func (lp *Timer) MustValidateDemand() {
 	if lp == nil {
@@ -72,6 +83,8 @@ func (lp *Timer) Set(t time.Time) {
 	}
 
 	lp.Time = t
+	lp.start = time.Time{}
+	lp.duration = 0
 
 	if lp.Time.IsZero() {
 		lp.Publish("targetTime", nil)
@@ -106,30 +119,48 @@ func (lp *Timer) DemandActive() bool {
 		power *= lp.current / lp.GetMaxCurrent()
 	}
 
-	se := lp.SocEstimator()
-	if se == nil {
-		lp.log.WARN.Println("target charging: not possible")
-		return false
-	}
-
 	// time
-	remainingDuration := time.Duration(float64(se.AssumedChargeDuration(lp.SoC, power)) / chargeEfficiency)
-	lp.finishAt = time.Now().Add(remainingDuration).Round(time.Minute)
+	var remainingDuration time.Duration
+
+	if lp.Energy > 0 {
+		// energy is measured at the charger, no need to account for efficiency
+		whRemaining := math.Max(float64(lp.Energy)*1e3-lp.GetChargedEnergy(), 0)
+		remainingDuration = time.Duration(float64(time.Hour) * whRemaining / power).Round(time.Second)
+
+		lp.log.DEBUG.Printf("estimated charge duration: %v to %dkWh at %.0fW", remainingDuration.Round(time.Minute), lp.Energy, power)
+	} else {
+		se := lp.SocEstimator()
+		if se == nil {
+			lp.log.WARN.Println("target charging: not possible")
+			return false
+		}
 
-	lp.log.DEBUG.Printf("estimated charge duration: %v to %d%% at %.0fW", remainingDuration.Round(time.Minute), lp.SoC, power)
+		remainingDuration = time.Duration(float64(se.AssumedChargeDuration(lp.SoC, power)) / chargeEfficiency)
+
+		// stale soc must not shorten the plan
+		if lp.SocStale() && remainingDuration < lp.duration {
+			lp.log.DEBUG.Printf("vehicle soc stale: keeping estimated charge duration %v", lp.duration.Round(time.Minute))
+			remainingDuration = lp.duration
+		}
+		lp.duration = remainingDuration
+
+		lp.log.DEBUG.Printf("estimated charge duration: %v to %d%% at %.0fW", remainingDuration.Round(time.Minute), lp.SoC, power)
+	}
+
+	lp.finishAt = lp.clock.Now().Add(remainingDuration).Round(time.Minute)
 	if lp.active {
 		lp.log.DEBUG.Printf("projected end: %v", lp.finishAt)
 		lp.log.DEBUG.Printf("desired finish time: %v", lp.Time)
 		lp.Publish("targetTimeProjectedStart", nil)
 	} else {
-		projectedStart := lp.Time.Add(-remainingDuration)
-		lp.log.DEBUG.Printf("projected start: %v", projectedStart)
-		lp.Publish("targetTimeProjectedStart", projectedStart)
+		lp.start = lp.Time.Add(-remainingDuration)
+		lp.log.DEBUG.Printf("projected start: %v", lp.start)
+		lp.Publish("targetTimeProjectedStart", lp.start)
 	}
 
 	// timer charging is already active- only deactivate once charging has stopped
 	if lp.active {
-		if time.Now().After(lp.Time) && lp.GetStatus() != api.StatusC {
+		if lp.clock.Now().After(lp.Time) && lp.GetStatus() != api.StatusC {
 			lp.Stop()
 		}
 
@@ -148,6 +179,15 @@ func (lp *Timer) DemandActive() bool {
 	return lp.active
 }
 
+// ProjectedStart returns the projected start of target charging if not yet active
+func (lp *Timer) ProjectedStart() time.Time {
+	if lp == nil || lp.Time.IsZero() || lp.active {
+		return time.Time{}
+	}
+
+	return lp.start
+}
+
 // Handle adjusts current up/down to achieve desired target time taking.
 func (lp *Timer) Handle() float64 {
 	action := "steady"
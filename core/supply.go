@@ -0,0 +1,97 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/provider"
+	"golang.org/x/exp/slices"
+)
+
+// SharedSupplyConfig defines two loadpoints sharing a single supply line via a changeover contactor
+type SharedSupplyConfig struct {
+	Loadpoints []string        `mapstructure:"loadpoints"` // loadpoint titles
+	Switch     provider.Config `mapstructure:"switch"`     // changeover contactor, false selects first and true selects second loadpoint
+	Delay      time.Duration   `mapstructure:"delay"`      // safety delay between charger disable, changeover and charger enable
+}
+
+// sharedSupply grants a mutually exclusive group of loadpoints access to a shared supply line
+type sharedSupply struct {
+	mu         sync.Mutex
+	loadpoints []*LoadPoint
+	changeover func(bool) error
+	delay      time.Duration
+	active     *LoadPoint // loadpoint currently connected to the supply
+	switched   time.Time  // time of last changeover
+}
+
+// newSharedSupplyFromConfig creates a shared supply and attaches it to the referenced loadpoints
+func newSharedSupplyFromConfig(cc SharedSupplyConfig, loadpoints []*LoadPoint) (*sharedSupply, error) {
+	if len(cc.Loadpoints) != 2 {
+		return nil, fmt.Errorf("shared supply: need exactly two loadpoints, got %d", len(cc.Loadpoints))
+	}
+
+	changeover, err := provider.NewBoolSetterFromConfig("switch", cc.Switch)
+	if err != nil {
+		return nil, fmt.Errorf("shared supply: %w", err)
+	}
+
+	s := &sharedSupply{
+		changeover: changeover,
+		delay:      cc.Delay,
+	}
+
+	for _, title := range cc.Loadpoints {
+		idx := slices.IndexFunc(loadpoints, func(lp *LoadPoint) bool {
+			return lp.Title == title
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("shared supply: loadpoint not found: %s", title)
+		}
+
+		lp := loadpoints[idx]
+		if lp.supply != nil || slices.Contains(s.loadpoints, lp) {
+			return nil, fmt.Errorf("shared supply: loadpoint already assigned: %s", title)
+		}
+
+		s.loadpoints = append(s.loadpoints, lp)
+	}
+
+	for _, lp := range s.loadpoints {
+		lp.supply = s
+	}
+
+	return s, nil
+}
+
+// acquire connects the supply to the given loadpoint if no other loadpoint of the group is charging.
+// It returns true once the loadpoint is connected and the safety delay after changeover has elapsed.
+// It is called from the site's update loop and therefore accesses other loadpoints' state without locking.
+func (s *sharedSupply) acquire(lp *LoadPoint) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.active == lp {
+		return lp.clock.Since(s.switched) >= s.delay
+	}
+
+	// other loadpoints must have been disabled for at least the safety delay
+	for _, other := range s.loadpoints {
+		if other != lp && (other.enabled || lp.clock.Since(other.guardUpdated) < s.delay) {
+			return false
+		}
+	}
+
+	if err := s.changeover(s.loadpoints[1] == lp); err != nil {
+		lp.log.ERROR.Printf("shared supply: %v", err)
+		return false
+	}
+
+	lp.log.DEBUG.Printf("shared supply: changeover to %s", lp.Title)
+
+	s.active = lp
+	s.switched = lp.clock.Now()
+
+	return s.delay == 0
+}
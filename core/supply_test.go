@@ -0,0 +1,54 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedSupply(t *testing.T) {
+	clck := clock.NewMock()
+	dt := 5 * time.Second
+
+	lp1 := &LoadPoint{log: util.NewLogger("lp1"), clock: clck}
+	lp2 := &LoadPoint{log: util.NewLogger("lp2"), clock: clck}
+
+	var selected []bool
+	s := &sharedSupply{
+		loadpoints: []*LoadPoint{lp1, lp2},
+		changeover: func(b bool) error {
+			selected = append(selected, b)
+			return nil
+		},
+		delay: dt,
+	}
+
+	// initial changeover to first loadpoint
+	clck.Add(dt)
+	assert.False(t, s.acquire(lp1), "changeover delay")
+	assert.Equal(t, []bool{false}, selected)
+
+	clck.Add(dt)
+	assert.True(t, s.acquire(lp1))
+	lp1.enabled = true
+	lp1.guardUpdated = clck.Now()
+
+	// second loadpoint blocked while first is charging
+	assert.False(t, s.acquire(lp2))
+
+	// first loadpoint disabled, wait for safety delay
+	lp1.enabled = false
+	lp1.guardUpdated = clck.Now()
+	assert.False(t, s.acquire(lp2))
+
+	clck.Add(dt)
+	assert.False(t, s.acquire(lp2), "changeover delay")
+	assert.Equal(t, []bool{false, true}, selected)
+
+	clck.Add(dt)
+	assert.True(t, s.acquire(lp2))
+	assert.Equal(t, []bool{false, true}, selected)
+}
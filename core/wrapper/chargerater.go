@@ -33,6 +33,13 @@ func NewChargeRater(log *util.Logger, meter api.Meter) *ChargeRater {
 	}
 }
 
+// SetClock replaces the realtime clock, e.g. for simulation
+func (cr *ChargeRater) SetClock(clck clock.Clock) {
+	cr.Lock()
+	defer cr.Unlock()
+	cr.clck = clck
+}
+
 // StartCharge records meter start energy. If meter does not supply TotalEnergy,
 // start time is recorded and  charged energy set to zero.
 func (cr *ChargeRater) StartCharge(continued bool) {
@@ -25,6 +25,13 @@ func NewChargeTimer() *ChargeTimer {
 	}
 }
 
+// SetClock replaces the realtime clock, e.g. for simulation
+func (m *ChargeTimer) SetClock(clck clock.Clock) {
+	m.Lock()
+	defer m.Unlock()
+	m.clck = clck
+}
+
 // StartCharge signals charge timer start
 func (m *ChargeTimer) StartCharge(continued bool) {
 	m.Lock()
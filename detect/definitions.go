@@ -42,6 +42,27 @@ const (
 	// taskTPLink       = "tplink"
 )
 
+// Templates maps task ids to the configuration templates of devices identified by the task.
+// Cloud-only devices like Easee cannot be detected on the local network.
+var Templates = map[string][]string{
+	taskSMA:          {"sma-home-manager", "sma-energy-meter", "sma-inverter"},
+	taskKEBA:         {"keba"},
+	taskE3DC:         {"e3dc"},
+	taskSonnen:       {"sonnenbatterie"},
+	taskPowerwall:    {"tesla-powerwall"},
+	taskWallbe:       {"wallbe"},
+	taskPhoenixEMEth: {"phoenix-em-eth"},
+	taskPhoenixEVEth: {"phoenix-ev-eth"},
+	taskEVSEWifi:     {"evsewifi"},
+	taskGoE:          {"go-e", "go-e-v3", "go-e-gemini"},
+	taskOpenwb:       {"openwb"},
+	taskInverter:     {"sunspec-inverter"},
+	taskBattery:      {"sunspec-hybrid"},
+	taskFroniusWeb:   {"fronius-solarapi-v1"},
+	taskTasmota:      {"tasmota"},
+	taskShelly:       {"shelly", "shelly-1pm", "shelly-3em"},
+}
+
 func init() {
 	taskList.Add(tasks.Task{
 		ID:   TaskPing,
@@ -0,0 +1,267 @@
+package devicetest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/templates"
+)
+
+// Checks of the charger conformance suite
+const (
+	CheckEnableLatency  = "enablelatency"
+	CheckDisableLatency = "disablelatency"
+	CheckCurrentLatency = "currentlatency"
+	CheckPhaseSwitch    = "phaseswitch"
+	CheckStatusCodes    = "statuscodes"
+)
+
+// phaseThreshold is the current below which a phase is considered inactive
+const phaseThreshold = 1 // A
+
+// ConformanceOptions control the charger conformance suite
+type ConformanceOptions struct {
+	Current  int64         // initial current reduced to 6A for measuring current change latency, 10A if zero
+	Timeout  time.Duration // maximum latency for the charger to follow a command, 1m if zero
+	Interval time.Duration // polling interval, 1s if zero
+}
+
+// ConformanceReport is the scored conformance report of a charger
+type ConformanceReport struct {
+	Report
+	Score        int      `json:"score"`        // share of passed checks in percent
+	Capabilities []string `json:"capabilities"` // template capabilities confirmed by the checks
+}
+
+// conformance runs the checks of a single charger
+type conformance struct {
+	c        api.Charger
+	timeout  time.Duration
+	interval time.Duration
+}
+
+// poll waits for the condition to become true and returns the elapsed time
+func (cf *conformance) poll(cond func() (bool, error)) (time.Duration, error) {
+	start := time.Now()
+
+	for {
+		ok, err := cond()
+		if err != nil {
+			return 0, err
+		}
+
+		elapsed := time.Since(start)
+		if ok {
+			return elapsed, nil
+		}
+
+		if elapsed > cf.timeout {
+			return 0, fmt.Errorf("timeout after %v", cf.timeout)
+		}
+
+		time.Sleep(cf.interval)
+	}
+}
+
+// enable switches the charger and measures the latency until the enabled state is reported
+func (cf *conformance) enable(enable bool) (interface{}, error) {
+	if err := cf.c.Enable(enable); err != nil {
+		return nil, err
+	}
+
+	return cf.poll(func() (bool, error) {
+		enabled, err := cf.c.Enabled()
+		return enabled == enable, err
+	})
+}
+
+// currents returns the phase currents if the charger measures them and is charging
+func (cf *conformance) currents() (api.MeterCurrent, bool) {
+	mc, ok := cf.c.(api.MeterCurrent)
+	if !ok {
+		return nil, false
+	}
+
+	// wait for connected vehicle to start charging
+	_, err := cf.poll(func() (bool, error) {
+		status, err := cf.c.Status()
+		if err == nil && status == api.StatusA {
+			err = errors.New("no vehicle")
+		}
+		return status == api.StatusC, err
+	})
+
+	return mc, err == nil
+}
+
+// Conformance exercises the charger against the expected semantics. Charging requires a connected vehicle.
+// The enabled state is restored afterwards.
+func Conformance(name string, c api.Charger, o ConformanceOptions) ConformanceReport {
+	r := ConformanceReport{Report: Report{Name: name, Class: "charger"}}
+
+	cf := &conformance{
+		c:        c,
+		timeout:  o.Timeout,
+		interval: o.Interval,
+	}
+
+	if cf.timeout == 0 {
+		cf.timeout = time.Minute
+	}
+	if cf.interval == 0 {
+		cf.interval = time.Second
+	}
+
+	current := o.Current
+	if current == 0 {
+		current = 10
+	}
+
+	var enabled bool
+	if !r.run(CheckEnabled, func() (interface{}, error) {
+		var err error
+		enabled, err = c.Enabled()
+		return enabled, err
+	}) {
+		for _, check := range []string{CheckEnableLatency, CheckCurrentLatency, CheckPhaseSwitch, CheckDisableLatency, CheckStatusCodes} {
+			r.skip(check, "enabled state unknown")
+		}
+		r.score()
+		return r
+	}
+
+	defer func() { _ = c.Enable(enabled) }()
+
+	r.run(CheckMaxCurrent, func() (interface{}, error) {
+		return current, c.MaxCurrent(current)
+	})
+
+	r.run(CheckEnableLatency, func() (interface{}, error) {
+		return cf.enable(true)
+	})
+
+	// current change latency requires a charging vehicle
+	mc, charging := cf.currents()
+	if charging {
+		r.run(CheckCurrentLatency, func() (interface{}, error) {
+			if err := c.MaxCurrent(6); err != nil {
+				return nil, err
+			}
+
+			return cf.poll(func() (bool, error) {
+				l1, l2, l3, err := mc.Currents()
+				return math.Max(l1, math.Max(l2, l3)) <= 6+phaseThreshold, err
+			})
+		})
+	} else {
+		r.skip(CheckCurrentLatency, "no charging vehicle or current measurement")
+	}
+
+	if ps, ok := c.(api.PhaseSwitcher); ok {
+		r.run(CheckPhaseSwitch, func() (interface{}, error) {
+			if err := ps.Phases1p3p(1); err != nil {
+				return nil, err
+			}
+			defer func() { _ = ps.Phases1p3p(3) }()
+
+			if !charging {
+				return nil, nil
+			}
+
+			// phases 2 and 3 must become inactive
+			return cf.poll(func() (bool, error) {
+				_, l2, l3, err := mc.Currents()
+				return l2 < phaseThreshold && l3 < phaseThreshold, err
+			})
+		})
+	} else {
+		r.skip(CheckPhaseSwitch, notImplemented)
+	}
+
+	r.run(CheckDisableLatency, func() (interface{}, error) {
+		return cf.enable(false)
+	})
+
+	// disabled charger must report valid status and stop charging
+	r.run(CheckStatusCodes, func() (interface{}, error) {
+		var status api.ChargeStatus
+
+		_, err := cf.poll(func() (bool, error) {
+			var err error
+			if status, err = c.Status(); err != nil {
+				return false, err
+			}
+
+			switch status {
+			case api.StatusA, api.StatusB:
+				return true, nil
+			case api.StatusC:
+				return false, nil
+			default:
+				return false, fmt.Errorf("invalid status: %s", status)
+			}
+		})
+
+		if err != nil && status == api.StatusC {
+			err = errors.New("charging while disabled")
+		}
+
+		return status, err
+	})
+
+	if _, ok := c.(api.ChargerEx); ok {
+		r.Capabilities = append(r.Capabilities, templates.CapabilityMilliAmps)
+	}
+
+	if res, _ := r.Result(CheckPhaseSwitch); res.Status == StatusPass && charging {
+		r.Capabilities = append(r.Capabilities, templates.Capability1p3p)
+	}
+
+	if _, ok := c.(api.Identifier); ok {
+		r.Capabilities = append(r.Capabilities, templates.CapabilityRFID)
+	}
+
+	r.score()
+
+	return r
+}
+
+// score calculates the share of passed checks
+func (r *ConformanceReport) score() {
+	var passed, total int
+
+	for _, res := range r.Results {
+		if res.Status != StatusSkip {
+			total++
+		}
+		if res.Status == StatusPass {
+			passed++
+		}
+	}
+
+	if total > 0 {
+		r.Score = 100 * passed / total
+	}
+}
+
+// WriteText writes the report as human-readable table
+func (r *ConformanceReport) WriteText(w io.Writer) error {
+	if err := r.Report.WriteText(w); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\nscore: %d%%\ncapabilities: %v\n", r.Score, r.Capabilities)
+	return err
+}
+
+// WriteJSON writes the report as JSON
+func (r *ConformanceReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,79 @@
+package devicetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/stretchr/testify/assert"
+)
+
+// simCharger follows commands immediately while a vehicle is connected
+type simCharger struct {
+	enabled bool
+	current int64
+	phases  int
+	ignore  bool // keep charging when disabled
+}
+
+func (c *simCharger) Status() (api.ChargeStatus, error) {
+	if c.enabled || c.ignore {
+		return api.StatusC, nil
+	}
+	return api.StatusB, nil
+}
+
+func (c *simCharger) Enabled() (bool, error) {
+	return c.enabled, nil
+}
+
+func (c *simCharger) Enable(enable bool) error {
+	c.enabled = enable
+	return nil
+}
+
+func (c *simCharger) MaxCurrent(current int64) error {
+	c.current = current
+	return nil
+}
+
+func (c *simCharger) Phases1p3p(phases int) error {
+	c.phases = phases
+	return nil
+}
+
+func (c *simCharger) Currents() (float64, float64, float64, error) {
+	i := float64(c.current)
+	if c.phases == 1 {
+		return i, 0, 0, nil
+	}
+	return i, i, i, nil
+}
+
+func TestConformance(t *testing.T) {
+	o := ConformanceOptions{Timeout: 10 * time.Millisecond, Interval: time.Millisecond}
+
+	c := &simCharger{phases: 3}
+	r := Conformance("test", c, o)
+
+	for _, check := range []string{CheckEnabled, CheckMaxCurrent, CheckEnableLatency, CheckCurrentLatency, CheckPhaseSwitch, CheckDisableLatency, CheckStatusCodes} {
+		res, ok := r.Result(check)
+		assert.True(t, ok, check)
+		assert.Equal(t, StatusPass, res.Status, check)
+	}
+
+	assert.Equal(t, 100, r.Score)
+	assert.Equal(t, []string{templates.Capability1p3p}, r.Capabilities)
+	assert.False(t, c.enabled, "enabled state restored")
+	assert.Equal(t, 3, c.phases, "phases restored")
+
+	// charger ignoring disable
+	r = Conformance("test", &simCharger{phases: 3, ignore: true}, o)
+
+	res, _ := r.Result(CheckStatusCodes)
+	assert.Equal(t, StatusFail, res.Status)
+	assert.Equal(t, "charging while disabled", res.Error)
+	assert.Equal(t, 85, r.Score)
+	assert.False(t, r.Passed())
+}
@@ -0,0 +1,149 @@
+package devicetest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// Status is the outcome of a single check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip" // capability not implemented or not tested
+)
+
+// Checks of the capability matrix
+const (
+	CheckStatus     = "status"
+	CheckEnabled    = "enabled"
+	CheckEnable     = "enable"
+	CheckDisable    = "disable"
+	CheckMaxCurrent = "maxcurrent"
+	CheckPhases     = "phases"
+	CheckRestore    = "restore"
+	CheckIdentify   = "identify"
+	CheckPower      = "power"
+	CheckEnergy     = "energy"
+	CheckCurrents   = "currents"
+	CheckSoC        = "soc"
+	CheckRange      = "range"
+)
+
+// Result is the result of a single check
+type Result struct {
+	Check    string        `json:"check"`
+	Status   Status        `json:"status"`
+	Value    interface{}   `json:"value,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Report is the capability test report of a device
+type Report struct {
+	Name    string   `json:"name"`
+	Class   string   `json:"class"`
+	Results []Result `json:"results"`
+}
+
+// Options control which checks modify the device state
+type Options struct {
+	ReadOnly       bool  // skip checks changing the device state
+	Current        int64 // current for testing maxcurrent, 6A if zero
+	Phases         int   // phases for testing phase switching, 3 if zero
+	RestoreCurrent int64 // current restored after testing, e.g. the loadpoint's maximum current
+	RestorePhases  int   // phases restored after testing, e.g. the loadpoint's configured phases
+}
+
+// Result returns the result of the given check
+func (r *Report) Result(check string) (Result, bool) {
+	for _, res := range r.Results {
+		if res.Check == check {
+			return res, true
+		}
+	}
+	return Result{}, false
+}
+
+// Passed returns true if no check failed
+func (r *Report) Passed() bool {
+	for _, res := range r.Results {
+		if res.Status == StatusFail {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns the first failed check's error
+func (r *Report) Err() error {
+	for _, res := range r.Results {
+		if res.Status == StatusFail {
+			return fmt.Errorf("%s: %s", res.Check, res.Error)
+		}
+	}
+	return nil
+}
+
+// run executes and records the check
+func (r *Report) run(check string, fun func() (interface{}, error)) bool {
+	start := time.Now()
+	val, err := fun()
+
+	res := Result{
+		Check:    check,
+		Status:   StatusPass,
+		Value:    val,
+		Duration: time.Since(start),
+	}
+
+	if err != nil {
+		res.Status = StatusFail
+		res.Value = nil
+		res.Error = err.Error()
+
+		if errors.Is(err, api.ErrNotAvailable) {
+			res.Status = StatusSkip
+		}
+	}
+
+	r.Results = append(r.Results, res)
+
+	return res.Status == StatusPass
+}
+
+// skip records a skipped check
+func (r *Report) skip(check, reason string) {
+	r.Results = append(r.Results, Result{Check: check, Status: StatusSkip, Error: reason})
+}
+
+// WriteText writes the report as human-readable table
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "%s (%s)\n", r.Name, r.Class)
+	for _, res := range r.Results {
+		val := res.Error
+		if res.Status == StatusPass && res.Value != nil {
+			val = fmt.Sprintf("%v", res.Value)
+		}
+
+		fmt.Fprintf(tw, "%s:\t%s\t%s\t%v\n", res.Check, res.Status, val, res.Duration.Round(time.Millisecond))
+	}
+
+	return tw.Flush()
+}
+
+// WriteJSON writes the report as JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,185 @@
+package devicetest
+
+import (
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+const notImplemented = "not implemented"
+
+// Charger runs the charger capability matrix. The enabled state and, if given by the options,
+// the current and phases are restored afterwards.
+func Charger(name string, c api.Charger, o Options) Report {
+	r := Report{Name: name, Class: "charger"}
+
+	r.run(CheckStatus, func() (interface{}, error) {
+		return c.Status()
+	})
+
+	var enabled bool
+	ok := r.run(CheckEnabled, func() (interface{}, error) {
+		var err error
+		enabled, err = c.Enabled()
+		return enabled, err
+	})
+
+	if o.ReadOnly || !ok {
+		for _, check := range []string{CheckEnable, CheckDisable, CheckMaxCurrent, CheckPhases, CheckRestore} {
+			r.skip(check, "read-only")
+		}
+	} else {
+		current := o.Current
+		if current == 0 {
+			current = 6
+		}
+
+		r.run(CheckMaxCurrent, func() (interface{}, error) {
+			return current, c.MaxCurrent(current)
+		})
+
+		ps, switchable := c.(api.PhaseSwitcher)
+		if switchable {
+			phases := o.Phases
+			if phases == 0 {
+				phases = 3
+			}
+
+			r.run(CheckPhases, func() (interface{}, error) {
+				return phases, ps.Phases1p3p(phases)
+			})
+		} else {
+			r.skip(CheckPhases, notImplemented)
+		}
+
+		// restore previous settings
+		if o.RestoreCurrent > 0 || switchable && o.RestorePhases > 0 {
+			r.run(CheckRestore, func() (interface{}, error) {
+				if switchable && o.RestorePhases > 0 {
+					if err := ps.Phases1p3p(o.RestorePhases); err != nil {
+						return nil, err
+					}
+				}
+
+				if o.RestoreCurrent > 0 {
+					if err := c.MaxCurrent(o.RestoreCurrent); err != nil {
+						return nil, err
+					}
+				}
+
+				return nil, nil
+			})
+		} else {
+			r.skip(CheckRestore, "previous settings unknown")
+		}
+
+		// toggle and restore enabled state
+		for _, enable := range []bool{!enabled, enabled} {
+			check := CheckDisable
+			if enable {
+				check = CheckEnable
+			}
+
+			enable := enable
+			r.run(check, func() (interface{}, error) {
+				return nil, c.Enable(enable)
+			})
+		}
+	}
+
+	if id, ok := c.(api.Identifier); ok {
+		r.run(CheckIdentify, func() (interface{}, error) {
+			return id.Identify()
+		})
+	} else {
+		r.skip(CheckIdentify, notImplemented)
+	}
+
+	meter(&r, c)
+
+	return r
+}
+
+// Meter runs the meter capability matrix
+func Meter(name string, m api.Meter) Report {
+	r := Report{Name: name, Class: "meter"}
+	meter(&r, m)
+	return r
+}
+
+func meter(r *Report, v interface{}) {
+	if m, ok := v.(api.Meter); ok {
+		r.run(CheckPower, func() (interface{}, error) {
+			return m.CurrentPower()
+		})
+	} else {
+		r.skip(CheckPower, notImplemented)
+	}
+
+	if m, ok := v.(api.MeterEnergy); ok {
+		r.run(CheckEnergy, func() (interface{}, error) {
+			return m.TotalEnergy()
+		})
+	} else {
+		r.skip(CheckEnergy, notImplemented)
+	}
+
+	if m, ok := v.(api.MeterCurrent); ok {
+		r.run(CheckCurrents, func() (interface{}, error) {
+			i1, i2, i3, err := m.Currents()
+			return []float64{i1, i2, i3}, err
+		})
+	} else {
+		r.skip(CheckCurrents, notImplemented)
+	}
+
+	if m, ok := v.(api.Battery); ok {
+		r.run(CheckSoC, func() (interface{}, error) {
+			return soc(m)
+		})
+	} else {
+		r.skip(CheckSoC, notImplemented)
+	}
+}
+
+// Vehicle runs the vehicle capability matrix
+func Vehicle(name string, v api.Vehicle) Report {
+	r := Report{Name: name, Class: "vehicle"}
+
+	r.run(CheckSoC, func() (interface{}, error) {
+		return soc(v)
+	})
+
+	if vr, ok := v.(api.VehicleRange); ok {
+		r.run(CheckRange, func() (interface{}, error) {
+			return vr.Range()
+		})
+	} else {
+		r.skip(CheckRange, notImplemented)
+	}
+
+	if vs, ok := v.(api.ChargeState); ok {
+		r.run(CheckStatus, func() (interface{}, error) {
+			return vs.Status()
+		})
+	} else {
+		r.skip(CheckStatus, notImplemented)
+	}
+
+	return r
+}
+
+// soc waits up to 1m for the device to wake up
+func soc(b api.Battery) (float64, error) {
+	start := time.Now()
+
+	for {
+		soc, err := b.SoC()
+		if !errors.Is(err, api.ErrMustRetry) || time.Since(start) > time.Minute {
+			return soc, err
+		}
+
+		time.Sleep(3 * time.Second)
+	}
+}
@@ -0,0 +1,92 @@
+package devicetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	charger := mock.NewMockCharger(ctrl)
+	charger.EXPECT().Status().Return(api.StatusB, nil)
+	charger.EXPECT().Enabled().Return(false, nil)
+	charger.EXPECT().MaxCurrent(int64(6)).Return(nil)
+
+	gomock.InOrder(
+		charger.EXPECT().Enable(true).Return(nil),
+		charger.EXPECT().Enable(false).Return(errors.New("foo")),
+	)
+
+	r := Charger("test", charger, Options{})
+
+	expect := map[string]Status{
+		CheckStatus:     StatusPass,
+		CheckEnabled:    StatusPass,
+		CheckMaxCurrent: StatusPass,
+		CheckPhases:     StatusSkip,
+		CheckRestore:    StatusSkip,
+		CheckEnable:     StatusPass,
+		CheckDisable:    StatusFail,
+		CheckIdentify:   StatusSkip,
+		CheckPower:      StatusSkip,
+	}
+
+	for check, status := range expect {
+		res, ok := r.Result(check)
+		assert.True(t, ok, check)
+		assert.Equal(t, status, res.Status, check)
+	}
+
+	assert.False(t, r.Passed())
+	assert.EqualError(t, r.Err(), "disable: foo")
+}
+
+func TestChargerRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	charger := struct {
+		*mock.MockCharger
+		*mock.MockPhaseSwitcher
+	}{
+		mock.NewMockCharger(ctrl),
+		mock.NewMockPhaseSwitcher(ctrl),
+	}
+
+	charger.MockCharger.EXPECT().Status().Return(api.StatusB, nil)
+	charger.MockCharger.EXPECT().Enabled().Return(true, nil)
+
+	gomock.InOrder(
+		charger.MockCharger.EXPECT().MaxCurrent(int64(6)).Return(nil),
+		charger.MockPhaseSwitcher.EXPECT().Phases1p3p(3).Return(nil),
+		charger.MockPhaseSwitcher.EXPECT().Phases1p3p(1).Return(nil),
+		charger.MockCharger.EXPECT().MaxCurrent(int64(16)).Return(nil),
+		charger.MockCharger.EXPECT().Enable(false).Return(nil),
+		charger.MockCharger.EXPECT().Enable(true).Return(nil),
+	)
+
+	r := Charger("test", charger, Options{RestoreCurrent: 16, RestorePhases: 1})
+
+	res, _ := r.Result(CheckRestore)
+	assert.Equal(t, StatusPass, res.Status)
+	assert.True(t, r.Passed())
+}
+
+func TestChargerReadOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	charger := mock.NewMockCharger(ctrl)
+	charger.EXPECT().Status().Return(api.StatusA, nil)
+	charger.EXPECT().Enabled().Return(true, nil)
+
+	r := Charger("test", charger, Options{ReadOnly: true})
+
+	res, _ := r.Result(CheckEnable)
+	assert.Equal(t, StatusSkip, res.Status)
+	assert.True(t, r.Passed())
+}
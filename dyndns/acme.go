@@ -0,0 +1,321 @@
+package dyndns
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/mitchellh/go-homedir"
+	"golang.org/x/crypto/acme"
+)
+
+// Challenger publishes the ACME dns-01 challenge as TXT record of the domain's _acme-challenge subdomain
+type Challenger interface {
+	SetChallenge(value string) error
+	ClearChallenge() error
+}
+
+// ACMEConfig is the certificate configuration using the dns-01 challenge
+type ACMEConfig struct {
+	Email     string // account contact
+	Directory string // ACME directory, defaults to Let's Encrypt
+	Cache     string // directory for account key and certificate
+}
+
+const (
+	acmeCache   = "~/.evcc/acme"
+	acmeRenewal = 30 * 24 * time.Hour // renew certificates expiring within
+	acmeTimeout = 10 * time.Minute
+)
+
+// DNSPropagation is the delay for the challenge record becoming visible to the ACME server
+var DNSPropagation = time.Minute
+
+// Certificates obtains and renews the domain's certificate
+type Certificates struct {
+	mu     sync.RWMutex
+	client *acme.Client
+	ch     Challenger
+	domain string
+	email  string
+	cache  string
+	cert   *tls.Certificate
+}
+
+// NewCertificates creates a certificate manager using the updater for dns-01 challenges.
+// A previously obtained certificate is loaded from the cache.
+func NewCertificates(u Updater, domain string, cc ACMEConfig) (*Certificates, error) {
+	ch, ok := u.(Challenger)
+	if !ok {
+		return nil, errors.New("acme: dns challenge not supported")
+	}
+
+	if domain == "" {
+		return nil, errors.New("acme: missing domain")
+	}
+
+	if cc.Cache == "" {
+		cc.Cache = acmeCache
+	}
+
+	cache, err := homedir.Expand(cc.Cache)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(cache, 0o700); err != nil {
+		return nil, err
+	}
+
+	c := &Certificates{
+		ch:     ch,
+		domain: domain,
+		email:  cc.Email,
+		cache:  cache,
+	}
+
+	key, err := c.accountKey()
+	if err != nil {
+		return nil, fmt.Errorf("acme: %w", err)
+	}
+
+	if cc.Directory == "" {
+		cc.Directory = acme.LetsEncryptURL
+	}
+
+	c.client = &acme.Client{
+		Key:          key,
+		DirectoryURL: cc.Directory,
+		HTTPClient:   request.NewClient(log),
+		UserAgent:    "evcc",
+	}
+
+	if err := c.load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.WARN.Printf("acme: %v", err)
+	}
+
+	return c, nil
+}
+
+// accountKey loads or creates the ACME account key
+func (c *Certificates) accountKey() (*ecdsa.PrivateKey, error) {
+	file := filepath.Join(c.cache, "account.key")
+
+	if b, err := os.ReadFile(file); err == nil {
+		block, _ := pem.Decode(b)
+		if block == nil {
+			return nil, fmt.Errorf("invalid account key: %s", file)
+		}
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b}), 0o600)
+}
+
+// load reads the cached certificate
+func (c *Certificates) load() error {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(c.cache, "cert.pem"), filepath.Join(c.cache, "key.pem"))
+	if err != nil {
+		return err
+	}
+
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return err
+	}
+
+	if err := cert.Leaf.VerifyHostname(c.domain); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.cert = &cert
+	c.mu.Unlock()
+
+	return nil
+}
+
+// save writes the certificate chain and its key to the cache
+func (c *Certificates) save(der [][]byte, key *ecdsa.PrivateKey) error {
+	var certs []byte
+	for _, b := range der {
+		certs = append(certs, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: b})...)
+	}
+
+	b, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(c.cache, "key.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b}), 0o600); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(c.cache, "cert.pem"), certs, 0o600)
+}
+
+// GetCertificate provides the certificate for tls.Config
+func (c *Certificates) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.cert == nil {
+		return nil, fmt.Errorf("acme: no certificate for %s", c.domain)
+	}
+
+	return c.cert, nil
+}
+
+// due checks if the certificate is missing or expires soon
+func (c *Certificates) due(now time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cert == nil || now.Add(acmeRenewal).After(c.cert.Leaf.NotAfter)
+}
+
+// Run obtains the certificate and renews it before expiry
+func (c *Certificates) Run() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for ; true; <-ticker.C {
+		if !c.due(time.Now()) {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), acmeTimeout)
+		err := c.obtain(ctx)
+		cancel()
+
+		if err != nil {
+			log.ERROR.Printf("acme: %v", err)
+			continue
+		}
+
+		log.INFO.Printf("acme: certificate for %s obtained", c.domain)
+	}
+}
+
+// obtain orders a new certificate
+func (c *Certificates) obtain(ctx context.Context) error {
+	acct := new(acme.Account)
+	if c.email != "" {
+		acct.Contact = []string{"mailto:" + c.email}
+	}
+
+	if _, err := c.client.Register(ctx, acct, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return fmt.Errorf("register: %w", err)
+	}
+
+	order, err := c.client.AuthorizeOrder(ctx, acme.DomainIDs(c.domain))
+	if err != nil {
+		return fmt.Errorf("order: %w", err)
+	}
+
+	for _, uri := range order.AuthzURLs {
+		if err := c.authorize(ctx, uri); err != nil {
+			return err
+		}
+	}
+
+	if order, err = c.client.WaitOrder(ctx, order.URI); err != nil {
+		return fmt.Errorf("order: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: []string{c.domain}}, key)
+	if err != nil {
+		return err
+	}
+
+	der, _, err := c.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return fmt.Errorf("certificate: %w", err)
+	}
+
+	if err := c.save(der, key); err != nil {
+		return err
+	}
+
+	return c.load()
+}
+
+// authorize completes the dns-01 challenge of the authorization
+func (c *Certificates) authorize(ctx context.Context, uri string) error {
+	authz, err := c.client.GetAuthorization(ctx, uri)
+	if err != nil {
+		return fmt.Errorf("authorization: %w", err)
+	}
+
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var chal *acme.Challenge
+	for _, ch := range authz.Challenges {
+		if ch.Type == "dns-01" {
+			chal = ch
+			break
+		}
+	}
+
+	if chal == nil {
+		return errors.New("authorization: dns-01 challenge not offered")
+	}
+
+	value, err := c.client.DNS01ChallengeRecord(chal.Token)
+	if err != nil {
+		return err
+	}
+
+	if err := c.ch.SetChallenge(value); err != nil {
+		return fmt.Errorf("set challenge: %w", err)
+	}
+
+	defer func() {
+		if err := c.ch.ClearChallenge(); err != nil {
+			log.WARN.Printf("acme: clear challenge: %v", err)
+		}
+	}()
+
+	select {
+	case <-time.After(DNSPropagation):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if _, err := c.client.Accept(ctx, chal); err != nil {
+		return fmt.Errorf("challenge: %w", err)
+	}
+
+	if _, err := c.client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("authorization: %w", err)
+	}
+
+	return nil
+}
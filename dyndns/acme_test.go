@@ -0,0 +1,69 @@
+package dyndns
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ipUpdater struct{}
+
+func (u *ipUpdater) Update(string) error { return nil }
+
+func TestCertificatesCache(t *testing.T) {
+	_, err := NewCertificates(new(ipUpdater), "home.duckdns.org", ACMEConfig{Cache: t.TempDir()})
+	assert.Error(t, err, "challenge not supported")
+
+	u, err := NewDuckDNS("foo", "home.duckdns.org")
+	require.NoError(t, err)
+
+	cc := ACMEConfig{Cache: t.TempDir()}
+	c, err := NewCertificates(u, "home.duckdns.org", cc)
+	require.NoError(t, err)
+
+	_, err = c.GetCertificate(nil)
+	assert.Error(t, err)
+	assert.True(t, c.due(time.Now()))
+
+	// self-signed certificate
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "home.duckdns.org"},
+		DNSNames:     []string{"home.duckdns.org"},
+		NotBefore:    now,
+		NotAfter:     now.Add(90 * 24 * time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	require.NoError(t, c.save([][]byte{der}, key))
+
+	// loaded from cache with same account key
+	c2, err := NewCertificates(u, "home.duckdns.org", cc)
+	require.NoError(t, err)
+	assert.Equal(t, c.client.Key, c2.client.Key)
+
+	cert, err := c2.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, der, cert.Certificate[0])
+
+	assert.False(t, c2.due(now))
+	assert.True(t, c2.due(now.Add(70*24*time.Hour)))
+
+	// cached certificate of other domain is ignored
+	c3, err := NewCertificates(u, "other.duckdns.org", cc)
+	require.NoError(t, err)
+	assert.True(t, c3.due(now))
+}
@@ -0,0 +1,180 @@
+package dyndns
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/evcc-io/evcc/util/request"
+	"golang.org/x/oauth2"
+)
+
+const cloudflareURI = "https://api.cloudflare.com/client/v4"
+
+// Cloudflare implements the cloudflare.com dns record updater
+type Cloudflare struct {
+	*request.Helper
+	zone, domain string
+	record       string // record id
+	challenge    string // acme challenge record id
+}
+
+type cloudflareConfig struct {
+	Token, Zone, Domain string
+}
+
+type cloudflareRecord struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	TTL     int    `json:"ttl,omitempty"`
+}
+
+type cloudflareResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result []cloudflareRecord `json:"result"`
+}
+
+func (r cloudflareResponse) error() error {
+	if r.Success {
+		return nil
+	}
+
+	var msgs []string
+	for _, e := range r.Errors {
+		msgs = append(msgs, e.Message)
+	}
+
+	return fmt.Errorf("cloudflare: %s", strings.Join(msgs, ", "))
+}
+
+// NewCloudflare creates new Cloudflare updater using an api token with dns edit permission
+func NewCloudflare(token, zone, domain string) (*Cloudflare, error) {
+	if token == "" || zone == "" || domain == "" {
+		return nil, errors.New("cloudflare: missing token, zone or domain")
+	}
+
+	u := &Cloudflare{
+		Helper: request.NewHelper(log),
+		zone:   zone,
+		domain: domain,
+	}
+
+	u.Client.Transport = &oauth2.Transport{
+		Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}),
+		Base:   u.Client.Transport,
+	}
+
+	return u, nil
+}
+
+// recordID finds the domain's A record
+func (u *Cloudflare) recordID() (string, error) {
+	var res cloudflareResponse
+
+	uri := fmt.Sprintf("%s/zones/%s/dns_records?type=A&name=%s", cloudflareURI, u.zone, url.QueryEscape(u.domain))
+	if err := u.GetJSON(uri, &res); err != nil {
+		return "", err
+	}
+
+	if err := res.error(); err != nil {
+		return "", err
+	}
+
+	if len(res.Result) == 0 {
+		return "", fmt.Errorf("cloudflare: record not found: %s", u.domain)
+	}
+
+	return res.Result[0].ID, nil
+}
+
+// Update implements the Updater interface
+func (u *Cloudflare) Update(ip string) error {
+	if u.record == "" {
+		id, err := u.recordID()
+		if err != nil {
+			return err
+		}
+		u.record = id
+	}
+
+	data := cloudflareRecord{
+		Type:    "A",
+		Name:    u.domain,
+		Content: ip,
+	}
+
+	uri := fmt.Sprintf("%s/zones/%s/dns_records/%s", cloudflareURI, u.zone, u.record)
+	req, err := request.New(http.MethodPut, uri, request.MarshalJSON(data), request.JSONEncoding)
+	if err != nil {
+		return err
+	}
+
+	var res struct {
+		cloudflareResponse
+		Result cloudflareRecord `json:"result"`
+	}
+
+	if err := u.DoJSON(req, &res); err != nil {
+		return err
+	}
+
+	return res.error()
+}
+
+// SetChallenge implements the Challenger interface
+func (u *Cloudflare) SetChallenge(value string) error {
+	data := cloudflareRecord{
+		Type:    "TXT",
+		Name:    "_acme-challenge." + u.domain,
+		Content: value,
+		TTL:     120,
+	}
+
+	uri := fmt.Sprintf("%s/zones/%s/dns_records", cloudflareURI, u.zone)
+	req, err := request.New(http.MethodPost, uri, request.MarshalJSON(data), request.JSONEncoding)
+	if err != nil {
+		return err
+	}
+
+	var res struct {
+		cloudflareResponse
+		Result cloudflareRecord `json:"result"`
+	}
+
+	if err := u.DoJSON(req, &res); err != nil {
+		return err
+	}
+
+	u.challenge = res.Result.ID
+
+	return res.error()
+}
+
+// ClearChallenge implements the Challenger interface
+func (u *Cloudflare) ClearChallenge() error {
+	if u.challenge == "" {
+		return nil
+	}
+
+	uri := fmt.Sprintf("%s/zones/%s/dns_records/%s", cloudflareURI, u.zone, u.challenge)
+	req, err := request.New(http.MethodDelete, uri, nil, request.AcceptJSON)
+	if err != nil {
+		return err
+	}
+
+	var res cloudflareResponse
+	if err := u.DoJSON(req, &res); err != nil {
+		return err
+	}
+
+	u.challenge = ""
+
+	return res.error()
+}
@@ -0,0 +1,94 @@
+package dyndns
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// Updater updates a dynamic dns record
+type Updater interface {
+	Update(ip string) error
+}
+
+var log = util.NewLogger("dyndns")
+
+// PublicAddressURI is the service returning the public ip address
+var PublicAddressURI = "https://api.ipify.org"
+
+// NewUpdaterFromConfig creates a new dynamic dns updater
+func NewUpdaterFromConfig(typ string, other map[string]interface{}) (res Updater, err error) {
+	switch strings.ToLower(typ) {
+	case "cloudflare":
+		var cc cloudflareConfig
+		if err = util.DecodeOther(other, &cc); err == nil {
+			res, err = NewCloudflare(cc.Token, cc.Zone, cc.Domain)
+		}
+	case "desec":
+		var cc tokenConfig
+		if err = util.DecodeOther(other, &cc); err == nil {
+			res, err = NewDeSEC(cc.Token, cc.Domain)
+		}
+	case "duckdns":
+		var cc tokenConfig
+		if err = util.DecodeOther(other, &cc); err == nil {
+			res, err = NewDuckDNS(cc.Token, cc.Domain)
+		}
+	default:
+		err = fmt.Errorf("unknown dyndns type: %s", typ)
+	}
+
+	return res, err
+}
+
+type tokenConfig struct {
+	Token, Domain string
+}
+
+// publicAddress returns the public ipv4 address
+func publicAddress(helper *request.Helper) (string, error) {
+	b, err := helper.GetBody(PublicAddressURI)
+	if err != nil {
+		return "", err
+	}
+
+	ip := net.ParseIP(strings.TrimSpace(string(b)))
+	if ip == nil || ip.To4() == nil {
+		return "", fmt.Errorf("invalid address: %s", b)
+	}
+
+	return ip.String(), nil
+}
+
+// Run updates the dns record whenever the public address changes
+func Run(u Updater, interval time.Duration) {
+	helper := request.NewHelper(log)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var current string
+	for ; true; <-ticker.C {
+		ip, err := publicAddress(helper)
+		if err != nil {
+			log.ERROR.Printf("public address: %v", err)
+			continue
+		}
+
+		if ip == current {
+			continue
+		}
+
+		if err := u.Update(ip); err != nil {
+			log.ERROR.Printf("update: %v", err)
+			continue
+		}
+
+		log.INFO.Printf("updated address: %s", ip)
+		current = ip
+	}
+}
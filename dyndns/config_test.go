@@ -0,0 +1,65 @@
+package dyndns
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicAddress(t *testing.T) {
+	var res string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, res)
+	}))
+	defer srv.Close()
+
+	PublicAddressURI = srv.URL
+	helper := request.NewHelper(log)
+
+	res = "203.0.113.7"
+	ip, err := publicAddress(helper)
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.7", ip)
+
+	for _, res = range []string{"", "2001:db8::1", "<html>"} {
+		_, err := publicAddress(helper)
+		assert.Error(t, err, res)
+	}
+}
+
+func TestNewUpdaterFromConfig(t *testing.T) {
+	_, err := NewUpdaterFromConfig("duckdns", map[string]interface{}{"token": "foo", "domain": "home.duckdns.org"})
+	require.NoError(t, err)
+
+	_, err = NewUpdaterFromConfig("cloudflare", map[string]interface{}{"token": "foo", "domain": "home.example.com"})
+	assert.Error(t, err, "missing zone")
+
+	_, err = NewUpdaterFromConfig("foo", nil)
+	assert.Error(t, err)
+}
+
+func TestDuckDNSChallenge(t *testing.T) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprintln(w, "OK")
+	}))
+	defer srv.Close()
+
+	u, err := NewDuckDNS("foo", "home.duckdns.org")
+	require.NoError(t, err)
+	u.uri = srv.URL
+
+	require.NoError(t, u.SetChallenge("bar"))
+	assert.Equal(t, "home", query.Get("domains"))
+	assert.Equal(t, "bar", query.Get("txt"))
+
+	require.NoError(t, u.ClearChallenge())
+	assert.Equal(t, "true", query.Get("clear"))
+}
@@ -0,0 +1,94 @@
+package dyndns
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// DeSEC implements the desec.io updater
+type DeSEC struct {
+	*request.Helper
+	token, domain string
+}
+
+// NewDeSEC creates new deSEC updater
+func NewDeSEC(token, domain string) (*DeSEC, error) {
+	if token == "" || domain == "" {
+		return nil, errors.New("desec: missing token or domain")
+	}
+
+	u := &DeSEC{
+		Helper: request.NewHelper(log),
+		token:  token,
+		domain: domain,
+	}
+
+	return u, nil
+}
+
+// Update implements the Updater interface
+func (u *DeSEC) Update(ip string) error {
+	uri := fmt.Sprintf("https://update.dedyn.io/?hostname=%s&myipv4=%s", url.QueryEscape(u.domain), ip)
+
+	req, err := request.New(http.MethodGet, uri, nil, map[string]string{
+		"Authorization": "Token " + u.token,
+	})
+	if err != nil {
+		return err
+	}
+
+	b, err := u.DoBody(req)
+	if err == nil && !strings.HasPrefix(strings.TrimSpace(string(b)), "good") {
+		err = fmt.Errorf("desec: %s", b)
+	}
+
+	return err
+}
+
+// desecRRSet is a deSEC resource record set
+type desecRRSet struct {
+	Subname string   `json:"subname"`
+	Type    string   `json:"type"`
+	TTL     int      `json:"ttl"`
+	Records []string `json:"records"`
+}
+
+// SetChallenge implements the Challenger interface
+func (u *DeSEC) SetChallenge(value string) error {
+	return u.challenge([]string{strconv.Quote(value)})
+}
+
+// ClearChallenge implements the Challenger interface
+func (u *DeSEC) ClearChallenge() error {
+	return u.challenge([]string{})
+}
+
+// challenge replaces the acme challenge record set, empty records delete it
+func (u *DeSEC) challenge(records []string) error {
+	uri := fmt.Sprintf("https://desec.io/api/v1/domains/%s/rrsets/", url.PathEscape(u.domain))
+
+	data := []desecRRSet{{
+		Subname: "_acme-challenge",
+		Type:    "TXT",
+		TTL:     3600, // deSEC minimum
+		Records: records,
+	}}
+
+	req, err := request.New(http.MethodPut, uri, request.MarshalJSON(data), map[string]string{
+		"Authorization": "Token " + u.token,
+		"Content-Type":  request.JSONContent,
+		"Accept":        request.JSONContent,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = u.DoBody(req)
+	return err
+}
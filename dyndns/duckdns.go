@@ -0,0 +1,68 @@
+package dyndns
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/evcc-io/evcc/util/request"
+)
+
+const duckDNSURI = "https://www.duckdns.org/update"
+
+// DuckDNS implements the duckdns.org updater
+type DuckDNS struct {
+	*request.Helper
+	uri           string
+	token, domain string
+}
+
+// NewDuckDNS creates new DuckDNS updater
+func NewDuckDNS(token, domain string) (*DuckDNS, error) {
+	if token == "" || domain == "" {
+		return nil, errors.New("duckdns: missing token or domain")
+	}
+
+	u := &DuckDNS{
+		Helper: request.NewHelper(log),
+		uri:    duckDNSURI,
+		token:  token,
+		domain: strings.TrimSuffix(domain, ".duckdns.org"),
+	}
+
+	return u, nil
+}
+
+// Update implements the Updater interface
+func (u *DuckDNS) Update(ip string) error {
+	uri := fmt.Sprintf("%s?domains=%s&token=%s&ip=%s",
+		u.uri, url.QueryEscape(u.domain), url.QueryEscape(u.token), ip)
+
+	return u.get(uri)
+}
+
+// SetChallenge implements the Challenger interface
+func (u *DuckDNS) SetChallenge(value string) error {
+	uri := fmt.Sprintf("%s?domains=%s&token=%s&txt=%s",
+		u.uri, url.QueryEscape(u.domain), url.QueryEscape(u.token), url.QueryEscape(value))
+
+	return u.get(uri)
+}
+
+// ClearChallenge implements the Challenger interface
+func (u *DuckDNS) ClearChallenge() error {
+	uri := fmt.Sprintf("%s?domains=%s&token=%s&txt=&clear=true",
+		u.uri, url.QueryEscape(u.domain), url.QueryEscape(u.token))
+
+	return u.get(uri)
+}
+
+func (u *DuckDNS) get(uri string) error {
+	b, err := u.GetBody(uri)
+	if err == nil && strings.TrimSpace(string(b)) != "OK" {
+		err = fmt.Errorf("duckdns: %s", b)
+	}
+
+	return err
+}
@@ -0,0 +1,47 @@
+package forecaster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// Config is the typed forecaster configuration
+type Config struct {
+	Type  string
+	Other map[string]interface{} `mapstructure:",remain"`
+}
+
+type forecasterRegistry map[string]func(map[string]interface{}) (api.Forecaster, error)
+
+func (r forecasterRegistry) Add(name string, factory func(map[string]interface{}) (api.Forecaster, error)) {
+	if _, exists := r[name]; exists {
+		panic(fmt.Sprintf("cannot register duplicate forecaster type: %s", name))
+	}
+	r[name] = factory
+}
+
+func (r forecasterRegistry) Get(name string) (func(map[string]interface{}) (api.Forecaster, error), error) {
+	factory, exists := r[name]
+	if !exists {
+		return nil, fmt.Errorf("forecaster type not registered: %s", name)
+	}
+	return factory, nil
+}
+
+var registry forecasterRegistry = make(map[string]func(map[string]interface{}) (api.Forecaster, error))
+
+// NewFromConfig creates forecaster from configuration
+func NewFromConfig(typ string, other map[string]interface{}) (f api.Forecaster, err error) {
+	factory, err := registry.Get(strings.ToLower(typ))
+	if err == nil {
+		if f, err = factory(other); err != nil {
+			err = fmt.Errorf("cannot create forecaster '%s': %w", typ, err)
+		}
+	} else {
+		err = fmt.Errorf("invalid forecaster type: %s", typ)
+	}
+
+	return
+}
@@ -0,0 +1,81 @@
+package forecaster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Custom is a forecast read from a plugin, e.g. a script or http api serving a machine learning model.
+// The plugin returns a json list of slots:
+//
+//	[{"start": "2022-10-01T12:00:00Z", "end": "2022-10-01T13:00:00Z", "value": 1200}]
+type Custom struct {
+	forecastG func() (string, error)
+}
+
+func init() {
+	registry.Add(api.Custom, NewCustomFromConfig)
+}
+
+// NewCustomFromConfig creates custom forecaster from generic config
+func NewCustomFromConfig(other map[string]interface{}) (api.Forecaster, error) {
+	cc := struct {
+		Forecast provider.Config
+		Cache    time.Duration
+	}{
+		Cache: 15 * time.Minute,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.Forecast.Source == "" {
+		return nil, errors.New("missing forecast")
+	}
+
+	forecastG, err := provider.NewStringGetterFromConfig(cc.Forecast)
+	if err != nil {
+		return nil, fmt.Errorf("forecast: %w", err)
+	}
+
+	return &Custom{
+		forecastG: provider.Cached(forecastG, cc.Cache),
+	}, nil
+}
+
+var _ api.Forecaster = (*Custom)(nil)
+
+// Forecast implements the api.Forecaster interface
+func (f *Custom) Forecast() ([]api.ForecastValue, error) {
+	s, err := f.forecastG()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []struct {
+		Start time.Time `json:"start"`
+		End   time.Time `json:"end"`
+		Value float64   `json:"value"`
+	}
+
+	if err := json.Unmarshal([]byte(s), &res); err != nil {
+		return nil, fmt.Errorf("invalid forecast: %w", err)
+	}
+
+	slots := make([]api.ForecastValue, 0, len(res))
+	for _, r := range res {
+		if !r.End.After(r.Start) {
+			return nil, fmt.Errorf("invalid forecast slot: %v-%v", r.Start, r.End)
+		}
+		slots = append(slots, api.ForecastValue{Start: r.Start, End: r.End, Value: r.Value})
+	}
+
+	return slots, nil
+}
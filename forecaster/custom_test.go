@@ -0,0 +1,33 @@
+package forecaster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustom(t *testing.T) {
+	var s string
+	f := &Custom{forecastG: func() (string, error) { return s, nil }}
+
+	s = `[{"start": "2022-10-01T12:00:00Z", "end": "2022-10-01T13:00:00Z", "value": 1200}]`
+	res, err := f.Forecast()
+	require.NoError(t, err)
+
+	start := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, []api.ForecastValue{{Start: start, End: start.Add(time.Hour), Value: 1200}}, res)
+
+	s = `[{"start": "2022-10-01T13:00:00Z", "end": "2022-10-01T12:00:00Z", "value": 1200}]`
+	_, err = f.Forecast()
+	assert.Error(t, err, "end before start")
+
+	s = `{}`
+	_, err = f.Forecast()
+	assert.Error(t, err)
+
+	_, err = NewFromConfig("custom", nil)
+	assert.Error(t, err, "missing forecast")
+}
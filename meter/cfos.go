@@ -94,3 +94,10 @@ func (wb *CfosPowerBrain) TotalEnergy() (float64, error) {
 
 // 	return currents[0], currents[1], currents[2], nil
 // }
+
+var _ api.Closer = (*CfosPowerBrain)(nil)
+
+// Close implements the api.Closer interface
+func (wb *CfosPowerBrain) Close() error {
+	return wb.conn.Close()
+}
@@ -0,0 +1,54 @@
+package meter
+
+import (
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/chaos"
+)
+
+// Faulty injects the scenario's meter faults into the named meter's readings, native and plugin meters alike.
+// The meter is returned unchanged if fault injection is disabled.
+func Faulty(name string, m api.Meter) api.Meter {
+	if !chaos.Enabled() {
+		return m
+	}
+
+	meter, _ := NewConfigurable(chaos.Float(name, m.CurrentPower))
+
+	// decorate energy reading
+	var totalEnergy func() (float64, error)
+	if m, ok := m.(api.MeterEnergy); ok {
+		totalEnergy = chaos.Float(name, m.TotalEnergy)
+	}
+
+	// decorate battery reading
+	var batterySoC func() (float64, error)
+	if m, ok := m.(api.Battery); ok {
+		batterySoC = chaos.Float(name, m.SoC)
+	}
+
+	// decorate currents reading
+	var currents func() (float64, float64, float64, error)
+	if m, ok := m.(api.MeterCurrent); ok {
+		currents = m.Currents
+	}
+
+	// decorate frequency reading
+	var frequency func() (float64, error)
+	if m, ok := m.(api.MeterFrequency); ok {
+		frequency = chaos.Float(name, m.Frequency)
+	}
+
+	// decorate island detection
+	var island func() (bool, error)
+	if m, ok := m.(api.MeterIsland); ok {
+		island = chaos.Getter(name, m.Island)
+	}
+
+	// forward closing the wrapped meter
+	var close func() error
+	if m, ok := m.(api.Closer); ok {
+		close = m.Close
+	}
+
+	return meter.Decorate(totalEnergy, currents, batterySoC, frequency, island, close)
+}
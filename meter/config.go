@@ -28,6 +28,11 @@ var registry meterRegistry = make(map[string]func(map[string]interface{}) (api.M
 
 // NewFromConfig creates meter from configuration
 func NewFromConfig(typ string, other map[string]interface{}) (v api.Meter, err error) {
+	invert, other, err := invertConfig(other)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create meter '%s': %w", typ, err)
+	}
+
 	factory, err := registry.Get(strings.ToLower(typ))
 	if err == nil {
 		if v, err = factory(other); err != nil {
@@ -37,5 +42,9 @@ func NewFromConfig(typ string, other map[string]interface{}) (v api.Meter, err e
 		err = fmt.Errorf("invalid meter type: %s", typ)
 	}
 
+	if err == nil && invert {
+		v = inverted(v)
+	}
+
 	return
 }
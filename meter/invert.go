@@ -0,0 +1,74 @@
+package meter
+
+import (
+	"context"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// invertConfig splits the generic invert option from the meter configuration
+func invertConfig(other map[string]interface{}) (bool, map[string]interface{}, error) {
+	var cc struct {
+		Invert bool
+		Other  map[string]interface{} `mapstructure:",remain"`
+	}
+
+	err := util.DecodeOther(other, &cc)
+
+	return cc.Invert, cc.Other, err
+}
+
+// inverted reverses the power direction of a meter with opposite sign convention, e.g. grid meters reporting export as positive
+func inverted(m api.Meter) api.Meter {
+	meter, _ := NewConfigurable(func() (float64, error) {
+		power, err := m.CurrentPower()
+		return -power, err
+	})
+
+	// keep power requests cancellable
+	if mc, ok := m.(api.MeterContext); ok {
+		meter, _ = NewConfigurableContext(func(ctx context.Context) (float64, error) {
+			power, err := mc.CurrentPowerContext(ctx)
+			return -power, err
+		})
+	}
+
+	// decorate energy reading
+	var totalEnergy func() (float64, error)
+	if m, ok := m.(api.MeterEnergy); ok {
+		totalEnergy = m.TotalEnergy
+	}
+
+	// decorate battery reading
+	var batterySoC func() (float64, error)
+	if m, ok := m.(api.Battery); ok {
+		batterySoC = m.SoC
+	}
+
+	// decorate currents reading
+	var currents func() (float64, float64, float64, error)
+	if m, ok := m.(api.MeterCurrent); ok {
+		currents = m.Currents
+	}
+
+	// decorate frequency reading
+	var frequency func() (float64, error)
+	if m, ok := m.(api.MeterFrequency); ok {
+		frequency = m.Frequency
+	}
+
+	// decorate island detection
+	var island func() (bool, error)
+	if m, ok := m.(api.MeterIsland); ok {
+		island = m.Island
+	}
+
+	// forward closing the wrapped meter
+	var close func() error
+	if m, ok := m.(api.Closer); ok {
+		close = m.Close
+	}
+
+	return meter.Decorate(totalEnergy, currents, batterySoC, frequency, island, close)
+}
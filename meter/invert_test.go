@@ -0,0 +1,55 @@
+package meter
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+func TestInvertConfig(t *testing.T) {
+	invert, other, err := invertConfig(map[string]interface{}{"template": "foo", "Invert": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !invert || len(other) != 1 || other["template"] != "foo" {
+		t.Errorf("unexpected invert: %v, other: %v", invert, other)
+	}
+}
+
+func TestInverted(t *testing.T) {
+	m, _ := NewConfigurable(func() (float64, error) { return 1000, nil })
+	energy := func() (float64, error) { return 42, nil }
+
+	res := inverted(m.Decorate(energy, nil, nil, nil, nil, nil))
+
+	if power, err := res.CurrentPower(); err != nil || power != -1000 {
+		t.Errorf("expected -1000W, got %.0f %v", power, err)
+	}
+
+	if m, ok := res.(api.MeterEnergy); !ok {
+		t.Error("missing energy")
+	} else if f, err := m.TotalEnergy(); err != nil || f != 42 {
+		t.Errorf("expected 42kWh, got %.0f %v", f, err)
+	}
+
+	if _, ok := res.(api.Battery); ok {
+		t.Error("unexpected battery")
+	}
+}
+
+func TestInvertedCloser(t *testing.T) {
+	m, _ := NewConfigurable(func() (float64, error) { return 1000, nil })
+
+	var closed bool
+	res := inverted(m.Decorate(nil, nil, nil, nil, nil, func() error {
+		closed = true
+		return nil
+	}))
+
+	if c, ok := res.(api.Closer); !ok {
+		t.Error("missing closer")
+	} else if err := c.Close(); err != nil || !closed {
+		t.Errorf("expected closed, got %v", err)
+	}
+}
@@ -0,0 +1,328 @@
+package meter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/meter/tic"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/grid-x/serial"
+)
+
+// Linky meter implementation reading the TIC interface of french Linky meters
+type Linky struct {
+	mu       sync.Mutex
+	log      *util.Logger
+	addr     string
+	device   string
+	baudrate int
+	mode     tic.Mode
+	timeout  time.Duration
+	stop     chan struct{}
+	once     sync.Once
+	frame    tic.Frame
+	updated  time.Time
+}
+
+func init() {
+	registry.Add("linky", NewLinkyFromConfig)
+}
+
+//go:generate go run ../cmd/tools/decorate.go -f decorateLinky -b *Linky -r api.Meter -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)"
+
+// NewLinkyFromConfig creates a Linky meter from generic config
+func NewLinkyFromConfig(other map[string]interface{}) (api.Meter, error) {
+	cc := struct {
+		URI      string
+		Device   string
+		Baudrate int
+		Mode     string
+		Timeout  time.Duration
+	}{
+		Timeout: 15 * time.Second,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if (cc.URI == "") == (cc.Device == "") {
+		return nil, errors.New("need either uri or device")
+	}
+
+	var mode tic.Mode
+	switch strings.ToLower(cc.Mode) {
+	case "", "historic":
+		mode = tic.Historic
+	case "standard":
+		mode = tic.Standard
+	default:
+		return nil, fmt.Errorf("invalid mode: %s", cc.Mode)
+	}
+
+	return NewLinky(cc.URI, cc.Device, cc.Baudrate, mode, cc.Timeout)
+}
+
+// NewLinky creates Linky meter
+func NewLinky(uri, device string, baudrate int, mode tic.Mode, timeout time.Duration) (api.Meter, error) {
+	if baudrate == 0 {
+		baudrate = mode.Baudrate()
+	}
+
+	m := &Linky{
+		log:      util.NewLogger("linky"),
+		addr:     uri,
+		device:   device,
+		baudrate: baudrate,
+		mode:     mode,
+		timeout:  timeout,
+	}
+
+	// historic mode only reports the apparent power drawn from the grid
+	if mode == tic.Historic {
+		m.log.WARN.Println("historic mode does not report export, producers must use standard mode")
+	}
+
+	conn, err := m.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{}, 1)
+	m.stop = make(chan struct{})
+
+	go m.run(conn, done, m.stop)
+
+	// wait for initial value
+	select {
+	case <-done:
+	case <-time.NewTimer(timeout).C:
+		m.Close()
+		return nil, os.ErrDeadlineExceeded
+	}
+
+	// decorate three-phase currents
+	var currents func() (float64, float64, float64, error)
+
+	for _, label := range m.currentLabels() {
+		_, err = m.get(label)
+		if err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		currents = m.currents
+	}
+
+	return decorateLinky(m, currents), nil
+}
+
+// run reads frames until stopped, reconnecting on connection errors
+func (m *Linky) run(conn io.ReadCloser, done, stop chan struct{}) {
+	// unblock reading once stopped
+	go func() {
+		<-stop
+		m.mu.Lock()
+		if conn != nil {
+			conn.Close()
+		}
+		m.mu.Unlock()
+	}()
+
+	var r *bufio.Reader
+	if conn != nil {
+		r = bufio.NewReader(conn)
+	}
+
+	handle := func(op string, err error) {
+		m.log.ERROR.Printf("%s: %v", op, err)
+		if errors.Is(err, net.ErrClosed) || errors.Is(err, io.EOF) {
+			m.mu.Lock()
+			conn.Close()
+			conn, r = nil, nil
+			m.mu.Unlock()
+		}
+	}
+
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		if r == nil {
+			c, err := m.connect()
+			if err != nil {
+				m.log.ERROR.Printf("connect: %v", err)
+				time.Sleep(time.Second)
+				continue
+			}
+
+			m.mu.Lock()
+			conn, r = c, bufio.NewReader(c)
+			m.mu.Unlock()
+		}
+
+		if _, err := r.ReadBytes(tic.STX); err != nil {
+			handle("read", err)
+			continue
+		}
+
+		b, err := r.ReadBytes(tic.ETX)
+		if err != nil {
+			handle("read", err)
+			continue
+		}
+
+		m.log.TRACE.Printf("read: %q", b)
+
+		frame, err := tic.ParseFrame(b, m.mode)
+		if err != nil {
+			m.log.ERROR.Printf("could not parse frame: %v", err)
+			continue
+		}
+
+		m.mu.Lock()
+		m.frame = frame
+		m.updated = time.Now()
+		m.mu.Unlock()
+
+		select {
+		case done <- struct{}{}:
+		default:
+		}
+	}
+}
+
+var _ api.Closer = (*Linky)(nil)
+
+// Close implements the api.Closer interface
+func (m *Linky) Close() error {
+	m.once.Do(func() { close(m.stop) })
+	return nil
+}
+
+func (m *Linky) connect() (io.ReadCloser, error) {
+	if m.device != "" {
+		port, err := serial.Open(&serial.Config{
+			Address:  m.device,
+			BaudRate: m.baudrate,
+			DataBits: 7,
+			StopBits: 1,
+			Parity:   "E",
+			Timeout:  m.timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return port, nil
+	}
+
+	dialer := net.Dialer{Timeout: request.Timeout}
+
+	return dialer.Dial("tcp", m.addr)
+}
+
+func (m *Linky) get(label string) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if time.Since(m.updated) > m.timeout {
+		return 0, os.ErrDeadlineExceeded
+	}
+
+	res, ok := m.frame[label]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", api.ErrNotAvailable, label)
+	}
+
+	return strconv.ParseFloat(res, 64)
+}
+
+// CurrentPower implements the api.Meter interface
+func (m *Linky) CurrentPower() (float64, error) {
+	// export is not reported in historic mode
+	if m.mode == tic.Historic {
+		return m.get(tic.ApparentPower)
+	}
+
+	res, err := m.get(tic.ApparentPowerImport)
+	if err != nil {
+		return 0, err
+	}
+
+	// injection is only reported for producers
+	export, err := m.get(tic.ApparentPowerExport)
+	if err != nil && !errors.Is(err, api.ErrNotAvailable) {
+		return 0, err
+	}
+
+	return res - export, nil
+}
+
+var _ api.MeterEnergy = (*Linky)(nil)
+
+// TotalEnergy implements the api.MeterEnergy interface
+func (m *Linky) TotalEnergy() (float64, error) {
+	if m.mode == tic.Standard {
+		res, err := m.get(tic.EnergyImport)
+		return res / 1e3, err
+	}
+
+	// sum indexes of the active tariff option
+	var res float64
+	var found bool
+
+	for _, label := range tic.Indexes {
+		f, err := m.get(label)
+		if errors.Is(err, api.ErrNotAvailable) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		res += f
+		found = true
+	}
+
+	if !found {
+		return 0, api.ErrNotAvailable
+	}
+
+	return res / 1e3, nil
+}
+
+func (m *Linky) currentLabels() []string {
+	if m.mode == tic.Standard {
+		return []string{tic.CurrentRMSL1, tic.CurrentRMSL2, tic.CurrentRMSL3}
+	}
+	return []string{tic.CurrentL1, tic.CurrentL2, tic.CurrentL3}
+}
+
+// currents implements the api.MeterCurrent interface
+func (m *Linky) currents() (float64, float64, float64, error) {
+	var res [3]float64
+
+	for i, label := range m.currentLabels() {
+		var err error
+		if res[i], err = m.get(label); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+
+	return res[0], res[1], res[2], nil
+}
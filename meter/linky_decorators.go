@@ -0,0 +1,35 @@
+package meter
+
+// Code generated by github.com/evcc-io/evcc/cmd/tools/decorate.go. DO NOT EDIT.
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+func decorateLinky(base *Linky, meterCurrent func() (float64, float64, float64, error)) api.Meter {
+	switch {
+	case meterCurrent == nil:
+		return base
+
+	case meterCurrent != nil:
+		return &struct {
+			*Linky
+			api.MeterCurrent
+		}{
+			Linky: base,
+			MeterCurrent: &decorateLinkyMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+	}
+
+	return nil
+}
+
+type decorateLinkyMeterCurrentImpl struct {
+	meterCurrent func() (float64, float64, float64, error)
+}
+
+func (impl *decorateLinkyMeterCurrentImpl) Currents() (float64, float64, float64, error) {
+	return impl.meterCurrent()
+}
@@ -1,6 +1,7 @@
 package meter
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -13,27 +14,29 @@ func init() {
 	registry.Add(api.Custom, NewConfigurableFromConfig)
 }
 
-//go:generate go run ../cmd/tools/decorate.go -f decorateMeter -b api.Meter -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.Battery,SoC,func() (float64, error)"
+//go:generate go run ../cmd/tools/decorate.go -f decorateMeter -b api.Meter -t "api.MeterContext,CurrentPowerContext,func(context.Context) (float64, error)" -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.Battery,SoC,func() (float64, error)" -t "api.MeterFrequency,Frequency,func() (float64, error)" -t "api.MeterIsland,Island,func() (bool, error)" -t "api.Closer,Close,func() error"
 
 // NewConfigurableFromConfig creates api.Meter from config
 func NewConfigurableFromConfig(other map[string]interface{}) (api.Meter, error) {
 	var cc struct {
-		Power    provider.Config
-		Energy   *provider.Config  // optional
-		SoC      *provider.Config  // optional
-		Currents []provider.Config // optional
+		Power     provider.Config
+		Energy    *provider.Config  // optional
+		SoC       *provider.Config  // optional
+		Currents  []provider.Config // optional
+		Frequency *provider.Config  // optional
+		Island    *provider.Config  // optional
 	}
 
 	if err := util.DecodeOther(other, &cc); err != nil {
 		return nil, err
 	}
 
-	power, err := provider.NewFloatGetterFromConfig(cc.Power)
+	power, err := provider.NewFloatGetterContextFromConfig(cc.Power)
 	if err != nil {
 		return nil, fmt.Errorf("power: %w", err)
 	}
 
-	m, _ := NewConfigurable(power)
+	m, _ := NewConfigurableContext(power)
 
 	// decorate Meter with MeterEnergy
 	var totalEnergyG func() (float64, error)
@@ -73,7 +76,25 @@ func NewConfigurableFromConfig(other map[string]interface{}) (api.Meter, error)
 		}
 	}
 
-	res := m.Decorate(totalEnergyG, currentsG, batterySoCG)
+	// decorate Meter with MeterFrequency
+	var frequencyG func() (float64, error)
+	if cc.Frequency != nil {
+		frequencyG, err = provider.NewFloatGetterFromConfig(*cc.Frequency)
+		if err != nil {
+			return nil, fmt.Errorf("frequency: %w", err)
+		}
+	}
+
+	// decorate Meter with MeterIsland
+	var islandG func() (bool, error)
+	if cc.Island != nil {
+		islandG, err = provider.NewBoolGetterFromConfig(*cc.Island)
+		if err != nil {
+			return nil, fmt.Errorf("island: %w", err)
+		}
+	}
+
+	res := m.Decorate(totalEnergyG, currentsG, batterySoCG, frequencyG, islandG, nil)
 
 	return res, nil
 }
@@ -103,9 +124,22 @@ func NewConfigurable(currentPowerG func() (float64, error)) (*Meter, error) {
 	return m, nil
 }
 
+// NewConfigurableContext creates a new meter with power requests cancelled by context
+func NewConfigurableContext(currentPowerG func(context.Context) (float64, error)) (*Meter, error) {
+	m := &Meter{
+		currentPowerG: func() (float64, error) {
+			return currentPowerG(context.Background())
+		},
+		currentPowerCtxG: currentPowerG,
+	}
+	return m, nil
+}
+
 // Meter is an api.Meter implementation with configurable getters and setters.
 type Meter struct {
-	currentPowerG func() (float64, error)
+	currentPowerG    func() (float64, error)
+	currentPowerCtxG func(context.Context) (float64, error)
+	powerEx          util.Exclusive
 }
 
 // Decorate attaches additional capabilities to the base meter
@@ -113,11 +147,24 @@ func (m *Meter) Decorate(
 	totalEnergy func() (float64, error),
 	currents func() (float64, float64, float64, error),
 	batterySoC func() (float64, error),
+	frequency func() (float64, error),
+	island func() (bool, error),
+	close func() error,
 ) api.Meter {
-	return decorateMeter(m, totalEnergy, currents, batterySoC)
+	return decorateMeter(m, m.CurrentPowerContext, totalEnergy, currents, batterySoC, frequency, island, close)
 }
 
 // CurrentPower implements the api.Meter interface
 func (m *Meter) CurrentPower() (float64, error) {
 	return m.currentPowerG()
 }
+
+var _ api.MeterContext = (*Meter)(nil)
+
+// CurrentPowerContext implements the api.MeterContext interface
+func (m *Meter) CurrentPowerContext(ctx context.Context) (float64, error) {
+	if m.currentPowerCtxG == nil {
+		return util.WithContext(ctx, &m.powerEx, m.currentPowerG)
+	}
+	return m.currentPowerCtxG(ctx)
+}
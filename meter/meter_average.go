@@ -55,7 +55,25 @@ func NewMovingAverageFromConfig(other map[string]interface{}) (api.Meter, error)
 		currents = m.Currents
 	}
 
-	res := meter.Decorate(totalEnergy, currents, batterySoC)
+	// decorate frequency reading
+	var frequency func() (float64, error)
+	if m, ok := m.(api.MeterFrequency); ok {
+		frequency = m.Frequency
+	}
+
+	// decorate island detection
+	var island func() (bool, error)
+	if m, ok := m.(api.MeterIsland); ok {
+		island = m.Island
+	}
+
+	// forward closing the wrapped meter
+	var close func() error
+	if m, ok := m.(api.Closer); ok {
+		close = m.Close
+	}
+
+	res := meter.Decorate(totalEnergy, currents, batterySoC, frequency, island, close)
 
 	return res, nil
 }
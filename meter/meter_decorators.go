@@ -3,109 +3,2695 @@ package meter
 // Code generated by github.com/evcc-io/evcc/cmd/tools/decorate.go. DO NOT EDIT.
 
 import (
+	"context"
+
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateMeter(base api.Meter, meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), battery func() (float64, error)) api.Meter {
-	switch {
-	case battery == nil && meterCurrent == nil && meterEnergy == nil:
-		return base
+func decorateMeter(base api.Meter, meterContext func(context.Context) (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), battery func() (float64, error), meterFrequency func() (float64, error), meterIsland func() (bool, error), closer func() error) api.Meter {
+	switch {
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return base
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterEnergy
+		}{
+			Meter: base,
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterEnergy
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterCurrent
+		}{
+			Meter: base,
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterCurrent
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Meter: base,
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterCurrent
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterCurrent
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterFrequency
+		}{
+			Meter: base,
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterFrequency
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterCurrent
+			api.MeterFrequency
+		}{
+			Meter: base,
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterFrequency
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterCurrent
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterCurrent
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterCurrent
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterCurrent
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterCurrent
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer == nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterCurrent
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterCurrent
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterCurrent
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterCurrent
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland == nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterIsland
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+			api.MeterIsland
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterCurrent
+			api.MeterIsland
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterIsland
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterCurrent
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
 
-	case battery == nil && meterCurrent == nil && meterEnergy != nil:
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
 		return &struct {
 			api.Meter
+			api.Closer
+			api.MeterContext
 			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
 		}{
 			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
 			MeterEnergy: &decorateMeterMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterCurrent
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
 		}
 
-	case battery == nil && meterCurrent != nil && meterEnergy == nil:
+	case battery == nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
 		return &struct {
 			api.Meter
+			api.Closer
 			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
 		}{
 			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
 			MeterCurrent: &decorateMeterMeterCurrentImpl{
 				meterCurrent: meterCurrent,
 			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
 		}
 
-	case battery == nil && meterCurrent != nil && meterEnergy != nil:
+	case battery == nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
 		return &struct {
 			api.Meter
+			api.Closer
+			api.MeterContext
 			api.MeterCurrent
 			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
 		}{
 			Meter: base,
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
 			MeterCurrent: &decorateMeterMeterCurrentImpl{
 				meterCurrent: meterCurrent,
 			},
 			MeterEnergy: &decorateMeterMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
 		}
 
-	case battery != nil && meterCurrent == nil && meterEnergy == nil:
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
 		return &struct {
 			api.Meter
 			api.Battery
+			api.Closer
+			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
 		}{
 			Meter: base,
 			Battery: &decorateMeterBatteryImpl{
 				battery: battery,
 			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
 		}
 
-	case battery != nil && meterCurrent == nil && meterEnergy != nil:
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent == nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
 		return &struct {
 			api.Meter
 			api.Battery
+			api.Closer
+			api.MeterContext
 			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
 		}{
 			Meter: base,
 			Battery: &decorateMeterBatteryImpl{
 				battery: battery,
 			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
 			MeterEnergy: &decorateMeterMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterCurrent
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy == nil && meterFrequency != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.Closer
+			api.MeterContext
+			api.MeterCurrent
+			api.MeterFrequency
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
 		}
 
-	case battery != nil && meterCurrent != nil && meterEnergy == nil:
+	case battery != nil && closer != nil && meterContext == nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
 		return &struct {
 			api.Meter
 			api.Battery
+			api.Closer
 			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
 		}{
 			Meter: base,
 			Battery: &decorateMeterBatteryImpl{
 				battery: battery,
 			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
 			MeterCurrent: &decorateMeterMeterCurrentImpl{
 				meterCurrent: meterCurrent,
 			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
 		}
 
-	case battery != nil && meterCurrent != nil && meterEnergy != nil:
+	case battery != nil && closer != nil && meterContext != nil && meterCurrent != nil && meterEnergy != nil && meterFrequency != nil && meterIsland != nil:
 		return &struct {
 			api.Meter
 			api.Battery
+			api.Closer
+			api.MeterContext
 			api.MeterCurrent
 			api.MeterEnergy
+			api.MeterFrequency
+			api.MeterIsland
 		}{
 			Meter: base,
 			Battery: &decorateMeterBatteryImpl{
 				battery: battery,
 			},
+			Closer: &decorateMeterCloserImpl{
+				closer: closer,
+			},
+			MeterContext: &decorateMeterMeterContextImpl{
+				meterContext: meterContext,
+			},
 			MeterCurrent: &decorateMeterMeterCurrentImpl{
 				meterCurrent: meterCurrent,
 			},
 			MeterEnergy: &decorateMeterMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
+			MeterFrequency: &decorateMeterMeterFrequencyImpl{
+				meterFrequency: meterFrequency,
+			},
+			MeterIsland: &decorateMeterMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
 		}
 	}
 
@@ -120,6 +2706,22 @@ func (impl *decorateMeterBatteryImpl) SoC() (float64, error) {
 	return impl.battery()
 }
 
+type decorateMeterCloserImpl struct {
+	closer func() error
+}
+
+func (impl *decorateMeterCloserImpl) Close() error {
+	return impl.closer()
+}
+
+type decorateMeterMeterContextImpl struct {
+	meterContext func(context.Context) (float64, error)
+}
+
+func (impl *decorateMeterMeterContextImpl) CurrentPowerContext(ctx context.Context) (float64, error) {
+	return impl.meterContext(ctx)
+}
+
 type decorateMeterMeterCurrentImpl struct {
 	meterCurrent func() (float64, float64, float64, error)
 }
@@ -135,3 +2737,19 @@ type decorateMeterMeterEnergyImpl struct {
 func (impl *decorateMeterMeterEnergyImpl) TotalEnergy() (float64, error) {
 	return impl.meterEnergy()
 }
+
+type decorateMeterMeterFrequencyImpl struct {
+	meterFrequency func() (float64, error)
+}
+
+func (impl *decorateMeterMeterFrequencyImpl) Frequency() (float64, error) {
+	return impl.meterFrequency()
+}
+
+type decorateMeterMeterIslandImpl struct {
+	meterIsland func() (bool, error)
+}
+
+func (impl *decorateMeterMeterIslandImpl) Island() (bool, error) {
+	return impl.meterIsland()
+}
@@ -0,0 +1,58 @@
+package meter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+func TestDecoratedMeterContext(t *testing.T) {
+	cancelledC := make(chan struct{}, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			cancelledC <- struct{}{}
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	m, err := NewConfigurableFromConfig(map[string]interface{}{
+		"power":  map[string]interface{}{"source": "http", "uri": srv.URL},
+		"energy": map[string]interface{}{"source": "http", "uri": srv.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := m.(api.MeterEnergy); !ok {
+		t.Fatal("missing energy")
+	}
+
+	for name, m := range map[string]api.Meter{"decorated": m, "inverted": inverted(m)} {
+		mc, ok := m.(api.MeterContext)
+		if !ok {
+			t.Errorf("%s: missing context", name)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		_, err := mc.CurrentPowerContext(ctx)
+		cancel()
+
+		if err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+
+		select {
+		case <-cancelledC:
+		case <-time.After(500 * time.Millisecond):
+			t.Errorf("%s: request not cancelled", name)
+		}
+	}
+}
@@ -20,13 +20,14 @@ type Modbus struct {
 	opPower  modbus.Operation
 	opEnergy modbus.Operation
 	opSoC    modbus.Operation
+	opGrid   modbus.Operation
 }
 
 func init() {
 	registry.Add("modbus", NewModbusFromConfig)
 }
 
-//go:generate go run ../cmd/tools/decorate.go -f decorateModbus -b api.Meter -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.Battery,SoC,func() (float64, error)"
+//go:generate go run ../cmd/tools/decorate.go -f decorateModbus -b api.Meter -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.Battery,SoC,func() (float64, error)" -t "api.MeterIsland,Island,func() (bool, error)"
 
 // NewModbusFromConfig creates api.Meter from config
 func NewModbusFromConfig(other map[string]interface{}) (api.Meter, error) {
@@ -34,6 +35,7 @@ func NewModbusFromConfig(other map[string]interface{}) (api.Meter, error) {
 		Model              string
 		modbus.Settings    `mapstructure:",squash"`
 		Power, Energy, SoC string
+		GridConnection     string // grid connection status, e.g. SunSpec 122:0:ECPConn, zero indicates island operation
 		Currents           []string
 		Delay              time.Duration
 		Timeout            time.Duration
@@ -142,7 +144,17 @@ func NewModbusFromConfig(other map[string]interface{}) (api.Meter, error) {
 		soc = m.soc
 	}
 
-	return decorateModbus(m, totalEnergy, currentsG, soc), nil
+	// decorate island detection
+	var island func() (bool, error)
+	if cc.GridConnection != "" {
+		if err := modbus.ParseOperation(device, cc.GridConnection, &m.opGrid); err != nil {
+			return nil, fmt.Errorf("invalid measurement for grid connection: %s", cc.GridConnection)
+		}
+
+		island = m.island
+	}
+
+	return decorateModbus(m, totalEnergy, currentsG, soc, island), nil
 }
 
 // floatGetter executes configured modbus read operation and implements func() (float64, error)
@@ -190,3 +202,16 @@ func (m *Modbus) totalEnergy() (float64, error) {
 func (m *Modbus) soc() (float64, error) {
 	return m.floatGetter(m.opSoC)
 }
+
+// island implements the api.MeterIsland interface
+func (m *Modbus) island() (bool, error) {
+	res, err := m.floatGetter(m.opGrid)
+	return res == 0, err
+}
+
+var _ api.Closer = (*Modbus)(nil)
+
+// Close implements the api.Closer interface
+func (m *Modbus) Close() error {
+	return m.conn.Close()
+}
@@ -6,12 +6,12 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), battery func() (float64, error)) api.Meter {
+func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), battery func() (float64, error), meterIsland func() (bool, error)) api.Meter {
 	switch {
-	case battery == nil && meterCurrent == nil && meterEnergy == nil:
+	case battery == nil && meterCurrent == nil && meterEnergy == nil && meterIsland == nil:
 		return base
 
-	case battery == nil && meterCurrent == nil && meterEnergy != nil:
+	case battery == nil && meterCurrent == nil && meterEnergy != nil && meterIsland == nil:
 		return &struct {
 			api.Meter
 			api.MeterEnergy
@@ -22,7 +22,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery == nil && meterCurrent != nil && meterEnergy == nil:
+	case battery == nil && meterCurrent != nil && meterEnergy == nil && meterIsland == nil:
 		return &struct {
 			api.Meter
 			api.MeterCurrent
@@ -33,7 +33,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery == nil && meterCurrent != nil && meterEnergy != nil:
+	case battery == nil && meterCurrent != nil && meterEnergy != nil && meterIsland == nil:
 		return &struct {
 			api.Meter
 			api.MeterCurrent
@@ -48,7 +48,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery != nil && meterCurrent == nil && meterEnergy == nil:
+	case battery != nil && meterCurrent == nil && meterEnergy == nil && meterIsland == nil:
 		return &struct {
 			api.Meter
 			api.Battery
@@ -59,7 +59,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery != nil && meterCurrent == nil && meterEnergy != nil:
+	case battery != nil && meterCurrent == nil && meterEnergy != nil && meterIsland == nil:
 		return &struct {
 			api.Meter
 			api.Battery
@@ -74,7 +74,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery != nil && meterCurrent != nil && meterEnergy == nil:
+	case battery != nil && meterCurrent != nil && meterEnergy == nil && meterIsland == nil:
 		return &struct {
 			api.Meter
 			api.Battery
@@ -89,7 +89,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery != nil && meterCurrent != nil && meterEnergy != nil:
+	case battery != nil && meterCurrent != nil && meterEnergy != nil && meterIsland == nil:
 		return &struct {
 			api.Meter
 			api.Battery
@@ -107,6 +107,142 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 				meterEnergy: meterEnergy,
 			},
 		}
+
+	case battery == nil && meterCurrent == nil && meterEnergy == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterIsland: &decorateModbusMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && meterCurrent == nil && meterEnergy != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterEnergy: &decorateModbusMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateModbusMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && meterCurrent != nil && meterEnergy == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterCurrent
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterCurrent: &decorateModbusMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterIsland: &decorateModbusMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery == nil && meterCurrent != nil && meterEnergy != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			MeterCurrent: &decorateModbusMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateModbusMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateModbusMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && meterCurrent == nil && meterEnergy == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateModbusBatteryImpl{
+				battery: battery,
+			},
+			MeterIsland: &decorateModbusMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && meterCurrent == nil && meterEnergy != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateModbusBatteryImpl{
+				battery: battery,
+			},
+			MeterEnergy: &decorateModbusMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateModbusMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && meterCurrent != nil && meterEnergy == nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterCurrent
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateModbusBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateModbusMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterIsland: &decorateModbusMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
+
+	case battery != nil && meterCurrent != nil && meterEnergy != nil && meterIsland != nil:
+		return &struct {
+			api.Meter
+			api.Battery
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterIsland
+		}{
+			Meter: base,
+			Battery: &decorateModbusBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateModbusMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateModbusMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterIsland: &decorateModbusMeterIslandImpl{
+				meterIsland: meterIsland,
+			},
+		}
 	}
 
 	return nil
@@ -135,3 +271,11 @@ type decorateModbusMeterEnergyImpl struct {
 func (impl *decorateModbusMeterEnergyImpl) TotalEnergy() (float64, error) {
 	return impl.meterEnergy()
 }
+
+type decorateModbusMeterIslandImpl struct {
+	meterIsland func() (bool, error)
+}
+
+func (impl *decorateModbusMeterIslandImpl) Island() (bool, error) {
+	return impl.meterIsland()
+}
@@ -0,0 +1,111 @@
+package meter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+func init() {
+	registry.Add("p4", NewP4FromConfig)
+}
+
+// P4 meter derives the grid power from the cumulative energy readings of the dutch P4 portal.
+// Readings are only updated every 15 minutes, the power is the average since the previous reading.
+type P4 struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	importG func() (float64, error)
+	exportG func() (float64, error)
+	energy  float64 // net energy of last reading in kWh
+	updated time.Time
+	power   float64
+}
+
+// NewP4FromConfig creates a P4 meter from generic config
+func NewP4FromConfig(other map[string]interface{}) (api.Meter, error) {
+	var cc struct {
+		Import provider.Config  // cumulative import energy in kWh
+		Export *provider.Config // optional cumulative export energy in kWh
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	importG, err := provider.NewFloatGetterFromConfig(cc.Import)
+	if err != nil {
+		return nil, fmt.Errorf("import: %w", err)
+	}
+
+	var exportG func() (float64, error)
+	if cc.Export != nil {
+		if exportG, err = provider.NewFloatGetterFromConfig(*cc.Export); err != nil {
+			return nil, fmt.Errorf("export: %w", err)
+		}
+	}
+
+	m := &P4{
+		clock:   clock.New(),
+		importG: importG,
+		exportG: exportG,
+	}
+
+	return m, nil
+}
+
+// netEnergy returns import minus export energy
+func (m *P4) netEnergy() (float64, error) {
+	res, err := m.importG()
+	if err != nil {
+		return 0, err
+	}
+
+	if m.exportG != nil {
+		export, err := m.exportG()
+		if err != nil {
+			return 0, err
+		}
+
+		res -= export
+	}
+
+	return res, nil
+}
+
+// CurrentPower implements the api.Meter interface
+func (m *P4) CurrentPower() (float64, error) {
+	energy, err := m.netEnergy()
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// average power between consecutive readings
+	if !m.updated.IsZero() && energy != m.energy {
+		if hours := m.clock.Since(m.updated).Hours(); hours > 0 {
+			m.power = 1e3 * (energy - m.energy) / hours
+		}
+	}
+
+	if m.updated.IsZero() || energy != m.energy {
+		m.energy = energy
+		m.updated = m.clock.Now()
+	}
+
+	return m.power, nil
+}
+
+var _ api.MeterEnergy = (*P4)(nil)
+
+// TotalEnergy implements the api.MeterEnergy interface
+func (m *P4) TotalEnergy() (float64, error) {
+	return m.importG()
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/evcc-io/evcc/util/templates"
 	"github.com/evcc-io/evcc/util/test"
+	"github.com/evcc-io/evcc/util/test/replay"
 	"golang.org/x/exp/slices"
 )
 
@@ -19,13 +20,14 @@ var acceptable = []string{
 	"connect: connection refused",
 	"connect: network is unreachable",
 	"i/o timeout",
-	"'sma': missing uri or serial", // SMA
-	"'fritzdect': missing ain",     // FritzDect
+	"'sma': missing uri or serial",       // SMA
+	"'fritzdect': missing ain",           // FritzDect
+	"'linky': need either uri or device", // Linky
 	"[1ESY1161052714 1ESY1161229249 1EMH0008842285 1ESY1161978584 1EMH0004864048 1ESY1161979033 7ELS8135823805]", // Discovergy
-	"can only have either uri or device",                                          // modbus
-	"(Client.Timeout exceeded while awaiting headers)",                            // http
-	"unexpected status: 401",                                                      // Discovergy
-	"unexpected status: 503",                                                      // Discovergy
+	"can only have either uri or device",               // modbus
+	"(Client.Timeout exceeded while awaiting headers)", // http
+	"unexpected status: 401",                           // Discovergy
+	"unexpected status: 503",                           // Discovergy
 	"login failed: Put \"https://192.0.2.2/v1/login\": context deadline exceeded", // LG ESS
 }
 
@@ -59,3 +61,9 @@ func TestTemplates(t *testing.T) {
 		})
 	}
 }
+
+func TestReplay(t *testing.T) {
+	replay.Run(t, "testdata/replay/*.yaml", func(values map[string]interface{}) (interface{}, error) {
+		return NewFromConfig("template", values)
+	})
+}
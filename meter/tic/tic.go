@@ -0,0 +1,133 @@
+package tic
+
+// Télé-Information Client (TIC) of french Linky meters
+// https://www.enedis.fr/media/2035/download
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Mode is the TIC transmission mode
+type Mode int
+
+const (
+	Historic Mode = iota
+	Standard
+)
+
+// Baudrate returns the mode's serial baudrate
+func (m Mode) Baudrate() int {
+	if m == Standard {
+		return 9600
+	}
+	return 1200
+}
+
+// separator returns the mode's field separator
+func (m Mode) separator() byte {
+	if m == Standard {
+		return '\t'
+	}
+	return ' '
+}
+
+const (
+	STX = 0x02 // start of frame
+	ETX = 0x03 // end of frame
+	LF  = 0x0a // start of group
+	CR  = 0x0d // end of group
+)
+
+// historic mode labels
+const (
+	ApparentPower = "PAPP"   // VA
+	Current       = "IINST"  // A, single phase
+	CurrentL1     = "IINST1" // A
+	CurrentL2     = "IINST2" // A
+	CurrentL3     = "IINST3" // A
+)
+
+// Indexes are the historic mode energy indexes of the different tariff options
+var Indexes = []string{
+	"BASE",         // Wh, base
+	"HCHC", "HCHP", // Wh, heures creuses
+	"EJPHN", "EJPHPM", // Wh, EJP
+	"BBRHCJB", "BBRHPJB", // Wh, tempo blue days
+	"BBRHCJW", "BBRHPJW", // Wh, tempo white days
+	"BBRHCJR", "BBRHPJR", // Wh, tempo red days
+}
+
+// standard mode labels
+const (
+	ApparentPowerImport = "SINSTS" // VA
+	ApparentPowerExport = "SINSTI" // VA, producers only
+	EnergyImport        = "EAST"   // Wh
+	EnergyExport        = "EAIT"   // Wh, producers only
+	CurrentRMSL1        = "IRMS1"  // A
+	CurrentRMSL2        = "IRMS2"  // A
+	CurrentRMSL3        = "IRMS3"  // A
+)
+
+// Frame maps the labels of a frame to their values
+type Frame map[string]string
+
+// ParseFrame parses the groups of a frame without start and end markers. Groups with invalid checksum are skipped.
+func ParseFrame(b []byte, mode Mode) (Frame, error) {
+	res := make(Frame)
+
+	for _, g := range bytes.Split(b, []byte{LF}) {
+		g = bytes.Trim(g, string([]byte{STX, ETX, CR}))
+		if len(g) == 0 {
+			continue
+		}
+
+		label, value, err := parseGroup(g, mode)
+		if err != nil {
+			continue
+		}
+
+		res[label] = value
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("no valid groups")
+	}
+
+	return res, nil
+}
+
+// parseGroup parses label and value of a group, skipping the optional timestamp of standard mode
+func parseGroup(g []byte, mode Mode) (string, string, error) {
+	sep := mode.separator()
+
+	if len(g) < 3 || g[len(g)-2] != sep {
+		return "", "", fmt.Errorf("invalid group: %q", g)
+	}
+
+	// historic mode checksum excludes the last separator
+	data := g[:len(g)-1]
+	if mode == Historic {
+		data = g[:len(g)-2]
+	}
+
+	if sum := checksum(data); sum != g[len(g)-1] {
+		return "", "", fmt.Errorf("checksum mismatch: %q", g)
+	}
+
+	fields := bytes.Split(g[:len(g)-2], []byte{sep})
+	if len(fields) < 2 {
+		return "", "", fmt.Errorf("invalid group: %q", g)
+	}
+
+	return string(fields[0]), string(fields[len(fields)-1]), nil
+}
+
+func checksum(b []byte) byte {
+	var sum byte
+	for _, c := range b {
+		sum += c
+	}
+	return sum&0x3f + 0x20
+}
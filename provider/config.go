@@ -1,7 +1,10 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+
+	"github.com/evcc-io/evcc/util"
 )
 
 // provider types
@@ -15,6 +18,9 @@ type (
 	FloatProvider interface {
 		FloatGetter() func() (float64, error)
 	}
+	FloatContextProvider interface {
+		FloatGetterContext() func(context.Context) (float64, error)
+	}
 	BoolProvider interface {
 		BoolGetter() func() (bool, error)
 	}
@@ -92,6 +98,33 @@ func NewFloatGetterFromConfig(config Config) (res func() (float64, error), err e
 	return
 }
 
+// NewFloatGetterContextFromConfig creates a FloatGetter cancelled by context from config.
+// Requests of providers not supporting cancellation are abandoned once the context is done.
+func NewFloatGetterContextFromConfig(config Config) (func(context.Context) (float64, error), error) {
+	factory, err := registry.Get(config.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := factory(config.Other)
+	if err != nil {
+		return nil, err
+	}
+
+	switch prov := provider.(type) {
+	case FloatContextProvider:
+		return prov.FloatGetterContext(), nil
+	case FloatProvider:
+		g := prov.FloatGetter()
+		ex := new(util.Exclusive)
+		return func(ctx context.Context) (float64, error) {
+			return util.WithContext(ctx, ex, g)
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid plugin source: %s", config.Source)
+	}
+}
+
 // NewStringGetterFromConfig creates a StringGetter from config
 func NewStringGetterFromConfig(config Config) (res func() (string, error), err error) {
 	switch typ := config.Source; typ {
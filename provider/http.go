@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"math"
@@ -146,7 +147,7 @@ func (p *HTTP) WithAuth(typ, user, password string) (*HTTP, error) {
 }
 
 // request executes the configured request or returns the cached value
-func (p *HTTP) request(body ...string) ([]byte, error) {
+func (p *HTTP) request(ctx context.Context, body ...string) ([]byte, error) {
 	if time.Since(p.updated) >= p.cache {
 		var b io.Reader
 		if len(body) == 1 {
@@ -159,7 +160,7 @@ func (p *HTTP) request(body ...string) ([]byte, error) {
 			return []byte{}, err
 		}
 
-		p.val, p.err = p.DoBody(req)
+		p.val, p.err = p.DoBody(req.WithContext(ctx))
 		p.updated = time.Now()
 	}
 
@@ -168,10 +169,19 @@ func (p *HTTP) request(body ...string) ([]byte, error) {
 
 // FloatGetter parses float from request
 func (p *HTTP) FloatGetter() func() (float64, error) {
-	g := p.StringGetter()
+	g := p.FloatGetterContext()
 
 	return func() (float64, error) {
-		s, err := g()
+		return g(context.Background())
+	}
+}
+
+// FloatGetterContext parses float from request cancelled by context
+func (p *HTTP) FloatGetterContext() func(context.Context) (float64, error) {
+	g := p.stringGetter()
+
+	return func(ctx context.Context) (float64, error) {
+		s, err := g(ctx)
 		if err != nil {
 			return 0, err
 		}
@@ -197,8 +207,16 @@ func (p *HTTP) IntGetter() func() (int64, error) {
 
 // StringGetter sends string request
 func (p *HTTP) StringGetter() func() (string, error) {
+	g := p.stringGetter()
+
 	return func() (string, error) {
-		b, err := p.request(p.body)
+		return g(context.Background())
+	}
+}
+
+func (p *HTTP) stringGetter() func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		b, err := p.request(ctx, p.body)
 
 		if err == nil && p.pipeline != nil {
 			b, err = p.pipeline.Process(b)
@@ -222,7 +240,7 @@ func (p *HTTP) set(param string, val interface{}) error {
 	body, err := setFormattedValue(p.body, param, val)
 
 	if err == nil {
-		_, err = p.request(body)
+		_, err = p.request(context.Background(), body)
 	}
 
 	return err
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+)
+
+func TestHTTPContext(t *testing.T) {
+	blockC := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-blockC:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(blockC)
+
+	g := NewHTTP(util.NewLogger("foo"), http.MethodGet, srv.URL, false, 1, 0).FloatGetterContext()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := g(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
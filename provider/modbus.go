@@ -40,6 +40,7 @@ func NewModbusFromConfig(other map[string]interface{}) (IntProvider, error) {
 		Delay           time.Duration
 		ConnectDelay    time.Duration
 		Timeout         time.Duration
+		Verify          int // write verification retries
 	}{
 		Scale: 1,
 	}
@@ -74,6 +75,11 @@ func NewModbusFromConfig(other map[string]interface{}) (IntProvider, error) {
 		conn.ConnectDelay(cc.ConnectDelay)
 	}
 
+	// read back written registers
+	if cc.Verify > 0 {
+		conn.Verify(cc.Verify)
+	}
+
 	log := util.NewLogger("modbus")
 	conn.Logger(log.TRACE)
 
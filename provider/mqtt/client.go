@@ -151,6 +151,15 @@ func (m *Client) ListenSetter(topic string, callback func(string)) {
 	})
 }
 
+// Unlisten removes all listeners and unsubscribes from topic
+func (m *Client) Unlisten(topic string) {
+	m.mux.Lock()
+	delete(m.listener, topic)
+	m.mux.Unlock()
+
+	m.WaitForToken(m.Client.Unsubscribe(topic))
+}
+
 // listen attaches listener to topic
 func (m *Client) listen(topic string) {
 	token := m.Client.Subscribe(topic, m.Qos, func(c paho.Client, msg paho.Message) {
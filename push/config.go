@@ -32,6 +32,21 @@ func NewMessengerFromConfig(typ string, other map[string]interface{}) (res Sende
 		if err = util.DecodeOther(other, &cc); err == nil {
 			res, err = NewShoutrrrMessenger(cc.URI)
 		}
+	case "twilio":
+		var cc twilioConfig
+		if err = util.DecodeOther(other, &cc); err == nil {
+			res, err = NewTwilioMessenger(cc.Account, cc.Token, cc.From, cc.Recipients)
+		}
+	case "vonage":
+		var cc vonageConfig
+		if err = util.DecodeOther(other, &cc); err == nil {
+			res, err = NewVonageMessenger(cc.Key, cc.Secret, cc.From, cc.Recipients)
+		}
+	case "gsm":
+		var cc gsmConfig
+		if err = util.DecodeOther(other, &cc); err == nil {
+			res, err = NewGSMMessenger(cc.Device, cc.Baudrate, cc.Pin, cc.Recipients)
+		}
 	case "script":
 		var cc scriptConfig
 		if err = util.DecodeOther(other, &cc); err == nil {
@@ -0,0 +1,155 @@
+package push
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grid-x/serial"
+)
+
+// gsmTimeout is the maximum time to wait for a modem response. Sending may take several seconds on weak networks.
+const gsmTimeout = 30 * time.Second
+
+// GSM implements an SMS messenger using a local GSM modem controlled by AT commands
+type GSM struct {
+	sync.Mutex
+	port       io.ReadWriteCloser
+	recipients []string
+}
+
+type gsmConfig struct {
+	Device     string
+	Baudrate   int
+	Pin        string
+	Recipients []string
+}
+
+// NewGSMMessenger creates new GSM modem messenger
+func NewGSMMessenger(device string, baudrate int, pin string, recipients []string) (*GSM, error) {
+	if device == "" || len(recipients) == 0 {
+		return nil, errors.New("gsm: missing device or recipients")
+	}
+
+	if baudrate == 0 {
+		baudrate = 115200
+	}
+
+	port, err := serial.Open(&serial.Config{
+		Address:  device,
+		BaudRate: baudrate,
+		DataBits: 8,
+		StopBits: 1,
+		Parity:   "N",
+		Timeout:  time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gsm: %w", err)
+	}
+
+	m := &GSM{
+		port:       port,
+		recipients: recipients,
+	}
+
+	if err := m.init(pin); err != nil {
+		port.Close()
+		return nil, fmt.Errorf("gsm: %w", err)
+	}
+
+	return m, nil
+}
+
+// init unlocks the SIM if required and selects text mode
+func (m *GSM) init(pin string) error {
+	if _, err := m.command("AT", "OK"); err != nil {
+		return err
+	}
+
+	res, err := m.command("AT+CPIN?", "OK")
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(res, "SIM PIN") {
+		if pin == "" {
+			return errors.New("sim locked, missing pin")
+		}
+		if _, err := m.command(fmt.Sprintf(`AT+CPIN="%s"`, pin), "OK"); err != nil {
+			return fmt.Errorf("pin: %w", err)
+		}
+	}
+
+	_, err = m.command("AT+CMGF=1", "OK")
+	return err
+}
+
+// command writes an AT command and reads the response until the expected terminator
+func (m *GSM) command(cmd, expect string) (string, error) {
+	if _, err := m.port.Write([]byte(cmd + "\r")); err != nil {
+		return "", err
+	}
+	return m.read(expect)
+}
+
+// read reads the modem response until the expected terminator or an error is received
+func (m *GSM) read(expect string) (string, error) {
+	var res strings.Builder
+	buf := make([]byte, 128)
+
+	for deadline := time.Now().Add(gsmTimeout); time.Now().Before(deadline); {
+		n, err := m.port.Read(buf)
+		if err != nil && !errors.Is(err, serial.ErrTimeout) {
+			return res.String(), err
+		}
+
+		res.Write(buf[:n])
+		s := res.String()
+
+		switch {
+		case strings.Contains(s, expect):
+			return s, nil
+		case strings.Contains(s, "ERROR"):
+			return s, fmt.Errorf("modem error: %s", strings.TrimSpace(s))
+		}
+	}
+
+	return res.String(), serial.ErrTimeout
+}
+
+// gsmText limits the message to a single text-mode SMS
+func gsmText(s string) string {
+	if r := []rune(s); len(r) > 160 {
+		return string(r[:160])
+	}
+	return s
+}
+
+// Send sends to all receivers
+func (m *GSM) Send(title, msg string) {
+	m.Lock()
+	defer m.Unlock()
+
+	text := gsmText(smsText(title, msg))
+
+	for _, to := range m.recipients {
+		log.DEBUG.Printf("gsm: sending to %s", to)
+
+		_, err := m.command(fmt.Sprintf(`AT+CMGS="%s"`, to), ">")
+		if err == nil {
+			// message is terminated by ctrl-z
+			if _, err = m.port.Write([]byte(text + "\x1a")); err == nil {
+				_, err = m.read("OK")
+			}
+		}
+
+		if err != nil {
+			// abort pending message input
+			_, _ = m.port.Write([]byte("\x1b"))
+			log.ERROR.Printf("gsm: %v", err)
+		}
+	}
+}
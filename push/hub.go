@@ -7,6 +7,7 @@ import (
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/evcc-io/evcc/util"
+	"golang.org/x/exp/slices"
 )
 
 // Event is a notification event
@@ -25,10 +26,16 @@ type EventTemplate struct {
 	Title, Msg *template.Template
 }
 
+// subscriber is a sender optionally restricted to a list of events
+type subscriber struct {
+	sender Sender
+	events []string
+}
+
 // Hub subscribes to event notifications and sends them to client devices
 type Hub struct {
 	definitions map[string]EventTemplate
-	sender      []Sender
+	sender      []subscriber
 	cache       *util.Cache
 }
 
@@ -61,9 +68,9 @@ func NewHub(cc map[string]EventTemplateConfig, cache *util.Cache) (*Hub, error)
 	return h, nil
 }
 
-// Add adds a sender to the list of senders
-func (h *Hub) Add(sender Sender) {
-	h.sender = append(h.sender, sender)
+// Add adds a sender to the list of senders. If events are given, only these events are sent.
+func (h *Hub) Add(sender Sender, events ...string) {
+	h.sender = append(h.sender, subscriber{sender, events})
 }
 
 // apply applies the event template to the content to produce the actual message
@@ -113,9 +120,13 @@ func (h *Hub) Run(events <-chan Event) {
 			continue
 		}
 
-		for _, sender := range h.sender {
+		for _, sub := range h.sender {
+			if len(sub.events) > 0 && !slices.Contains(sub.events, ev.Event) {
+				continue
+			}
+
 			if strings.TrimSpace(msg) != "" {
-				go sender.Send(title, msg)
+				go sub.sender.Send(title, msg)
 			} else {
 				log.DEBUG.Printf("did not send empty message template for %s: %v", ev.Event, err)
 			}
@@ -0,0 +1,14 @@
+package push
+
+import (
+	"strings"
+)
+
+// smsText combines title and message into a single text message
+func smsText(title, msg string) string {
+	msg = strings.TrimSpace(msg)
+	if title = strings.TrimSpace(title); title != "" {
+		msg = title + ": " + msg
+	}
+	return msg
+}
@@ -0,0 +1,75 @@
+package push
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/transport"
+)
+
+const twilioURI = "https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json"
+
+// Twilio implements the Twilio SMS messenger
+type Twilio struct {
+	*request.Helper
+	uri        string
+	from       string
+	recipients []string
+}
+
+type twilioConfig struct {
+	Account    string
+	Token      string
+	From       string
+	Recipients []string
+}
+
+// NewTwilioMessenger creates new Twilio SMS messenger
+func NewTwilioMessenger(account, token, from string, recipients []string) (*Twilio, error) {
+	if account == "" || token == "" {
+		return nil, errors.New("twilio: missing account or token")
+	}
+	if from == "" || len(recipients) == 0 {
+		return nil, errors.New("twilio: missing sender or recipients")
+	}
+
+	m := &Twilio{
+		Helper:     request.NewHelper(util.NewLogger("twilio").Redact(token)),
+		uri:        fmt.Sprintf(twilioURI, url.PathEscape(account)),
+		from:       from,
+		recipients: recipients,
+	}
+
+	m.Client.Transport = transport.BasicAuth(account, token, m.Client.Transport)
+
+	return m, nil
+}
+
+// Send sends to all receivers
+func (m *Twilio) Send(title, msg string) {
+	for _, to := range m.recipients {
+		go func(to string) {
+			log.DEBUG.Printf("twilio: sending to %s", to)
+
+			data := url.Values{
+				"From": {m.from},
+				"To":   {to},
+				"Body": {smsText(title, msg)},
+			}
+
+			req, err := request.New(http.MethodPost, m.uri, strings.NewReader(data.Encode()), request.URLEncoding)
+			if err == nil {
+				_, err = m.DoBody(req)
+			}
+
+			if err != nil {
+				log.ERROR.Printf("twilio: %v", err)
+			}
+		}(to)
+	}
+}
@@ -0,0 +1,97 @@
+package push
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+const vonageURI = "https://rest.nexmo.com/sms/json"
+
+// Vonage implements the Vonage (formerly Nexmo) SMS messenger
+type Vonage struct {
+	*request.Helper
+	key, secret string
+	from        string
+	recipients  []string
+}
+
+type vonageConfig struct {
+	Key        string
+	Secret     string
+	From       string
+	Recipients []string
+}
+
+type vonageResponse struct {
+	Messages []struct {
+		Status    string `json:"status"`
+		ErrorText string `json:"error-text"`
+	} `json:"messages"`
+}
+
+// NewVonageMessenger creates new Vonage SMS messenger
+func NewVonageMessenger(key, secret, from string, recipients []string) (*Vonage, error) {
+	if key == "" || secret == "" {
+		return nil, errors.New("vonage: missing key or secret")
+	}
+	if from == "" || len(recipients) == 0 {
+		return nil, errors.New("vonage: missing sender or recipients")
+	}
+
+	m := &Vonage{
+		Helper:     request.NewHelper(util.NewLogger("vonage").Redact(secret)),
+		key:        key,
+		secret:     secret,
+		from:       from,
+		recipients: recipients,
+	}
+
+	return m, nil
+}
+
+// Send sends to all receivers
+func (m *Vonage) Send(title, msg string) {
+	for _, to := range m.recipients {
+		go func(to string) {
+			log.DEBUG.Printf("vonage: sending to %s", to)
+
+			if err := m.send(to, smsText(title, msg)); err != nil {
+				log.ERROR.Printf("vonage: %v", err)
+			}
+		}(to)
+	}
+}
+
+func (m *Vonage) send(to, text string) error {
+	data := url.Values{
+		"api_key":    {m.key},
+		"api_secret": {m.secret},
+		"from":       {m.from},
+		"to":         {strings.TrimPrefix(to, "+")},
+		"text":       {text},
+	}
+
+	req, err := request.New(http.MethodPost, vonageURI, strings.NewReader(data.Encode()), request.URLEncoding)
+	if err != nil {
+		return err
+	}
+
+	var res vonageResponse
+	if err := m.DoJSON(req, &res); err != nil {
+		return err
+	}
+
+	// status 0 indicates success, any other value an error
+	for _, msg := range res.Messages {
+		if msg.Status != "0" {
+			return errors.New(msg.ErrorText)
+		}
+	}
+
+	return nil
+}
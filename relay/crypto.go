@@ -0,0 +1,114 @@
+package relay
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+const (
+	keySize       = 32
+	nonceSize     = 24
+	timestampSize = 8
+)
+
+// keyPair is the instance's key pair used for end-to-end encryption
+type keyPair struct {
+	Public  [keySize]byte `json:"public"`
+	Private [keySize]byte `json:"private"`
+}
+
+// storedKeys is the persisted key pair with the private key sealed by the machine-bound secret
+type storedKeys struct {
+	Public [keySize]byte `json:"public"`
+	Sealed []byte        `json:"sealed"`
+}
+
+func newKeyPair() (keyPair, error) {
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return keyPair{}, err
+	}
+	return keyPair{Public: *public, Private: *private}, nil
+}
+
+// secretKey derives the key for sealing the stored private key
+func secretKey(secret string) *[keySize]byte {
+	key := sha256.Sum256([]byte(secret))
+	return &key
+}
+
+// store seals the private key for persisting
+func (k keyPair) store(secret string) (storedKeys, error) {
+	var nonce [nonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return storedKeys{}, err
+	}
+
+	return storedKeys{
+		Public: k.Public,
+		Sealed: secretbox.Seal(nonce[:], k.Private[:], &nonce, secretKey(secret)),
+	}, nil
+}
+
+// load opens the sealed private key
+func (s storedKeys) load(secret string) (keyPair, error) {
+	if len(s.Sealed) < nonceSize+secretbox.Overhead {
+		return keyPair{}, errors.New("invalid stored key")
+	}
+
+	var nonce [nonceSize]byte
+	copy(nonce[:], s.Sealed[:nonceSize])
+
+	private, ok := secretbox.Open(nil, s.Sealed[nonceSize:], &nonce, secretKey(secret))
+	if !ok || len(private) != keySize {
+		return keyPair{}, errors.New("invalid stored key")
+	}
+
+	k := keyPair{Public: s.Public}
+	copy(k.Private[:], private)
+
+	return k, nil
+}
+
+// seal encrypts the timestamped message for the peer, prefixing the random nonce
+func (k keyPair) seal(peer *[keySize]byte, msg []byte, ts time.Time) ([]byte, error) {
+	var nonce [nonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+
+	b := binary.BigEndian.AppendUint64(make([]byte, 0, timestampSize+len(msg)), uint64(ts.UnixMilli()))
+
+	return box.Seal(nonce[:], append(b, msg...), &nonce, peer, &k.Private), nil
+}
+
+// open decrypts the nonce-prefixed message from the peer and returns the nonce, message and timestamp
+func (k keyPair) open(peer *[keySize]byte, b []byte) ([nonceSize]byte, []byte, time.Time, error) {
+	var nonce [nonceSize]byte
+
+	if len(b) < nonceSize+box.Overhead+timestampSize {
+		return nonce, nil, time.Time{}, errors.New("message too short")
+	}
+
+	copy(nonce[:], b[:nonceSize])
+
+	msg, ok := box.Open(nil, b[nonceSize:], &nonce, peer, &k.Private)
+	if !ok {
+		return nonce, nil, time.Time{}, errors.New("decryption failed")
+	}
+
+	ts := time.UnixMilli(int64(binary.BigEndian.Uint64(msg[:timestampSize])))
+
+	return nonce, msg[timestampSize:], ts, nil
+}
+
+func encodeKey(key [keySize]byte) string {
+	return base64.RawURLEncoding.EncodeToString(key[:])
+}
@@ -0,0 +1,326 @@
+package relay
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util"
+	"github.com/gorilla/websocket"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	keysKey  = "relay.keys"
+	peersKey = "relay.peers"
+
+	// PairPath is the relayed request path for pairing a remote peer
+	PairPath = "/pair"
+
+	idSize       = 16
+	maxBackoff   = 5 * time.Minute
+	maxFrameSize = 1 << 20
+
+	// replayWindow is the maximum age of a request, nonces are remembered for twice the window
+	replayWindow = time.Minute
+
+	// pairingTTL is the validity of a pairing code
+	pairingTTL = 10 * time.Minute
+)
+
+// Config is the relay configuration
+type Config struct {
+	Broker string // broker websocket url, e.g. wss://relay.example.com
+	Token  string // broker account token
+}
+
+// Relay tunnels end-to-end encrypted http requests from paired peers through a broker.
+//
+// Each message exchanged with the broker is a frame of
+//
+//	[16 byte stream id][32 byte peer public key][24 byte nonce][encrypted 8 byte unix milli timestamp and http request or response]
+//
+// The broker only routes frames by instance and stream id and cannot read their content.
+// Requests outside the replay window or with a nonce already seen are rejected.
+type Relay struct {
+	mu      sync.Mutex
+	log     *util.Logger
+	clock   clock.Clock
+	conf    Config
+	id      string
+	keys    keyPair
+	peers   []string                      // paired peer public keys
+	code    string                        // current pairing code, empty if none requested
+	expires time.Time                     // expiry of the pairing code
+	nonces  map[[nonceSize]byte]time.Time // nonces seen within the replay window
+	handler http.Handler
+}
+
+// New creates a relay client serving the handler. The private key is stored sealed by the machine-bound secret.
+// The handler must apply the same authentication as the local api.
+func New(conf Config, id, secret string, handler http.Handler) (*Relay, error) {
+	if conf.Broker == "" || conf.Token == "" {
+		return nil, errors.New("relay: missing broker or token")
+	}
+
+	r := &Relay{
+		log:     util.NewLogger("relay"),
+		clock:   clock.New(),
+		conf:    conf,
+		id:      id,
+		nonces:  make(map[[nonceSize]byte]time.Time),
+		handler: handler,
+	}
+
+	var err error
+	if r.keys, err = loadKeys(secret); err != nil {
+		if r.keys, err = newKeyPair(); err != nil {
+			return nil, err
+		}
+
+		stored, err := r.keys.store(secret)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := settings.SetJson(keysKey, stored); err != nil {
+			return nil, err
+		}
+	}
+
+	_ = settings.Json(peersKey, &r.peers)
+
+	return r, nil
+}
+
+// loadKeys loads the stored key pair, migrating unsealed keys
+func loadKeys(secret string) (keyPair, error) {
+	var stored storedKeys
+	if err := settings.Json(keysKey, &stored); err != nil {
+		return keyPair{}, err
+	}
+
+	if stored.Sealed == nil {
+		var keys keyPair
+		if err := settings.Json(keysKey, &keys); err != nil || keys.Private == [keySize]byte{} {
+			return keyPair{}, errors.New("invalid stored key")
+		}
+
+		stored, err := keys.store(secret)
+		if err == nil {
+			err = settings.SetJson(keysKey, stored)
+		}
+
+		return keys, err
+	}
+
+	return stored.load(secret)
+}
+
+// fresh checks the request timestamp against the replay window and records the nonce
+func (r *Relay) fresh(nonce [nonceSize]byte, ts time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	if ts.Before(now.Add(-replayWindow)) || ts.After(now.Add(replayWindow)) {
+		return false
+	}
+
+	for n, seen := range r.nonces {
+		if now.Sub(seen) > 2*replayWindow {
+			delete(r.nonces, n)
+		}
+	}
+
+	if _, ok := r.nonces[nonce]; ok {
+		return false
+	}
+
+	r.nonces[nonce] = now
+
+	return true
+}
+
+// PairingToken creates a token for pairing a remote peer, replacing any previous token.
+// It contains the instance id for routing, the public key for encryption and a single-use pairing code
+// valid until the returned expiry.
+func (r *Relay) PairingToken() (string, time.Time, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.code = hex.EncodeToString(b)
+	r.expires = r.clock.Now().Add(pairingTTL)
+
+	token := strings.Join([]string{r.id, encodeKey(r.keys.Public), r.code}, ".")
+	return base64.RawURLEncoding.EncodeToString([]byte(token)), r.expires, nil
+}
+
+// pair adds the peer if the pairing code matches and has not expired. The code is used up.
+func (r *Relay) pair(peer, code string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.code == "" || r.clock.Now().After(r.expires) || subtle.ConstantTimeCompare([]byte(code), []byte(r.code)) != 1 {
+		return false
+	}
+
+	if !slices.Contains(r.peers, peer) {
+		r.peers = append(r.peers, peer)
+		_ = settings.SetJson(peersKey, r.peers)
+	}
+
+	r.code = ""
+
+	return true
+}
+
+func (r *Relay) paired(peer string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Contains(r.peers, peer)
+}
+
+// serve executes the request and returns the serialized response
+func (r *Relay) serve(peer string, b []byte) ([]byte, error) {
+	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	w := httptest.NewRecorder()
+
+	switch {
+	case req.Method == http.MethodPost && req.URL.Path == PairPath:
+		code, _ := io.ReadAll(io.LimitReader(req.Body, 64))
+		if r.pair(peer, strings.TrimSpace(string(code))) {
+			r.log.INFO.Printf("paired peer %s", peer)
+			w.WriteHeader(http.StatusNoContent)
+		} else {
+			w.WriteHeader(http.StatusForbidden)
+		}
+
+	case r.paired(peer):
+		r.handler.ServeHTTP(w, req)
+
+	default:
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+
+	var res bytes.Buffer
+	err = w.Result().Write(&res)
+
+	return res.Bytes(), err
+}
+
+// handle decrypts a request frame, serves it and returns the encrypted response frame
+func (r *Relay) handle(frame []byte) ([]byte, error) {
+	if len(frame) < idSize+keySize {
+		return nil, errors.New("invalid frame")
+	}
+
+	var peer [keySize]byte
+	copy(peer[:], frame[idSize:idSize+keySize])
+
+	nonce, msg, ts, err := r.keys.open(&peer, frame[idSize+keySize:])
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.fresh(nonce, ts) {
+		return nil, errors.New("replayed or expired request")
+	}
+
+	b, err := r.serve(encodeKey(peer), msg)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := r.keys.seal(&peer, b, r.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return append(slices.Clone(frame[:idSize+keySize]), sealed...), nil
+}
+
+// connect connects to the broker and serves relayed requests until the connection fails
+func (r *Relay) connect() error {
+	uri := fmt.Sprintf("%s/v1/instance/%s", strings.TrimSuffix(r.conf.Broker, "/"), r.id)
+
+	conn, _, err := websocket.DefaultDialer.Dial(uri, http.Header{
+		"Authorization": []string{"Bearer " + r.conf.Token},
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	conn.SetReadLimit(maxFrameSize)
+	r.log.INFO.Printf("connected to %s", r.conf.Broker)
+
+	var mu sync.Mutex
+	for {
+		typ, frame, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		if typ != websocket.BinaryMessage {
+			continue
+		}
+
+		go func(frame []byte) {
+			res, err := r.handle(frame)
+			if err != nil {
+				r.log.DEBUG.Printf("frame: %v", err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err := conn.WriteMessage(websocket.BinaryMessage, res); err != nil {
+				r.log.ERROR.Println(err)
+			}
+		}(frame)
+	}
+}
+
+// Run maintains the broker connection, reconnecting with backoff
+func (r *Relay) Run() {
+	backoff := time.Second
+	for {
+		start := time.Now()
+		err := r.connect()
+
+		if time.Since(start) > maxBackoff {
+			backoff = time.Second
+		}
+
+		r.log.ERROR.Printf("broker: %v (reconnecting in %v)", err, backoff)
+		time.Sleep(backoff)
+
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package relay
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelay(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	r, err := New(Config{Broker: "wss://localhost", Token: "token"}, "instance", "secret", handler)
+	require.NoError(t, err)
+
+	clck := clock.NewMock()
+	r.clock = clck
+
+	peer, err := newKeyPair()
+	require.NoError(t, err)
+
+	id := bytes.Repeat([]byte{1}, idSize)
+
+	// frame creates an encrypted request frame from the peer
+	frame := func(method, path, body string, ts time.Time) []byte {
+		var b bytes.Buffer
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		require.NoError(t, req.Write(&b))
+
+		sealed, err := peer.seal(&r.keys.Public, b.Bytes(), ts)
+		require.NoError(t, err)
+
+		return append(append(append([]byte{}, id...), peer.Public[:]...), sealed...)
+	}
+
+	// request sends an encrypted request from the peer and returns the decrypted response status
+	request := func(method, path, body string) int {
+		frame := frame(method, path, body, clck.Now())
+
+		res, err := r.handle(frame)
+		require.NoError(t, err)
+		require.Equal(t, frame[:idSize+keySize], res[:idSize+keySize])
+
+		_, msg, _, err := peer.open(&r.keys.Public, res[idSize+keySize:])
+		require.NoError(t, err)
+
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(msg)), nil)
+		require.NoError(t, err)
+
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, request(http.MethodGet, "/api/state", ""))
+
+	// no pairing without requested token
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, PairPath, ""))
+
+	_, expires, err := r.PairingToken()
+	require.NoError(t, err)
+	assert.Equal(t, clck.Now().Add(pairingTTL), expires)
+
+	// expired code
+	clck.Add(pairingTTL + time.Second)
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, PairPath, r.code))
+
+	_, _, err = r.PairingToken()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, PairPath, "wrong"))
+
+	code := r.code
+	assert.Equal(t, http.StatusNoContent, request(http.MethodPost, PairPath, code))
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, PairPath, code), "code used up")
+
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "/api/state", ""))
+
+	// tampered frame
+	_, err = r.handle(bytes.Repeat([]byte{0}, idSize+keySize+nonceSize+32))
+	assert.Error(t, err)
+
+	// replayed frame
+	f := frame(http.MethodGet, "/api/state", "", clck.Now())
+	_, err = r.handle(f)
+	require.NoError(t, err)
+	_, err = r.handle(f)
+	assert.Error(t, err)
+
+	// expired frame
+	_, err = r.handle(frame(http.MethodGet, "/api/state", "", clck.Now().Add(-2*replayWindow)))
+	assert.Error(t, err)
+}
+
+func TestStoredKeys(t *testing.T) {
+	keys, err := newKeyPair()
+	require.NoError(t, err)
+
+	stored, err := keys.store("secret")
+	require.NoError(t, err)
+	assert.NotContains(t, string(stored.Sealed), string(keys.Private[:]))
+
+	res, err := stored.load("secret")
+	require.NoError(t, err)
+	assert.Equal(t, keys, res)
+
+	_, err = stored.load("other")
+	assert.Error(t, err)
+}
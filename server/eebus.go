@@ -137,10 +137,15 @@ func (c *EEBus) DeviceInfo() communication.ManufacturerDetails {
 	return EEBUSDetails
 }
 
-func (c *EEBus) Register(ski, ip string, shipConnectHandler func(string, ship.Conn) error, shipDisconnectHandler func(string)) {
+// normalizeSKI removes separators from the SKI
+func normalizeSKI(ski string) string {
 	ski = strings.ReplaceAll(ski, "-", "")
 	ski = strings.ReplaceAll(ski, " ", "")
-	ski = strings.ToLower(ski)
+	return strings.ToLower(ski)
+}
+
+func (c *EEBus) Register(ski, ip string, shipConnectHandler func(string, ship.Conn) error, shipDisconnectHandler func(string)) {
+	ski = normalizeSKI(ski)
 	c.log.TRACE.Printf("registering ski: %s", ski)
 
 	if ski == c.SKI {
@@ -158,6 +163,15 @@ func (c *EEBus) Register(ski, ip string, shipConnectHandler func(string, ship.Co
 	_ = c.handleDiscoveredSKI(ski)
 }
 
+// Connected returns true if the registered client with the given SKI is connected
+func (c *EEBus) Connected(ski string) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	conn, ok := c.connectedClients[normalizeSKI(ski)]
+	return ok && !conn.IsConnectionClosed()
+}
+
 func (c *EEBus) Run() {
 	go c.browseMDNS()
 
@@ -1,9 +1,11 @@
 package server
 
 import (
+	"crypto/tls"
 	"fmt"
 	"io/fs"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/evcc-io/evcc/core/site"
@@ -11,6 +13,7 @@ import (
 	"github.com/evcc-io/evcc/util/telemetry"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
+	"golang.org/x/exp/slices"
 )
 
 // Assets is the embedded assets file system
@@ -41,24 +44,17 @@ func routeLogger(inner http.Handler) http.HandlerFunc {
 // HTTPd wraps an http.Server and adds the root router
 type HTTPd struct {
 	*http.Server
+	hub     *SocketHub
+	tenancy *Tenancy
+	mu      sync.RWMutex
+	site    *mux.Router // site and loadpoint api, replaced on reload
+	cert    func(*tls.ClientHelloInfo) (*tls.Certificate, error)
 }
 
 // NewHTTPd creates HTTP server with configured routes for loadpoint
 func NewHTTPd(addr string, hub *SocketHub) *HTTPd {
 	router := mux.NewRouter().StrictSlash(true)
 
-	// websocket
-	router.HandleFunc("/ws", socketHandler(hub))
-
-	// static - individual handlers per root and folders
-	static := router.PathPrefix("/").Subrouter()
-	static.Use(handlers.CompressHandler)
-
-	static.HandleFunc("/", indexHandler())
-	for _, dir := range []string{"assets", "meta"} {
-		static.PathPrefix("/" + dir).Handler(http.FileServer(http.FS(Assets)))
-	}
-
 	srv := &HTTPd{
 		Server: &http.Server{
 			Addr:         addr,
@@ -68,28 +64,100 @@ func NewHTTPd(addr string, hub *SocketHub) *HTTPd {
 			IdleTimeout:  120 * time.Second,
 			ErrorLog:     log.ERROR,
 		},
+		hub: hub,
 	}
 	srv.SetKeepAlivesEnabled(true)
 
+	// websocket
+	router.HandleFunc("/ws", socketHandler(hub))
+
+	// static - individual handlers per root and folders
+	static := router.PathPrefix("/").Subrouter()
+	static.Use(handlers.CompressHandler)
+
+	static.HandleFunc("/", srv.loginHandler(indexHandler()))
+	for _, dir := range []string{"assets", "meta"} {
+		static.PathPrefix("/" + dir).Handler(http.FileServer(http.FS(Assets)))
+	}
+
 	return srv
 }
 
+// loginHandler authenticates the web ui opened with token query if tenancy is configured
+func (s *HTTPd) loginHandler(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.tenancy.login(w, r)
+		h(w, r)
+	}
+}
+
+// AdminHandler restricts the handler to admin requests if tenancy is configured
+func (s *HTTPd) AdminHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.tenancy == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+		s.tenancy.handler(adminHandler(h.ServeHTTP)).ServeHTTP(w, r)
+	})
+}
+
 // Router returns the main router
 func (s *HTTPd) Router() *mux.Router {
 	return s.Handler.(*mux.Router)
 }
 
-// RegisterSiteHandlers connects the http handlers to the site
+// SetTenancy restricts api and websocket access to tenants. Must be called before registering handlers.
+func (s *HTTPd) SetTenancy(tenancy *Tenancy) {
+	s.tenancy = tenancy
+	s.hub.tenancy = tenancy
+}
+
+// SetCertificate provides the certificate for tls listeners. Must be called before serving.
+func (s *HTTPd) SetCertificate(cert func(*tls.ClientHelloInfo) (*tls.Certificate, error)) {
+	s.cert = cert
+}
+
+// tenantRoutes are the site routes accessible to tenants, other site routes require admin access
+var tenantRoutes = []string{"health", "state", "sessions", "sessions2", "sessions3", "sessions4", "billing", "billing2", "timeline", "widget", "status", "language"}
+
+// siteRouter returns the current site api router
+func (s *HTTPd) siteRouter() *mux.Router {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.site
+}
+
+// matchSite matches requests handled by the current site api including method mismatches
+func (s *HTTPd) matchSite(r *http.Request, _ *mux.RouteMatch) bool {
+	var match mux.RouteMatch
+	return s.siteRouter().Match(r, &match) || match.MatchErr == mux.ErrMethodMismatch
+}
+
+// RegisterSiteHandlers connects the http handlers to the site. Calling it again replaces the previous site's handlers.
 func (s *HTTPd) RegisterSiteHandlers(site site.API, cache *util.Cache) {
-	router := s.Server.Handler.(*mux.Router)
+	router := mux.NewRouter().StrictSlash(true)
+
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.site == nil {
+			s.Router().MatcherFunc(s.matchSite).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				s.siteRouter().ServeHTTP(w, r)
+			})
+		}
+		s.site = router
+	}()
 
 	// api
 	api := router.PathPrefix("/api").Subrouter()
 	api.Use(jsonHandler)
 	api.Use(handlers.CompressHandler)
 	api.Use(handlers.CORS(
-		handlers.AllowedHeaders([]string{"Content-Type"}),
+		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
 	))
+	api.Use(s.tenancy.handler)
 
 	// site api
 	routes := map[string]route{
@@ -97,23 +165,56 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, cache *util.Cache) {
 		"state":         {[]string{"GET"}, "/state", stateHandler(cache)},
 		"buffersoc":     {[]string{"POST", "OPTIONS"}, "/buffersoc/{value:[0-9.]+}", floatHandler(site.SetBufferSoC, site.GetBufferSoC)},
 		"prioritysoc":   {[]string{"POST", "OPTIONS"}, "/prioritysoc/{value:[0-9.]+}", floatHandler(site.SetPrioritySoC, site.GetPrioritySoC)},
+		"battery":       {[]string{"GET"}, "/batterypriority", batteryPriorityHandler(site)},
+		"battery2":      {[]string{"POST", "OPTIONS"}, "/batterypriority", batteryPriorityUpdateHandler(site)},
+		"circuits":      {[]string{"GET"}, "/circuits", circuitsHandler(site)},
+		"circuits2":     {[]string{"POST", "OPTIONS"}, "/circuits/{name}", circuitUpdateHandler(site)},
 		"residualpower": {[]string{"POST", "OPTIONS"}, "/residualpower/{value:[-0-9.]+}", floatHandler(site.SetResidualPower, site.GetResidualPower)},
-		"sessions":      {[]string{"GET"}, "/sessions", sessionHandler},
+		"sessions":      {[]string{"GET"}, "/sessions", sessionHandler(site)},
+		"sessions2":     {[]string{"PUT", "OPTIONS"}, "/sessions/{id:[0-9]+}", sessionAnnotationHandler},
+		"sessions3":     {[]string{"GET"}, "/sessions/stats", sessionStatsHandler(site)},
+		"sessions4":     {[]string{"GET"}, "/sessions/savings", sessionSavingsHandler(site)},
+		"billing":       {[]string{"GET"}, "/billing/periods", billingPeriodsHandler},
+		"billing2":      {[]string{"GET"}, "/billing/periods/{id:[0-9]+}/statement", billingStatementHandler(s.tenancy)},
+		"timeline":      {[]string{"GET"}, "/timeline", timelineHandler(site)},
+		"batch":         {[]string{"POST", "OPTIONS"}, "/batch", batchHandler(site)},
+		"forecast":      {[]string{"GET"}, "/forecast/battery", batteryForecastHandler(site)},
+		"widget":        {[]string{"GET"}, "/widget", widgetHandler(site, cache)},
+		"status":        {[]string{"GET"}, "/status/plain", plainStatusHandler(cache)},
+		"tariff":        {[]string{"POST", "OPTIONS"}, "/tariff/grid", gridRatesImportHandler(site)},
 		"telemetry":     {[]string{"GET"}, "/settings/telemetry", boolGetHandler(telemetry.Enabled)},
 		"telemetry2":    {[]string{"POST", "OPTIONS"}, "/settings/telemetry/{value:[a-z]+}", boolHandler(telemetry.Enable, telemetry.Enabled)},
+		"telemetry3":    {[]string{"GET"}, "/settings/telemetry/categories", telemetryCategoriesHandler},
+		"telemetry4":    {[]string{"POST", "OPTIONS"}, "/settings/telemetry/categories/{value:[a-z,]*}", telemetryCategoriesHandler},
+		"telemetry5":    {[]string{"GET"}, "/telemetry/preview", telemetryPreviewHandler},
+		"telemetry6":    {[]string{"GET"}, "/telemetry/aggregate", telemetryAggregateHandler},
+		"language":      {[]string{"GET"}, "/settings/language", languageHandler},
+		"language2":     {[]string{"POST", "OPTIONS"}, "/settings/language/{value:[a-zA-Z-]+}", languageHandler},
+		"plans":         {[]string{"GET"}, "/plans", plansHandler(site)},
+		"plans2":        {[]string{"POST", "OPTIONS"}, "/plans/{name}", planHandler(site, nil)},
 	}
 
-	for _, r := range routes {
-		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	if s.tenancy != nil {
+		routes["tenants"] = route{[]string{"GET"}, "/tenants", tenantsHandler(s.tenancy, site.Location())}
+	}
+
+	for name, r := range routes {
+		h := r.HandlerFunc
+		if !slices.Contains(tenantRoutes, name) {
+			h = adminHandler(h)
+		}
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(h)
 	}
 
 	// loadpoint api
 	for id, lp := range site.LoadPoints() {
 		loadpoint := api.PathPrefix(fmt.Sprintf("/loadpoints/%d", id)).Subrouter()
+		loadpoint.Use(loadpointHandler(id))
 
 		routes := map[string]route{
 			"mode":          {[]string{"POST", "OPTIONS"}, "/mode/{value:[a-z]+}", chargeModeHandler(lp)},
 			"targetenergy":  {[]string{"POST", "OPTIONS"}, "/targetenergy/{value:[0-9]+}", intHandler(pass(lp.SetTargetEnergy), lp.GetTargetEnergy)},
+			"targetrange":   {[]string{"POST", "OPTIONS"}, "/targetrange/{value:[0-9]+}", intHandler(lp.SetTargetRange, lp.GetTargetRange)},
 			"targetsoc":     {[]string{"POST", "OPTIONS"}, "/targetsoc/{value:[0-9]+}", intHandler(pass(lp.SetTargetSoC), lp.GetTargetSoC)},
 			"minsoc":        {[]string{"POST", "OPTIONS"}, "/minsoc/{value:[0-9]+}", intHandler(pass(lp.SetMinSoC), lp.GetMinSoC)},
 			"mincurrent":    {[]string{"POST", "OPTIONS"}, "/mincurrent/{value:[0-9]+}", floatHandler(pass(lp.SetMinCurrent), lp.GetMinCurrent)},
@@ -121,12 +222,20 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, cache *util.Cache) {
 			"phases":        {[]string{"POST", "OPTIONS"}, "/phases/{value:[0-9]+}", phasesHandler(lp)},
 			"targetcharge":  {[]string{"POST", "OPTIONS"}, "/targetcharge/{soc:[0-9]+}/{time:[0-9TZ:.-]+}", targetChargeHandler(lp)},
 			"targetcharge2": {[]string{"DELETE", "OPTIONS"}, "/targetcharge", targetChargeRemoveHandler(lp)},
+			"targetcharge3": {[]string{"POST", "OPTIONS"}, "/targetcharge/range/{range:[0-9]+}/{time:[0-9TZ:.-]+}", targetRangeChargeHandler(lp)},
+			"plan":          {[]string{"POST", "OPTIONS"}, "/plan/{name}", planHandler(site, lp)},
 			"vehicle":       {[]string{"POST", "OPTIONS"}, "/vehicle/{vehicle:[0-9]+}", vehicleHandler(site, lp)},
 			"vehicle2":      {[]string{"DELETE", "OPTIONS"}, "/vehicle", vehicleRemoveHandler(lp)},
 			"vehicleDetect": {[]string{"PATCH", "OPTIONS"}, "/vehicle", vehicleDetectHandler(lp)},
 			"remotedemand":  {[]string{"POST", "OPTIONS"}, "/remotedemand/{demand:[a-z]+}/{source::[0-9a-zA-Z_-]+}", remoteDemandHandler(lp)},
+			"session":       {[]string{"PUT", "OPTIONS"}, "/session", loadpointAnnotationHandler(lp)},
+			"wakeup":        {[]string{"POST", "OPTIONS"}, "/charger/wakeup", chargerWakeUpHandler(lp)},
 		}
 
+		// repeating plans must match before named plans
+		loadpoint.Methods(http.MethodGet).Path("/plan/repeating").Handler(repeatingPlansHandler(lp))
+		loadpoint.Methods(http.MethodPost, http.MethodOptions).Path("/plan/repeating").Handler(repeatingPlansUpdateHandler(lp))
+
 		for _, r := range routes {
 			loadpoint.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
 		}
@@ -134,6 +243,28 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, cache *util.Cache) {
 
 }
 
+// RegisterReloadHandler connects the configuration reload handler. Reloading requires admin access.
+func (s *HTTPd) RegisterReloadHandler(reload func() error) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
+	))
+	api.Use(s.tenancy.handler)
+
+	api.Methods(http.MethodPost, http.MethodOptions).Path("/reload").Handler(adminHandler(func(w http.ResponseWriter, r *http.Request) {
+		if err := reload(); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+		jsonResult(w, true)
+	}))
+}
+
 // RegisterShutdownHandler connects the http handlers to the site
 func (s *HTTPd) RegisterShutdownHandler(callback func()) {
 	router := s.Server.Handler.(*mux.Router)
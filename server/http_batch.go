@@ -0,0 +1,235 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+)
+
+// batchMu serializes batch requests
+var batchMu sync.Mutex
+
+// batchRequest is a list of setpoint changes applied with all-or-nothing semantics
+type batchRequest struct {
+	DryRun  bool          `json:"dryRun"`
+	Changes []batchChange `json:"changes"`
+}
+
+// batchChange is a single setpoint change. Loadpoint is omitted for site settings.
+type batchChange struct {
+	Loadpoint *int            `json:"loadpoint,omitempty"`
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// batchOp applies a validated change and reverts it if a later change fails
+type batchOp struct {
+	apply  func() error
+	revert func() error
+}
+
+// undoable creates a batch operation restoring the previous value on revert
+func undoable[T any](get func() T, set func(T) error, val T) batchOp {
+	var old T
+	return batchOp{
+		apply: func() error {
+			old = get()
+			return set(val)
+		},
+		revert: func() error {
+			return set(old)
+		},
+	}
+}
+
+func decodeRange[T int | float64](raw json.RawMessage, min, max T) (T, error) {
+	var val T
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return val, err
+	}
+	if val < min || val > max {
+		return val, fmt.Errorf("value %v not in [%v..%v]", val, min, max)
+	}
+	return val, nil
+}
+
+// siteBatchOp validates a site setpoint change
+func siteBatchOp(site site.API, c batchChange) (batchOp, error) {
+	var op batchOp
+
+	val, err := decodeRange(c.Value, -1e6, 1e6)
+	if err != nil {
+		return op, err
+	}
+
+	switch c.Key {
+	case "bufferSoC":
+		if val < 0 || val > 100 {
+			return op, fmt.Errorf("value %v not in [0..100]", val)
+		}
+		op = undoable(site.GetBufferSoC, site.SetBufferSoC, val)
+	case "prioritySoC":
+		if val < 0 || val > 100 {
+			return op, fmt.Errorf("value %v not in [0..100]", val)
+		}
+		op = undoable(site.GetPrioritySoC, site.SetPrioritySoC, val)
+	case "residualPower":
+		op = undoable(site.GetResidualPower, site.SetResidualPower, val)
+	default:
+		return op, errors.New("invalid key")
+	}
+
+	return op, nil
+}
+
+type targetCharge struct {
+	SoC  int       `json:"soc"`
+	Time time.Time `json:"time"`
+}
+
+// loadpointBatchOp validates a loadpoint setpoint change
+func loadpointBatchOp(lp loadpoint.API, c batchChange) (batchOp, error) {
+	var op batchOp
+
+	switch c.Key {
+	case "mode":
+		var s string
+		if err := json.Unmarshal(c.Value, &s); err != nil {
+			return op, err
+		}
+		mode, err := api.ChargeModeString(s)
+		if err != nil {
+			return op, err
+		}
+		op = undoable(lp.GetMode, pass(lp.SetMode), mode)
+
+	case "targetEnergy":
+		val, err := decodeRange(c.Value, 0, 1000)
+		if err != nil {
+			return op, err
+		}
+		op = undoable(lp.GetTargetEnergy, pass(lp.SetTargetEnergy), val)
+
+	case "targetSoC", "minSoC":
+		val, err := decodeRange(c.Value, 0, 100)
+		if err != nil {
+			return op, err
+		}
+		if c.Key == "targetSoC" {
+			op = undoable(lp.GetTargetSoC, pass(lp.SetTargetSoC), val)
+		} else {
+			op = undoable(lp.GetMinSoC, pass(lp.SetMinSoC), val)
+		}
+
+	case "minCurrent", "maxCurrent":
+		val, err := decodeRange(c.Value, 0.0, 1000)
+		if err != nil {
+			return op, err
+		}
+		if c.Key == "minCurrent" {
+			op = undoable(lp.GetMinCurrent, pass(lp.SetMinCurrent), val)
+		} else {
+			op = undoable(lp.GetMaxCurrent, pass(lp.SetMaxCurrent), val)
+		}
+
+	case "phases":
+		val, err := decodeRange(c.Value, 0, 3)
+		if err != nil {
+			return op, err
+		}
+		if val == 2 {
+			return op, errors.New("invalid phases: 2")
+		}
+		op = undoable(lp.GetPhases, lp.SetPhases, val)
+
+	case "targetCharge":
+		var val targetCharge
+		if err := json.Unmarshal(c.Value, &val); err != nil {
+			return op, err
+		}
+		if val.SoC < 0 || val.SoC > 100 {
+			return op, fmt.Errorf("soc %d not in [0..100]", val.SoC)
+		}
+
+		get := func() targetCharge {
+			return targetCharge{SoC: lp.GetTargetSoC(), Time: lp.GetTargetTime()}
+		}
+		set := func(v targetCharge) error {
+			lp.SetTargetCharge(v.Time, v.SoC)
+			return nil
+		}
+		op = undoable(get, set, val)
+
+	default:
+		return op, errors.New("invalid key")
+	}
+
+	return op, nil
+}
+
+// batchHandler validates all changes and applies them with all-or-nothing semantics
+func batchHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req batchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		loadpoints := site.LoadPoints()
+
+		// validate all changes before applying any
+		ops := make([]batchOp, 0, len(req.Changes))
+		for i, c := range req.Changes {
+			var op batchOp
+			var err error
+
+			switch {
+			case c.Loadpoint == nil:
+				op, err = siteBatchOp(site, c)
+			case *c.Loadpoint < 0 || *c.Loadpoint >= len(loadpoints):
+				err = fmt.Errorf("invalid loadpoint: %d", *c.Loadpoint)
+			default:
+				op, err = loadpointBatchOp(loadpoints[*c.Loadpoint], c)
+			}
+
+			if err != nil {
+				jsonError(w, http.StatusBadRequest, fmt.Errorf("change %d (%s): %w", i, c.Key, err))
+				return
+			}
+
+			ops = append(ops, op)
+		}
+
+		if req.DryRun {
+			jsonResult(w, struct{}{})
+			return
+		}
+
+		batchMu.Lock()
+		defer batchMu.Unlock()
+
+		for i, op := range ops {
+			if err := op.apply(); err != nil {
+				// revert applied changes in reverse order
+				for j := i - 1; j >= 0; j-- {
+					if err := ops[j].revert(); err != nil {
+						log.ERROR.Printf("batch: revert change %d: %v", j, err)
+					}
+				}
+
+				jsonError(w, http.StatusInternalServerError, fmt.Errorf("change %d (%s): %w", i, req.Changes[i].Key, err))
+				return
+			}
+		}
+
+		jsonResult(w, struct{}{})
+	}
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchUndoable(t *testing.T) {
+	val := 1
+	get := func() int { return val }
+	set := func(v int) error {
+		val = v
+		return nil
+	}
+
+	op := undoable(get, set, 2)
+
+	assert.NoError(t, op.apply())
+	assert.Equal(t, 2, val)
+
+	assert.NoError(t, op.revert())
+	assert.Equal(t, 1, val)
+}
+
+func TestBatchDecodeRange(t *testing.T) {
+	_, err := decodeRange(json.RawMessage("101"), 0, 100)
+	assert.Error(t, err)
+
+	res, err := decodeRange(json.RawMessage("6.5"), 0.0, 32)
+	assert.NoError(t, err)
+	assert.Equal(t, 6.5, res)
+}
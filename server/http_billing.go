@@ -0,0 +1,135 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/evcc-io/evcc/core/db"
+	dbserver "github.com/evcc-io/evcc/server/db"
+	"github.com/gorilla/mux"
+	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
+)
+
+// billingPeriodsHandler returns the closed billing periods
+func billingPeriodsHandler(w http.ResponseWriter, r *http.Request) {
+	if dbserver.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
+
+	res, err := db.Periods(dbserver.Instance)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	jsonResult(w, res)
+}
+
+// billingStatementHandler returns the statement of a closed billing period grouped by tenant, loadpoint, vehicle or tag
+func billingStatementHandler(tenancy *Tenancy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if dbserver.Instance == nil {
+			jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+			return
+		}
+
+		id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		period, opening, sessions, err := db.PeriodSessions(dbserver.Instance, uint(id))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			jsonError(w, http.StatusNotFound, err)
+			return
+		}
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		// tenants only see their sessions
+		tn := requestTenant(r)
+		if tn != nil {
+			var filtered db.Sessions
+			for _, s := range sessions {
+				if slices.Contains(tn.Loadpoints, s.Loadpoint) {
+					filtered = append(filtered, s)
+				}
+			}
+			sessions = filtered
+		}
+
+		group := r.URL.Query().Get("group")
+		if group == "" {
+			group = "loadpoint"
+			if tenancy != nil {
+				group = "tenant"
+			}
+		}
+
+		res := db.Statement{
+			Period:  period,
+			Opening: opening,
+			Groups:  make([]db.StatementGroup, 0),
+		}
+
+		meterEnergy := func(loadpoints ...string) *float64 {
+			if energy, ok := period.MeterEnergy(opening, loadpoints...); ok {
+				return &energy
+			}
+			return nil
+		}
+
+		if group == "tenant" {
+			if tenancy == nil {
+				jsonError(w, http.StatusBadRequest, errors.New("tenancy not configured"))
+				return
+			}
+
+			for _, t := range tenancy.tenants {
+				if tn != nil && t != tn {
+					continue
+				}
+
+				sg := db.StatementGroup{
+					SessionStats: db.SessionStats{Group: t.Name},
+					MeterEnergy:  meterEnergy(t.Loadpoints...),
+				}
+
+				for _, s := range sessions {
+					if slices.Contains(t.Loadpoints, s.Loadpoint) {
+						sg.Sessions++
+						sg.ChargedEnergy += s.ChargedEnergy
+						sg.Price += s.Price
+					}
+				}
+
+				res.Groups = append(res.Groups, sg)
+			}
+
+			jsonResult(w, res)
+			return
+		}
+
+		stats, err := sessions.Stats(group)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		for _, st := range stats {
+			sg := db.StatementGroup{SessionStats: st}
+			if group == "loadpoint" {
+				sg.MeterEnergy = meterEnergy(st.Group)
+			}
+			res.Groups = append(res.Groups, sg)
+		}
+
+		jsonResult(w, res)
+	}
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+)
+
+var (
+	// ErrDeviceNotFound indicates that a device is not managed by the config api
+	ErrDeviceNotFound = errors.New("device not found")
+
+	// ErrDeviceInUse indicates that a device cannot be changed while it is used
+	ErrDeviceInUse = errors.New("device in use")
+)
+
+// DeviceConfig is a template-based device configuration managed at runtime
+type DeviceConfig struct {
+	Class string                 `json:"class"`
+	Name  string                 `json:"name"`
+	Other map[string]interface{} `json:"config"`
+}
+
+// DeviceConfigurator creates, updates, tests and deletes devices at runtime
+type DeviceConfigurator interface {
+	Devices() []DeviceConfig
+	AddDevice(DeviceConfig) error
+	UpdateDevice(DeviceConfig) error
+	DeleteDevice(class, name string) error
+	TestDevice(DeviceConfig) (map[string]interface{}, error)
+}
+
+// RegisterConfigHandlers connects the http handlers to the device configurator. All routes require admin access.
+func (s *HTTPd) RegisterConfigHandlers(dc DeviceConfigurator) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api/config").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
+	))
+	api.Use(s.tenancy.handler)
+
+	routes := map[string]route{
+		"devices":  {[]string{"GET"}, "/devices", devicesHandler(dc)},
+		"devices2": {[]string{"POST", "OPTIONS"}, "/devices", deviceAddHandler(dc)},
+		"devices3": {[]string{"POST", "OPTIONS"}, "/devices/test", deviceTestHandler(dc)},
+		"devices4": {[]string{"PUT", "OPTIONS"}, "/devices/{class:[a-z]+}/{name}", deviceUpdateHandler(dc)},
+		"devices5": {[]string{"DELETE", "OPTIONS"}, "/devices/{class:[a-z]+}/{name}", deviceDeleteHandler(dc)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(adminHandler(r.HandlerFunc))
+	}
+}
+
+// deviceStatus maps configurator errors to http status codes
+func deviceStatus(err error) int {
+	if errors.Is(err, ErrDeviceNotFound) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, ErrDeviceInUse) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+// decodeDevice decodes the device configuration from the request body
+func decodeDevice(r *http.Request) (DeviceConfig, error) {
+	var res DeviceConfig
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return res, err
+	}
+	if res.Class == "" || res.Name == "" {
+		return res, errors.New("missing class or name")
+	}
+	return res, nil
+}
+
+// devicesHandler lists the runtime configured devices
+func devicesHandler(dc DeviceConfigurator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := dc.Devices()
+		if res == nil {
+			res = []DeviceConfig{}
+		}
+		jsonResult(w, res)
+	}
+}
+
+// deviceAddHandler creates a device
+func deviceAddHandler(dc DeviceConfigurator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		device, err := decodeDevice(r)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := dc.AddDevice(device); err != nil {
+			jsonError(w, deviceStatus(err), err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		jsonResult(w, device)
+	}
+}
+
+// deviceUpdateHandler replaces the configuration of an existing device
+func deviceUpdateHandler(dc DeviceConfigurator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		var device DeviceConfig
+		if err := json.NewDecoder(r.Body).Decode(&device); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		device.Class = vars["class"]
+		device.Name = vars["name"]
+
+		if err := dc.UpdateDevice(device); err != nil {
+			jsonError(w, deviceStatus(err), err)
+			return
+		}
+
+		jsonResult(w, device)
+	}
+}
+
+// deviceDeleteHandler removes a device
+func deviceDeleteHandler(dc DeviceConfigurator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		if err := dc.DeleteDevice(vars["class"], vars["name"]); err != nil {
+			jsonError(w, deviceStatus(err), err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// deviceTestHandler creates a device without storing it and returns its current values
+func deviceTestHandler(dc DeviceConfigurator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		device, err := decodeDevice(r)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := dc.TestDevice(device)
+		if err != nil {
+			jsonError(w, deviceStatus(err), err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConfigurator struct {
+	devices []DeviceConfig
+}
+
+func (f *fakeConfigurator) Devices() []DeviceConfig {
+	return f.devices
+}
+
+func (f *fakeConfigurator) AddDevice(dc DeviceConfig) error {
+	if dc.Class != "meter" {
+		return errors.New("invalid class")
+	}
+	f.devices = append(f.devices, dc)
+	return nil
+}
+
+func (f *fakeConfigurator) UpdateDevice(dc DeviceConfig) error {
+	for i, d := range f.devices {
+		if d.Class == dc.Class && d.Name == dc.Name {
+			f.devices[i] = dc
+			return nil
+		}
+	}
+	return ErrDeviceNotFound
+}
+
+func (f *fakeConfigurator) DeleteDevice(class, name string) error {
+	for i, d := range f.devices {
+		if d.Class == class && d.Name == name {
+			f.devices = append(f.devices[:i], f.devices[i+1:]...)
+			return nil
+		}
+	}
+	return ErrDeviceNotFound
+}
+
+func (f *fakeConfigurator) TestDevice(dc DeviceConfig) (map[string]interface{}, error) {
+	return map[string]interface{}{"power": 1000.0}, nil
+}
+
+func TestConfigHandlers(t *testing.T) {
+	dc := new(fakeConfigurator)
+	srv := NewHTTPd(":0", nil)
+	srv.RegisterConfigHandlers(dc)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	device := `{"class":"meter","name":"grid","config":{"template":"demo"}}`
+
+	w := do(http.MethodPost, "/api/config/devices", device)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	require.Len(t, dc.devices, 1)
+	assert.Equal(t, "demo", dc.devices[0].Other["template"])
+
+	w = do(http.MethodPost, "/api/config/devices", `{"class":"meter"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do(http.MethodPost, "/api/config/devices", `{"class":"foo","name":"bar"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do(http.MethodGet, "/api/config/devices", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var res struct {
+		Result []DeviceConfig
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, dc.devices, res.Result)
+
+	w = do(http.MethodPut, "/api/config/devices/meter/grid", `{"config":{"template":"other"}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "other", dc.devices[0].Other["template"])
+
+	w = do(http.MethodPut, "/api/config/devices/meter/pv", `{"config":{}}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = do(http.MethodPost, "/api/config/devices/test", device)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"result":{"power":1000}}`, w.Body.String())
+
+	w = do(http.MethodDelete, "/api/config/devices/meter/grid", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, dc.devices)
+
+	w = do(http.MethodDelete, "/api/config/devices/meter/grid", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
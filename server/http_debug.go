@@ -0,0 +1,136 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"regexp"
+	"runtime"
+	rpprof "runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// DeviceLabel is the profiler label identifying the device which started a goroutine
+const DeviceLabel = "device"
+
+// WithDeviceLabel runs fn with the device's profiler label. Goroutines started by fn inherit the label.
+func WithDeviceLabel(device string, fn func()) {
+	rpprof.Do(context.Background(), rpprof.Labels(DeviceLabel, device), func(context.Context) {
+		fn()
+	})
+}
+
+var deviceLabelRegex = regexp.MustCompile(`"` + DeviceLabel + `":("(?:[^"\\]|\\.)*")`)
+
+// deviceGoroutines returns the number of goroutines by device label
+func deviceGoroutines() (map[string]int, error) {
+	var b bytes.Buffer
+	if err := rpprof.Lookup("goroutine").WriteTo(&b, 1); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]int)
+
+	// goroutines are grouped by stack, each group is followed by its labels
+	var count int
+	scanner := bufio.NewScanner(&b)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if i := strings.Index(line, " @ "); i > 0 {
+			count, _ = strconv.Atoi(line[:i])
+			continue
+		}
+
+		if !strings.HasPrefix(line, "# labels: ") {
+			continue
+		}
+
+		if m := deviceLabelRegex.FindStringSubmatch(line); m != nil {
+			if device, err := strconv.Unquote(m[1]); err == nil {
+				res[device] += count
+			}
+		}
+	}
+
+	return res, scanner.Err()
+}
+
+type runtimeStats struct {
+	Goroutines       int            `json:"goroutines"`
+	DeviceGoroutines map[string]int `json:"deviceGoroutines"`
+	HeapAlloc        uint64         `json:"heapAlloc"`
+	HeapInuse        uint64         `json:"heapInuse"`
+	HeapObjects      uint64         `json:"heapObjects"`
+	Sys              uint64         `json:"sys"`
+	NumGC            uint32         `json:"numGC"`
+	LastGC           time.Time      `json:"lastGC"`
+	PauseTotal       time.Duration  `json:"pauseTotal"`
+}
+
+// runtimeHandler returns a summary of the go runtime's goroutine and memory statistics
+func runtimeHandler(w http.ResponseWriter, r *http.Request) {
+	devices, err := deviceGoroutines()
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	res := runtimeStats{
+		Goroutines:       runtime.NumGoroutine(),
+		DeviceGoroutines: devices,
+		HeapAlloc:        ms.HeapAlloc,
+		HeapInuse:        ms.HeapInuse,
+		HeapObjects:      ms.HeapObjects,
+		Sys:              ms.Sys,
+		NumGC:            ms.NumGC,
+		PauseTotal:       time.Duration(ms.PauseTotalNs),
+	}
+
+	if ms.LastGC > 0 {
+		res.LastGC = time.Unix(0, int64(ms.LastGC))
+	}
+
+	jsonResult(w, res)
+}
+
+// RegisterProfileHandlers exposes the pprof profiles and runtime statistics. If tenancy is configured, they require admin access,
+// otherwise they require the network credentials. Without either, they are not exposed.
+// Must be called after SetTenancy.
+func (s *HTTPd) RegisterProfileHandlers(cred Credentials) error {
+	auth := s.tenancy.handler
+	if s.tenancy == nil {
+		if cred.User == "" || cred.Password == "" {
+			return errors.New("profiling requires tenancy or network user and password")
+		}
+
+		auth = func(h http.Handler) http.Handler {
+			return basicAuthHandler(h, cred)
+		}
+	}
+
+	router := s.Server.Handler.(*mux.Router)
+
+	router.Methods("GET").Path("/api/debug/runtime").Handler(auth(adminHandler(runtimeHandler)))
+
+	debug := router.PathPrefix("/debug/pprof").Subrouter()
+	debug.Use(auth)
+
+	debug.Handle("/cmdline", adminHandler(pprof.Cmdline))
+	debug.Handle("/profile", adminHandler(pprof.Profile))
+	debug.Handle("/symbol", adminHandler(pprof.Symbol))
+	debug.Handle("/trace", adminHandler(pprof.Trace))
+	debug.PathPrefix("/").Handler(adminHandler(pprof.Index))
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceGoroutines(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+
+	WithDeviceLabel(`meter."grid"`, func() {
+		for i := 0; i < 2; i++ {
+			go func() { <-done }()
+		}
+	})
+
+	res, err := deviceGoroutines()
+	require.NoError(t, err)
+	assert.Equal(t, 2, res[`meter."grid"`])
+}
+
+func TestProfileHandlers(t *testing.T) {
+	tenancy := &Tenancy{admin: "admin", tenants: []*tenant{{TenantConfig: TenantConfig{Name: "flat1", Token: "secret1"}}}}
+
+	s := &HTTPd{Server: &http.Server{Handler: mux.NewRouter()}, hub: new(SocketHub)}
+	s.SetTenancy(tenancy)
+	require.NoError(t, s.RegisterProfileHandlers(Credentials{}))
+
+	tc := []struct {
+		token  string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"secret1", http.StatusForbidden},
+		{"admin", http.StatusOK},
+	}
+
+	for _, tc := range tc {
+		for _, path := range []string{"/debug/pprof/goroutine?debug=1", "/api/debug/runtime"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			rr := httptest.NewRecorder()
+			s.Handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.status, rr.Code, path, tc)
+		}
+	}
+}
+
+func TestProfileHandlersCredentials(t *testing.T) {
+	s := &HTTPd{Server: &http.Server{Handler: mux.NewRouter()}, hub: new(SocketHub)}
+
+	// not exposed without authentication
+	assert.Error(t, s.RegisterProfileHandlers(Credentials{}))
+
+	require.NoError(t, s.RegisterProfileHandlers(Credentials{User: "admin", Password: "secret"}))
+
+	tc := []struct {
+		user, password string
+		status         int
+	}{
+		{"", "", http.StatusUnauthorized},
+		{"admin", "wrong", http.StatusUnauthorized},
+		{"admin", "secret", http.StatusOK},
+	}
+
+	for _, tc := range tc {
+		for _, path := range []string{"/debug/pprof/goroutine?debug=1", "/api/debug/runtime"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tc.user != "" {
+				req.SetBasicAuth(tc.user, tc.password)
+			}
+
+			rr := httptest.NewRecorder()
+			s.Handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.status, rr.Code, path, tc)
+		}
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"net/http"
 	"strconv"
+	"strings"
 	"text/template"
 	"time"
 
@@ -18,7 +20,9 @@ import (
 	dbserver "github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/locale"
+	"github.com/evcc-io/evcc/util/telemetry"
 	"github.com/gorilla/mux"
+	"gorm.io/gorm"
 )
 
 func indexHandler() http.HandlerFunc {
@@ -175,33 +179,319 @@ func stateHandler(cache *util.Cache) http.HandlerFunc {
 		for _, k := range []string{"availableVersion", "releaseNotes"} {
 			delete(res, k)
 		}
+		if tn := requestTenant(r); tn != nil {
+			res = tn.state(res)
+		}
 		jsonResult(w, res)
 	}
 }
 
+// telemetryCategoriesHandler returns and optionally updates the comma-separated telemetry categories
+func telemetryCategoriesHandler(w http.ResponseWriter, r *http.Request) {
+	if val, ok := mux.Vars(r)["value"]; ok {
+		categories := make([]string, 0)
+		if val != "" {
+			categories = strings.Split(val, ",")
+		}
+
+		if err := telemetry.SetCategories(categories); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	jsonResult(w, telemetry.Categories())
+}
+
+// telemetryPreviewHandler returns the telemetry data that would be sent
+func telemetryPreviewHandler(w http.ResponseWriter, r *http.Request) {
+	jsonResult(w, telemetry.Preview())
+}
+
+// telemetryAggregateHandler returns the locally aggregated anonymized telemetry
+func telemetryAggregateHandler(w http.ResponseWriter, r *http.Request) {
+	jsonResult(w, telemetry.Aggregate())
+}
+
+// languageHandler returns and optionally updates the default language
+func languageHandler(w http.ResponseWriter, r *http.Request) {
+	if lang, ok := mux.Vars(r)["value"]; ok {
+		if err := locale.SetLanguage(lang); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	res := struct {
+		Language  string   `json:"language"`
+		Languages []string `json:"languages"`
+	}{
+		Language:  locale.CurrentLanguage(),
+		Languages: locale.Languages(),
+	}
+
+	jsonResult(w, res)
+}
+
+// batteryForecastHandler returns the battery soc forecast
+func batteryForecastHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		horizon := 24 * time.Hour
+
+		if val := r.URL.Query().Get("hours"); val != "" {
+			hours, err := strconv.Atoi(val)
+			if err != nil {
+				jsonError(w, http.StatusBadRequest, err)
+				return
+			}
+			horizon = time.Duration(hours) * time.Hour
+		}
+
+		jsonResult(w, site.GetBatteryForecast(horizon))
+	}
+}
+
+// maxImportSize limits the size of uploaded files
+const maxImportSize = 1 << 20
+
+// gridRatesImportHandler imports a csv or xlsx grid price schedule from the request body
+func gridRatesImportHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+		if err == nil {
+			err = site.ImportGridRates(b)
+		}
+
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, site.GetGridRates(48*time.Hour))
+	}
+}
+
 // sessionHandler returns the list of charging sessions
-func sessionHandler(w http.ResponseWriter, r *http.Request) {
+func sessionHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if dbserver.Instance == nil {
+			jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+			return
+		}
+
+		q, err := sessionQuery(r, site.Location())
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, next, err := q.Find(dbserver.Instance)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res = res.In(site.Location())
+
+		if r.URL.Query().Get("format") == "csv" {
+			accept := r.Header.Get("Accept-Language")
+			ctx := context.WithValue(context.Background(), locale.Locale, accept)
+			csvResult(ctx, w, &res)
+			return
+		}
+
+		if next != "" {
+			jsonWrite(w, map[string]interface{}{"result": res, "next": next})
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// sessionAnnotationHandler attaches tags and note to a persisted session
+func sessionAnnotationHandler(w http.ResponseWriter, r *http.Request) {
 	if dbserver.Instance == nil {
 		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
 		return
 	}
 
-	var res db.Sessions
-	if txn := dbserver.Instance.Where("charged_kwh>=0.05").Order("created desc").Find(&res); txn.Error != nil {
-		jsonError(w, http.StatusInternalServerError, txn.Error)
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	if r.URL.Query().Get("format") == "csv" {
-		accept := r.Header.Get("Accept-Language")
-		ctx := context.WithValue(context.Background(), locale.Locale, accept)
-		csvResult(ctx, w, &res)
+	// tenants may only annotate sessions of their loadpoints
+	if tn := requestTenant(r); tn != nil {
+		var session db.Session
+		if err := dbserver.Instance.Where("loadpoint IN ?", tn.Loadpoints).First(&session, id).Error; err != nil {
+			jsonError(w, http.StatusNotFound, err)
+			return
+		}
+	}
+
+	var annotation db.Annotation
+	if err := json.NewDecoder(r.Body).Decode(&annotation); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := db.Annotate(dbserver.Instance, uint(id), annotation)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+	if errors.Is(err, db.ErrFrozen) {
+		jsonError(w, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
 		return
 	}
 
 	jsonResult(w, res)
 }
 
+// sessionStatsHandler returns the filtered sessions grouped by loadpoint, vehicle or tag
+func sessionStatsHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if dbserver.Instance == nil {
+			jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+			return
+		}
+
+		q, err := sessionQuery(r, site.Location())
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		sessions, _, err := q.Find(dbserver.Instance)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		group := r.URL.Query().Get("group")
+		if group == "" {
+			group = "tag"
+		}
+
+		res, err := sessions.Stats(group)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// sessionSavingsHandler returns the sessions' savings compared to the reference price and charging from grid only
+func sessionSavingsHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if dbserver.Instance == nil {
+			jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+			return
+		}
+
+		q, err := sessionQuery(r, site.Location())
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		sessions, _, err := q.Find(dbserver.Instance)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		group := r.URL.Query().Get("group")
+		if group == "" {
+			group = "total"
+		}
+
+		res, err := sessions.Savings(group, site.GetReferencePrice())
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// sessionQuery parses session filter, sort and pagination parameters. Dates are interpreted in loc.
+func sessionQuery(r *http.Request, loc *time.Location) (db.SessionQuery, error) {
+	query := r.URL.Query()
+
+	q := db.SessionQuery{
+		Loadpoint: query.Get("loadpoint"),
+		Vehicle:   query.Get("vehicle"),
+		Tag:       query.Get("tag"),
+		Sort:      query.Get("sort"),
+		Cursor:    query.Get("cursor"),
+	}
+
+	if tn := requestTenant(r); tn != nil {
+		q.Loadpoints = tn.Loadpoints
+	}
+
+	for key, ts := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+		if val := query.Get(key); val != "" {
+			t, err := parseDate(val, loc)
+			if err != nil {
+				return q, fmt.Errorf("invalid %s: %s", key, val)
+			}
+			*ts = t
+		}
+	}
+
+	if val := query.Get("limit"); val != "" {
+		limit, err := strconv.Atoi(val)
+		if err != nil {
+			return q, fmt.Errorf("invalid limit: %s", val)
+		}
+		q.Limit = limit
+	}
+
+	return q, nil
+}
+
+// parseDate parses RFC3339 timestamps or dates in loc
+func parseDate(val string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, val); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", val, loc)
+}
+
+// loadpointAnnotationHandler attaches tags and note to the loadpoint's current session
+func loadpointAnnotationHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var annotation db.Annotation
+		if err := json.NewDecoder(r.Body).Decode(&annotation); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		lp.AnnotateSession(annotation.Tags, annotation.Note)
+
+		jsonResult(w, annotation)
+	}
+}
+
+// chargerWakeUpHandler powers up the charger from standby
+func chargerWakeUpHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lp.WakeCharger()
+		jsonResult(w, struct{}{})
+	}
+}
+
 // chargeModeHandler updates charge mode
 func chargeModeHandler(lp loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -299,6 +589,44 @@ func targetChargeHandler(loadpoint targetCharger) http.HandlerFunc {
 	}
 }
 
+// targetRangeChargeHandler updates target range and time
+func targetRangeChargeHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		rangeV, err := strconv.Atoi(vars["range"])
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		timeV, err := time.Parse(time.RFC3339, vars["time"])
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := lp.SetTargetRange(rangeV); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		lp.SetTargetCharge(timeV, lp.GetTargetSoC())
+
+		res := struct {
+			Range int       `json:"range"`
+			SoC   int       `json:"soc"`
+			Time  time.Time `json:"time"`
+		}{
+			Range: rangeV,
+			SoC:   lp.GetTargetSoC(),
+			Time:  timeV,
+		}
+
+		jsonResult(w, res)
+	}
+}
+
 // targetChargeRemoveHandler removes target soc
 func targetChargeRemoveHandler(loadpoint loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -308,6 +636,130 @@ func targetChargeRemoveHandler(loadpoint loadpoint.API) http.HandlerFunc {
 	}
 }
 
+// batteryPriorityHandler returns the battery priority
+func batteryPriorityHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonResult(w, site.GetBatteryPriority())
+	}
+}
+
+// batteryPriorityUpdateHandler sets the battery priority, omitted fields keep their value
+func batteryPriorityUpdateHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p := site.GetBatteryPriority()
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := site.SetBatteryPriority(p); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, site.GetBatteryPriority())
+	}
+}
+
+// circuitsHandler returns the circuits' limits and assigned load
+func circuitsHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonResult(w, site.GetCircuits())
+	}
+}
+
+// circuitUpdateHandler sets the circuit's limits, omitted fields keep their value. The applied limits are returned.
+func circuitUpdateHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["name"]
+
+		circuits := site.GetCircuits()
+
+		idx := -1
+		for i, c := range circuits {
+			if c.Name == name {
+				idx = i
+			}
+		}
+
+		if idx < 0 {
+			jsonError(w, http.StatusNotFound, fmt.Errorf("circuit not found: %s", name))
+			return
+		}
+
+		limits := circuits[idx].CircuitLimits
+		if err := json.NewDecoder(r.Body).Decode(&limits); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := site.SetCircuitLimits(name, limits); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		// limits may have been lowered to the configured limits
+		for _, c := range site.GetCircuits() {
+			if c.Name == name {
+				limits = c.CircuitLimits
+			}
+		}
+
+		jsonResult(w, limits)
+	}
+}
+
+// plansHandler returns the plan templates
+func plansHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonResult(w, site.GetPlans())
+	}
+}
+
+// planHandler applies a plan template to the loadpoint, or to the plan's loadpoints if nil
+func planHandler(site site.API, lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ts, err := site.ApplyPlan(mux.Vars(r)["name"], lp)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res := struct {
+			Time time.Time `json:"time"`
+		}{
+			Time: ts,
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// repeatingPlansHandler returns the recurring target charges of the loadpoint's active vehicle
+func repeatingPlansHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonResult(w, lp.GetRepeatingPlans())
+	}
+}
+
+// repeatingPlansUpdateHandler replaces the recurring target charges of the loadpoint's active vehicle
+func repeatingPlansUpdateHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var plans []loadpoint.RepeatingPlan
+		if err := json.NewDecoder(r.Body).Decode(&plans); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := lp.SetRepeatingPlans(plans); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, lp.GetRepeatingPlans())
+	}
+}
+
 // vehicleHandler sets active vehicle
 func vehicleHandler(site site.API, loadpoint loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -322,6 +774,11 @@ func vehicleHandler(site site.API, loadpoint loadpoint.API) http.HandlerFunc {
 			return
 		}
 
+		if tn := requestTenant(r); tn != nil && !tn.vehicles[val] {
+			jsonError(w, http.StatusForbidden, errors.New("vehicle not accessible"))
+			return
+		}
+
 		loadpoint.SetVehicle(vehicles[val])
 
 		res := struct {
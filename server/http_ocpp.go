@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+)
+
+var (
+	// ErrChargePointNotFound indicates that a chargepoint is not waiting for registration or not registered
+	ErrChargePointNotFound = errors.New("charge point not found")
+
+	// ErrRestartRequired indicates that a change has been stored but is applied after restart
+	ErrRestartRequired = errors.New("restart required")
+)
+
+// OCPPRegistration maps an accepted chargepoint to a new loadpoint
+type OCPPRegistration struct {
+	StationID string `json:"stationId"`
+	Title     string `json:"title"`              // loadpoint title
+	Template  string `json:"template,omitempty"` // loadpoint and charger defaults
+}
+
+// OCPPRegistrar accepts or rejects unknown ocpp chargepoints
+type OCPPRegistrar interface {
+	Pending() []ocpp.PendingChargePoint
+	Registrations() []OCPPRegistration
+	Templates() []string
+	Accept(OCPPRegistration) error
+	Reject(id string) error
+	Remove(id string) error
+}
+
+// RegisterOCPPHandlers connects the http handlers to the ocpp registrar. All routes require admin access.
+func (s *HTTPd) RegisterOCPPHandlers(or OCPPRegistrar) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api/ocpp").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
+	))
+	api.Use(s.tenancy.handler)
+
+	routes := map[string]route{
+		"pending":       {[]string{"GET"}, "/pending", ocppPendingHandler(or)},
+		"pending2":      {[]string{"POST", "OPTIONS"}, "/pending/{id}", ocppAcceptHandler(or)},
+		"pending3":      {[]string{"DELETE", "OPTIONS"}, "/pending/{id}", ocppRejectHandler(or)},
+		"registrations": {[]string{"GET"}, "/registrations", ocppRegistrationsHandler(or)},
+		"registration":  {[]string{"DELETE", "OPTIONS"}, "/registrations/{id}", ocppRemoveHandler(or)},
+		"templates":     {[]string{"GET"}, "/templates", ocppTemplatesHandler(or)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(adminHandler(r.HandlerFunc))
+	}
+}
+
+// ocppStatus maps registrar errors to http status codes
+func ocppStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrChargePointNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrRestartRequired):
+		return http.StatusAccepted
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// ocppPendingHandler lists the chargepoints waiting for registration
+func ocppPendingHandler(or OCPPRegistrar) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonResult(w, or.Pending())
+	}
+}
+
+// ocppAcceptHandler accepts a pending chargepoint as new loadpoint
+func ocppAcceptHandler(or OCPPRegistrar) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var reg OCPPRegistration
+		if err := json.NewDecoder(r.Body).Decode(&reg); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		reg.StationID = mux.Vars(r)["id"]
+
+		if err := or.Accept(reg); err != nil {
+			jsonError(w, ocppStatus(err), err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		jsonResult(w, reg)
+	}
+}
+
+// ocppRejectHandler rejects a pending chargepoint
+func ocppRejectHandler(or OCPPRegistrar) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := or.Reject(mux.Vars(r)["id"]); err != nil {
+			jsonError(w, ocppStatus(err), err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ocppRegistrationsHandler lists the accepted chargepoints
+func ocppRegistrationsHandler(or OCPPRegistrar) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := or.Registrations()
+		if res == nil {
+			res = []OCPPRegistration{}
+		}
+		jsonResult(w, res)
+	}
+}
+
+// ocppRemoveHandler removes an accepted chargepoint and its loadpoint
+func ocppRemoveHandler(or OCPPRegistrar) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := or.Remove(mux.Vars(r)["id"]); err != nil {
+			jsonError(w, ocppStatus(err), err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ocppTemplatesHandler lists the registration templates
+func ocppTemplatesHandler(or OCPPRegistrar) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := or.Templates()
+		if res == nil {
+			res = []string{}
+		}
+		jsonResult(w, res)
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
+)
+
+type fakeRegistrar struct {
+	pending       []ocpp.PendingChargePoint
+	registrations []OCPPRegistration
+	restart       bool
+}
+
+func (f *fakeRegistrar) Pending() []ocpp.PendingChargePoint {
+	return f.pending
+}
+
+func (f *fakeRegistrar) Registrations() []OCPPRegistration {
+	return f.registrations
+}
+
+func (f *fakeRegistrar) Templates() []string {
+	return []string{"default"}
+}
+
+func (f *fakeRegistrar) remove(id string) bool {
+	idx := slices.IndexFunc(f.pending, func(p ocpp.PendingChargePoint) bool {
+		return p.ID == id
+	})
+	if idx >= 0 {
+		f.pending = slices.Delete(f.pending, idx, idx+1)
+	}
+	return idx >= 0
+}
+
+func (f *fakeRegistrar) Accept(reg OCPPRegistration) error {
+	if reg.Title == "" {
+		return errors.New("missing title")
+	}
+	if !f.remove(reg.StationID) {
+		return ErrChargePointNotFound
+	}
+	f.registrations = append(f.registrations, reg)
+	if f.restart {
+		return fmt.Errorf("%w: reload not supported", ErrRestartRequired)
+	}
+	return nil
+}
+
+func (f *fakeRegistrar) Remove(id string) error {
+	idx := slices.IndexFunc(f.registrations, func(reg OCPPRegistration) bool {
+		return reg.StationID == id
+	})
+	if idx < 0 {
+		return ErrChargePointNotFound
+	}
+	f.registrations = slices.Delete(f.registrations, idx, idx+1)
+	return nil
+}
+
+func (f *fakeRegistrar) Reject(id string) error {
+	if !f.remove(id) {
+		return ErrChargePointNotFound
+	}
+	return nil
+}
+
+func TestOCPPHandlers(t *testing.T) {
+	or := &fakeRegistrar{
+		pending: []ocpp.PendingChargePoint{{ID: "foo", Vendor: "Acme"}, {ID: "bar"}, {ID: "baz"}},
+	}
+	srv := NewHTTPd(":0", nil)
+	srv.RegisterOCPPHandlers(or)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := do(http.MethodGet, "/api/ocpp/pending", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var pending struct {
+		Result []ocpp.PendingChargePoint
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&pending))
+	assert.Equal(t, or.pending, pending.Result)
+
+	w = do(http.MethodGet, "/api/ocpp/registrations", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"result":[]}`, w.Body.String())
+
+	w = do(http.MethodPost, "/api/ocpp/pending/foo", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do(http.MethodPost, "/api/ocpp/pending/foo", `{"title":"Garage","template":"default"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, []OCPPRegistration{{StationID: "foo", Title: "Garage", Template: "default"}}, or.registrations)
+
+	w = do(http.MethodPost, "/api/ocpp/pending/foo", `{"title":"Garage"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// registered but not applied
+	or.restart = true
+	w = do(http.MethodPost, "/api/ocpp/pending/bar", `{"title":"Carport"}`)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Len(t, or.registrations, 2)
+
+	w = do(http.MethodDelete, "/api/ocpp/pending/baz", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, or.pending)
+
+	w = do(http.MethodDelete, "/api/ocpp/pending/baz", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = do(http.MethodDelete, "/api/ocpp/registrations/bar", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Len(t, or.registrations, 1)
+
+	w = do(http.MethodDelete, "/api/ocpp/registrations/bar", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = do(http.MethodGet, "/api/ocpp/templates", "")
+	assert.JSONEq(t, `{"result":["default"]}`, w.Body.String())
+}
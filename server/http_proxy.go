@@ -0,0 +1,121 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/handlers"
+)
+
+// forwardedHeaders are the proxy headers only honored from trusted proxies
+var forwardedHeaders = []string{
+	"Forwarded",
+	"X-Forwarded-For",
+	"X-Forwarded-Host",
+	"X-Forwarded-Prefix",
+	"X-Forwarded-Proto",
+	"X-Forwarded-Scheme",
+	"X-Real-Ip",
+}
+
+// ParseTrustedProxies parses the networks of reverse proxies allowed to set X-Forwarded-* headers.
+// Single addresses are treated as host networks.
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	var res []*net.IPNet
+
+	for _, p := range proxies {
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy: %s", p)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+
+			res = append(res, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %w", err)
+		}
+
+		res = append(res, network)
+	}
+
+	return res, nil
+}
+
+// trustedProxy checks if the request's remote address belongs to a trusted proxy
+func trustedProxy(remoteAddr string, trusted []*net.IPNet) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// proxyHandler is a middleware for serving behind reverse proxies.
+// It honors X-Forwarded-* headers from trusted proxies only and serves requests below the base path,
+// independent of the proxy stripping the path prefix or not.
+func proxyHandler(h http.Handler, basePath string, trusted []*net.IPNet) http.Handler {
+	prefix := strings.TrimSuffix(basePath, "/")
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if prefix != "" {
+			switch {
+			case r.URL.Path == prefix:
+				// relative ui assets require trailing slash
+				http.Redirect(w, r, prefix+"/", http.StatusMovedPermanently)
+				return
+
+			case strings.HasPrefix(r.URL.Path, prefix+"/"):
+				r2 := new(http.Request)
+				*r2 = *r
+				r2.URL = new(url.URL)
+				*r2.URL = *r.URL
+				r2.URL.Path = strings.TrimPrefix(r.URL.Path, prefix)
+				r2.URL.RawPath = ""
+				r = r2
+			}
+		}
+
+		h.ServeHTTP(w, r)
+	})
+
+	forwarded := handlers.ProxyHeaders(handler)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if trustedProxy(r.RemoteAddr, trusted) {
+			forwarded.ServeHTTP(w, r)
+			return
+		}
+
+		// drop headers spoofed by untrusted clients
+		r = r.Clone(r.Context())
+		for _, header := range forwardedHeaders {
+			r.Header.Del(header)
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyHandler(t *testing.T) {
+	var path string
+	h := proxyHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+	}), "/evcc/", nil)
+
+	tc := []struct {
+		in, out string
+		status  int
+	}{
+		{"/evcc/api/state", "/api/state", http.StatusOK},
+		{"/api/state", "/api/state", http.StatusOK}, // prefix stripped by proxy
+		{"/evcc", "", http.StatusMovedPermanently},
+	}
+
+	for _, tc := range tc {
+		path = ""
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.in, nil))
+
+		assert.Equal(t, tc.status, rec.Code)
+		assert.Equal(t, tc.out, path)
+	}
+}
+
+func TestProxyHandlerTrusted(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "::1"})
+	require.NoError(t, err)
+
+	var uri string
+	h := proxyHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uri = util.ForwardedURI(r, "http://evcc.local")
+	}), "", trusted)
+
+	tc := []struct {
+		remote, uri string
+	}{
+		{"10.1.2.3:1234", "https://evcc.example.com"},
+		{"[::1]:1234", "https://evcc.example.com"},
+		{"192.168.1.1:1234", "http://evcc.local"},
+	}
+
+	for _, tc := range tc {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tc.remote
+		req.Header.Set("X-Forwarded-Host", "evcc.example.com")
+		req.Header.Set("X-Forwarded-Proto", "https")
+
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, tc.uri, uri, tc.remote)
+	}
+
+	_, err = ParseTrustedProxies([]string{"foo"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// RelayPairer creates tokens for pairing remote peers with the relay
+type RelayPairer interface {
+	PairingToken() (string, time.Time, error)
+}
+
+// RegisterRelayHandlers exposes the relay pairing. If tenancy is configured, it requires admin access.
+func (s *HTTPd) RegisterRelayHandlers(rp RelayPairer) {
+	router := s.Server.Handler.(*mux.Router)
+	router.Methods(http.MethodPost).Path("/api/relay/pairing").Handler(s.AdminHandler(relayPairingHandler(rp)))
+}
+
+// relayPairingHandler creates a single-use pairing token, invalidating the previous token
+func relayPairingHandler(rp RelayPairer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, expires, err := rp.PairingToken()
+		if err != nil {
+			jsonError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		jsonResult(w, struct {
+			Token   string    `json:"token"`
+			Expires time.Time `json:"expires"`
+		}{token, expires})
+	}
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePairer struct{}
+
+func (fakePairer) PairingToken() (string, time.Time, error) {
+	return "token", time.Unix(0, 0).UTC(), nil
+}
+
+func TestRelayHandlers(t *testing.T) {
+	tenancy := &Tenancy{admin: "admin", tenants: []*tenant{{TenantConfig: TenantConfig{Name: "flat1", Token: "secret1"}}}}
+
+	s := &HTTPd{Server: &http.Server{Handler: mux.NewRouter()}, hub: new(SocketHub)}
+	s.SetTenancy(tenancy)
+	s.RegisterRelayHandlers(fakePairer{})
+
+	tc := []struct {
+		token  string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"secret1", http.StatusForbidden},
+		{"admin", http.StatusOK},
+	}
+
+	for _, tc := range tc {
+		req := httptest.NewRequest(http.MethodPost, "/api/relay/pairing", nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+
+		rr := httptest.NewRecorder()
+		s.Handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, tc.status, rr.Code, tc)
+		if tc.status == http.StatusOK {
+			assert.JSONEq(t, `{"result":{"token":"token","expires":"1970-01-01T00:00:00Z"}}`, rr.Body.String())
+		}
+	}
+}
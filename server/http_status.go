@@ -0,0 +1,153 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/locale"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// plainStatus formats the cached state as short, human-readable lines re-using the ui translations
+type plainStatus struct {
+	localizer *i18n.Localizer
+	lines     []string
+}
+
+func (s *plainStatus) localize(id string) string {
+	msg, err := s.localizer.Localize(&locale.Config{MessageID: id})
+	if err != nil {
+		msg = id[strings.LastIndex(id, ".")+1:]
+	}
+	return msg
+}
+
+func (s *plainStatus) add(values ...string) {
+	var res []string
+	for _, v := range values {
+		if v != "" {
+			res = append(res, v)
+		}
+	}
+	s.lines = append(s.lines, strings.Join(res, ", "))
+}
+
+func (s *plainStatus) value(id, val string) string {
+	return fmt.Sprintf("%s: %s", s.localize(id), val)
+}
+
+func plainDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%d:%02d h", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// site adds the energy flow summary
+func (s *plainStatus) site(state map[string]interface{}) {
+	if title, ok := state["siteTitle"].(string); ok && title != "" {
+		s.add(title)
+	}
+
+	if pv, ok := state["pvPower"].(float64); ok {
+		s.add(s.value("main.energyflow.pvProduction", widgetPower(pv)))
+	}
+
+	if home, ok := state["homePower"].(float64); ok {
+		s.add(s.value("main.energyflow.homePower", widgetPower(home)))
+	}
+
+	if grid, ok := state["gridPower"].(float64); ok {
+		if grid >= 0 {
+			s.add(s.value("main.energyflow.gridImport", widgetPower(grid)))
+		} else {
+			s.add(s.value("main.energyflow.pvExport", widgetPower(-grid)))
+		}
+	}
+
+	if soc, ok := state["batterySoC"].(float64); ok {
+		var power string
+		if p, ok := state["batteryPower"].(float64); ok && p < 0 {
+			power = s.value("main.energyflow.batteryCharge", widgetPower(-p))
+		} else if ok && p > 0 {
+			power = s.value("main.energyflow.batteryDischarge", widgetPower(p))
+		}
+
+		s.add(s.value("main.energyflow.battery", fmt.Sprintf("%.0f%%", soc)), power)
+	}
+}
+
+// loadpoints adds a line per loadpoint
+func (s *plainStatus) loadpoints(state map[string]interface{}) {
+	lps, _ := state["loadpoints"].([]map[string]interface{})
+
+	for _, lp := range lps {
+		// hidden from tenant
+		if lp == nil {
+			continue
+		}
+
+		title, _ := lp["title"].(string)
+		if title == "" {
+			title = s.localize("main.loadpoint.fallbackName")
+		}
+
+		connected, _ := lp["connected"].(bool)
+		charging, _ := lp["charging"].(bool)
+
+		status := "disconnected"
+		if connected {
+			status = "connected"
+		}
+		if charging {
+			status = "charging"
+		}
+
+		values := []string{
+			fmt.Sprintf("%s: %s", title, s.localize("main.vehicleSoC."+status)),
+			s.localize(fmt.Sprintf("main.mode.%v", lp["mode"])),
+		}
+
+		if power, ok := lp["chargePower"].(float64); ok && charging {
+			values = append(values, s.value("main.loadpoint.power", widgetPower(power)))
+		}
+
+		if soc, ok := lp["vehicleSoC"].(float64); ok && connected {
+			values = append(values, s.value("main.vehicle.vehicleSoC", fmt.Sprintf("%.0f%%", soc)))
+		}
+
+		if energy, ok := lp["chargedEnergy"].(float64); ok && connected && energy > 0 {
+			values = append(values, s.value("main.loadpoint.charged", fmt.Sprintf("%.1f kWh", energy/1e3)))
+		}
+
+		if remaining, ok := lp["chargeRemainingDuration"].(time.Duration); ok && charging && remaining > 0 {
+			values = append(values, s.value("main.loadpoint.remaining", plainDuration(remaining)))
+		}
+
+		s.add(values...)
+	}
+}
+
+// plainStatusHandler returns a localized plain text summary of site and loadpoints, e.g. for screen readers, login banners or sms gateways.
+// The language is taken from the lang query parameter or the Accept-Language header.
+func plainStatusHandler(cache *util.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lang := r.URL.Query().Get("lang")
+		if lang == "" {
+			lang = r.Header.Get("Accept-Language")
+		}
+
+		state := cache.State()
+		if tn := requestTenant(r); tn != nil {
+			state = tn.state(state)
+		}
+
+		s := plainStatus{localizer: locale.NewLocalizer(lang)}
+		s.site(state)
+		s.loadpoints(state)
+
+		w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+		_, _ = w.Write([]byte(strings.Join(s.lines, "\n") + "\n"))
+	}
+}
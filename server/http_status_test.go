@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/locale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlainStatusHandler(t *testing.T) {
+	require.NoError(t, locale.Init())
+
+	cache := util.NewCache()
+	for key, val := range map[string]interface{}{"siteTitle": "Home", "pvPower": 4200.0, "homePower": 800.0, "gridPower": -2000.0, "batterySoC": 57.0, "batteryPower": -1000.0} {
+		cache.Add(key, util.Param{Key: key, Val: val})
+	}
+
+	id := 0
+	for key, val := range map[string]interface{}{"title": "Garage", "mode": "pv", "connected": true, "charging": true, "chargePower": 3700.0, "vehicleSoC": 45.0, "chargeRemainingDuration": 80 * time.Minute} {
+		cache.Add("lp-1/"+key, util.Param{LoadPoint: &id, Key: key, Val: val})
+	}
+
+	h := plainStatusHandler(cache)
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, "/status/plain?lang=en", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain"))
+
+	assert.Equal(t, strings.Join([]string{
+		"Home",
+		"Production: 4.2 kW",
+		"Consumption: 0.8 kW",
+		"Grid export: 2.0 kW",
+		"Battery: 57%, Battery charge: 1.0 kW",
+		"Garage: charging, PV, Power: 3.7 kW, SoC: 45%, Remaining: 1:20 h",
+	}, "\n")+"\n", w.Body.String())
+
+	// localized by header
+	r := httptest.NewRequest(http.MethodGet, "/status/plain", nil)
+	r.Header.Set("Accept-Language", "de-DE,de;q=0.9")
+
+	w = httptest.NewRecorder()
+	h(w, r)
+	assert.Contains(t, w.Body.String(), "Garage: lädt")
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/core/site"
+	dbserver "github.com/evcc-io/evcc/server/db"
+)
+
+// timelineHandler returns the loadpoints' decision timeline
+func timelineHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if dbserver.Instance == nil {
+			jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+			return
+		}
+
+		query := r.URL.Query()
+
+		q := db.EventQuery{
+			Loadpoint: query.Get("loadpoint"),
+			Type:      query.Get("type"),
+		}
+
+		if tn := requestTenant(r); tn != nil {
+			q.Loadpoints = tn.Loadpoints
+		}
+
+		for key, ts := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+			if val := query.Get(key); val != "" {
+				t, err := parseDate(val, site.Location())
+				if err != nil {
+					jsonError(w, http.StatusBadRequest, fmt.Errorf("invalid %s: %s", key, val))
+					return
+				}
+				*ts = t
+			}
+		}
+
+		if val := query.Get("limit"); val != "" {
+			limit, err := strconv.Atoi(val)
+			if err != nil {
+				jsonError(w, http.StatusBadRequest, fmt.Errorf("invalid limit: %s", val))
+				return
+			}
+			q.Limit = limit
+		}
+
+		res, err := q.Find(dbserver.Instance)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
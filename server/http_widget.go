@@ -0,0 +1,168 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+	"golang.org/x/exp/slices"
+)
+
+// widgetFields are the available widget fields
+var widgetFields = []string{"price", "prices", "solar", "loadpoints"}
+
+// widgetMaxHours limits the widget's forecast horizon
+const widgetMaxHours = 24
+
+type widgetSlot struct {
+	Time  string `json:"time"`
+	Value string `json:"value"`
+}
+
+type widgetLoadpoint struct {
+	Title  string `json:"title"`
+	Mode   string `json:"mode"`
+	Status string `json:"status"`
+	Power  string `json:"power"`
+	SoC    string `json:"soc,omitempty"`
+}
+
+func widgetPower(w float64) string {
+	return fmt.Sprintf("%.1f kW", w/1e3)
+}
+
+func widgetTime(ts time.Time, loc *time.Location) string {
+	return ts.In(loc).Format("15:04")
+}
+
+// widgetLoadpoints formats the cached loadpoint states
+func widgetLoadpoints(state map[string]interface{}) []widgetLoadpoint {
+	lps, _ := state["loadpoints"].([]map[string]interface{})
+
+	res := make([]widgetLoadpoint, 0, len(lps))
+	for _, lp := range lps {
+		// hidden from tenant
+		if lp == nil {
+			continue
+		}
+
+		status := "disconnected"
+		if connected, _ := lp["connected"].(bool); connected {
+			status = "connected"
+		}
+		if charging, _ := lp["charging"].(bool); charging {
+			status = "charging"
+		}
+
+		power, _ := lp["chargePower"].(float64)
+
+		wlp := widgetLoadpoint{
+			Title:  fmt.Sprint(lp["title"]),
+			Mode:   fmt.Sprint(lp["mode"]),
+			Status: status,
+			Power:  widgetPower(power),
+		}
+
+		if soc, ok := lp["vehicleSoC"].(float64); ok && status != "disconnected" {
+			wlp.SoC = fmt.Sprintf("%.0f%%", soc)
+		}
+
+		res = append(res, wlp)
+	}
+
+	return res
+}
+
+// widgetHandler returns compact, pre-formatted prices, solar forecast and loadpoint status for low-power displays.
+// Unchanged responses are answered with 304 Not Modified using ETag.
+func widgetHandler(site site.API, cache *util.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		fields := widgetFields
+		if val := query.Get("fields"); val != "" {
+			fields = strings.Split(val, ",")
+			for _, f := range fields {
+				if !slices.Contains(widgetFields, f) {
+					jsonError(w, http.StatusBadRequest, fmt.Errorf("invalid field: %s", f))
+					return
+				}
+			}
+		}
+
+		hours := 6
+		if val := query.Get("hours"); val != "" {
+			var err error
+			if hours, err = strconv.Atoi(val); err != nil || hours < 1 || hours > widgetMaxHours {
+				jsonError(w, http.StatusBadRequest, fmt.Errorf("invalid hours: %s", val))
+				return
+			}
+		}
+		horizon := time.Duration(hours) * time.Hour
+
+		state := cache.State()
+		if tn := requestTenant(r); tn != nil {
+			state = tn.state(state)
+		}
+
+		res := make(map[string]interface{})
+
+		for _, f := range fields {
+			switch f {
+			case "price", "prices":
+				rates := site.GetGridRates(horizon)
+
+				if f == "price" {
+					if len(rates) > 0 {
+						res[f] = fmt.Sprintf("%.3f %v", rates[0].Price, state["currency"])
+					}
+					continue
+				}
+
+				slots := make([]widgetSlot, 0, len(rates))
+				for _, rate := range rates {
+					slots = append(slots, widgetSlot{Time: widgetTime(rate.Start, site.Location()), Value: fmt.Sprintf("%.3f", rate.Price)})
+				}
+				res[f] = slots
+
+			case "solar":
+				forecast := site.GetSolarForecast(horizon)
+
+				slots := make([]widgetSlot, 0, len(forecast))
+				for _, slot := range forecast {
+					slots = append(slots, widgetSlot{Time: widgetTime(slot.Start, site.Location()), Value: widgetPower(slot.Power)})
+				}
+				res[f] = slots
+
+			case "loadpoints":
+				res[f] = widgetLoadpoints(state)
+			}
+		}
+
+		b, err := json.Marshal(map[string]interface{}{"result": res})
+		if err != nil {
+			jsonError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		h := fnv.New64a()
+		_, _ = h.Write(b)
+		etag := fmt.Sprintf(`"%x"`, h.Sum64())
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "max-age=60")
+
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		_, _ = w.Write(b)
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type widgetSite struct {
+	site.API
+}
+
+func (s *widgetSite) GetGridRates(time.Duration) []api.Rate {
+	start := time.Date(2022, 10, 1, 12, 0, 0, 0, time.Local)
+	return []api.Rate{
+		{Start: start, End: start.Add(time.Hour), Price: 0.3},
+		{Start: start.Add(time.Hour), End: start.Add(2 * time.Hour), Price: 0.25},
+	}
+}
+
+func (s *widgetSite) GetSolarForecast(time.Duration) []api.ForecastSlot {
+	return nil
+}
+
+func (s *widgetSite) Location() *time.Location {
+	return time.Local
+}
+
+func TestWidgetHandler(t *testing.T) {
+	cache := util.NewCache()
+	cache.Add("currency", util.Param{Key: "currency", Val: "EUR"})
+
+	id := 0
+	for key, val := range map[string]interface{}{"title": "Garage", "mode": "pv", "connected": true, "charging": true, "chargePower": 7400.0, "vehicleSoC": 62.0} {
+		cache.Add("lp-1/"+key, util.Param{LoadPoint: &id, Key: key, Val: val})
+	}
+
+	h := widgetHandler(&widgetSite{}, cache)
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, "/widget?fields=price,prices,loadpoints", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var res struct {
+		Result struct {
+			Price      string
+			Prices     []widgetSlot
+			Loadpoints []widgetLoadpoint
+		}
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+
+	assert.Equal(t, "0.300 EUR", res.Result.Price)
+	assert.Equal(t, []widgetSlot{{"12:00", "0.300"}, {"13:00", "0.250"}}, res.Result.Prices)
+	assert.Equal(t, []widgetLoadpoint{{"Garage", "pv", "charging", "7.4 kW", "62%"}}, res.Result.Loadpoints)
+
+	// unchanged
+	req := httptest.NewRequest(http.MethodGet, "/widget?fields=price,prices,loadpoints", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+
+	w = httptest.NewRecorder()
+	h(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	// invalid field
+	w = httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, "/widget?fields=foo", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -1,14 +1,23 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	influxhttp "github.com/influxdata/influxdb-client-go/v2/api/http"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
 	influxlog "github.com/influxdata/influxdb-client-go/v2/log"
+	"github.com/samber/lo"
 )
 
 // InfluxConfig is the influx db configuration
@@ -20,15 +29,18 @@ type InfluxConfig struct {
 	User     string
 	Password string
 	Interval time.Duration
+	Queue    int // points buffered in the database during outages
 }
 
 // Influx is a influx publisher
 type Influx struct {
 	sync.Mutex
-	log      *util.Logger
-	client   influxdb2.Client
-	org      string
-	database string
+	log        *util.Logger
+	client     influxdb2.Client
+	org        string
+	database   string
+	loadPoints []loadpoint.API
+	outbox     *Outbox // optional queue during outages
 }
 
 // NewInfluxClient creates new publisher for influx
@@ -72,8 +84,90 @@ func (m *Influx) supportedType(p util.Param) bool {
 	}
 }
 
+// SetLoadPoints replaces the loadpoints used for tagging, e.g. after reloading the configuration
+func (m *Influx) SetLoadPoints(loadPoints []loadpoint.API) {
+	m.Lock()
+	defer m.Unlock()
+	m.loadPoints = loadPoints
+}
+
+// loadPointName returns the loadpoint's name or empty string for unknown loadpoints
+func (m *Influx) loadPointName(id int) string {
+	m.Lock()
+	defer m.Unlock()
+
+	if id < len(m.loadPoints) {
+		return m.loadPoints[id].Name()
+	}
+	return ""
+}
+
+// SetQueue enables buffering of points in the outbox while the database is unavailable
+func (m *Influx) SetQueue(outbox *Outbox) {
+	m.outbox = outbox
+}
+
+// point converts the parameter to an influx point, returns nil for unsupported values
+func (m *Influx) point(param util.Param, vehicles map[int]string) *write.Point {
+	// vehicle name
+	if param.LoadPoint != nil {
+		if name, ok := param.Val.(string); ok && param.Key == "vehicleTitle" {
+			vehicles[*param.LoadPoint] = name
+			return nil
+		}
+	}
+
+	if !m.supportedType(param) {
+		return nil
+	}
+
+	tags := map[string]string{}
+	if param.LoadPoint != nil {
+		tags["loadpoint"] = m.loadPointName(*param.LoadPoint)
+		tags["vehicle"] = vehicles[*param.LoadPoint]
+	}
+
+	fields := map[string]interface{}{}
+
+	// array to slice
+	val := param.Val
+	if v, ok := val.([3]float64); ok {
+		val = v[:]
+	}
+
+	// add slice as phase values
+	if phases, ok := val.([]float64); ok {
+		var total float64
+		for i, v := range phases {
+			total += v
+			fields[fmt.Sprintf("l%d", i+1)] = v
+		}
+
+		// add total as "value"
+		val = total
+	}
+
+	fields["value"] = val
+
+	m.log.TRACE.Printf("write %s=%v (%v)", param.Key, param.Val, tags)
+	return influxdb2.NewPoint(param.Key, tags, fields, time.Now())
+}
+
 // Run Influx publisher
 func (m *Influx) Run(loadPoints []loadpoint.API, in <-chan util.Param) {
+	m.SetLoadPoints(loadPoints)
+
+	if m.outbox != nil {
+		m.runQueued(in)
+	} else {
+		m.runAsync(in)
+	}
+
+	m.client.Close()
+}
+
+// runAsync adds points to batch for async writing
+func (m *Influx) runAsync(in <-chan util.Param) {
 	writer := m.client.WriteAPI(m.org, m.database)
 
 	// log errors
@@ -87,53 +181,110 @@ func (m *Influx) Run(loadPoints []loadpoint.API, in <-chan util.Param) {
 	// track active vehicle per loadpoint
 	vehicles := make(map[int]string)
 
-	// add points to batch for async writing
 	for param := range in {
-		// vehicle name
-		if param.LoadPoint != nil {
-			if name, ok := param.Val.(string); ok && param.Key == "vehicleTitle" {
-				vehicles[*param.LoadPoint] = name
-				continue
-			}
+		if p := m.point(param, vehicles); p != nil {
+			writer.WritePoint(p)
 		}
+	}
+}
 
-		if !m.supportedType(param) {
-			continue
-		}
+// runQueued collects points and writes them in batches, points are queued while the database is unavailable
+func (m *Influx) runQueued(in <-chan util.Param) {
+	writer := m.client.WriteAPIBlocking(m.org, m.database)
+
+	// write in background since the input must not block
+	batchC := make(chan []string, 1)
+	doneC := make(chan struct{})
 
-		tags := map[string]string{}
-		if param.LoadPoint != nil {
-			tags["loadpoint"] = loadPoints[*param.LoadPoint].Name()
-			tags["vehicle"] = vehicles[*param.LoadPoint]
+	go func() {
+		for lines := range batchC {
+			m.write(writer, lines)
 		}
+		close(doneC)
+	}()
+
+	// track active vehicle per loadpoint
+	vehicles := make(map[int]string)
 
-		fields := map[string]interface{}{}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
 
-		// array to slice
-		val := param.Val
-		if v, ok := val.([3]float64); ok {
-			val = v[:]
-		}
+	var lines []string
+
+	for {
+		select {
+		case param, ok := <-in:
+			if !ok {
+				batchC <- lines
+				close(batchC)
+				<-doneC
+				return
+			}
+
+			if p := m.point(param, vehicles); p != nil {
+				lines = append(lines, strings.TrimSuffix(write.PointToLineProtocol(p, time.Second), "\n"))
+			}
+
+		case <-ticker.C:
+			if len(lines) == 0 && m.outbox.Len() == 0 {
+				continue
+			}
 
-		// add slice as phase values
-		if phases, ok := val.([]float64); ok {
-			var total float64
-			for i, v := range phases {
-				total += v
-				fields[fmt.Sprintf("l%d", i+1)] = v
+			// continue collecting while previous write is pending
+			select {
+			case batchC <- lines:
+				lines = nil
+			default:
 			}
+		}
+	}
+}
+
+// retryable checks if the write error is caused by unavailability instead of invalid data
+func (m *Influx) retryable(err error) bool {
+	var herr *influxhttp.Error
+	if errors.As(err, &herr) && herr.StatusCode >= http.StatusBadRequest && herr.StatusCode < http.StatusInternalServerError {
+		return herr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// write replays the queued points before writing the batch. If writing fails the batch is queued.
+func (m *Influx) write(writer api.WriteAPIBlocking, lines []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), request.Timeout)
+	defer cancel()
 
-			// add total as "value"
-			val = total
+	err := m.outbox.Replay(func(msgs []OutboxMessage) error {
+		err := writer.WriteRecord(ctx, lo.Map(msgs, func(msg OutboxMessage, _ int) string {
+			return msg.Payload
+		})...)
+
+		// drop invalid points
+		if err != nil && !m.retryable(err) {
+			go m.log.ERROR.Printf("dropping %d queued points: %v", len(msgs), err)
+			return nil
 		}
 
-		fields["value"] = val
+		return err
+	})
 
-		// write asynchronously
-		m.log.TRACE.Printf("write %s=%v (%v)", param.Key, param.Val, tags)
-		p := influxdb2.NewPoint(param.Key, tags, fields, time.Now())
-		writer.WritePoint(p)
+	if err == nil {
+		if err = writer.WriteRecord(ctx, lines...); err != nil && !m.retryable(err) {
+			go m.log.ERROR.Println(err)
+			return
+		}
 	}
 
-	m.client.Close()
+	if err != nil {
+		// log async as we're part of the logging loop
+		go m.log.WARN.Printf("queueing %d points: %v", len(lines), err)
+
+		msgs := lo.Map(lines, func(line string, _ int) OutboxMessage {
+			return OutboxMessage{Payload: line}
+		})
+
+		if err := m.outbox.Push(msgs...); err != nil {
+			go m.log.ERROR.Printf("queue: %v", err)
+		}
+	}
 }
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// ListenSystemd is the listen address for systemd socket activation
+const ListenSystemd = "systemd"
+
+// ListenConfig is a listen address with its authentication policy
+type ListenConfig struct {
+	Address string // host:port including IPv6 addresses like [::1]:7070, or systemd for socket activation
+	Auth    bool   // require basic authentication
+	TLS     bool   // serve https using the acme certificate
+}
+
+// Credentials are the basic authentication credentials
+type Credentials struct {
+	User     string
+	Password string
+}
+
+// listener is a network listener with its authentication policy
+type listener struct {
+	net.Listener
+	auth, tls bool
+}
+
+// systemdListeners returns the sockets passed by systemd socket activation
+func systemdListeners() ([]net.Listener, error) {
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err != nil || pid != os.Getpid() {
+		return nil, errors.New("systemd: no sockets passed")
+	}
+
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds == 0 {
+		return nil, errors.New("systemd: no sockets passed")
+	}
+
+	// first passed file descriptor
+	const listenFdsStart = 3
+
+	var res []net.Listener
+	for fd := listenFdsStart; fd < listenFdsStart+fds; fd++ {
+		l, err := net.FileListener(os.NewFile(uintptr(fd), "systemd-"+strconv.Itoa(fd)))
+		if err != nil {
+			return nil, fmt.Errorf("systemd: %w", err)
+		}
+		res = append(res, l)
+	}
+
+	return res, nil
+}
+
+// listen opens the configured listeners, tls listeners require tlsConf
+func listen(conf []ListenConfig, cred Credentials, tlsConf *tls.Config) (res []listener, err error) {
+	// close opened listeners on error
+	defer func() {
+		if err != nil {
+			for _, l := range res {
+				l.Close()
+			}
+		}
+	}()
+
+	for _, lc := range conf {
+		if lc.Auth && cred.Password == "" {
+			return res, fmt.Errorf("%s: authentication requires password", lc.Address)
+		}
+
+		if lc.TLS && tlsConf == nil {
+			return res, fmt.Errorf("%s: tls requires dyndns acme certificate", lc.Address)
+		}
+
+		// wrap tls listeners
+		wrap := func(l net.Listener) listener {
+			if lc.TLS {
+				l = tls.NewListener(l, tlsConf)
+			}
+			return listener{Listener: l, auth: lc.Auth, tls: lc.TLS}
+		}
+
+		if lc.Address == ListenSystemd {
+			ls, err := systemdListeners()
+			if err != nil {
+				return res, err
+			}
+
+			for _, l := range ls {
+				res = append(res, wrap(l))
+			}
+
+			continue
+		}
+
+		l, err := net.Listen("tcp", lc.Address)
+		if err != nil {
+			return res, err
+		}
+
+		res = append(res, wrap(l))
+	}
+
+	return res, nil
+}
+
+// AuthenticatedHandler returns the handler requiring basic authentication if credentials are configured,
+// independent of the listener, e.g. for remote access
+func (s *HTTPd) AuthenticatedHandler(cred Credentials) http.Handler {
+	if cred.User == "" {
+		return s.Handler
+	}
+	return basicAuthHandler(s.Handler, cred)
+}
+
+// basicAuthHandler is a middleware requiring basic authentication
+func basicAuthHandler(h http.Handler, cred Credentials) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || subtle.ConstantTimeCompare([]byte(user), []byte(cred.User)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(password), []byte(cred.Password)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="evcc"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// ListenAndServeAll serves below base path on all configured listeners and returns on the first error.
+// Forwarded headers are honored from trusted proxies only.
+func (s *HTTPd) ListenAndServeAll(conf []ListenConfig, cred Credentials, basePath string, trustedProxies []string) error {
+	trusted, err := ParseTrustedProxies(trustedProxies)
+	if err != nil {
+		return err
+	}
+
+	var tlsConf *tls.Config
+	if s.cert != nil {
+		tlsConf = &tls.Config{
+			GetCertificate: s.cert,
+			MinVersion:     tls.VersionTLS12,
+		}
+	}
+
+	listeners, err := listen(conf, cred, tlsConf)
+	if err != nil {
+		return err
+	}
+
+	errC := make(chan error, len(listeners))
+	handler := proxyHandler(s.Handler, basePath, trusted)
+
+	for _, l := range listeners {
+		log.INFO.Printf("listening at %s (auth: %v, tls: %v)", l.Addr(), l.auth, l.tls)
+
+		srv := &http.Server{
+			Handler:      handler,
+			ReadTimeout:  s.ReadTimeout,
+			WriteTimeout: s.WriteTimeout,
+			IdleTimeout:  s.IdleTimeout,
+			ErrorLog:     s.ErrorLog,
+		}
+
+		if l.auth {
+			srv.Handler = basicAuthHandler(handler, cred)
+		}
+
+		go func(l net.Listener) {
+			errC <- srv.Serve(l)
+		}(l.Listener)
+	}
+
+	return <-errC
+}
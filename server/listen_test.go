@@ -0,0 +1,56 @@
+package server
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicAuthHandler(t *testing.T) {
+	h := basicAuthHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), Credentials{
+		User:     "admin",
+		Password: "secret",
+	})
+
+	tc := []struct {
+		user, password string
+		status         int
+	}{
+		{"admin", "secret", http.StatusOK},
+		{"admin", "foo", http.StatusUnauthorized},
+		{"", "", http.StatusUnauthorized},
+	}
+
+	for _, tc := range tc {
+		req := httptest.NewRequest(http.MethodGet, "/api/state", nil)
+		if tc.user != "" {
+			req.SetBasicAuth(tc.user, tc.password)
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, tc.status, rec.Code)
+	}
+}
+
+func TestListenRequiresPassword(t *testing.T) {
+	_, err := listen([]ListenConfig{{Address: "127.0.0.1:0"}, {Address: "[::1]:0", Auth: true}}, Credentials{}, nil)
+	assert.Error(t, err)
+}
+
+func TestListenRequiresCertificate(t *testing.T) {
+	_, err := listen([]ListenConfig{{Address: "127.0.0.1:0", TLS: true}}, Credentials{}, nil)
+	assert.Error(t, err)
+
+	res, err := listen([]ListenConfig{{Address: "127.0.0.1:0", TLS: true}}, Credentials{}, new(tls.Config))
+	assert.NoError(t, err)
+
+	for _, l := range res {
+		assert.True(t, l.tls)
+		l.Close()
+	}
+}
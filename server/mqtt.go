@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -11,19 +12,108 @@ import (
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/provider/mqtt"
 	"github.com/evcc-io/evcc/util"
+	"golang.org/x/exp/slices"
 )
 
+// MQTTPermission allows the commands matching the topic patterns. Commands are accepted on the
+// default topic or, if client is set, on the client's topic <root>/clients/<client> only.
+// Since MQTT does not identify the publisher, broker ACLs must restrict each client's credentials to its topic.
+type MQTTPermission struct {
+	Client string   // optional client topic
+	Topics []string // command topics relative to root, e.g. loadpoints/+/mode, supports + and # wildcards
+}
+
 // MQTT is the MQTT server. It uses the MQTT client for publishing.
 type MQTT struct {
-	Handler *mqtt.Client
-	root    string
+	Handler     *mqtt.Client
+	log         *util.Logger
+	root        string
+	permissions []MQTTPermission
+	setters     []string // subscribed setter topics
+	outbox      *Outbox  // optional queue during outages
+
+	mu       sync.Mutex
+	pending  []OutboxMessage // messages collected for queueing in the outbox
+	flushing bool            // pending messages are being written to the outbox
 }
 
-// NewMQTT creates MQTT server
-func NewMQTT(root string) *MQTT {
+// NewMQTT creates MQTT server. Without permissions all commands are accepted on the default topic.
+func NewMQTT(root string, permissions []MQTTPermission) (*MQTT, error) {
+	for _, p := range permissions {
+		if strings.ContainsAny(p.Client, "/+#") {
+			return nil, fmt.Errorf("invalid client: %s", p.Client)
+		}
+
+		if len(p.Topics) == 0 {
+			return nil, fmt.Errorf("missing topics for client: %s", p.Client)
+		}
+	}
+
 	return &MQTT{
-		Handler: mqtt.Instance,
-		root:    root,
+		Handler:     mqtt.Instance,
+		log:         util.NewLogger("mqtt"),
+		root:        root,
+		permissions: permissions,
+	}, nil
+}
+
+// topicMatch checks if the topic matches the pattern using MQTT wildcards
+func topicMatch(pattern, topic string) bool {
+	pp := strings.Split(pattern, "/")
+	tt := strings.Split(topic, "/")
+
+	for i, p := range pp {
+		if p == "#" {
+			return true
+		}
+
+		if i >= len(tt) || (p != "+" && p != tt[i]) {
+			return false
+		}
+	}
+
+	return len(pp) == len(tt)
+}
+
+// commandRoots returns the topic roots the command is permitted on
+func (m *MQTT) commandRoots(cmd string) []string {
+	if len(m.permissions) == 0 {
+		return []string{m.root}
+	}
+
+	var res []string
+
+	for _, p := range m.permissions {
+		if slices.IndexFunc(p.Topics, func(pattern string) bool {
+			return topicMatch(strings.Trim(pattern, "/"), cmd)
+		}) < 0 {
+			continue
+		}
+
+		root := m.root
+		if p.Client != "" {
+			root = fmt.Sprintf("%s/clients/%s", m.root, p.Client)
+		}
+
+		if !slices.Contains(res, root) {
+			res = append(res, root)
+		}
+	}
+
+	return res
+}
+
+// listenSetter listens to the command's setter topics
+func (m *MQTT) listenSetter(cmd string, callback func(string)) {
+	roots := m.commandRoots(cmd)
+	if len(roots) == 0 {
+		m.log.DEBUG.Printf("command not permitted: %s", cmd)
+	}
+
+	for _, root := range roots {
+		topic := fmt.Sprintf("%s/%s/set", root, cmd)
+		m.Handler.ListenSetter(topic, callback)
+		m.setters = append(m.setters, topic)
 	}
 }
 
@@ -50,7 +140,77 @@ func (m *MQTT) encode(v interface{}) string {
 	}
 }
 
+// SetQueue enables buffering of messages in the outbox while the broker is unavailable
+func (m *MQTT) SetQueue(outbox *Outbox) {
+	m.outbox = outbox
+}
+
+// queued checks if messages are waiting in the outbox or for being written to it
+func (m *MQTT) queued() bool {
+	return len(m.pending) > 0 || m.flushing || m.outbox.Len() > 0
+}
+
+// flush writes the collected messages to the outbox in a single batch
+func (m *MQTT) flush() {
+	m.mu.Lock()
+	msgs := m.pending
+	m.pending = nil
+	m.flushing = len(msgs) > 0
+	m.mu.Unlock()
+
+	if len(msgs) == 0 {
+		return
+	}
+
+	if err := m.outbox.Push(msgs...); err != nil {
+		// log async as we're part of the logging loop
+		go m.log.ERROR.Printf("queue: %v", err)
+	}
+
+	m.mu.Lock()
+	m.flushing = false
+	m.mu.Unlock()
+}
+
+// replay queues the collected messages and publishes the queued messages once the broker is available
+func (m *MQTT) replay() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		m.flush()
+
+		if m.outbox.Len() == 0 || !m.Handler.Client.IsConnectionOpen() {
+			continue
+		}
+
+		if err := m.outbox.Replay(func(msgs []OutboxMessage) error {
+			for _, msg := range msgs {
+				if err := m.Handler.Publish(msg.Topic, msg.Retained, msg.Payload); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			// log async as we're part of the logging loop
+			go m.log.WARN.Printf("replay: %v", err)
+		}
+	}
+}
+
 func (m *MQTT) publishSingleValue(topic string, retained bool, payload interface{}) {
+	// queue while disconnected and until queued messages are replayed to keep order
+	if m.outbox != nil {
+		m.mu.Lock()
+		if m.queued() || !m.Handler.Client.IsConnectionOpen() {
+			// collect for writing to the outbox in batches
+			m.pending = append(m.pending, OutboxMessage{Topic: topic, Payload: m.encode(payload), Retained: retained})
+			m.mu.Unlock()
+			return
+		}
+		m.mu.Unlock()
+	}
+
 	token := m.Handler.Client.Publish(topic, m.Handler.Qos, retained, m.encode(payload))
 	go m.Handler.WaitForToken(token)
 }
@@ -86,35 +246,50 @@ func (m *MQTT) publish(topic string, retained bool, payload interface{}) {
 }
 
 func (m *MQTT) listenSetters(topic string, site site.API, lp loadpoint.API) {
-	m.Handler.ListenSetter(topic+"/mode/set", func(payload string) {
+	m.listenSetter(topic+"/mode", func(payload string) {
 		lp.SetMode(api.ChargeMode(payload))
 	})
-	m.Handler.ListenSetter(topic+"/minSoC/set", func(payload string) {
+	m.listenSetter(topic+"/minSoC", func(payload string) {
 		if soc, err := strconv.Atoi(payload); err == nil {
 			lp.SetMinSoC(soc)
 		}
 	})
-	m.Handler.ListenSetter(topic+"/targetSoC/set", func(payload string) {
+	m.listenSetter(topic+"/targetSoC", func(payload string) {
 		if soc, err := strconv.Atoi(payload); err == nil {
 			lp.SetTargetSoC(soc)
 		}
 	})
-	m.Handler.ListenSetter(topic+"/minCurrent/set", func(payload string) {
+	m.listenSetter(topic+"/targetRange", func(payload string) {
+		if km, err := strconv.Atoi(payload); err == nil {
+			_ = lp.SetTargetRange(km)
+		}
+	})
+	m.listenSetter(topic+"/targetEnergy", func(payload string) {
+		if energy, err := strconv.Atoi(payload); err == nil {
+			lp.SetTargetEnergy(energy)
+		}
+	})
+	m.listenSetter(topic+"/minCurrent", func(payload string) {
 		if current, err := strconv.ParseFloat(payload, 64); err == nil {
 			lp.SetMinCurrent(current)
 		}
 	})
-	m.Handler.ListenSetter(topic+"/maxCurrent/set", func(payload string) {
+	m.listenSetter(topic+"/maxCurrent", func(payload string) {
 		if current, err := strconv.ParseFloat(payload, 64); err == nil {
 			lp.SetMaxCurrent(current)
 		}
 	})
-	m.Handler.ListenSetter(topic+"/phases/set", func(payload string) {
+	m.listenSetter(topic+"/phases", func(payload string) {
 		if phases, err := strconv.Atoi(payload); err == nil {
 			_ = lp.SetPhases(phases)
 		}
 	})
-	m.Handler.ListenSetter(topic+"/vehicle/set", func(payload string) {
+	m.listenSetter(topic+"/plan", func(payload string) {
+		if payload != "" {
+			_, _ = site.ApplyPlan(payload, lp)
+		}
+	})
+	m.listenSetter(topic+"/vehicle", func(payload string) {
 		if vehicle, err := strconv.Atoi(payload); err == nil {
 			if vehicle >= 0 {
 				if vehicles := site.GetVehicles(); vehicle < len(vehicles) {
@@ -133,33 +308,10 @@ func (m *MQTT) Run(site site.API, in <-chan util.Param) {
 	topic := fmt.Sprintf("%s/status", m.root)
 	m.publish(topic, true, "online")
 
-	// site setters
-	m.Handler.ListenSetter(fmt.Sprintf("%s/site/prioritySoC/set", m.root), func(payload string) {
-		if soc, err := strconv.Atoi(payload); err == nil {
-			_ = site.SetPrioritySoC(float64(soc))
-		}
-	})
-
-	m.Handler.ListenSetter(fmt.Sprintf("%s/site/bufferSoC/set", m.root), func(payload string) {
-		if soc, err := strconv.Atoi(payload); err == nil {
-			_ = site.SetBufferSoC(float64(soc))
-		}
-	})
-
-	m.Handler.ListenSetter(fmt.Sprintf("%s/site/residualPower/set", m.root), func(payload string) {
-		if soc, err := strconv.Atoi(payload); err == nil {
-			_ = site.SetResidualPower(float64(soc))
-		}
-	})
-
-	// number of loadpoints
-	topic = fmt.Sprintf("%s/loadpoints", m.root)
-	m.publish(topic, true, len(site.LoadPoints()))
+	m.Listen(site)
 
-	// loadpoint setters
-	for id, lp := range site.LoadPoints() {
-		topic := fmt.Sprintf("%s/loadpoints/%d", m.root, id+1)
-		m.listenSetters(topic, site, lp)
+	if m.outbox != nil {
+		go m.replay()
 	}
 
 	// TODO remove deprecated topics
@@ -192,3 +344,81 @@ func (m *MQTT) Run(site site.API, in <-chan util.Param) {
 		m.publish(topic, true, p.Val)
 	}
 }
+
+// circuitSetter returns a setter changing one of the named circuit's limits
+func circuitSetter(s site.API, name, limit string) func(string) {
+	return func(payload string) {
+		val, err := strconv.ParseFloat(payload, 64)
+		if err != nil {
+			return
+		}
+
+		for _, c := range s.GetCircuits() {
+			if c.Name != name {
+				continue
+			}
+
+			if limit == "maxPower" {
+				c.MaxPower = val
+			} else {
+				c.MaxCurrent = val
+			}
+
+			_ = s.SetCircuitLimits(name, c.CircuitLimits)
+		}
+	}
+}
+
+// Listen subscribes the site and loadpoint setters. Calling it again replaces the previous site's setters.
+func (m *MQTT) Listen(site site.API) {
+	for _, topic := range m.setters {
+		m.Handler.Unlisten(topic)
+	}
+	m.setters = nil
+
+	// site setters
+	m.listenSetter("site/prioritySoC", func(payload string) {
+		if soc, err := strconv.Atoi(payload); err == nil {
+			_ = site.SetPrioritySoC(float64(soc))
+		}
+	})
+
+	m.listenSetter("site/bufferSoC", func(payload string) {
+		if soc, err := strconv.Atoi(payload); err == nil {
+			_ = site.SetBufferSoC(float64(soc))
+		}
+	})
+
+	m.listenSetter("site/batteryPriority", func(payload string) {
+		p := site.GetBatteryPriority()
+		p.Mode = payload
+		_ = site.SetBatteryPriority(p)
+	})
+
+	for _, c := range site.GetCircuits() {
+		for _, limit := range []string{"maxCurrent", "maxPower"} {
+			m.listenSetter(fmt.Sprintf("site/circuits/%s/%s", c.Name, limit), circuitSetter(site, c.Name, limit))
+		}
+	}
+
+	m.listenSetter("site/residualPower", func(payload string) {
+		if soc, err := strconv.Atoi(payload); err == nil {
+			_ = site.SetResidualPower(float64(soc))
+		}
+	})
+
+	m.listenSetter("site/plan", func(payload string) {
+		if payload != "" {
+			_, _ = site.ApplyPlan(payload, nil)
+		}
+	})
+
+	// number of loadpoints
+	topic := fmt.Sprintf("%s/loadpoints", m.root)
+	m.publish(topic, true, len(site.LoadPoints()))
+
+	// loadpoint setters
+	for id, lp := range site.LoadPoints() {
+		m.listenSetters(fmt.Sprintf("loadpoints/%d", id+1), site, lp)
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"path/filepath"
+	"testing"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/evcc-io/evcc/provider/mqtt"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMqttCommandRoots(t *testing.T) {
+	m, err := NewMQTT("evcc", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"evcc"}, m.commandRoots("loadpoints/1/mode"))
+
+	m, err = NewMQTT("evcc", []MQTTPermission{
+		{Topics: []string{"site/#"}},
+		{Client: "ha", Topics: []string{"loadpoints/+/mode", "/site/bufferSoC/"}},
+	})
+	require.NoError(t, err)
+
+	tc := []struct {
+		cmd   string
+		roots []string
+	}{
+		{"loadpoints/1/mode", []string{"evcc/clients/ha"}},
+		{"loadpoints/1/targetSoC", nil},
+		{"loadpoints/mode", nil},
+		{"site/bufferSoC", []string{"evcc", "evcc/clients/ha"}},
+		{"site/prioritySoC", []string{"evcc"}},
+	}
+
+	for _, tc := range tc {
+		assert.Equal(t, tc.roots, m.commandRoots(tc.cmd), tc.cmd)
+	}
+
+	_, err = NewMQTT("evcc", []MQTTPermission{{Client: "a/b", Topics: []string{"#"}}})
+	assert.Error(t, err)
+
+	_, err = NewMQTT("evcc", []MQTTPermission{{Client: "ha"}})
+	assert.Error(t, err)
+}
+
+func TestMqttQueueBatches(t *testing.T) {
+	instance := db.Instance
+	defer func() { db.Instance = instance }()
+
+	require.NoError(t, db.NewInstance("sqlite", filepath.Join(t.TempDir(), "evcc.db")))
+
+	outbox, err := NewOutbox("mqtt", 100)
+	require.NoError(t, err)
+
+	// disconnected client
+	m := &MQTT{
+		Handler: &mqtt.Client{Client: paho.NewClient(paho.NewClientOptions())},
+		log:     util.NewLogger("foo"),
+		outbox:  outbox,
+	}
+
+	for i := 0; i < 3; i++ {
+		m.publishSingleValue("evcc/foo", false, i)
+	}
+
+	// messages are collected until flushed
+	assert.Len(t, m.pending, 3)
+	assert.Equal(t, 0, outbox.Len())
+
+	m.flush()
+	assert.Empty(t, m.pending)
+	assert.Equal(t, 3, outbox.Len())
+
+	// queued messages keep order
+	var payloads []string
+	require.NoError(t, outbox.Replay(func(msgs []OutboxMessage) error {
+		for _, msg := range msgs {
+			payloads = append(payloads, msg.Payload)
+		}
+		return nil
+	}))
+	assert.Equal(t, []string{"0", "1", "2"}, payloads)
+}
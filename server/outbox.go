@@ -0,0 +1,149 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db"
+	"gorm.io/gorm"
+)
+
+// outboxBatch is the number of messages replayed at once
+const outboxBatch = 500
+
+// OutboxMessage is an outgoing message buffered in the database during an outage
+type OutboxMessage struct {
+	ID       uint      `gorm:"primarykey"`
+	Target   string    `gorm:"index"` // integration the message belongs to
+	Topic    string    // topic or measurement
+	Payload  string    // encoded message
+	Retained bool      // mqtt retained flag
+	Created  time.Time // time the message was queued
+}
+
+// Outbox is a persistent, size-bounded queue of outgoing messages of a single integration.
+// Queued messages are replayed in order once the integration is available again.
+type Outbox struct {
+	mu     sync.Mutex
+	db     *gorm.DB
+	target string
+	limit  int
+	size   int64 // atomic, modified with lock held
+}
+
+// NewOutbox creates the outbox of the target integration keeping at most limit messages, dropping the oldest
+func NewOutbox(target string, limit int) (*Outbox, error) {
+	if db.Instance == nil {
+		return nil, errors.New("queue requires database")
+	}
+
+	if err := db.Instance.AutoMigrate(new(OutboxMessage)); err != nil {
+		return nil, err
+	}
+
+	o := &Outbox{
+		db:     db.Instance,
+		target: target,
+		limit:  limit,
+	}
+
+	// messages left from previous run
+	err := o.messages().Count(&o.size).Error
+
+	return o, err
+}
+
+func (o *Outbox) messages() *gorm.DB {
+	return o.db.Model(new(OutboxMessage)).Where("target = ?", o.target)
+}
+
+// Len returns the number of queued messages without waiting for pending database operations
+func (o *Outbox) Len() int {
+	return int(atomic.LoadInt64(&o.size))
+}
+
+// Push queues messages, the oldest messages are dropped if the queue is full
+func (o *Outbox) Push(msgs ...OutboxMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := time.Now()
+	for i := range msgs {
+		msgs[i].ID = 0
+		msgs[i].Target = o.target
+		if msgs[i].Created.IsZero() {
+			msgs[i].Created = now
+		}
+	}
+
+	if err := o.db.CreateInBatches(msgs, outboxBatch).Error; err != nil {
+		return err
+	}
+	atomic.AddInt64(&o.size, int64(len(msgs)))
+
+	if excess := atomic.LoadInt64(&o.size) - int64(o.limit); excess > 0 {
+		oldest := o.messages().Select("id").Order("id").Limit(int(excess))
+		res := o.db.Where("id IN (?)", oldest).Delete(new(OutboxMessage))
+		if res.Error != nil {
+			return res.Error
+		}
+		atomic.AddInt64(&o.size, -res.RowsAffected)
+	}
+
+	return nil
+}
+
+// Replay sends the queued messages in order and removes them once sent.
+// Replay stops at the first error keeping the remaining messages. Messages may be pushed while replaying,
+// but only a single replay must be active at any time.
+func (o *Outbox) Replay(send func([]OutboxMessage) error) error {
+	for {
+		msgs, err := o.next()
+		if err != nil || len(msgs) == 0 {
+			return err
+		}
+
+		if err := send(msgs); err != nil {
+			return err
+		}
+
+		if err := o.remove(msgs[len(msgs)-1].ID); err != nil {
+			return err
+		}
+	}
+}
+
+// next returns the next batch of queued messages
+func (o *Outbox) next() ([]OutboxMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var msgs []OutboxMessage
+	if atomic.LoadInt64(&o.size) == 0 {
+		return nil, nil
+	}
+
+	err := o.messages().Order("id").Limit(outboxBatch).Find(&msgs).Error
+	if err == nil && len(msgs) == 0 {
+		atomic.StoreInt64(&o.size, 0)
+	}
+
+	return msgs, err
+}
+
+// remove deletes the sent messages up to id
+func (o *Outbox) remove(id uint) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	res := o.db.Where("target = ? AND id <= ?", o.target, id).Delete(new(OutboxMessage))
+	atomic.AddInt64(&o.size, -res.RowsAffected)
+
+	return res.Error
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox(t *testing.T) {
+	instance := db.Instance
+	defer func() { db.Instance = instance }()
+
+	db.Instance = nil
+	_, err := NewOutbox("foo", 3)
+	assert.Error(t, err)
+
+	require.NoError(t, db.NewInstance("sqlite", filepath.Join(t.TempDir(), "evcc.db")))
+
+	o, err := NewOutbox("foo", 3)
+	require.NoError(t, err)
+
+	// other targets are independent
+	other, err := NewOutbox("bar", 3)
+	require.NoError(t, err)
+	require.NoError(t, other.Push(OutboxMessage{Payload: "x"}))
+
+	// oldest messages are dropped
+	for _, payload := range []string{"1", "2", "3", "4"} {
+		require.NoError(t, o.Push(OutboxMessage{Topic: "t", Payload: payload}))
+	}
+	assert.Equal(t, 3, o.Len())
+
+	// failed replay keeps messages
+	assert.Error(t, o.Replay(func([]OutboxMessage) error { return errors.New("offline") }))
+	assert.Equal(t, 3, o.Len())
+
+	var payloads []string
+	require.NoError(t, o.Replay(func(msgs []OutboxMessage) error {
+		for _, msg := range msgs {
+			payloads = append(payloads, msg.Payload)
+		}
+		return nil
+	}))
+	assert.Equal(t, []string{"2", "3", "4"}, payloads)
+	assert.Equal(t, 0, o.Len())
+	assert.Equal(t, 1, other.Len())
+
+	// queued messages survive restart
+	other, err = NewOutbox("bar", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 1, other.Len())
+}
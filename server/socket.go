@@ -5,22 +5,35 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/evcc-io/evcc/util"
 	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+	"golang.org/x/exp/slices"
 )
 
 const (
 	// Time allowed to write a message to the peer
 	socketWriteTimeout = 10 * time.Second
+
+	// state version key added to each message, allows resuming with ?since=<version>
+	socketVersionKey = "stateVersion"
+
+	// websocket subprotocol for binary MessagePack encoding
+	socketMsgpack = "msgpack"
 )
 
+// socketIgnore are transient keys not part of the resumable state
+var socketIgnore = []string{"warn", "error"}
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
 	CheckOrigin:     func(r *http.Request) bool { return true },
+	Subprotocols:    []string{socketMsgpack},
 }
 
 // SocketClient is a middleman between the websocket connection and the hub.
@@ -32,6 +45,31 @@ type SocketClient struct {
 
 	// Buffered channel of outbound messages.
 	send chan []byte
+
+	// State version the client has already received
+	since uint64
+
+	// Client negotiated MessagePack encoding
+	binary bool
+
+	// Tenant the client is restricted to
+	tenant *tenant
+}
+
+// filter returns the params visible to the client's tenant
+func (c *SocketClient) filter(params []util.Param) []util.Param {
+	if c.tenant == nil {
+		return params
+	}
+
+	res := make([]util.Param, 0, len(params))
+	for _, p := range params {
+		if p, ok := c.tenant.param(p); ok {
+			res = append(res, p)
+		}
+	}
+
+	return res
 }
 
 // writePump pumps messages from the hub to the websocket connection.
@@ -41,11 +79,16 @@ func (c *SocketClient) writePump() {
 		c.hub.unregister <- c
 	}()
 
+	typ := websocket.TextMessage
+	if c.binary {
+		typ = websocket.BinaryMessage
+	}
+
 	for msg := range c.send {
 		if err := c.conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout)); err != nil {
 			return
 		}
-		if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+		if err := c.conn.WriteMessage(typ, msg); err != nil {
 			return
 		}
 	}
@@ -53,12 +96,28 @@ func (c *SocketClient) writePump() {
 
 // ServeWebsocket handles websocket requests from the peer.
 func ServeWebsocket(hub *SocketHub, w http.ResponseWriter, r *http.Request) {
+	var tn *tenant
+	if hub.tenancy != nil {
+		var err error
+		if tn, err = hub.tenancy.authenticate(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.ERROR.Println(err)
 		return
 	}
-	client := &SocketClient{hub: hub, conn: conn, send: make(chan []byte, 256)}
+	client := &SocketClient{hub: hub, conn: conn, send: make(chan []byte, 256), tenant: tn}
+	client.binary = conn.Subprotocol() == socketMsgpack
+
+	// resume from known state version
+	if since, err := strconv.ParseUint(r.URL.Query().Get("since"), 10, 64); err == nil {
+		client.since = since
+	}
+
 	client.hub.register <- client
 
 	// run writing to client in goroutine
@@ -76,6 +135,12 @@ type SocketHub struct {
 
 	// Unregister requests from clients.
 	unregister chan *SocketClient
+
+	// Versioned state for resuming clients
+	state *util.Cache
+
+	// Tenancy restricting clients to their loadpoints and vehicles
+	tenancy *Tenancy
 }
 
 // NewSocketHub creates a web socket hub that distributes meter status and
@@ -85,6 +150,7 @@ func NewSocketHub() *SocketHub {
 		register:   make(chan *SocketClient),
 		unregister: make(chan *SocketClient),
 		clients:    make(map[*SocketClient]bool),
+		state:      util.NewCache(),
 	}
 }
 
@@ -114,6 +180,40 @@ func encode(v interface{}) (string, error) {
 	return s, nil
 }
 
+// encodeBinary converts values to MessagePack compatible types matching the json encoding
+func encodeBinary(v interface{}) interface{} {
+	switch val := v.(type) {
+	case time.Time:
+		if val.IsZero() {
+			return ""
+		}
+		return val.Format(time.RFC3339Nano)
+	case time.Duration:
+		return int64(val.Seconds())
+	case float64:
+		if math.IsNaN(val) {
+			return nil
+		}
+		return val
+	case nil, bool, string, int, int64:
+		return val
+	default:
+		// use json representation for structured values
+		var res interface{}
+		if b, err := json.Marshal(v); err == nil {
+			_ = json.Unmarshal(b, &res)
+		}
+		return res
+	}
+}
+
+func key(p util.Param) string {
+	if p.LoadPoint != nil {
+		return fmt.Sprintf("loadpoints.%d.%s", *p.LoadPoint, p.Key)
+	}
+	return p.Key
+}
+
 func kv(p util.Param) string {
 	val, err := encode(p.Val)
 	if err != nil {
@@ -122,43 +222,82 @@ func kv(p util.Param) string {
 
 	var msg strings.Builder
 	msg.WriteString("\"")
-	if p.LoadPoint != nil {
-		msg.WriteString(fmt.Sprintf("loadpoints.%d.", *p.LoadPoint))
-	}
-	msg.WriteString(p.Key)
+	msg.WriteString(key(p))
 	msg.WriteString("\":")
 	msg.WriteString(val)
 
 	return msg.String()
 }
 
-func (h *SocketHub) welcome(client *SocketClient, params []util.Param) {
-	h.clients[client] = true
+// marshal encodes params as json object or MessagePack map
+func marshal(params []util.Param, binary bool) []byte {
+	if binary {
+		res := make(map[string]interface{}, len(params))
+		for _, p := range params {
+			res[key(p)] = encodeBinary(p.Val)
+		}
+
+		b, err := msgpack.Marshal(res)
+		if err != nil {
+			log.ERROR.Printf("socket: %v", err)
+		}
+
+		return b
+	}
 
 	var msg strings.Builder
 	msg.WriteString("{")
-	for _, p := range params {
-		if msg.Len() > 1 {
+	for i, p := range params {
+		if i > 0 {
 			msg.WriteString(",")
 		}
 		msg.WriteString(kv(p))
 	}
 	msg.WriteString("}")
 
+	return []byte(msg.String())
+}
+
+func (h *SocketHub) welcome(client *SocketClient) {
+	h.clients[client] = true
+
+	params, version := h.state.Since(client.since)
+	params = append(client.filter(params), util.Param{Key: socketVersionKey, Val: version})
+
 	select {
-	case client.send <- []byte(msg.String()):
+	case client.send <- marshal(params, client.binary):
 	default:
 		close(client.send)
 	}
 }
 
 func (h *SocketHub) broadcast(p util.Param) {
+	version := h.state.Version()
+	if !slices.Contains(socketIgnore, p.Key) {
+		version = h.state.Add(p.UniqueID(), p)
+	}
+
 	if len(h.clients) > 0 {
-		msg := "{" + kv(p) + "}"
+		params := []util.Param{p, {Key: socketVersionKey, Val: version}}
+
+		// encode once per negotiated encoding
+		msgs := make(map[bool][]byte)
 
 		for client := range h.clients {
+			msg, ok := msgs[client.binary]
+
+			// tenant clients receive individually filtered messages
+			if client.tenant != nil {
+				msg, ok = marshal(client.filter(params), client.binary), true
+			}
+
+			if !ok {
+				msg = marshal(params, client.binary)
+				msgs[client.binary] = msg
+			}
+
 			select {
-			case client.send <- []byte(msg):
+			case client.send <- msg:
 			default:
 				h.unregister <- client
 			}
@@ -167,11 +306,11 @@ func (h *SocketHub) broadcast(p util.Param) {
 }
 
 // Run starts data and status distribution
-func (h *SocketHub) Run(in <-chan util.Param, cache *util.Cache) {
+func (h *SocketHub) Run(in <-chan util.Param) {
 	for {
 		select {
 		case client := <-h.register:
-			h.welcome(client, cache.All())
+			h.welcome(client)
 		case client := <-h.unregister:
 			if _, ok := h.clients[client]; ok {
 				close(client.send)
@@ -5,6 +5,9 @@ import (
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/vmihailenco/msgpack/v5"
 )
 
 func TestEncode(t *testing.T) {
@@ -33,3 +36,27 @@ func TestEncode(t *testing.T) {
 		}
 	}
 }
+
+func TestMarshalBinary(t *testing.T) {
+	lp := 0
+	params := []util.Param{
+		{Key: "pvPower", Val: 1.5},
+		{Key: "chargeDuration", Val: time.Hour, LoadPoint: &lp},
+		{Key: "targetTime", Val: time.Time{}},
+	}
+
+	var res map[string]interface{}
+	if err := msgpack.Unmarshal(marshal(params, true), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"pvPower":                     1.5,
+		"loadpoints.0.chargeDuration": int64(3600),
+		"targetTime":                  "",
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("expected %v, got %v", expected, res)
+	}
+}
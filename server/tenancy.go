@@ -0,0 +1,302 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	dbserver "github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util"
+	"golang.org/x/exp/slices"
+)
+
+// TenantConfig scopes api access to the tenant's loadpoints and vehicles
+type TenantConfig struct {
+	Name       string
+	Token      string
+	Loadpoints []string // loadpoint titles
+	Vehicles   []string // vehicle titles
+}
+
+// TenancyConfig is the multi-tenant api configuration
+type TenancyConfig struct {
+	Admin   string // admin token with unrestricted access
+	Tenants []TenantConfig
+}
+
+// tenant is a tenant with resolved loadpoint and vehicle ids
+type tenant struct {
+	TenantConfig
+	loadpoints map[int]bool
+	vehicles   map[int]bool
+}
+
+// Tenancy authenticates api requests and restricts tenants to their loadpoints, vehicles and sessions
+type Tenancy struct {
+	admin   string
+	tenants []*tenant
+}
+
+type tenantKeyType struct{}
+
+var tenantKey tenantKeyType
+
+// NewTenancy creates tenancy from config. Returns nil if no tenants are configured.
+func NewTenancy(cc TenancyConfig, site site.API) (*Tenancy, error) {
+	if len(cc.Tenants) == 0 {
+		return nil, nil
+	}
+
+	if cc.Admin == "" {
+		return nil, errors.New("missing admin token")
+	}
+
+	t := &Tenancy{admin: cc.Admin}
+	tokens := []string{cc.Admin}
+
+	for _, tc := range cc.Tenants {
+		if tc.Name == "" || tc.Token == "" {
+			return nil, errors.New("tenant: missing name or token")
+		}
+
+		if slices.Contains(tokens, tc.Token) {
+			return nil, fmt.Errorf("tenant %s: duplicate token", tc.Name)
+		}
+		tokens = append(tokens, tc.Token)
+
+		tn := &tenant{
+			TenantConfig: tc,
+			loadpoints:   make(map[int]bool),
+			vehicles:     make(map[int]bool),
+		}
+
+		// empty loadpoints must not match all sessions
+		if tn.Loadpoints == nil {
+			tn.Loadpoints = []string{}
+		}
+
+		for _, title := range tc.Loadpoints {
+			id := slices.IndexFunc(site.LoadPoints(), func(lp loadpoint.API) bool { return lp.Name() == title })
+			if id < 0 {
+				return nil, fmt.Errorf("tenant %s: invalid loadpoint: %s", tc.Name, title)
+			}
+			tn.loadpoints[id] = true
+		}
+
+		for _, title := range tc.Vehicles {
+			id := slices.IndexFunc(site.GetVehicles(), func(v api.Vehicle) bool { return v.Title() == title })
+			if id < 0 {
+				return nil, fmt.Errorf("tenant %s: invalid vehicle: %s", tc.Name, title)
+			}
+			tn.vehicles[id] = true
+		}
+
+		t.tenants = append(t.tenants, tn)
+	}
+
+	return t, nil
+}
+
+// tokenCookie authenticates the web ui once it has been opened with token query
+const tokenCookie = "evcc_token"
+
+// requestToken returns the token from authorization header, token query or web ui cookie
+func requestToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+
+	if token := r.URL.Query().Get("token"); token != "" {
+		return token
+	}
+
+	if c, err := r.Cookie(tokenCookie); err == nil {
+		return c.Value
+	}
+
+	return ""
+}
+
+// tokenEqual compares tokens in constant time
+func tokenEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// authenticate returns the request's tenant, nil for admin requests
+func (t *Tenancy) authenticate(r *http.Request) (*tenant, error) {
+	token := requestToken(r)
+	if token == "" {
+		return nil, errors.New("missing token")
+	}
+
+	if tokenEqual(token, t.admin) {
+		return nil, nil
+	}
+
+	for _, tn := range t.tenants {
+		if tokenEqual(token, tn.Token) {
+			return tn, nil
+		}
+	}
+
+	return nil, errors.New("invalid token")
+}
+
+// login stores a valid token query as cookie for the web ui's api and websocket requests
+func (t *Tenancy) login(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if t == nil || token == "" {
+		return
+	}
+
+	if _, err := t.authenticate(r); err != nil {
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     tokenCookie,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// handler authenticates requests and attaches the tenant to the request context
+func (t *Tenancy) handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if t == nil || r.Method == http.MethodOptions {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		tn, err := t.authenticate(r)
+		if err != nil {
+			jsonError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tenantKey, tn)))
+	})
+}
+
+// requestTenant returns the authenticated tenant, nil for admin or without tenancy
+func requestTenant(r *http.Request) *tenant {
+	tn, _ := r.Context().Value(tenantKey).(*tenant)
+	return tn
+}
+
+// adminHandler restricts the handler to admin requests
+func adminHandler(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requestTenant(r) != nil {
+			jsonError(w, http.StatusForbidden, errors.New("admin only"))
+			return
+		}
+		h(w, r)
+	}
+}
+
+// loadpointHandler restricts access to the tenant's loadpoint
+func loadpointHandler(id int) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tn := requestTenant(r); tn != nil && !tn.loadpoints[id] {
+				jsonError(w, http.StatusForbidden, errors.New("loadpoint not accessible"))
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// state removes other tenants' loadpoints and vehicles from the state, keeping ids
+func (tn *tenant) state(res map[string]interface{}) map[string]interface{} {
+	if lps, ok := res["loadpoints"].([]map[string]interface{}); ok {
+		for id := range lps {
+			if !tn.loadpoints[id] {
+				lps[id] = nil
+			}
+		}
+	}
+
+	if p, ok := tn.param(util.Param{Key: "vehicles", Val: res["vehicles"]}); ok {
+		res["vehicles"] = p.Val
+	}
+
+	return res
+}
+
+// param filters the published value for the tenant, returning false if not visible
+func (tn *tenant) param(p util.Param) (util.Param, bool) {
+	if p.LoadPoint != nil {
+		return p, tn.loadpoints[*p.LoadPoint]
+	}
+
+	if titles, ok := p.Val.([]string); ok && p.Key == "vehicles" {
+		res := make([]string, len(titles))
+		for id, title := range titles {
+			if tn.vehicles[id] {
+				res[id] = title
+			}
+		}
+		p.Val = res
+	}
+
+	return p, true
+}
+
+// TenantStats is the aggregated admin view of a tenant
+type TenantStats struct {
+	Name          string   `json:"name"`
+	Loadpoints    []string `json:"loadpoints"`
+	Vehicles      []string `json:"vehicles"`
+	Sessions      int      `json:"sessions"`
+	ChargedEnergy float64  `json:"chargedEnergy"`
+}
+
+// tenantsHandler returns the tenants' charged sessions and energy within the requested period
+func tenantsHandler(t *Tenancy, loc *time.Location) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q, err := sessionQuery(r, loc)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		var res []TenantStats
+		for _, tn := range t.tenants {
+			ts := TenantStats{
+				Name:       tn.Name,
+				Loadpoints: tn.TenantConfig.Loadpoints,
+				Vehicles:   tn.TenantConfig.Vehicles,
+			}
+
+			if dbserver.Instance != nil {
+				q.Loadpoints = tn.TenantConfig.Loadpoints
+
+				sessions, _, err := q.Find(dbserver.Instance)
+				if err != nil {
+					jsonError(w, http.StatusBadRequest, err)
+					return
+				}
+
+				for _, s := range sessions {
+					ts.Sessions++
+					ts.ChargedEnergy += s.ChargedEnergy
+				}
+			}
+
+			res = append(res, ts)
+		}
+
+		jsonResult(w, res)
+	}
+}
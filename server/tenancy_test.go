@@ -0,0 +1,128 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenancy(t *testing.T) {
+	tn := &tenant{
+		TenantConfig: TenantConfig{Name: "flat1", Token: "secret1", Loadpoints: []string{"Carport"}},
+		loadpoints:   map[int]bool{1: true},
+		vehicles:     map[int]bool{0: true},
+	}
+
+	tenancy := &Tenancy{admin: "admin", tenants: []*tenant{tn}}
+
+	router := mux.NewRouter()
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(tenancy.handler)
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	api.HandleFunc("/state", ok)
+	api.HandleFunc("/buffersoc", adminHandler(ok))
+
+	for id := 0; id < 2; id++ {
+		lp := api.PathPrefix(fmt.Sprintf("/loadpoints/%d", id)).Subrouter()
+		lp.Use(loadpointHandler(id))
+		lp.HandleFunc("/mode", ok)
+	}
+
+	tc := []struct {
+		token, path string
+		status      int
+	}{
+		{"", "/api/state", http.StatusUnauthorized},
+		{"foo", "/api/state", http.StatusUnauthorized},
+		{"secret1", "/api/state", http.StatusOK},
+		{"secret1", "/api/buffersoc", http.StatusForbidden},
+		{"secret1", "/api/loadpoints/0/mode", http.StatusForbidden},
+		{"secret1", "/api/loadpoints/1/mode", http.StatusOK},
+		{"admin", "/api/buffersoc", http.StatusOK},
+		{"admin", "/api/loadpoints/0/mode", http.StatusOK},
+	}
+
+	for _, tc := range tc {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, tc.status, rr.Code, tc)
+	}
+
+	// query token for websocket clients
+	req := httptest.NewRequest(http.MethodGet, "/ws?token=secret1", nil)
+	res, err := tenancy.authenticate(req)
+	assert.NoError(t, err)
+	assert.Equal(t, tn, res)
+}
+
+func TestTenancyLogin(t *testing.T) {
+	tenancy := &Tenancy{admin: "admin"}
+	s := &HTTPd{tenancy: tenancy}
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	// invalid token is not stored
+	rr := httptest.NewRecorder()
+	s.loginHandler(ok)(rr, httptest.NewRequest(http.MethodGet, "/?token=foo", nil))
+	assert.Empty(t, rr.Result().Cookies())
+
+	// web ui opened with token
+	rr = httptest.NewRecorder()
+	s.loginHandler(ok)(rr, httptest.NewRequest(http.MethodGet, "/?token=admin", nil))
+	cookies := rr.Result().Cookies()
+	assert.Len(t, cookies, 1)
+
+	// api and admin-only routes authenticated by cookie
+	metrics := s.AdminHandler(http.HandlerFunc(ok))
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rr = httptest.NewRecorder()
+	metrics.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	req.AddCookie(cookies[0])
+	rr = httptest.NewRecorder()
+	metrics.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestTenantFilter(t *testing.T) {
+	tn := &tenant{
+		loadpoints: map[int]bool{1: true},
+		vehicles:   map[int]bool{1: true},
+	}
+
+	lp0, lp1 := 0, 1
+
+	_, ok := tn.param(util.Param{LoadPoint: &lp0, Key: "chargePower", Val: 1.0})
+	assert.False(t, ok)
+
+	_, ok = tn.param(util.Param{LoadPoint: &lp1, Key: "chargePower", Val: 1.0})
+	assert.True(t, ok)
+
+	p, ok := tn.param(util.Param{Key: "vehicles", Val: []string{"car1", "car2"}})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"", "car2"}, p.Val)
+
+	state := tn.state(map[string]interface{}{
+		"gridPower":  1.0,
+		"vehicles":   []string{"car1", "car2"},
+		"loadpoints": []map[string]interface{}{{"title": "Garage"}, {"title": "Carport"}},
+	})
+
+	assert.Equal(t, 1.0, state["gridPower"])
+	assert.Equal(t, []string{"", "car2"}, state["vehicles"])
+	assert.Equal(t, []map[string]interface{}{nil, {"title": "Carport"}}, state["loadpoints"])
+}
@@ -23,8 +23,9 @@ func removeIfExists(file string) {
 	}
 }
 
-// HealthListener attaches listener to unix domain socket and runs listener
-func HealthListener(site site.API) {
+// HealthListener attaches listener to unix domain socket and runs listener.
+// The site is resolved per request to follow configuration reloads.
+func HealthListener(site func() site.API) {
 	removeIfExists(SocketPath)
 
 	l, err := net.Listen("unix", SocketPath)
@@ -34,7 +35,9 @@ func HealthListener(site site.API) {
 
 	mux := http.NewServeMux()
 	httpd := http.Server{Handler: mux}
-	mux.HandleFunc("/health", healthHandler(site))
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		healthHandler(site())(w, r)
+	})
 
 	go func() { _ = httpd.Serve(l) }()
 
@@ -5,6 +5,6 @@ package server
 import "github.com/evcc-io/evcc/core/site"
 
 // HealthListener attaches listener to unix domain socket
-func HealthListener(_ site.API) {
+func HealthListener(_ func() site.API) {
 	// nop
 }
@@ -0,0 +1,209 @@
+// Package vehicleproxy shares the configured vehicles with other evcc instances or tools.
+// All clients use the single authenticated vehicle session of this instance and a common response cache.
+package vehicleproxy
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+	"github.com/gorilla/mux"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Features which are exposed in addition to the vehicle's soc
+const (
+	FeatureStatus   = "status"
+	FeatureRange    = "range"
+	FeatureOdometer = "odometer"
+)
+
+// Vehicle is the vehicle's description
+type Vehicle struct {
+	Name     string   `json:"name"`
+	Title    string   `json:"title"`
+	Capacity float64  `json:"capacity"`
+	Features []string `json:"features"`
+}
+
+// Response is the proxy's response envelope
+type Response[T any] struct {
+	Result T      `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+type vehicle struct {
+	Vehicle
+	soc      func() (float64, error)
+	status   func() (api.ChargeStatus, error)
+	rng      func() (int64, error)
+	odometer func() (float64, error)
+}
+
+type handler struct {
+	log      *util.Logger
+	token    string
+	vehicles map[string]*vehicle
+}
+
+// New creates the proxy handler for the given vehicles. Vehicle responses are cached for the cache duration.
+func New(vehicles map[string]api.Vehicle, token string, cache time.Duration) http.Handler {
+	h := &handler{
+		log:      util.NewLogger("vehicle-proxy"),
+		token:    token,
+		vehicles: make(map[string]*vehicle),
+	}
+
+	for name, v := range vehicles {
+		pv := &vehicle{
+			Vehicle: Vehicle{
+				Name:     name,
+				Title:    v.Title(),
+				Capacity: v.Capacity(),
+				Features: []string{},
+			},
+			soc: provider.Cached(v.SoC, cache),
+		}
+
+		if vv, ok := v.(api.ChargeState); ok {
+			pv.Features = append(pv.Features, FeatureStatus)
+			pv.status = provider.Cached(vv.Status, cache)
+		}
+
+		if vv, ok := v.(api.VehicleRange); ok {
+			pv.Features = append(pv.Features, FeatureRange)
+			pv.rng = provider.Cached(vv.Range, cache)
+		}
+
+		if vv, ok := v.(api.VehicleOdometer); ok {
+			pv.Features = append(pv.Features, FeatureOdometer)
+			pv.odometer = provider.Cached(vv.Odometer, cache)
+		}
+
+		h.vehicles[name] = pv
+	}
+
+	router := mux.NewRouter()
+	router.Use(h.authHandler)
+
+	router.HandleFunc("/vehicles", h.vehiclesHandler).Methods(http.MethodGet)
+	router.HandleFunc("/vehicles/{name}", h.vehicleHandler).Methods(http.MethodGet)
+	router.HandleFunc("/vehicles/{name}/soc", valueHandler(h, func(v *vehicle) func() (float64, error) { return v.soc })).Methods(http.MethodGet)
+	router.HandleFunc("/vehicles/{name}/status", valueHandler(h, func(v *vehicle) func() (api.ChargeStatus, error) { return v.status })).Methods(http.MethodGet)
+	router.HandleFunc("/vehicles/{name}/range", valueHandler(h, func(v *vehicle) func() (int64, error) { return v.rng })).Methods(http.MethodGet)
+	router.HandleFunc("/vehicles/{name}/odometer", valueHandler(h, func(v *vehicle) func() (float64, error) { return v.odometer })).Methods(http.MethodGet)
+
+	return router
+}
+
+// StartProxy starts the vehicle proxy at the given port
+func StartProxy(port int, vehicles map[string]api.Vehicle, token string, cache time.Duration) error {
+	h := New(vehicles, token, cache)
+
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+
+	log := util.NewLogger("vehicle-proxy")
+	log.DEBUG.Printf("vehicle proxy for %s listening at :%d", strings.Join(maps.Keys(vehicles), ", "), port)
+
+	go func() {
+		srv := &http.Server{Handler: h, ReadHeaderTimeout: 10 * time.Second}
+		log.ERROR.Println(srv.Serve(l))
+	}()
+
+	return nil
+}
+
+// authHandler validates the bearer token if configured
+func (h *handler) authHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.token != "" {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+				writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, res any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, Response[any]{Error: err.Error()})
+}
+
+// vehicle returns the requested vehicle or writes an error response
+func (h *handler) vehicle(w http.ResponseWriter, r *http.Request) (*vehicle, bool) {
+	name := mux.Vars(r)["name"]
+
+	v, ok := h.vehicles[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("vehicle does not exist: %s", name))
+	}
+
+	return v, ok
+}
+
+func (h *handler) vehiclesHandler(w http.ResponseWriter, r *http.Request) {
+	res := make([]Vehicle, 0, len(h.vehicles))
+	for _, v := range h.vehicles {
+		res = append(res, v.Vehicle)
+	}
+
+	slices.SortFunc(res, func(a, b Vehicle) bool { return a.Name < b.Name })
+
+	writeJSON(w, http.StatusOK, Response[[]Vehicle]{Result: res})
+}
+
+func (h *handler) vehicleHandler(w http.ResponseWriter, r *http.Request) {
+	if v, ok := h.vehicle(w, r); ok {
+		writeJSON(w, http.StatusOK, Response[Vehicle]{Result: v.Vehicle})
+	}
+}
+
+// valueHandler serves the vehicle's cached getter selected by get
+func valueHandler[T any](h *handler, get func(*vehicle) func() (T, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		v, ok := h.vehicle(w, r)
+		if !ok {
+			return
+		}
+
+		err := api.ErrNotAvailable
+
+		var res T
+		if g := get(v); g != nil {
+			res, err = g()
+		}
+
+		switch {
+		case err == nil:
+			writeJSON(w, http.StatusOK, Response[T]{Result: res})
+		case errors.Is(err, api.ErrNotAvailable):
+			writeError(w, http.StatusNotFound, err)
+		case errors.Is(err, api.ErrMustRetry):
+			writeError(w, http.StatusServiceUnavailable, err)
+		default:
+			h.log.DEBUG.Printf("%s: %v", v.Name, err)
+			writeError(w, http.StatusBadGateway, err)
+		}
+	}
+}
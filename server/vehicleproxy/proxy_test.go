@@ -0,0 +1,95 @@
+package vehicleproxy_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/vehicleproxy"
+	"github.com/evcc-io/evcc/vehicle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testVehicle struct {
+	api.Vehicle
+	calls int
+	err   error
+}
+
+func (v *testVehicle) Title() string     { return "Car" }
+func (v *testVehicle) Capacity() float64 { return 50 }
+
+func (v *testVehicle) SoC() (float64, error) {
+	v.calls++
+	return 42, v.err
+}
+
+func (v *testVehicle) Range() (int64, error) {
+	return 300, nil
+}
+
+func TestProxy(t *testing.T) {
+	tv := &testVehicle{}
+
+	srv := httptest.NewServer(vehicleproxy.New(map[string]api.Vehicle{"car": tv}, "secret", time.Hour))
+	defer srv.Close()
+
+	_, err := vehicle.NewProxyFromConfig(map[string]interface{}{"uri": srv.URL, "vehicle": "car", "token": "wrong"})
+	assert.ErrorIs(t, err, api.ErrAuthExpired)
+
+	_, err = vehicle.NewProxyFromConfig(map[string]interface{}{"uri": srv.URL, "vehicle": "bike", "token": "secret"})
+	assert.Error(t, err)
+
+	// clients share the proxy's cache
+	for i := 0; i < 2; i++ {
+		v, err := vehicle.NewProxyFromConfig(map[string]interface{}{"uri": srv.URL, "vehicle": "car", "token": "secret", "cache": 0})
+		require.NoError(t, err)
+
+		assert.Equal(t, float64(50), v.Capacity())
+
+		soc, err := v.SoC()
+		require.NoError(t, err)
+		assert.Equal(t, float64(42), soc)
+
+		vr, ok := v.(api.VehicleRange)
+		require.True(t, ok)
+
+		rng, err := vr.Range()
+		require.NoError(t, err)
+		assert.Equal(t, int64(300), rng)
+
+		_, ok = v.(api.ChargeState)
+		assert.False(t, ok)
+	}
+
+	assert.Equal(t, 1, tv.calls)
+}
+
+func TestProxyErrors(t *testing.T) {
+	tv := &testVehicle{err: api.ErrMustRetry}
+
+	srv := httptest.NewServer(vehicleproxy.New(map[string]api.Vehicle{"car": tv}, "", time.Hour))
+	defer srv.Close()
+
+	v, err := vehicle.NewProxyFromConfig(map[string]interface{}{"uri": srv.URL, "vehicle": "car", "cache": 0})
+	require.NoError(t, err)
+
+	_, err = v.SoC()
+	assert.ErrorIs(t, err, api.ErrMustRetry)
+
+	// retries are not cached
+	tv.err = errors.New("login failed")
+
+	_, err = v.SoC()
+	assert.EqualError(t, err, "login failed: unexpected status: 502")
+	assert.Equal(t, 2, tv.calls)
+
+	resp, err := http.Get(srv.URL + "/vehicles/car/status")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/evcc-io/evcc/cmd/shutdown"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+)
+
+// WatchdogConfig is the hardware watchdog configuration
+type WatchdogConfig struct {
+	Device   string        // hardware watchdog device, e.g. /dev/watchdog
+	Interval time.Duration // hardware watchdog heartbeat interval
+}
+
+const watchdogInterval = 5 * time.Second
+
+// Watchdog sends heartbeats to the systemd service manager and an optional hardware watchdog
+// while the site's control loop is healthy. A hung control loop stops the heartbeats and leads
+// to a restart of the service or a reset of the system.
+type Watchdog struct {
+	log      *util.Logger
+	site     func() site.API
+	socket   string         // systemd notification socket
+	systemd  bool           // systemd watchdog enabled
+	device   io.WriteCloser // hardware watchdog device
+	interval time.Duration
+}
+
+// NewWatchdog creates a watchdog for the site resolved at each heartbeat
+func NewWatchdog(cc WatchdogConfig, site func() site.API) (*Watchdog, error) {
+	w := &Watchdog{
+		log:      util.NewLogger("watchdog"),
+		site:     site,
+		socket:   os.Getenv("NOTIFY_SOCKET"),
+		interval: cc.Interval,
+	}
+
+	if w.interval == 0 {
+		w.interval = watchdogInterval
+	}
+
+	// systemd expects heartbeats within WATCHDOG_USEC, use half of it to account for delays
+	if usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64); err == nil && usec > 0 && w.socket != "" {
+		if pid := os.Getenv("WATCHDOG_PID"); pid == "" || pid == strconv.Itoa(os.Getpid()) {
+			w.systemd = true
+			if timeout := time.Duration(usec) * time.Microsecond / 2; timeout < w.interval {
+				w.interval = timeout
+			}
+		}
+	}
+
+	if cc.Device != "" {
+		// opening the device arms the hardware watchdog
+		f, err := os.OpenFile(cc.Device, os.O_WRONLY, 0)
+		if err != nil {
+			return nil, fmt.Errorf("watchdog: %w", err)
+		}
+		w.device = f
+	}
+
+	return w, nil
+}
+
+// notify sends a state notification to the systemd service manager
+func (w *Watchdog) notify(state string) {
+	if w.socket == "" {
+		return
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: w.socket, Net: "unixgram"})
+	if err == nil {
+		_, err = conn.Write([]byte(state))
+		conn.Close()
+	}
+
+	if err != nil {
+		w.log.ERROR.Printf("notify: %v", err)
+	}
+}
+
+// heartbeat signals liveness if the control loop is healthy
+func (w *Watchdog) heartbeat() bool {
+	if site := w.site(); site == nil || !site.Healthy() {
+		return false
+	}
+
+	if w.systemd {
+		w.notify("WATCHDOG=1")
+	}
+
+	if w.device != nil {
+		if _, err := w.device.Write([]byte{0}); err != nil {
+			w.log.ERROR.Printf("device: %v", err)
+		}
+	}
+
+	return true
+}
+
+// stop disarms the hardware watchdog on regular shutdown
+func (w *Watchdog) stop() {
+	w.notify("STOPPING=1")
+
+	if w.device != nil {
+		// magic close character disarms the watchdog
+		_, _ = w.device.Write([]byte("V"))
+		if err := w.device.Close(); err != nil {
+			w.log.ERROR.Printf("device: %v", err)
+		}
+	}
+}
+
+// Run sends heartbeats until shutdown
+func (w *Watchdog) Run() {
+	if !w.systemd && w.device == nil {
+		w.notify("READY=1")
+		return
+	}
+
+	w.log.DEBUG.Printf("heartbeat interval: %v", w.interval)
+
+	stopC := make(chan struct{})
+	doneC := make(chan struct{})
+
+	shutdown.Register(func() {
+		close(stopC)
+		<-doneC
+	})
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.notify("READY=1")
+
+	var healthy bool
+	for {
+		select {
+		case <-ticker.C:
+			if ok := w.heartbeat(); ok != healthy {
+				healthy = ok
+				if !ok {
+					w.log.WARN.Println("control loop unhealthy, heartbeat suspended")
+				}
+			}
+
+		case <-stopC:
+			w.stop()
+			close(doneC)
+			return
+		}
+	}
+}
@@ -0,0 +1,77 @@
+//go:build !windows
+
+package server
+
+import (
+	"bytes"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type watchdogSite struct {
+	site.API
+	healthy bool
+}
+
+func (s *watchdogSite) Healthy() bool {
+	return s.healthy
+}
+
+type watchdogDevice struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (d *watchdogDevice) Close() error {
+	d.closed = true
+	return nil
+}
+
+func TestWatchdogHeartbeat(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	received := func() string {
+		b := make([]byte, 64)
+		n, err := conn.Read(b)
+		require.NoError(t, err)
+		return string(b[:n])
+	}
+
+	s := &watchdogSite{healthy: true}
+	dev := new(watchdogDevice)
+
+	w := &Watchdog{
+		log:     util.NewLogger("foo"),
+		site:    func() site.API { return s },
+		socket:  socket,
+		systemd: true,
+		device:  dev,
+	}
+
+	assert.True(t, w.heartbeat())
+	assert.Equal(t, "WATCHDOG=1", received())
+	assert.Equal(t, 1, dev.Len())
+
+	// hung control loop
+	s.healthy = false
+	assert.False(t, w.heartbeat())
+	assert.Equal(t, 1, dev.Len())
+
+	// no site
+	w.site = func() site.API { return nil }
+	assert.False(t, w.heartbeat())
+
+	w.stop()
+	assert.Equal(t, "STOPPING=1", received())
+	assert.True(t, dev.closed)
+	assert.Equal(t, byte('V'), dev.Bytes()[dev.Len()-1])
+}
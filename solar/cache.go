@@ -0,0 +1,44 @@
+package solar
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// cache periodically refreshes and holds the forecast
+type cache struct {
+	mux  sync.Mutex
+	log  *util.Logger
+	data []api.ForecastSlot
+}
+
+// run refreshes the forecast in the given interval
+func (c *cache) run(interval time.Duration, fetch func() ([]api.ForecastSlot, error)) {
+	for ; true; <-time.NewTicker(interval).C {
+		data, err := fetch()
+		if err != nil {
+			c.log.ERROR.Println(err)
+			continue
+		}
+
+		c.mux.Lock()
+		c.data = data
+		c.mux.Unlock()
+	}
+}
+
+// Forecast implements the api.SolarForecast interface
+func (c *cache) Forecast() ([]api.ForecastSlot, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if len(c.data) == 0 {
+		return nil, errors.New("no forecast available")
+	}
+
+	return c.data, nil
+}
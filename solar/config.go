@@ -0,0 +1,28 @@
+package solar
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// Config is the typed solar forecast configuration
+type Config struct {
+	Type  string
+	Other map[string]interface{} `mapstructure:",remain"`
+}
+
+// NewFromConfig creates solar forecast from config
+func NewFromConfig(typ string, other map[string]interface{}) (f api.SolarForecast, err error) {
+	switch strings.ToLower(typ) {
+	case "forecast.solar":
+		f, err = NewForecastSolar(other)
+	case "solcast":
+		f, err = NewSolcast(other)
+	default:
+		return nil, errors.New("unknown solar forecast: " + typ)
+	}
+
+	return
+}
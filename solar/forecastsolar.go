@@ -0,0 +1,114 @@
+package solar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// https://doc.forecast.solar/api:estimate
+
+const forecastSolarURI = "https://api.forecast.solar/estimate/%g/%g/%g/%g/%g"
+
+// ForecastSolar is the forecast.solar public api forecast
+type ForecastSolar struct {
+	*cache
+	uri string
+}
+
+var _ api.SolarForecast = (*ForecastSolar)(nil)
+
+type forecastSolarResponse struct {
+	Result struct {
+		WattHoursPeriod map[string]float64 `json:"watt_hours_period"`
+	}
+	Message struct {
+		Info struct {
+			Timezone string
+		}
+	}
+}
+
+// NewForecastSolar creates forecast.solar forecast
+func NewForecastSolar(other map[string]interface{}) (*ForecastSolar, error) {
+	cc := struct {
+		Latitude, Longitude float64
+		Declination         float64 // panel tilt, 0 horizontal to 90 vertical
+		Azimuth             float64 // -180 north, -90 east, 0 south, 90 west
+		KWp                 float64 `mapstructure:"kwp"`
+		Interval            time.Duration
+	}{
+		Interval: time.Hour, // public api is rate limited
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.KWp <= 0 {
+		return nil, fmt.Errorf("missing kwp")
+	}
+
+	f := &ForecastSolar{
+		cache: &cache{log: util.NewLogger("forecast.solar")},
+		uri:   fmt.Sprintf(forecastSolarURI, cc.Latitude, cc.Longitude, cc.Declination, cc.Azimuth, cc.KWp),
+	}
+
+	go f.run(cc.Interval, f.fetch)
+
+	return f, nil
+}
+
+func (f *ForecastSolar) fetch() ([]api.ForecastSlot, error) {
+	var res forecastSolarResponse
+	if err := request.NewHelper(f.log).GetJSON(f.uri, &res); err != nil {
+		return nil, err
+	}
+
+	return forecastSolarSlots(res)
+}
+
+// forecastSolarSlots converts the energy of the periods ending at the given local times to power slots
+func forecastSolarSlots(res forecastSolarResponse) ([]api.ForecastSlot, error) {
+	loc, err := time.LoadLocation(res.Message.Info.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	ends := make([]time.Time, 0, len(res.Result.WattHoursPeriod))
+	energy := make(map[time.Time]float64)
+
+	for key, wh := range res.Result.WattHoursPeriod {
+		ts, err := time.ParseInLocation("2006-01-02 15:04:05", key, loc)
+		if err != nil {
+			return nil, err
+		}
+
+		ends = append(ends, ts)
+		energy[ts] = wh
+	}
+
+	sort.Slice(ends, func(i, j int) bool { return ends[i].Before(ends[j]) })
+
+	var slots []api.ForecastSlot
+	for i := 1; i < len(ends); i++ {
+		start, end := ends[i-1], ends[i]
+
+		// periods do not span the night
+		if start.YearDay() != end.YearDay() {
+			continue
+		}
+
+		slots = append(slots, api.ForecastSlot{
+			Start: start,
+			End:   end,
+			Power: energy[end] / end.Sub(start).Hours(),
+		})
+	}
+
+	return slots, nil
+}
@@ -0,0 +1,97 @@
+package solar
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// https://docs.solcast.com.au/#forecasts-rooftop-site
+
+const solcastURI = "https://api.solcast.com.au/rooftop_sites/%s/forecasts?format=json"
+
+// Solcast is the Solcast rooftop site forecast
+type Solcast struct {
+	*cache
+	uri, key string
+}
+
+var _ api.SolarForecast = (*Solcast)(nil)
+
+type solcastResponse struct {
+	Forecasts []struct {
+		PvEstimate float64   `json:"pv_estimate"` // kW
+		PeriodEnd  time.Time `json:"period_end"`
+		Period     string    `json:"period"` // ISO 8601 duration, e.g. PT30M
+	}
+}
+
+// NewSolcast creates Solcast forecast
+func NewSolcast(other map[string]interface{}) (*Solcast, error) {
+	cc := struct {
+		Site, Token string
+		Interval    time.Duration
+	}{
+		Interval: 3 * time.Hour, // free api is limited to 10 requests per day
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.Site == "" || cc.Token == "" {
+		return nil, errors.New("missing site or token")
+	}
+
+	f := &Solcast{
+		cache: &cache{log: util.NewLogger("solcast")},
+		uri:   fmt.Sprintf(solcastURI, cc.Site),
+		key:   cc.Token,
+	}
+
+	go f.run(cc.Interval, f.fetch)
+
+	return f, nil
+}
+
+func (f *Solcast) fetch() ([]api.ForecastSlot, error) {
+	req, err := request.New(http.MethodGet, f.uri, nil, map[string]string{
+		"Authorization": "Bearer " + f.key,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res solcastResponse
+	if err := request.NewHelper(f.log).DoJSON(req, &res); err != nil {
+		return nil, err
+	}
+
+	return solcastSlots(res)
+}
+
+// solcastSlots converts the periods' estimates to power slots
+func solcastSlots(res solcastResponse) ([]api.ForecastSlot, error) {
+	slots := make([]api.ForecastSlot, 0, len(res.Forecasts))
+
+	for _, f := range res.Forecasts {
+		period, err := time.ParseDuration(strings.ToLower(strings.TrimPrefix(f.Period, "PT")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid period: %s", f.Period)
+		}
+
+		slots = append(slots, api.ForecastSlot{
+			Start: f.PeriodEnd.Add(-period),
+			End:   f.PeriodEnd,
+			Power: 1e3 * f.PvEstimate,
+		})
+	}
+
+	return slots, nil
+}
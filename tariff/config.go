@@ -16,6 +16,8 @@ func NewFromConfig(typ string, other map[string]interface{}) (t api.Tariff, err
 		t, err = NewAwattar(other)
 	case "tibber":
 		t, err = NewTibber(other)
+	case "schedule":
+		t, err = NewSchedule(other)
 	default:
 		return nil, errors.New("unknown tariff: " + typ)
 	}
@@ -0,0 +1,180 @@
+package tariff
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/tariff/schedule"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Schedule is a custom price schedule imported from csv or xlsx
+type Schedule struct {
+	mux     sync.Mutex
+	log     *util.Logger
+	path    string
+	cheap   float64
+	data    []api.Rate
+	modTime time.Time      // file modification time of loaded schedule
+	loc     *time.Location // timezone of schedule timestamps
+}
+
+var (
+	_ api.Tariff         = (*Schedule)(nil)
+	_ api.TariffRates    = (*Schedule)(nil)
+	_ api.TariffImporter = (*Schedule)(nil)
+	_ api.TariffLocation = (*Schedule)(nil)
+)
+
+func NewSchedule(other map[string]interface{}) (*Schedule, error) {
+	cc := struct {
+		Cheap    float64
+		Path     string
+		Interval time.Duration
+	}{
+		Interval: time.Minute,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	t := &Schedule{
+		log:   util.NewLogger("schedule"),
+		cheap: cc.Cheap,
+		path:  cc.Path,
+		loc:   time.Local,
+	}
+
+	if t.path != "" {
+		if err := t.reload(); err != nil {
+			return nil, err
+		}
+
+		go t.Run(cc.Interval)
+	}
+
+	return t, nil
+}
+
+// Run reloads the schedule when the file changes
+func (t *Schedule) Run(interval time.Duration) {
+	for range time.NewTicker(interval).C {
+		if err := t.reload(); err != nil {
+			t.log.ERROR.Println(err)
+		}
+	}
+}
+
+// reload loads the schedule file if it has been modified
+func (t *Schedule) reload() error {
+	fi, err := os.Stat(t.path)
+	if err != nil {
+		return err
+	}
+
+	t.mux.Lock()
+	modified := !fi.ModTime().Equal(t.modTime)
+	loc := t.loc
+	t.mux.Unlock()
+
+	if !modified {
+		return nil
+	}
+
+	b, err := os.ReadFile(t.path)
+	if err != nil {
+		return err
+	}
+
+	data, err := schedule.Parse(b, loc)
+	if err != nil {
+		return err
+	}
+
+	t.log.DEBUG.Printf("loaded %d prices from %s", len(data), t.path)
+
+	t.mux.Lock()
+	t.data = data
+	t.modTime = fi.ModTime()
+	t.mux.Unlock()
+
+	return nil
+}
+
+// Import implements the api.TariffImporter interface.
+// The schedule is persisted to the configured file.
+func (t *Schedule) Import(b []byte) error {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	data, err := schedule.Parse(b, t.loc)
+	if err != nil {
+		return err
+	}
+
+	if t.path != "" {
+		if err := os.WriteFile(t.path, b, 0o644); err != nil {
+			return err
+		}
+
+		if fi, err := os.Stat(t.path); err == nil {
+			t.modTime = fi.ModTime()
+		}
+	}
+
+	t.log.DEBUG.Printf("imported %d prices", len(data))
+	t.data = data
+
+	return nil
+}
+
+// SetLocation implements the api.TariffLocation interface.
+// The schedule file is reloaded using the new timezone.
+func (t *Schedule) SetLocation(loc *time.Location) {
+	t.mux.Lock()
+	t.loc = loc
+	t.modTime = time.Time{}
+	t.mux.Unlock()
+
+	if t.path != "" {
+		if err := t.reload(); err != nil {
+			t.log.ERROR.Println(err)
+		}
+	}
+}
+
+func (t *Schedule) Rates() ([]api.Rate, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.data, nil
+}
+
+func (t *Schedule) CurrentPrice() (float64, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	now := time.Now()
+	for _, r := range t.data {
+		if !r.Start.After(now) && r.End.After(now) {
+			return r.Price, nil
+		}
+	}
+
+	return 0, errors.New("unable to find current price")
+}
+
+func (t *Schedule) IsCheap() (bool, error) {
+	price, err := t.CurrentPrice()
+	return price <= t.cheap, err
+}
+
+var _ api.TariffCheapLimit = (*Schedule)(nil)
+
+// CheapLimit implements the api.TariffCheapLimit interface
+func (t *Schedule) CheapLimit() float64 {
+	return t.cheap
+}
@@ -0,0 +1,34 @@
+package schedule
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+)
+
+// csvRows reads timestamp and price columns separated by comma or semicolon
+func csvRows(b []byte) ([]row, error) {
+	r := csv.NewReader(bytes.NewReader(b))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	// semicolon in first line, e.g. spreadsheets using decimal comma
+	if line, _, _ := bytes.Cut(b, []byte("\n")); bytes.ContainsRune(line, ';') {
+		r.Comma = ';'
+	}
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]row, 0, len(records))
+	for i, rec := range records {
+		if len(rec) < 2 {
+			return nil, fmt.Errorf("row %d: missing price", i+1)
+		}
+		res = append(res, row{ts: rec[0], price: rec[1]})
+	}
+
+	return res, nil
+}
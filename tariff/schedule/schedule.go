@@ -0,0 +1,116 @@
+// Package schedule parses price schedules published as spreadsheets
+package schedule
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// timeFormats are the accepted timestamp formats, interpreted in the given location without zone
+var timeFormats = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"02.01.2006 15:04:05",
+	"02.01.2006 15:04",
+}
+
+// row is a spreadsheet row's timestamp and price cells
+type row struct {
+	ts, price string
+}
+
+// Parse parses a csv or xlsx price schedule of timestamp and price rows.
+// Each price is valid until the next row's timestamp. A heading row is skipped.
+// Timestamps without zone are interpreted in loc.
+func Parse(b []byte, loc *time.Location) ([]api.Rate, error) {
+	var rows []row
+	var err error
+
+	if bytes.HasPrefix(b, []byte("PK\x03\x04")) {
+		rows, err = xlsxRows(b)
+	} else {
+		rows, err = csvRows(b)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rates(rows, loc)
+}
+
+func parseTime(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+
+	for _, f := range timeFormats {
+		if ts, err := time.ParseInLocation(f, s, loc); err == nil {
+			return ts, nil
+		}
+	}
+
+	// excel serial date in days
+	if days, err := strconv.ParseFloat(s, 64); err == nil {
+		return excelTime(days, loc), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid timestamp: %s", s)
+}
+
+// excelTime converts the excel serial date in days since 1899-12-30 to time in loc
+func excelTime(days float64, loc *time.Location) time.Time {
+	day := int(days)
+	seconds := int((days-float64(day))*86400 + 0.5)
+	return time.Date(1899, 12, 30, 0, 0, seconds, 0, loc).AddDate(0, 0, day)
+}
+
+func parsePrice(s string) (float64, error) {
+	// decimal comma
+	return strconv.ParseFloat(strings.Replace(strings.TrimSpace(s), ",", ".", 1), 64)
+}
+
+func rates(rows []row, loc *time.Location) ([]api.Rate, error) {
+	res := make([]api.Rate, 0, len(rows))
+
+	for i, r := range rows {
+		ts, err := parseTime(r.ts, loc)
+		if err == nil {
+			var price float64
+			if price, err = parsePrice(r.price); err == nil {
+				res = append(res, api.Rate{Start: ts, Price: price})
+				continue
+			}
+		}
+
+		// heading
+		if i == 0 {
+			continue
+		}
+
+		return nil, fmt.Errorf("row %d: %w", i+1, err)
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("empty schedule")
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Start.Before(res[j].Start) })
+
+	// last price is valid for the previous slot's duration
+	duration := time.Hour
+	for i := range res {
+		if i+1 < len(res) {
+			duration = res[i+1].Start.Sub(res[i].Start)
+		}
+		res[i].End = res[i].Start.Add(duration)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,69 @@
+package schedule
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCsv(t *testing.T) {
+	for _, csv := range []string{
+		"timestamp,price\n2022-10-01 00:00,0.30\n2022-10-01 01:00,0.25\n",
+		"Zeit;Preis\n01.10.2022 00:00;0,30\n01.10.2022 01:00;0,25\n",
+	} {
+		res, err := Parse([]byte(csv), time.Local)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+
+		start := time.Date(2022, 10, 1, 0, 0, 0, 0, time.Local)
+		assert.Equal(t, start, res[0].Start)
+		assert.Equal(t, start.Add(time.Hour), res[0].End)
+		assert.Equal(t, 0.30, res[0].Price)
+		assert.Equal(t, start.Add(2*time.Hour), res[1].End, "last slot duration")
+	}
+
+	_, err := Parse([]byte("2022-10-01 00:00,0.30\nfoo,0.25\n"), time.Local)
+	assert.Error(t, err)
+}
+
+func TestLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	res, err := Parse([]byte("2022-10-01 00:00,0.30\n"), loc)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2022, 10, 1, 4, 0, 0, 0, time.UTC), res[0].Start.UTC())
+}
+
+func TestXlsx(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for name, content := range map[string]string{
+		"xl/sharedStrings.xml": `<sst><si><t>Start</t></si><si><t>Price</t></si><si><t>2022-10-01 01:00</t></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+			<row r="2"><c r="A2"><v>44835</v></c><c r="B2"><v>0.3</v></c></row>
+			<row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3"><v>0.25</v></c></row>
+		</sheetData></worksheet>`,
+	} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, _ = w.Write([]byte(content))
+	}
+	require.NoError(t, zw.Close())
+
+	res, err := Parse(buf.Bytes(), time.Local)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	start := time.Date(2022, 10, 1, 0, 0, 0, 0, time.Local)
+	assert.Equal(t, start, res[0].Start)
+	assert.Equal(t, 0.3, res[0].Price)
+	assert.Equal(t, start.Add(time.Hour), res[1].Start)
+	assert.Equal(t, 0.25, res[1].Price)
+}
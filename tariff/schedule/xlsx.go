@@ -0,0 +1,114 @@
+package schedule
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// xlsxSheet is the sheet's xml relevant to reading cell values
+type xlsxSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+func xlsxDecode(zr *zip.Reader, name string, res interface{}) (bool, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return true, err
+		}
+		defer rc.Close()
+
+		return true, xml.NewDecoder(rc).Decode(res)
+	}
+
+	return false, nil
+}
+
+// xlsxRows reads the timestamp and price from columns A and B of the workbook's first sheet
+func xlsxRows(b []byte) ([]row, error) {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if _, err := xlsxDecode(zr, "xl/sharedStrings.xml", &shared); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	strs := make([]string, 0, len(shared.Items))
+	for _, si := range shared.Items {
+		s := si.Text
+		for _, r := range si.Runs {
+			s += r.Text
+		}
+		strs = append(strs, s)
+	}
+
+	var sheet xlsxSheet
+	found, err := xlsxDecode(zr, "xl/worksheets/sheet1.xml", &sheet)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.New("missing worksheet")
+	}
+
+	res := make([]row, 0, len(sheet.Rows))
+	for _, r := range sheet.Rows {
+		var cols row
+
+		for _, c := range r.Cells {
+			val := c.Value
+
+			switch c.Type {
+			case "s":
+				idx, err := strconv.Atoi(val)
+				if err != nil || idx >= len(strs) {
+					return nil, errors.New("invalid shared string: " + val)
+				}
+				val = strs[idx]
+			case "inlineStr":
+				val = c.Inline
+			}
+
+			switch col := strings.TrimRight(c.Ref, "0123456789"); col {
+			case "A":
+				cols.ts = val
+			case "B":
+				cols.price = val
+			}
+		}
+
+		if cols.ts != "" || cols.price != "" {
+			res = append(res, cols)
+		}
+	}
+
+	return res, nil
+}
@@ -25,7 +25,10 @@ type Tibber struct {
 	data   []tibber.PriceInfo
 }
 
-var _ api.Tariff = (*Tibber)(nil)
+var (
+	_ api.Tariff      = (*Tibber)(nil)
+	_ api.TariffRates = (*Tibber)(nil)
+)
 
 func NewTibber(other map[string]interface{}) (*Tibber, error) {
 	t := &Tibber{
@@ -93,7 +96,8 @@ func (t *Tibber) Run() {
 		}
 
 		t.mux.Lock()
-		t.data = res.Viewer.Home.CurrentSubscription.PriceInfo.Today
+		pi := res.Viewer.Home.CurrentSubscription.PriceInfo
+		t.data = append(pi.Today, pi.Tomorrow...)
 		t.mux.Unlock()
 	}
 }
@@ -116,3 +120,26 @@ func (t *Tibber) IsCheap() (bool, error) {
 	price, err := t.CurrentPrice()
 	return price <= t.Cheap, err
 }
+
+var _ api.TariffCheapLimit = (*Tibber)(nil)
+
+// CheapLimit implements the api.TariffCheapLimit interface
+func (t *Tibber) CheapLimit() float64 {
+	return t.Cheap
+}
+
+func (t *Tibber) Rates() ([]api.Rate, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	res := make([]api.Rate, 0, len(t.data))
+	for _, pi := range t.data {
+		res = append(res, api.Rate{
+			Start: pi.StartsAt,
+			End:   pi.StartsAt.Add(time.Hour),
+			Price: pi.Total,
+		})
+	}
+
+	return res, nil
+}
@@ -3,18 +3,24 @@ package util
 import (
 	"fmt"
 	"sync"
+	"time"
 )
 
-// Cache is a data store
+// Cache is a versioned data store
 type Cache struct {
 	sync.Mutex
-	val map[string]Param
+	version  uint64
+	val      map[string]Param
+	versions map[string]uint64
 }
 
-// NewCache creates cache
+// NewCache creates cache.
+// Versions start at the current time in microseconds to remain monotonic across restarts.
 func NewCache() *Cache {
 	return &Cache{
-		val: make(map[string]Param),
+		version:  uint64(time.Now().UnixMicro()),
+		val:      make(map[string]Param),
+		versions: make(map[string]uint64),
 	}
 }
 
@@ -77,12 +83,39 @@ func (c *Cache) All() []Param {
 	return copy
 }
 
-// Add entry to cache
-func (c *Cache) Add(key string, param Param) {
+// Add entry to cache and return the new cache version
+func (c *Cache) Add(key string, param Param) uint64 {
 	c.Lock()
 	defer c.Unlock()
 
+	c.version++
 	c.val[key] = param
+	c.versions[key] = c.version
+
+	return c.version
+}
+
+// Version returns the current cache version
+func (c *Cache) Version() uint64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.version
+}
+
+// Since provides a copy of the values added after the given version and the current version.
+// All values are returned if the version is unknown to the cache.
+func (c *Cache) Since(version uint64) ([]Param, uint64) {
+	c.Lock()
+	defer c.Unlock()
+
+	res := make([]Param, 0)
+	for key, val := range c.val {
+		if version > c.version || c.versions[key] > version {
+			res = append(res, val)
+		}
+	}
+
+	return res, c.version
 }
 
 // Get entry from cache
@@ -96,3 +129,18 @@ func (c *Cache) Get(key string) Param {
 
 	return Param{}
 }
+
+// RemoveLoadPoints removes the values of all loadpoints starting at the given index
+func (c *Cache) RemoveLoadPoints(from int) {
+	c.Lock()
+	defer c.Unlock()
+
+	for key, val := range c.val {
+		if val.LoadPoint != nil && *val.LoadPoint >= from {
+			delete(c.val, key)
+			delete(c.versions, key)
+		}
+	}
+
+	c.version++
+}
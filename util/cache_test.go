@@ -0,0 +1,42 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheSince(t *testing.T) {
+	c := NewCache()
+
+	c.Add("a", Param{Key: "a", Val: 1})
+	v := c.Add("b", Param{Key: "b", Val: 2})
+	c.Add("a", Param{Key: "a", Val: 3})
+
+	res, version := c.Since(v)
+	assert.Equal(t, []Param{{Key: "a", Val: 3}}, res)
+	assert.Equal(t, c.Version(), version)
+
+	// nothing new
+	res, _ = c.Since(version)
+	assert.Empty(t, res)
+
+	// unknown future version from previous run returns everything
+	res, _ = c.Since(version + 1)
+	assert.Len(t, res, 2)
+}
+
+func TestCacheRemoveLoadPoints(t *testing.T) {
+	c := NewCache()
+
+	lp := func(id int) *int { return &id }
+
+	c.Add("a", Param{Key: "a", Val: 1})
+	c.Add("lp0", Param{LoadPoint: lp(0), Key: "b", Val: 2})
+	c.Add("lp1", Param{LoadPoint: lp(1), Key: "b", Val: 3})
+
+	c.RemoveLoadPoints(1)
+
+	assert.Len(t, c.All(), 2)
+	assert.Len(t, c.State()["loadpoints"], 1)
+}
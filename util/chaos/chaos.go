@@ -0,0 +1,321 @@
+// Package chaos injects controlled faults into meters, modbus connections, http requests and oauth token sources
+// for robustness testing. Faults are defined by a scenario file and only active if a scenario has been loaded
+// using the --chaos flag:
+//
+//	faults:
+//	- target: modbus
+//	  match: 192.0.2.10:502
+//	  kind: drop
+//	  delay: 5s
+//	  probability: 0.1
+//	- target: meter
+//	  match: pv
+//	  kind: nan
+//	  after: 1m
+//	  duration: 30s
+//	- target: oauth
+//	  kind: expire
+//	  after: 10m
+package chaos
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"gopkg.in/yaml.v3"
+)
+
+// fault targets
+const (
+	Meter  = "meter"  // meter readings, matched by meter name
+	Modbus = "modbus" // modbus connections, matched by address or device
+	HTTP   = "http"   // http requests, matched by host
+	OAuth  = "oauth"  // oauth token sources
+)
+
+// fault kinds
+const (
+	KindDelay  = "delay"  // delay the operation
+	KindError  = "error"  // fail the operation
+	KindDrop   = "drop"   // fail the operation with timeout as if the response got lost
+	KindNaN    = "nan"    // return NaN float values, meter only
+	KindStatus = "status" // respond with http status, http only
+	KindExpire = "expire" // expire the token forcing a refresh, oauth only
+)
+
+// ErrInjected is returned by injected error faults
+var ErrInjected = errors.New("chaos: injected fault")
+
+// Fault is a fault injected into matching operations
+type Fault struct {
+	Target      string        `yaml:"target"`
+	Match       string        `yaml:"match"`       // optional substring of meter name, modbus address or http host
+	Kind        string        `yaml:"kind"`        // fault kind
+	Delay       time.Duration `yaml:"delay"`       // delay for delay and drop faults
+	Status      int           `yaml:"status"`      // http status for status faults
+	Probability float64       `yaml:"probability"` // probability of injecting, defaults to 1
+	After       time.Duration `yaml:"after"`       // fault becomes active after the scenario has been loaded
+	Duration    time.Duration `yaml:"duration"`    // fault remains active for the duration, forever if empty
+}
+
+// Scenario is a set of faults
+type Scenario struct {
+	Faults []Fault `yaml:"faults"`
+
+	log     *util.Logger
+	clock   clock.Clock
+	mu      sync.Mutex
+	rand    *rand.Rand
+	started time.Time
+}
+
+var instance *Scenario
+
+// Load loads the scenario file and enables fault injection
+func Load(file string) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var s Scenario
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	if err := s.init(clock.New()); err != nil {
+		return err
+	}
+
+	instance = &s
+	s.log.WARN.Printf("fault injection enabled: %d faults", len(s.Faults))
+
+	return nil
+}
+
+// Enabled returns true if a scenario has been loaded
+func Enabled() bool {
+	return instance != nil
+}
+
+func (s *Scenario) init(clock clock.Clock) error {
+	for i, f := range s.Faults {
+		if err := f.validate(); err != nil {
+			return fmt.Errorf("fault %d: %w", i+1, err)
+		}
+
+		if f.Probability == 0 {
+			s.Faults[i].Probability = 1
+		}
+	}
+
+	s.log = util.NewLogger("chaos")
+	s.clock = clock
+	s.rand = rand.New(rand.NewSource(clock.Now().UnixNano()))
+	s.started = clock.Now()
+
+	return nil
+}
+
+func (f Fault) validate() error {
+	switch f.Target {
+	case Meter, Modbus, HTTP, OAuth:
+	default:
+		return fmt.Errorf("invalid target: %s", f.Target)
+	}
+
+	switch f.Kind {
+	case KindDelay:
+		if f.Delay <= 0 {
+			return errors.New("missing delay")
+		}
+	case KindError, KindDrop:
+	case KindNaN:
+		if f.Target != Meter {
+			return fmt.Errorf("%s faults only apply to %s", f.Kind, Meter)
+		}
+	case KindStatus:
+		if f.Target != HTTP {
+			return fmt.Errorf("%s faults only apply to %s", f.Kind, HTTP)
+		}
+		if f.Status < 100 {
+			return errors.New("missing status")
+		}
+	case KindExpire:
+		if f.Target != OAuth {
+			return fmt.Errorf("%s faults only apply to %s", f.Kind, OAuth)
+		}
+	default:
+		return fmt.Errorf("invalid kind: %s", f.Kind)
+	}
+
+	if f.Probability < 0 || f.Probability > 1 {
+		return fmt.Errorf("invalid probability: %v", f.Probability)
+	}
+
+	return nil
+}
+
+// faults returns the active faults matching target and name
+func (s *Scenario) faults(target, name string) []Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elapsed := s.clock.Since(s.started)
+
+	var res []Fault
+	for _, f := range s.Faults {
+		if f.Target != target || !strings.Contains(name, f.Match) {
+			continue
+		}
+
+		if elapsed < f.After || (f.Duration > 0 && elapsed >= f.After+f.Duration) {
+			continue
+		}
+
+		if s.rand.Float64() >= f.Probability {
+			continue
+		}
+
+		res = append(res, f)
+	}
+
+	return res
+}
+
+// inject applies delays and returns the first other active fault
+func (s *Scenario) inject(target, name string) *Fault {
+	for _, f := range s.faults(target, name) {
+		f := f
+		s.log.DEBUG.Printf("%s %s: %s", target, name, f.Kind)
+
+		if f.Kind == KindDelay {
+			s.clock.Sleep(f.Delay)
+			continue
+		}
+
+		if f.Kind == KindDrop {
+			s.clock.Sleep(f.Delay)
+		}
+
+		return &f
+	}
+
+	return nil
+}
+
+// err returns the fault's error
+func (f *Fault) err(target, name string) error {
+	if f.Kind == KindDrop {
+		return fmt.Errorf("chaos: dropped %s %s: %w", target, name, os.ErrDeadlineExceeded)
+	}
+	return fmt.Errorf("%w: %s %s", ErrInjected, target, name)
+}
+
+// Float wraps the named meter's float reading
+func Float(name string, g func() (float64, error)) func() (float64, error) {
+	if instance == nil || g == nil {
+		return g
+	}
+
+	return func() (float64, error) {
+		if f := instance.inject(Meter, name); f != nil {
+			if f.Kind == KindNaN {
+				return math.NaN(), nil
+			}
+			return 0, f.err(Meter, name)
+		}
+		return g()
+	}
+}
+
+// Getter wraps the named meter's non-float reading
+func Getter[T any](name string, g func() (T, error)) func() (T, error) {
+	if instance == nil || g == nil {
+		return g
+	}
+
+	return func() (T, error) {
+		if f := instance.inject(Meter, name); f != nil && f.Kind != KindNaN {
+			var zero T
+			return zero, f.err(Meter, name)
+		}
+		return g()
+	}
+}
+
+// ModbusError returns the modbus connection's injected fault. It is called before the request is sent.
+func ModbusError(key string) error {
+	if instance == nil {
+		return nil
+	}
+
+	if f := instance.inject(Modbus, key); f != nil {
+		return f.err(Modbus, key)
+	}
+
+	return nil
+}
+
+// TokenExpired returns true if the oauth token should be considered expired or the injected refresh error
+func TokenExpired() (bool, error) {
+	if instance == nil {
+		return false, nil
+	}
+
+	if f := instance.inject(OAuth, ""); f != nil {
+		if f.Kind == KindExpire {
+			return true, nil
+		}
+		return false, f.err(OAuth, "refresh")
+	}
+
+	return false, nil
+}
+
+type roundTripper struct {
+	base http.RoundTripper
+}
+
+// Transport wraps the http transport
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if instance == nil {
+		return base
+	}
+
+	return &roundTripper{base: base}
+}
+
+func (r *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+
+	if f := instance.inject(HTTP, host); f != nil {
+		if f.Kind != KindStatus {
+			return nil, f.err(HTTP, host)
+		}
+
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+			StatusCode: f.Status,
+			Proto:      req.Proto,
+			ProtoMajor: req.ProtoMajor,
+			ProtoMinor: req.ProtoMinor,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(bytes.NewReader(nil)),
+			Request:    req,
+		}, nil
+	}
+
+	return r.base.RoundTrip(req)
+}
@@ -0,0 +1,129 @@
+package chaos
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scenario(t *testing.T, faults ...Fault) *clock.Mock {
+	t.Helper()
+
+	clock := clock.NewMock()
+	s := &Scenario{Faults: faults}
+	require.NoError(t, s.init(clock))
+
+	instance = s
+	t.Cleanup(func() { instance = nil })
+
+	return clock
+}
+
+func TestValidate(t *testing.T) {
+	for _, f := range []Fault{
+		{Target: "foo", Kind: KindError},
+		{Target: Meter, Kind: "foo"},
+		{Target: Meter, Kind: KindDelay},
+		{Target: Modbus, Kind: KindNaN},
+		{Target: Meter, Kind: KindStatus, Status: 500},
+		{Target: HTTP, Kind: KindStatus},
+		{Target: HTTP, Kind: KindExpire},
+		{Target: Meter, Kind: KindError, Probability: 2},
+	} {
+		assert.Error(t, f.validate(), f)
+	}
+
+	assert.NoError(t, Fault{Target: OAuth, Kind: KindExpire}.validate())
+}
+
+func TestDisabled(t *testing.T) {
+	g := func() (float64, error) { return 1, nil }
+	assert.Equal(t, http.DefaultTransport, Transport(http.DefaultTransport))
+	assert.NoError(t, ModbusError("localhost:502"))
+
+	res, err := Float("foo", g)()
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, res)
+}
+
+func TestMeter(t *testing.T) {
+	clock := scenario(t,
+		Fault{Target: Meter, Match: "pv", Kind: KindNaN, After: time.Minute, Duration: time.Minute},
+		Fault{Target: Meter, Match: "grid", Kind: KindError},
+	)
+
+	g := func() (float64, error) { return 1, nil }
+	pv := Float("pv", g)
+	grid := Getter("grid", g)
+
+	// not yet active
+	res, err := pv()
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, res)
+
+	clock.Add(time.Minute)
+	res, err = pv()
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(res))
+
+	// expired
+	clock.Add(time.Minute)
+	res, err = pv()
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, res)
+
+	_, err = grid()
+	assert.ErrorIs(t, err, ErrInjected)
+}
+
+func TestModbusDrop(t *testing.T) {
+	clock := scenario(t, Fault{Target: Modbus, Match: ":502", Kind: KindDrop, Delay: time.Second})
+
+	done := make(chan error)
+	go func() { done <- ModbusError("localhost:502") }()
+
+	// wait for sleep to be scheduled
+	for {
+		clock.Add(time.Second)
+
+		select {
+		case err := <-done:
+			assert.True(t, errors.Is(err, os.ErrDeadlineExceeded))
+			assert.NoError(t, ModbusError("localhost:1502"))
+			return
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	scenario(t, Fault{Target: HTTP, Match: "127.0.0.1", Kind: KindStatus, Status: http.StatusServiceUnavailable})
+
+	client := &http.Client{Transport: Transport(http.DefaultTransport)}
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestTokenExpired(t *testing.T) {
+	scenario(t, Fault{Target: OAuth, Kind: KindExpire})
+
+	expired, err := TokenExpired()
+	assert.NoError(t, err)
+	assert.True(t, expired)
+}
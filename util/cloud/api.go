@@ -1,6 +1,11 @@
 package cloud
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+	"google.golang.org/grpc/status"
+)
 
 var (
 	// ErrNotAuthorized indicates request token is not authorized
@@ -9,3 +14,23 @@ var (
 	// ErrVehicleNotAvailable indicates vehicle not available, client should retry to prepare vehicle
 	ErrVehicleNotAvailable = errors.New("vehicle not available")
 )
+
+// Error maps grpc status errors to their sentinel errors
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	for _, e := range []error{ErrNotAuthorized, ErrVehicleNotAvailable, api.ErrMustRetry} {
+		if s.Message() == e.Error() {
+			return e
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,296 @@
+// Package config edits the evcc.yaml configuration file as yaml node tree.
+// Untouched sections, user comments and key ordering are preserved when writing the file back:
+//
+//	f, err := config.Load("evcc.yaml")
+//	...
+//	err = f.Set("site.title", "Home")
+//	err = f.Save("evcc.yaml")
+//
+// Paths are dot-separated mapping keys and sequence indices, e.g. `loadpoints.0.title`.
+// Blank lines are retained between top-level keys only, comment indentation follows the yaml encoder.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is a parsed configuration file
+type File struct {
+	doc    yaml.Node
+	spaced map[*yaml.Node]bool // top-level keys preceded by a blank line
+}
+
+// New creates an empty configuration file
+func New() *File {
+	return &File{doc: yaml.Node{
+		Kind:    yaml.DocumentNode,
+		Content: []*yaml.Node{{Kind: yaml.MappingNode}},
+	}}
+}
+
+// Parse parses the configuration
+func Parse(b []byte) (*File, error) {
+	var res File
+	if err := yaml.Unmarshal(b, &res.doc); err != nil {
+		return nil, err
+	}
+
+	// empty file
+	if len(res.doc.Content) == 0 {
+		return New(), nil
+	}
+
+	if res.doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("invalid configuration file")
+	}
+
+	res.spaced = spacedKeys(res.Root(), strings.Split(string(b), "\n"))
+
+	return &res, nil
+}
+
+// Load reads and parses the configuration file
+func Load(filename string) (*File, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(b)
+}
+
+// Root returns the top-level mapping
+func (f *File) Root() *yaml.Node {
+	return f.doc.Content[0]
+}
+
+// split splits the path into its segments
+func split(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// child returns the mapping value or sequence element of the segment or nil
+func child(n *yaml.Node, segment string) *yaml.Node {
+	if n == nil {
+		return nil
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		return MapValue(n, segment)
+	case yaml.SequenceNode:
+		if i, err := strconv.Atoi(segment); err == nil && i >= 0 && i < len(n.Content) {
+			return n.Content[i]
+		}
+	}
+
+	return nil
+}
+
+// Get returns the node at the path or nil
+func (f *File) Get(path string) *yaml.Node {
+	n := f.Root()
+	for _, segment := range split(path) {
+		if n = child(n, segment); n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// Decode decodes the node at the path into the value. Missing paths leave the value untouched.
+func (f *File) Decode(path string, v interface{}) error {
+	n := f.Get(path)
+	if n == nil {
+		return nil
+	}
+	return n.Decode(v)
+}
+
+// Set sets the value at the path, creating missing mappings.
+// Values can be given as *yaml.Node or any value encodable as yaml.
+// Comments of a replaced node are kept unless the new node has its own.
+func (f *File) Set(path string, value interface{}) error {
+	segments := split(path)
+	if len(segments) == 0 {
+		return errors.New("empty path")
+	}
+
+	val, ok := value.(*yaml.Node)
+	if !ok {
+		val = new(yaml.Node)
+		if err := val.Encode(value); err != nil {
+			return err
+		}
+	}
+
+	n := f.Root()
+	for i, segment := range segments[:len(segments)-1] {
+		next := child(n, segment)
+		if next == nil {
+			if n.Kind != yaml.MappingNode {
+				return fmt.Errorf("%s: not found", strings.Join(segments[:i+1], "."))
+			}
+
+			next = &yaml.Node{Kind: yaml.MappingNode}
+			SetMapValue(n, segment, next)
+		}
+		n = next
+	}
+
+	last := segments[len(segments)-1]
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		if prev := MapValue(n, last); prev != nil {
+			keepComments(prev, val)
+		}
+		SetMapValue(n, last, val)
+
+	case yaml.SequenceNode:
+		i, err := strconv.Atoi(last)
+		if err != nil || i < 0 || i > len(n.Content) {
+			return fmt.Errorf("%s: invalid index", path)
+		}
+
+		if i == len(n.Content) {
+			n.Content = append(n.Content, val)
+		} else {
+			keepComments(n.Content[i], val)
+			n.Content[i] = val
+		}
+
+	default:
+		return fmt.Errorf("%s: not a mapping or sequence", strings.Join(segments[:len(segments)-1], "."))
+	}
+
+	return nil
+}
+
+// keepComments copies the comments of the replaced node unless the new node has its own
+func keepComments(prev, val *yaml.Node) {
+	if val.HeadComment == "" && val.LineComment == "" && val.FootComment == "" {
+		val.HeadComment, val.LineComment, val.FootComment = prev.HeadComment, prev.LineComment, prev.FootComment
+	}
+}
+
+// Delete removes the value at the path. Missing paths are ignored.
+func (f *File) Delete(path string) {
+	segments := split(path)
+	if len(segments) == 0 {
+		return
+	}
+
+	n := f.Root()
+	for _, segment := range segments[:len(segments)-1] {
+		if n = child(n, segment); n == nil {
+			return
+		}
+	}
+
+	last := segments[len(segments)-1]
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		DeleteMapValue(n, last)
+	case yaml.SequenceNode:
+		if i, err := strconv.Atoi(last); err == nil && i >= 0 && i < len(n.Content) {
+			n.Content = append(n.Content[:i], n.Content[i+1:]...)
+		}
+	}
+}
+
+// commentStart returns the first line of the key including its head comment
+func commentStart(key *yaml.Node) int {
+	if key.HeadComment == "" {
+		return key.Line
+	}
+	return key.Line - strings.Count(key.HeadComment, "\n") - 1
+}
+
+// spacedKeys returns the top-level keys preceded by a blank line in the source.
+// Blank lines are not retained by the yaml parser but separate the sections of most configuration files.
+func spacedKeys(root *yaml.Node, lines []string) map[*yaml.Node]bool {
+	res := make(map[*yaml.Node]bool)
+
+	for i := 2; i < len(root.Content); i += 2 {
+		key := root.Content[i]
+		if prev := commentStart(key) - 2; prev >= 0 && prev < len(lines) && strings.TrimSpace(lines[prev]) == "" {
+			res[key] = true
+		}
+	}
+
+	return res
+}
+
+// Bytes renders the configuration file
+func (f *File) Bytes() ([]byte, error) {
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(&f.doc); err != nil {
+		return nil, err
+	}
+
+	if len(f.spaced) == 0 {
+		return out.Bytes(), nil
+	}
+
+	// restore blank lines between top-level keys by locating the keys in order
+	lines := strings.SplitAfter(out.String(), "\n")
+	blank := make(map[int]bool)
+
+	var pos int
+	for i := 0; i < len(f.Root().Content); i += 2 {
+		key := f.Root().Content[i]
+
+		first := key.Value + ":"
+		if key.HeadComment != "" {
+			first = strings.SplitN(key.HeadComment, "\n", 2)[0]
+		}
+
+		for ; pos < len(lines); pos++ {
+			if strings.HasPrefix(lines[pos], first) {
+				blank[pos] = f.spaced[key] && pos > 0 && strings.TrimSpace(lines[pos-1]) != ""
+				break
+			}
+		}
+	}
+
+	var res strings.Builder
+	for i, line := range lines {
+		if blank[i] {
+			res.WriteString("\n")
+		}
+		res.WriteString(line)
+	}
+
+	return []byte(res.String()), nil
+}
+
+// Save writes the configuration file keeping a backup of the previous version
+func (f *File) Save(filename string) error {
+	b, err := f.Bytes()
+	if err != nil {
+		return err
+	}
+
+	if b, err := os.ReadFile(filename); err == nil {
+		if err := os.WriteFile(filename+".bak", b, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(filename, b, 0o644)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const conf = `# evcc configuration
+site:
+  title: Home # shown in the ui
+  meters:
+    grid: grid
+
+# loadpoints
+loadpoints:
+  - title: Garage
+    charger: wallbox
+`
+
+func TestRoundTrip(t *testing.T) {
+	f, err := Parse([]byte(conf))
+	require.NoError(t, err)
+
+	b, err := f.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, conf, string(b))
+}
+
+func TestModify(t *testing.T) {
+	f, err := Parse([]byte(conf))
+	require.NoError(t, err)
+
+	var title string
+	require.NoError(t, f.Decode("loadpoints.0.title", &title))
+	assert.Equal(t, "Garage", title)
+
+	require.NoError(t, f.Set("site.title", "My Home"))
+	require.NoError(t, f.Set("site.meters.pv", []string{"pv1", "pv2"}))
+	require.NoError(t, f.Set("loadpoints.1", map[string]string{"title": "Carport"}))
+	require.NoError(t, f.Set("interval", "30s"))
+	f.Delete("loadpoints.0.charger")
+
+	assert.Error(t, f.Set("loadpoints.3.title", "Parking"))
+	assert.Nil(t, f.Get("loadpoints.3"))
+
+	b, err := f.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, `# evcc configuration
+site:
+  title: My Home # shown in the ui
+  meters:
+    grid: grid
+    pv:
+      - pv1
+      - pv2
+
+# loadpoints
+loadpoints:
+  - title: Garage
+  - title: Carport
+interval: 30s
+`, string(b))
+}
+
+func TestEmpty(t *testing.T) {
+	f, err := Parse(nil)
+	require.NoError(t, err)
+
+	require.NoError(t, f.Set("site.title", "Home"))
+
+	b, err := f.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, "site:\n  title: Home\n", string(b))
+
+	_, err = Parse([]byte("- foo"))
+	assert.Error(t, err)
+}
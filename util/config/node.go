@@ -0,0 +1,63 @@
+package config
+
+import "gopkg.in/yaml.v3"
+
+// MapValue returns the value node of the mapping's key or nil
+func MapValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i < len(m.Content)-1; i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// SetMapValue sets or adds the mapping's key
+func SetMapValue(m *yaml.Node, key string, val *yaml.Node) {
+	for i := 0; i < len(m.Content)-1; i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = val
+			return
+		}
+	}
+
+	m.Content = append(m.Content, Scalar(key), val)
+}
+
+// DeleteMapValue removes the mapping's key
+func DeleteMapValue(m *yaml.Node, key string) {
+	for i := 0; i < len(m.Content)-1; i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// Scalar creates a string node
+func Scalar(val string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: val}
+}
+
+// Scalars returns the values of a scalar or sequence node
+func Scalars(n *yaml.Node) []string {
+	if n == nil {
+		return nil
+	}
+
+	if n.Kind == yaml.ScalarNode {
+		return []string{n.Value}
+	}
+
+	var res []string
+	for _, c := range n.Content {
+		res = append(res, c.Value)
+	}
+
+	return res
+}
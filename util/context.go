@@ -0,0 +1,62 @@
+package util
+
+import (
+	"context"
+	"sync"
+)
+
+// Exclusive serializes calls of functions not supporting cancellation.
+// The zero value is ready to use.
+type Exclusive struct {
+	once sync.Once
+	sem  chan struct{}
+}
+
+// acquire waits until no previous call is running or ctx is done
+func (e *Exclusive) acquire(ctx context.Context) error {
+	e.once.Do(func() {
+		e.sem = make(chan struct{}, 1)
+	})
+
+	select {
+	case e.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *Exclusive) release() {
+	<-e.sem
+}
+
+// WithContext executes fn and returns early with the context's error once ctx is done.
+// Functions not supporting cancellation keep running in the background and their result is discarded.
+// No new call is started using the same Exclusive until the previous call has returned, since
+// abandoned calls would otherwise access the device concurrently.
+func WithContext[T any](ctx context.Context, ex *Exclusive, fn func() (T, error)) (T, error) {
+	type result struct {
+		val T
+		err error
+	}
+
+	var zero T
+	if err := ex.acquire(ctx); err != nil {
+		return zero, err
+	}
+
+	resC := make(chan result, 1)
+
+	go func() {
+		defer ex.release()
+		val, err := fn()
+		resC <- result{val, err}
+	}()
+
+	select {
+	case res := <-resC:
+		return res.val, res.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithContext(t *testing.T) {
+	ex := new(Exclusive)
+
+	res, err := WithContext(context.Background(), ex, func() (int, error) {
+		return 1, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	blockC := make(chan struct{})
+
+	_, err = WithContext(ctx, ex, func() (int, error) {
+		<-blockC
+		return 2, nil
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// abandoned call still running, no new call must be started
+	var calls int32
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel2()
+
+	_, err = WithContext(ctx2, ex, func() (int, error) {
+		atomic.AddInt32(&calls, 1)
+		return 3, nil
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	// previous call returned
+	close(blockC)
+
+	res, err = WithContext(context.Background(), ex, func() (int, error) {
+		atomic.AddInt32(&calls, 1)
+		return 4, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, res)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
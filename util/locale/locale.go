@@ -2,12 +2,17 @@ package locale
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/BurntSushi/toml"
 	"github.com/cloudfoundry/jibber_jabber"
 	assets "github.com/evcc-io/evcc/assets/i18n"
 	"github.com/evcc-io/evcc/util/locale/internal"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/exp/slices"
 	"golang.org/x/text/language"
 )
 
@@ -19,6 +24,8 @@ var (
 	Bundle    *i18n.Bundle
 	Language  string
 	Localizer *i18n.Localizer
+
+	mu sync.RWMutex
 )
 
 func Init() error {
@@ -36,18 +43,96 @@ func Init() error {
 		}
 	}
 
-	Language, err := jibber_jabber.DetectLanguage()
+	lang, err := jibber_jabber.DetectLanguage()
 	if err != nil {
-		Language = "de"
+		lang = "de"
+	}
+
+	setLanguage(lang)
+
+	return nil
+}
+
+// LoadDir loads additional or overriding translation files (*.toml, *.json) from the given directory.
+// Messages of already loaded languages are replaced.
+func LoadDir(dir string) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("loading locales failed: %w", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, f := range files {
+		if ext := filepath.Ext(f.Name()); f.IsDir() || ext != ".toml" && ext != ".json" {
+			continue
+		}
+
+		if _, err := Bundle.LoadMessageFile(filepath.Join(dir, f.Name())); err != nil {
+			return fmt.Errorf("loading locales failed: %w", err)
+		}
 	}
 
+	// refresh localizer to pick up new messages
 	Localizer = i18n.NewLocalizer(Bundle, Language)
 
 	return nil
 }
 
+// Languages returns the list of available languages
+func Languages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var res []string
+	for _, tag := range Bundle.LanguageTags() {
+		res = append(res, tag.String())
+	}
+
+	return res
+}
+
+// SetLanguage changes the default language at runtime
+func SetLanguage(lang string) error {
+	lang = strings.ToLower(lang)
+	if !slices.Contains(Languages(), lang) {
+		return fmt.Errorf("invalid language: %s", lang)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	setLanguage(lang)
+
+	return nil
+}
+
+func setLanguage(lang string) {
+	Language = lang
+	Localizer = i18n.NewLocalizer(Bundle, Language)
+}
+
+// CurrentLanguage returns the default language
+func CurrentLanguage() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return Language
+}
+
+// NewLocalizer creates a localizer for the given languages falling back to the default language
+func NewLocalizer(langs ...string) *i18n.Localizer {
+	mu.RLock()
+	defer mu.RUnlock()
+	return i18n.NewLocalizer(Bundle, append(langs, Language)...)
+}
+
 func Localize(lc *Config) string {
-	msg, _, err := Localizer.LocalizeWithTag(lc)
+	mu.RLock()
+	localizer := Localizer
+	mu.RUnlock()
+
+	msg, _, err := localizer.LocalizeWithTag(lc)
 	if err != nil {
 		msg = lc.MessageID
 	}
@@ -4,6 +4,7 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -14,13 +15,17 @@ import (
 
 var id string
 
-// CustomID sets the machine id to a custom value
+// CustomID sets the machine id to a custom value. Setting the same value again, e.g. on reload, is allowed.
 func CustomID(cid string) error {
+	cid = strings.TrimSpace(cid)
+
 	if id != "" {
-		panic("machine id already generated")
+		if id == cid {
+			return nil
+		}
+		return errors.New("machine id already generated")
 	}
 
-	cid = strings.TrimSpace(cid)
 	if l := len(cid); l != 32 && l != 64 {
 		return fmt.Errorf("expected 32 or 64 characters machine id, got %d", l)
 	}
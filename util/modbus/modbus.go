@@ -1,6 +1,8 @@
 package modbus
 
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math"
@@ -9,7 +11,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/chaos"
 	"github.com/grid-x/modbus"
 	"github.com/volkszaehler/mbmd/encoding"
 	"github.com/volkszaehler/mbmd/meters"
@@ -57,8 +61,11 @@ func (s *Settings) String() string {
 type Connection struct {
 	slaveID uint8
 	mu      sync.Mutex
+	key     string
 	conn    meters.Connection
 	delay   time.Duration
+	retries int // write verification retries
+	closed  bool
 }
 
 func (mb *Connection) prepare(slaveID uint8) {
@@ -68,18 +75,81 @@ func (mb *Connection) prepare(slaveID uint8) {
 	}
 }
 
-func (mb *Connection) handle(res []byte, err error) ([]byte, error) {
+func (mb *Connection) handle(op func() ([]byte, error)) ([]byte, error) {
+	err := chaos.ModbusError(mb.key)
+
+	var res []byte
+	if err == nil {
+		res, err = op()
+	}
+
 	if err != nil {
 		mb.conn.Close()
 	}
 	return res, err
 }
 
+// ErrWriteIgnored indicates that the device did not apply a written value
+var ErrWriteIgnored = api.ErrWriteIgnored
+
+// verifyDelay is the delay before the first write retry, increasing with each retry
+var verifyDelay = 100 * time.Millisecond
+
+// writeVerify executes the write and reads back the written holding registers if verification is enabled
+func (mb *Connection) writeVerify(slaveID uint8, address, quantity uint16, write func() ([]byte, error), reconnect, verify bool) ([]byte, []byte, error) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	if reconnect {
+		mb.conn.Close()
+	}
+
+	mb.prepare(slaveID)
+	res, err := mb.handle(write)
+	if err != nil || !verify {
+		return res, nil, err
+	}
+
+	b, err := mb.handle(func() ([]byte, error) {
+		return mb.conn.ModbusClient().ReadHoldingRegisters(address, quantity)
+	})
+	if err != nil {
+		err = fmt.Errorf("verify: %w", err)
+	}
+
+	return res, b, err
+}
+
+// verified executes the write and, if verification is enabled, reads back the written holding registers.
+// Ignored writes are retried with increasing delay, re-establishing the connection after the first retry.
+// The connection is not locked while waiting for the retry.
+func (mb *Connection) verified(slaveID uint8, address, quantity uint16, value []byte, write func() ([]byte, error)) ([]byte, error) {
+	retries := mb.verifyRetries()
+
+	for retry := 0; ; retry++ {
+		res, b, err := mb.writeVerify(slaveID, address, quantity, write, retry > 1, retries > 0)
+		if err != nil || retries == 0 || bytes.Equal(b, value) {
+			return res, err
+		}
+
+		if retry == retries {
+			return res, fmt.Errorf("%w: register %d: wrote %0x, read %0x after %d retries", ErrWriteIgnored, address, value, b, retry)
+		}
+
+		time.Sleep(time.Duration(retry+1) * verifyDelay)
+	}
+}
+
 // Delay sets delay so use between subsequent modbus operations
 func (mb *Connection) Delay(delay time.Duration) {
 	mb.delay = delay
 }
 
+// Verify enables reading back written holding registers, retrying writes the device did not apply
+func (mb *Connection) Verify(retries int) {
+	mb.retries = retries
+}
+
 // ConnectDelay sets the initial delay after connecting before starting communication
 func (mb *Connection) ConnectDelay(delay time.Duration) {
 	mb.conn.ConnectDelay(delay)
@@ -100,7 +170,9 @@ func (mb *Connection) ReadCoilsWithSlave(slaveID uint8, address, quantity uint16
 	mb.mu.Lock()
 	defer mb.mu.Unlock()
 	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().ReadCoils(address, quantity))
+	return mb.handle(func() ([]byte, error) {
+		return mb.conn.ModbusClient().ReadCoils(address, quantity)
+	})
 }
 
 // WriteSingleCoil wraps the underlying implementation
@@ -108,7 +180,9 @@ func (mb *Connection) WriteSingleCoilWithSlave(slaveID uint8, address, value uin
 	mb.mu.Lock()
 	defer mb.mu.Unlock()
 	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().WriteSingleCoil(address, value))
+	return mb.handle(func() ([]byte, error) {
+		return mb.conn.ModbusClient().WriteSingleCoil(address, value)
+	})
 }
 
 // ReadInputRegisters wraps the underlying implementation
@@ -116,7 +190,9 @@ func (mb *Connection) ReadInputRegistersWithSlave(slaveID uint8, address, quanti
 	mb.mu.Lock()
 	defer mb.mu.Unlock()
 	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().ReadInputRegisters(address, quantity))
+	return mb.handle(func() ([]byte, error) {
+		return mb.conn.ModbusClient().ReadInputRegisters(address, quantity)
+	})
 }
 
 // ReadHoldingRegisters wraps the underlying implementation
@@ -124,23 +200,25 @@ func (mb *Connection) ReadHoldingRegistersWithSlave(slaveID uint8, address, quan
 	mb.mu.Lock()
 	defer mb.mu.Unlock()
 	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().ReadHoldingRegisters(address, quantity))
+	return mb.handle(func() ([]byte, error) {
+		return mb.conn.ModbusClient().ReadHoldingRegisters(address, quantity)
+	})
 }
 
 // WriteSingleRegister wraps the underlying implementation
 func (mb *Connection) WriteSingleRegisterWithSlave(slaveID uint8, address, value uint16) ([]byte, error) {
-	mb.mu.Lock()
-	defer mb.mu.Unlock()
-	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().WriteSingleRegister(address, value))
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, value)
+	return mb.verified(slaveID, address, 1, b, func() ([]byte, error) {
+		return mb.conn.ModbusClient().WriteSingleRegister(address, value)
+	})
 }
 
 // WriteMultipleRegisters wraps the underlying implementation
 func (mb *Connection) WriteMultipleRegistersWithSlave(slaveID uint8, address, quantity uint16, value []byte) ([]byte, error) {
-	mb.mu.Lock()
-	defer mb.mu.Unlock()
-	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().WriteMultipleRegisters(address, quantity, value))
+	return mb.verified(slaveID, address, quantity, value, func() ([]byte, error) {
+		return mb.conn.ModbusClient().WriteMultipleRegisters(address, quantity, value)
+	})
 }
 
 // ReadDiscreteInputs wraps the underlying implementation
@@ -148,7 +226,9 @@ func (mb *Connection) ReadDiscreteInputsWithSlave(slaveID uint8, address, quanti
 	mb.mu.Lock()
 	defer mb.mu.Unlock()
 	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().ReadDiscreteInputs(address, quantity))
+	return mb.handle(func() ([]byte, error) {
+		return mb.conn.ModbusClient().ReadDiscreteInputs(address, quantity)
+	})
 }
 
 // WriteMultipleCoils wraps the underlying implementation
@@ -156,7 +236,9 @@ func (mb *Connection) WriteMultipleCoilsWithSlave(slaveID uint8, address, quanti
 	mb.mu.Lock()
 	defer mb.mu.Unlock()
 	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().WriteMultipleCoils(address, quantity, value))
+	return mb.handle(func() ([]byte, error) {
+		return mb.conn.ModbusClient().WriteMultipleCoils(address, quantity, value)
+	})
 }
 
 // ReadWriteMultipleRegisters wraps the underlying implementation
@@ -164,7 +246,9 @@ func (mb *Connection) ReadWriteMultipleRegistersWithSlave(slaveID uint8, readAdd
 	mb.mu.Lock()
 	defer mb.mu.Unlock()
 	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().ReadWriteMultipleRegisters(readAddress, readQuantity, writeAddress, writeQuantity, value))
+	return mb.handle(func() ([]byte, error) {
+		return mb.conn.ModbusClient().ReadWriteMultipleRegisters(readAddress, readQuantity, writeAddress, writeQuantity, value)
+	})
 }
 
 // MaskWriteRegister wraps the underlying implementation
@@ -172,7 +256,9 @@ func (mb *Connection) MaskWriteRegisterWithSlave(slaveID uint8, address, andMask
 	mb.mu.Lock()
 	defer mb.mu.Unlock()
 	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().MaskWriteRegister(address, andMask, orMask))
+	return mb.handle(func() ([]byte, error) {
+		return mb.conn.ModbusClient().MaskWriteRegister(address, andMask, orMask)
+	})
 }
 
 // ReadFIFOQueue wraps the underlying implementation
@@ -180,7 +266,9 @@ func (mb *Connection) ReadFIFOQueueWithSlave(slaveID uint8, address uint16) (res
 	mb.mu.Lock()
 	defer mb.mu.Unlock()
 	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().ReadFIFOQueue(address))
+	return mb.handle(func() ([]byte, error) {
+		return mb.conn.ModbusClient().ReadFIFOQueue(address)
+	})
 }
 
 func (mb *Connection) ReadCoils(address, quantity uint16) ([]byte, error) {
@@ -227,8 +315,15 @@ func (mb *Connection) ReadFIFOQueue(address uint16) (results []byte, err error)
 	return mb.ReadFIFOQueueWithSlave(mb.slaveID, address)
 }
 
+// sharedConnection is a physical connection shared by all devices on the same bus
+type sharedConnection struct {
+	meters.Connection
+	refs    int
+	retries int // write verification retries of all devices on the bus
+}
+
 var (
-	connections = make(map[string]meters.Connection)
+	connections = make(map[string]*sharedConnection)
 	mu          sync.Mutex
 )
 
@@ -237,14 +332,74 @@ func registeredConnection(key string, newConn meters.Connection) meters.Connecti
 	defer mu.Unlock()
 
 	if conn, ok := connections[key]; ok {
-		return conn
+		conn.refs++
+		return conn.Connection
 	}
 
-	connections[key] = newConn
+	connections[key] = &sharedConnection{Connection: newConn, refs: 1}
 
 	return newConn
 }
 
+// connectionKey returns the key of the physical connection
+func connectionKey(uri, device string) string {
+	if device != "" {
+		return device
+	}
+	return util.DefaultPort(uri, 502)
+}
+
+// VerifyWrites enables write verification for all devices using the physical connection,
+// e.g. for devices creating their connection internally
+func VerifyWrites(uri, device string, retries int) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if conn, ok := connections[connectionKey(uri, device)]; ok {
+		conn.retries = retries
+	}
+}
+
+// verifyRetries returns the write verification retries of the device or its physical connection
+func (mb *Connection) verifyRetries() int {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if conn, ok := connections[mb.key]; ok && conn.retries > mb.retries {
+		return conn.retries
+	}
+	return mb.retries
+}
+
+// releaseConnection closes the physical connection once it is no longer used by any device
+func releaseConnection(key string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	conn, ok := connections[key]
+	if !ok {
+		return
+	}
+
+	if conn.refs--; conn.refs == 0 {
+		conn.Close()
+		delete(connections, key)
+	}
+}
+
+// Close releases the device's use of the physical connection
+func (mb *Connection) Close() error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	if !mb.closed {
+		mb.closed = true
+		releaseConnection(mb.key)
+	}
+
+	return nil
+}
+
 // ProtocolFromRTU identifies the wire format from the RTU setting
 func ProtocolFromRTU(rtu *bool) Protocol {
 	if rtu != nil && *rtu {
@@ -256,6 +411,7 @@ func ProtocolFromRTU(rtu *bool) Protocol {
 // NewConnection creates physical modbus device from config
 func NewConnection(uri, device, comset string, baudrate int, proto Protocol, slaveID uint8) (*Connection, error) {
 	var conn meters.Connection
+	var key string
 
 	if device != "" && uri != "" {
 		return nil, errors.New("invalid modbus configuration: can only have either uri or device")
@@ -274,6 +430,7 @@ func NewConnection(uri, device, comset string, baudrate int, proto Protocol, sla
 			return nil, errors.New("invalid modbus configuration: need baudrate and comset")
 		}
 
+		key = device
 		if proto == Ascii {
 			conn = registeredConnection(device, meters.NewASCII(device, baudrate, comset))
 		} else {
@@ -283,6 +440,7 @@ func NewConnection(uri, device, comset string, baudrate int, proto Protocol, sla
 
 	if uri != "" {
 		uri = util.DefaultPort(uri, 502)
+		key = uri
 
 		switch proto {
 		case Rtu:
@@ -300,6 +458,7 @@ func NewConnection(uri, device, comset string, baudrate int, proto Protocol, sla
 
 	slaveConn := &Connection{
 		slaveID: slaveID,
+		key:     key,
 		conn:    conn,
 	}
 
@@ -1,6 +1,14 @@
 package modbus
 
-import "testing"
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andig/mbserver"
+)
 
 func TestParsePoint(t *testing.T) {
 	tc := []struct {
@@ -28,3 +36,117 @@ func TestParsePoint(t *testing.T) {
 		}
 	}
 }
+
+func TestConnectionRelease(t *testing.T) {
+	const uri = "127.0.0.1:1502"
+
+	refs := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if conn, ok := connections[uri]; ok {
+			return conn.refs
+		}
+		return 0
+	}
+
+	c1, err := NewConnection(uri, "", "", 0, Tcp, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := NewConnection(uri, "", "", 0, Tcp, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r := refs(); r != 2 {
+		t.Errorf("expected 2 refs, got %d", r)
+	}
+
+	// repeated close must release only once
+	_ = c1.Close()
+	_ = c1.Close()
+
+	if r := refs(); r != 1 {
+		t.Errorf("expected 1 ref, got %d", r)
+	}
+
+	_ = c2.Close()
+
+	if r := refs(); r != 0 {
+		t.Errorf("expected connection to be released, got %d refs", r)
+	}
+}
+
+// ignoringHandler ignores a number of holding register writes
+type ignoringHandler struct {
+	mbserver.DummyHandler
+	mu      sync.Mutex
+	ignore  int
+	writes  int
+	holding map[uint16]uint16
+}
+
+func (h *ignoringHandler) HandleHoldingRegisters(req *mbserver.HoldingRegistersRequest) ([]uint16, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if req.IsWrite {
+		if h.writes++; h.writes > h.ignore {
+			for i, v := range req.Args {
+				h.holding[req.Addr+uint16(i)] = v
+			}
+		}
+		return nil, nil
+	}
+
+	res := make([]uint16, req.Quantity)
+	for i := range res {
+		res[i] = h.holding[req.Addr+uint16(i)]
+	}
+
+	return res, nil
+}
+
+func TestWriteVerification(t *testing.T) {
+	verifyDelay = time.Millisecond
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &ignoringHandler{holding: make(map[uint16]uint16)}
+
+	srv, _ := mbserver.New(h)
+	if err := srv.Start(l); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = srv.Stop() }()
+
+	conn, err := NewConnection(l.Addr().String(), "", "", 0, Tcp, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.Verify(2)
+
+	// ignored writes are retried
+	h.ignore = 2
+	if _, err := conn.WriteSingleRegister(1, 16); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if h.writes != 3 || h.holding[1] != 16 {
+		t.Errorf("expected value written after 3 attempts, got %d attempts and %d", h.writes, h.holding[1])
+	}
+
+	// retries exhausted
+	h.ignore, h.writes = 10, 0
+	if _, err := conn.WriteMultipleRegisters(1, 1, []byte{0, 32}); !errors.Is(err, ErrWriteIgnored) {
+		t.Errorf("expected ErrWriteIgnored, got %v", err)
+	}
+	if h.writes != 3 {
+		t.Errorf("expected 3 attempts, got %d", h.writes)
+	}
+}
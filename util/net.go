@@ -3,6 +3,7 @@ package util
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -70,3 +71,22 @@ func LocalIPs() (ips []net.IPNet) {
 
 	return ips
 }
+
+// ForwardedURI returns the external base uri of a request behind a reverse proxy
+// using X-Forwarded-Proto/Host/Prefix headers, or the fallback uri if not proxied.
+// Forwarded headers of untrusted clients are removed by the server's proxy handler.
+func ForwardedURI(r *http.Request, fallback string) string {
+	host := r.Header.Get("X-Forwarded-Host")
+	if host == "" {
+		return fallback
+	}
+
+	scheme := r.Header.Get("X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	prefix := strings.TrimSuffix(r.Header.Get("X-Forwarded-Prefix"), "/")
+
+	return fmt.Sprintf("%s://%s%s", scheme, host, prefix)
+}
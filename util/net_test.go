@@ -1,6 +1,8 @@
 package util
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -46,3 +48,20 @@ func TestDefaultSchemeWithEmptyUri(t *testing.T) {
 		t.Errorf("expected %s, got %s", expect, uri)
 	}
 }
+
+func TestForwardedURI(t *testing.T) {
+	fallback := "http://evcc.local:7070"
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if uri := ForwardedURI(r, fallback); uri != fallback {
+		t.Errorf("expected %s, got %s", fallback, uri)
+	}
+
+	r.Header.Set("X-Forwarded-Host", "example.com")
+	r.Header.Set("X-Forwarded-Proto", "https")
+	r.Header.Set("X-Forwarded-Prefix", "/evcc/")
+
+	if uri, expect := ForwardedURI(r, fallback), "https://example.com/evcc"; uri != expect {
+		t.Errorf("expected %s, got %s", expect, uri)
+	}
+}
@@ -0,0 +1,171 @@
+package nfc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// PN532 frame identifiers and commands, see NXP PN532 user manual
+const (
+	hostToPN532 = 0xD4
+	pn532ToHost = 0xD5
+
+	cmdSAMConfiguration      = 0x14
+	cmdRFConfiguration       = 0x32
+	cmdInListPassiveTarget   = 0x4A
+	cfgItemMaxRetries        = 0x05
+	brTy106kbpsTypeA         = 0x00
+	samModeNormal            = 0x01
+	samTimeout               = 0x14 // 1s
+	passiveActivationRetries = 0x10
+)
+
+var (
+	wakeup = []byte{0x55, 0x55, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	ack    = []byte{0x00, 0x00, 0xFF, 0x00, 0xFF, 0x00}
+)
+
+// PN532 is a PN532 NFC controller connected via serial interface
+type PN532 struct {
+	w io.Writer
+	r *bufio.Reader
+}
+
+// NewPN532 creates PN532 controller on the given connection
+func NewPN532(conn io.ReadWriter) *PN532 {
+	return &PN532{
+		w: conn,
+		r: bufio.NewReader(conn),
+	}
+}
+
+// frame encodes a normal information frame
+func frame(cmd byte, data ...byte) []byte {
+	payload := append([]byte{hostToPN532, cmd}, data...)
+
+	res := []byte{0x00, 0x00, 0xFF, byte(len(payload)), byte(-len(payload))}
+	res = append(res, payload...)
+
+	var sum byte
+	for _, b := range payload {
+		sum += b
+	}
+
+	return append(res, -sum, 0x00)
+}
+
+// readFrame reads the next frame payload, returning nil for ack frames
+func (d *PN532) readFrame() ([]byte, error) {
+	// find start code
+	var prev byte = 0xFF
+	for {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		if prev == 0x00 && b == 0xFF {
+			break
+		}
+		prev = b
+	}
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(d.r, header); err != nil {
+		return nil, err
+	}
+
+	length, lcs := header[0], header[1]
+
+	// ack frame
+	if length == 0 && lcs == 0xFF {
+		_, err := d.r.ReadByte()
+		return nil, err
+	}
+
+	if length+lcs != 0 {
+		return nil, errors.New("invalid length checksum")
+	}
+
+	// payload, data checksum and postamble
+	b := make([]byte, int(length)+2)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return nil, err
+	}
+
+	payload := b[:length]
+
+	sum := b[length]
+	for _, b := range payload {
+		sum += b
+	}
+	if sum != 0 {
+		return nil, errors.New("invalid data checksum")
+	}
+
+	return payload, nil
+}
+
+// command executes the command and returns the response data
+func (d *PN532) command(cmd byte, data ...byte) ([]byte, error) {
+	if _, err := d.w.Write(frame(cmd, data...)); err != nil {
+		return nil, err
+	}
+
+	if res, err := d.readFrame(); err != nil {
+		return nil, fmt.Errorf("ack: %w", err)
+	} else if res != nil {
+		return nil, errors.New("missing ack")
+	}
+
+	res, err := d.readFrame()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) < 2 || res[0] != pn532ToHost || res[1] != cmd+1 {
+		return nil, fmt.Errorf("invalid response: %x", res)
+	}
+
+	return res[2:], nil
+}
+
+// Init wakes up the controller and configures it for reading tags
+func (d *PN532) Init() error {
+	if _, err := d.w.Write(wakeup); err != nil {
+		return err
+	}
+
+	if _, err := d.command(cmdSAMConfiguration, samModeNormal, samTimeout, 0x01); err != nil {
+		return fmt.Errorf("sam configuration: %w", err)
+	}
+
+	// limit passive activation retries so polling returns if no tag is present
+	if _, err := d.command(cmdRFConfiguration, cfgItemMaxRetries, 0xFF, 0x01, passiveActivationRetries); err != nil {
+		return fmt.Errorf("rf configuration: %w", err)
+	}
+
+	return nil
+}
+
+// ReadPassiveTarget returns the uid of an ISO14443A tag in the field or nil if none is present
+func (d *PN532) ReadPassiveTarget() ([]byte, error) {
+	res, err := d.command(cmdInListPassiveTarget, 0x01, brTy106kbpsTypeA)
+	if err != nil {
+		return nil, err
+	}
+
+	// number of targets
+	if len(res) == 0 || res[0] == 0 {
+		return nil, nil
+	}
+
+	// Tg, SENS_RES (2), SEL_RES, NFCIDLength, NFCID
+	if len(res) < 6 || len(res) < 6+int(res[5]) {
+		return nil, fmt.Errorf("invalid target data: %x", res)
+	}
+
+	return res[6 : 6+res[5]], nil
+}
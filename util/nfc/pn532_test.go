@@ -0,0 +1,88 @@
+package nfc
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePN532 acknowledges each command frame and answers with the response for its command
+type fakePN532 struct {
+	bytes.Buffer
+	responses map[byte][]byte
+}
+
+func (f *fakePN532) Write(b []byte) (int, error) {
+	// ignore wakeup sequence
+	if b[0] == 0x55 {
+		return len(b), nil
+	}
+
+	cmd := b[6]
+
+	f.Buffer.Write(ack)
+
+	res := frame(0, f.responses[cmd]...)
+	res[5], res[6] = pn532ToHost, cmd+1
+
+	// recalculate data checksum for modified payload
+	var sum byte
+	for _, b := range res[5 : len(res)-2] {
+		sum += b
+	}
+	res[len(res)-2] = -sum
+
+	f.Buffer.Write(res)
+
+	return len(b), nil
+}
+
+func TestFrame(t *testing.T) {
+	assert.Equal(t, []byte{0x00, 0x00, 0xFF, 0x05, 0xFB, 0xD4, 0x14, 0x01, 0x14, 0x01, 0x02, 0x00}, frame(cmdSAMConfiguration, 0x01, 0x14, 0x01))
+}
+
+func TestReadPassiveTarget(t *testing.T) {
+	f := &fakePN532{responses: map[byte][]byte{
+		cmdInListPassiveTarget: {0x01, 0x01, 0x00, 0x04, 0x08, 0x04, 0xDE, 0xAD, 0xBE, 0xEF},
+	}}
+
+	d := NewPN532(f)
+	require.NoError(t, d.Init())
+
+	uid, err := d.ReadPassiveTarget()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xDE, 0xAD, 0xBE, 0xEF}, uid)
+
+	// no tag present
+	f.responses[cmdInListPassiveTarget] = []byte{0x00}
+
+	uid, err = d.ReadPassiveTarget()
+	require.NoError(t, err)
+	assert.Nil(t, uid)
+}
+
+func TestReaderValidity(t *testing.T) {
+	f := &fakePN532{responses: map[byte][]byte{
+		cmdInListPassiveTarget: {0x01, 0x01, 0x00, 0x04, 0x08, 0x04, 0xDE, 0xAD, 0xBE, 0xEF},
+	}}
+
+	clck := clock.NewMock()
+	r := newReader(NewPN532(f), time.Minute)
+	r.clock = clck
+
+	require.NoError(t, r.poll())
+
+	id, err := r.Identify()
+	require.NoError(t, err)
+	assert.Equal(t, "DEADBEEF", id)
+
+	clck.Add(2 * time.Minute)
+
+	id, err = r.Identify()
+	require.NoError(t, err)
+	assert.Equal(t, "", id)
+}
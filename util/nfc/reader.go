@@ -0,0 +1,137 @@
+package nfc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/grid-x/serial"
+)
+
+// Config is the NFC reader configuration
+type Config struct {
+	Device   string        // serial device, e.g. /dev/ttyUSB0
+	Baudrate int           // defaults to 115200
+	Validity time.Duration // duration a presented tag identifies the session, defaults to 5m
+}
+
+// Reader polls a PN532 NFC reader and provides the uid of the last presented tag as identification
+type Reader struct {
+	log      *util.Logger
+	clock    clock.Clock
+	mu       sync.Mutex
+	port     serial.Port
+	dev      *PN532
+	validity time.Duration
+	id       string
+	updated  time.Time
+	stopC    chan struct{}
+}
+
+// NewReaderFromConfig creates a reader from config
+func NewReaderFromConfig(cc Config) (*Reader, error) {
+	if cc.Baudrate == 0 {
+		cc.Baudrate = 115200
+	}
+	if cc.Validity == 0 {
+		cc.Validity = 5 * time.Minute
+	}
+
+	port, err := serial.Open(&serial.Config{
+		Address:  cc.Device,
+		BaudRate: cc.Baudrate,
+		DataBits: 8,
+		StopBits: 1,
+		Parity:   "N",
+		Timeout:  2 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("nfc: %w", err)
+	}
+
+	r := newReader(NewPN532(port), cc.Validity)
+	r.port = port
+
+	if err := r.dev.Init(); err != nil {
+		port.Close()
+		return nil, fmt.Errorf("nfc: %w", err)
+	}
+
+	go r.run(500 * time.Millisecond)
+
+	return r, nil
+}
+
+func newReader(dev *PN532, validity time.Duration) *Reader {
+	return &Reader{
+		log:      util.NewLogger("nfc"),
+		clock:    clock.New(),
+		dev:      dev,
+		validity: validity,
+		stopC:    make(chan struct{}),
+	}
+}
+
+func (r *Reader) run(interval time.Duration) {
+	ticker := r.clock.Ticker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stopC:
+			return
+		case <-ticker.C:
+			if err := r.poll(); err != nil {
+				r.log.ERROR.Println(err)
+			}
+		}
+	}
+}
+
+// poll reads a tag from the field and updates the identification
+func (r *Reader) poll() error {
+	uid, err := r.dev.ReadPassiveTarget()
+	if err != nil || uid == nil {
+		return err
+	}
+
+	id := strings.ToUpper(fmt.Sprintf("%x", uid))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id != r.id || r.clock.Since(r.updated) > r.validity {
+		r.log.DEBUG.Println("tag:", id)
+	}
+
+	r.id = id
+	r.updated = r.clock.Now()
+
+	return nil
+}
+
+var _ api.Identifier = (*Reader)(nil)
+
+// Identify implements the api.Identifier interface
+func (r *Reader) Identify() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.id == "" || r.clock.Since(r.updated) > r.validity {
+		return "", nil
+	}
+
+	return r.id, nil
+}
+
+var _ api.Closer = (*Reader)(nil)
+
+// Close implements the api.Closer interface
+func (r *Reader) Close() error {
+	close(r.stopC)
+	return r.port.Close()
+}
@@ -2,9 +2,14 @@ package oauth
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	"sync"
 	"time"
 
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/chaos"
+	"github.com/evcc-io/evcc/util/request"
 	"github.com/imdario/mergo"
 	"golang.org/x/oauth2"
 )
@@ -26,10 +31,17 @@ func RefreshTokenSource(token *oauth2.Token, refresher TokenRefresher) oauth2.To
 func (ts *TokenSource) Token() (*oauth2.Token, error) {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
-	var err error
-	if ts.token == nil || time.Until(ts.token.Expiry) < time.Minute {
+
+	expired, err := chaos.TokenExpired()
+	if err != nil {
+		return ts.token, fmt.Errorf("%w: %v", api.ErrAuthExpired, err)
+	}
+
+	if ts.token == nil || expired || time.Until(ts.token.Expiry) < time.Minute {
 		var token *oauth2.Token
-		if token, err = ts.refresher.RefreshToken(ts.token); err == nil {
+		if token, err = ts.refresher.RefreshToken(ts.token); err != nil {
+			err = refreshError(err)
+		} else {
 			if token.AccessToken == "" {
 				err = errors.New("token refresh failed to obtain access token")
 			} else {
@@ -40,6 +52,22 @@ func (ts *TokenSource) Token() (*oauth2.Token, error) {
 	return ts.token, err
 }
 
+// refreshError marks refresh tokens rejected by the authorization server as expired
+func refreshError(err error) error {
+	var se request.StatusError
+	if errors.As(err, &se) && se.HasStatus(http.StatusBadRequest, http.StatusUnauthorized) {
+		return fmt.Errorf("%w: %v", api.ErrAuthExpired, err)
+	}
+
+	var re *oauth2.RetrieveError
+	if errors.As(err, &re) && re.Response != nil &&
+		(re.Response.StatusCode == http.StatusBadRequest || re.Response.StatusCode == http.StatusUnauthorized) {
+		return fmt.Errorf("%w: %v", api.ErrAuthExpired, err)
+	}
+
+	return err
+}
+
 // mergeToken updates a token while preventing wiping the refresh token
 func (ts *TokenSource) mergeToken(t *oauth2.Token) error {
 	return mergo.Merge(ts.token, t, mergo.WithOverride)
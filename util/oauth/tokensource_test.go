@@ -1,8 +1,12 @@
 package oauth
 
 import (
+	"errors"
+	"net/http"
 	"testing"
 
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/request"
 	"golang.org/x/oauth2"
 )
 
@@ -29,3 +33,21 @@ func TestMerge(t *testing.T) {
 		t.Error("unexpected refresh token", ts.token)
 	}
 }
+
+func TestRefreshError(t *testing.T) {
+	for code, expired := range map[int]bool{
+		http.StatusBadRequest:          true,
+		http.StatusUnauthorized:        true,
+		http.StatusTooManyRequests:     false,
+		http.StatusInternalServerError: false,
+	} {
+		err := refreshError(request.NewStatusError(&http.Response{StatusCode: code}))
+		if errors.Is(err, api.ErrAuthExpired) != expired {
+			t.Errorf("%d: unexpected error %v", code, err)
+		}
+	}
+
+	if err := refreshError(errors.New("foo")); errors.Is(err, api.ErrAuthExpired) {
+		t.Error("unexpected error", err)
+	}
+}
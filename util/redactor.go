@@ -2,12 +2,16 @@ package util
 
 import (
 	"bytes"
+	"io"
 	"net/url"
 	"os"
 	"sync"
 )
 
 var (
+	// LogOutput is the console log destination
+	LogOutput io.Writer = os.Stdout
+
 	// RedactReplacement is the default replacement string
 	RedactReplacement = "***"
 
@@ -40,7 +44,7 @@ func (l *Redactor) Write(p []byte) (n int, err error) {
 		p = bytes.ReplaceAll(p, []byte(s), []byte(RedactReplacement))
 	}
 	l.mu.Unlock()
-	return os.Stdout.Write(p)
+	return LogOutput.Write(p)
 }
 
 // RedactDefaultHook expands a redaction item to include URL encoding
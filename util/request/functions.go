@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/evcc-io/evcc/api"
 )
 
 var (
@@ -39,6 +41,19 @@ func (e StatusError) Error() string {
 	return fmt.Sprintf("unexpected status: %d", e.resp.StatusCode)
 }
 
+// Unwrap maps the status code to the api errors. Unauthorized is not mapped since it
+// cannot be told apart from invalid credentials, see oauth token refresh instead.
+func (e StatusError) Unwrap() error {
+	switch e.resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return api.ErrRateLimited
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return api.ErrTimeout
+	default:
+		return nil
+	}
+}
+
 // Response returns the respose with the unexpected error
 func (e StatusError) Response() *http.Response {
 	return e.resp
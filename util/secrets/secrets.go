@@ -0,0 +1,264 @@
+// Package secrets stores passwords and tokens encrypted outside of the configuration file.
+// The configuration references secrets by name using the `!secret` tag:
+//
+//	chargers:
+//	- name: wallbox
+//	  type: template
+//	  template: easee
+//	  user: me@example.org
+//	  password: !secret easee-password
+//
+// Secrets are encrypted using a key derived from EVCC_SECRETS_KEY or, if not set, the machine id.
+// The machine id is the configured plant if set, it must be applied before opening the secrets.
+package secrets
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/evcc-io/evcc/util/machine"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Tag references a secret by name
+	Tag = "!secret"
+
+	// DefaultFilename is the secrets file next to the configuration file
+	DefaultFilename = "evcc.secrets"
+
+	keySize   = 32
+	nonceSize = 24
+	saltSize  = 16
+)
+
+// Store is an encrypted secrets file
+type Store struct {
+	file    string
+	key     [keySize]byte
+	salt    []byte
+	secrets map[string]string
+}
+
+type storeFile struct {
+	Salt    string            `yaml:"salt"`
+	Secrets map[string]string `yaml:"secrets"`
+}
+
+// Filename returns the secrets file belonging to the configuration file
+func Filename(configFile string) string {
+	return filepath.Join(filepath.Dir(configFile), DefaultFilename)
+}
+
+// passphrase returns the configured passphrase or the protected machine id
+func passphrase() (string, error) {
+	if key := os.Getenv("EVCC_SECRETS_KEY"); key != "" {
+		return key, nil
+	}
+	return machine.ProtectedID("evcc-secrets")
+}
+
+// Open opens the secrets file. A missing file is created on Save.
+func Open(file string) (*Store, error) {
+	s := &Store{
+		file:    file,
+		secrets: make(map[string]string),
+	}
+
+	var sf storeFile
+
+	b, err := os.ReadFile(file)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(b, &sf); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if s.salt, err = base64.StdEncoding.DecodeString(sf.Salt); err != nil {
+			return nil, fmt.Errorf("%s: salt: %w", file, err)
+		}
+		if sf.Secrets != nil {
+			s.secrets = sf.Secrets
+		}
+
+	case errors.Is(err, os.ErrNotExist):
+		s.salt = make([]byte, saltSize)
+		if _, err := rand.Read(s.salt); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, err
+	}
+
+	pass, err := passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := scrypt.Key([]byte(pass), s.salt, 1<<15, 8, 1, keySize)
+	if err != nil {
+		return nil, err
+	}
+	copy(s.key[:], key)
+
+	return s, nil
+}
+
+// Names returns the names of the stored secrets
+func (s *Store) Names() []string {
+	res := maps.Keys(s.secrets)
+	slices.Sort(res)
+	return res
+}
+
+// Get decrypts the named secret
+func (s *Store) Get(name string) (string, error) {
+	enc, ok := s.secrets[name]
+	if !ok {
+		return "", fmt.Errorf("secret not found: %s", name)
+	}
+
+	b, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil || len(b) < nonceSize+secretbox.Overhead {
+		return "", fmt.Errorf("secret %s: invalid encoding", name)
+	}
+
+	var nonce [nonceSize]byte
+	copy(nonce[:], b[:nonceSize])
+
+	msg, ok := secretbox.Open(nil, b[nonceSize:], &nonce, &s.key)
+	if !ok {
+		return "", fmt.Errorf("secret %s: decryption failed, check EVCC_SECRETS_KEY", name)
+	}
+
+	return string(msg), nil
+}
+
+// Set encrypts the named secret
+func (s *Store) Set(name, value string) error {
+	var nonce [nonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return err
+	}
+
+	b := secretbox.Seal(nonce[:], []byte(value), &nonce, &s.key)
+	s.secrets[name] = base64.StdEncoding.EncodeToString(b)
+
+	return nil
+}
+
+// Save writes the secrets file readable for the owner only
+func (s *Store) Save() error {
+	b, err := yaml.Marshal(storeFile{
+		Salt:    base64.StdEncoding.EncodeToString(s.salt),
+		Secrets: s.secrets,
+	})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.file, b, 0o600)
+}
+
+// references returns the scalar nodes tagged as secret references
+func references(n *yaml.Node) []*yaml.Node {
+	if n.Kind == yaml.ScalarNode && n.Tag == Tag {
+		return []*yaml.Node{n}
+	}
+
+	var res []*yaml.Node
+	for _, c := range n.Content {
+		res = append(res, references(c)...)
+	}
+
+	return res
+}
+
+// Resolve replaces the secret references of the yaml document with the secrets' values.
+// The secrets file is only opened if the document contains references.
+func Resolve(b []byte, file string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	refs := references(&doc)
+	if len(refs) == 0 {
+		return b, nil
+	}
+
+	s, err := Open(file)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range refs {
+		val, err := s.Get(n.Value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n.Line, err)
+		}
+
+		n.Tag = "!!str"
+		n.Value = val
+		n.Style = yaml.DoubleQuotedStyle
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+
+	err = enc.Encode(&doc)
+
+	return out.Bytes(), err
+}
+
+// Reference replaces the scalar values of the yaml document matching a secret's value with a reference to the secret
+func Reference(b []byte, refs map[string]string) ([]byte, error) {
+	if len(refs) == 0 {
+		return b, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	var replace func(n *yaml.Node)
+	replace = func(n *yaml.Node) {
+		if n.Kind == yaml.MappingNode {
+			// only replace values, never keys
+			for i := 1; i < len(n.Content); i += 2 {
+				if v := n.Content[i]; v.Kind == yaml.ScalarNode {
+					if name, ok := refs[v.Value]; ok {
+						v.Tag = Tag
+						v.Value = name
+						v.Style = 0
+					}
+				}
+			}
+		}
+
+		for _, c := range n.Content {
+			replace(c)
+		}
+	}
+
+	replace(&doc)
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+
+	err := enc.Encode(&doc)
+
+	return out.Bytes(), err
+}
@@ -0,0 +1,65 @@
+package secrets
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Setenv("EVCC_SECRETS_KEY", "foo")
+	file := filepath.Join(t.TempDir(), DefaultFilename)
+
+	s, err := Open(file)
+	require.NoError(t, err)
+	require.NoError(t, s.Set("password", "secret"))
+	require.NoError(t, s.Save())
+
+	s, err = Open(file)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"password"}, s.Names())
+
+	val, err := s.Get("password")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", val)
+
+	_, err = s.Get("token")
+	assert.Error(t, err)
+
+	// wrong key
+	t.Setenv("EVCC_SECRETS_KEY", "bar")
+	s, err = Open(file)
+	require.NoError(t, err)
+
+	_, err = s.Get("password")
+	assert.Error(t, err)
+}
+
+func TestReferences(t *testing.T) {
+	t.Setenv("EVCC_SECRETS_KEY", "foo")
+	file := filepath.Join(t.TempDir(), DefaultFilename)
+
+	s, err := Open(file)
+	require.NoError(t, err)
+	require.NoError(t, s.Set("wallbox-password", "s3cr3t: #1"))
+	require.NoError(t, s.Save())
+
+	b, err := Reference([]byte("user: me\npassword: \"s3cr3t: #1\"\n"), map[string]string{"s3cr3t: #1": "wallbox-password"})
+	require.NoError(t, err)
+	assert.Equal(t, "user: me\npassword: !secret wallbox-password\n", string(b))
+
+	b, err = Resolve(b, file)
+	require.NoError(t, err)
+	assert.Equal(t, "user: me\npassword: \"s3cr3t: #1\"\n", string(b))
+
+	// documents without references are left untouched
+	plain := []byte("password: plain # comment\n")
+	b, err = Resolve(plain, filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	assert.Equal(t, plain, b)
+
+	_, err = Resolve([]byte("password: !secret missing\n"), file)
+	assert.EqualError(t, err, "line 1: secret not found: missing")
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/util"
@@ -47,19 +48,38 @@ func Create(machineID string) {
 	instanceID = machineID
 }
 
-func UpdateChargeProgress(log *util.Logger, power, deltaCharged, deltaGreen float64) {
-	log.DEBUG.Printf("telemetry: charge: Δ%.0f/%.0fWh @ %.0fW", deltaGreen*1e3, deltaCharged*1e3, power)
+// RecordChargeProgress adds the charge progress to the local aggregate and payload preview
+// independent of telemetry being enabled and returns the payload of the enabled categories.
+// Must be called from the site loop only.
+func RecordChargeProgress(power, deltaCharged, deltaGreen float64) InstanceChargeProgress {
+	var greenPower float64
+	if deltaCharged > 0 {
+		greenPower = power * deltaGreen / deltaCharged
+	}
 
 	data := InstanceChargeProgress{
 		InstanceID: instanceID,
 		ChargeProgress: ChargeProgress{
 			ChargePower:  power,
-			GreenPower:   power * deltaGreen / deltaCharged,
+			GreenPower:   greenPower,
 			ChargeEnergy: deltaCharged,
 			GreenEnergy:  deltaGreen,
 		},
 	}
 
+	// aggregate locally before removing disabled categories
+	addAggregate(time.Now(), data.ChargeProgress)
+
+	data = filter(data, Categories())
+	setPreview(data)
+
+	return data
+}
+
+// UpdateChargeProgress sends the charge progress payload
+func UpdateChargeProgress(log *util.Logger, data InstanceChargeProgress) {
+	log.DEBUG.Printf("telemetry: charge: Δ%.0f/%.0fWh @ %.0fW", data.GreenEnergy*1e3, data.ChargeEnergy*1e3, data.ChargePower)
+
 	uri := fmt.Sprintf("%s/v1/charge", api)
 	req, err := request.New(http.MethodPost, uri, request.MarshalJSON(data), map[string]string{
 		"Authorization": "Bearer " + sponsor.Token,
@@ -0,0 +1,138 @@
+package telemetry
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db/settings"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	categoriesSetting = "telemetry.categories"
+	aggregateSetting  = "telemetry.aggregate"
+
+	// maxAggregateDays is the number of days kept in the local aggregate
+	maxAggregateDays = 400
+)
+
+// Telemetry categories
+const (
+	CategoryPower  = "power"  // charge and green power
+	CategoryEnergy = "energy" // charged and green energy
+)
+
+// AllCategories are the available telemetry categories
+var AllCategories = []string{CategoryPower, CategoryEnergy}
+
+var (
+	mu        sync.Mutex
+	preview   *InstanceChargeProgress
+	aggregate []DailyAggregate
+	loaded    bool
+)
+
+// DailyAggregate is the anonymized daily charge energy
+type DailyAggregate struct {
+	Day          string  `json:"day"`
+	ChargeEnergy float64 `json:"chargeEnergy"`
+	GreenEnergy  float64 `json:"greenEnergy"`
+}
+
+// PreviewData is the data that would be sent
+type PreviewData struct {
+	Enabled    bool                    `json:"enabled"`
+	Categories []string                `json:"categories"`
+	Payload    *InstanceChargeProgress `json:"payload"`
+}
+
+// Categories returns the enabled telemetry categories, all by default
+func Categories() []string {
+	var res []string
+	if err := settings.Json(categoriesSetting, &res); err != nil {
+		return AllCategories
+	}
+	return res
+}
+
+// SetCategories sets the enabled telemetry categories
+func SetCategories(categories []string) error {
+	for _, c := range categories {
+		if !slices.Contains(AllCategories, c) {
+			return fmt.Errorf("invalid category: %s", c)
+		}
+	}
+
+	return settings.SetJson(categoriesSetting, categories)
+}
+
+// Preview returns the last charge progress payload, independent of telemetry being enabled
+func Preview() PreviewData {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return PreviewData{
+		Enabled:    Enabled(),
+		Categories: Categories(),
+		Payload:    preview,
+	}
+}
+
+// Aggregate returns the locally aggregated daily charge energy
+func Aggregate() []DailyAggregate {
+	mu.Lock()
+	defer mu.Unlock()
+
+	loadAggregate()
+
+	return slices.Clone(aggregate)
+}
+
+func loadAggregate() {
+	if !loaded {
+		_ = settings.Json(aggregateSetting, &aggregate)
+		loaded = true
+	}
+}
+
+// filter removes the data of disabled categories
+func filter(data InstanceChargeProgress, categories []string) InstanceChargeProgress {
+	if !slices.Contains(categories, CategoryPower) {
+		data.ChargePower, data.GreenPower = 0, 0
+	}
+	if !slices.Contains(categories, CategoryEnergy) {
+		data.ChargeEnergy, data.GreenEnergy = 0, 0
+	}
+	return data
+}
+
+// setPreview stores the payload preview
+func setPreview(data InstanceChargeProgress) {
+	mu.Lock()
+	defer mu.Unlock()
+	preview = &data
+}
+
+// addAggregate adds the charged energy to the local aggregate
+func addAggregate(ts time.Time, data ChargeProgress) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	loadAggregate()
+
+	day := ts.Format("2006-01-02")
+	if n := len(aggregate); n == 0 || aggregate[n-1].Day != day {
+		aggregate = append(aggregate, DailyAggregate{Day: day})
+	}
+
+	if n := len(aggregate); n > maxAggregateDays {
+		aggregate = aggregate[n-maxAggregateDays:]
+	}
+
+	last := &aggregate[len(aggregate)-1]
+	last.ChargeEnergy += data.ChargeEnergy
+	last.GreenEnergy += data.GreenEnergy
+
+	_ = settings.SetJson(aggregateSetting, aggregate)
+}
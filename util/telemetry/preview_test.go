@@ -0,0 +1,42 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	data := InstanceChargeProgress{
+		InstanceID:     "id",
+		ChargeProgress: ChargeProgress{ChargePower: 1, GreenPower: 2, ChargeEnergy: 3, GreenEnergy: 4},
+	}
+
+	assert.Equal(t, data, filter(data, AllCategories))
+	assert.Equal(t, ChargeProgress{ChargeEnergy: 3, GreenEnergy: 4}, filter(data, []string{CategoryEnergy}).ChargeProgress)
+	assert.Equal(t, ChargeProgress{}, filter(data, nil).ChargeProgress)
+}
+
+func TestCategories(t *testing.T) {
+	assert.Equal(t, AllCategories, Categories())
+
+	require.NoError(t, SetCategories([]string{CategoryPower}))
+	assert.Equal(t, []string{CategoryPower}, Categories())
+
+	assert.Error(t, SetCategories([]string{"foo"}))
+}
+
+func TestAggregate(t *testing.T) {
+	ts := time.Date(2023, 1, 1, 12, 0, 0, 0, time.Local)
+
+	addAggregate(ts, ChargeProgress{ChargeEnergy: 1, GreenEnergy: 0.5})
+	addAggregate(ts.Add(time.Hour), ChargeProgress{ChargeEnergy: 1, GreenEnergy: 0.5})
+	addAggregate(ts.AddDate(0, 0, 1), ChargeProgress{ChargeEnergy: 2})
+
+	assert.Equal(t, []DailyAggregate{
+		{Day: "2023-01-01", ChargeEnergy: 2, GreenEnergy: 1},
+		{Day: "2023-01-02", ChargeEnergy: 2},
+	}, Aggregate())
+}
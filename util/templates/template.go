@@ -59,7 +59,7 @@ func (t *Template) Validate() error {
 		}
 	}
 
-	for _, p := range t.Params {
+	for i, p := range t.Params {
 		switch p.Name {
 		case ParamUsage:
 			for _, c := range p.Choice {
@@ -78,6 +78,17 @@ func (t *Template) Validate() error {
 		if p.ValueType != "" && !slices.Contains(ValidParamValueTypes, p.ValueType) {
 			return fmt.Errorf("invalid value type '%s' in template %s", p.ValueType, t.Template)
 		}
+
+		for _, d := range p.Dependencies {
+			if !slices.Contains(ValidDependencies, d.Check) {
+				return fmt.Errorf("invalid dependency check '%s' of param %s in template %s", d.Check, p.Name, t.Template)
+			}
+
+			// dependencies are evaluated in order
+			if idx, _ := t.ParamByName(d.Name); idx < 0 || idx >= i {
+				return fmt.Errorf("invalid dependency '%s' of param %s in template %s: must be a previous param", d.Name, p.Name, t.Template)
+			}
+		}
 	}
 
 	return nil
@@ -159,6 +159,13 @@ type LinkedTemplate struct {
 	ExcludeTemplate string // only consider this if no device of the named linked template was added
 }
 
+// ParamDependency defines a condition on a previous param's value
+type ParamDependency struct {
+	Name  string // name of the param this param depends on
+	Check string // check to perform, see DependencyCheck constants
+	Value string // value to compare against for DependencyCheckEqual
+}
+
 // Param is a proxy template parameter
 // Params can be defined:
 // 1. in the template: uses entries in 4. for default properties and values, can be overwritten here
@@ -187,17 +194,53 @@ type Param struct {
 	Value         string       // user provided value via cli configuration
 	Values        []string     // user provided list of values e.g. for ValueType "stringlist"
 	ValueType     string       // string representation of the value type, "string" is default
+	Unit          string       // cli unit of the value, e.g. "A", "kW" or "%"
+	Min           int64        // cli minimum value for number and float value types, 0 if not limited
+	Max           int64        // cli maximum value for number and float value types, 0 if not limited
 	ValidValues   []string     // list of valid values the user can provide
 	Choice        []string     // defines a set of choices, e.g. "grid", "pv", "battery", "charge" for "usage"
 	AllInOne      bool         // defines if the defined usages can all be present in a single device
 	Requirements  Requirements // requirements for this param to be usable, only supported via ValueType "bool"
 
+	Dependencies []ParamDependency // cli conditions on previous params' values which must all be met for the param to be asked
+
 	Baudrate int    // device specific default for modbus RS485 baudrate
 	Comset   string // device specific default for modbus RS485 comset
 	Port     int    // device specific default for modbus TCPIP port
 	ID       int    // device specific default for modbus ID
 }
 
+// DependenciesMet returns true if all dependencies are met by the given param values
+func (p *Param) DependenciesMet(values map[string]interface{}) bool {
+	for _, d := range p.Dependencies {
+		var value string
+		switch v := values[d.Name].(type) {
+		case nil:
+		case []string:
+			value = strings.Join(v, ",")
+		default:
+			value = fmt.Sprintf("%v", v)
+		}
+
+		switch d.Check {
+		case DependencyCheckEmpty:
+			if value != "" {
+				return false
+			}
+		case DependencyCheckNotEmpty:
+			if value == "" {
+				return false
+			}
+		case DependencyCheckEqual:
+			if !strings.EqualFold(value, d.Value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 // return a default value or example value depending on the renderMode
 func (p *Param) DefaultValue(renderMode string) interface{} {
 	// return empty list to allow iterating over in template
@@ -216,7 +259,7 @@ func (p *Param) DefaultValue(renderMode string) interface{} {
 //
 // always overwrites if not provided empty: description, valuetype, default, mask, required
 //
-// only overwrite if not provided empty and empty in param: help, example, requirements
+// only overwrite if not provided empty and empty in param: help, example, unit, min, max, requirements
 func (p *Param) OverwriteProperties(withParam Param) {
 	// always overwrite if defined
 	p.Description.Update(withParam.Description, true)
@@ -244,6 +287,18 @@ func (p *Param) OverwriteProperties(withParam Param) {
 		p.Example = withParam.Example
 	}
 
+	if p.Unit == "" && withParam.Unit != "" {
+		p.Unit = withParam.Unit
+	}
+
+	if p.Min == 0 && withParam.Min != 0 {
+		p.Min = withParam.Min
+	}
+
+	if p.Max == 0 && withParam.Max != 0 {
+		p.Max = withParam.Max
+	}
+
 	if p.ValidValues == nil && withParam.ValidValues != nil {
 		p.ValidValues = withParam.ValidValues
 	}
@@ -0,0 +1,64 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamDependencies(t *testing.T) {
+	p := Param{
+		Name: "capacity",
+		Dependencies: []ParamDependency{
+			{Name: "battery", Check: DependencyCheckEqual, Value: "true"},
+			{Name: "host", Check: DependencyCheckNotEmpty},
+		},
+	}
+
+	assert.True(t, p.DependenciesMet(map[string]interface{}{"battery": "true", "host": "192.0.2.2"}))
+	assert.False(t, p.DependenciesMet(map[string]interface{}{"battery": "false", "host": "192.0.2.2"}))
+	assert.False(t, p.DependenciesMet(map[string]interface{}{"battery": "true"}))
+
+	p.Dependencies = []ParamDependency{{Name: "identifiers", Check: DependencyCheckEmpty}}
+	assert.True(t, p.DependenciesMet(map[string]interface{}{"identifiers": []string{}}))
+	assert.False(t, p.DependenciesMet(map[string]interface{}{"identifiers": []string{"foo"}}))
+}
+
+func TestValidateDependencies(t *testing.T) {
+	tmpl := Template{
+		TemplateDefinition: TemplateDefinition{
+			Template: "foo",
+			Params: []Param{
+				{Name: "battery", ValueType: ParamValueTypeBool},
+				{Name: "capacity", Dependencies: []ParamDependency{{Name: "battery", Check: DependencyCheckEqual, Value: "true"}}},
+			},
+		},
+	}
+	assert.NoError(t, tmpl.Validate())
+
+	tmpl.Params[1].Dependencies[0].Check = "foo"
+	assert.Error(t, tmpl.Validate())
+
+	// dependencies must reference previous params
+	tmpl.Params[1].Dependencies[0] = ParamDependency{Name: "capacity", Check: DependencyCheckNotEmpty}
+	assert.Error(t, tmpl.Validate())
+}
+
+func TestModbusIdRange(t *testing.T) {
+	for _, class := range []Class{Charger, Meter} {
+		for _, tmpl := range ByClass(class) {
+			_, modbus := tmpl.ParamByName(ParamModbus)
+			if modbus.ID == 0 {
+				continue
+			}
+
+			for _, typ := range modbus.Choice {
+				for _, p := range configDefaults.Modbus.Types[typ].Params {
+					if p.Name == ModbusParamNameId {
+						assert.NoError(t, p.validateScalar(modbus.ID), tmpl.Template)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"golang.org/x/exp/slices"
+)
+
+// ValidateValue validates a user provided value against the param's value type, valid values and range
+func (t *Template) ValidateValue(key string, val interface{}) error {
+	i, p := t.ParamByName(key)
+	if i == -1 {
+		if slices.Contains(predefinedTemplateProperties, strings.ToLower(key)) {
+			return nil
+		}
+		return fmt.Errorf("invalid key: %s", key)
+	}
+
+	if list, ok := val.([]interface{}); ok {
+		if p.ValueType != ParamValueTypeStringList {
+			return fmt.Errorf("%s: list not allowed", p.Name)
+		}
+
+		for _, v := range list {
+			if err := p.validateScalar(v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return p.validateScalar(val)
+}
+
+func (p *Param) validateScalar(val interface{}) error {
+	switch val.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		return fmt.Errorf("%s: map not allowed", p.Name)
+	}
+
+	value := fmt.Sprintf("%v", val)
+
+	if len(p.ValidValues) > 0 && !slices.Contains(p.ValidValues, value) {
+		return fmt.Errorf("%s: invalid value %s, must be one of %s", p.Name, value, strings.Join(p.ValidValues, ", "))
+	}
+
+	// modbus choices are interfaces, not interface types
+	if len(p.Choice) > 0 && p.Name != ParamModbus && !slices.Contains(p.Choice, value) {
+		return fmt.Errorf("%s: invalid value %s, must be one of %s", p.Name, value, strings.Join(p.Choice, ", "))
+	}
+
+	switch p.ValueType {
+	case ParamValueTypeNumber:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid number: %s", p.Name, value)
+		}
+		return p.validateRange(float64(i))
+
+	case ParamValueTypeFloat:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid float: %s", p.Name, value)
+		}
+		return p.validateRange(f)
+
+	case ParamValueTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s: invalid bool: %s", p.Name, value)
+		}
+
+	case ParamValueTypeDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("%s: invalid duration: %s", p.Name, value)
+		}
+
+	case ParamValueTypeChargeModes:
+		if _, err := api.ChargeModeString(value); err != nil {
+			return fmt.Errorf("%s: invalid charge mode: %s", p.Name, value)
+		}
+	}
+
+	return nil
+}
+
+func (p *Param) validateRange(val float64) error {
+	if p.Min != 0 && val < float64(p.Min) {
+		return fmt.Errorf("%s: value %v lower than %d", p.Name, val, p.Min)
+	}
+	if p.Max != 0 && val > float64(p.Max) {
+		return fmt.Errorf("%s: value %v bigger than %d", p.Name, val, p.Max)
+	}
+	return nil
+}
+
+// MissingParams returns the required params without default value which are not contained in keys
+func (t *Template) MissingParams(keys []string) []string {
+	var res []string
+
+	for _, p := range t.Params {
+		if !p.Required || p.Default != "" || p.Deprecated || p.Name == ParamModbus {
+			continue
+		}
+
+		if slices.IndexFunc(keys, func(k string) bool { return strings.EqualFold(k, p.Name) }) == -1 {
+			res = append(res, p.Name)
+		}
+	}
+
+	return res
+}
+
+// Deprecated returns true if the param is deprecated
+func (t *Template) Deprecated(key string) bool {
+	i, p := t.ParamByName(key)
+	return i >= 0 && p.Deprecated
+}
@@ -0,0 +1,52 @@
+package replay
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// httpHandler serves the recorded responses
+type httpHandler struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+	missing   []string // requests not covered by the recording
+}
+
+func (h *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, e := range h.exchanges {
+		method := e.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+
+		if method != r.Method || e.Path != r.URL.RequestURI() {
+			continue
+		}
+
+		typ := e.Type
+		if typ == "" {
+			typ = "application/json"
+		}
+		w.Header().Set("Content-Type", typ)
+
+		if e.Status != 0 {
+			w.WriteHeader(e.Status)
+		}
+
+		_, _ = w.Write([]byte(e.Body))
+		return
+	}
+
+	h.mu.Lock()
+	h.missing = append(h.missing, r.Method+" "+r.URL.RequestURI())
+	h.mu.Unlock()
+
+	w.WriteHeader(http.StatusNotFound)
+}
+
+// startHTTP starts a http server replaying the exchanges
+func startHTTP(exchanges []Exchange) (*httpHandler, *httptest.Server) {
+	h := &httpHandler{exchanges: exchanges}
+	return h, httptest.NewServer(h)
+}
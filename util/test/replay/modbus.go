@@ -0,0 +1,104 @@
+package replay
+
+import (
+	"net"
+	"sync"
+
+	"github.com/andig/mbserver"
+)
+
+// modbusHandler serves the recorded registers. Writes are accepted for recorded registers and update their values.
+type modbusHandler struct {
+	mu        sync.Mutex
+	registers []Registers
+	missing   []string // requests not covered by the recording
+}
+
+// lookup returns the recorded values for the request or nil
+func (h *modbusHandler) lookup(id uint8, typ string, addr, qty uint16) []uint16 {
+	for _, r := range h.registers {
+		rid := r.ID
+		if rid == 0 {
+			rid = 1
+		}
+
+		if rid == id && r.Type == typ && addr >= r.Address && int(addr)+int(qty) <= int(r.Address)+len(r.Values) {
+			return r.Values[addr-r.Address : addr-r.Address+qty]
+		}
+	}
+
+	return nil
+}
+
+func (h *modbusHandler) registersRequest(id uint8, typ string, addr, qty uint16, write bool, args []uint16) ([]uint16, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	res := h.lookup(id, typ, addr, qty)
+	if res == nil {
+		h.missing = append(h.missing, fmtRequest(id, typ, addr, qty))
+		return nil, mbserver.ErrIllegalDataAddress
+	}
+
+	if write {
+		copy(res, args)
+		return nil, nil
+	}
+
+	return append([]uint16(nil), res...), nil
+}
+
+func toBools(u []uint16) []bool {
+	res := make([]bool, 0, len(u))
+	for _, v := range u {
+		res = append(res, v != 0)
+	}
+	return res
+}
+
+func toUint16s(b []bool) []uint16 {
+	res := make([]uint16, 0, len(b))
+	for _, v := range b {
+		var u uint16
+		if v {
+			u = 1
+		}
+		res = append(res, u)
+	}
+	return res
+}
+
+func (h *modbusHandler) HandleCoils(req *mbserver.CoilsRequest) ([]bool, error) {
+	res, err := h.registersRequest(req.UnitId, "coil", req.Addr, req.Quantity, req.IsWrite, toUint16s(req.Args))
+	return toBools(res), err
+}
+
+func (h *modbusHandler) HandleDiscreteInputs(req *mbserver.DiscreteInputsRequest) ([]bool, error) {
+	res, err := h.registersRequest(req.UnitId, "discrete", req.Addr, req.Quantity, false, nil)
+	return toBools(res), err
+}
+
+func (h *modbusHandler) HandleHoldingRegisters(req *mbserver.HoldingRegistersRequest) ([]uint16, error) {
+	return h.registersRequest(req.UnitId, "holding", req.Addr, req.Quantity, req.IsWrite, req.Args)
+}
+
+func (h *modbusHandler) HandleInputRegisters(req *mbserver.InputRegistersRequest) ([]uint16, error) {
+	return h.registersRequest(req.UnitId, "input", req.Addr, req.Quantity, false, nil)
+}
+
+// startModbus starts a modbus tcp server replaying the registers
+func startModbus(registers []Registers) (*modbusHandler, *mbserver.ModbusServer, int, error) {
+	h := &modbusHandler{registers: registers}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	srv, err := mbserver.New(h)
+	if err == nil {
+		err = srv.Start(l)
+	}
+
+	return h, srv, l.Addr().(*net.TCPAddr).Port, err
+}
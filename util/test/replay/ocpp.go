@@ -0,0 +1,236 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ocppPort is the port of the charger/ocpp central system
+const ocppPort = 8887
+
+// ocpp-j message types
+const (
+	ocppCall       = 2
+	ocppCallResult = 3
+	ocppCallError  = 4
+)
+
+// ocppClient replays a charge point connecting to the central system.
+// The charge point connects again until the boot notification is accepted.
+type ocppClient struct {
+	mu      sync.Mutex
+	ocpp    OCPP
+	missing []string // requests not covered by the recording and failed messages
+
+	conn    *websocket.Conn
+	wmu     sync.Mutex
+	id      int
+	pending map[string]chan ocppResult
+
+	done chan struct{}
+}
+
+type ocppResult struct {
+	payload json.RawMessage
+	err     error
+}
+
+// startOCPP starts a charge point replaying the recording
+func startOCPP(o OCPP) *ocppClient {
+	c := &ocppClient{
+		ocpp:    o,
+		pending: make(map[string]chan ocppResult),
+		done:    make(chan struct{}),
+	}
+
+	go c.run()
+
+	return c
+}
+
+// stop disconnects the charge point
+func (c *ocppClient) stop() {
+	close(c.done)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+}
+
+func (c *ocppClient) fail(format string, args ...interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.missing = append(c.missing, fmt.Sprintf(format, args...))
+}
+
+func (c *ocppClient) run() {
+	uri := fmt.Sprintf("ws://127.0.0.1:%d/%s", ocppPort, c.ocpp.Station)
+	dialer := websocket.Dialer{Subprotocols: []string{"ocpp1.6"}}
+
+	for {
+		select {
+		case <-c.done:
+			return
+		default:
+		}
+
+		if conn, _, err := dialer.Dial(uri, nil); err == nil {
+			c.mu.Lock()
+			c.conn = conn
+			c.mu.Unlock()
+
+			go c.read(conn)
+
+			if c.boot() {
+				for _, m := range c.ocpp.Connect {
+					c.send(m)
+				}
+
+				return
+			}
+
+			_ = conn.Close()
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// boot sends the boot notification and returns true if accepted
+func (c *ocppClient) boot() bool {
+	boot := c.ocpp.Boot
+	if boot == nil {
+		boot = map[string]interface{}{"chargePointVendor": "replay", "chargePointModel": "replay"}
+	}
+
+	payload, err := c.call("BootNotification", boot)
+	if err != nil {
+		return false
+	}
+
+	var res struct{ Status string }
+	return json.Unmarshal(payload, &res) == nil && res.Status == "Accepted"
+}
+
+// send sends a recorded charge point message
+func (c *ocppClient) send(m Message) {
+	if _, err := c.call(m.Action, m.Payload); err != nil {
+		c.fail("%s: %v", m.Action, err)
+	}
+}
+
+// call sends a message and waits for the central system's result
+func (c *ocppClient) call(action string, payload interface{}) (json.RawMessage, error) {
+	c.mu.Lock()
+	c.id++
+	id := strconv.Itoa(c.id)
+	resC := make(chan ocppResult, 1)
+	c.pending[id] = resC
+	c.mu.Unlock()
+
+	if err := c.write(ocppCall, id, action, payload); err != nil {
+		return nil, err
+	}
+
+	select {
+	case res := <-resC:
+		return res.payload, res.err
+	case <-time.After(10 * time.Second):
+		return nil, errors.New("timeout")
+	case <-c.done:
+		return nil, errors.New("stopped")
+	}
+}
+
+func (c *ocppClient) write(frame ...interface{}) error {
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	return conn.WriteJSON(frame)
+}
+
+// read dispatches the central system's messages until the connection is closed
+func (c *ocppClient) read(conn *websocket.Conn) {
+	for {
+		var frame []json.RawMessage
+		if err := conn.ReadJSON(&frame); err != nil {
+			return
+		}
+
+		var typ int
+		var id string
+		if len(frame) < 3 || json.Unmarshal(frame[0], &typ) != nil || json.Unmarshal(frame[1], &id) != nil {
+			continue
+		}
+
+		switch typ {
+		case ocppCall:
+			var action string
+			if len(frame) == 4 && json.Unmarshal(frame[2], &action) == nil {
+				c.respond(id, action, frame[3])
+			}
+
+		case ocppCallResult, ocppCallError:
+			c.mu.Lock()
+			resC, ok := c.pending[id]
+			delete(c.pending, id)
+			c.mu.Unlock()
+
+			if !ok {
+				continue
+			}
+
+			res := ocppResult{payload: frame[2]}
+			if typ == ocppCallError {
+				res = ocppResult{err: fmt.Errorf("call error: %s", frame[2])}
+			}
+			resC <- res
+		}
+	}
+}
+
+// respond answers a central system request with the recorded response and sends the response's messages
+func (c *ocppClient) respond(id, action string, payload json.RawMessage) {
+	var req struct{ RequestedMessage string }
+	_ = json.Unmarshal(payload, &req)
+
+	for _, r := range c.ocpp.Responses {
+		if r.Action != action || (r.Trigger != "" && r.Trigger != req.RequestedMessage) {
+			continue
+		}
+
+		if err := c.write(ocppCallResult, id, r.Payload); err != nil {
+			return
+		}
+
+		go func(messages []Message) {
+			for _, m := range messages {
+				c.send(m)
+			}
+		}(r.Send)
+
+		return
+	}
+
+	request := action
+	if req.RequestedMessage != "" {
+		request += " " + req.RequestedMessage
+	}
+	c.fail("%s", request)
+
+	_ = c.write(ocppCallError, id, "NotImplemented", "not recorded", struct{}{})
+}
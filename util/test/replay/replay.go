@@ -0,0 +1,177 @@
+package replay
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fmtRequest formats a modbus request not covered by the recording
+func fmtRequest(id uint8, typ string, addr, qty uint16) string {
+	return fmt.Sprintf("id %d %s %d..%d", id, typ, addr, int(addr)+int(qty)-1)
+}
+
+// Run replays the scenario fixtures matching the pattern, e.g. testdata/replay/*.yaml,
+// against the devices created from the fixtures' templates
+func Run(t *testing.T, pattern string, create func(map[string]interface{}) (interface{}, error)) {
+	files, err := filepath.Glob(pattern)
+	require.NoError(t, err)
+
+	for _, file := range files {
+		file := file
+
+		t.Run(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)), func(t *testing.T) {
+			s, err := Load(file)
+			require.NoError(t, err)
+
+			RunScenario(t, s, create)
+		})
+	}
+}
+
+// RunScenario replays a scenario against the device created from the scenario's template
+func RunScenario(t *testing.T, s Scenario, create func(map[string]interface{}) (interface{}, error)) {
+	var interfaces int
+	for _, ok := range []bool{len(s.Modbus) > 0, len(s.HTTP) > 0, s.OCPP != nil} {
+		if ok {
+			interfaces++
+		}
+	}
+	if interfaces > 1 {
+		t.Fatal("scenario can only replay one of modbus, http or ocpp")
+	}
+
+	values := map[string]interface{}{"template": s.Template}
+	for k, v := range s.Params {
+		values[k] = v
+	}
+
+	var missing func() []string
+
+	if len(s.Modbus) > 0 {
+		h, srv, port, err := startModbus(s.Modbus)
+		require.NoError(t, err)
+		defer func() { _ = srv.Stop() }()
+
+		values["host"] = "127.0.0.1"
+		values["port"] = port
+
+		missing = func() []string {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			return h.missing
+		}
+	}
+
+	if len(s.HTTP) > 0 {
+		h, srv := startHTTP(s.HTTP)
+		defer srv.Close()
+
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		values["host"] = u.Host
+
+		missing = func() []string {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			return h.missing
+		}
+	}
+
+	if s.OCPP != nil {
+		if s.OCPP.Station == "" {
+			s.OCPP.Station = "replay"
+		}
+		values["stationid"] = s.OCPP.Station
+
+		// don't wait for the charge point forever
+		if _, ok := values["timeout"]; !ok {
+			values["timeout"] = "10s"
+		}
+
+		c := startOCPP(*s.OCPP)
+		defer c.stop()
+
+		missing = func() []string {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.missing
+		}
+	}
+
+	dev, err := create(values)
+	require.NoError(t, err)
+
+	if c, ok := dev.(api.Closer); ok {
+		defer c.Close()
+	}
+
+	check(t, dev, s.Expect)
+
+	if missing != nil {
+		assert.Empty(t, missing(), "requests not covered by recording")
+	}
+}
+
+// check compares the device readings with the expected values
+func check(t *testing.T, dev interface{}, e Expect) {
+	if e.Power != nil {
+		m, ok := dev.(api.Meter)
+		require.True(t, ok, "not a meter")
+
+		power, err := m.CurrentPower()
+		require.NoError(t, err, "power")
+		assert.InDelta(t, *e.Power, power, 1e-3, "power")
+	}
+
+	if e.Energy != nil {
+		m, ok := dev.(api.MeterEnergy)
+		require.True(t, ok, "no energy")
+
+		energy, err := m.TotalEnergy()
+		require.NoError(t, err, "energy")
+		assert.InDelta(t, *e.Energy, energy, 1e-3, "energy")
+	}
+
+	if e.Currents != nil {
+		m, ok := dev.(api.MeterCurrent)
+		require.True(t, ok, "no currents")
+
+		l1, l2, l3, err := m.Currents()
+		require.NoError(t, err, "currents")
+		assert.InDeltaSlice(t, e.Currents, []float64{l1, l2, l3}, 1e-3, "currents")
+	}
+
+	if e.SoC != nil {
+		m, ok := dev.(api.Battery)
+		require.True(t, ok, "no soc")
+
+		soc, err := m.SoC()
+		require.NoError(t, err, "soc")
+		assert.InDelta(t, *e.SoC, soc, 1e-3, "soc")
+	}
+
+	if e.Status != "" {
+		c, ok := dev.(api.Charger)
+		require.True(t, ok, "not a charger")
+
+		status, err := c.Status()
+		require.NoError(t, err, "status")
+		assert.Equal(t, api.ChargeStatus(e.Status), status, "status")
+	}
+
+	if e.Enabled != nil {
+		c, ok := dev.(api.Charger)
+		require.True(t, ok, "not a charger")
+
+		enabled, err := c.Enabled()
+		require.NoError(t, err, "enabled")
+		assert.Equal(t, *e.Enabled, enabled, "enabled")
+	}
+}
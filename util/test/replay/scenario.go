@@ -0,0 +1,96 @@
+// Package replay tests device templates against recorded device traffic.
+//
+// A scenario fixture records the modbus registers, http responses or ocpp messages of a real device
+// together with the readings expected from the template:
+//
+//	template: shelly-3em
+//	params:
+//	  usage: grid
+//	http:
+//	  - path: /status
+//	    body: '{"emeters":[{"power":100,"total":1000}]}'
+//	expect:
+//	  power: 100
+//
+// The template's host and port params are set to the replaying servers.
+// Templates with modbus interface choice need the `modbus: tcpip` param.
+// For ocpp, a charge point connects to the central system using the template's stationid param.
+// Requests not covered by the recording fail the scenario.
+package replay
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is a recorded device interaction
+type Scenario struct {
+	Template string                 // template name
+	Params   map[string]interface{} // template params
+	Modbus   []Registers            // recorded modbus registers
+	HTTP     []Exchange             // recorded http responses
+	OCPP     *OCPP                  // recorded ocpp charge point
+	Expect   Expect                 // expected device readings
+}
+
+// Registers is a recorded block of consecutive modbus registers
+type Registers struct {
+	ID      uint8    // slave id, 1 if not set
+	Type    string   // holding, input, coil or discrete
+	Address uint16   // first register address
+	Values  []uint16 // register values, 0 or 1 for coils and discrete inputs
+}
+
+// Exchange is a recorded http request and response
+type Exchange struct {
+	Method string // request method, GET if not set
+	Path   string // request path including query
+	Status int    // response status, 200 if not set
+	Type   string // response content type, application/json if not set
+	Body   string // response body
+}
+
+// OCPP is a recorded ocpp charge point
+type OCPP struct {
+	Station   string      // station id, replay if not set
+	Boot      interface{} // boot notification payload
+	Connect   []Message   // messages sent once booted, each after the previous has been confirmed
+	Responses []Response  // recorded responses to central system requests
+}
+
+// Message is a recorded ocpp charge point message
+type Message struct {
+	Action  string      // e.g. StatusNotification
+	Payload interface{} // message payload
+}
+
+// Response is a recorded ocpp response to a central system request
+type Response struct {
+	Action  string      // request action, e.g. GetConfiguration
+	Trigger string      // requested message of TriggerMessage requests, any if not set
+	Payload interface{} // response payload
+	Send    []Message   // messages sent after responding, e.g. the triggered message
+}
+
+// Expect are the expected device readings. Unset readings are not checked.
+type Expect struct {
+	Power    *float64  // current power in W
+	Energy   *float64  // total energy in kWh
+	SoC      *float64  // battery or vehicle soc in %
+	Currents []float64 // phase currents in A
+	Status   string    // charger status A..F
+	Enabled  *bool     // charger enabled
+}
+
+// Load reads a scenario fixture
+func Load(filename string) (Scenario, error) {
+	var res Scenario
+
+	b, err := os.ReadFile(filename)
+	if err == nil {
+		err = yaml.Unmarshal(b, &res)
+	}
+
+	return res, err
+}
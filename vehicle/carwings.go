@@ -99,7 +99,7 @@ func NewCarWingsFromConfig(other map[string]interface{}) (api.Vehicle, error) {
 
 // connectIfRequired will return ErrMustRetry if ErrNotLoggedIn error could be resolved
 func (v *CarWings) connectIfRequired(err error) error {
-	if errors.Is(err, carwings.ErrNotLoggedIn) || err.Error() == "received status code 404" {
+	if errors.Is(carwingsError(err), carwings.ErrNotLoggedIn) {
 		if err = v.session.Connect(v.user, v.password); err == nil {
 			err = api.ErrMustRetry
 		}
@@ -237,3 +237,12 @@ func (v *CarWings) Climater() (active bool, outsideTemp, targetTemp float64, err
 
 	return false, 0, 0, err
 }
+
+// carwingsError maps the untyped status errors returned by the carwings client to its sentinel errors
+func carwingsError(err error) error {
+	// expired sessions are answered with not found
+	if err != nil && err.Error() == fmt.Sprintf("received status code %d", http.StatusNotFound) {
+		return carwings.ErrNotLoggedIn
+	}
+	return err
+}
@@ -2,7 +2,7 @@ package vehicle
 
 import (
 	"context"
-	"strings"
+	"errors"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -101,14 +101,16 @@ func (v *Cloud) chargeState() (float64, error) {
 	defer cancel()
 
 	res, err := v.client.SoC(ctx, req)
+	err = cloud.Error(err)
 
-	if err != nil && strings.Contains(err.Error(), api.ErrMustRetry.Error()) {
-		return 0, api.ErrMustRetry
+	if errors.Is(err, api.ErrMustRetry) {
+		return 0, err
 	}
 
-	if err != nil && strings.Contains(err.Error(), cloud.ErrVehicleNotAvailable.Error()) && v.prepareVehicle() == nil {
+	if errors.Is(err, cloud.ErrVehicleNotAvailable) && v.prepareVehicle() == nil {
 		req.VehicleId = v.vehicleID
 		res, err = v.client.SoC(ctx, req)
+		err = cloud.Error(err)
 	}
 
 	return res.GetSoc(), err
@@ -6,12 +6,14 @@ import (
 )
 
 type embed struct {
-	Title_       string           `mapstructure:"title"`
-	Capacity_    float64          `mapstructure:"capacity"`
-	Phases_      int              `mapstructure:"phases"`
-	Identifiers_ []string         `mapstructure:"identifiers"`
-	Features_    []api.Feature    `mapstructure:"features"`
-	OnIdentify   api.ActionConfig `mapstructure:"onIdentify"`
+	Title_       string             `mapstructure:"title"`
+	Capacity_    float64            `mapstructure:"capacity"`
+	Phases_      int                `mapstructure:"phases"`
+	Identifiers_ []string           `mapstructure:"identifiers"`
+	Features_    []api.Feature      `mapstructure:"features"`
+	OnIdentify   api.ActionConfig   `mapstructure:"onIdentify"`
+	Geofence_    api.GeofenceConfig `mapstructure:"geofence"`
+	SocModel_    string             `mapstructure:"socModel"`
 }
 
 // Title implements the api.Vehicle interface
@@ -39,6 +41,20 @@ func (v *embed) OnIdentified() api.ActionConfig {
 	return v.OnIdentify
 }
 
+var _ api.Geofencer = (*embed)(nil)
+
+// Geofence implements the api.Geofencer interface
+func (v *embed) Geofence() api.GeofenceConfig {
+	return v.Geofence_
+}
+
+var _ api.SocModeler = (*embed)(nil)
+
+// SocModel implements the api.SocModeler interface
+func (v *embed) SocModel() string {
+	return v.SocModel_
+}
+
 var _ api.FeatureDescriber = (*embed)(nil)
 
 // Features implements the api.Describer interface
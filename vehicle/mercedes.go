@@ -8,6 +8,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/vehicle/mercedes"
+	"golang.org/x/oauth2"
 )
 
 // Mercedes is an api.Vehicle implementation for Mercedes cars
@@ -26,6 +27,7 @@ func NewMercedesFromConfig(other map[string]interface{}) (api.Vehicle, error) {
 	cc := struct {
 		embed                  `mapstructure:",squash"`
 		ClientID, ClientSecret string
+		Tokens                 Tokens // optional, login using the ui otherwise
 		VIN                    string
 		Sandbox                bool
 		Cache                  time.Duration
@@ -47,17 +49,19 @@ func NewMercedesFromConfig(other map[string]interface{}) (api.Vehicle, error) {
 
 	var options []mercedes.IdentityOption
 
-	// TODO Load tokens from a persistence storage and use those during startup
-	// e.g. persistence.Load("key")
-	// if tokens != nil {
-	// 	options = append(options, mercedes.WithToken(&oauth2.Token{
-	// 		AccessToken:  tokens.Access,
-	// 		RefreshToken: tokens.Refresh,
-	// 		Expiry:       tokens.Expiry,
-	// 	}))
-	// }
-
-	log := util.NewLogger("mercedes")
+	if cc.Tokens != (Tokens{}) {
+		if err := cc.Tokens.Error(); err != nil {
+			return nil, err
+		}
+
+		options = append(options, mercedes.WithToken(&oauth2.Token{
+			AccessToken:  cc.Tokens.Access,
+			RefreshToken: cc.Tokens.Refresh,
+			Expiry:       time.Now(),
+		}))
+	}
+
+	log := util.NewLogger("mercedes").Redact(cc.ClientSecret, cc.Tokens.Access, cc.Tokens.Refresh)
 
 	// TODO session secret from config/persistence
 	identity, err := mercedes.NewIdentity(log, cc.ClientID, cc.ClientSecret, options...)
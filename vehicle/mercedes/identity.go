@@ -36,8 +36,8 @@ type Identity struct {
 	authC   chan<- bool
 }
 
-// TODO SessionSecret from config/persistence
-func NewIdentity(log *util.Logger, id, secret string, options ...IdentityOption) (*Identity, error) {
+// OAuth2Config returns the client's oauth2 configuration
+func OAuth2Config(id, secret string) (*oauth2.Config, error) {
 	provider, err := oidc.NewProvider(context.Background(), OAuthURI)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize OIDC provider: %s", err)
@@ -54,6 +54,16 @@ func NewIdentity(log *util.Logger, id, secret string, options ...IdentityOption)
 		},
 	}
 
+	return oc, nil
+}
+
+// TODO SessionSecret from config/persistence
+func NewIdentity(log *util.Logger, id, secret string, options ...IdentityOption) (*Identity, error) {
+	oc, err := OAuth2Config(id, secret)
+	if err != nil {
+		return nil, err
+	}
+
 	v := &Identity{
 		log: log,
 		oc:  oc,
@@ -147,5 +157,5 @@ func (v *Identity) callbackHandler(w http.ResponseWriter, r *http.Request) {
 		provider.ResetCached()
 	}
 
-	http.Redirect(w, r, v.baseURL, http.StatusFound)
+	http.Redirect(w, r, util.ForwardedURI(r, v.baseURL), http.StatusFound)
 }
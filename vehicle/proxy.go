@@ -0,0 +1,146 @@
+package vehicle
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/server/vehicleproxy"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"golang.org/x/exp/slices"
+)
+
+// Proxy is an api.Vehicle implementation using the vehicle proxy of another evcc instance
+type Proxy struct {
+	*embed
+	*request.Helper
+	uri, token string
+	socG       func() (float64, error)
+	statusG    func() (api.ChargeStatus, error)
+	rangeG     func() (int64, error)
+	odometerG  func() (float64, error)
+}
+
+func init() {
+	registry.Add("proxy", NewProxyFromConfig)
+}
+
+// NewProxyFromConfig creates a new vehicle
+func NewProxyFromConfig(other map[string]interface{}) (api.Vehicle, error) {
+	cc := struct {
+		embed   `mapstructure:",squash"`
+		URI     string
+		Vehicle string
+		Token   string
+		Cache   time.Duration
+	}{
+		Cache: interval,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.URI == "" || cc.Vehicle == "" {
+		return nil, errors.New("missing uri or vehicle")
+	}
+
+	log := util.NewLogger("proxy").Redact(cc.Token)
+
+	v := &Proxy{
+		embed:  &cc.embed,
+		Helper: request.NewHelper(log),
+		uri:    fmt.Sprintf("%s/vehicles/%s", strings.TrimSuffix(util.DefaultScheme(cc.URI, "http"), "/"), url.PathEscape(cc.Vehicle)),
+		token:  cc.Token,
+	}
+
+	res, err := proxyValue[vehicleproxy.Vehicle](v, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if v.Capacity_ == 0 {
+		v.Capacity_ = res.Capacity
+	}
+
+	v.socG = provider.Cached(func() (float64, error) { return proxyValue[float64](v, "soc") }, cc.Cache)
+
+	var status func() (api.ChargeStatus, error)
+	if slices.Contains(res.Features, vehicleproxy.FeatureStatus) {
+		v.statusG = provider.Cached(func() (api.ChargeStatus, error) { return proxyValue[api.ChargeStatus](v, "status") }, cc.Cache)
+		status = v.statusG
+	}
+
+	var rng func() (int64, error)
+	if slices.Contains(res.Features, vehicleproxy.FeatureRange) {
+		v.rangeG = provider.Cached(func() (int64, error) { return proxyValue[int64](v, "range") }, cc.Cache)
+		rng = v.rangeG
+	}
+
+	var odo func() (float64, error)
+	if slices.Contains(res.Features, vehicleproxy.FeatureOdometer) {
+		v.odometerG = provider.Cached(func() (float64, error) { return proxyValue[float64](v, "odometer") }, cc.Cache)
+		odo = v.odometerG
+	}
+
+	return decorateVehicle(v, status, rng, odo), nil
+}
+
+// get retrieves the vehicle's path from the proxy
+func (v *Proxy) get(path string, res any) error {
+	uri := v.uri
+	if path != "" {
+		uri += "/" + path
+	}
+
+	headers := request.AcceptJSON
+	if v.token != "" {
+		headers = map[string]string{
+			"Accept":        request.JSONContent,
+			"Authorization": "Bearer " + v.token,
+		}
+	}
+
+	req, err := request.New(http.MethodGet, uri, nil, headers)
+	if err == nil {
+		err = v.DoJSON(req, res)
+	}
+
+	return err
+}
+
+// proxyValue retrieves a vehicle value from the proxy and maps the proxy's errors to the api errors
+func proxyValue[T any](v *Proxy, path string) (T, error) {
+	var res vehicleproxy.Response[T]
+
+	err := v.get(path, &res)
+
+	if se := new(request.StatusError); errors.As(err, se) {
+		switch se.StatusCode() {
+		case http.StatusUnauthorized:
+			// the proxy token is static, rejection requires reconfiguration
+			err = api.ErrAuthExpired
+		case http.StatusNotFound:
+			err = api.ErrNotAvailable
+		case http.StatusServiceUnavailable:
+			err = api.ErrMustRetry
+		default:
+			if res.Error != "" {
+				err = fmt.Errorf("%s: %w", res.Error, err)
+			}
+		}
+	}
+
+	return res.Result, err
+}
+
+// SoC implements the api.Vehicle interface
+func (v *Proxy) SoC() (float64, error) {
+	return v.socG()
+}
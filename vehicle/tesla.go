@@ -2,6 +2,9 @@ package vehicle
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/bogosj/tesla"
@@ -202,9 +205,9 @@ var _ api.VehicleChargeController = (*Tesla)(nil)
 
 // StartCharge implements the api.VehicleChargeController interface
 func (v *Tesla) StartCharge() error {
-	err := v.vehicle.StartCharging()
+	err := teslaError(v.vehicle.StartCharging())
 
-	if err != nil && err.Error() == "408 Request Timeout" {
+	if errors.Is(err, api.ErrTimeout) {
 		if _, err := v.vehicle.Wakeup(); err != nil {
 			return err
 		}
@@ -217,7 +220,7 @@ func (v *Tesla) StartCharge() error {
 				return api.ErrTimeout
 			default:
 				time.Sleep(2 * time.Second)
-				if err := v.vehicle.StartCharging(); err == nil || err.Error() != "408 Request Timeout" {
+				if err := teslaError(v.vehicle.StartCharging()); !errors.Is(err, api.ErrTimeout) {
 					return err
 				}
 			}
@@ -229,12 +232,20 @@ func (v *Tesla) StartCharge() error {
 
 // StopCharge implements the api.VehicleChargeController interface
 func (v *Tesla) StopCharge() error {
-	err := v.vehicle.StopCharging()
+	err := teslaError(v.vehicle.StopCharging())
 
 	// ignore sleeping vehicle
-	if err != nil && err.Error() == "408 Request Timeout" {
+	if errors.Is(err, api.ErrTimeout) {
 		err = nil
 	}
 
 	return err
 }
+
+// teslaError maps the response status returned as error by the tesla client to api errors
+func teslaError(err error) error {
+	if err != nil && err.Error() == fmt.Sprintf("%d %s", http.StatusRequestTimeout, http.StatusText(http.StatusRequestTimeout)) {
+		return api.ErrTimeout
+	}
+	return err
+}
@@ -8,12 +8,14 @@ import (
 
 // Wrapper wraps an api.Vehicle to capture initialization errors
 type Wrapper struct {
+	v   api.Vehicle
 	err error
 }
 
 // New creates a new Vehicle
 func New(w api.Vehicle, err error) (api.Vehicle, error) {
 	v := &Wrapper{
+		v:   w,
 		err: fmt.Errorf("vehicle not available: %w", err),
 	}
 
@@ -53,3 +55,13 @@ var _ api.Battery = (*Wrapper)(nil)
 func (v *Wrapper) SoC() (float64, error) {
 	return 0, v.err
 }
+
+var _ api.Closer = (*Wrapper)(nil)
+
+// Close implements the api.Closer interface and releases the partially created vehicle
+func (v *Wrapper) Close() error {
+	if c, ok := v.v.(api.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
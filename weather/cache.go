@@ -0,0 +1,45 @@
+package weather
+
+import (
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// cache periodically refreshes and holds the warnings
+type cache struct {
+	mux     sync.Mutex
+	log     *util.Logger
+	data    []api.WeatherWarning
+	updated time.Time
+}
+
+// run refreshes the warnings in the given interval
+func (c *cache) run(interval time.Duration, fetch func() ([]api.WeatherWarning, error)) {
+	for ; true; <-time.NewTicker(interval).C {
+		data, err := fetch()
+		if err != nil {
+			c.log.ERROR.Println(err)
+			continue
+		}
+
+		c.mux.Lock()
+		c.data = data
+		c.updated = time.Now()
+		c.mux.Unlock()
+	}
+}
+
+// Warnings implements the api.WeatherWarnings interface
+func (c *cache) Warnings() ([]api.WeatherWarning, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.updated.IsZero() {
+		return nil, api.ErrMustRetry
+	}
+
+	return c.data, nil
+}
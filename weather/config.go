@@ -0,0 +1,28 @@
+package weather
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// Config is the typed weather warning configuration
+type Config struct {
+	Type  string
+	Other map[string]interface{} `mapstructure:",remain"`
+}
+
+// NewFromConfig creates weather warnings from config
+func NewFromConfig(typ string, other map[string]interface{}) (w api.WeatherWarnings, err error) {
+	switch strings.ToLower(typ) {
+	case "dwd":
+		w, err = NewDWD(other)
+	case "nws":
+		w, err = NewNWS(other)
+	default:
+		return nil, errors.New("unknown weather warnings: " + typ)
+	}
+
+	return
+}
@@ -0,0 +1,100 @@
+package weather
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// https://www.dwd.de/DE/wetter/warnungen_aktuell/objekt_einbindung/objekteinbindung.html
+
+const dwdURI = "https://www.dwd.de/DWD/warnungen/warnapp/json/warnings.json"
+
+// DWD are the Deutscher Wetterdienst warnings for a warn cell
+type DWD struct {
+	*cache
+	cell string
+}
+
+var _ api.WeatherWarnings = (*DWD)(nil)
+
+type dwdWarning struct {
+	Event      string
+	Level      int
+	Start, End int64 // unix ms
+}
+
+type dwdResponse struct {
+	Warnings map[string][]dwdWarning
+}
+
+// NewDWD creates DWD weather warnings
+func NewDWD(other map[string]interface{}) (*DWD, error) {
+	cc := struct {
+		Cell     string // warn cell id
+		Interval time.Duration
+	}{
+		Interval: 15 * time.Minute,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.Cell == "" {
+		return nil, errors.New("missing cell")
+	}
+
+	w := &DWD{
+		cache: &cache{log: util.NewLogger("dwd")},
+		cell:  cc.Cell,
+	}
+
+	go w.run(cc.Interval, w.fetch)
+
+	return w, nil
+}
+
+func (w *DWD) fetch() ([]api.WeatherWarning, error) {
+	b, err := request.NewHelper(w.log).GetBody(dwdURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return dwdWarnings(b, w.cell)
+}
+
+// dwdWarnings decodes the jsonp response's warnings for the given cell
+func dwdWarnings(b []byte, cell string) ([]api.WeatherWarning, error) {
+	start, end := bytes.IndexByte(b, '('), bytes.LastIndexByte(b, ')')
+	if start < 0 || end < start {
+		return nil, errors.New("invalid response")
+	}
+
+	var res dwdResponse
+	if err := json.Unmarshal(b[start+1:end], &res); err != nil {
+		return nil, err
+	}
+
+	warnings := make([]api.WeatherWarning, 0)
+	for _, w := range res.Warnings[cell] {
+		warning := api.WeatherWarning{
+			Event:    w.Event,
+			Severity: w.Level,
+			Start:    time.UnixMilli(w.Start),
+		}
+
+		if w.End > 0 {
+			warning.End = time.UnixMilli(w.End)
+		}
+
+		warnings = append(warnings, warning)
+	}
+
+	return warnings, nil
+}
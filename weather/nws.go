@@ -0,0 +1,101 @@
+package weather
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// https://www.weather.gov/documentation/services-web-api
+
+const nwsURI = "https://api.weather.gov/alerts/active?point=%g,%g"
+
+// NWS are the US National Weather Service alerts for a location
+type NWS struct {
+	*cache
+	uri string
+}
+
+var _ api.WeatherWarnings = (*NWS)(nil)
+
+var nwsSeverity = map[string]int{
+	"Minor":    1,
+	"Moderate": 2,
+	"Severe":   3,
+	"Extreme":  4,
+}
+
+type nwsResponse struct {
+	Features []struct {
+		Properties struct {
+			Event    string
+			Severity string
+			Onset    time.Time
+			Ends     *time.Time
+			Expires  time.Time
+		}
+	}
+}
+
+// NewNWS creates NWS weather warnings
+func NewNWS(other map[string]interface{}) (*NWS, error) {
+	cc := struct {
+		Latitude, Longitude float64
+		Interval            time.Duration
+	}{
+		Interval: 15 * time.Minute,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	w := &NWS{
+		cache: &cache{log: util.NewLogger("nws")},
+		uri:   fmt.Sprintf(nwsURI, cc.Latitude, cc.Longitude),
+	}
+
+	go w.run(cc.Interval, w.fetch)
+
+	return w, nil
+}
+
+func (w *NWS) fetch() ([]api.WeatherWarning, error) {
+	// api requires identifying user agent
+	req, err := request.New(http.MethodGet, w.uri, nil, map[string]string{
+		"Accept":     "application/geo+json",
+		"User-Agent": "evcc/" + server.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res nwsResponse
+	if err := request.NewHelper(w.log).DoJSON(req, &res); err != nil {
+		return nil, err
+	}
+
+	warnings := make([]api.WeatherWarning, 0, len(res.Features))
+	for _, f := range res.Features {
+		p := f.Properties
+
+		end := p.Expires
+		if p.Ends != nil {
+			end = *p.Ends
+		}
+
+		warnings = append(warnings, api.WeatherWarning{
+			Event:    p.Event,
+			Severity: nwsSeverity[p.Severity],
+			Start:    p.Onset,
+			End:      end,
+		})
+	}
+
+	return warnings, nil
+}